@@ -21,20 +21,20 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type DataItem struct {
+type LoginPassword struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       int32                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	InfoType string               `protobuf:"bytes,2,opt,name=info_type,json=infoType,proto3" json:"info_type,omitempty"` // 'login_password', 'text', 'binary', 'bank_card'
-	Info     string               `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
-	Meta     string               `protobuf:"bytes,4,opt,name=meta,proto3" json:"meta,omitempty"`
-	Created  *timestamp.Timestamp `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
+	Login    string `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Url      string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// Секрет в base32 или ссылка otpauth://totp/ с параметрами генерации кода.
+	TotpSecret string `protobuf:"bytes,4,opt,name=totp_secret,json=totpSecret,proto3" json:"totp_secret,omitempty"`
 }
 
-func (x *DataItem) Reset() {
-	*x = DataItem{}
+func (x *LoginPassword) Reset() {
+	*x = LoginPassword{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_data_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -42,13 +42,13 @@ func (x *DataItem) Reset() {
 	}
 }
 
-func (x *DataItem) String() string {
+func (x *LoginPassword) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DataItem) ProtoMessage() {}
+func (*LoginPassword) ProtoMessage() {}
 
-func (x *DataItem) ProtoReflect() protoreflect.Message {
+func (x *LoginPassword) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_data_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -60,56 +60,49 @@ func (x *DataItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DataItem.ProtoReflect.Descriptor instead.
-func (*DataItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use LoginPassword.ProtoReflect.Descriptor instead.
+func (*LoginPassword) Descriptor() ([]byte, []int) {
 	return file_api_proto_data_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *DataItem) GetId() int32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *DataItem) GetInfoType() string {
+func (x *LoginPassword) GetLogin() string {
 	if x != nil {
-		return x.InfoType
+		return x.Login
 	}
 	return ""
 }
 
-func (x *DataItem) GetInfo() string {
+func (x *LoginPassword) GetPassword() string {
 	if x != nil {
-		return x.Info
+		return x.Password
 	}
 	return ""
 }
 
-func (x *DataItem) GetMeta() string {
+func (x *LoginPassword) GetUrl() string {
 	if x != nil {
-		return x.Meta
+		return x.Url
 	}
 	return ""
 }
 
-func (x *DataItem) GetCreated() *timestamp.Timestamp {
+func (x *LoginPassword) GetTotpSecret() string {
 	if x != nil {
-		return x.Created
+		return x.TotpSecret
 	}
-	return nil
+	return ""
 }
 
-type AddDataRequest struct {
+type Text struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data *DataItem `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Content string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
 }
 
-func (x *AddDataRequest) Reset() {
-	*x = AddDataRequest{}
+func (x *Text) Reset() {
+	*x = Text{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_data_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -117,13 +110,13 @@ func (x *AddDataRequest) Reset() {
 	}
 }
 
-func (x *AddDataRequest) String() string {
+func (x *Text) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddDataRequest) ProtoMessage() {}
+func (*Text) ProtoMessage() {}
 
-func (x *AddDataRequest) ProtoReflect() protoreflect.Message {
+func (x *Text) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_data_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -135,28 +128,30 @@ func (x *AddDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddDataRequest.ProtoReflect.Descriptor instead.
-func (*AddDataRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Text.ProtoReflect.Descriptor instead.
+func (*Text) Descriptor() ([]byte, []int) {
 	return file_api_proto_data_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *AddDataRequest) GetData() *DataItem {
+func (x *Text) GetContent() string {
 	if x != nil {
-		return x.Data
+		return x.Content
 	}
-	return nil
+	return ""
 }
 
-type AddDataResponse struct {
+type Binary struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Mime     string `protobuf:"bytes,2,opt,name=mime,proto3" json:"mime,omitempty"`
+	Bytes    []byte `protobuf:"bytes,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
 }
 
-func (x *AddDataResponse) Reset() {
-	*x = AddDataResponse{}
+func (x *Binary) Reset() {
+	*x = Binary{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_data_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -164,13 +159,13 @@ func (x *AddDataResponse) Reset() {
 	}
 }
 
-func (x *AddDataResponse) String() string {
+func (x *Binary) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddDataResponse) ProtoMessage() {}
+func (*Binary) ProtoMessage() {}
 
-func (x *AddDataResponse) ProtoReflect() protoreflect.Message {
+func (x *Binary) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_data_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -182,28 +177,45 @@ func (x *AddDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddDataResponse.ProtoReflect.Descriptor instead.
-func (*AddDataResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Binary.ProtoReflect.Descriptor instead.
+func (*Binary) Descriptor() ([]byte, []int) {
 	return file_api_proto_data_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *AddDataResponse) GetId() int32 {
+func (x *Binary) GetFilename() string {
 	if x != nil {
-		return x.Id
+		return x.Filename
 	}
-	return 0
+	return ""
 }
 
-type GetDataRequest struct {
+func (x *Binary) GetMime() string {
+	if x != nil {
+		return x.Mime
+	}
+	return ""
+}
+
+func (x *Binary) GetBytes() []byte {
+	if x != nil {
+		return x.Bytes
+	}
+	return nil
+}
+
+type BankCard struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Number string `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Holder string `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	Expiry string `protobuf:"bytes,3,opt,name=expiry,proto3" json:"expiry,omitempty"` // MM/YY
+	Cvv    string `protobuf:"bytes,4,opt,name=cvv,proto3" json:"cvv,omitempty"`
 }
 
-func (x *GetDataRequest) Reset() {
-	*x = GetDataRequest{}
+func (x *BankCard) Reset() {
+	*x = BankCard{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_data_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -211,13 +223,13 @@ func (x *GetDataRequest) Reset() {
 	}
 }
 
-func (x *GetDataRequest) String() string {
+func (x *BankCard) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDataRequest) ProtoMessage() {}
+func (*BankCard) ProtoMessage() {}
 
-func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
+func (x *BankCard) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_data_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -229,28 +241,70 @@ func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDataRequest.ProtoReflect.Descriptor instead.
-func (*GetDataRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BankCard.ProtoReflect.Descriptor instead.
+func (*BankCard) Descriptor() ([]byte, []int) {
 	return file_api_proto_data_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetDataRequest) GetId() int32 {
+func (x *BankCard) GetNumber() string {
 	if x != nil {
-		return x.Id
+		return x.Number
 	}
-	return 0
+	return ""
 }
 
-type GetDataResponse struct {
+func (x *BankCard) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *BankCard) GetExpiry() string {
+	if x != nil {
+		return x.Expiry
+	}
+	return ""
+}
+
+func (x *BankCard) GetCvv() string {
+	if x != nil {
+		return x.Cvv
+	}
+	return ""
+}
+
+type DataItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data *DataItem `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Id       int32                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	InfoType string               `protobuf:"bytes,2,opt,name=info_type,json=infoType,proto3" json:"info_type,omitempty"` // 'login_password', 'text', 'binary', 'bank_card'
+	Info     string               `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`                         // произвольная строка или зашифрованный клиентом payload
+	Meta     string               `protobuf:"bytes,4,opt,name=meta,proto3" json:"meta,omitempty"`
+	Created  *timestamp.Timestamp `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
+	// Types that are assignable to Payload:
+	//	*DataItem_LoginPassword
+	//	*DataItem_Text
+	//	*DataItem_Binary
+	//	*DataItem_BankCard
+	Payload   isDataItem_Payload   `protobuf_oneof:"payload"`
+	Revision  int64                `protobuf:"varint,10,opt,name=revision,proto3" json:"revision,omitempty"` // ревизия пользователя, на которой запись менялась последней
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version   int64                `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`                // версия записи: 1 при создании, +1 при каждом изменении
+	VaultId   int32                `protobuf:"varint,13,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"` // общее хранилище записи, 0 - личные данные
+	// Теги, папка и избранное не шифруются клиентом: сервер фильтрует по ним.
+	Tags     []string `protobuf:"bytes,14,rep,name=tags,proto3" json:"tags,omitempty"`
+	Folder   string   `protobuf:"bytes,15,opt,name=folder,proto3" json:"folder,omitempty"` // путь папки через "/", пусто - вне папок
+	Favorite bool     `protobuf:"varint,16,opt,name=favorite,proto3" json:"favorite,omitempty"`
+	// Токены слепого индекса для SearchData: HMAC слов meta ключом пользователя
+	// или хранилища. Сервер только сохраняет их и в ответах не возвращает.
+	SearchTokens []string `protobuf:"bytes,17,rep,name=search_tokens,json=searchTokens,proto3" json:"search_tokens,omitempty"`
 }
 
-func (x *GetDataResponse) Reset() {
-	*x = GetDataResponse{}
+func (x *DataItem) Reset() {
+	*x = DataItem{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_data_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -258,13 +312,13 @@ func (x *GetDataResponse) Reset() {
 	}
 }
 
-func (x *GetDataResponse) String() string {
+func (x *DataItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDataResponse) ProtoMessage() {}
+func (*DataItem) ProtoMessage() {}
 
-func (x *GetDataResponse) ProtoReflect() protoreflect.Message {
+func (x *DataItem) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_data_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -276,19 +330,166 @@ func (x *GetDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDataResponse.ProtoReflect.Descriptor instead.
-func (*GetDataResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DataItem.ProtoReflect.Descriptor instead.
+func (*DataItem) Descriptor() ([]byte, []int) {
 	return file_api_proto_data_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *GetDataResponse) GetData() *DataItem {
+func (x *DataItem) GetId() int32 {
 	if x != nil {
-		return x.Data
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DataItem) GetInfoType() string {
+	if x != nil {
+		return x.InfoType
+	}
+	return ""
+}
+
+func (x *DataItem) GetInfo() string {
+	if x != nil {
+		return x.Info
+	}
+	return ""
+}
+
+func (x *DataItem) GetMeta() string {
+	if x != nil {
+		return x.Meta
+	}
+	return ""
+}
+
+func (x *DataItem) GetCreated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Created
 	}
 	return nil
 }
 
-type UpdateDataRequest struct {
+func (m *DataItem) GetPayload() isDataItem_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *DataItem) GetLoginPassword() *LoginPassword {
+	if x, ok := x.GetPayload().(*DataItem_LoginPassword); ok {
+		return x.LoginPassword
+	}
+	return nil
+}
+
+func (x *DataItem) GetText() *Text {
+	if x, ok := x.GetPayload().(*DataItem_Text); ok {
+		return x.Text
+	}
+	return nil
+}
+
+func (x *DataItem) GetBinary() *Binary {
+	if x, ok := x.GetPayload().(*DataItem_Binary); ok {
+		return x.Binary
+	}
+	return nil
+}
+
+func (x *DataItem) GetBankCard() *BankCard {
+	if x, ok := x.GetPayload().(*DataItem_BankCard); ok {
+		return x.BankCard
+	}
+	return nil
+}
+
+func (x *DataItem) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *DataItem) GetUpdatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *DataItem) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DataItem) GetVaultId() int32 {
+	if x != nil {
+		return x.VaultId
+	}
+	return 0
+}
+
+func (x *DataItem) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *DataItem) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *DataItem) GetFavorite() bool {
+	if x != nil {
+		return x.Favorite
+	}
+	return false
+}
+
+func (x *DataItem) GetSearchTokens() []string {
+	if x != nil {
+		return x.SearchTokens
+	}
+	return nil
+}
+
+type isDataItem_Payload interface {
+	isDataItem_Payload()
+}
+
+type DataItem_LoginPassword struct {
+	LoginPassword *LoginPassword `protobuf:"bytes,6,opt,name=login_password,json=loginPassword,proto3,oneof"`
+}
+
+type DataItem_Text struct {
+	Text *Text `protobuf:"bytes,7,opt,name=text,proto3,oneof"`
+}
+
+type DataItem_Binary struct {
+	Binary *Binary `protobuf:"bytes,8,opt,name=binary,proto3,oneof"`
+}
+
+type DataItem_BankCard struct {
+	BankCard *BankCard `protobuf:"bytes,9,opt,name=bank_card,json=bankCard,proto3,oneof"`
+}
+
+func (*DataItem_LoginPassword) isDataItem_Payload() {}
+
+func (*DataItem_Text) isDataItem_Payload() {}
+
+func (*DataItem_Binary) isDataItem_Payload() {}
+
+func (*DataItem_BankCard) isDataItem_Payload() {}
+
+type AddDataRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -296,8 +497,8 @@ type UpdateDataRequest struct {
 	Data *DataItem `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 }
 
-func (x *UpdateDataRequest) Reset() {
-	*x = UpdateDataRequest{}
+func (x *AddDataRequest) Reset() {
+	*x = AddDataRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_data_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -305,13 +506,13 @@ func (x *UpdateDataRequest) Reset() {
 	}
 }
 
-func (x *UpdateDataRequest) String() string {
+func (x *AddDataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateDataRequest) ProtoMessage() {}
+func (*AddDataRequest) ProtoMessage() {}
 
-func (x *UpdateDataRequest) ProtoReflect() protoreflect.Message {
+func (x *AddDataRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_data_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -323,26 +524,28 @@ func (x *UpdateDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateDataRequest.ProtoReflect.Descriptor instead.
-func (*UpdateDataRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddDataRequest.ProtoReflect.Descriptor instead.
+func (*AddDataRequest) Descriptor() ([]byte, []int) {
 	return file_api_proto_data_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *UpdateDataRequest) GetData() *DataItem {
+func (x *AddDataRequest) GetData() *DataItem {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-type UpdateDataResponse struct {
+type AddDataResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *UpdateDataResponse) Reset() {
-	*x = UpdateDataResponse{}
+func (x *AddDataResponse) Reset() {
+	*x = AddDataResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_proto_data_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -350,13 +553,13 @@ func (x *UpdateDataResponse) Reset() {
 	}
 }
 
-func (x *UpdateDataResponse) String() string {
+func (x *AddDataResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateDataResponse) ProtoMessage() {}
+func (*AddDataResponse) ProtoMessage() {}
 
-func (x *UpdateDataResponse) ProtoReflect() protoreflect.Message {
+func (x *AddDataResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_data_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -368,12 +571,19 @@ func (x *UpdateDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateDataResponse.ProtoReflect.Descriptor instead.
-func (*UpdateDataResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddDataResponse.ProtoReflect.Descriptor instead.
+func (*AddDataResponse) Descriptor() ([]byte, []int) {
 	return file_api_proto_data_proto_rawDescGZIP(), []int{6}
 }
 
-type DeleteDataRequest struct {
+func (x *AddDataResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetDataRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -381,10 +591,199 @@ type DeleteDataRequest struct {
 	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
+func (x *GetDataRequest) Reset() {
+	*x = GetDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDataRequest) ProtoMessage() {}
+
+func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDataRequest.ProtoReflect.Descriptor instead.
+func (*GetDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetDataRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data *DataItem `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *GetDataResponse) Reset() {
+	*x = GetDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDataResponse) ProtoMessage() {}
+
+func (x *GetDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDataResponse.ProtoReflect.Descriptor instead.
+func (*GetDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetDataResponse) GetData() *DataItem {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type UpdateDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data *DataItem `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *UpdateDataRequest) Reset() {
+	*x = UpdateDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDataRequest) ProtoMessage() {}
+
+func (x *UpdateDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDataRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateDataRequest) GetData() *DataItem {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type UpdateDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version int64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"` // новая версия записи
+}
+
+func (x *UpdateDataResponse) Reset() {
+	*x = UpdateDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDataResponse) ProtoMessage() {}
+
+func (x *UpdateDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDataResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateDataResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DeleteDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // версия, которую видел клиент
+}
+
 func (x *DeleteDataRequest) Reset() {
 	*x = DeleteDataRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_data_proto_msgTypes[7]
+		mi := &file_api_proto_data_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -397,176 +796,2663 @@ func (x *DeleteDataRequest) String() string {
 func (*DeleteDataRequest) ProtoMessage() {}
 
 func (x *DeleteDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_data_proto_msgTypes[7]
+	mi := &file_api_proto_data_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDataRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteDataRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteDataRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// VersionConflict - деталь ошибки Aborted: запись изменили после того,
+// как клиент прочитал версию.
+type VersionConflict struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CurrentVersion int64 `protobuf:"varint,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+}
+
+func (x *VersionConflict) Reset() {
+	*x = VersionConflict{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VersionConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionConflict) ProtoMessage() {}
+
+func (x *VersionConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use DeleteDataRequest.ProtoReflect.Descriptor instead.
-func (*DeleteDataRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_data_proto_rawDescGZIP(), []int{7}
-}
-
-func (x *DeleteDataRequest) GetId() int32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-type DeleteDataResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-}
-
-func (x *DeleteDataResponse) Reset() {
-	*x = DeleteDataResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_data_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *DeleteDataResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*DeleteDataResponse) ProtoMessage() {}
-
-func (x *DeleteDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_data_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionConflict.ProtoReflect.Descriptor instead.
+func (*VersionConflict) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *VersionConflict) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *VersionConflict) GetCurrentVersion() int64 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+type DeleteDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteDataResponse) Reset() {
+	*x = DeleteDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDataResponse) ProtoMessage() {}
+
+func (x *DeleteDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDataResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{13}
+}
+
+type ListDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InfoType      string               `protobuf:"bytes,1,opt,name=info_type,json=infoType,proto3" json:"info_type,omitempty"`          // пусто - без фильтра по типу
+	CreatedFrom   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"` // включительно
+	CreatedTo     *timestamp.Timestamp `protobuf:"bytes,3,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`       // не включительно
+	PageSize      int32                `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Cursor        string               `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`                   // next_cursor из предыдущего ответа
+	Descending    bool                 `protobuf:"varint,6,opt,name=descending,proto3" json:"descending,omitempty"`          // сортировка по created от новых к старым
+	VaultId       int32                `protobuf:"varint,7,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"` // 0 - личные данные, иначе записи общего хранилища
+	Tag           string               `protobuf:"bytes,8,opt,name=tag,proto3" json:"tag,omitempty"`                         // пусто - без фильтра по тегу
+	Folder        string               `protobuf:"bytes,9,opt,name=folder,proto3" json:"folder,omitempty"`                   // записи прямо в папке; "/" - вне папок, пусто - без фильтра
+	FavoritesOnly bool                 `protobuf:"varint,10,opt,name=favorites_only,json=favoritesOnly,proto3" json:"favorites_only,omitempty"`
+}
+
+func (x *ListDataRequest) Reset() {
+	*x = ListDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDataRequest) ProtoMessage() {}
+
+func (x *ListDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDataRequest.ProtoReflect.Descriptor instead.
+func (*ListDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListDataRequest) GetInfoType() string {
+	if x != nil {
+		return x.InfoType
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetCreatedFrom() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListDataRequest) GetCreatedTo() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *ListDataRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDataRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *ListDataRequest) GetVaultId() int32 {
+	if x != nil {
+		return x.VaultId
+	}
+	return 0
+}
+
+func (x *ListDataRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetFavoritesOnly() bool {
+	if x != nil {
+		return x.FavoritesOnly
+	}
+	return false
+}
+
+type DataHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int32                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	InfoType  string               `protobuf:"bytes,2,opt,name=info_type,json=infoType,proto3" json:"info_type,omitempty"`
+	Meta      string               `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
+	Created   *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	Tags      []string             `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Folder    string               `protobuf:"bytes,6,opt,name=folder,proto3" json:"folder,omitempty"`
+	Favorite  bool                 `protobuf:"varint,7,opt,name=favorite,proto3" json:"favorite,omitempty"`
+	DeletedAt *timestamp.Timestamp `protobuf:"bytes,8,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"` // только в ListTrash
+}
+
+func (x *DataHeader) Reset() {
+	*x = DataHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DataHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataHeader) ProtoMessage() {}
+
+func (x *DataHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataHeader.ProtoReflect.Descriptor instead.
+func (*DataHeader) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DataHeader) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DataHeader) GetInfoType() string {
+	if x != nil {
+		return x.InfoType
+	}
+	return ""
+}
+
+func (x *DataHeader) GetMeta() string {
+	if x != nil {
+		return x.Meta
+	}
+	return ""
+}
+
+func (x *DataHeader) GetCreated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *DataHeader) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *DataHeader) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *DataHeader) GetFavorite() bool {
+	if x != nil {
+		return x.Favorite
+	}
+	return false
+}
+
+func (x *DataHeader) GetDeletedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type ListDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items      []*DataHeader `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextCursor string        `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // пусто, если страниц больше нет
+}
+
+func (x *ListDataResponse) Reset() {
+	*x = ListDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDataResponse) ProtoMessage() {}
+
+func (x *ListDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDataResponse.ProtoReflect.Descriptor instead.
+func (*ListDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListDataResponse) GetItems() []*DataHeader {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListDataResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// BinaryHeader - описание файла, передаётся первым сообщением потока.
+type BinaryHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           int32                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`    // заполняется сервером при скачивании
+	Info         string               `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"` // зашифрованный клиентом payload Binary без содержимого
+	Meta         string               `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
+	Size         int64                `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`                                    // размер потока в байтах
+	Sha256       string               `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`                                 // hex SHA-256 всех байт потока
+	SearchTokens []string             `protobuf:"bytes,6,rep,name=search_tokens,json=searchTokens,proto3" json:"search_tokens,omitempty"` // как DataItem.search_tokens
+	Created      *timestamp.Timestamp `protobuf:"bytes,7,opt,name=created,proto3" json:"created,omitempty"`                               // время создания записи; пусто - время загрузки
+}
+
+func (x *BinaryHeader) Reset() {
+	*x = BinaryHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BinaryHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BinaryHeader) ProtoMessage() {}
+
+func (x *BinaryHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BinaryHeader.ProtoReflect.Descriptor instead.
+func (*BinaryHeader) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BinaryHeader) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BinaryHeader) GetInfo() string {
+	if x != nil {
+		return x.Info
+	}
+	return ""
+}
+
+func (x *BinaryHeader) GetMeta() string {
+	if x != nil {
+		return x.Meta
+	}
+	return ""
+}
+
+func (x *BinaryHeader) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BinaryHeader) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *BinaryHeader) GetSearchTokens() []string {
+	if x != nil {
+		return x.SearchTokens
+	}
+	return nil
+}
+
+func (x *BinaryHeader) GetCreated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+type UploadBinaryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Part:
+	//	*UploadBinaryRequest_Header
+	//	*UploadBinaryRequest_Chunk
+	Part isUploadBinaryRequest_Part `protobuf_oneof:"part"`
+}
+
+func (x *UploadBinaryRequest) Reset() {
+	*x = UploadBinaryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadBinaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBinaryRequest) ProtoMessage() {}
+
+func (x *UploadBinaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBinaryRequest.ProtoReflect.Descriptor instead.
+func (*UploadBinaryRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{18}
+}
+
+func (m *UploadBinaryRequest) GetPart() isUploadBinaryRequest_Part {
+	if m != nil {
+		return m.Part
+	}
+	return nil
+}
+
+func (x *UploadBinaryRequest) GetHeader() *BinaryHeader {
+	if x, ok := x.GetPart().(*UploadBinaryRequest_Header); ok {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *UploadBinaryRequest) GetChunk() []byte {
+	if x, ok := x.GetPart().(*UploadBinaryRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isUploadBinaryRequest_Part interface {
+	isUploadBinaryRequest_Part()
+}
+
+type UploadBinaryRequest_Header struct {
+	Header *BinaryHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type UploadBinaryRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadBinaryRequest_Header) isUploadBinaryRequest_Part() {}
+
+func (*UploadBinaryRequest_Chunk) isUploadBinaryRequest_Part() {}
+
+type UploadBinaryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *UploadBinaryResponse) Reset() {
+	*x = UploadBinaryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadBinaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBinaryResponse) ProtoMessage() {}
+
+func (x *UploadBinaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBinaryResponse.ProtoReflect.Descriptor instead.
+func (*UploadBinaryResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UploadBinaryResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DownloadBinaryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DownloadBinaryRequest) Reset() {
+	*x = DownloadBinaryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadBinaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadBinaryRequest) ProtoMessage() {}
+
+func (x *DownloadBinaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadBinaryRequest.ProtoReflect.Descriptor instead.
+func (*DownloadBinaryRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DownloadBinaryRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DownloadBinaryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Part:
+	//	*DownloadBinaryResponse_Header
+	//	*DownloadBinaryResponse_Chunk
+	Part isDownloadBinaryResponse_Part `protobuf_oneof:"part"`
+}
+
+func (x *DownloadBinaryResponse) Reset() {
+	*x = DownloadBinaryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadBinaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadBinaryResponse) ProtoMessage() {}
+
+func (x *DownloadBinaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadBinaryResponse.ProtoReflect.Descriptor instead.
+func (*DownloadBinaryResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{21}
+}
+
+func (m *DownloadBinaryResponse) GetPart() isDownloadBinaryResponse_Part {
+	if m != nil {
+		return m.Part
+	}
+	return nil
+}
+
+func (x *DownloadBinaryResponse) GetHeader() *BinaryHeader {
+	if x, ok := x.GetPart().(*DownloadBinaryResponse_Header); ok {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *DownloadBinaryResponse) GetChunk() []byte {
+	if x, ok := x.GetPart().(*DownloadBinaryResponse_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isDownloadBinaryResponse_Part interface {
+	isDownloadBinaryResponse_Part()
+}
+
+type DownloadBinaryResponse_Header struct {
+	Header *BinaryHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type DownloadBinaryResponse_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*DownloadBinaryResponse_Header) isDownloadBinaryResponse_Part() {}
+
+func (*DownloadBinaryResponse_Chunk) isDownloadBinaryResponse_Part() {}
+
+type GetChangesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SinceRevision int64 `protobuf:"varint,1,opt,name=since_revision,json=sinceRevision,proto3" json:"since_revision,omitempty"` // 0 - все записи
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *GetChangesRequest) Reset() {
+	*x = GetChangesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChangesRequest) ProtoMessage() {}
+
+func (x *GetChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChangesRequest.ProtoReflect.Descriptor instead.
+func (*GetChangesRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetChangesRequest) GetSinceRevision() int64 {
+	if x != nil {
+		return x.SinceRevision
+	}
+	return 0
+}
+
+func (x *GetChangesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Tombstone - след удалённой записи.
+type Tombstone struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int32                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Revision  int64                `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	DeletedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+}
+
+func (x *Tombstone) Reset() {
+	*x = Tombstone{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Tombstone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tombstone) ProtoMessage() {}
+
+func (x *Tombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tombstone.ProtoReflect.Descriptor instead.
+func (*Tombstone) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Tombstone) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Tombstone) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *Tombstone) GetDeletedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type GetChangesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Upserts   []*DataItem  `protobuf:"bytes,1,rep,name=upserts,proto3" json:"upserts,omitempty"`
+	Deletions []*Tombstone `protobuf:"bytes,2,rep,name=deletions,proto3" json:"deletions,omitempty"`
+	Revision  int64        `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`              // since_revision для следующего запроса
+	HasMore   bool         `protobuf:"varint,4,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"` // изменения не поместились в limit
+}
+
+func (x *GetChangesResponse) Reset() {
+	*x = GetChangesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChangesResponse) ProtoMessage() {}
+
+func (x *GetChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChangesResponse.ProtoReflect.Descriptor instead.
+func (*GetChangesResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetChangesResponse) GetUpserts() []*DataItem {
+	if x != nil {
+		return x.Upserts
+	}
+	return nil
+}
+
+func (x *GetChangesResponse) GetDeletions() []*Tombstone {
+	if x != nil {
+		return x.Deletions
+	}
+	return nil
+}
+
+func (x *GetChangesResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *GetChangesResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// MoveDataRequest - перенос записи в другое хранилище. Клиент заново
+// шифрует info и meta ключом целевого хранилища и передаёт их в data.
+type MoveDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data    *DataItem `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`                       // id, version и новое содержимое записи
+	VaultId int32     `protobuf:"varint,2,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"` // 0 - в личные данные
+}
+
+func (x *MoveDataRequest) Reset() {
+	*x = MoveDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveDataRequest) ProtoMessage() {}
+
+func (x *MoveDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveDataRequest.ProtoReflect.Descriptor instead.
+func (*MoveDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MoveDataRequest) GetData() *DataItem {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *MoveDataRequest) GetVaultId() int32 {
+	if x != nil {
+		return x.VaultId
+	}
+	return 0
+}
+
+type MoveDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version int64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *MoveDataResponse) Reset() {
+	*x = MoveDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveDataResponse) ProtoMessage() {}
+
+func (x *MoveDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveDataResponse.ProtoReflect.Descriptor instead.
+func (*MoveDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *MoveDataResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ListFoldersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListFoldersRequest) Reset() {
+	*x = ListFoldersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFoldersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFoldersRequest) ProtoMessage() {}
+
+func (x *ListFoldersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFoldersRequest.ProtoReflect.Descriptor instead.
+func (*ListFoldersRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{27}
+}
+
+type ListFoldersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Folders []string `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"` // пути непустых папок по алфавиту
+}
+
+func (x *ListFoldersResponse) Reset() {
+	*x = ListFoldersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFoldersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFoldersResponse) ProtoMessage() {}
+
+func (x *ListFoldersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFoldersResponse.ProtoReflect.Descriptor instead.
+func (*ListFoldersResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListFoldersResponse) GetFolders() []string {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+// SearchDataRequest - поиск по слепому индексу: находятся записи,
+// у которых есть все tokens.
+type SearchDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tokens   []string `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`                   // HMAC слов запроса тем же ключом, что при записи
+	VaultId  int32    `protobuf:"varint,2,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"` // 0 - личные данные, иначе записи общего хранилища
+	PageSize int32    `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Cursor   string   `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor из предыдущего ответа
+}
+
+func (x *SearchDataRequest) Reset() {
+	*x = SearchDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchDataRequest) ProtoMessage() {}
+
+func (x *SearchDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchDataRequest.ProtoReflect.Descriptor instead.
+func (*SearchDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SearchDataRequest) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *SearchDataRequest) GetVaultId() int32 {
+	if x != nil {
+		return x.VaultId
+	}
+	return 0
+}
+
+func (x *SearchDataRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchDataRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type SearchDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items      []*DataHeader `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextCursor string        `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+}
+
+func (x *SearchDataResponse) Reset() {
+	*x = SearchDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchDataResponse) ProtoMessage() {}
+
+func (x *SearchDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchDataResponse.ProtoReflect.Descriptor instead.
+func (*SearchDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SearchDataResponse) GetItems() []*DataHeader {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *SearchDataResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// IndexDataRequest - замена токенов слепого индекса записи без изменения
+// её содержимого и версии, например для записей, созданных до появления поиска.
+type IndexDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SearchTokens []string `protobuf:"bytes,2,rep,name=search_tokens,json=searchTokens,proto3" json:"search_tokens,omitempty"`
+}
+
+func (x *IndexDataRequest) Reset() {
+	*x = IndexDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexDataRequest) ProtoMessage() {}
+
+func (x *IndexDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexDataRequest.ProtoReflect.Descriptor instead.
+func (*IndexDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *IndexDataRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *IndexDataRequest) GetSearchTokens() []string {
+	if x != nil {
+		return x.SearchTokens
+	}
+	return nil
+}
+
+type IndexDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *IndexDataResponse) Reset() {
+	*x = IndexDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexDataResponse) ProtoMessage() {}
+
+func (x *IndexDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexDataResponse.ProtoReflect.Descriptor instead.
+func (*IndexDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{32}
+}
+
+// DataVersion - прежняя версия записи. info и meta зашифрованы клиентом
+// тем же ключом, что и текущая версия.
+type DataVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version  int64                `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	InfoType string               `protobuf:"bytes,2,opt,name=info_type,json=infoType,proto3" json:"info_type,omitempty"`
+	Info     string               `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
+	Meta     string               `protobuf:"bytes,4,opt,name=meta,proto3" json:"meta,omitempty"`
+	SavedAt  *timestamp.Timestamp `protobuf:"bytes,5,opt,name=saved_at,json=savedAt,proto3" json:"saved_at,omitempty"` // когда эта версия была записана
+}
+
+func (x *DataVersion) Reset() {
+	*x = DataVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DataVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataVersion) ProtoMessage() {}
+
+func (x *DataVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataVersion.ProtoReflect.Descriptor instead.
+func (*DataVersion) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DataVersion) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DataVersion) GetInfoType() string {
+	if x != nil {
+		return x.InfoType
+	}
+	return ""
+}
+
+func (x *DataVersion) GetInfo() string {
+	if x != nil {
+		return x.Info
+	}
+	return ""
+}
+
+func (x *DataVersion) GetMeta() string {
+	if x != nil {
+		return x.Meta
+	}
+	return ""
+}
+
+func (x *DataVersion) GetSavedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.SavedAt
+	}
+	return nil
+}
+
+type ListVersionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ListVersionsRequest) Reset() {
+	*x = ListVersionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsRequest) ProtoMessage() {}
+
+func (x *ListVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListVersionsRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListVersionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Versions []*DataVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`               // от новых к старым
+	VaultId  int32          `protobuf:"varint,2,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"` // хранилище записи, по нему клиент выбирает ключ
+}
+
+func (x *ListVersionsResponse) Reset() {
+	*x = ListVersionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsResponse) ProtoMessage() {}
+
+func (x *ListVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListVersionsResponse) GetVersions() []*DataVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *ListVersionsResponse) GetVaultId() int32 {
+	if x != nil {
+		return x.VaultId
+	}
+	return 0
+}
+
+// RestoreVersionRequest - замена содержимого записи прежней версией.
+// Текущее содержимое при этом само попадает в историю.
+type RestoreVersionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // версия из ListVersions
+}
+
+func (x *RestoreVersionRequest) Reset() {
+	*x = RestoreVersionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVersionRequest) ProtoMessage() {}
+
+func (x *RestoreVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVersionRequest.ProtoReflect.Descriptor instead.
+func (*RestoreVersionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RestoreVersionRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RestoreVersionRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type RestoreVersionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version int64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"` // новая версия записи
+}
+
+func (x *RestoreVersionResponse) Reset() {
+	*x = RestoreVersionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVersionResponse) ProtoMessage() {}
+
+func (x *RestoreVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVersionResponse.ProtoReflect.Descriptor instead.
+func (*RestoreVersionResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RestoreVersionResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// ListTrashRequest - удалённые записи, которые ещё можно восстановить:
+// личные или общего хранилища vault_id.
+type ListTrashRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VaultId int32 `protobuf:"varint,1,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"`
+}
+
+func (x *ListTrashRequest) Reset() {
+	*x = ListTrashRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashRequest) ProtoMessage() {}
+
+func (x *ListTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashRequest.ProtoReflect.Descriptor instead.
+func (*ListTrashRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListTrashRequest) GetVaultId() int32 {
+	if x != nil {
+		return x.VaultId
+	}
+	return 0
+}
+
+type ListTrashResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*DataHeader `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"` // от недавно удалённых к давним
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListTrashResponse) GetItems() []*DataHeader {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// RestoreDataRequest - возврат записи из корзины.
+type RestoreDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *RestoreDataRequest) Reset() {
+	*x = RestoreDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreDataRequest) ProtoMessage() {}
+
+func (x *RestoreDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreDataRequest.ProtoReflect.Descriptor instead.
+func (*RestoreDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RestoreDataRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RestoreDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version int64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"` // новая версия записи
+}
+
+func (x *RestoreDataResponse) Reset() {
+	*x = RestoreDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreDataResponse) ProtoMessage() {}
+
+func (x *RestoreDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreDataResponse.ProtoReflect.Descriptor instead.
+func (*RestoreDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RestoreDataResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// PurgeDataRequest - окончательное удаление записи из корзины.
+type PurgeDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *PurgeDataRequest) Reset() {
+	*x = PurgeDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDataRequest) ProtoMessage() {}
+
+func (x *PurgeDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDataRequest.ProtoReflect.Descriptor instead.
+func (*PurgeDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PurgeDataRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type PurgeDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PurgeDataResponse) Reset() {
+	*x = PurgeDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_data_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDataResponse) ProtoMessage() {}
+
+func (x *PurgeDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_data_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDataResponse.ProtoReflect.Descriptor instead.
+func (*PurgeDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_data_proto_rawDescGZIP(), []int{43}
+}
+
+var File_api_proto_data_proto protoreflect.FileDescriptor
+
+var file_api_proto_data_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x61, 0x74, 0x61,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x74, 0x0a,
+	0x0d, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c,
+	0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
+	0x72, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x70, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x70, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x22, 0x20, 0x0a, 0x04, 0x54, 0x65, 0x78, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x4e, 0x0a, 0x06, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12,
+	0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d,
+	0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x69, 0x6d, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x22, 0x64, 0x0a, 0x08, 0x42, 0x61, 0x6e, 0x6b, 0x43, 0x61, 0x72,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x6f, 0x6c, 0x64, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x76, 0x76,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x76, 0x76, 0x22, 0xd0, 0x04, 0x0a, 0x08,
+	0x44, 0x61, 0x74, 0x61, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x66, 0x6f,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x66,
+	0x6f, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x74,
+	0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x34, 0x0a,
+	0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x12, 0x3c, 0x0a, 0x0e, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x64, 0x61,
+	0x74, 0x61, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x48, 0x00, 0x52, 0x0d, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x12, 0x20, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x54, 0x65, 0x78, 0x74, 0x48, 0x00, 0x52, 0x04, 0x74,
+	0x65, 0x78, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72,
+	0x79, 0x48, 0x00, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x2d, 0x0a, 0x09, 0x62,
+	0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x43, 0x61, 0x72, 0x64, 0x48, 0x00,
+	0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x61, 0x72, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x76,
+	0x61, 0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76,
+	0x61, 0x75, 0x6c, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x0e,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f,
+	0x6c, 0x64, 0x65, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x76, 0x6f, 0x72, 0x69, 0x74, 0x65, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x66, 0x61, 0x76, 0x6f, 0x72, 0x69, 0x74, 0x65, 0x12, 0x23,
+	0x0a, 0x0d, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18,
+	0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x34,
+	0x0a, 0x0e, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x22, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0x21, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x20, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x35, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x61, 0x74,
+	0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x22, 0x37, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x49,
+	0x74, 0x65, 0x6d, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x2e, 0x0a, 0x12, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x3d, 0x0a, 0x11, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x4a, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x22, 0x14, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xe9, 0x02, 0x0a, 0x0f, 0x4c,
+	0x69, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x69, 0x6e, 0x66, 0x6f, 0x54, 0x79, 0x70, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x54, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65,
+	0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x76, 0x61,
+	0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x61,
+	0x75, 0x6c, 0x74, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65,
+	0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12,
+	0x25, 0x0a, 0x0e, 0x66, 0x61, 0x76, 0x6f, 0x72, 0x69, 0x74, 0x65, 0x73, 0x5f, 0x6f, 0x6e, 0x6c,
+	0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x66, 0x61, 0x76, 0x6f, 0x72, 0x69, 0x74,
+	0x65, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x22, 0x86, 0x02, 0x0a, 0x0a, 0x44, 0x61, 0x74, 0x61, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x66, 0x6f, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x76, 0x6f,
+	0x72, 0x69, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x66, 0x61, 0x76, 0x6f,
+	0x72, 0x69, 0x74, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22,
+	0x5b, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0xcd, 0x01, 0x0a,
+	0x0c, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x6e, 0x66,
+	0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61,
+	0x32, 0x35, 0x36, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35,
+	0x36, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x22, 0x63, 0x0a, 0x13,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72,
+	0x79, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x06, 0x0a, 0x04, 0x70, 0x61, 0x72,
+	0x74, 0x22, 0x26, 0x0a, 0x14, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x69, 0x6e, 0x61, 0x72,
+	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x27, 0x0a, 0x15, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x66, 0x0a, 0x16, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x69,
+	0x6e, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64,
+	0x61, 0x74, 0x61, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x42, 0x06, 0x0a, 0x04, 0x70, 0x61, 0x72, 0x74, 0x22, 0x50, 0x0a, 0x11, 0x47, 0x65,
+	0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x25, 0x0a, 0x0e, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x5f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x72, 0x0a, 0x09,
+	0x54, 0x6f, 0x6d, 0x62, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0xa4, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x75, 0x70, 0x73, 0x65, 0x72,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e,
+	0x44, 0x61, 0x74, 0x61, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x07, 0x75, 0x70, 0x73, 0x65, 0x72, 0x74,
+	0x73, 0x12, 0x2d, 0x0a, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x54, 0x6f, 0x6d, 0x62,
+	0x73, 0x74, 0x6f, 0x6e, 0x65, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08,
+	0x68, 0x61, 0x73, 0x5f, 0x6d, 0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x68, 0x61, 0x73, 0x4d, 0x6f, 0x72, 0x65, 0x22, 0x50, 0x0a, 0x0f, 0x4d, 0x6f, 0x76, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e,
+	0x44, 0x61, 0x74, 0x61, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x19,
+	0x0a, 0x08, 0x76, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x76, 0x61, 0x75, 0x6c, 0x74, 0x49, 0x64, 0x22, 0x2c, 0x0a, 0x10, 0x4d, 0x6f, 0x76,
+	0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x14, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x46,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2f, 0x0a,
+	0x13, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x22, 0x7b,
+	0x0a, 0x11, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x76,
+	0x61, 0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76,
+	0x61, 0x75, 0x6c, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0x5d, 0x0a, 0x12, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x26, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78,
+	0x74, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0x47, 0x0a, 0x10, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x23,
+	0x0a, 0x0d, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x73, 0x22, 0x13, 0x0a, 0x11, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x44, 0x61, 0x74, 0x61,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa3, 0x01, 0x0a, 0x0b, 0x44, 0x61, 0x74,
+	0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x66, 0x6f, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69,
+	0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x61, 0x76, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x73, 0x61, 0x76, 0x65, 0x64, 0x41, 0x74, 0x22, 0x25,
+	0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x60, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a,
+	0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x19, 0x0a, 0x08,
+	0x76, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x76, 0x61, 0x75, 0x6c, 0x74, 0x49, 0x64, 0x22, 0x41, 0x0a, 0x15, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x32, 0x0a, 0x16, 0x52, 0x65,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x2d,
+	0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x76, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x61, 0x75, 0x6c, 0x74, 0x49, 0x64, 0x22, 0x3b, 0x0a,
+	0x11, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x73, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x24, 0x0a, 0x12, 0x52, 0x65,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x2f, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x22, 0x0a, 0x10, 0x50, 0x75, 0x72, 0x67, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x13, 0x0a, 0x11, 0x50, 0x75, 0x72, 0x67, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xe5, 0x08, 0x0a, 0x0b, 0x44,
+	0x61, 0x74, 0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x41, 0x64,
+	0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x14, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x41, 0x64, 0x64,
+	0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x64, 0x61,
+	0x74, 0x61, 0x2e, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x14, 0x2e,
+	0x64, 0x61, 0x74, 0x61, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0a, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x17, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0a, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x17, 0x2e, 0x64, 0x61, 0x74, 0x61,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x18, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x08,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x15, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0c, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x19, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01,
+	0x12, 0x4d, 0x0a, 0x0e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x69, 0x6e, 0x61,
+	0x72, 0x79, 0x12, 0x1b, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42,
+	0x69, 0x6e, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12,
+	0x3f, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x17, 0x2e,
+	0x64, 0x61, 0x74, 0x61, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x47, 0x65,
+	0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x39, 0x0a, 0x08, 0x4d, 0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x15, 0x2e, 0x64,
+	0x61, 0x74, 0x61, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x4c,
+	0x69, 0x73, 0x74, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x12, 0x18, 0x2e, 0x64, 0x61, 0x74,
+	0x61, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3f, 0x0a, 0x0a, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x61, 0x74, 0x61, 0x12, 0x17, 0x2e,
+	0x64, 0x61, 0x74, 0x61, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3c, 0x0a, 0x09, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x44, 0x61, 0x74, 0x61, 0x12, 0x16, 0x2e,
+	0x64, 0x61, 0x74, 0x61, 0x2e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45,
+	0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x19,
+	0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x64, 0x61, 0x74, 0x61,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x52,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x52, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x73, 0x68, 0x12,
+	0x16, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x73, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x73, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x42, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x18, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x61, 0x74, 0x61,
+	0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x50, 0x75, 0x72, 0x67, 0x65, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x16, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x64, 0x61, 0x74, 0x61,
+	0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x0c, 0x5a, 0x0a, 0x61, 0x70, 0x69, 0x2f, 0x64, 0x61, 0x74, 0x61, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_proto_data_proto_rawDescOnce sync.Once
+	file_api_proto_data_proto_rawDescData = file_api_proto_data_proto_rawDesc
+)
+
+func file_api_proto_data_proto_rawDescGZIP() []byte {
+	file_api_proto_data_proto_rawDescOnce.Do(func() {
+		file_api_proto_data_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_proto_data_proto_rawDescData)
+	})
+	return file_api_proto_data_proto_rawDescData
+}
+
+var file_api_proto_data_proto_msgTypes = make([]protoimpl.MessageInfo, 44)
+var file_api_proto_data_proto_goTypes = []any{
+	(*LoginPassword)(nil),          // 0: data.LoginPassword
+	(*Text)(nil),                   // 1: data.Text
+	(*Binary)(nil),                 // 2: data.Binary
+	(*BankCard)(nil),               // 3: data.BankCard
+	(*DataItem)(nil),               // 4: data.DataItem
+	(*AddDataRequest)(nil),         // 5: data.AddDataRequest
+	(*AddDataResponse)(nil),        // 6: data.AddDataResponse
+	(*GetDataRequest)(nil),         // 7: data.GetDataRequest
+	(*GetDataResponse)(nil),        // 8: data.GetDataResponse
+	(*UpdateDataRequest)(nil),      // 9: data.UpdateDataRequest
+	(*UpdateDataResponse)(nil),     // 10: data.UpdateDataResponse
+	(*DeleteDataRequest)(nil),      // 11: data.DeleteDataRequest
+	(*VersionConflict)(nil),        // 12: data.VersionConflict
+	(*DeleteDataResponse)(nil),     // 13: data.DeleteDataResponse
+	(*ListDataRequest)(nil),        // 14: data.ListDataRequest
+	(*DataHeader)(nil),             // 15: data.DataHeader
+	(*ListDataResponse)(nil),       // 16: data.ListDataResponse
+	(*BinaryHeader)(nil),           // 17: data.BinaryHeader
+	(*UploadBinaryRequest)(nil),    // 18: data.UploadBinaryRequest
+	(*UploadBinaryResponse)(nil),   // 19: data.UploadBinaryResponse
+	(*DownloadBinaryRequest)(nil),  // 20: data.DownloadBinaryRequest
+	(*DownloadBinaryResponse)(nil), // 21: data.DownloadBinaryResponse
+	(*GetChangesRequest)(nil),      // 22: data.GetChangesRequest
+	(*Tombstone)(nil),              // 23: data.Tombstone
+	(*GetChangesResponse)(nil),     // 24: data.GetChangesResponse
+	(*MoveDataRequest)(nil),        // 25: data.MoveDataRequest
+	(*MoveDataResponse)(nil),       // 26: data.MoveDataResponse
+	(*ListFoldersRequest)(nil),     // 27: data.ListFoldersRequest
+	(*ListFoldersResponse)(nil),    // 28: data.ListFoldersResponse
+	(*SearchDataRequest)(nil),      // 29: data.SearchDataRequest
+	(*SearchDataResponse)(nil),     // 30: data.SearchDataResponse
+	(*IndexDataRequest)(nil),       // 31: data.IndexDataRequest
+	(*IndexDataResponse)(nil),      // 32: data.IndexDataResponse
+	(*DataVersion)(nil),            // 33: data.DataVersion
+	(*ListVersionsRequest)(nil),    // 34: data.ListVersionsRequest
+	(*ListVersionsResponse)(nil),   // 35: data.ListVersionsResponse
+	(*RestoreVersionRequest)(nil),  // 36: data.RestoreVersionRequest
+	(*RestoreVersionResponse)(nil), // 37: data.RestoreVersionResponse
+	(*ListTrashRequest)(nil),       // 38: data.ListTrashRequest
+	(*ListTrashResponse)(nil),      // 39: data.ListTrashResponse
+	(*RestoreDataRequest)(nil),     // 40: data.RestoreDataRequest
+	(*RestoreDataResponse)(nil),    // 41: data.RestoreDataResponse
+	(*PurgeDataRequest)(nil),       // 42: data.PurgeDataRequest
+	(*PurgeDataResponse)(nil),      // 43: data.PurgeDataResponse
+	(*timestamp.Timestamp)(nil),    // 44: google.protobuf.Timestamp
+}
+var file_api_proto_data_proto_depIdxs = []int32{
+	44, // 0: data.DataItem.created:type_name -> google.protobuf.Timestamp
+	0,  // 1: data.DataItem.login_password:type_name -> data.LoginPassword
+	1,  // 2: data.DataItem.text:type_name -> data.Text
+	2,  // 3: data.DataItem.binary:type_name -> data.Binary
+	3,  // 4: data.DataItem.bank_card:type_name -> data.BankCard
+	44, // 5: data.DataItem.updated_at:type_name -> google.protobuf.Timestamp
+	4,  // 6: data.AddDataRequest.data:type_name -> data.DataItem
+	4,  // 7: data.GetDataResponse.data:type_name -> data.DataItem
+	4,  // 8: data.UpdateDataRequest.data:type_name -> data.DataItem
+	44, // 9: data.ListDataRequest.created_from:type_name -> google.protobuf.Timestamp
+	44, // 10: data.ListDataRequest.created_to:type_name -> google.protobuf.Timestamp
+	44, // 11: data.DataHeader.created:type_name -> google.protobuf.Timestamp
+	44, // 12: data.DataHeader.deleted_at:type_name -> google.protobuf.Timestamp
+	15, // 13: data.ListDataResponse.items:type_name -> data.DataHeader
+	44, // 14: data.BinaryHeader.created:type_name -> google.protobuf.Timestamp
+	17, // 15: data.UploadBinaryRequest.header:type_name -> data.BinaryHeader
+	17, // 16: data.DownloadBinaryResponse.header:type_name -> data.BinaryHeader
+	44, // 17: data.Tombstone.deleted_at:type_name -> google.protobuf.Timestamp
+	4,  // 18: data.GetChangesResponse.upserts:type_name -> data.DataItem
+	23, // 19: data.GetChangesResponse.deletions:type_name -> data.Tombstone
+	4,  // 20: data.MoveDataRequest.data:type_name -> data.DataItem
+	15, // 21: data.SearchDataResponse.items:type_name -> data.DataHeader
+	44, // 22: data.DataVersion.saved_at:type_name -> google.protobuf.Timestamp
+	33, // 23: data.ListVersionsResponse.versions:type_name -> data.DataVersion
+	15, // 24: data.ListTrashResponse.items:type_name -> data.DataHeader
+	5,  // 25: data.DataService.AddData:input_type -> data.AddDataRequest
+	7,  // 26: data.DataService.GetData:input_type -> data.GetDataRequest
+	9,  // 27: data.DataService.UpdateData:input_type -> data.UpdateDataRequest
+	11, // 28: data.DataService.DeleteData:input_type -> data.DeleteDataRequest
+	14, // 29: data.DataService.ListData:input_type -> data.ListDataRequest
+	18, // 30: data.DataService.UploadBinary:input_type -> data.UploadBinaryRequest
+	20, // 31: data.DataService.DownloadBinary:input_type -> data.DownloadBinaryRequest
+	22, // 32: data.DataService.GetChanges:input_type -> data.GetChangesRequest
+	25, // 33: data.DataService.MoveData:input_type -> data.MoveDataRequest
+	27, // 34: data.DataService.ListFolders:input_type -> data.ListFoldersRequest
+	29, // 35: data.DataService.SearchData:input_type -> data.SearchDataRequest
+	31, // 36: data.DataService.IndexData:input_type -> data.IndexDataRequest
+	34, // 37: data.DataService.ListVersions:input_type -> data.ListVersionsRequest
+	36, // 38: data.DataService.RestoreVersion:input_type -> data.RestoreVersionRequest
+	38, // 39: data.DataService.ListTrash:input_type -> data.ListTrashRequest
+	40, // 40: data.DataService.RestoreData:input_type -> data.RestoreDataRequest
+	42, // 41: data.DataService.PurgeData:input_type -> data.PurgeDataRequest
+	6,  // 42: data.DataService.AddData:output_type -> data.AddDataResponse
+	8,  // 43: data.DataService.GetData:output_type -> data.GetDataResponse
+	10, // 44: data.DataService.UpdateData:output_type -> data.UpdateDataResponse
+	13, // 45: data.DataService.DeleteData:output_type -> data.DeleteDataResponse
+	16, // 46: data.DataService.ListData:output_type -> data.ListDataResponse
+	19, // 47: data.DataService.UploadBinary:output_type -> data.UploadBinaryResponse
+	21, // 48: data.DataService.DownloadBinary:output_type -> data.DownloadBinaryResponse
+	24, // 49: data.DataService.GetChanges:output_type -> data.GetChangesResponse
+	26, // 50: data.DataService.MoveData:output_type -> data.MoveDataResponse
+	28, // 51: data.DataService.ListFolders:output_type -> data.ListFoldersResponse
+	30, // 52: data.DataService.SearchData:output_type -> data.SearchDataResponse
+	32, // 53: data.DataService.IndexData:output_type -> data.IndexDataResponse
+	35, // 54: data.DataService.ListVersions:output_type -> data.ListVersionsResponse
+	37, // 55: data.DataService.RestoreVersion:output_type -> data.RestoreVersionResponse
+	39, // 56: data.DataService.ListTrash:output_type -> data.ListTrashResponse
+	41, // 57: data.DataService.RestoreData:output_type -> data.RestoreDataResponse
+	43, // 58: data.DataService.PurgeData:output_type -> data.PurgeDataResponse
+	42, // [42:59] is the sub-list for method output_type
+	25, // [25:42] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_data_proto_init() }
+func file_api_proto_data_proto_init() {
+	if File_api_proto_data_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_proto_data_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*LoginPassword); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Text); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Binary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*BankCard); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*DataItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*AddDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*AddDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*GetDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*GetDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*VersionConflict); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*ListDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*DataHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*ListDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*BinaryHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*UploadBinaryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*UploadBinaryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*DownloadBinaryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*DownloadBinaryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use DeleteDataResponse.ProtoReflect.Descriptor instead.
-func (*DeleteDataResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_data_proto_rawDescGZIP(), []int{8}
-}
-
-var File_api_proto_data_proto protoreflect.FileDescriptor
-
-var file_api_proto_data_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x61, 0x74, 0x61,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x1f, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x95, 0x01,
-	0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e,
-	0x66, 0x6f, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69,
-	0x6e, 0x66, 0x6f, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6d,
-	0x65, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12,
-	0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x64, 0x22, 0x34, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74,
-	0x61, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x21, 0x0a, 0x0f, 0x41,
-	0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x20,
-	0x0a, 0x0e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
-	0x22, 0x35, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x49, 0x74, 0x65,
-	0x6d, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x37, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x04,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x61, 0x74,
-	0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x22, 0x14, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x14, 0x0a, 0x12, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x32, 0xff, 0x01, 0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x12, 0x36, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x14, 0x2e, 0x64,
-	0x61, 0x74, 0x61, 0x2e, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x15, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74,
-	0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x47, 0x65, 0x74,
-	0x44, 0x61, 0x74, 0x61, 0x12, 0x14, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x47, 0x65, 0x74, 0x44,
-	0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x64, 0x61, 0x74,
-	0x61, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x3f, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12,
-	0x17, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74,
-	0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61,
-	0x12, 0x17, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61,
-	0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x64, 0x61, 0x74, 0x61,
-	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x0c, 0x5a, 0x0a, 0x61, 0x70, 0x69, 0x2f, 0x64, 0x61, 0x74, 0x61, 0x70,
-	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_api_proto_data_proto_rawDescOnce sync.Once
-	file_api_proto_data_proto_rawDescData = file_api_proto_data_proto_rawDesc
-)
-
-func file_api_proto_data_proto_rawDescGZIP() []byte {
-	file_api_proto_data_proto_rawDescOnce.Do(func() {
-		file_api_proto_data_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_proto_data_proto_rawDescData)
-	})
-	return file_api_proto_data_proto_rawDescData
-}
-
-var file_api_proto_data_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
-var file_api_proto_data_proto_goTypes = []any{
-	(*DataItem)(nil),            // 0: data.DataItem
-	(*AddDataRequest)(nil),      // 1: data.AddDataRequest
-	(*AddDataResponse)(nil),     // 2: data.AddDataResponse
-	(*GetDataRequest)(nil),      // 3: data.GetDataRequest
-	(*GetDataResponse)(nil),     // 4: data.GetDataResponse
-	(*UpdateDataRequest)(nil),   // 5: data.UpdateDataRequest
-	(*UpdateDataResponse)(nil),  // 6: data.UpdateDataResponse
-	(*DeleteDataRequest)(nil),   // 7: data.DeleteDataRequest
-	(*DeleteDataResponse)(nil),  // 8: data.DeleteDataResponse
-	(*timestamp.Timestamp)(nil), // 9: google.protobuf.Timestamp
-}
-var file_api_proto_data_proto_depIdxs = []int32{
-	9, // 0: data.DataItem.created:type_name -> google.protobuf.Timestamp
-	0, // 1: data.AddDataRequest.data:type_name -> data.DataItem
-	0, // 2: data.GetDataResponse.data:type_name -> data.DataItem
-	0, // 3: data.UpdateDataRequest.data:type_name -> data.DataItem
-	1, // 4: data.DataService.AddData:input_type -> data.AddDataRequest
-	3, // 5: data.DataService.GetData:input_type -> data.GetDataRequest
-	5, // 6: data.DataService.UpdateData:input_type -> data.UpdateDataRequest
-	7, // 7: data.DataService.DeleteData:input_type -> data.DeleteDataRequest
-	2, // 8: data.DataService.AddData:output_type -> data.AddDataResponse
-	4, // 9: data.DataService.GetData:output_type -> data.GetDataResponse
-	6, // 10: data.DataService.UpdateData:output_type -> data.UpdateDataResponse
-	8, // 11: data.DataService.DeleteData:output_type -> data.DeleteDataResponse
-	8, // [8:12] is the sub-list for method output_type
-	4, // [4:8] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
-}
-
-func init() { file_api_proto_data_proto_init() }
-func file_api_proto_data_proto_init() {
-	if File_api_proto_data_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_api_proto_data_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*DataItem); i {
+		file_api_proto_data_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*GetChangesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -577,8 +3463,8 @@ func file_api_proto_data_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_data_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*AddDataRequest); i {
+		file_api_proto_data_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*Tombstone); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -589,8 +3475,8 @@ func file_api_proto_data_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_data_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*AddDataResponse); i {
+		file_api_proto_data_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*GetChangesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -601,8 +3487,8 @@ func file_api_proto_data_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_data_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*GetDataRequest); i {
+		file_api_proto_data_proto_msgTypes[25].Exporter = func(v any, i int) any {
+			switch v := v.(*MoveDataRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -613,8 +3499,8 @@ func file_api_proto_data_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_data_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*GetDataResponse); i {
+		file_api_proto_data_proto_msgTypes[26].Exporter = func(v any, i int) any {
+			switch v := v.(*MoveDataResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -625,8 +3511,8 @@ func file_api_proto_data_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_data_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*UpdateDataRequest); i {
+		file_api_proto_data_proto_msgTypes[27].Exporter = func(v any, i int) any {
+			switch v := v.(*ListFoldersRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -637,8 +3523,8 @@ func file_api_proto_data_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_data_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*UpdateDataResponse); i {
+		file_api_proto_data_proto_msgTypes[28].Exporter = func(v any, i int) any {
+			switch v := v.(*ListFoldersResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -649,8 +3535,8 @@ func file_api_proto_data_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_data_proto_msgTypes[7].Exporter = func(v any, i int) any {
-			switch v := v.(*DeleteDataRequest); i {
+		file_api_proto_data_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchDataRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -661,8 +3547,164 @@ func file_api_proto_data_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_data_proto_msgTypes[8].Exporter = func(v any, i int) any {
-			switch v := v.(*DeleteDataResponse); i {
+		file_api_proto_data_proto_msgTypes[30].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[31].Exporter = func(v any, i int) any {
+			switch v := v.(*IndexDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[32].Exporter = func(v any, i int) any {
+			switch v := v.(*IndexDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[33].Exporter = func(v any, i int) any {
+			switch v := v.(*DataVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[34].Exporter = func(v any, i int) any {
+			switch v := v.(*ListVersionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[35].Exporter = func(v any, i int) any {
+			switch v := v.(*ListVersionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[36].Exporter = func(v any, i int) any {
+			switch v := v.(*RestoreVersionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[37].Exporter = func(v any, i int) any {
+			switch v := v.(*RestoreVersionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[38].Exporter = func(v any, i int) any {
+			switch v := v.(*ListTrashRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[39].Exporter = func(v any, i int) any {
+			switch v := v.(*ListTrashResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[40].Exporter = func(v any, i int) any {
+			switch v := v.(*RestoreDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[41].Exporter = func(v any, i int) any {
+			switch v := v.(*RestoreDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[42].Exporter = func(v any, i int) any {
+			switch v := v.(*PurgeDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_data_proto_msgTypes[43].Exporter = func(v any, i int) any {
+			switch v := v.(*PurgeDataResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -674,13 +3716,27 @@ func file_api_proto_data_proto_init() {
 			}
 		}
 	}
+	file_api_proto_data_proto_msgTypes[4].OneofWrappers = []any{
+		(*DataItem_LoginPassword)(nil),
+		(*DataItem_Text)(nil),
+		(*DataItem_Binary)(nil),
+		(*DataItem_BankCard)(nil),
+	}
+	file_api_proto_data_proto_msgTypes[18].OneofWrappers = []any{
+		(*UploadBinaryRequest_Header)(nil),
+		(*UploadBinaryRequest_Chunk)(nil),
+	}
+	file_api_proto_data_proto_msgTypes[21].OneofWrappers = []any{
+		(*DownloadBinaryResponse_Header)(nil),
+		(*DownloadBinaryResponse_Chunk)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_proto_data_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   44,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
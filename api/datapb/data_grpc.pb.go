@@ -19,10 +19,23 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DataService_AddData_FullMethodName    = "/data.DataService/AddData"
-	DataService_GetData_FullMethodName    = "/data.DataService/GetData"
-	DataService_UpdateData_FullMethodName = "/data.DataService/UpdateData"
-	DataService_DeleteData_FullMethodName = "/data.DataService/DeleteData"
+	DataService_AddData_FullMethodName        = "/data.DataService/AddData"
+	DataService_GetData_FullMethodName        = "/data.DataService/GetData"
+	DataService_UpdateData_FullMethodName     = "/data.DataService/UpdateData"
+	DataService_DeleteData_FullMethodName     = "/data.DataService/DeleteData"
+	DataService_ListData_FullMethodName       = "/data.DataService/ListData"
+	DataService_UploadBinary_FullMethodName   = "/data.DataService/UploadBinary"
+	DataService_DownloadBinary_FullMethodName = "/data.DataService/DownloadBinary"
+	DataService_GetChanges_FullMethodName     = "/data.DataService/GetChanges"
+	DataService_MoveData_FullMethodName       = "/data.DataService/MoveData"
+	DataService_ListFolders_FullMethodName    = "/data.DataService/ListFolders"
+	DataService_SearchData_FullMethodName     = "/data.DataService/SearchData"
+	DataService_IndexData_FullMethodName      = "/data.DataService/IndexData"
+	DataService_ListVersions_FullMethodName   = "/data.DataService/ListVersions"
+	DataService_RestoreVersion_FullMethodName = "/data.DataService/RestoreVersion"
+	DataService_ListTrash_FullMethodName      = "/data.DataService/ListTrash"
+	DataService_RestoreData_FullMethodName    = "/data.DataService/RestoreData"
+	DataService_PurgeData_FullMethodName      = "/data.DataService/PurgeData"
 )
 
 // DataServiceClient is the client API for DataService service.
@@ -33,6 +46,19 @@ type DataServiceClient interface {
 	GetData(ctx context.Context, in *GetDataRequest, opts ...grpc.CallOption) (*GetDataResponse, error)
 	UpdateData(ctx context.Context, in *UpdateDataRequest, opts ...grpc.CallOption) (*UpdateDataResponse, error)
 	DeleteData(ctx context.Context, in *DeleteDataRequest, opts ...grpc.CallOption) (*DeleteDataResponse, error)
+	ListData(ctx context.Context, in *ListDataRequest, opts ...grpc.CallOption) (*ListDataResponse, error)
+	UploadBinary(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadBinaryRequest, UploadBinaryResponse], error)
+	DownloadBinary(ctx context.Context, in *DownloadBinaryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadBinaryResponse], error)
+	GetChanges(ctx context.Context, in *GetChangesRequest, opts ...grpc.CallOption) (*GetChangesResponse, error)
+	MoveData(ctx context.Context, in *MoveDataRequest, opts ...grpc.CallOption) (*MoveDataResponse, error)
+	ListFolders(ctx context.Context, in *ListFoldersRequest, opts ...grpc.CallOption) (*ListFoldersResponse, error)
+	SearchData(ctx context.Context, in *SearchDataRequest, opts ...grpc.CallOption) (*SearchDataResponse, error)
+	IndexData(ctx context.Context, in *IndexDataRequest, opts ...grpc.CallOption) (*IndexDataResponse, error)
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+	RestoreVersion(ctx context.Context, in *RestoreVersionRequest, opts ...grpc.CallOption) (*RestoreVersionResponse, error)
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	RestoreData(ctx context.Context, in *RestoreDataRequest, opts ...grpc.CallOption) (*RestoreDataResponse, error)
+	PurgeData(ctx context.Context, in *PurgeDataRequest, opts ...grpc.CallOption) (*PurgeDataResponse, error)
 }
 
 type dataServiceClient struct {
@@ -83,6 +109,148 @@ func (c *dataServiceClient) DeleteData(ctx context.Context, in *DeleteDataReques
 	return out, nil
 }
 
+func (c *dataServiceClient) ListData(ctx context.Context, in *ListDataRequest, opts ...grpc.CallOption) (*ListDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDataResponse)
+	err := c.cc.Invoke(ctx, DataService_ListData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) UploadBinary(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadBinaryRequest, UploadBinaryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DataService_ServiceDesc.Streams[0], DataService_UploadBinary_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadBinaryRequest, UploadBinaryResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_UploadBinaryClient = grpc.ClientStreamingClient[UploadBinaryRequest, UploadBinaryResponse]
+
+func (c *dataServiceClient) DownloadBinary(ctx context.Context, in *DownloadBinaryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadBinaryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DataService_ServiceDesc.Streams[1], DataService_DownloadBinary_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadBinaryRequest, DownloadBinaryResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_DownloadBinaryClient = grpc.ServerStreamingClient[DownloadBinaryResponse]
+
+func (c *dataServiceClient) GetChanges(ctx context.Context, in *GetChangesRequest, opts ...grpc.CallOption) (*GetChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetChangesResponse)
+	err := c.cc.Invoke(ctx, DataService_GetChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) MoveData(ctx context.Context, in *MoveDataRequest, opts ...grpc.CallOption) (*MoveDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveDataResponse)
+	err := c.cc.Invoke(ctx, DataService_MoveData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) ListFolders(ctx context.Context, in *ListFoldersRequest, opts ...grpc.CallOption) (*ListFoldersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFoldersResponse)
+	err := c.cc.Invoke(ctx, DataService_ListFolders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) SearchData(ctx context.Context, in *SearchDataRequest, opts ...grpc.CallOption) (*SearchDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchDataResponse)
+	err := c.cc.Invoke(ctx, DataService_SearchData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) IndexData(ctx context.Context, in *IndexDataRequest, opts ...grpc.CallOption) (*IndexDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IndexDataResponse)
+	err := c.cc.Invoke(ctx, DataService_IndexData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, DataService_ListVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) RestoreVersion(ctx context.Context, in *RestoreVersionRequest, opts ...grpc.CallOption) (*RestoreVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreVersionResponse)
+	err := c.cc.Invoke(ctx, DataService_RestoreVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, DataService_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) RestoreData(ctx context.Context, in *RestoreDataRequest, opts ...grpc.CallOption) (*RestoreDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreDataResponse)
+	err := c.cc.Invoke(ctx, DataService_RestoreData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) PurgeData(ctx context.Context, in *PurgeDataRequest, opts ...grpc.CallOption) (*PurgeDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeDataResponse)
+	err := c.cc.Invoke(ctx, DataService_PurgeData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DataServiceServer is the server API for DataService service.
 // All implementations must embed UnimplementedDataServiceServer
 // for forward compatibility.
@@ -91,6 +259,19 @@ type DataServiceServer interface {
 	GetData(context.Context, *GetDataRequest) (*GetDataResponse, error)
 	UpdateData(context.Context, *UpdateDataRequest) (*UpdateDataResponse, error)
 	DeleteData(context.Context, *DeleteDataRequest) (*DeleteDataResponse, error)
+	ListData(context.Context, *ListDataRequest) (*ListDataResponse, error)
+	UploadBinary(grpc.ClientStreamingServer[UploadBinaryRequest, UploadBinaryResponse]) error
+	DownloadBinary(*DownloadBinaryRequest, grpc.ServerStreamingServer[DownloadBinaryResponse]) error
+	GetChanges(context.Context, *GetChangesRequest) (*GetChangesResponse, error)
+	MoveData(context.Context, *MoveDataRequest) (*MoveDataResponse, error)
+	ListFolders(context.Context, *ListFoldersRequest) (*ListFoldersResponse, error)
+	SearchData(context.Context, *SearchDataRequest) (*SearchDataResponse, error)
+	IndexData(context.Context, *IndexDataRequest) (*IndexDataResponse, error)
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	RestoreVersion(context.Context, *RestoreVersionRequest) (*RestoreVersionResponse, error)
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	RestoreData(context.Context, *RestoreDataRequest) (*RestoreDataResponse, error)
+	PurgeData(context.Context, *PurgeDataRequest) (*PurgeDataResponse, error)
 	mustEmbedUnimplementedDataServiceServer()
 }
 
@@ -113,6 +294,45 @@ func (UnimplementedDataServiceServer) UpdateData(context.Context, *UpdateDataReq
 func (UnimplementedDataServiceServer) DeleteData(context.Context, *DeleteDataRequest) (*DeleteDataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteData not implemented")
 }
+func (UnimplementedDataServiceServer) ListData(context.Context, *ListDataRequest) (*ListDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListData not implemented")
+}
+func (UnimplementedDataServiceServer) UploadBinary(grpc.ClientStreamingServer[UploadBinaryRequest, UploadBinaryResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadBinary not implemented")
+}
+func (UnimplementedDataServiceServer) DownloadBinary(*DownloadBinaryRequest, grpc.ServerStreamingServer[DownloadBinaryResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadBinary not implemented")
+}
+func (UnimplementedDataServiceServer) GetChanges(context.Context, *GetChangesRequest) (*GetChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChanges not implemented")
+}
+func (UnimplementedDataServiceServer) MoveData(context.Context, *MoveDataRequest) (*MoveDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveData not implemented")
+}
+func (UnimplementedDataServiceServer) ListFolders(context.Context, *ListFoldersRequest) (*ListFoldersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFolders not implemented")
+}
+func (UnimplementedDataServiceServer) SearchData(context.Context, *SearchDataRequest) (*SearchDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchData not implemented")
+}
+func (UnimplementedDataServiceServer) IndexData(context.Context, *IndexDataRequest) (*IndexDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IndexData not implemented")
+}
+func (UnimplementedDataServiceServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedDataServiceServer) RestoreVersion(context.Context, *RestoreVersionRequest) (*RestoreVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreVersion not implemented")
+}
+func (UnimplementedDataServiceServer) ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedDataServiceServer) RestoreData(context.Context, *RestoreDataRequest) (*RestoreDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreData not implemented")
+}
+func (UnimplementedDataServiceServer) PurgeData(context.Context, *PurgeDataRequest) (*PurgeDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeData not implemented")
+}
 func (UnimplementedDataServiceServer) mustEmbedUnimplementedDataServiceServer() {}
 func (UnimplementedDataServiceServer) testEmbeddedByValue()                     {}
 
@@ -206,6 +426,222 @@ func _DataService_DeleteData_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DataService_ListData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).ListData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_ListData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).ListData(ctx, req.(*ListDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_UploadBinary_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DataServiceServer).UploadBinary(&grpc.GenericServerStream[UploadBinaryRequest, UploadBinaryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_UploadBinaryServer = grpc.ClientStreamingServer[UploadBinaryRequest, UploadBinaryResponse]
+
+func _DataService_DownloadBinary_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadBinaryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataServiceServer).DownloadBinary(m, &grpc.GenericServerStream[DownloadBinaryRequest, DownloadBinaryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_DownloadBinaryServer = grpc.ServerStreamingServer[DownloadBinaryResponse]
+
+func _DataService_GetChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).GetChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_GetChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).GetChanges(ctx, req.(*GetChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_MoveData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).MoveData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_MoveData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).MoveData(ctx, req.(*MoveDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_ListFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFoldersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).ListFolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_ListFolders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).ListFolders(ctx, req.(*ListFoldersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_SearchData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).SearchData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_SearchData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).SearchData(ctx, req.(*SearchDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_IndexData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IndexDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).IndexData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_IndexData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).IndexData(ctx, req.(*IndexDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_ListVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_RestoreVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).RestoreVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_RestoreVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).RestoreVersion(ctx, req.(*RestoreVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_RestoreData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).RestoreData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_RestoreData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).RestoreData(ctx, req.(*RestoreDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_PurgeData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).PurgeData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_PurgeData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).PurgeData(ctx, req.(*PurgeDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DataService_ServiceDesc is the grpc.ServiceDesc for DataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,7 +665,62 @@ var DataService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteData",
 			Handler:    _DataService_DeleteData_Handler,
 		},
+		{
+			MethodName: "ListData",
+			Handler:    _DataService_ListData_Handler,
+		},
+		{
+			MethodName: "GetChanges",
+			Handler:    _DataService_GetChanges_Handler,
+		},
+		{
+			MethodName: "MoveData",
+			Handler:    _DataService_MoveData_Handler,
+		},
+		{
+			MethodName: "ListFolders",
+			Handler:    _DataService_ListFolders_Handler,
+		},
+		{
+			MethodName: "SearchData",
+			Handler:    _DataService_SearchData_Handler,
+		},
+		{
+			MethodName: "IndexData",
+			Handler:    _DataService_IndexData_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _DataService_ListVersions_Handler,
+		},
+		{
+			MethodName: "RestoreVersion",
+			Handler:    _DataService_RestoreVersion_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _DataService_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreData",
+			Handler:    _DataService_RestoreData_Handler,
+		},
+		{
+			MethodName: "PurgeData",
+			Handler:    _DataService_PurgeData_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadBinary",
+			Handler:       _DataService_UploadBinary_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadBinary",
+			Handler:       _DataService_DownloadBinary_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/data.proto",
 }
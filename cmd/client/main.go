@@ -8,7 +8,10 @@ import (
 
 	"github.com/NikolosHGW/goph-keeper/internal/client/command"
 	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/client/infrastructure/cache"
 	"github.com/NikolosHGW/goph-keeper/internal/client/infrastructure/config"
+	"github.com/NikolosHGW/goph-keeper/internal/client/infrastructure/profile"
+	"github.com/NikolosHGW/goph-keeper/internal/client/infrastructure/session"
 	"github.com/NikolosHGW/goph-keeper/internal/client/service"
 	"github.com/NikolosHGW/goph-keeper/pkg/logger"
 )
@@ -21,7 +24,21 @@ func main() {
 		log.Fatalf("ошибка инициализации логгер: %v", err)
 	}
 
-	grpcClient, err := service.NewGRPCClient(config.GetServerAddress(), myLogger, config.GetRootCertPath())
+	// Профилями можно управлять, даже если сервер текущего профиля недоступен
+	// или его сертификата нет на диске, поэтому команда выполняется до подключения.
+	profileCommand := command.NewProfileCommand(
+		profile.NewFileStore(config.GetProfilesPath()), config.GetProfile(), os.Stdin, os.Stdout,
+	)
+	if args := config.GetArgs(); len(args) > 0 && args[0] == profileCommand.Name() {
+		os.Exit(command.NewCLI(nil, os.Stderr, profileCommand).Run(args))
+	}
+
+	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+
+	grpcClient, err := service.NewGRPCClient(
+		config.GetServerAddress(), myLogger, config.GetRootCertPath(), tokenHolder,
+	)
 	if err != nil {
 		myLogger.LogInfo("Ошибка инициализации gRPC клиента", err)
 		os.Exit(1)
@@ -33,18 +50,100 @@ func main() {
 		}
 	}()
 
-	tokenHolder := &entity.TokenHolder{}
-
 	authService := service.NewAuthService(grpcClient, myLogger)
-	dataService := service.NewDataService(grpcClient, myLogger)
+	cryptoService := service.NewCryptoService()
+	cachedDataService := service.NewCachedDataService(
+		service.NewDataService(grpcClient, myLogger),
+		cache.NewFileStore(config.GetCacheDir()),
+		keyHolder,
+	)
+	vaultService := service.NewVaultService(grpcClient, keyHolder)
+	dataService := service.NewEncryptedDataService(cachedDataService, keyHolder, vaultService)
+	shareService := service.NewShareService(grpcClient)
+	auditService := service.NewAuditService(grpcClient)
+	archiveService := service.NewArchiveService(dataService)
+	sessionService := service.NewSessionService(
+		session.NewFileStore(config.GetSessionDir()), config.GetProfile(), config.GetServerAddress(),
+	)
+
+	loginCommand := command.NewLoginCommand(
+		authService, cryptoService, tokenHolder, keyHolder, config.GetLogin(), os.Stdin, os.Stdout,
+	)
+	addCommand := command.NewAddCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	getCommand := command.NewGetCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	listCommand := command.NewListCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	tagCommand := command.NewTagCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	mvCommand := command.NewMvCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	lsCommand := command.NewLsCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	searchCommand := command.NewSearchCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	deleteCommand := command.NewDeleteCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	vaultCommand := command.NewVaultCommand(vaultService, dataService, tokenHolder, os.Stdin, os.Stdout)
+	shareCommand := command.NewShareCommand(shareService, dataService, tokenHolder, os.Stdin, os.Stdout)
+	redeemCommand := command.NewRedeemCommand(shareService, os.Stdin, os.Stdout)
+	historyCommand := command.NewHistoryCommand(auditService, dataService, tokenHolder, os.Stdin, os.Stdout)
+	restoreCommand := command.NewRestoreCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	trashCommand := command.NewTrashCommand(dataService, tokenHolder, os.Stdin, os.Stdout)
+	exportCommand := command.NewExportCommand(
+		archiveService, tokenHolder, config.GetExportPassphrase(), os.Stdin, os.Stdout,
+	)
+	importCommand := command.NewImportCommand(
+		archiveService, tokenHolder, config.GetExportPassphrase(), os.Stdin, os.Stdout,
+	)
+
+	if args := config.GetArgs(); len(args) > 0 {
+		sessionManager := command.NewSessionManager(sessionService, tokenHolder, keyHolder, os.Stdin, os.Stderr)
+		cli := command.NewCLI(func() error {
+			if config.GetMasterPassword() != "" {
+				restored, err := sessionManager.RestoreWith(config.GetMasterPassword())
+				if err != nil || restored {
+					return err
+				}
+			}
+			return loginCommand.Authenticate(
+				config.GetLogin(), config.GetPassword(), config.GetOTP(), config.GetMasterPassword(),
+			)
+		}, os.Stderr, addCommand, getCommand, listCommand, tagCommand, mvCommand, lsCommand, searchCommand,
+			deleteCommand, trashCommand, vaultCommand, shareCommand, redeemCommand, historyCommand, restoreCommand,
+			exportCommand, importCommand, profileCommand)
+		code := cli.Run(args)
+		if err := sessionManager.Persist(); err != nil {
+			myLogger.LogInfo("Ошибка сохранения сессии", err)
+		}
+		if err := grpcClient.Close(); err != nil {
+			myLogger.LogInfo("не удалось закрыть соединение клиента gRPC", err)
+		}
+		os.Exit(code)
+	}
 
 	commands := []command.Command{
-		command.NewRegisterCommand(authService, tokenHolder, os.Stdin, os.Stdout),
-		command.NewLoginCommand(authService, tokenHolder, os.Stdin, os.Stdout),
-		command.NewAddCommand(dataService, tokenHolder, os.Stdin, os.Stdout),
-		command.NewGetCommand(dataService, tokenHolder, os.Stdin, os.Stdout),
+		command.NewRegisterCommand(authService, cryptoService, tokenHolder, keyHolder, os.Stdin, os.Stdout),
+		loginCommand,
+		addCommand,
+		getCommand,
+		command.NewOTPCommand(dataService, tokenHolder, os.Stdin, os.Stdout),
+		listCommand,
+		lsCommand,
+		searchCommand,
 		command.NewUpdateCommand(dataService, tokenHolder, os.Stdin, os.Stdout),
-		command.NewDeleteCommand(dataService, tokenHolder, os.Stdin, os.Stdout),
+		tagCommand,
+		mvCommand,
+		deleteCommand,
+		trashCommand,
+		command.NewUploadCommand(dataService, tokenHolder, os.Stdin, os.Stdout),
+		command.NewDownloadCommand(dataService, tokenHolder, os.Stdin, os.Stdout),
+		command.NewSyncCommand(cachedDataService, tokenHolder, os.Stdout),
+		command.NewEncryptCommand(dataService, tokenHolder, os.Stdin, os.Stdout),
+		vaultCommand,
+		shareCommand,
+		redeemCommand,
+		command.NewSessionsCommand(authService, tokenHolder, os.Stdin, os.Stdout),
+		historyCommand,
+		restoreCommand,
+		exportCommand,
+		importCommand,
+		command.NewTwoFactorCommand(authService, tokenHolder, os.Stdin, os.Stdout),
+		command.NewLogoutCommand(authService, tokenHolder, keyHolder, os.Stdout),
+		profileCommand,
 	}
 
 	commandNames := make([]string, len(commands))
@@ -55,6 +154,11 @@ func main() {
 		commandNames[i] = cmd.Name()
 	}
 
+	sessionManager := command.NewSessionManager(sessionService, tokenHolder, keyHolder, os.Stdin, os.Stdout)
+	if err := sessionManager.Restore(); err != nil {
+		myLogger.LogInfo("Ошибка восстановления сессии", err)
+	}
+
 	fmt.Println("Доступные команды: ", strings.Join(commandNames, ", "))
 	for {
 		fmt.Print("Введите команду: ")
@@ -74,5 +178,8 @@ func main() {
 		if err != nil {
 			myLogger.LogInfo("Ошибка вызова команды", err)
 		}
+		if err := sessionManager.Persist(); err != nil {
+			myLogger.LogInfo("Ошибка сохранения сессии", err)
+		}
 	}
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"log"
@@ -8,13 +10,21 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/NikolosHGW/goph-keeper/api/auditpb"
 	"github.com/NikolosHGW/goph-keeper/api/authpb"
 	"github.com/NikolosHGW/goph-keeper/api/datapb"
 	"github.com/NikolosHGW/goph-keeper/api/registerpb"
+	"github.com/NikolosHGW/goph-keeper/api/sharepb"
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
 	"github.com/NikolosHGW/goph-keeper/internal/server/handler"
+	"github.com/NikolosHGW/goph-keeper/internal/server/infrastructure/blobstore"
+	"github.com/NikolosHGW/goph-keeper/internal/server/infrastructure/checkpoint"
 	"github.com/NikolosHGW/goph-keeper/internal/server/infrastructure/config"
 	"github.com/NikolosHGW/goph-keeper/internal/server/infrastructure/db"
+	"github.com/NikolosHGW/goph-keeper/internal/server/infrastructure/keyring"
 	"github.com/NikolosHGW/goph-keeper/internal/server/infrastructure/repository"
 	"github.com/NikolosHGW/goph-keeper/internal/server/interceptor"
 	"github.com/NikolosHGW/goph-keeper/internal/server/service"
@@ -51,15 +61,86 @@ func run() error {
 	}()
 
 	userRepo := repository.NewUser(database, myLogger)
-	dataRepo := repository.NewDataRepository(database, myLogger)
+	dataRepo := repository.NewDataRepository(database, myLogger, config.GetHistoryRetention())
+	loginAttemptRepo := repository.NewLoginAttemptRepository(
+		database, myLogger, config.GetLoginMaxAttempts(), config.GetLoginLockDuration(),
+	)
+	sessionRepo := repository.NewSessionRepository(database, myLogger)
+	twoFactorRepo := repository.NewTwoFactorRepository(database, myLogger)
+	vaultRepo := repository.NewVaultRepository(database, myLogger)
+	shareRepo := repository.NewShareRepository(database, myLogger)
+	auditRepo := repository.NewAuditRepository(database, myLogger)
 
 	registerService := service.NewRegister(myLogger)
-	tokenService := service.NewToken(myLogger, config.GetSecretKey())
-	encryptionService := service.NewEncryptionService([]byte(config.GetCryptoKeyPath()))
-	dataService := service.NewDataService(dataRepo, encryptionService)
+	tokenService := service.NewToken(myLogger, config.GetSecretKey(), config.GetAccessTokenTTL())
+	sessionService := service.NewSessionService(sessionRepo, tokenService, config.GetRefreshTokenTTL())
+	passwordService := service.NewPassword(myLogger)
+	encryptionKeys, err := keyring.Load(config.GetCryptoKeyPath())
+	if err != nil {
+		return fmt.Errorf("не удалось загрузить ключи шифрования: %w", err)
+	}
+	encryptionService, err := service.NewEncryptionService(encryptionKeys)
+	if err != nil {
+		return fmt.Errorf("не удалось инициализировать шифрование: %w", err)
+	}
+
+	switch config.GetCommand() {
+	case "":
+	case "rotate-keys":
+		rotator := service.NewKeyRotator(
+			dataRepo, twoFactorRepo, shareRepo, encryptionService, config.GetRotateBatchSize(),
+		)
+		rotated, err := rotator.Rotate(context.Background())
+		if err != nil {
+			return fmt.Errorf("ротация ключей прервана после %d записей: %w", rotated, err)
+		}
+		myLogger.LogStringInfo("Ротация ключей завершена", "rotated", fmt.Sprint(rotated))
+		return nil
+	case "verify-audit":
+		checkpoints, err := checkpoint.Load(config.GetAuditCheckpointPath())
+		if err != nil {
+			return err
+		}
+		// Ключ подписи нужен, только чтобы проверить контрольные точки.
+		var signingKey ed25519.PrivateKey
+		if len(checkpoints) > 0 {
+			if signingKey, err = keyring.LoadSigningKey(config.GetAuditKeyPath()); err != nil {
+				return err
+			}
+		}
+		return verifyAudit(service.NewAuditChain(auditRepo, signingKey), checkpoints, myLogger)
+	case "audit-checkpoint":
+		signingKey, err := keyring.LoadSigningKey(config.GetAuditKeyPath())
+		if err != nil {
+			return err
+		}
+		cp, err := service.NewAuditChain(auditRepo, signingKey).Checkpoint(context.Background())
+		if err != nil {
+			return fmt.Errorf("не удалось создать контрольную точку: %w", err)
+		}
+		if err := checkpoint.Append(config.GetAuditCheckpointPath(), cp); err != nil {
+			return err
+		}
+		myLogger.LogStringInfo("Контрольная точка аудита записана", "event_id", fmt.Sprint(cp.EventID))
+		return nil
+	default:
+		return fmt.Errorf("неизвестная подкоманда: %s", config.GetCommand())
+	}
+
+	blobStore, err := blobstore.NewFileSystem(config.GetBlobDir())
+	if err != nil {
+		return fmt.Errorf("не удалось инициализировать хранилище файлов: %w", err)
+	}
+	dataService := service.NewDataService(dataRepo, vaultRepo, encryptionService, blobStore)
+	vaultService := service.NewVaultService(vaultRepo)
+	shareService := service.NewShareService(shareRepo, vaultRepo, encryptionService)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, loginAttemptRepo, encryptionService)
+	auditService := service.NewAuditService(auditRepo)
 
-	registerUsecase := usecase.NewRegister(registerService, tokenService, userRepo)
-	authUsecase := usecase.NewAuth(tokenService, userRepo)
+	registerUsecase := usecase.NewRegister(registerService, sessionService, userRepo)
+	authUsecase := usecase.NewAuth(
+		sessionService, userRepo, passwordService, loginAttemptRepo, tokenService, twoFactorService,
+	)
 
 	listen, err := net.Listen("tcp", config.GetRunAddress())
 	if err != nil {
@@ -69,6 +150,9 @@ func run() error {
 	noAuthMethods := []string{
 		"/register.Register/RegisterUser",
 		"/auth.Auth/LoginUser",
+		"/auth.Auth/RefreshToken",
+		"/auth.Auth/CompleteLogin",
+		"/share.ShareService/RedeemShare",
 	}
 
 	creds, err := credentials.NewServerTLSFromFile(config.GetServerCrtPath(), config.GetServerKeyPath())
@@ -76,18 +160,58 @@ func run() error {
 		return fmt.Errorf("не удалось загрузить TLS сертификаты: %w", err)
 	}
 
+	authInterceptor := interceptor.NewAuthInterceptor(tokenService, sessionService, noAuthMethods)
+	auditInterceptor := interceptor.NewAuditInterceptor(auditService, tokenService, myLogger)
+
 	srv := grpc.NewServer(
 		grpc.Creds(creds),
 		grpc.ChainUnaryInterceptor(
-			interceptor.NewAuthInterceptor(tokenService, noAuthMethods).Unary(),
+			auditInterceptor.Unary(),
+			authInterceptor.Unary(),
+		),
+		grpc.ChainStreamInterceptor(
+			auditInterceptor.Stream(),
+			authInterceptor.Stream(),
 		),
 	)
 
 	reflection.Register(srv)
 
 	registerpb.RegisterRegisterServer(srv, handler.NewRegisterServer(registerUsecase))
-	authpb.RegisterAuthServer(srv, handler.NewAuthServer(authUsecase))
+	authpb.RegisterAuthServer(srv, handler.NewAuthServer(authUsecase, sessionService, twoFactorService))
 	datapb.RegisterDataServiceServer(srv, handler.NewDataServer(dataService, myLogger))
+	vaultpb.RegisterVaultServiceServer(srv, handler.NewVaultServer(vaultService, myLogger))
+	sharepb.RegisterShareServiceServer(srv, handler.NewShareServer(shareService, myLogger))
+	auditpb.RegisterAuditServiceServer(srv, handler.NewAuditServer(auditService, myLogger))
+
+	if interval := config.GetAuditCheckpointInterval(); interval > 0 {
+		signingKey, err := keyring.LoadSigningKey(config.GetAuditKeyPath())
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go writeAuditCheckpoints(
+			ctx, service.NewAuditChain(auditRepo, signingKey), config.GetAuditCheckpointPath(), interval, myLogger,
+		)
+	}
+
+	// Очистка корзины останавливается после GracefulStop: запросы, которые
+	// ещё дорабатывают, могут восстанавливать записи из корзины.
+	stopPurger := func() {}
+	if retention, interval := config.GetTrashRetention(), config.GetTrashPurgeInterval(); retention > 0 && interval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			purgeTrash(ctx, service.NewTrashPurger(dataRepo, blobStore, retention), interval, myLogger)
+		}()
+		stopPurger = func() {
+			cancel()
+			<-done
+		}
+	}
+	defer stopPurger()
 
 	errChan := make(chan error, 1)
 
@@ -111,8 +235,93 @@ func run() error {
 	}
 
 	srv.GracefulStop()
+	stopPurger()
 
 	myLogger.LogStringInfo("Сервер успешно остановлен", "address", config.GetRunAddress())
 
 	return nil
 }
+
+type auditChain interface {
+	Checkpoint(ctx context.Context) (*entity.AuditCheckpoint, error)
+	Verify(ctx context.Context, checkpoints []*entity.AuditCheckpoint) (int, *entity.AuditBreak, error)
+}
+
+type trashPurger interface {
+	Purge(ctx context.Context) (int, error)
+}
+
+type serverLogger interface {
+	LogInfo(message string, err error)
+	LogStringInfo(message string, key, val string)
+}
+
+// verifyAudit - проверяет цепочку журнала аудита и сверяет её с контрольными точками.
+func verifyAudit(chain auditChain, checkpoints []*entity.AuditCheckpoint, myLogger serverLogger) error {
+	checked, brk, err := chain.Verify(context.Background(), checkpoints)
+	if err != nil {
+		return fmt.Errorf("проверка журнала аудита прервана: %w", err)
+	}
+	if brk != nil {
+		return fmt.Errorf("цепочка журнала аудита нарушена на событии %d: %s", brk.EventID, brk.Reason)
+	}
+
+	myLogger.LogStringInfo("Цепочка журнала аудита цела", "events", fmt.Sprint(checked))
+	myLogger.LogStringInfo("Сверено с контрольными точками", "checkpoints", fmt.Sprint(len(checkpoints)))
+	return nil
+}
+
+// writeAuditCheckpoints - раз в interval дописывает подписанную контрольную
+// точку, если с прошлой точки в журнале появились события.
+func writeAuditCheckpoints(
+	ctx context.Context, chain auditChain, path string, interval time.Duration, myLogger serverLogger,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastEventID int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cp, err := chain.Checkpoint(ctx)
+		if err != nil {
+			myLogger.LogInfo("не удалось создать контрольную точку аудита", err)
+			continue
+		}
+		if cp.EventID == lastEventID {
+			continue
+		}
+		if err := checkpoint.Append(path, cp); err != nil {
+			myLogger.LogInfo("не удалось записать контрольную точку аудита", err)
+			continue
+		}
+		lastEventID = cp.EventID
+	}
+}
+
+// purgeTrash - сразу и затем раз в interval окончательно удаляет записи,
+// срок хранения которых в корзине истёк. Возвращается после отмены ctx.
+func purgeTrash(ctx context.Context, purger trashPurger, interval time.Duration, myLogger serverLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purger.Purge(ctx)
+		if err != nil && ctx.Err() == nil {
+			myLogger.LogInfo("не удалось очистить корзину", err)
+		}
+		if purged > 0 {
+			myLogger.LogStringInfo("Корзина очищена", "purged", fmt.Sprint(purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Package blindindex - слепой индекс для поиска по зашифрованному описанию
+// записей. Клиент режет meta на слова и передаёт серверу HMAC каждого слова
+// и его префиксов ключом, производным от ключа шифрования; сервер хранит
+// токены рядом с записью и ищет по ним, не видя ни слов, ни ключа.
+// Одинаковые слова дают одинаковые токены, поэтому сервер видит, у каких
+// записей есть общие слова, но не какие это слова.
+package blindindex
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxTokens - сколько токенов может быть у одной записи.
+	MaxTokens = 512
+	// MaxQueryTokens - сколько слов может быть в поисковом запросе.
+	MaxQueryTokens = 16
+	// MinPrefix и MaxPrefix - длины префиксов слова в индексе: запрос "git"
+	// находит "github". Слова короче MinPrefix индексируются целиком,
+	// слова запроса длиннее MaxPrefix обрезаются.
+	MinPrefix = 3
+	MaxPrefix = 16
+
+	tokenLength = sha256.Size * 2
+	keyLabel    = "gophkeeper-search-index"
+)
+
+// ErrInvalid - токены не похожи на выход Tokens или Query.
+var ErrInvalid = errors.New("некорректные токены поиска")
+
+// Key - ключ индекса из ключа шифрования записей. Отдельный ключ нужен,
+// чтобы токены нельзя было сопоставить с шифротекстами.
+func Key(encryptionKey []byte) []byte {
+	mac := hmac.New(sha256.New, encryptionKey)
+	mac.Write([]byte(keyLabel))
+	return mac.Sum(nil)
+}
+
+// Tokens - токены текста text для записи в индекс: слова и их префиксы
+// без повторов. Если их больше MaxTokens, остаются первые: начало описания
+// обычно содержит название записи.
+func Tokens(key []byte, text string) []string {
+	seen := make(map[string]bool)
+	tokens := make([]string, 0)
+	add := func(term string) {
+		token := hash(key, term)
+		if !seen[token] && len(tokens) < MaxTokens {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, word := range words(text) {
+		if len(word) < MinPrefix {
+			add(string(word))
+			continue
+		}
+		for n := MinPrefix; n <= min(len(word), MaxPrefix); n++ {
+			add(string(word[:n]))
+		}
+	}
+
+	return tokens
+}
+
+// Query - токены слов поискового запроса. Запись подходит, если у неё
+// есть все токены запроса.
+func Query(key []byte, query string) ([]string, error) {
+	seen := make(map[string]bool)
+	tokens := make([]string, 0)
+	for _, word := range words(query) {
+		token := hash(key, string(word[:min(len(word), MaxPrefix)]))
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: в запросе нет слов", ErrInvalid)
+	}
+	if len(tokens) > MaxQueryTokens {
+		return nil, fmt.Errorf("%w: в запросе больше %d слов", ErrInvalid, MaxQueryTokens)
+	}
+
+	return tokens, nil
+}
+
+// Validate - проверяет, что токенов не больше limit и каждый - hex HMAC-SHA256.
+func Validate(tokens []string, limit int) error {
+	if len(tokens) > limit {
+		return fmt.Errorf("%w: больше %d", ErrInvalid, limit)
+	}
+	for _, token := range tokens {
+		if len(token) != tokenLength || strings.ToLower(token) != token {
+			return ErrInvalid
+		}
+		if _, err := hex.DecodeString(token); err != nil {
+			return ErrInvalid
+		}
+	}
+
+	return nil
+}
+
+// words - слова текста в нижнем регистре: последовательности букв и цифр.
+func words(text string) [][]rune {
+	var result [][]rune
+	for _, field := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		result = append(result, []rune(field))
+	}
+	return result
+}
+
+func hash(key []byte, term string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(term))
+	return hex.EncodeToString(mac.Sum(nil))
+}
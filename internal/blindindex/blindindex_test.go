@@ -0,0 +1,77 @@
+package blindindex
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryMatchesTokens(t *testing.T) {
+	key := Key([]byte("0123456789abcdef0123456789abcdef"))
+	indexed := Tokens(key, "GitHub: рабочий аккаунт, 2FA")
+
+	tests := []struct {
+		name    string
+		query   string
+		matches bool
+	}{
+		{name: "слово целиком", query: "github", matches: true},
+		{name: "префикс и регистр", query: "GIT", matches: true},
+		{name: "несколько слов", query: "рабочий git", matches: true},
+		{name: "короткое слово", query: "2fa", matches: true},
+		{name: "нет одного из слов", query: "github личный", matches: false},
+		{name: "середина слова", query: "hub", matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := Query(key, tt.query)
+			require.NoError(t, err)
+
+			all := true
+			for _, token := range query {
+				all = all && slices.Contains(indexed, token)
+			}
+			assert.Equal(t, tt.matches, all)
+		})
+	}
+}
+
+func TestTokens_KeyAndLimits(t *testing.T) {
+	first := Tokens(Key([]byte("first")), "github")
+	second := Tokens(Key([]byte("second")), "github")
+	assert.Len(t, first, len("github")-MinPrefix+1)
+	assert.NotEqual(t, first, second)
+	assert.NoError(t, Validate(first, MaxTokens))
+
+	long := Tokens(Key([]byte("k")), strings.Repeat("слово ", 10)+strings.Repeat("x", 40))
+	assert.Len(t, long, 3+MaxPrefix-MinPrefix+1, "слово даёт 3 префикса")
+
+	var many strings.Builder
+	for i := 0; i < MaxTokens; i++ {
+		fmt.Fprintf(&many, "w%d ", i)
+	}
+	assert.Len(t, Tokens(Key([]byte("k")), many.String()), MaxTokens)
+}
+
+func TestQuery_Invalid(t *testing.T) {
+	_, err := Query([]byte("k"), " ,.- ")
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	_, err = Query([]byte("k"), strings.Repeat("a b c d e f g h i j k l m n o p q ", 2)+"r")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestValidate(t *testing.T) {
+	valid := Tokens([]byte("k"), "ok")
+
+	assert.NoError(t, Validate(valid, 1))
+	assert.ErrorIs(t, Validate(append(valid, valid...), 1), ErrInvalid)
+	assert.ErrorIs(t, Validate([]string{"abc"}, 1), ErrInvalid)
+	assert.ErrorIs(t, Validate([]string{strings.ToUpper(valid[0])}, 1), ErrInvalid)
+	assert.ErrorIs(t, Validate([]string{strings.Repeat("z", 64)}, 1), ErrInvalid)
+}
@@ -0,0 +1,218 @@
+// Package archive - формат файла экспорта записей.
+//
+// Файл - JSON-конверт:
+//
+//	{
+//	  "format": "gophkeeper-export",
+//	  "version": 1,
+//	  "kdf": {"name": "argon2id", "salt": "<base64>", "time": 3, "memory": 65536, "threads": 4},
+//	  "cipher": "xchacha20-poly1305",
+//	  "nonce": "<base64, 24 байта>",
+//	  "ciphertext": "<base64>"
+//	}
+//
+// Ключ выводится из пароля архива по Argon2id с параметрами из kdf,
+// ciphertext расшифровывается XChaCha20-Poly1305 с дополнительными данными
+// "gophkeeper-export/<version>". Внутри - JSON:
+//
+//	{"exported_at": "<RFC 3339>", "items": [<DataItem>, ...]}
+//
+// где DataItem - сообщение из api/proto/data.proto в JSON-представлении
+// protobuf с именами полей как в .proto: info_type, meta, created, tags,
+// folder, favorite и одно из login_password, text, binary (bytes в base64),
+// bank_card.
+package archive
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// FormatName - значение поля format.
+	FormatName = "gophkeeper-export"
+	// Version - версия формата, которую пишет Write.
+	Version = 1
+
+	kdfName    = "argon2id"
+	cipherName = "xchacha20-poly1305"
+	saltSize   = 16
+)
+
+// Параметры Argon2id для новых архивов, как у ключа из мастер-пароля.
+const (
+	kdfTime    = 3
+	kdfMemory  = 64 * 1024
+	kdfThreads = 4
+
+	// Верхние границы параметров из чужого архива, чтобы он не занял всю память.
+	maxKDFTime   = 16
+	maxKDFMemory = 1024 * 1024
+)
+
+var (
+	// ErrFormat - файл не является архивом экспорта или повреждён.
+	ErrFormat = errors.New("файл не является архивом экспорта")
+	// ErrVersion - архив записан более новой версией клиента.
+	ErrVersion = errors.New("неподдерживаемая версия архива")
+	// ErrPassphrase - неверный пароль архива или архив изменён.
+	ErrPassphrase = errors.New("неверный пароль архива или архив повреждён")
+)
+
+// Contents - расшифрованное содержимое архива.
+type Contents struct {
+	ExportedAt time.Time
+	Items      []*datapb.DataItem
+}
+
+type kdfParams struct {
+	Name    string `json:"name"`
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+type envelope struct {
+	Format     string    `json:"format"`
+	Cipher     string    `json:"cipher"`
+	KDF        kdfParams `json:"kdf"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+	Version    int       `json:"version"`
+}
+
+type plaintext struct {
+	ExportedAt time.Time         `json:"exported_at"`
+	Items      []json.RawMessage `json:"items"`
+}
+
+// itemJSON - представление записи в архиве.
+var itemJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+// Write - шифрует записи паролем passphrase и пишет архив в w. Из записей
+// сохраняются только тип, описание, дата создания, теги, папка, избранное
+// и содержимое. Текст старых записей без типизированного содержимого
+// переносится из info в text.
+func Write(w io.Writer, passphrase string, items []*datapb.DataItem, exportedAt time.Time) error {
+	body := plaintext{ExportedAt: exportedAt.UTC(), Items: make([]json.RawMessage, 0, len(items))}
+	for _, item := range items {
+		raw, err := itemJSON.Marshal(ExportItem(item))
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации записи %d: %w", item.Id, err)
+		}
+		body.Items = append(body.Items, raw)
+	}
+	plain, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации архива: %w", err)
+	}
+
+	env := envelope{
+		Format:  FormatName,
+		Version: Version,
+		Cipher:  cipherName,
+		KDF:     kdfParams{Name: kdfName, Time: kdfTime, Memory: kdfMemory, Threads: kdfThreads},
+		Nonce:   make([]byte, chacha20poly1305.NonceSizeX),
+	}
+	env.KDF.Salt = make([]byte, saltSize)
+	if _, err := rand.Read(env.KDF.Salt); err != nil {
+		return fmt.Errorf("ошибка генерации соли: %w", err)
+	}
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return fmt.Errorf("ошибка генерации nonce: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(deriveKey(passphrase, env.KDF))
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации шифра: %w", err)
+	}
+	env.Ciphertext = aead.Seal(nil, env.Nonce, plain, additionalData(env.Version))
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(env); err != nil {
+		return fmt.Errorf("ошибка записи архива: %w", err)
+	}
+
+	return nil
+}
+
+// Read - читает и расшифровывает архив.
+func Read(r io.Reader, passphrase string) (*Contents, error) {
+	var env envelope
+	if err := json.NewDecoder(r).Decode(&env); err != nil || env.Format != FormatName {
+		return nil, ErrFormat
+	}
+	if env.Version != Version {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, env.Version)
+	}
+	if env.Cipher != cipherName || env.KDF.Name != kdfName || len(env.KDF.Salt) == 0 ||
+		env.KDF.Time == 0 || env.KDF.Time > maxKDFTime || env.KDF.Memory > maxKDFMemory ||
+		env.KDF.Threads == 0 || len(env.Nonce) != chacha20poly1305.NonceSizeX {
+		return nil, ErrFormat
+	}
+
+	aead, err := chacha20poly1305.NewX(deriveKey(passphrase, env.KDF))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка инициализации шифра: %w", err)
+	}
+	plain, err := aead.Open(nil, env.Nonce, env.Ciphertext, additionalData(env.Version))
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+
+	var body plaintext
+	if err := json.Unmarshal(plain, &body); err != nil {
+		return nil, ErrFormat
+	}
+	contents := &Contents{ExportedAt: body.ExportedAt, Items: make([]*datapb.DataItem, 0, len(body.Items))}
+	for i, raw := range body.Items {
+		item := &datapb.DataItem{}
+		if err := protojson.Unmarshal(raw, item); err != nil {
+			return nil, fmt.Errorf("%w: запись %d: %v", ErrFormat, i+1, err)
+		}
+		contents.Items = append(contents.Items, item)
+	}
+
+	return contents, nil
+}
+
+// ExportItem - запись в том виде, в котором она попадает в архив. Записи,
+// созданные до появления типов, хранят текст прямо в info, которого в
+// архиве нет, поэтому он переносится в text.
+func ExportItem(item *datapb.DataItem) *datapb.DataItem {
+	exported := &datapb.DataItem{
+		InfoType: item.InfoType,
+		Meta:     item.Meta,
+		Created:  item.Created,
+		Tags:     item.Tags,
+		Folder:   item.Folder,
+		Favorite: item.Favorite,
+		Payload:  item.Payload,
+	}
+	if item.Payload == nil && item.Info != "" {
+		exported.Payload = &datapb.DataItem_Text{Text: &datapb.Text{Content: item.Info}}
+	}
+
+	return exported
+}
+
+func deriveKey(passphrase string, params kdfParams) []byte {
+	return argon2.IDKey([]byte(passphrase), params.Salt, params.Time, params.Memory, params.Threads,
+		chacha20poly1305.KeySize)
+}
+
+// additionalData - привязывает шифротекст к версии формата.
+func additionalData(version int) []byte {
+	return []byte(fmt.Sprintf("%s/%d", FormatName, version))
+}
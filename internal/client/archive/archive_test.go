@@ -0,0 +1,102 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testItems() []*datapb.DataItem {
+	created := timestamppb.New(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	return []*datapb.DataItem{
+		{
+			Id: 5, Version: 3, InfoType: "login_password", Meta: "почта", Created: created,
+			Payload: &datapb.DataItem_LoginPassword{
+				LoginPassword: &datapb.LoginPassword{Login: "user", Password: "secret"},
+			},
+		},
+		{
+			Id: 6, InfoType: "binary", Meta: "скан", Created: created,
+			Payload: &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: "a.pdf", Bytes: []byte{0, 1, 2}}},
+		},
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	exportedAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "пароль архива", testItems(), exportedAt))
+
+	assert.NotContains(t, buf.String(), "secret")
+	var env map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &env))
+	assert.Equal(t, FormatName, env["format"])
+	assert.Equal(t, float64(Version), env["version"])
+
+	contents, err := Read(bytes.NewReader(buf.Bytes()), "пароль архива")
+	require.NoError(t, err)
+	assert.Equal(t, exportedAt, contents.ExportedAt)
+	require.Len(t, contents.Items, 2)
+	for i, item := range testItems() {
+		item.Id, item.Version = 0, 0
+		assert.True(t, proto.Equal(item, contents.Items[i]), "запись %d: %v", i, contents.Items[i])
+	}
+}
+
+func TestWrite_LegacyText(t *testing.T) {
+	legacy := []*datapb.DataItem{{Id: 7, InfoType: "text", Meta: "заметка", Info: "старый текст"}}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "пароль", legacy, time.Now()))
+
+	contents, err := Read(bytes.NewReader(buf.Bytes()), "пароль")
+	require.NoError(t, err)
+	require.Len(t, contents.Items, 1)
+	assert.Equal(t, "старый текст", contents.Items[0].GetText().GetContent())
+	assert.Empty(t, contents.Items[0].GetInfo())
+	assert.Equal(t, "старый текст", legacy[0].GetInfo(), "исходная запись не должна меняться")
+}
+
+func TestRead_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "пароль", testItems(), time.Now()))
+	valid := buf.String()
+
+	tests := []struct {
+		name        string
+		content     string
+		passphrase  string
+		expectedErr error
+	}{
+		{name: "неверный пароль", content: valid, passphrase: "другой", expectedErr: ErrPassphrase},
+		{name: "не архив", content: `{"items": []}`, passphrase: "пароль", expectedErr: ErrFormat},
+		{name: "не JSON", content: "login,password", passphrase: "пароль", expectedErr: ErrFormat},
+		{
+			name:        "новая версия",
+			content:     strings.Replace(valid, `"version": 1`, `"version": 2`, 1),
+			passphrase:  "пароль",
+			expectedErr: ErrVersion,
+		},
+		{
+			name:        "непомерные параметры KDF",
+			content:     strings.Replace(valid, `"memory": 65536`, `"memory": 4194304`, 1),
+			passphrase:  "пароль",
+			expectedErr: ErrFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.content), tt.passphrase)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -5,9 +5,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/NikolosHGW/goph-keeper/api/datapb"
 	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 type dataService interface {
@@ -44,7 +50,7 @@ func (c *AddCommand) Execute() error {
 		return fmt.Errorf("вы должны войти в систему")
 	}
 
-	var infoType, info, meta string
+	var infoType, meta string
 	_, err := fmt.Fprint(c.writer, "Введите тип информации (login_password, text, binary, bank_card): ")
 	if err != nil {
 		return fmt.Errorf("ошибка stdin тип информации: %w", err)
@@ -56,14 +62,10 @@ func (c *AddCommand) Execute() error {
 		return fmt.Errorf("ошибка ввода типа информации: unexpected EOF")
 	}
 
-	_, err = fmt.Fprint(c.writer, "Введите данные: ")
-	if err != nil {
-		return fmt.Errorf("ошибка stdin ввода данных: %w", err)
-	}
-	if scanner.Scan() {
-		info = scanner.Text()
-	} else {
-		return fmt.Errorf("ошибка ввода пароля: unexpected EOF")
+	dataItem := &datapb.DataItem{InfoType: infoType}
+	prompter := &payloadPrompter{scanner: scanner, writer: c.writer}
+	if err := prompter.fill(dataItem, nil); err != nil {
+		return err
 	}
 
 	_, err = fmt.Fprint(c.writer, "Введите метаинформацию: ")
@@ -76,11 +78,7 @@ func (c *AddCommand) Execute() error {
 		return fmt.Errorf("ошибка ввода метаинформации: unexpected EOF")
 	}
 
-	dataItem := &datapb.DataItem{
-		InfoType: infoType,
-		Info:     info,
-		Meta:     meta,
-	}
+	dataItem.Meta = meta
 
 	id, err := c.dataService.AddData(context.Background(), c.tokenHolder.Token, dataItem)
 	if err != nil {
@@ -94,3 +92,149 @@ func (c *AddCommand) Execute() error {
 
 	return nil
 }
+
+func (c *AddCommand) Usage() string {
+	return "add --type тип [--meta текст] [--vault id] [--data-file путь|-] [--login --password --url --totp] " +
+		"[--content текст] [--number --holder --expiry --cvv] [--tags a,b] [--folder путь] [--favorite] " +
+		"[--output plain|table|json]"
+}
+
+// Run - добавляет запись из аргументов и выводит её ID. Содержимое можно
+// передать файлом (--data-file, "-" - stdin): для text - текст, для binary -
+// сам файл, для login_password и bank_card - JSON с полями записи.
+// Флаги полей имеют приоритет над значениями из файла.
+func (c *AddCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	infoType := flags.String("type", "", "тип информации")
+	meta := flags.String("meta", "", "метаинформация")
+	vaultID := flags.Int("vault", 0, "ID общего хранилища, 0 - личные данные")
+	dataFile := flags.String("data-file", "", "файл с данными, - для stdin")
+	tags := flags.String("tags", "", "теги через запятую")
+	folder := flags.String("folder", "", "папка, например work/db")
+	favorite := flags.Bool("favorite", false, "добавить в избранное")
+	content := flags.String("content", "", "текст")
+	lp := &datapb.LoginPassword{}
+	flags.StringVar(&lp.Login, "login", "", "логин")
+	flags.StringVar(&lp.Password, "password", "", "пароль")
+	flags.StringVar(&lp.Url, "url", "", "URL")
+	flags.StringVar(&lp.TotpSecret, "totp", "", "TOTP-секрет или ссылка otpauth://")
+	card := &datapb.BankCard{}
+	flags.StringVar(&card.Number, "number", "", "номер карты")
+	flags.StringVar(&card.Holder, "holder", "", "владелец")
+	flags.StringVar(&card.Expiry, "expiry", "", "срок действия (MM/YY)")
+	flags.StringVar(&card.Cvv, "cvv", "", "CVV")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("лишние аргументы: %s", strings.Join(positional, " "))
+	}
+	if !payload.IsKnownType(*infoType) {
+		return usageErrorf("некорректный --type %q, допустимы: login_password, text, binary, bank_card", *infoType)
+	}
+	tagList, err := parseTags(*tags)
+	if err != nil {
+		return err
+	}
+	folderPath, err := parseFolder(*folder)
+	if err != nil {
+		return err
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	data, err := c.readDataFile(*dataFile)
+	if err != nil {
+		return err
+	}
+
+	dataItem := &datapb.DataItem{
+		InfoType: *infoType,
+		Meta:     *meta,
+		VaultId:  int32(*vaultID),
+		Tags:     tagList,
+		Folder:   folderPath,
+		Favorite: *favorite,
+	}
+	switch *infoType {
+	case payload.TypeLoginPassword:
+		if err := mergeDataFile(data, lp); err != nil {
+			return err
+		}
+		if err := normalizeTOTP(lp); err != nil {
+			return err
+		}
+		dataItem.Payload = &datapb.DataItem_LoginPassword{LoginPassword: lp}
+	case payload.TypeText:
+		if *content == "" {
+			*content = string(data)
+		}
+		dataItem.Payload = &datapb.DataItem_Text{Text: &datapb.Text{Content: *content}}
+	case payload.TypeBinary:
+		if data == nil {
+			return usageErrorf("для binary нужен --data-file")
+		}
+		filename, mimeType := "stdin", defaultMime
+		if *dataFile != "-" {
+			filename, mimeType = filepath.Base(*dataFile), mimeByPath(*dataFile)
+		}
+		dataItem.Payload = &datapb.DataItem_Binary{Binary: &datapb.Binary{
+			Filename: filename,
+			Mime:     mimeType,
+			Bytes:    data,
+		}}
+	case payload.TypeBankCard:
+		if err := mergeDataFile(data, card); err != nil {
+			return err
+		}
+		dataItem.Payload = &datapb.DataItem_BankCard{BankCard: card}
+	}
+
+	id, err := c.dataService.AddData(context.Background(), c.tokenHolder.Token, dataItem)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления данных: %w", err)
+	}
+
+	return writeID(c.writer, format, id)
+}
+
+// readDataFile - читает --data-file; nil, если флаг не задан.
+func (c *AddCommand) readDataFile(path string) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch path {
+	case "":
+		return nil, nil
+	case "-":
+		data, err = io.ReadAll(c.reader)
+	default:
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения --data-file: %w", err)
+	}
+
+	return data, nil
+}
+
+// mergeDataFile - заполняет fields значениями из JSON, не затирая поля,
+// уже заданные флагами.
+func mergeDataFile(data []byte, fields proto.Message) error {
+	if data == nil {
+		return nil
+	}
+
+	fromFile := fields.ProtoReflect().New().Interface()
+	if err := protojson.Unmarshal(data, fromFile); err != nil {
+		return usageErrorf("некорректный JSON в --data-file: %v", err)
+	}
+	proto.Merge(fromFile, fields)
+	proto.Reset(fields)
+	proto.Merge(fields, fromFile)
+
+	return nil
+}
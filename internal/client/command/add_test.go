@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -24,6 +26,8 @@ func (m *MockDataService) AddData(ctx context.Context, token string, data *datap
 }
 
 func TestAddCommand_Execute(t *testing.T) {
+	const typePrompt = "Введите тип информации (login_password, text, binary, bank_card): "
+
 	tests := []struct {
 		name           string
 		token          string
@@ -35,21 +39,73 @@ func TestAddCommand_Execute(t *testing.T) {
 		{
 			name:  "Successful add",
 			token: "valid_token",
-			input: "login_password\nuser123\nmeta_info\n",
+			input: "login_password\nuser123\npass\n\n\nmeta_info\n",
 			mockSetup: func(m *MockDataService) {
 				dataItem := &datapb.DataItem{
 					InfoType: "login_password",
-					Info:     "user123",
 					Meta:     "meta_info",
+					Payload: &datapb.DataItem_LoginPassword{
+						LoginPassword: &datapb.LoginPassword{Login: "user123", Password: "pass"},
+					},
 				}
 				m.On("AddData", mock.Anything, "valid_token", dataItem).Return(int32(1), nil)
 			},
-			expectedOutput: "Введите тип информации (login_password, text, binary, bank_card): " +
-				"Введите данные: " +
+			expectedOutput: typePrompt +
+				"Логин: Пароль: URL: TOTP-секрет или ссылка otpauth://: " +
 				"Введите метаинформацию: " +
 				"Данные успешно добавлены с ID: 1\n",
 			expectedError: nil,
 		},
+		{
+			name:  "Import otpauth URI",
+			token: "valid_token",
+			input: "login_password\n\npass\n\notpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&issuer=GitHub\n\n",
+			mockSetup: func(m *MockDataService) {
+				dataItem := &datapb.DataItem{
+					InfoType: "login_password",
+					Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{
+						Login:      "octocat",
+						Password:   "pass",
+						TotpSecret: "otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&issuer=GitHub",
+					}},
+				}
+				m.On("AddData", mock.Anything, "valid_token", dataItem).Return(int32(3), nil)
+			},
+			expectedOutput: typePrompt +
+				"Логин: Пароль: URL: TOTP-секрет или ссылка otpauth://: " +
+				"Введите метаинформацию: " +
+				"Данные успешно добавлены с ID: 3\n",
+			expectedError: nil,
+		},
+		{
+			name:           "Invalid TOTP secret",
+			token:          "valid_token",
+			input:          "login_password\nuser\npass\n\notpauth://hotp/x?secret=JBSWY3DPEHPK3PXP\n",
+			mockSetup:      func(m *MockDataService) {},
+			expectedOutput: typePrompt + "Логин: Пароль: URL: TOTP-секрет или ссылка otpauth://: ",
+			expectedError: errors.New(
+				"ошибка разбора TOTP: некорректная ссылка otpauth://: поддерживается только otpauth://totp/",
+			),
+		},
+		{
+			name:  "Successful add bank card",
+			token: "valid_token",
+			input: "bank_card\n4111111111111111\nIVAN IVANOV\n12/29\n123\n\n",
+			mockSetup: func(m *MockDataService) {
+				dataItem := &datapb.DataItem{
+					InfoType: "bank_card",
+					Payload: &datapb.DataItem_BankCard{BankCard: &datapb.BankCard{
+						Number: "4111111111111111", Holder: "IVAN IVANOV", Expiry: "12/29", Cvv: "123",
+					}},
+				}
+				m.On("AddData", mock.Anything, "valid_token", dataItem).Return(int32(2), nil)
+			},
+			expectedOutput: typePrompt +
+				"Номер карты: Владелец: Срок действия (MM/YY): CVV: " +
+				"Введите метаинформацию: " +
+				"Данные успешно добавлены с ID: 2\n",
+			expectedError: nil,
+		},
 		{
 			name:           "Missing token",
 			token:          "",
@@ -63,44 +119,47 @@ func TestAddCommand_Execute(t *testing.T) {
 			token:          "valid_token",
 			input:          "",
 			mockSetup:      func(m *MockDataService) {},
-			expectedOutput: "Введите тип информации (login_password, text, binary, bank_card): ",
+			expectedOutput: typePrompt,
 			expectedError:  errors.New("ошибка ввода типа информации: unexpected EOF"),
 		},
 		{
-			name:      "Error reading data",
-			token:     "valid_token",
-			input:     "login_password\n",
-			mockSetup: func(m *MockDataService) {},
-			expectedOutput: "Введите тип информации (login_password, text, binary, bank_card): " +
-				"Введите данные: ",
-			expectedError: errors.New("ошибка ввода пароля: unexpected EOF"),
+			name:           "Unknown info type",
+			token:          "valid_token",
+			input:          "password\n",
+			mockSetup:      func(m *MockDataService) {},
+			expectedOutput: typePrompt,
+			expectedError:  errors.New("неизвестный тип информации: password"),
 		},
 		{
-			name:      "Error reading meta information",
-			token:     "valid_token",
-			input:     "login_password\nuser123\n",
-			mockSetup: func(m *MockDataService) {},
-			expectedOutput: "Введите тип информации (login_password, text, binary, bank_card): " +
-				"Введите данные: " +
-				"Введите метаинформацию: ",
-			expectedError: errors.New("ошибка ввода метаинформации: unexpected EOF"),
+			name:           "Error reading data",
+			token:          "valid_token",
+			input:          "login_password\n",
+			mockSetup:      func(m *MockDataService) {},
+			expectedOutput: typePrompt + "Логин: ",
+			expectedError:  errors.New("ошибка ввода поля \"Логин\": unexpected EOF"),
+		},
+		{
+			name:           "Error reading meta information",
+			token:          "valid_token",
+			input:          "text\nhello\n",
+			mockSetup:      func(m *MockDataService) {},
+			expectedOutput: typePrompt + "Текст: Введите метаинформацию: ",
+			expectedError:  errors.New("ошибка ввода метаинформации: unexpected EOF"),
 		},
 		{
 			name:  "Error adding data",
 			token: "valid_token",
-			input: "login_password\nuser123\nmeta_info\n",
+			input: "text\nhello\nmeta_info\n",
 			mockSetup: func(m *MockDataService) {
 				dataItem := &datapb.DataItem{
-					InfoType: "login_password",
-					Info:     "user123",
+					InfoType: "text",
 					Meta:     "meta_info",
+					Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "hello"}},
 				}
 				m.On("AddData", mock.Anything, "valid_token", dataItem).Return(int32(0), fmt.Errorf("service error"))
 			},
-			expectedOutput: "Введите тип информации (login_password, text, binary, bank_card): " +
-				"Введите данные: " +
-				"Введите метаинформацию: ",
-			expectedError: errors.New("ошибка добавления данных: service error"),
+			expectedOutput: typePrompt + "Текст: Введите метаинформацию: ",
+			expectedError:  errors.New("ошибка добавления данных: service error"),
 		},
 	}
 
@@ -133,3 +192,110 @@ func TestAddCommand_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestAddCommand_Execute_Binary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "note.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("file content"), 0o600))
+
+	mockService := new(MockDataService)
+	mockService.On("AddData", mock.Anything, "valid_token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+		b := item.GetBinary()
+		return item.InfoType == "binary" && b.GetFilename() == "note.txt" &&
+			strings.HasPrefix(b.GetMime(), "text/plain") && string(b.GetBytes()) == "file content"
+	})).Return(int32(3), nil)
+
+	reader := strings.NewReader("binary\n" + path + "\n\n")
+	var writer bytes.Buffer
+
+	cmd := NewAddCommand(mockService, &entity.TokenHolder{Token: "valid_token"}, reader, &writer)
+
+	assert.NoError(t, cmd.Execute())
+	mockService.AssertExpectations(t)
+}
+
+func TestAddCommand_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "login.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"login":"user","password":"from-file","url":"https://a"}`), 0o600))
+
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		mockSetup      func(m *MockDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "login_password из файла, флаги важнее",
+			args: []string{"--type", "login_password", "--data-file", path, "--password", "from-flag", "--meta", "site"},
+			mockSetup: func(m *MockDataService) {
+				m.On("AddData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					lp := item.GetLoginPassword()
+					return item.Meta == "site" && lp.GetLogin() == "user" &&
+						lp.GetPassword() == "from-flag" && lp.GetUrl() == "https://a"
+				})).Return(int32(4), nil)
+			},
+			expectedOutput: "4\n",
+		},
+		{
+			name:  "текст из stdin, вывод JSON",
+			args:  []string{"--type=text", "--data-file", "-", "--json"},
+			stdin: "секрет",
+			mockSetup: func(m *MockDataService) {
+				m.On("AddData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					return item.GetText().GetContent() == "секрет"
+				})).Return(int32(5), nil)
+			},
+			expectedOutput: "{\n  \"id\": 5\n}\n",
+		},
+		{
+			name: "теги, папка и избранное",
+			args: []string{"--type", "text", "--content", "x", "--tags", "work, db,work", "--folder", "/work//db/", "--favorite"},
+			mockSetup: func(m *MockDataService) {
+				m.On("AddData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					return strings.Join(item.Tags, ",") == "db,work" && item.Folder == "work/db" && item.Favorite
+				})).Return(int32(6), nil)
+			},
+			expectedOutput: "6\n",
+		},
+		{
+			name:         "некорректный тег",
+			args:         []string{"--type", "text", "--tags", strings.Repeat("x", 65)},
+			mockSetup:    func(m *MockDataService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "binary без файла",
+			args:         []string{"--type", "binary"},
+			mockSetup:    func(m *MockDataService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "неизвестный тип",
+			args:         []string{"--type", "note"},
+			mockSetup:    func(m *MockDataService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "некорректный TOTP",
+			args:         []string{"--type", "login_password", "--login", "u", "--totp", "не секрет"},
+			mockSetup:    func(m *MockDataService) {},
+			expectedCode: ExitFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewAddCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(tt.stdin), writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Коды завершения неинтерактивного режима.
+const (
+	ExitOK       = 0
+	ExitFailure  = 1
+	ExitUsage    = 2
+	ExitAuth     = 3
+	ExitNotFound = 4
+	ExitConflict = 5
+)
+
+// ErrNotLoggedIn - команда вызвана до входа в систему.
+var ErrNotLoggedIn = errors.New("вы должны войти в систему")
+
+// Runner - команда, которую можно вызвать из скрипта: параметры берутся
+// из аргументов командной строки, а не запрашиваются в диалоге.
+type Runner interface {
+	Command
+	Usage() string
+	Run(args []string) error
+}
+
+// localRunner - команда, которой не нужен вход, например управление профилями.
+type localRunner interface {
+	RequiresLogin() bool
+}
+
+// UsageError - некорректные аргументы командной строки.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &UsageError{Err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode - код завершения процесса для ошибки команды.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var usage *UsageError
+	if errors.As(err, &usage) {
+		return ExitUsage
+	}
+	var conflict *entity.VersionConflictError
+	if errors.As(err, &conflict) {
+		return ExitConflict
+	}
+	if errors.Is(err, ErrNotLoggedIn) || errors.Is(err, entity.ErrWrongMasterPassword) {
+		return ExitAuth
+	}
+
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return ExitAuth
+	case codes.NotFound:
+		return ExitNotFound
+	default:
+		return ExitFailure
+	}
+}
+
+// CLI - неинтерактивный режим: выполняет одну команду из аргументов
+// и возвращает код завершения. Результат пишется в stdout команды,
+// ошибки - в stderr.
+type CLI struct {
+	runners map[string]Runner
+	login   func() error
+	stderr  io.Writer
+}
+
+// NewCLI - конструктор неинтерактивного режима. login вызывается перед
+// командой и должен заполнить токен и ключ шифрования.
+func NewCLI(login func() error, stderr io.Writer, runners ...Runner) *CLI {
+	c := &CLI{
+		runners: make(map[string]Runner, len(runners)),
+		login:   login,
+		stderr:  stderr,
+	}
+	for _, r := range runners {
+		c.runners[r.Name()] = r
+	}
+
+	return c
+}
+
+// Run - выполняет команду args[0] с аргументами args[1:].
+func (c *CLI) Run(args []string) int {
+	if len(args) == 0 {
+		c.printUsage()
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		c.printUsage()
+		return ExitOK
+	}
+
+	runner, ok := c.runners[args[0]]
+	if !ok {
+		fmt.Fprintf(c.stderr, "неизвестная команда: %s\n", args[0])
+		c.printUsage()
+		return ExitUsage
+	}
+
+	if local, ok := runner.(localRunner); !ok || local.RequiresLogin() {
+		if err := c.login(); err != nil {
+			fmt.Fprintf(c.stderr, "login: %v\n", err)
+			return ExitAuth
+		}
+	}
+
+	err := runner.Run(args[1:])
+	if err != nil {
+		fmt.Fprintf(c.stderr, "%s: %v\n", runner.Name(), err)
+		var usage *UsageError
+		if errors.As(err, &usage) {
+			fmt.Fprintf(c.stderr, "использование: %s\n", runner.Usage())
+		}
+	}
+
+	return ExitCode(err)
+}
+
+func (c *CLI) printUsage() {
+	names := make([]string, 0, len(c.runners))
+	for name := range c.runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(c.stderr, "команды:")
+	for _, name := range names {
+		fmt.Fprintf(c.stderr, "  %s\n", c.runners[name].Usage())
+	}
+	fmt.Fprintln(c.stderr, "общие флаги: --output plain|table|json, --json")
+}
+
+// cliFlags - флаги команды с общими флагами формата вывода.
+type cliFlags struct {
+	*flag.FlagSet
+	output string
+	json   bool
+}
+
+func newCLIFlags(name string) *cliFlags {
+	f := &cliFlags{FlagSet: flag.NewFlagSet(name, flag.ContinueOnError)}
+	f.SetOutput(io.Discard)
+	f.StringVar(&f.output, "output", string(FormatPlain), "формат вывода: plain, table, json")
+	f.BoolVar(&f.json, "json", false, "то же, что --output json")
+
+	return f
+}
+
+// parse - разбирает флаги и позиционные аргументы, которые могут идти
+// в любом порядке: "get 5 --field password" и "get --field password 5".
+func (f *cliFlags) parse(args []string) ([]string, Format, error) {
+	var positional []string
+	for {
+		if err := f.Parse(args); err != nil {
+			return nil, "", &UsageError{Err: err}
+		}
+		args = f.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if f.json {
+		return positional, FormatJSON, nil
+	}
+	format, err := ParseFormat(f.output)
+	if err != nil {
+		return nil, "", &UsageError{Err: err}
+	}
+
+	return positional, format, nil
+}
+
+// parseID - разбирает единственный позиционный аргумент - ID записи.
+func parseID(positional []string) (int32, error) {
+	if len(positional) != 1 {
+		return 0, usageErrorf("ожидается ровно один ID записи, получено: %s", strings.Join(positional, " "))
+	}
+
+	id, err := strconv.ParseInt(positional[0], 10, 32)
+	if err != nil {
+		return 0, usageErrorf("некорректный ID: %s", positional[0])
+	}
+
+	return int32(id), nil
+}
@@ -0,0 +1,161 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"успех", nil, ExitOK},
+		{"ошибка аргументов", usageErrorf("некорректный ID"), ExitUsage},
+		{"без входа", fmt.Errorf("get: %w", ErrNotLoggedIn), ExitAuth},
+		{"неверный мастер-пароль", fmt.Errorf("login: %w", entity.ErrWrongMasterPassword), ExitAuth},
+		{"сервер отклонил токен", fmt.Errorf("ошибка: %w", status.Error(codes.Unauthenticated, "нет")), ExitAuth},
+		{"нет записи", fmt.Errorf("ошибка: %w", status.Error(codes.NotFound, "нет")), ExitNotFound},
+		{"конфликт версий", fmt.Errorf("ошибка: %w", &entity.VersionConflictError{}), ExitConflict},
+		{"прочее", errors.New("сеть недоступна"), ExitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestCLI_Run(t *testing.T) {
+	item := &datapb.DataItem{
+		Id:       5,
+		InfoType: "text",
+		Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "секрет"}},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		loginErr       error
+		mockSetup      func(m *MockGetDataService)
+		expectedCode   int
+		expectedOutput string
+		expectedStderr string
+	}{
+		{
+			name: "get с полем",
+			args: []string{"get", "5", "--field", "content"},
+			mockSetup: func(m *MockGetDataService) {
+				m.On("GetData", mock.Anything, "token", int32(5)).Return(item, nil)
+			},
+			expectedCode:   ExitOK,
+			expectedOutput: "секрет\n",
+		},
+		{
+			name:           "без команды",
+			args:           []string{},
+			mockSetup:      func(m *MockGetDataService) {},
+			expectedCode:   ExitUsage,
+			expectedStderr: "get <id>",
+		},
+		{
+			name:           "неизвестная команда",
+			args:           []string{"rm", "5"},
+			mockSetup:      func(m *MockGetDataService) {},
+			expectedCode:   ExitUsage,
+			expectedStderr: "неизвестная команда: rm",
+		},
+		{
+			name:           "ошибка входа",
+			args:           []string{"get", "5"},
+			loginErr:       errors.New("неверный пароль"),
+			mockSetup:      func(m *MockGetDataService) {},
+			expectedCode:   ExitAuth,
+			expectedStderr: "неверный пароль",
+		},
+		{
+			name:           "некорректные аргументы",
+			args:           []string{"get", "5", "6"},
+			mockSetup:      func(m *MockGetDataService) {},
+			expectedCode:   ExitUsage,
+			expectedStderr: "использование: get <id>",
+		},
+		{
+			name: "запись не найдена",
+			args: []string{"get", "7"},
+			mockSetup: func(m *MockGetDataService) {
+				m.On("GetData", mock.Anything, "token", int32(7)).
+					Return(nil, status.Error(codes.NotFound, "запись не найдена"))
+			},
+			expectedCode:   ExitNotFound,
+			expectedStderr: "get: ошибка получения данных",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockGetDataService)
+			tt.mockSetup(service)
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			tokenHolder := &entity.TokenHolder{}
+			login := func() error {
+				if tt.loginErr != nil {
+					return tt.loginErr
+				}
+				tokenHolder.Token = "token"
+				return nil
+			}
+
+			cli := NewCLI(login, stderr, NewGetCommand(service, tokenHolder, &bytes.Buffer{}, stdout))
+			code := cli.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, code)
+			assert.Equal(t, tt.expectedOutput, stdout.String())
+			assert.Contains(t, stderr.String(), tt.expectedStderr)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCLIFlags_Parse(t *testing.T) {
+	tests := []struct {
+		name               string
+		args               []string
+		expectedPositional []string
+		expectedFormat     Format
+		expectedErr        bool
+	}{
+		{"флаги после аргумента", []string{"5", "--output", "table"}, []string{"5"}, FormatTable, false},
+		{"флаги до аргумента", []string{"--output=json", "5"}, []string{"5"}, FormatJSON, false},
+		{"--json", []string{"--json"}, nil, FormatJSON, false},
+		{"по умолчанию plain", []string{"5"}, []string{"5"}, FormatPlain, false},
+		{"неизвестный формат", []string{"--output", "xml"}, nil, "", true},
+		{"неизвестный флаг", []string{"--color"}, nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positional, format, err := newCLIFlags("test").parse(tt.args)
+
+			if tt.expectedErr {
+				var usage *UsageError
+				assert.ErrorAs(t, err, &usage)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPositional, positional)
+			assert.Equal(t, tt.expectedFormat, format)
+		})
+	}
+}
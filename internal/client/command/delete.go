@@ -3,15 +3,18 @@ package command
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
 
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
 	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 )
 
 type deleteDataService interface {
-	DeleteData(ctx context.Context, token string, id int32) error
+	GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error)
+	DeleteData(ctx context.Context, token string, id int32, version int64) error
 }
 
 type DeleteCommand struct {
@@ -61,15 +64,60 @@ func (c *DeleteCommand) Execute() error {
 	}
 	id := int32(id64)
 
-	err = c.dataService.DeleteData(context.Background(), c.tokenHolder.Token, id)
-	if err != nil {
-		return fmt.Errorf("ошибка удаления данных: %w", err)
+	if err := c.remove(id); err != nil {
+		return err
 	}
 
-	_, err = fmt.Fprintln(c.writer, "Данные успешно удалены.")
+	_, err = fmt.Fprintln(c.writer, "Данные перемещены в корзину.")
 	if err != nil {
 		return fmt.Errorf("ошибка вывода результата: %w", err)
 	}
 
 	return nil
 }
+
+func (c *DeleteCommand) Usage() string {
+	return "delete <id> [--output plain|table|json]"
+}
+
+// Run - удаляет запись по ID и выводит её ID.
+func (c *DeleteCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional)
+	if err != nil {
+		return err
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	if err := c.remove(id); err != nil {
+		return err
+	}
+
+	return writeID(c.writer, format, id)
+}
+
+func (c *DeleteCommand) remove(id int32) error {
+	// Удаляем ровно ту версию, которую видит пользователь: если запись успели
+	// изменить на другом устройстве, сервер откажет в удалении.
+	dataItem, err := c.dataService.GetData(context.Background(), c.tokenHolder.Token, id)
+	if err != nil {
+		return fmt.Errorf("ошибка получения данных: %w", err)
+	}
+
+	err = c.dataService.DeleteData(context.Background(), c.tokenHolder.Token, id, dataItem.Version)
+	var conflict *entity.VersionConflictError
+	if errors.As(err, &conflict) {
+		return fmt.Errorf("запись изменена на другом устройстве, просмотрите её и повторите удаление: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка удаления данных: %w", err)
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
 	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -18,8 +19,16 @@ type MockDeleteDataService struct {
 	mock.Mock
 }
 
-func (m *MockDeleteDataService) DeleteData(ctx context.Context, token string, id int32) error {
+func (m *MockDeleteDataService) GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error) {
 	args := m.Called(ctx, token, id)
+	if dataItem, ok := args.Get(0).(*datapb.DataItem); ok {
+		return dataItem, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDeleteDataService) DeleteData(ctx context.Context, token string, id int32, version int64) error {
+	args := m.Called(ctx, token, id, version)
 	return args.Error(0)
 }
 
@@ -37,9 +46,11 @@ func TestDeleteCommand_Execute(t *testing.T) {
 			token: "valid_token",
 			input: "1\n",
 			mockSetup: func(m *MockDeleteDataService) {
-				m.On("DeleteData", context.Background(), "valid_token", int32(1)).Return(nil)
+				m.On("GetData", context.Background(), "valid_token", int32(1)).
+					Return(&datapb.DataItem{Id: 1, Version: 3}, nil)
+				m.On("DeleteData", context.Background(), "valid_token", int32(1), int64(3)).Return(nil)
 			},
-			expectedOutput: "Введите ID данных: Данные успешно удалены.\n",
+			expectedOutput: "Введите ID данных: Данные перемещены в корзину.\n",
 			expectedError:  nil,
 		},
 		{
@@ -79,11 +90,36 @@ func TestDeleteCommand_Execute(t *testing.T) {
 			token: "valid_token",
 			input: "2\n",
 			mockSetup: func(m *MockDeleteDataService) {
-				m.On("DeleteData", context.Background(), "valid_token", int32(2)).Return(fmt.Errorf("service error"))
+				m.On("GetData", context.Background(), "valid_token", int32(2)).
+					Return(&datapb.DataItem{Id: 2, Version: 1}, nil)
+				m.On("DeleteData", context.Background(), "valid_token", int32(2), int64(1)).Return(fmt.Errorf("service error"))
 			},
 			expectedOutput: "Введите ID данных: ",
 			expectedError:  errors.New("ошибка удаления данных: service error"),
 		},
+		{
+			name:  "Ошибка получения данных",
+			token: "valid_token",
+			input: "4\n",
+			mockSetup: func(m *MockDeleteDataService) {
+				m.On("GetData", context.Background(), "valid_token", int32(4)).Return(nil, fmt.Errorf("not found"))
+			},
+			expectedOutput: "Введите ID данных: ",
+			expectedError:  errors.New("ошибка получения данных: not found"),
+		},
+		{
+			name:  "Конфликт версий",
+			token: "valid_token",
+			input: "3\n",
+			mockSetup: func(m *MockDeleteDataService) {
+				m.On("GetData", context.Background(), "valid_token", int32(3)).
+					Return(&datapb.DataItem{Id: 3, Version: 1}, nil)
+				m.On("DeleteData", context.Background(), "valid_token", int32(3), int64(1)).
+					Return(&entity.VersionConflictError{ID: 3, CurrentVersion: 2})
+			},
+			expectedOutput: "Введите ID данных: ",
+			expectedError:  errors.New("запись изменена на другом устройстве, просмотрите её и повторите удаление"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,3 +162,54 @@ func TestDeleteCommand_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteCommand_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		mockSetup      func(m *MockDeleteDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "успешное удаление",
+			args: []string{"1"},
+			mockSetup: func(m *MockDeleteDataService) {
+				m.On("GetData", mock.Anything, "token", int32(1)).Return(&datapb.DataItem{Id: 1, Version: 3}, nil)
+				m.On("DeleteData", mock.Anything, "token", int32(1), int64(3)).Return(nil)
+			},
+			expectedOutput: "1\n",
+		},
+		{
+			name: "конфликт версий",
+			args: []string{"1"},
+			mockSetup: func(m *MockDeleteDataService) {
+				m.On("GetData", mock.Anything, "token", int32(1)).Return(&datapb.DataItem{Id: 1, Version: 3}, nil)
+				m.On("DeleteData", mock.Anything, "token", int32(1), int64(3)).
+					Return(&entity.VersionConflictError{})
+			},
+			expectedCode: ExitConflict,
+		},
+		{
+			name:         "без ID",
+			args:         []string{},
+			mockSetup:    func(m *MockDeleteDataService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockDeleteDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewDeleteCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
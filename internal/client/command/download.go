@@ -0,0 +1,99 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type downloadDataService interface {
+	DownloadBinary(ctx context.Context, token string, id int32, dst io.Writer) (*datapb.DataItem, error)
+}
+
+// DownloadCommand - скачивает файл во временный файл рядом с целевым
+// и переименовывает его только после успешной проверки.
+type DownloadCommand struct {
+	dataService downloadDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewDownloadCommand(
+	dataService downloadDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *DownloadCommand {
+	return &DownloadCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *DownloadCommand) Name() string {
+	return "download"
+}
+
+func (c *DownloadCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	idStr, err := prompter.field("ID данных", "")
+	if err != nil {
+		return err
+	}
+	id64, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		return fmt.Errorf("некорректный ID: %w", err)
+	}
+	target, err := prompter.field("Путь для сохранения", ".")
+	if err != nil {
+		return err
+	}
+
+	dir := target
+	if info, statErr := os.Stat(target); statErr != nil || !info.IsDir() {
+		dir = filepath.Dir(target)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return fmt.Errorf("ошибка создания файла: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	item, err := c.dataService.DownloadBinary(context.Background(), c.tokenHolder.Token, int32(id64), tmp)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка скачивания файла: %w", err)
+	}
+
+	path := target
+	if dir == target {
+		path = filepath.Join(dir, filepath.Base(item.GetBinary().GetFilename()))
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("ошибка сохранения файла: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.writer, "Файл сохранён: %s\n", path)
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockDownloadDataService struct {
+	mock.Mock
+}
+
+func (m *MockDownloadDataService) DownloadBinary(
+	ctx context.Context, token string, id int32, dst io.Writer,
+) (*datapb.DataItem, error) {
+	args := m.Called(ctx, token, id, dst)
+	if item, ok := args.Get(0).(*datapb.DataItem); ok {
+		return item, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func writeDownload(content string) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		_, _ = args.Get(3).(io.Writer).Write([]byte(content))
+	}
+}
+
+func TestDownloadCommand_Execute(t *testing.T) {
+	item := &datapb.DataItem{
+		Id:       5,
+		InfoType: "binary",
+		Payload:  &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: "scan.pdf"}},
+	}
+
+	tests := []struct {
+		name          string
+		target        func(dir string) string
+		expectedPath  func(dir string) string
+		serviceErr    error
+		expectedError string
+	}{
+		{
+			name:         "Сохранение в каталог под именем из записи",
+			target:       func(dir string) string { return dir },
+			expectedPath: func(dir string) string { return filepath.Join(dir, "scan.pdf") },
+		},
+		{
+			name:         "Сохранение по указанному пути",
+			target:       func(dir string) string { return filepath.Join(dir, "copy.pdf") },
+			expectedPath: func(dir string) string { return filepath.Join(dir, "copy.pdf") },
+		},
+		{
+			name:          "Ошибка скачивания не оставляет файлов",
+			target:        func(dir string) string { return dir },
+			serviceErr:    errors.New("контрольная сумма файла не совпадает"),
+			expectedError: "ошибка скачивания файла: контрольная сумма файла не совпадает",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			mockService := new(MockDownloadDataService)
+			call := mockService.On("DownloadBinary", mock.Anything, "valid_token", int32(5), mock.Anything).
+				Run(writeDownload("%PDF"))
+			if tt.serviceErr != nil {
+				call.Return(nil, tt.serviceErr)
+			} else {
+				call.Return(item, nil)
+			}
+
+			var output bytes.Buffer
+			cmd := NewDownloadCommand(
+				mockService,
+				&entity.TokenHolder{Token: "valid_token"},
+				strings.NewReader("5\n"+tt.target(dir)+"\n"),
+				&output,
+			)
+
+			err := cmd.Execute()
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				entries, readErr := os.ReadDir(dir)
+				require.NoError(t, readErr)
+				assert.Empty(t, entries)
+				return
+			}
+
+			require.NoError(t, err)
+			content, err := os.ReadFile(tt.expectedPath(dir))
+			require.NoError(t, err)
+			assert.Equal(t, "%PDF", string(content))
+			assert.Contains(t, output.String(), "Файл сохранён: "+tt.expectedPath(dir))
+		})
+	}
+}
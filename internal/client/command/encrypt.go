@@ -0,0 +1,102 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type legacyDataService interface {
+	LegacyItems(ctx context.Context, token string) ([]*datapb.DataItem, error)
+	UpdateData(ctx context.Context, token string, data *datapb.DataItem) error
+}
+
+var legacyColumns = []string{"id", "type", "meta", "content"}
+
+// EncryptCommand - шифрует личные записи, сохранённые до появления
+// шифрования. Их содержимое приходит с сервера открытым текстом и не
+// защищено ключом, поэтому записи сначала показываются пользователю
+// и шифруются только после подтверждения.
+type EncryptCommand struct {
+	dataService legacyDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewEncryptCommand(
+	dataService legacyDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *EncryptCommand {
+	return &EncryptCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *EncryptCommand) Name() string {
+	return "encrypt"
+}
+
+func (c *EncryptCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	items, err := c.dataService.LegacyItems(context.Background(), c.tokenHolder.Token)
+	if err != nil {
+		return fmt.Errorf("ошибка поиска незашифрованных записей: %w", err)
+	}
+	if len(items) == 0 {
+		if _, err := fmt.Fprintln(c.writer, "Незашифрованных записей нет."); err != nil {
+			return fmt.Errorf("ошибка вывода результата: %w", err)
+		}
+		return nil
+	}
+
+	rows := make([]record, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, record{
+			{"id", item.GetId()},
+			{"type", item.GetInfoType()},
+			{"meta", item.GetMeta()},
+			{"content", item.GetInfo()},
+		})
+	}
+	if err := writeRecords(c.writer, FormatTable, legacyColumns, rows); err != nil {
+		return err
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	answer, err := prompter.field("Это ваши данные? Зашифровать их ключом мастер-пароля? (y/N)", "")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(answer, "y") {
+		if _, err := fmt.Fprintln(c.writer, "Записи не изменены."); err != nil {
+			return fmt.Errorf("ошибка вывода результата: %w", err)
+		}
+		return nil
+	}
+
+	for _, item := range items {
+		if err := c.dataService.UpdateData(context.Background(), c.tokenHolder.Token, item); err != nil {
+			return fmt.Errorf("ошибка шифрования записи %d: %w", item.GetId(), err)
+		}
+	}
+
+	if _, err := fmt.Fprintf(c.writer, "Зашифровано записей: %d\n", len(items)); err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockLegacyDataService struct {
+	mock.Mock
+}
+
+func (m *MockLegacyDataService) LegacyItems(ctx context.Context, token string) ([]*datapb.DataItem, error) {
+	args := m.Called(ctx, token)
+	if items, ok := args.Get(0).([]*datapb.DataItem); ok {
+		return items, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLegacyDataService) UpdateData(ctx context.Context, token string, data *datapb.DataItem) error {
+	args := m.Called(ctx, token, data)
+	return args.Error(0)
+}
+
+func TestEncryptCommand_Execute(t *testing.T) {
+	legacy := &datapb.DataItem{Id: 4, InfoType: "text", Meta: "заметка", Info: "старый текст", Version: 2}
+
+	tests := []struct {
+		name           string
+		token          string
+		input          string
+		mockSetup      func(m *MockLegacyDataService)
+		expectedOutput []string
+		expectedError  string
+	}{
+		{
+			name:  "Записи зашифрованы после подтверждения",
+			token: "valid_token",
+			input: "y\n",
+			mockSetup: func(m *MockLegacyDataService) {
+				m.On("LegacyItems", mock.Anything, "valid_token").Return([]*datapb.DataItem{legacy}, nil)
+				m.On("UpdateData", mock.Anything, "valid_token", legacy).Return(nil)
+			},
+			expectedOutput: []string{"старый текст", "Зашифровано записей: 1\n"},
+		},
+		{
+			name:  "Без подтверждения записи не меняются",
+			token: "valid_token",
+			input: "\n",
+			mockSetup: func(m *MockLegacyDataService) {
+				m.On("LegacyItems", mock.Anything, "valid_token").Return([]*datapb.DataItem{legacy}, nil)
+			},
+			expectedOutput: []string{"заметка", "Записи не изменены.\n"},
+		},
+		{
+			name:  "Незашифрованных записей нет",
+			token: "valid_token",
+			mockSetup: func(m *MockLegacyDataService) {
+				m.On("LegacyItems", mock.Anything, "valid_token").Return(nil, nil)
+			},
+			expectedOutput: []string{"Незашифрованных записей нет.\n"},
+		},
+		{
+			name:          "Не авторизован",
+			mockSetup:     func(m *MockLegacyDataService) {},
+			expectedError: "вы должны войти в систему",
+		},
+		{
+			name:  "Ошибка сохранения",
+			token: "valid_token",
+			input: "y\n",
+			mockSetup: func(m *MockLegacyDataService) {
+				m.On("LegacyItems", mock.Anything, "valid_token").Return([]*datapb.DataItem{legacy}, nil)
+				m.On("UpdateData", mock.Anything, "valid_token", legacy).Return(errors.New("conflict"))
+			},
+			expectedError: "ошибка шифрования записи 4: conflict",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockLegacyDataService)
+			tt.mockSetup(mockService)
+
+			var output bytes.Buffer
+			cmd := NewEncryptCommand(
+				mockService, &entity.TokenHolder{Token: tt.token}, strings.NewReader(tt.input), &output,
+			)
+
+			err := cmd.Execute()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				for _, expected := range tt.expectedOutput {
+					assert.Contains(t, output.String(), expected)
+				}
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type exportService interface {
+	Export(ctx context.Context, token, passphrase string, w io.Writer) (int, error)
+}
+
+// ExportCommand - выгружает все личные записи в файл, зашифрованный
+// отдельной парольной фразой: его можно хранить вне сервера и загрузить
+// обратно командой import.
+type ExportCommand struct {
+	archiveService exportService
+	tokenHolder    *entity.TokenHolder
+	// passphrase - парольная фраза для неинтерактивного режима.
+	passphrase string
+	reader     io.Reader
+	writer     io.Writer
+}
+
+func NewExportCommand(
+	archiveService exportService,
+	tokenHolder *entity.TokenHolder,
+	passphrase string,
+	reader io.Reader,
+	writer io.Writer,
+) *ExportCommand {
+	return &ExportCommand{
+		archiveService: archiveService,
+		tokenHolder:    tokenHolder,
+		passphrase:     passphrase,
+		reader:         reader,
+		writer:         writer,
+	}
+}
+
+func (c *ExportCommand) Name() string {
+	return "export"
+}
+
+func (c *ExportCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	path, err := prompter.field("Путь к файлу архива", "")
+	if err != nil {
+		return err
+	}
+	passphrase, err := prompter.field("Парольная фраза архива", "")
+	if err != nil {
+		return err
+	}
+	confirm, err := prompter.field("Повторите парольную фразу", "")
+	if err != nil {
+		return err
+	}
+	if passphrase != confirm {
+		return fmt.Errorf("парольные фразы не совпадают")
+	}
+
+	return c.export(path, passphrase, FormatTable)
+}
+
+func (c *ExportCommand) Usage() string {
+	return "export <file> [--output plain|table|json]"
+}
+
+// Run - парольная фраза берётся из GOPHKEEPER_EXPORT_PASSPHRASE.
+func (c *ExportCommand) Run(args []string) error {
+	positional, format, err := newCLIFlags(c.Name()).parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("ожидается ровно один путь к файлу, получено: %s", strings.Join(positional, " "))
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	return c.export(positional[0], c.passphrase, format)
+}
+
+// export - пишет архив во временный файл рядом с целевым и переименовывает
+// его только после успешной выгрузки, чтобы не оставить обрезанный архив.
+func (c *ExportCommand) export(path, passphrase string, format Format) error {
+	if passphrase == "" {
+		return usageErrorf("не задана парольная фраза архива")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return fmt.Errorf("ошибка создания файла: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	count, err := c.archiveService.Export(context.Background(), c.tokenHolder.Token, passphrase, tmp)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка экспорта: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("ошибка сохранения файла: %w", err)
+	}
+
+	return writeRecord(c.writer, format, record{{"file", path}, {"items", count}})
+}
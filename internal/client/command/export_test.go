@@ -0,0 +1,147 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockArchiveService struct {
+	mock.Mock
+}
+
+func (m *MockArchiveService) Export(ctx context.Context, token, passphrase string, w io.Writer) (int, error) {
+	args := m.Called(ctx, token, passphrase, w)
+	if args.Error(1) == nil {
+		_, _ = io.WriteString(w, "archive")
+	}
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockArchiveService) Import(
+	ctx context.Context, token, passphrase string, r io.Reader, dryRun bool,
+) ([]entity.ImportEntry, error) {
+	args := m.Called(ctx, token, passphrase, r, dryRun)
+	report, _ := args.Get(0).([]entity.ImportEntry)
+	return report, args.Error(1)
+}
+
+func (m *MockArchiveService) ImportForeign(
+	ctx context.Context, token, format string, r io.Reader, dryRun bool,
+) ([]entity.ImportEntry, error) {
+	args := m.Called(ctx, token, format, r, dryRun)
+	report, _ := args.Get(0).([]entity.ImportEntry)
+	return report, args.Error(1)
+}
+
+func TestExportCommand_Run(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		passphrase    string
+		withPath      bool
+		setupMock     func(m *MockArchiveService)
+		expectedItems string
+		expectedCode  int
+	}{
+		{
+			name:       "архив записан",
+			passphrase: "фраза",
+			withPath:   true,
+			setupMock: func(m *MockArchiveService) {
+				m.On("Export", ctx, "token", "фраза", mock.Anything).Return(3, nil)
+			},
+			expectedItems: "3",
+		},
+		{
+			name:       "ошибка экспорта",
+			passphrase: "фраза",
+			withPath:   true,
+			setupMock: func(m *MockArchiveService) {
+				m.On("Export", ctx, "token", "фраза", mock.Anything).Return(0, errors.New("недоступен"))
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "нет парольной фразы",
+			withPath:     true,
+			setupMock:    func(m *MockArchiveService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "нет пути",
+			passphrase:   "фраза",
+			setupMock:    func(m *MockArchiveService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "backup.gkx")
+			var args []string
+			if tt.withPath {
+				args = []string{path}
+			}
+			archive := new(MockArchiveService)
+			tt.setupMock(archive)
+			writer := &bytes.Buffer{}
+
+			cmd := NewExportCommand(archive, &entity.TokenHolder{Token: "token"}, tt.passphrase, &bytes.Buffer{}, writer)
+			err := cmd.Run(args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			if tt.expectedItems != "" {
+				assert.Equal(t, "file\t"+path+"\nitems\t"+tt.expectedItems+"\n", writer.String())
+				require.Len(t, entries, 1)
+				assert.Equal(t, "backup.gkx", entries[0].Name())
+			} else {
+				assert.Empty(t, writer.String())
+				assert.Empty(t, entries, "временный файл должен удаляться")
+			}
+			archive.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportCommand_Execute(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.gkx")
+
+	t.Run("фразы не совпадают", func(t *testing.T) {
+		archive := new(MockArchiveService)
+		input := bytes.NewBufferString(path + "\nфраза\nдругая\n")
+
+		err := NewExportCommand(archive, &entity.TokenHolder{Token: "token"}, "", input, &bytes.Buffer{}).Execute()
+
+		require.Error(t, err)
+		assert.NoFileExists(t, path)
+		archive.AssertExpectations(t)
+	})
+
+	t.Run("архив записан", func(t *testing.T) {
+		archive := new(MockArchiveService)
+		archive.On("Export", context.Background(), "token", "фраза", mock.Anything).Return(2, nil)
+		input := bytes.NewBufferString(path + "\nфраза\nфраза\n")
+
+		err := NewExportCommand(archive, &entity.TokenHolder{Token: "token"}, "", input, &bytes.Buffer{}).Execute()
+
+		require.NoError(t, err)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "archive", string(content))
+		archive.AssertExpectations(t)
+	})
+}
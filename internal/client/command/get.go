@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/NikolosHGW/goph-keeper/api/datapb"
 	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
@@ -69,9 +70,53 @@ func (c *GetCommand) Execute() error {
 
 	fmt.Fprintf(c.writer, "ID: %d\n", dataItem.Id)
 	fmt.Fprintf(c.writer, "Тип: %s\n", dataItem.InfoType)
-	fmt.Fprintf(c.writer, "Данные: %s\n", dataItem.Info)
+	printPayload(c.writer, dataItem)
 	fmt.Fprintf(c.writer, "Мета: %s\n", dataItem.Meta)
 	fmt.Fprintf(c.writer, "Создано: %s\n", dataItem.Created.AsTime())
 
 	return nil
 }
+
+func (c *GetCommand) Usage() string {
+	return "get <id> [--field имя] [--output plain|table|json]"
+}
+
+// Run - выводит запись по ID. С --field выводится только значение поля
+// без форматирования, чтобы подставлять его в скрипты:
+// PASSWORD=$(gophkeeper get 5 --field password).
+func (c *GetCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	field := flags.String("field", "", "вывести только значение поля")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional)
+	if err != nil {
+		return err
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	dataItem, err := c.dataService.GetData(context.Background(), c.tokenHolder.Token, id)
+	if err != nil {
+		return fmt.Errorf("ошибка получения данных: %w", err)
+	}
+
+	r := itemRecord(dataItem)
+	if *field == "" {
+		return writeRecord(c.writer, format, r)
+	}
+
+	value, ok := r.get(*field)
+	if !ok {
+		return usageErrorf("у записи %d нет поля %q, доступны: %s", id, *field, strings.Join(r.names(), ", "))
+	}
+	_, err = fmt.Fprintln(c.writer, value.value)
+	if err != nil {
+		return fmt.Errorf("ошибка вывода: %w", err)
+	}
+
+	return nil
+}
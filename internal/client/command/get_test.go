@@ -57,6 +57,27 @@ func TestGetCommand_Execute(t *testing.T) {
 			expectedOutput: "Введите ID данных: ID: 1\nТип: login_password\nДанные: user123\nМета: meta_info\nСоздано: 2023-10-18 12:00:00 +0000 UTC\n",
 			expectedError:  nil,
 		},
+		{
+			name:  "Получение банковской карты",
+			token: "valid_token",
+			input: "2\n",
+			mockSetup: func(m *MockGetDataService) {
+				dataItem := &datapb.DataItem{
+					Id:       2,
+					InfoType: "bank_card",
+					Meta:     "meta_info",
+					Created:  fixedTimestamp,
+					Payload: &datapb.DataItem_BankCard{BankCard: &datapb.BankCard{
+						Number: "4111111111111111", Holder: "IVAN IVANOV", Expiry: "12/29", Cvv: "123",
+					}},
+				}
+				m.On("GetData", mock.Anything, "valid_token", int32(2)).Return(dataItem, nil)
+			},
+			expectedOutput: "Введите ID данных: ID: 2\nТип: bank_card\n" +
+				"Номер карты: 4111111111111111\nВладелец: IVAN IVANOV\nСрок действия: 12/29\nCVV: 123\n" +
+				"Мета: meta_info\nСоздано: 2023-10-18 12:00:00 +0000 UTC\n",
+			expectedError: nil,
+		},
 		{
 			name:           "Без токена",
 			token:          "",
@@ -122,3 +143,76 @@ func TestGetCommand_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCommand_Run(t *testing.T) {
+	item := &datapb.DataItem{
+		Id:       3,
+		InfoType: "login_password",
+		Meta:     "site",
+		Created:  timestamppb.New(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)),
+		Version:  2,
+		Tags:     []string{"db", "prod"},
+		Folder:   "work/db",
+		Favorite: true,
+		Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{
+			Login: "user", Password: "p@ss",
+		}},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name:           "одно поле",
+			args:           []string{"3", "--field", "password"},
+			expectedOutput: "p@ss\n",
+		},
+		{
+			name: "plain",
+			args: []string{"3"},
+			expectedOutput: "id\t3\ntype\tlogin_password\nmeta\tsite\ncreated\t2024-05-01T10:00:00Z\n" +
+				"version\t2\nfolder\twork/db\ntags\tdb,prod\nfavorite\ttrue\nlogin\tuser\npassword\tp@ss\nurl\t\ntotp_secret\t\n",
+		},
+		{
+			name: "json",
+			args: []string{"--output", "json", "3"},
+			expectedOutput: `{
+  "created": "2024-05-01T10:00:00Z",
+  "favorite": true,
+  "folder": "work/db",
+  "id": 3,
+  "login": "user",
+  "meta": "site",
+  "password": "p@ss",
+  "tags": "db,prod",
+  "totp_secret": "",
+  "type": "login_password",
+  "url": "",
+  "version": 2
+}
+`,
+		},
+		{
+			name:         "нет такого поля",
+			args:         []string{"3", "--field", "cvv"},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockGetDataService)
+			service.On("GetData", mock.Anything, "token", int32(3)).Return(item, nil)
+			writer := &bytes.Buffer{}
+
+			cmd := NewGetCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/auditpb"
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+)
+
+// historyPageSize - сколько событий журнала показывается за раз.
+const historyPageSize = 50
+
+type auditService interface {
+	ListEvents(ctx context.Context, token string, beforeID int64, limit int32) ([]*auditpb.AuditEvent, error)
+}
+
+type versionService interface {
+	ListVersions(ctx context.Context, token string, id int32) (*datapb.ListVersionsResponse, error)
+}
+
+// HistoryCommand - журнал обращений к учётной записи: кто, откуда и когда
+// читал и менял записи, включая неудачные попытки входа. С ID записи
+// выводит её сохранённые прошлые версии.
+type HistoryCommand struct {
+	auditService   auditService
+	versionService versionService
+	tokenHolder    *entity.TokenHolder
+	reader         io.Reader
+	writer         io.Writer
+}
+
+func NewHistoryCommand(
+	auditService auditService,
+	versionService versionService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *HistoryCommand {
+	return &HistoryCommand{
+		auditService:   auditService,
+		versionService: versionService,
+		tokenHolder:    tokenHolder,
+		reader:         reader,
+		writer:         writer,
+	}
+}
+
+func (c *HistoryCommand) Name() string {
+	return "history"
+}
+
+// Execute - выводит журнал страницами от новых событий к старым.
+func (c *HistoryCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	var beforeID int64
+	for {
+		events, err := c.auditService.ListEvents(context.Background(), c.tokenHolder.Token, beforeID, historyPageSize)
+		if err != nil {
+			return fmt.Errorf("ошибка получения журнала: %w", err)
+		}
+		if err := writeRecords(c.writer, FormatTable, historyColumns, historyRecords(events)); err != nil {
+			return err
+		}
+		if len(events) < historyPageSize {
+			return nil
+		}
+
+		answer, err := prompter.field("Показать более старые события? (y/N)", "")
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(answer, "y") {
+			return nil
+		}
+		beforeID = events[len(events)-1].Id
+	}
+}
+
+func (c *HistoryCommand) Usage() string {
+	return "history [--limit 50] [--before id] [--output plain|table|json] | history <id> [версия]"
+}
+
+// Run - выводит одну страницу журнала. --before - id последнего события
+// предыдущей страницы. С ID записи выводит список её прошлых версий,
+// с ID и номером версии - содержимое этой версии.
+func (c *HistoryCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	limit := flags.Int("limit", historyPageSize, "сколько событий вывести")
+	before := flags.Int64("before", 0, "выводить события старше события с этим id")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return c.runVersions(positional, format)
+	}
+	if *limit <= 0 || *before < 0 {
+		return usageErrorf("--limit должен быть положительным, --before - неотрицательным")
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	events, err := c.auditService.ListEvents(context.Background(), c.tokenHolder.Token, *before, int32(*limit))
+	if err != nil {
+		return fmt.Errorf("ошибка получения журнала: %w", err)
+	}
+
+	return writeRecords(c.writer, format, historyColumns, historyRecords(events))
+}
+
+// runVersions - выводит прошлые версии записи или одну из них целиком.
+func (c *HistoryCommand) runVersions(positional []string, format Format) error {
+	var id int32
+	var version int64
+	var err error
+	if len(positional) > 1 {
+		id, version, err = parseIDVersion(positional)
+	} else {
+		id, err = parseID(positional)
+	}
+	if err != nil {
+		return err
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	res, err := c.versionService.ListVersions(context.Background(), c.tokenHolder.Token, id)
+	if err != nil {
+		return fmt.Errorf("ошибка получения истории записи: %w", err)
+	}
+	if version == 0 {
+		return writeRecords(c.writer, format, versionColumns, versionRecords(res.Versions))
+	}
+
+	for _, v := range res.Versions {
+		if v.GetVersion() == version {
+			return writeRecord(c.writer, format, versionRecord(id, v))
+		}
+	}
+	return fmt.Errorf("версии %d нет в истории записи %d", version, id)
+}
+
+// historyColumns - поля события в выводе history.
+var historyColumns = []string{"id", "time", "action", "item", "ip", "outcome", "client"}
+
+func historyRecords(events []*auditpb.AuditEvent) []record {
+	rows := make([]record, 0, len(events))
+	for _, e := range events {
+		item := ""
+		if e.GetDataId() != 0 {
+			item = fmt.Sprint(e.GetDataId())
+		}
+		rows = append(rows, record{
+			{"id", e.GetId()},
+			{"time", e.GetCreatedAt().AsTime().Local().Format(time.DateTime)},
+			// Полное имя метода длинное, для чтения хватает последней части: GetData, LoginUser.
+			{"action", path.Base(e.GetAction())},
+			{"item", item},
+			{"ip", e.GetClientIp()},
+			{"outcome", e.GetOutcome()},
+			{"client", e.GetUserAgent()},
+		})
+	}
+	return rows
+}
+
+// versionColumns - поля версии в выводе history <id>.
+var versionColumns = []string{"version", "saved", "type", "meta"}
+
+func versionRecords(versions []*datapb.DataVersion) []record {
+	rows := make([]record, 0, len(versions))
+	for _, v := range versions {
+		rows = append(rows, record{
+			{"version", v.GetVersion()},
+			{"saved", v.GetSavedAt().AsTime().Local().Format(time.DateTime)},
+			{"type", v.GetInfoType()},
+			{"meta", v.GetMeta()},
+		})
+	}
+	return rows
+}
+
+// versionRecord - версия записи id целиком, с содержимым как в get.
+func versionRecord(id int32, v *datapb.DataVersion) record {
+	item := &datapb.DataItem{InfoType: v.GetInfoType(), Info: v.GetInfo()}
+	payload.Decode(v.GetInfo(), item)
+
+	r := record{
+		{"id", id},
+		{"version", v.GetVersion()},
+		{"type", v.GetInfoType()},
+		{"meta", v.GetMeta()},
+		{"saved", v.GetSavedAt().AsTime().Format(time.RFC3339)},
+	}
+	return append(r, payloadFields(item)...)
+}
@@ -0,0 +1,193 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/auditpb"
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) ListEvents(
+	ctx context.Context, token string, beforeID int64, limit int32,
+) ([]*auditpb.AuditEvent, error) {
+	args := m.Called(ctx, token, beforeID, limit)
+	events, _ := args.Get(0).([]*auditpb.AuditEvent)
+	return events, args.Error(1)
+}
+
+type MockVersionService struct {
+	mock.Mock
+}
+
+func (m *MockVersionService) ListVersions(
+	ctx context.Context, token string, id int32,
+) (*datapb.ListVersionsResponse, error) {
+	args := m.Called(ctx, token, id)
+	res, _ := args.Get(0).(*datapb.ListVersionsResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockVersionService) RestoreVersion(ctx context.Context, token string, id int32, version int64) (int64, error) {
+	args := m.Called(ctx, token, id, version)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestHistoryCommand_Run(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2030, 1, 1, 12, 0, 0, 0, time.Local)
+	events := []*auditpb.AuditEvent{
+		{
+			Id: 9, Action: "/data.DataService/GetData", DataId: 5, ClientIp: "10.0.0.1",
+			UserAgent: "gophkeeper-client/linux", Outcome: "OK", CreatedAt: timestamppb.New(created),
+		},
+		{
+			Id: 8, Action: "/auth.Auth/LoginUser", ClientIp: "10.0.0.2",
+			Outcome: "Unauthenticated", CreatedAt: timestamppb.New(created),
+		},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(m *MockAuditService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "последние события",
+			args: []string{},
+			setupMock: func(m *MockAuditService) {
+				m.On("ListEvents", ctx, "token", int64(0), int32(historyPageSize)).Return(events, nil)
+			},
+			expectedOutput: "9\t2030-01-01 12:00:00\tGetData\t5\t10.0.0.1\tOK\tgophkeeper-client/linux\n" +
+				"8\t2030-01-01 12:00:00\tLoginUser\t\t10.0.0.2\tUnauthenticated\t\n",
+		},
+		{
+			name: "следующая страница",
+			args: []string{"--limit", "2", "--before", "8"},
+			setupMock: func(m *MockAuditService) {
+				m.On("ListEvents", ctx, "token", int64(8), int32(2)).Return([]*auditpb.AuditEvent{}, nil)
+			},
+		},
+		{
+			name: "ошибка сервера",
+			args: []string{},
+			setupMock: func(m *MockAuditService) {
+				m.On("ListEvents", ctx, "token", int64(0), int32(historyPageSize)).Return(nil, errors.New("недоступен"))
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "некорректный размер",
+			args:         []string{"--limit", "0"},
+			setupMock:    func(m *MockAuditService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := new(MockAuditService)
+			tt.setupMock(audit)
+			writer := &bytes.Buffer{}
+
+			err := NewHistoryCommand(audit, new(MockVersionService), &entity.TokenHolder{Token: "token"}, &bytes.Buffer{}, writer).Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			audit.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHistoryCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	page := make([]*auditpb.AuditEvent, historyPageSize)
+	for i := range page {
+		page[i] = &auditpb.AuditEvent{Id: int64(100 - i), Action: "/data.DataService/ListData", Outcome: "OK"}
+	}
+	audit := new(MockAuditService)
+	audit.On("ListEvents", ctx, "token", int64(0), int32(historyPageSize)).Return(page, nil)
+	audit.On("ListEvents", ctx, "token", page[len(page)-1].Id, int32(historyPageSize)).
+		Return([]*auditpb.AuditEvent{{Id: 3, Action: "/auth.Auth/LoginUser", Outcome: "OK"}}, nil)
+	writer := &bytes.Buffer{}
+
+	err := NewHistoryCommand(audit, new(MockVersionService), &entity.TokenHolder{Token: "token"}, bytes.NewBufferString("y\n"), writer).Execute()
+
+	require.NoError(t, err)
+	assert.Contains(t, writer.String(), "Показать более старые события?")
+	assert.Contains(t, writer.String(), "LoginUser")
+	audit.AssertExpectations(t)
+}
+
+func TestHistoryCommand_RunVersions(t *testing.T) {
+	ctx := context.Background()
+	saved := time.Date(2030, 1, 1, 12, 0, 0, 0, time.Local)
+	history := &datapb.ListVersionsResponse{Versions: []*datapb.DataVersion{
+		{
+			Version: 2, InfoType: "login_password", Meta: "github", SavedAt: timestamppb.New(saved),
+			Info: `{"loginPassword":{"login":"alice","password":"old"}}`,
+		},
+		{Version: 1, InfoType: "login_password", Info: "legacy", Meta: "gh", SavedAt: timestamppb.New(saved)},
+	}}
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "список версий",
+			args: []string{"5"},
+			expectedOutput: "2\t2030-01-01 12:00:00\tlogin_password\tgithub\n" +
+				"1\t2030-01-01 12:00:00\tlogin_password\tgh\n",
+		},
+		{
+			name: "версия целиком",
+			args: []string{"5", "2"},
+			expectedOutput: "id\t5\nversion\t2\ntype\tlogin_password\nmeta\tgithub\n" +
+				"saved\t" + saved.UTC().Format(time.RFC3339) + "\n" +
+				"login\talice\npassword\told\nurl\t\ntotp_secret\t\n",
+		},
+		{
+			name: "версия до появления типов",
+			args: []string{"5", "1"},
+			expectedOutput: "id\t5\nversion\t1\ntype\tlogin_password\nmeta\tgh\n" +
+				"saved\t" + saved.UTC().Format(time.RFC3339) + "\ncontent\tlegacy\n",
+		},
+		{name: "версии нет", args: []string{"5", "7"}, expectedCode: ExitFailure},
+		{name: "некорректная версия", args: []string{"5", "x"}, expectedCode: ExitUsage},
+		{name: "лишние аргументы", args: []string{"5", "2", "1"}, expectedCode: ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions := new(MockVersionService)
+			if tt.expectedCode != ExitUsage {
+				versions.On("ListVersions", ctx, "token", int32(5)).Return(history, nil)
+			}
+			writer := &bytes.Buffer{}
+
+			err := NewHistoryCommand(new(MockAuditService), versions, &entity.TokenHolder{Token: "token"}, &bytes.Buffer{}, writer).
+				Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			versions.AssertExpectations(t)
+		})
+	}
+}
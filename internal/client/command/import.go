@@ -0,0 +1,136 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+// formatArchive - формат архива команды export.
+const formatArchive = "gophkeeper"
+
+type importService interface {
+	Import(ctx context.Context, token, passphrase string, r io.Reader, dryRun bool) ([]entity.ImportEntry, error)
+	ImportForeign(ctx context.Context, token, format string, r io.Reader, dryRun bool) ([]entity.ImportEntry, error)
+}
+
+// ImportCommand - добавляет записи из архива export или экспорта другого
+// менеджера паролей, пропуская те, что уже есть на сервере.
+type ImportCommand struct {
+	archiveService importService
+	tokenHolder    *entity.TokenHolder
+	// passphrase - парольная фраза для неинтерактивного режима.
+	passphrase string
+	reader     io.Reader
+	writer     io.Writer
+}
+
+func NewImportCommand(
+	archiveService importService,
+	tokenHolder *entity.TokenHolder,
+	passphrase string,
+	reader io.Reader,
+	writer io.Writer,
+) *ImportCommand {
+	return &ImportCommand{
+		archiveService: archiveService,
+		tokenHolder:    tokenHolder,
+		passphrase:     passphrase,
+		reader:         reader,
+		writer:         writer,
+	}
+}
+
+func (c *ImportCommand) Name() string {
+	return "import"
+}
+
+func (c *ImportCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	format, err := prompter.field("Формат: gophkeeper, keepass, bitwarden, 1password, csv", formatArchive)
+	if err != nil {
+		return err
+	}
+	path, err := prompter.field("Путь к файлу", "")
+	if err != nil {
+		return err
+	}
+	passphrase := ""
+	if format == formatArchive {
+		if passphrase, err = prompter.field("Парольная фраза архива", ""); err != nil {
+			return err
+		}
+	}
+	answer, err := prompter.field("Только проверить, ничего не добавляя? (y/N)", "")
+	if err != nil {
+		return err
+	}
+
+	return c.importFile(path, format, passphrase, strings.EqualFold(answer, "y"), FormatTable)
+}
+
+func (c *ImportCommand) Usage() string {
+	return "import <file> [--format gophkeeper|keepass|bitwarden|1password|csv] [--dry-run] " +
+		"[--output plain|table|json]"
+}
+
+// Run - парольная фраза архива gophkeeper берётся из GOPHKEEPER_EXPORT_PASSPHRASE.
+func (c *ImportCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	format := flags.String("format", formatArchive, "формат файла: gophkeeper, keepass, bitwarden, 1password, csv")
+	dryRun := flags.Bool("dry-run", false, "только посчитать новые записи и дубликаты")
+	positional, output, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("ожидается ровно один путь к файлу, получено: %s", strings.Join(positional, " "))
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	return c.importFile(positional[0], *format, c.passphrase, *dryRun, output)
+}
+
+// importFile - импортирует файл и выводит отчёт по каждой записи.
+func (c *ImportCommand) importFile(path, format, passphrase string, dryRun bool, output Format) error {
+	if format == formatArchive && passphrase == "" {
+		return usageErrorf("не задана парольная фраза архива")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var report []entity.ImportEntry
+	if format == formatArchive {
+		report, err = c.archiveService.Import(context.Background(), c.tokenHolder.Token, passphrase, file, dryRun)
+	} else {
+		report, err = c.archiveService.ImportForeign(context.Background(), c.tokenHolder.Token, format, file, dryRun)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка импорта: %w", err)
+	}
+
+	rows := make([]record, 0, len(report))
+	for _, e := range report {
+		rows = append(rows, record{{"status", e.Status}, {"type", e.InfoType}, {"title", e.Title}, {"reason", e.Reason}})
+	}
+
+	return writeRecords(c.writer, output, importColumns, rows)
+}
+
+// importColumns - поля отчёта импорта.
+var importColumns = []string{"status", "type", "title", "reason"}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportCommand_Run(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "backup.gkx")
+	require.NoError(t, os.WriteFile(path, []byte("archive"), 0o600))
+	report := []entity.ImportEntry{
+		{Status: entity.ImportAdded, InfoType: "login_password", Title: "почта"},
+		{Status: entity.ImportDuplicate, InfoType: "text", Title: "заметка"},
+	}
+
+	tests := []struct {
+		name           string
+		passphrase     string
+		args           []string
+		setupMock      func(m *MockArchiveService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name:       "архив",
+			passphrase: "фраза",
+			args:       []string{path},
+			setupMock: func(m *MockArchiveService) {
+				m.On("Import", ctx, "token", "фраза", mock.Anything, false).Return(report, nil)
+			},
+			expectedOutput: "added\tlogin_password\tпочта\t\nduplicate\ttext\tзаметка\t\n",
+		},
+		{
+			name: "пробный импорт Bitwarden",
+			args: []string{"--dry-run", path, "--format", "bitwarden", "--json"},
+			setupMock: func(m *MockArchiveService) {
+				m.On("ImportForeign", ctx, "token", "bitwarden", mock.Anything, true).Return([]entity.ImportEntry{
+					{Status: entity.ImportSkipped, Title: "паспорт", Reason: "не поддерживается"},
+				}, nil)
+			},
+			expectedOutput: "[\n  {\n    \"reason\": \"не поддерживается\",\n    \"status\": \"skipped\",\n" +
+				"    \"title\": \"паспорт\",\n    \"type\": \"\"\n  }\n]\n",
+		},
+		{
+			name:       "неверная фраза",
+			passphrase: "другая",
+			args:       []string{path},
+			setupMock: func(m *MockArchiveService) {
+				m.On("Import", ctx, "token", "другая", mock.Anything, false).Return(nil, errors.New("неверная фраза"))
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "файла нет",
+			passphrase:   "фраза",
+			args:         []string{path + ".missing"},
+			setupMock:    func(m *MockArchiveService) {},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "нет парольной фразы архива",
+			args:         []string{path},
+			setupMock:    func(m *MockArchiveService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := new(MockArchiveService)
+			tt.setupMock(archive)
+			writer := &bytes.Buffer{}
+
+			cmd := NewImportCommand(archive, &entity.TokenHolder{Token: "token"}, tt.passphrase, &bytes.Buffer{}, writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			archive.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportCommand_Execute(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keepass.xml")
+	require.NoError(t, os.WriteFile(path, []byte("<KeePassFile/>"), 0o600))
+	archive := new(MockArchiveService)
+	archive.On("ImportForeign", context.Background(), "token", "keepass", mock.Anything, true).
+		Return([]entity.ImportEntry{{Status: entity.ImportNew, InfoType: "login_password", Title: "почта"}}, nil)
+	writer := &bytes.Buffer{}
+	input := bytes.NewBufferString("keepass\n" + path + "\ny\n")
+
+	err := NewImportCommand(archive, &entity.TokenHolder{Token: "token"}, "", input, writer).Execute()
+
+	require.NoError(t, err)
+	assert.NotContains(t, writer.String(), "Парольная фраза")
+	assert.Contains(t, writer.String(), "new     login_password  почта")
+	archive.AssertExpectations(t)
+}
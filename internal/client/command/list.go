@@ -0,0 +1,208 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	listPageSize   = 20
+	listDateLayout = "2006-01-02"
+)
+
+type listDataService interface {
+	ListData(ctx context.Context, token string, req *datapb.ListDataRequest) (*datapb.ListDataResponse, error)
+}
+
+type ListCommand struct {
+	dataService listDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewListCommand(
+	dataService listDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *ListCommand {
+	return &ListCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *ListCommand) Name() string {
+	return "list"
+}
+
+func (c *ListCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	scanner := bufio.NewScanner(c.reader)
+
+	infoType, err := c.prompt(scanner, "Тип информации (пусто - все): ")
+	if err != nil {
+		return err
+	}
+
+	createdFrom, err := c.promptDate(scanner, "Создано с (ГГГГ-ММ-ДД, пусто - без ограничения): ")
+	if err != nil {
+		return err
+	}
+
+	createdTo, err := c.promptDate(scanner, "Создано до (ГГГГ-ММ-ДД, пусто - без ограничения): ")
+	if err != nil {
+		return err
+	}
+
+	req := &datapb.ListDataRequest{
+		InfoType:    infoType,
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		PageSize:    listPageSize,
+	}
+
+	for {
+		res, err := c.dataService.ListData(context.Background(), c.tokenHolder.Token, req)
+		if err != nil {
+			return fmt.Errorf("ошибка получения списка данных: %w", err)
+		}
+
+		if len(res.Items) == 0 && req.Cursor == "" {
+			fmt.Fprintln(c.writer, "Данные не найдены.")
+			return nil
+		}
+
+		for _, item := range res.Items {
+			fmt.Fprintf(c.writer, "%d\t%s\t%s\t%s\n",
+				item.Id, item.InfoType, item.Created.AsTime().Format(time.DateTime), item.Meta)
+		}
+
+		if res.NextCursor == "" {
+			return nil
+		}
+
+		answer, err := c.prompt(scanner, "Показать следующую страницу? (y/n): ")
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(answer, "y") {
+			return nil
+		}
+
+		req.Cursor = res.NextCursor
+	}
+}
+
+func (c *ListCommand) prompt(scanner *bufio.Scanner, text string) (string, error) {
+	_, err := fmt.Fprint(c.writer, text)
+	if err != nil {
+		return "", fmt.Errorf("ошибка вывода запроса: %w", err)
+	}
+	if !scanner.Scan() {
+		return "", fmt.Errorf("ошибка ввода: unexpected EOF")
+	}
+
+	return strings.TrimSpace(scanner.Text()), nil
+}
+
+func (c *ListCommand) promptDate(scanner *bufio.Scanner, text string) (*timestamppb.Timestamp, error) {
+	value, err := c.prompt(scanner, text)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseListDate(value)
+}
+
+func parseListDate(value string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(listDateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная дата: %w", err)
+	}
+
+	return timestamppb.New(date), nil
+}
+
+func (c *ListCommand) Usage() string {
+	return "list [--type тип] [--from ГГГГ-ММ-ДД] [--to ГГГГ-ММ-ДД] [--vault id] [--tag тег] [--folder папка|/] " +
+		"[--favorites] [--json] [--output plain|table|json]"
+}
+
+// Run - выводит все подходящие записи без постраничных вопросов.
+func (c *ListCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	infoType := flags.String("type", "", "тип информации")
+	from := flags.String("from", "", "создано с (ГГГГ-ММ-ДД)")
+	to := flags.String("to", "", "создано до (ГГГГ-ММ-ДД)")
+	vaultID := flags.Int("vault", 0, "ID общего хранилища, 0 - личные данные")
+	tag := flags.String("tag", "", "только записи с тегом")
+	folder := flags.String("folder", "", "только записи прямо в папке, / - вне папок")
+	favorites := flags.Bool("favorites", false, "только избранное")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("лишние аргументы: %s", strings.Join(positional, " "))
+	}
+
+	req := &datapb.ListDataRequest{
+		InfoType:      *infoType,
+		PageSize:      listPageSize,
+		VaultId:       int32(*vaultID),
+		Tag:           strings.TrimSpace(*tag),
+		FavoritesOnly: *favorites,
+	}
+	if *folder != "" {
+		path, err := parseFolder(*folder)
+		if err != nil {
+			return err
+		}
+		req.Folder = folderTitle(path)
+	}
+	if req.CreatedFrom, err = parseListDate(*from); err != nil {
+		return &UsageError{Err: err}
+	}
+	if req.CreatedTo, err = parseListDate(*to); err != nil {
+		return &UsageError{Err: err}
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	rows := []record{}
+	for {
+		res, err := c.dataService.ListData(context.Background(), c.tokenHolder.Token, req)
+		if err != nil {
+			return fmt.Errorf("ошибка получения списка данных: %w", err)
+		}
+		for _, item := range res.Items {
+			rows = append(rows, listRecord(item))
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		req.Cursor = res.NextCursor
+	}
+
+	return writeRecords(c.writer, format, listColumns, rows)
+}
@@ -0,0 +1,243 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockListDataService struct {
+	mock.Mock
+}
+
+func (m *MockListDataService) ListData(
+	ctx context.Context, token string, req *datapb.ListDataRequest,
+) (*datapb.ListDataResponse, error) {
+	args := m.Called(ctx, token, req)
+	res, _ := args.Get(0).(*datapb.ListDataResponse)
+	return res, args.Error(1)
+}
+
+func TestListCommand_Execute(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	prompts := "Тип информации (пусто - все): " +
+		"Создано с (ГГГГ-ММ-ДД, пусто - без ограничения): " +
+		"Создано до (ГГГГ-ММ-ДД, пусто - без ограничения): "
+
+	tests := []struct {
+		name           string
+		token          string
+		input          string
+		mockSetup      func(m *MockListDataService)
+		expectedOutput string
+		expectedError  error
+	}{
+		{
+			name:  "Вывод двух страниц",
+			token: "valid_token",
+			input: "text\n2024-05-01\n\ny\n",
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "valid_token", mock.MatchedBy(func(r *datapb.ListDataRequest) bool {
+					return r.Cursor == "" && r.InfoType == "text" && r.CreatedTo == nil &&
+						r.CreatedFrom.AsTime().Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(&datapb.ListDataResponse{
+					Items: []*datapb.DataHeader{
+						{Id: 1, InfoType: "text", Meta: "заметка", Created: timestamppb.New(created)},
+					},
+					NextCursor: "next",
+				}, nil).Once()
+				m.On("ListData", mock.Anything, "valid_token", mock.MatchedBy(func(r *datapb.ListDataRequest) bool {
+					return r.Cursor == "next"
+				})).Return(&datapb.ListDataResponse{
+					Items: []*datapb.DataHeader{
+						{Id: 2, InfoType: "text", Meta: "ещё одна", Created: timestamppb.New(created)},
+					},
+				}, nil).Once()
+			},
+			expectedOutput: prompts +
+				"1\ttext\t2024-05-01 12:00:00\tзаметка\n" +
+				"Показать следующую страницу? (y/n): " +
+				"2\ttext\t2024-05-01 12:00:00\tещё одна\n",
+			expectedError: nil,
+		},
+		{
+			name:  "Отказ от следующей страницы",
+			token: "valid_token",
+			input: "\n\n\nn\n",
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "valid_token", mock.Anything).Return(&datapb.ListDataResponse{
+					Items: []*datapb.DataHeader{
+						{Id: 1, InfoType: "text", Meta: "заметка", Created: timestamppb.New(created)},
+					},
+					NextCursor: "next",
+				}, nil).Once()
+			},
+			expectedOutput: prompts +
+				"1\ttext\t2024-05-01 12:00:00\tзаметка\n" +
+				"Показать следующую страницу? (y/n): ",
+			expectedError: nil,
+		},
+		{
+			name:  "Пустой список",
+			token: "valid_token",
+			input: "\n\n\n",
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "valid_token", mock.Anything).
+					Return(&datapb.ListDataResponse{}, nil).Once()
+			},
+			expectedOutput: prompts + "Данные не найдены.\n",
+			expectedError:  nil,
+		},
+		{
+			name:           "Отсутствие токена",
+			token:          "",
+			input:          "",
+			mockSetup:      func(m *MockListDataService) {},
+			expectedOutput: "",
+			expectedError:  errors.New("вы должны войти в систему"),
+		},
+		{
+			name:           "Некорректная дата",
+			token:          "valid_token",
+			input:          "\n01.05.2024\n",
+			mockSetup:      func(m *MockListDataService) {},
+			expectedOutput: "Тип информации (пусто - все): Создано с (ГГГГ-ММ-ДД, пусто - без ограничения): ",
+			expectedError:  errors.New("некорректная дата"),
+		},
+		{
+			name:  "Ошибка сервиса",
+			token: "valid_token",
+			input: "\n\n\n",
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "valid_token", mock.Anything).
+					Return(nil, fmt.Errorf("service error")).Once()
+			},
+			expectedOutput: prompts,
+			expectedError:  errors.New("ошибка получения списка данных: service error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockListDataService)
+			tt.mockSetup(mockService)
+
+			tokenHolder := &entity.TokenHolder{
+				Token: tt.token,
+			}
+
+			reader := strings.NewReader(tt.input)
+			var writer bytes.Buffer
+
+			cmd := NewListCommand(mockService, tokenHolder, reader, &writer)
+
+			err := cmd.Execute()
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedOutput, writer.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListCommand_Run(t *testing.T) {
+	created := timestamppb.New(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	first := &datapb.DataHeader{Id: 1, InfoType: "text", Meta: "a", Created: created}
+	second := &datapb.DataHeader{Id: 2, InfoType: "bank_card", Meta: "b", Created: created}
+
+	tests := []struct {
+		name           string
+		args           []string
+		mockSetup      func(m *MockListDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "все страницы, plain",
+			args: []string{"--type", "text"},
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.InfoType == "text" && req.Cursor == ""
+				})).Return(&datapb.ListDataResponse{Items: []*datapb.DataHeader{first}, NextCursor: "c1"}, nil)
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.Cursor == "c1"
+				})).Return(&datapb.ListDataResponse{Items: []*datapb.DataHeader{second}}, nil)
+			},
+			expectedOutput: "1\ttext\t2024-05-01 10:00:00\ta\n2\tbank_card\t2024-05-01 10:00:00\tb\n",
+		},
+		{
+			name: "таблица",
+			args: []string{"--output", "table"},
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "token", mock.Anything).
+					Return(&datapb.ListDataResponse{Items: []*datapb.DataHeader{first}}, nil)
+			},
+			expectedOutput: "ID  TYPE  CREATED              META\n1   text  2024-05-01 10:00:00  a\n",
+		},
+		{
+			name: "пустой список в JSON",
+			args: []string{"--json"},
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "token", mock.Anything).Return(&datapb.ListDataResponse{}, nil)
+			},
+			expectedOutput: "[]\n",
+		},
+		{
+			name: "фильтр по тегу, папке и избранному",
+			args: []string{"--tag", "db", "--folder", "work/ db/", "--favorites"},
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.Tag == "db" && req.Folder == "work/db" && req.FavoritesOnly
+				})).Return(&datapb.ListDataResponse{Items: []*datapb.DataHeader{first}}, nil)
+			},
+			expectedOutput: "1\ttext\t2024-05-01 10:00:00\ta\n",
+		},
+		{
+			name: "записи вне папок",
+			args: []string{"--folder", "/"},
+			mockSetup: func(m *MockListDataService) {
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.Folder == "/"
+				})).Return(&datapb.ListDataResponse{}, nil)
+			},
+		},
+		{
+			name:         "некорректная дата",
+			args:         []string{"--from", "01.05.2024"},
+			mockSetup:    func(m *MockListDataService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockListDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewListCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -3,34 +3,48 @@ package command
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 )
 
 type service interface {
-	Login(ctx context.Context, login, password string) (string, error)
+	Login(ctx context.Context, login, password string) (*entity.AuthTokens, *entity.KDFParams, error)
+	CompleteLogin(ctx context.Context, challenge, code string) (*entity.AuthTokens, *entity.KDFParams, error)
+	keyCheckSaver
 }
 
 type LoginCommand struct {
 	authService service
+	keyDeriver  keyDeriver
 	tokenHolder *entity.TokenHolder
+	keyHolder   *entity.KeyHolder
 	reader      io.Reader
 	writer      io.Writer
+	// defaultLogin - логин из профиля, подставляется при пустом вводе.
+	defaultLogin string
 }
 
 func NewLoginCommand(
 	authService service,
+	keyDeriver keyDeriver,
 	tokenHolder *entity.TokenHolder,
+	keyHolder *entity.KeyHolder,
+	defaultLogin string,
 	reader io.Reader,
 	writer io.Writer,
 ) *LoginCommand {
 	return &LoginCommand{
-		authService: authService,
-		tokenHolder: tokenHolder,
-		reader:      reader,
-		writer:      writer,
+		authService:  authService,
+		keyDeriver:   keyDeriver,
+		tokenHolder:  tokenHolder,
+		keyHolder:    keyHolder,
+		defaultLogin: defaultLogin,
+		reader:       reader,
+		writer:       writer,
 	}
 }
 
@@ -40,7 +54,12 @@ func (c *LoginCommand) Name() string {
 
 func (c *LoginCommand) Execute() error {
 	var login, password string
-	_, err := fmt.Fprint(c.writer, "Введите login: ")
+	var err error
+	if c.defaultLogin != "" {
+		_, err = fmt.Fprintf(c.writer, "Введите login (%s): ", c.defaultLogin)
+	} else {
+		_, err = fmt.Fprint(c.writer, "Введите login: ")
+	}
 	if err != nil {
 		return fmt.Errorf("ошибка stdin login: %w", err)
 	}
@@ -50,6 +69,9 @@ func (c *LoginCommand) Execute() error {
 	} else {
 		return fmt.Errorf("ошибка ввода логина: %w", scanner.Err())
 	}
+	if login == "" {
+		login = c.defaultLogin
+	}
 
 	_, err = fmt.Fprint(c.writer, "Введите password: ")
 	if err != nil {
@@ -61,12 +83,74 @@ func (c *LoginCommand) Execute() error {
 		return fmt.Errorf("ошибка ввода пароля: %w", scanner.Err())
 	}
 
-	token, err := c.authService.Login(context.Background(), login, password)
+	tokens, kdf, err := c.authService.Login(context.Background(), login, password)
+	var twoFactor *entity.TwoFactorRequiredError
+	if errors.As(err, &twoFactor) {
+		tokens, kdf, err = c.completeLogin(scanner, twoFactor.Challenge)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка входа: %w", err)
+	}
+
+	key, err := readMasterKey(scanner, c.writer, c.keyDeriver, kdf)
+	if err != nil {
+		return err
+	}
+	err = verifyMasterKey(c.keyDeriver, c.authService, tokens, key, kdf)
 	if err != nil {
 		return fmt.Errorf("ошибка входа: %w", err)
 	}
 
-	c.tokenHolder.Token = token
+	c.tokenHolder.Set(tokens)
+	c.keyHolder.Set(key, kdf)
 	fmt.Println("Вход выполнен успешно.")
 	return nil
 }
+
+// completeLogin - запрашивает код второго фактора и завершает вход.
+func (c *LoginCommand) completeLogin(
+	scanner *bufio.Scanner, challenge string,
+) (*entity.AuthTokens, *entity.KDFParams, error) {
+	_, err := fmt.Fprint(c.writer, "Введите код 2FA или код восстановления: ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка вывода запроса кода 2FA: %w", err)
+	}
+	if !scanner.Scan() {
+		return nil, nil, fmt.Errorf("ошибка ввода кода 2FA: %w", scanner.Err())
+	}
+
+	return c.authService.CompleteLogin(context.Background(), challenge, strings.TrimSpace(scanner.Text()))
+}
+
+// Authenticate - вход без диалога для неинтерактивного режима. code нужен,
+// только если у пользователя включена 2FA.
+func (c *LoginCommand) Authenticate(login, password, code, masterPassword string) error {
+	if login == "" || password == "" || masterPassword == "" {
+		return fmt.Errorf("не заданы логин, пароль или мастер-пароль")
+	}
+
+	tokens, kdf, err := c.authService.Login(context.Background(), login, password)
+	var twoFactor *entity.TwoFactorRequiredError
+	if errors.As(err, &twoFactor) {
+		if code == "" {
+			return fmt.Errorf("включена 2FA, нужен код: %w", err)
+		}
+		tokens, kdf, err = c.authService.CompleteLogin(context.Background(), twoFactor.Challenge, strings.TrimSpace(code))
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка входа: %w", err)
+	}
+
+	key, err := c.keyDeriver.DeriveKey(masterPassword, kdf)
+	if err != nil {
+		return fmt.Errorf("ошибка получения ключа шифрования: %w", err)
+	}
+	err = verifyMasterKey(c.keyDeriver, c.authService, tokens, key, kdf)
+	if err != nil {
+		return fmt.Errorf("ошибка входа: %w", err)
+	}
+
+	c.tokenHolder.Set(tokens)
+	c.keyHolder.Set(key, kdf)
+	return nil
+}
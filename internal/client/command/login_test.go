@@ -15,41 +15,94 @@ type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) Login(ctx context.Context, login, password string) (string, error) {
+func (m *MockService) Login(
+	ctx context.Context, login, password string,
+) (*entity.AuthTokens, *entity.KDFParams, error) {
 	args := m.Called(ctx, login, password)
-	return args.String(0), args.Error(1)
+	tokens, _ := args.Get(0).(*entity.AuthTokens)
+	kdf, _ := args.Get(1).(*entity.KDFParams)
+	return tokens, kdf, args.Error(2)
+}
+
+func (m *MockService) SetKeyCheck(ctx context.Context, token string, keyCheck []byte) error {
+	args := m.Called(ctx, token, keyCheck)
+	return args.Error(0)
+}
+
+func (m *MockService) CompleteLogin(
+	ctx context.Context, challenge, code string,
+) (*entity.AuthTokens, *entity.KDFParams, error) {
+	args := m.Called(ctx, challenge, code)
+	tokens, _ := args.Get(0).(*entity.AuthTokens)
+	kdf, _ := args.Get(1).(*entity.KDFParams)
+	return tokens, kdf, args.Error(2)
+}
+
+type mockKeyDeriver struct {
+	mock.Mock
+}
+
+func (m *mockKeyDeriver) DeriveKey(masterPassword string, params *entity.KDFParams) ([]byte, error) {
+	args := m.Called(masterPassword, params)
+	key, _ := args.Get(0).([]byte)
+	return key, args.Error(1)
+}
+
+func (m *mockKeyDeriver) NewKeyCheck(key []byte) ([]byte, error) {
+	args := m.Called(key)
+	keyCheck, _ := args.Get(0).([]byte)
+	return keyCheck, args.Error(1)
+}
+
+func (m *mockKeyDeriver) CheckKey(key, keyCheck []byte) error {
+	args := m.Called(key, keyCheck)
+	return args.Error(0)
+}
+
+var testKDF = &entity.KDFParams{
+	Salt: []byte("salt"), KeyCheck: []byte("check"), Time: 1, Memory: 1024, Threads: 1,
 }
 
 func TestLoginCommand_Execute_Success(t *testing.T) {
 	mockService := new(MockService)
 	expectedToken := "mocked_token"
-	mockService.On("Login", mock.Anything, "testuser", "testpass").Return(expectedToken, nil)
+	mockService.On("Login", mock.Anything, "testuser", "testpass").
+		Return(&entity.AuthTokens{AccessToken: expectedToken, RefreshToken: "refresh"}, testKDF, nil)
 
 	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+	keyDeriver := new(mockKeyDeriver)
 
-	input := "testuser\ntestpass\n"
+	keyDeriver.On("DeriveKey", "master", testKDF).Return([]byte("derived-key"), nil)
+	keyDeriver.On("CheckKey", []byte("derived-key"), testKDF.KeyCheck).Return(nil)
+
+	input := "testuser\ntestpass\nmaster\n"
 	reader := bytes.NewBufferString(input)
 	writer := &bytes.Buffer{}
 
-	cmd := NewLoginCommand(mockService, tokenHolder, reader, writer)
+	cmd := NewLoginCommand(mockService, keyDeriver, tokenHolder, keyHolder, "", reader, writer)
 
 	err := cmd.Execute()
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedToken, tokenHolder.Token)
+	assert.Equal(t, "refresh", tokenHolder.RefreshToken)
+	assert.Equal(t, []byte("derived-key"), keyHolder.Key)
 }
 
 func TestLoginCommand_Execute_AuthError(t *testing.T) {
 	mockService := new(MockService)
-	mockService.On("Login", mock.Anything, "testuser", "wrongpass").Return("", errors.New("authentication failed"))
+	mockService.On("Login", mock.Anything, "testuser", "wrongpass").Return(nil, nil, errors.New("authentication failed"))
 
 	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+	keyDeriver := new(mockKeyDeriver)
 
 	input := "testuser\nwrongpass\n"
 	reader := bytes.NewBufferString(input)
 	writer := &bytes.Buffer{}
 
-	cmd := NewLoginCommand(mockService, tokenHolder, reader, writer)
+	cmd := NewLoginCommand(mockService, keyDeriver, tokenHolder, keyHolder, "", reader, writer)
 
 	err := cmd.Execute()
 
@@ -61,12 +114,14 @@ func TestLoginCommand_Execute_AuthError(t *testing.T) {
 func TestLoginCommand_Execute_InputError(t *testing.T) {
 	mockService := new(MockService)
 	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+	keyDeriver := new(mockKeyDeriver)
 
 	t.Run("Ошибка ввода логина", func(t *testing.T) {
 		reader := bytes.NewBuffer(nil)
 		writer := &bytes.Buffer{}
 
-		cmd := NewLoginCommand(mockService, tokenHolder, reader, writer)
+		cmd := NewLoginCommand(mockService, keyDeriver, tokenHolder, keyHolder, "", reader, writer)
 
 		err := cmd.Execute()
 
@@ -80,7 +135,7 @@ func TestLoginCommand_Execute_InputError(t *testing.T) {
 		reader := bytes.NewBufferString(input)
 		writer := &bytes.Buffer{}
 
-		cmd := NewLoginCommand(mockService, tokenHolder, reader, writer)
+		cmd := NewLoginCommand(mockService, keyDeriver, tokenHolder, keyHolder, "", reader, writer)
 
 		err := cmd.Execute()
 
@@ -93,6 +148,8 @@ func TestLoginCommand_Execute_InputError(t *testing.T) {
 func TestLoginCommand_Execute_WriteError(t *testing.T) {
 	mockService := new(MockService)
 	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+	keyDeriver := new(mockKeyDeriver)
 
 	errorWriter := &ErrorWriter{}
 
@@ -100,7 +157,7 @@ func TestLoginCommand_Execute_WriteError(t *testing.T) {
 	reader := bytes.NewBufferString(input)
 	writer := errorWriter
 
-	cmd := NewLoginCommand(mockService, tokenHolder, reader, writer)
+	cmd := NewLoginCommand(mockService, keyDeriver, tokenHolder, keyHolder, "", reader, writer)
 
 	err := cmd.Execute()
 
@@ -115,3 +172,239 @@ type ErrorWriter struct{}
 func (e *ErrorWriter) Write(p []byte) (n int, err error) {
 	return 0, errors.New("write error")
 }
+
+func TestLoginCommand_Execute_MasterKeyError(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("Login", mock.Anything, "testuser", "testpass").Return(&entity.AuthTokens{AccessToken: "mocked_token"}, nil, nil)
+
+	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+	keyDeriver := new(mockKeyDeriver)
+	keyDeriver.On("DeriveKey", "master", (*entity.KDFParams)(nil)).
+		Return(nil, errors.New("сервер не вернул параметры KDF"))
+
+	reader := bytes.NewBufferString("testuser\ntestpass\nmaster\n")
+	writer := &bytes.Buffer{}
+
+	cmd := NewLoginCommand(mockService, keyDeriver, tokenHolder, keyHolder, "", reader, writer)
+
+	err := cmd.Execute()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ошибка получения ключа шифрования")
+	assert.Empty(t, tokenHolder.Token)
+	assert.Empty(t, keyHolder.Key)
+}
+
+func TestLoginCommand_Execute_TwoFactor(t *testing.T) {
+	tests := []struct {
+		name          string
+		completeErr   error
+		input         string
+		expectedToken string
+		expectedErr   string
+	}{
+		{
+			name:          "код подходит",
+			input:         "testuser\ntestpass\n 123456 \nmaster\n",
+			expectedToken: "mocked_token",
+		},
+		{
+			name:        "неверный код",
+			input:       "testuser\ntestpass\n 123456 \n",
+			completeErr: errors.New("неверный код подтверждения"),
+			expectedErr: "неверный код подтверждения",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("Login", mock.Anything, "testuser", "testpass").
+				Return(nil, nil, &entity.TwoFactorRequiredError{Challenge: "challenge"})
+			if tt.completeErr != nil {
+				mockService.On("CompleteLogin", mock.Anything, "challenge", "123456").Return(nil, nil, tt.completeErr)
+			} else {
+				mockService.On("CompleteLogin", mock.Anything, "challenge", "123456").
+					Return(&entity.AuthTokens{AccessToken: "mocked_token"}, testKDF, nil)
+			}
+
+			keyDeriver := new(mockKeyDeriver)
+			keyDeriver.On("DeriveKey", "master", testKDF).Return([]byte("derived-key"), nil)
+			keyDeriver.On("CheckKey", []byte("derived-key"), testKDF.KeyCheck).Return(nil)
+			tokenHolder := &entity.TokenHolder{}
+			writer := &bytes.Buffer{}
+
+			cmd := NewLoginCommand(
+				mockService, keyDeriver, tokenHolder, &entity.KeyHolder{}, "", bytes.NewBufferString(tt.input), writer,
+			)
+			err := cmd.Execute()
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Contains(t, writer.String(), "Введите код 2FA")
+			}
+			assert.Equal(t, tt.expectedToken, tokenHolder.Token)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginCommand_Authenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          string
+		twoFactor     bool
+		expectedToken string
+		expectedErr   string
+	}{
+		{
+			name:          "без 2FA",
+			expectedToken: "mocked_token",
+		},
+		{
+			name:          "с кодом 2FA",
+			code:          "123456",
+			twoFactor:     true,
+			expectedToken: "mocked_token",
+		},
+		{
+			name:        "2FA без кода",
+			twoFactor:   true,
+			expectedErr: "нужен код",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &entity.AuthTokens{AccessToken: "mocked_token"}
+			mockService := new(MockService)
+			keyDeriver := new(mockKeyDeriver)
+			if tt.twoFactor {
+				mockService.On("Login", mock.Anything, "user", "pass").
+					Return(nil, nil, &entity.TwoFactorRequiredError{Challenge: "challenge"})
+			} else {
+				mockService.On("Login", mock.Anything, "user", "pass").Return(tokens, testKDF, nil)
+			}
+			if tt.code != "" {
+				mockService.On("CompleteLogin", mock.Anything, "challenge", tt.code).Return(tokens, testKDF, nil)
+			}
+			if tt.expectedErr == "" {
+				keyDeriver.On("DeriveKey", "master", testKDF).Return([]byte("derived-key"), nil)
+				keyDeriver.On("CheckKey", []byte("derived-key"), testKDF.KeyCheck).Return(nil)
+			}
+			tokenHolder := &entity.TokenHolder{}
+			keyHolder := &entity.KeyHolder{}
+			writer := &bytes.Buffer{}
+
+			cmd := NewLoginCommand(mockService, keyDeriver, tokenHolder, keyHolder, "", &bytes.Buffer{}, writer)
+			err := cmd.Authenticate("user", "pass", tt.code, "master")
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []byte("derived-key"), keyHolder.Key)
+			}
+			assert.Equal(t, tt.expectedToken, tokenHolder.Token)
+			assert.Empty(t, writer.String())
+			mockService.AssertExpectations(t)
+			keyDeriver.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginCommand_Execute_DefaultLogin(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("Login", mock.Anything, "alice", "testpass").
+		Return(&entity.AuthTokens{AccessToken: "mocked_token"}, testKDF, nil)
+	keyDeriver := new(mockKeyDeriver)
+	keyDeriver.On("DeriveKey", "master", testKDF).Return([]byte("derived-key"), nil)
+	keyDeriver.On("CheckKey", []byte("derived-key"), testKDF.KeyCheck).Return(nil)
+	tokenHolder := &entity.TokenHolder{}
+	writer := &bytes.Buffer{}
+
+	cmd := NewLoginCommand(
+		mockService, keyDeriver, tokenHolder, &entity.KeyHolder{}, "alice",
+		bytes.NewBufferString("\ntestpass\nmaster\n"), writer,
+	)
+
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, writer.String(), "Введите login (alice): ")
+	assert.Equal(t, "mocked_token", tokenHolder.Token)
+	mockService.AssertExpectations(t)
+}
+
+func TestVerifyMasterKey(t *testing.T) {
+	tokens := &entity.AuthTokens{AccessToken: "token"}
+	key := []byte("derived-key")
+
+	tests := []struct {
+		setup       func(deriver *mockKeyDeriver, saver *MockService)
+		params      *entity.KDFParams
+		expectedErr error
+		name        string
+	}{
+		{
+			name:   "мастер-пароль верный",
+			params: testKDF,
+			setup: func(deriver *mockKeyDeriver, _ *MockService) {
+				deriver.On("CheckKey", key, testKDF.KeyCheck).Return(nil)
+			},
+		},
+		{
+			name:   "мастер-пароль неверный",
+			params: testKDF,
+			setup: func(deriver *mockKeyDeriver, _ *MockService) {
+				deriver.On("CheckKey", key, testKDF.KeyCheck).Return(entity.ErrWrongMasterPassword)
+			},
+			expectedErr: entity.ErrWrongMasterPassword,
+		},
+		{
+			name:   "проверки ещё нет",
+			params: &entity.KDFParams{Salt: []byte("salt")},
+			setup: func(deriver *mockKeyDeriver, saver *MockService) {
+				deriver.On("NewKeyCheck", key).Return([]byte("new-check"), nil)
+				saver.On("SetKeyCheck", mock.Anything, "token", []byte("new-check")).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deriver := new(mockKeyDeriver)
+			saver := new(MockService)
+			tt.setup(deriver, saver)
+
+			err := verifyMasterKey(deriver, saver, tokens, key, tt.params)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			deriver.AssertExpectations(t)
+			saver.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginCommand_Execute_WrongMasterPassword(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("Login", mock.Anything, "testuser", "testpass").
+		Return(&entity.AuthTokens{AccessToken: "mocked_token"}, testKDF, nil)
+	keyDeriver := new(mockKeyDeriver)
+	keyDeriver.On("DeriveKey", "wrong", testKDF).Return([]byte("other-key"), nil)
+	keyDeriver.On("CheckKey", []byte("other-key"), testKDF.KeyCheck).Return(entity.ErrWrongMasterPassword)
+	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+
+	cmd := NewLoginCommand(
+		mockService, keyDeriver, tokenHolder, keyHolder, "",
+		bytes.NewBufferString("testuser\ntestpass\nwrong\n"), &bytes.Buffer{},
+	)
+	err := cmd.Execute()
+
+	assert.ErrorIs(t, err, entity.ErrWrongMasterPassword)
+	assert.Equal(t, ExitAuth, ExitCode(err))
+	assert.Empty(t, tokenHolder.Token)
+	assert.Empty(t, keyHolder.Key)
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type sessionRevoker interface {
+	RevokeSession(ctx context.Context, token string, id int64) error
+}
+
+type LogoutCommand struct {
+	authService sessionRevoker
+	tokenHolder *entity.TokenHolder
+	keyHolder   *entity.KeyHolder
+	writer      io.Writer
+}
+
+func NewLogoutCommand(
+	authService sessionRevoker,
+	tokenHolder *entity.TokenHolder,
+	keyHolder *entity.KeyHolder,
+	writer io.Writer,
+) *LogoutCommand {
+	return &LogoutCommand{
+		authService: authService,
+		tokenHolder: tokenHolder,
+		keyHolder:   keyHolder,
+		writer:      writer,
+	}
+}
+
+func (c *LogoutCommand) Name() string {
+	return "logout"
+}
+
+// Execute - отзывает текущую сессию на сервере и забывает токены и ключ
+// шифрования. Локально выход выполняется, даже если сервер недоступен.
+func (c *LogoutCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	err := c.authService.RevokeSession(context.Background(), c.tokenHolder.Token, 0)
+
+	c.tokenHolder.Clear()
+	c.keyHolder.Clear()
+
+	if err != nil {
+		return fmt.Errorf("локальный выход выполнен, но сессию на сервере отозвать не удалось: %w", err)
+	}
+
+	_, err = fmt.Fprintln(c.writer, "Выход выполнен.")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLogoutCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		revokeErr      error
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "успешный выход",
+			token:          "valid_token",
+			expectedOutput: "Выход выполнен.\n",
+		},
+		{
+			name:          "сервер недоступен",
+			token:         "valid_token",
+			revokeErr:     errors.New("unavailable"),
+			expectedError: "локальный выход выполнен, но сессию на сервере отозвать не удалось: unavailable",
+		},
+		{
+			name:          "не авторизован",
+			expectedError: "вы должны войти в систему",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSessionsService)
+			if tt.token != "" {
+				mockService.On("RevokeSession", mock.Anything, tt.token, int64(0)).Return(tt.revokeErr)
+			}
+			tokenHolder := &entity.TokenHolder{Token: tt.token, RefreshToken: "refresh"}
+			keyHolder := &entity.KeyHolder{Key: []byte("key")}
+			var output bytes.Buffer
+
+			err := NewLogoutCommand(mockService, tokenHolder, keyHolder, &output).Execute()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedOutput, output.String())
+			if tt.token != "" {
+				assert.Empty(t, tokenHolder.Token)
+				assert.Empty(t, tokenHolder.RefreshToken)
+				assert.Nil(t, keyHolder.Key)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
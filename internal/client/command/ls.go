@@ -0,0 +1,168 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/label"
+)
+
+type lsDataService interface {
+	ListData(ctx context.Context, token string, req *datapb.ListDataRequest) (*datapb.ListDataResponse, error)
+	ListFolders(ctx context.Context, token string) ([]string, error)
+}
+
+// lsColumns - поля строки в выводе ls: вложенная папка или запись.
+var lsColumns = []string{"type", "id", "name"}
+
+// lsFolderType - тип строки ls для вложенной папки.
+const lsFolderType = "folder"
+
+type LsCommand struct {
+	dataService lsDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewLsCommand(
+	dataService lsDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *LsCommand {
+	return &LsCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *LsCommand) Name() string {
+	return "ls"
+}
+
+func (c *LsCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	_, err := fmt.Fprint(c.writer, "Папка (пусто - верхний уровень): ")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода запроса на ввод папки: %w", err)
+	}
+	scanner := bufio.NewScanner(c.reader)
+	if !scanner.Scan() {
+		return fmt.Errorf("ошибка ввода папки")
+	}
+
+	folder, err := label.Folder(scanner.Text())
+	if err != nil {
+		return err
+	}
+	rows, err := c.list(folder)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		_, err = fmt.Fprintln(c.writer, "Папка пуста.")
+		if err != nil {
+			return fmt.Errorf("ошибка вывода результата: %w", err)
+		}
+		return nil
+	}
+
+	return writeRecords(c.writer, FormatTable, lsColumns, rows)
+}
+
+func (c *LsCommand) Usage() string {
+	return "ls [папка] [--output plain|table|json]"
+}
+
+// Run - выводит вложенные папки (с "/" на конце), затем записи,
+// лежащие прямо в папке. Без папки - верхний уровень личных записей.
+func (c *LsCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return usageErrorf("лишние аргументы: %s", strings.Join(positional[1:], " "))
+	}
+	var folder string
+	if len(positional) == 1 {
+		if folder, err = parseFolder(positional[0]); err != nil {
+			return err
+		}
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	rows, err := c.list(folder)
+	if err != nil {
+		return err
+	}
+
+	return writeRecords(c.writer, format, lsColumns, rows)
+}
+
+func (c *LsCommand) list(folder string) ([]record, error) {
+	folders, err := c.dataService.ListFolders(context.Background(), c.tokenHolder.Token)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка папок: %w", err)
+	}
+
+	rows := []record{}
+	for _, child := range subfolders(folders, folder) {
+		rows = append(rows, record{{"type", lsFolderType}, {"id", ""}, {"name", child + label.Separator}})
+	}
+
+	req := &datapb.ListDataRequest{PageSize: listPageSize, Folder: folderTitle(folder)}
+	for {
+		res, err := c.dataService.ListData(context.Background(), c.tokenHolder.Token, req)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения списка данных: %w", err)
+		}
+		for _, item := range res.Items {
+			rows = append(rows, record{{"type", item.GetInfoType()}, {"id", item.GetId()}, {"name", item.GetMeta()}})
+		}
+		if res.NextCursor == "" {
+			return rows, nil
+		}
+		req.Cursor = res.NextCursor
+	}
+}
+
+// subfolders - имена папок, вложенных прямо в parent. Промежуточная папка
+// видна, даже если записи есть только глубже: для "a/b/c" у "a" есть "b".
+func subfolders(folders []string, parent string) []string {
+	prefix := ""
+	if parent != "" {
+		prefix = parent + label.Separator
+	}
+
+	var children []string
+	for _, folder := range folders {
+		rest, ok := strings.CutPrefix(folder, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		child, _, _ := strings.Cut(rest, label.Separator)
+		if !slices.Contains(children, child) {
+			children = append(children, child)
+		}
+	}
+	sort.Strings(children)
+
+	return children
+}
@@ -0,0 +1,113 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLsDataService struct {
+	MockListDataService
+}
+
+func (m *MockLsDataService) ListFolders(ctx context.Context, token string) ([]string, error) {
+	args := m.Called(ctx, token)
+	folders, _ := args.Get(0).([]string)
+	return folders, args.Error(1)
+}
+
+func TestLsCommand_Run(t *testing.T) {
+	folders := []string{"home", "work", "work/db/prod", "work/db-old", "work/web"}
+
+	tests := []struct {
+		name           string
+		args           []string
+		mockSetup      func(m *MockLsDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "верхний уровень",
+			args: []string{},
+			mockSetup: func(m *MockLsDataService) {
+				m.On("ListFolders", mock.Anything, "token").Return(folders, nil)
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.Folder == "/"
+				})).Return(&datapb.ListDataResponse{
+					Items: []*datapb.DataHeader{{Id: 1, InfoType: "text", Meta: "заметка"}},
+				}, nil)
+			},
+			expectedOutput: "folder\t\thome/\nfolder\t\twork/\ntext\t1\tзаметка\n",
+		},
+		{
+			name: "вложенная папка, все страницы",
+			args: []string{"work/"},
+			mockSetup: func(m *MockLsDataService) {
+				m.On("ListFolders", mock.Anything, "token").Return(folders, nil)
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.Folder == "work" && req.Cursor == ""
+				})).Return(&datapb.ListDataResponse{
+					Items:      []*datapb.DataHeader{{Id: 2, InfoType: "login_password", Meta: "почта"}},
+					NextCursor: "c1",
+				}, nil)
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.Cursor == "c1"
+				})).Return(&datapb.ListDataResponse{
+					Items: []*datapb.DataHeader{{Id: 3, InfoType: "text", Meta: "ключ"}},
+				}, nil)
+			},
+			expectedOutput: "folder\t\tdb/\nfolder\t\tdb-old/\nfolder\t\tweb/\n" +
+				"login_password\t2\tпочта\ntext\t3\tключ\n",
+		},
+		{
+			name: "пустая папка в JSON",
+			args: []string{"misc", "--json"},
+			mockSetup: func(m *MockLsDataService) {
+				m.On("ListFolders", mock.Anything, "token").Return(folders, nil)
+				m.On("ListData", mock.Anything, "token", mock.Anything).Return(&datapb.ListDataResponse{}, nil)
+			},
+			expectedOutput: "[]\n",
+		},
+		{
+			name:         "две папки",
+			args:         []string{"home", "work"},
+			mockSetup:    func(m *MockLsDataService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockLsDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewLsCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLsCommand_Execute(t *testing.T) {
+	service := new(MockLsDataService)
+	service.On("ListFolders", mock.Anything, "token").Return([]string{}, nil)
+	service.On("ListData", mock.Anything, "token", mock.Anything).Return(&datapb.ListDataResponse{}, nil)
+	writer := &bytes.Buffer{}
+
+	cmd := NewLsCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader("home\n"), writer)
+	err := cmd.Execute()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Папка (пусто - верхний уровень): Папка пуста.\n", writer.String())
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type keyDeriver interface {
+	DeriveKey(masterPassword string, params *entity.KDFParams) ([]byte, error)
+	NewKeyCheck(key []byte) ([]byte, error)
+	CheckKey(key, keyCheck []byte) error
+}
+
+type keyCheckSaver interface {
+	SetKeyCheck(ctx context.Context, token string, keyCheck []byte) error
+}
+
+// readMasterKey - запрашивает мастер-пароль и выводит из него ключ шифрования.
+// Мастер-пароль не передаётся на сервер, поэтому не совпадает с паролем входа.
+func readMasterKey(
+	scanner *bufio.Scanner,
+	writer io.Writer,
+	deriver keyDeriver,
+	params *entity.KDFParams,
+) ([]byte, error) {
+	_, err := fmt.Fprint(writer, "Введите мастер-пароль: ")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка stdin мастер-пароля: %w", err)
+	}
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("ошибка ввода мастер-пароля: %w", scanner.Err())
+	}
+
+	key, err := deriver.DeriveKey(scanner.Text(), params)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ключа шифрования: %w", err)
+	}
+
+	return key, nil
+}
+
+// verifyMasterKey - проверяет ключ по проверке, которую вернул сервер. Если
+// проверки ещё нет (регистрация или пользователь, зарегистрированный до её
+// появления), она создаётся из этого ключа и сохраняется на сервере.
+func verifyMasterKey(
+	deriver keyDeriver,
+	saver keyCheckSaver,
+	tokens *entity.AuthTokens,
+	key []byte,
+	params *entity.KDFParams,
+) error {
+	if len(params.KeyCheck) > 0 {
+		return deriver.CheckKey(key, params.KeyCheck)
+	}
+
+	keyCheck, err := deriver.NewKeyCheck(key)
+	if err != nil {
+		return fmt.Errorf("ошибка создания проверки мастер-пароля: %w", err)
+	}
+
+	return saver.SetKeyCheck(context.Background(), tokens.AccessToken, keyCheck)
+}
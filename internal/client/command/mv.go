@@ -0,0 +1,134 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/label"
+)
+
+type MvCommand struct {
+	dataService labelDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewMvCommand(
+	dataService labelDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *MvCommand {
+	return &MvCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *MvCommand) Name() string {
+	return "mv"
+}
+
+func (c *MvCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	_, err := fmt.Fprint(c.writer, "Введите ID данных: ")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода запроса на ввод ID: %w", err)
+	}
+	scanner := bufio.NewScanner(c.reader)
+	if !scanner.Scan() {
+		return fmt.Errorf("ошибка ввода ID")
+	}
+	id64, err := strconv.ParseInt(scanner.Text(), 10, 32)
+	if err != nil {
+		return fmt.Errorf("некорректный ID: %w", err)
+	}
+
+	_, err = fmt.Fprint(c.writer, "Папка (/ - вне папок): ")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода запроса на ввод папки: %w", err)
+	}
+	if !scanner.Scan() {
+		return fmt.Errorf("ошибка ввода папки")
+	}
+
+	folder, err := c.move(int32(id64), scanner.Text())
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.writer, "Запись перемещена в %s\n", folderTitle(folder))
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+func (c *MvCommand) Usage() string {
+	return "mv <id> <папка|/> [--output plain|table|json]"
+}
+
+// Run - перемещает запись в папку; "/" - убрать запись из папок.
+// Папки отдельно не создаются: папка существует, пока в ней есть записи.
+func (c *MvCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return usageErrorf("ожидаются ID записи и папка")
+	}
+	id, err := parseID(positional[:1])
+	if err != nil {
+		return err
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	folder, err := c.move(id, positional[1])
+	if err != nil {
+		return err
+	}
+
+	return writeRecord(c.writer, format, record{{"id", id}, {"folder", folderTitle(folder)}})
+}
+
+func (c *MvCommand) move(id int32, path string) (string, error) {
+	folder, err := parseFolder(path)
+	if err != nil {
+		return "", err
+	}
+
+	dataItem, err := c.dataService.GetData(context.Background(), c.tokenHolder.Token, id)
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения данных: %w", err)
+	}
+
+	dataItem.Folder = folder
+	if err := c.dataService.UpdateData(context.Background(), c.tokenHolder.Token, dataItem); err != nil {
+		return "", fmt.Errorf("ошибка обновления данных: %w", err)
+	}
+
+	return folder, nil
+}
+
+// folderTitle - папка для вывода: записи вне папок показываются как "/".
+func folderTitle(folder string) string {
+	if folder == "" {
+		return label.Root
+	}
+	return folder
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMvCommand_Execute(t *testing.T) {
+	service := new(MockUpdateDataService)
+	service.On("GetData", mock.Anything, "token", int32(2)).
+		Return(&datapb.DataItem{Id: 2, Folder: "home", Tags: []string{"db"}}, nil)
+	service.On("UpdateData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+		return item.Folder == "work/db" && strings.Join(item.Tags, ",") == "db"
+	})).Return(nil)
+	writer := &bytes.Buffer{}
+
+	cmd := NewMvCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader("2\nwork/ db\n"), writer)
+	err := cmd.Execute()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Введите ID данных: Папка (/ - вне папок): Запись перемещена в work/db\n", writer.String())
+	service.AssertExpectations(t)
+}
+
+func TestMvCommand_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		mockSetup      func(m *MockUpdateDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "в папку",
+			args: []string{"2", "/work/db/"},
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(2)).Return(&datapb.DataItem{Id: 2}, nil)
+				m.On("UpdateData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					return item.Folder == "work/db"
+				})).Return(nil)
+			},
+			expectedOutput: "id\t2\nfolder\twork/db\n",
+		},
+		{
+			name: "из папок",
+			args: []string{"2", "/", "--json"},
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(2)).Return(&datapb.DataItem{Id: 2, Folder: "home"}, nil)
+				m.On("UpdateData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					return item.Folder == ""
+				})).Return(nil)
+			},
+			expectedOutput: "{\n  \"folder\": \"/\",\n  \"id\": 2\n}\n",
+		},
+		{
+			name:         "без папки",
+			args:         []string{"2"},
+			mockSetup:    func(m *MockUpdateDataService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "слишком глубокая папка",
+			args:         []string{"2", strings.Repeat("a/", 17)},
+			mockSetup:    func(m *MockUpdateDataService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockUpdateDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewMvCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
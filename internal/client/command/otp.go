@@ -0,0 +1,89 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/totp"
+)
+
+type OTPCommand struct {
+	dataService getDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+	now         func() time.Time
+}
+
+func NewOTPCommand(
+	dataService getDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *OTPCommand {
+	return &OTPCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+		now:         time.Now,
+	}
+}
+
+func (c *OTPCommand) Name() string {
+	return "otp"
+}
+
+// Execute - выводит текущий TOTP-код записи login_password. Код считается
+// локально из расшифрованной записи, поэтому работает и без сети,
+// если запись есть в кеше.
+func (c *OTPCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	_, err := fmt.Fprint(c.writer, "Введите ID данных: ")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода запроса: %w", err)
+	}
+	scanner := bufio.NewScanner(c.reader)
+	if !scanner.Scan() {
+		return fmt.Errorf("ошибка ввода ID")
+	}
+	id, err := strconv.ParseInt(scanner.Text(), 10, 32)
+	if err != nil {
+		return fmt.Errorf("некорректный ID: %w", err)
+	}
+
+	item, err := c.dataService.GetData(context.Background(), c.tokenHolder.Token, int32(id))
+	if err != nil {
+		return fmt.Errorf("ошибка получения данных: %w", err)
+	}
+
+	secret := item.GetLoginPassword().GetTotpSecret()
+	if secret == "" {
+		return fmt.Errorf("для записи %d не настроен TOTP", id)
+	}
+	key, err := totp.ParseKey(secret)
+	if err != nil {
+		return fmt.Errorf("ошибка разбора TOTP: %w", err)
+	}
+
+	now := c.now()
+	code, err := key.Code(now)
+	if err != nil {
+		return fmt.Errorf("ошибка генерации кода: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.writer, "Код: %s\nОсталось: %d с\n", code, key.Remaining(now))
+	if err != nil {
+		return fmt.Errorf("ошибка вывода кода: %w", err)
+	}
+
+	return nil
+}
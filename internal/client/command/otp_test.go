@@ -0,0 +1,111 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOTPCommand_Execute(t *testing.T) {
+	// Ключ и ожидаемые коды - из приложения B RFC 6238.
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	withTOTP := func(value string) *datapb.DataItem {
+		return &datapb.DataItem{
+			Id:       1,
+			InfoType: "login_password",
+			Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{
+				Login: "user", TotpSecret: value,
+			}},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		input          string
+		mockSetup      func(m *MockGetDataService)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:  "секрет base32",
+			token: "token",
+			input: "1\n",
+			mockSetup: func(m *MockGetDataService) {
+				m.On("GetData", mock.Anything, "token", int32(1)).Return(withTOTP(secret), nil)
+			},
+			expectedOutput: "Введите ID данных: Код: 050471\nОсталось: 29 с\n",
+		},
+		{
+			name:  "ссылка otpauth с 8 цифрами",
+			token: "token",
+			input: "1\n",
+			mockSetup: func(m *MockGetDataService) {
+				m.On("GetData", mock.Anything, "token", int32(1)).
+					Return(withTOTP("otpauth://totp/ACME:user?secret="+secret+"&digits=8"), nil)
+			},
+			expectedOutput: "Введите ID данных: Код: 14050471\nОсталось: 29 с\n",
+		},
+		{
+			name:  "TOTP не настроен",
+			token: "token",
+			input: "1\n",
+			mockSetup: func(m *MockGetDataService) {
+				m.On("GetData", mock.Anything, "token", int32(1)).Return(withTOTP(""), nil)
+			},
+			expectedOutput: "Введите ID данных: ",
+			expectedError:  "для записи 1 не настроен TOTP",
+		},
+		{
+			name:  "запись не найдена",
+			token: "token",
+			input: "7\n",
+			mockSetup: func(m *MockGetDataService) {
+				m.On("GetData", mock.Anything, "token", int32(7)).
+					Return(nil, errors.New("запись 7 не найдена в локальном кеше"))
+			},
+			expectedOutput: "Введите ID данных: ",
+			expectedError:  "ошибка получения данных",
+		},
+		{
+			name:           "некорректный ID",
+			token:          "token",
+			input:          "abc\n",
+			mockSetup:      func(m *MockGetDataService) {},
+			expectedOutput: "Введите ID данных: ",
+			expectedError:  "некорректный ID",
+		},
+		{
+			name:          "без входа",
+			mockSetup:     func(m *MockGetDataService) {},
+			expectedError: "вы должны войти в систему",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockGetDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewOTPCommand(service, &entity.TokenHolder{Token: tt.token}, bytes.NewBufferString(tt.input), writer)
+			cmd.now = func() time.Time { return time.Unix(1111111111, 0) }
+
+			err := cmd.Execute()
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+)
+
+// Format - формат вывода неинтерактивных команд.
+type Format string
+
+const (
+	// FormatPlain - значения через табуляцию, без заголовков: удобно для cut и read.
+	FormatPlain Format = "plain"
+	// FormatTable - выровненная таблица с заголовками для человека.
+	FormatTable Format = "table"
+	// FormatJSON - JSON для jq и CI.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat - разбирает значение флага --output.
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case FormatPlain:
+		return FormatPlain, nil
+	case FormatTable:
+		return FormatTable, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("неизвестный формат вывода %q, допустимы: plain, table, json", value)
+	}
+}
+
+// outputField - именованное значение записи. Порядок полей сохраняется
+// в plain и table, в JSON значения сохраняют свой тип.
+type outputField struct {
+	name  string
+	value any
+}
+
+type record []outputField
+
+func (r record) get(name string) (outputField, bool) {
+	for _, f := range r {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return outputField{}, false
+}
+
+func (r record) names() []string {
+	names := make([]string, len(r))
+	for i, f := range r {
+		names[i] = f.name
+	}
+	return names
+}
+
+func (r record) object() map[string]any {
+	obj := make(map[string]any, len(r))
+	for _, f := range r {
+		obj[f.name] = f.value
+	}
+	return obj
+}
+
+// writeRecord - выводит одну запись: в plain и table по полю на строку.
+func writeRecord(w io.Writer, format Format, r record) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, r.object())
+	case FormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, f := range r {
+			if _, err := fmt.Fprintf(tw, "%s\t%v\n", f.name, f.value); err != nil {
+				return fmt.Errorf("ошибка вывода: %w", err)
+			}
+		}
+		return flushTable(tw)
+	default:
+		for _, f := range r {
+			if _, err := fmt.Fprintf(w, "%s\t%v\n", f.name, f.value); err != nil {
+				return fmt.Errorf("ошибка вывода: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// writeID - выводит ID созданной или удалённой записи: в JSON объектом,
+// иначе одним числом, чтобы его можно было сохранить в переменную.
+func writeID(w io.Writer, format Format, id int32) error {
+	if format == FormatJSON {
+		return writeJSON(w, map[string]int32{"id": id})
+	}
+	if _, err := fmt.Fprintln(w, id); err != nil {
+		return fmt.Errorf("ошибка вывода: %w", err)
+	}
+	return nil
+}
+
+// writeRecords - выводит список записей с одинаковым набором полей.
+func writeRecords(w io.Writer, format Format, columns []string, rows []record) error {
+	switch format {
+	case FormatJSON:
+		objects := make([]map[string]any, len(rows))
+		for i, r := range rows {
+			objects[i] = r.object()
+		}
+		return writeJSON(w, objects)
+	case FormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t"))); err != nil {
+			return fmt.Errorf("ошибка вывода: %w", err)
+		}
+		for _, r := range rows {
+			if err := writeRow(tw, r); err != nil {
+				return err
+			}
+		}
+		return flushTable(tw)
+	default:
+		for _, r := range rows {
+			if err := writeRow(w, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// flushTable - tabwriter копит строки и пишет их в w только при Flush,
+// поэтому ошибка вывода таблицы обычно приходит отсюда.
+func flushTable(tw *tabwriter.Writer) error {
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("ошибка вывода: %w", err)
+	}
+	return nil
+}
+
+func writeRow(w io.Writer, r record) error {
+	values := make([]string, len(r))
+	for i, f := range r {
+		values[i] = fmt.Sprint(f.value)
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(values, "\t")); err != nil {
+		return fmt.Errorf("ошибка вывода: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("ошибка вывода JSON: %w", err)
+	}
+	return nil
+}
+
+// itemRecord - поля записи для вывода: общие, затем поля её типа.
+func itemRecord(item *datapb.DataItem) record {
+	r := record{
+		{"id", item.GetId()},
+		{"type", item.GetInfoType()},
+		{"meta", item.GetMeta()},
+		{"created", item.GetCreated().AsTime().Format(time.RFC3339)},
+		{"version", item.GetVersion()},
+		{"folder", folderTitle(item.GetFolder())},
+		{"tags", strings.Join(item.GetTags(), ",")},
+		{"favorite", item.GetFavorite()},
+	}
+
+	return append(r, payloadFields(item)...)
+}
+
+// payloadFields - поля содержимого записи в зависимости от её типа.
+func payloadFields(item *datapb.DataItem) record {
+	var r record
+	switch p := item.Payload.(type) {
+	case *datapb.DataItem_LoginPassword:
+		r = append(r,
+			outputField{"login", p.LoginPassword.GetLogin()},
+			outputField{"password", p.LoginPassword.GetPassword()},
+			outputField{"url", p.LoginPassword.GetUrl()},
+			outputField{"totp_secret", p.LoginPassword.GetTotpSecret()},
+		)
+	case *datapb.DataItem_Text:
+		r = append(r, outputField{"content", p.Text.GetContent()})
+	case *datapb.DataItem_Binary:
+		r = append(r,
+			outputField{"filename", p.Binary.GetFilename()},
+			outputField{"mime", p.Binary.GetMime()},
+			outputField{"size", len(p.Binary.GetBytes())},
+		)
+	case *datapb.DataItem_BankCard:
+		r = append(r,
+			outputField{"number", p.BankCard.GetNumber()},
+			outputField{"holder", p.BankCard.GetHolder()},
+			outputField{"expiry", p.BankCard.GetExpiry()},
+			outputField{"cvv", p.BankCard.GetCvv()},
+		)
+	default:
+		r = append(r, outputField{"content", item.GetInfo()})
+	}
+
+	return r
+}
+
+// listColumns - поля записи в выводе list.
+var listColumns = []string{"id", "type", "created", "meta"}
+
+func listRecord(item *datapb.DataHeader) record {
+	return record{
+		{"id", item.GetId()},
+		{"type", item.GetInfoType()},
+		{"created", item.GetCreated().AsTime().Format(time.DateTime)},
+		{"meta", item.GetMeta()},
+	}
+}
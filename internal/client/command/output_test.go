@@ -0,0 +1,26 @@
+package command
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// brokenWriter - вывод в закрытый канал или на заполненный диск.
+type brokenWriter struct{}
+
+func (brokenWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestWriteRecords_WriteError(t *testing.T) {
+	rows := []record{{{"id", 1}, {"meta", "почта"}}}
+
+	for _, format := range []Format{FormatPlain, FormatTable, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			assert.ErrorContains(t, writeRecords(brokenWriter{}, format, []string{"id", "meta"}, rows), "broken pipe")
+			assert.ErrorContains(t, writeRecord(brokenWriter{}, format, rows[0]), "broken pipe")
+		})
+	}
+}
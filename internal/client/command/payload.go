@@ -0,0 +1,199 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+	"github.com/NikolosHGW/goph-keeper/internal/totp"
+)
+
+const defaultMime = "application/octet-stream"
+
+// payloadPrompter - запрашивает поля типизированной записи по одному.
+type payloadPrompter struct {
+	scanner *bufio.Scanner
+	writer  io.Writer
+}
+
+// field - запрашивает значение поля. Если задано текущее значение,
+// оно показывается в скобках и сохраняется при пустом вводе.
+func (p *payloadPrompter) field(label, current string) (string, error) {
+	var err error
+	if current != "" {
+		_, err = fmt.Fprintf(p.writer, "%s (%s): ", label, current)
+	} else {
+		_, err = fmt.Fprintf(p.writer, "%s: ", label)
+	}
+	if err != nil {
+		return "", fmt.Errorf("ошибка вывода запроса: %w", err)
+	}
+
+	if !p.scanner.Scan() {
+		return "", fmt.Errorf("ошибка ввода поля %q: unexpected EOF", label)
+	}
+	value := p.scanner.Text()
+	if value == "" {
+		return current, nil
+	}
+
+	return value, nil
+}
+
+// fill - заполняет payload записи item по её типу. current - текущая версия
+// записи при обновлении, nil при добавлении.
+func (p *payloadPrompter) fill(item, current *datapb.DataItem) error {
+	switch item.InfoType {
+	case payload.TypeLoginPassword:
+		return p.fillLoginPassword(item, current.GetLoginPassword())
+	case payload.TypeText:
+		return p.fillText(item, current)
+	case payload.TypeBinary:
+		return p.fillBinary(item, current.GetBinary())
+	case payload.TypeBankCard:
+		return p.fillBankCard(item, current.GetBankCard())
+	default:
+		return fmt.Errorf("неизвестный тип информации: %s", item.InfoType)
+	}
+}
+
+func (p *payloadPrompter) fillLoginPassword(item *datapb.DataItem, current *datapb.LoginPassword) error {
+	lp := &datapb.LoginPassword{}
+	var err error
+	if lp.Login, err = p.field("Логин", current.GetLogin()); err != nil {
+		return err
+	}
+	if lp.Password, err = p.field("Пароль", current.GetPassword()); err != nil {
+		return err
+	}
+	if lp.Url, err = p.field("URL", current.GetUrl()); err != nil {
+		return err
+	}
+	if lp.TotpSecret, err = p.field("TOTP-секрет или ссылка otpauth://", current.GetTotpSecret()); err != nil {
+		return err
+	}
+	if err := normalizeTOTP(lp); err != nil {
+		return err
+	}
+
+	item.Payload = &datapb.DataItem_LoginPassword{LoginPassword: lp}
+	return nil
+}
+
+// normalizeTOTP - проверяет TOTP-секрет записи и подставляет логин из ссылки otpauth://,
+// если он не задан.
+func normalizeTOTP(lp *datapb.LoginPassword) error {
+	if lp.TotpSecret == "" {
+		return nil
+	}
+
+	key, err := totp.ParseKey(lp.TotpSecret)
+	if err != nil {
+		return fmt.Errorf("ошибка разбора TOTP: %w", err)
+	}
+	// Ссылка хранится целиком: в ней могут быть нестандартные алгоритм, длина кода и шаг.
+	lp.TotpSecret = strings.TrimSpace(lp.TotpSecret)
+	if lp.Login == "" {
+		lp.Login = key.Account
+	}
+
+	return nil
+}
+
+func (p *payloadPrompter) fillText(item, current *datapb.DataItem) error {
+	// Записи, созданные до появления типов, хранят текст прямо в info.
+	currentText := current.GetText().GetContent()
+	if currentText == "" {
+		currentText = current.GetInfo()
+	}
+
+	content, err := p.field("Текст", currentText)
+	if err != nil {
+		return err
+	}
+
+	item.Payload = &datapb.DataItem_Text{Text: &datapb.Text{Content: content}}
+	return nil
+}
+
+func (p *payloadPrompter) fillBinary(item *datapb.DataItem, current *datapb.Binary) error {
+	path, err := p.field("Путь к файлу", "")
+	if err != nil {
+		return err
+	}
+	if path == "" && current != nil {
+		item.Payload = &datapb.DataItem_Binary{Binary: current}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+
+	item.Payload = &datapb.DataItem_Binary{Binary: &datapb.Binary{
+		Filename: filepath.Base(path),
+		Mime:     mimeByPath(path),
+		Bytes:    content,
+	}}
+	return nil
+}
+
+func mimeByPath(path string) string {
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		return defaultMime
+	}
+	return mimeType
+}
+
+func (p *payloadPrompter) fillBankCard(item *datapb.DataItem, current *datapb.BankCard) error {
+	card := &datapb.BankCard{}
+	var err error
+	if card.Number, err = p.field("Номер карты", current.GetNumber()); err != nil {
+		return err
+	}
+	if card.Holder, err = p.field("Владелец", current.GetHolder()); err != nil {
+		return err
+	}
+	if card.Expiry, err = p.field("Срок действия (MM/YY)", current.GetExpiry()); err != nil {
+		return err
+	}
+	if card.Cvv, err = p.field("CVV", current.GetCvv()); err != nil {
+		return err
+	}
+
+	item.Payload = &datapb.DataItem_BankCard{BankCard: card}
+	return nil
+}
+
+// printPayload - выводит содержимое записи по полям её типа.
+func printPayload(w io.Writer, item *datapb.DataItem) {
+	switch p := item.Payload.(type) {
+	case *datapb.DataItem_LoginPassword:
+		fmt.Fprintf(w, "Логин: %s\n", p.LoginPassword.GetLogin())
+		fmt.Fprintf(w, "Пароль: %s\n", p.LoginPassword.GetPassword())
+		fmt.Fprintf(w, "URL: %s\n", p.LoginPassword.GetUrl())
+		if p.LoginPassword.GetTotpSecret() != "" {
+			fmt.Fprintln(w, "TOTP: настроен (код - команда otp)")
+		}
+	case *datapb.DataItem_Text:
+		fmt.Fprintf(w, "Текст: %s\n", p.Text.GetContent())
+	case *datapb.DataItem_Binary:
+		fmt.Fprintf(w, "Файл: %s (%s, %d байт)\n",
+			p.Binary.GetFilename(), p.Binary.GetMime(), len(p.Binary.GetBytes()))
+	case *datapb.DataItem_BankCard:
+		fmt.Fprintf(w, "Номер карты: %s\n", p.BankCard.GetNumber())
+		fmt.Fprintf(w, "Владелец: %s\n", p.BankCard.GetHolder())
+		fmt.Fprintf(w, "Срок действия: %s\n", p.BankCard.GetExpiry())
+		fmt.Fprintf(w, "CVV: %s\n", p.BankCard.GetCvv())
+	default:
+		fmt.Fprintf(w, "Данные: %s\n", item.Info)
+	}
+}
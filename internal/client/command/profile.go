@@ -0,0 +1,206 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type profileStore interface {
+	Load() (*entity.Profiles, error)
+	Save(p *entity.Profiles) error
+}
+
+// ProfileCommand - управление профилями: у каждого свой сервер, корневой
+// сертификат, логин и локальный кеш. Выбранный профиль применяется
+// при следующем запуске клиента.
+type ProfileCommand struct {
+	store   profileStore
+	reader  io.Reader
+	writer  io.Writer
+	current string
+}
+
+func NewProfileCommand(store profileStore, current string, reader io.Reader, writer io.Writer) *ProfileCommand {
+	return &ProfileCommand{
+		store:   store,
+		current: current,
+		reader:  reader,
+		writer:  writer,
+	}
+}
+
+func (c *ProfileCommand) Name() string {
+	return "profile"
+}
+
+func (c *ProfileCommand) Execute() error {
+	scanner := bufio.NewScanner(c.reader)
+	prompter := &payloadPrompter{scanner: scanner, writer: c.writer}
+
+	action, err := prompter.field("Действие (list/use/add)", "")
+	if err != nil {
+		return err
+	}
+
+	switch strings.TrimSpace(action) {
+	case "list":
+		return c.list(FormatTable)
+	case "use":
+		name, err := prompter.field("Имя профиля", "")
+		if err != nil {
+			return err
+		}
+		return c.use(strings.TrimSpace(name))
+	case "add":
+		name, err := prompter.field("Имя профиля", "")
+		if err != nil {
+			return err
+		}
+		p := entity.Profile{}
+		if p.Address, err = prompter.field("Адрес сервера host:port", ""); err != nil {
+			return err
+		}
+		if p.RootCertPath, err = prompter.field("Путь к корневому сертификату", ""); err != nil {
+			return err
+		}
+		if p.Login, err = prompter.field("Логин (можно пусто)", ""); err != nil {
+			return err
+		}
+		if p.CacheDir, err = prompter.field("Каталог кеша (пусто - по умолчанию)", ""); err != nil {
+			return err
+		}
+		return c.add(strings.TrimSpace(name), p)
+	default:
+		return fmt.Errorf("неизвестное действие: %s", action)
+	}
+}
+
+// RequiresLogin - профилями можно управлять без входа.
+func (c *ProfileCommand) RequiresLogin() bool {
+	return false
+}
+
+func (c *ProfileCommand) Usage() string {
+	return "profile list | profile use <имя> | profile add <имя> --address host:port [--ca путь] [--login логин] " +
+		"[--cache-dir каталог]"
+}
+
+func (c *ProfileCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	p := entity.Profile{}
+	flags.StringVar(&p.Address, "address", "", "адрес сервера host:port")
+	flags.StringVar(&p.RootCertPath, "ca", "", "путь к корневому сертификату")
+	flags.StringVar(&p.Login, "login", "", "логин")
+	flags.StringVar(&p.CacheDir, "cache-dir", "", "каталог локального кеша")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return usageErrorf("не указано действие")
+	}
+
+	action, rest := positional[0], positional[1:]
+	switch {
+	case action == "list" && len(rest) == 0:
+		return c.list(format)
+	case action == "use" && len(rest) == 1:
+		return c.use(rest[0])
+	case action == "add" && len(rest) == 1:
+		if p.Address == "" {
+			return usageErrorf("не указан --address")
+		}
+		return c.add(rest[0], p)
+	default:
+		return usageErrorf("некорректные аргументы: %s", strings.Join(positional, " "))
+	}
+}
+
+func (c *ProfileCommand) list(format Format) error {
+	profiles, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(profiles.Items))
+	for name := range profiles.Items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]record, 0, len(names))
+	for _, name := range names {
+		p := profiles.Items[name]
+		current := ""
+		if name == c.current {
+			current = "*"
+		}
+		rows = append(rows, record{
+			{"current", current},
+			{"name", name},
+			{"address", p.Address},
+			{"login", p.Login},
+		})
+	}
+
+	return writeRecords(c.writer, format, []string{"current", "name", "address", "login"}, rows)
+}
+
+func (c *ProfileCommand) use(name string) error {
+	profiles, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Items[name]; !ok {
+		return fmt.Errorf("профиль %q не найден", name)
+	}
+
+	profiles.Current = name
+	if err := c.store.Save(profiles); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.writer, "Профиль %s выбран, он будет использован при следующем запуске.\n", name)
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+func (c *ProfileCommand) add(name string, p entity.Profile) error {
+	if !entity.ValidProfileName(name) {
+		return usageErrorf("некорректное имя профиля %q: допустимы латиница, цифры, _ и -", name)
+	}
+	if p.Address == "" {
+		return fmt.Errorf("не указан адрес сервера")
+	}
+
+	profiles, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Items[name]; ok {
+		return fmt.Errorf("профиль %q уже существует", name)
+	}
+
+	profiles.Items[name] = p
+	if profiles.Current == "" {
+		profiles.Current = name
+	}
+	if err := c.store.Save(profiles); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.writer, "Профиль %s добавлен.\n", name)
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
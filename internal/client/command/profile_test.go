@@ -0,0 +1,163 @@
+package command
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProfileStore struct {
+	mock.Mock
+}
+
+func (m *MockProfileStore) Load() (*entity.Profiles, error) {
+	args := m.Called()
+	profiles, _ := args.Get(0).(*entity.Profiles)
+	return profiles, args.Error(1)
+}
+
+func (m *MockProfileStore) Save(p *entity.Profiles) error {
+	args := m.Called(p)
+	return args.Error(0)
+}
+
+func testProfiles() *entity.Profiles {
+	return &entity.Profiles{
+		Current: "personal",
+		Items: map[string]entity.Profile{
+			"personal": {Address: "home:8080", RootCertPath: "/etc/home.pem"},
+			"team":     {Address: "vault.corp:443", Login: "alice"},
+		},
+	}
+}
+
+func TestProfileCommand_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(m *MockProfileStore)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "список",
+			args: []string{"list"},
+			setupMock: func(m *MockProfileStore) {
+				m.On("Load").Return(testProfiles(), nil)
+			},
+			expectedOutput: "*\tpersonal\thome:8080\t\n\tteam\tvault.corp:443\talice\n",
+		},
+		{
+			name: "выбор профиля",
+			args: []string{"use", "team"},
+			setupMock: func(m *MockProfileStore) {
+				m.On("Load").Return(testProfiles(), nil)
+				m.On("Save", mock.MatchedBy(func(p *entity.Profiles) bool {
+					return p.Current == "team"
+				})).Return(nil)
+			},
+			expectedOutput: "Профиль team выбран, он будет использован при следующем запуске.\n",
+		},
+		{
+			name: "неизвестный профиль",
+			args: []string{"use", "nope"},
+			setupMock: func(m *MockProfileStore) {
+				m.On("Load").Return(testProfiles(), nil)
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name: "первый профиль становится текущим",
+			args: []string{"add", "work", "--address", "work:443", "--ca", "/etc/work.pem", "--login", "bob"},
+			setupMock: func(m *MockProfileStore) {
+				m.On("Load").Return(&entity.Profiles{Items: map[string]entity.Profile{}}, nil)
+				m.On("Save", &entity.Profiles{
+					Current: "work",
+					Items: map[string]entity.Profile{
+						"work": {Address: "work:443", RootCertPath: "/etc/work.pem", Login: "bob"},
+					},
+				}).Return(nil)
+			},
+			expectedOutput: "Профиль work добавлен.\n",
+		},
+		{
+			name: "профиль уже есть",
+			args: []string{"add", "team", "--address", "x:1"},
+			setupMock: func(m *MockProfileStore) {
+				m.On("Load").Return(testProfiles(), nil)
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "add без адреса",
+			args:         []string{"add", "work"},
+			setupMock:    func(m *MockProfileStore) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "имя с путём",
+			args:         []string{"add", "../x", "--address", "x:1"},
+			setupMock:    func(m *MockProfileStore) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "имя из точек",
+			args:         []string{"add", "..", "--address", "x:1"},
+			setupMock:    func(m *MockProfileStore) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "пустое имя",
+			args:         []string{"add", "", "--address", "x:1"},
+			setupMock:    func(m *MockProfileStore) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(MockProfileStore)
+			tt.setupMock(store)
+			writer := &bytes.Buffer{}
+
+			cmd := NewProfileCommand(store, "personal", &bytes.Buffer{}, writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProfileCommand_Execute(t *testing.T) {
+	store := new(MockProfileStore)
+	store.On("Load").Return(testProfiles(), nil)
+	store.On("Save", mock.MatchedBy(func(p *entity.Profiles) bool {
+		return p.Current == "personal" && p.Items["work"] == entity.Profile{Address: "work:443", RootCertPath: "/w.pem"}
+	})).Return(nil)
+	writer := &bytes.Buffer{}
+
+	cmd := NewProfileCommand(store, "personal", bytes.NewBufferString("add\nwork\nwork:443\n/w.pem\n\n\n"), writer)
+
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, writer.String(), "Профиль work добавлен.")
+	store.AssertExpectations(t)
+}
+
+func TestCLI_RunLocalCommandWithoutLogin(t *testing.T) {
+	store := new(MockProfileStore)
+	store.On("Load").Return(testProfiles(), nil)
+	login := func() error {
+		t.Fatal("вход не нужен для profile")
+		return nil
+	}
+
+	code := NewCLI(login, &bytes.Buffer{}, NewProfileCommand(store, "", &bytes.Buffer{}, &bytes.Buffer{})).
+		Run([]string{"profile", "list"})
+
+	assert.Equal(t, ExitOK, code)
+}
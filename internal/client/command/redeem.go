@@ -0,0 +1,96 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+)
+
+type redeemService interface {
+	RedeemShare(ctx context.Context, shareToken string) (*datapb.DataItem, int32, error)
+}
+
+// RedeemCommand - открывает одноразовую ссылку. Вход не нужен: ключ
+// расшифровки содержится в самом токене.
+type RedeemCommand struct {
+	shareService redeemService
+	reader       io.Reader
+	writer       io.Writer
+}
+
+func NewRedeemCommand(shareService redeemService, reader io.Reader, writer io.Writer) *RedeemCommand {
+	return &RedeemCommand{shareService: shareService, reader: reader, writer: writer}
+}
+
+func (c *RedeemCommand) Name() string {
+	return "redeem"
+}
+
+func (c *RedeemCommand) Execute() error {
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	shareToken, err := prompter.field("Токен ссылки", "")
+	if err != nil {
+		return err
+	}
+
+	item, viewsLeft, err := c.shareService.RedeemShare(context.Background(), strings.TrimSpace(shareToken))
+	if err != nil {
+		return fmt.Errorf("ошибка открытия ссылки: %w", err)
+	}
+
+	fmt.Fprintf(c.writer, "Тип: %s\n", item.InfoType)
+	printPayload(c.writer, item)
+	fmt.Fprintf(c.writer, "Мета: %s\n", item.Meta)
+	fmt.Fprintf(c.writer, "Осталось просмотров: %d\n", viewsLeft)
+
+	return nil
+}
+
+// RequiresLogin - ссылку открывают без учётной записи.
+func (c *RedeemCommand) RequiresLogin() bool {
+	return false
+}
+
+func (c *RedeemCommand) Usage() string {
+	return "redeem <токен> [--save путь] [--output plain|table|json]"
+}
+
+// Run - выводит содержимое записи по ссылке; содержимое файла с --save
+// сохраняется на диск.
+func (c *RedeemCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	save := flags.String("save", "", "куда сохранить файл из ссылки")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("ожидается ровно один токен ссылки")
+	}
+
+	item, viewsLeft, err := c.shareService.RedeemShare(context.Background(), positional[0])
+	if err != nil {
+		return fmt.Errorf("ошибка открытия ссылки: %w", err)
+	}
+
+	if *save != "" {
+		file := item.GetBinary()
+		if file == nil {
+			return fmt.Errorf("по ссылке не файл, а запись типа %s", item.InfoType)
+		}
+		if err := os.WriteFile(*save, file.Bytes, 0o600); err != nil {
+			return fmt.Errorf("ошибка сохранения файла: %w", err)
+		}
+	}
+
+	r := record{{"type", item.GetInfoType()}, {"meta", item.GetMeta()}}
+	r = append(r, payloadFields(item)...)
+	r = append(r, outputField{"views_left", viewsLeft})
+
+	return writeRecord(c.writer, format, r)
+}
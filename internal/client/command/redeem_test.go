@@ -0,0 +1,81 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockRedeemService struct {
+	mock.Mock
+}
+
+func (m *MockRedeemService) RedeemShare(ctx context.Context, shareToken string) (*datapb.DataItem, int32, error) {
+	args := m.Called(ctx, shareToken)
+	item, _ := args.Get(0).(*datapb.DataItem)
+	return item, args.Get(1).(int32), args.Error(2)
+}
+
+func TestRedeemCommand_Run(t *testing.T) {
+	ctx := context.Background()
+	item := &datapb.DataItem{
+		InfoType: "login_password",
+		Meta:     "почта",
+		Payload: &datapb.DataItem_LoginPassword{
+			LoginPassword: &datapb.LoginPassword{Login: "user", Password: "secret"},
+		},
+	}
+	shares := new(MockRedeemService)
+	shares.On("RedeemShare", ctx, "abc.key").Return(item, int32(0), nil)
+	shares.On("RedeemShare", ctx, "used.key").Return(nil, int32(0), status.Error(codes.NotFound, "ссылка не найдена"))
+	writer := &bytes.Buffer{}
+	cmd := NewRedeemCommand(shares, &bytes.Buffer{}, writer)
+
+	require.NoError(t, cmd.Run([]string{"abc.key"}))
+	assert.Equal(t, "type\tlogin_password\nmeta\tпочта\nlogin\tuser\npassword\tsecret\nurl\t\ntotp_secret\t\n"+
+		"views_left\t0\n", writer.String())
+
+	assert.Equal(t, ExitNotFound, ExitCode(cmd.Run([]string{"used.key"})))
+	assert.Equal(t, ExitUsage, ExitCode(cmd.Run(nil)))
+	assert.False(t, cmd.RequiresLogin())
+}
+
+func TestRedeemCommand_RunSave(t *testing.T) {
+	ctx := context.Background()
+	item := &datapb.DataItem{
+		InfoType: "binary",
+		Payload:  &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: "a.txt", Bytes: []byte("content")}},
+	}
+	shares := new(MockRedeemService)
+	shares.On("RedeemShare", ctx, "abc.key").Return(item, int32(1), nil)
+	path := filepath.Join(t.TempDir(), "a.txt")
+
+	err := NewRedeemCommand(shares, &bytes.Buffer{}, &bytes.Buffer{}).Run([]string{"abc.key", "--save", path})
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+}
+
+func TestRedeemCommand_Execute(t *testing.T) {
+	shares := new(MockRedeemService)
+	shares.On("RedeemShare", context.Background(), "abc.key").
+		Return(&datapb.DataItem{InfoType: "text", Payload: &datapb.DataItem_Text{Text: &datapb.Text{Content: "код"}}}, int32(2), nil)
+	writer := &bytes.Buffer{}
+
+	err := NewRedeemCommand(shares, bytes.NewBufferString("abc.key\n"), writer).Execute()
+
+	require.NoError(t, err)
+	assert.Contains(t, writer.String(), "Текст: код")
+	assert.Contains(t, writer.String(), "Осталось просмотров: 2")
+}
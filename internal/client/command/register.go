@@ -10,25 +10,32 @@ import (
 )
 
 type authService interface {
-	Register(ctx context.Context, login, password string) (string, error)
+	Register(ctx context.Context, login, password string) (*entity.AuthTokens, *entity.KDFParams, error)
+	keyCheckSaver
 }
 
 type RegisterCommand struct {
 	authService authService
+	keyDeriver  keyDeriver
 	tokenHolder *entity.TokenHolder
+	keyHolder   *entity.KeyHolder
 	reader      io.Reader
 	writer      io.Writer
 }
 
 func NewRegisterCommand(
 	authService authService,
+	keyDeriver keyDeriver,
 	tokenHolder *entity.TokenHolder,
+	keyHolder *entity.KeyHolder,
 	reader io.Reader,
 	writer io.Writer,
 ) *RegisterCommand {
 	return &RegisterCommand{
 		authService: authService,
+		keyDeriver:  keyDeriver,
 		tokenHolder: tokenHolder,
+		keyHolder:   keyHolder,
 		reader:      reader,
 		writer:      writer,
 	}
@@ -61,12 +68,22 @@ func (c *RegisterCommand) Execute() error {
 		return fmt.Errorf("ошибка ввода пароля: %w", scanner.Err())
 	}
 
-	token, err := c.authService.Register(context.Background(), login, password)
+	tokens, kdf, err := c.authService.Register(context.Background(), login, password)
 	if err != nil {
 		return fmt.Errorf("ошибка регистрации: %w", err)
 	}
 
-	c.tokenHolder.Token = token
+	key, err := readMasterKey(scanner, c.writer, c.keyDeriver, kdf)
+	if err != nil {
+		return err
+	}
+	err = verifyMasterKey(c.keyDeriver, c.authService, tokens, key, kdf)
+	if err != nil {
+		return fmt.Errorf("ошибка регистрации: %w", err)
+	}
+
+	c.tokenHolder.Set(tokens)
+	c.keyHolder.Set(key, kdf)
 	_, err = fmt.Fprintln(c.writer, "Регистрация прошла успешно.")
 	if err != nil {
 		return fmt.Errorf("ошибка Fprintln : %w", err)
@@ -15,28 +15,47 @@ type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) Register(ctx context.Context, login, password string) (string, error) {
+func (m *MockAuthService) Register(
+	ctx context.Context, login, password string,
+) (*entity.AuthTokens, *entity.KDFParams, error) {
 	args := m.Called(ctx, login, password)
-	return args.String(0), args.Error(1)
+	tokens, _ := args.Get(0).(*entity.AuthTokens)
+	kdf, _ := args.Get(1).(*entity.KDFParams)
+	return tokens, kdf, args.Error(2)
+}
+
+func (m *MockAuthService) SetKeyCheck(ctx context.Context, token string, keyCheck []byte) error {
+	args := m.Called(ctx, token, keyCheck)
+	return args.Error(0)
 }
 
 func TestRegisterCommand_Execute_Success(t *testing.T) {
 	mockAuthService := new(MockAuthService)
 	expectedToken := "mocked_token"
-	mockAuthService.On("Register", mock.Anything, "testuser", "testpass").Return(expectedToken, nil)
+	kdf := &entity.KDFParams{Salt: []byte("salt"), Time: 1, Memory: 1024, Threads: 1}
+	mockAuthService.On("Register", mock.Anything, "testuser", "testpass").
+		Return(&entity.AuthTokens{AccessToken: expectedToken, RefreshToken: "refresh"}, kdf, nil)
+	mockAuthService.On("SetKeyCheck", mock.Anything, expectedToken, []byte("check")).Return(nil)
 
 	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+	keyDeriver := new(mockKeyDeriver)
+
+	keyDeriver.On("DeriveKey", "master", kdf).Return([]byte("derived-key"), nil)
+	keyDeriver.On("NewKeyCheck", []byte("derived-key")).Return([]byte("check"), nil)
 
-	input := "testuser\ntestpass\n"
+	input := "testuser\ntestpass\nmaster\n"
 	reader := bytes.NewBufferString(input)
 	writer := &bytes.Buffer{}
 
-	cmd := NewRegisterCommand(mockAuthService, tokenHolder, reader, writer)
+	cmd := NewRegisterCommand(mockAuthService, keyDeriver, tokenHolder, keyHolder, reader, writer)
 
 	err := cmd.Execute()
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedToken, tokenHolder.Token)
+	assert.Equal(t, "refresh", tokenHolder.RefreshToken)
+	assert.Equal(t, []byte("derived-key"), keyHolder.Key)
 	assert.Contains(t, writer.String(), "Регистрация прошла успешно.")
 
 	mockAuthService.AssertExpectations(t)
@@ -44,15 +63,17 @@ func TestRegisterCommand_Execute_Success(t *testing.T) {
 
 func TestRegisterCommand_Execute_RegisterError(t *testing.T) {
 	mockAuthService := new(MockAuthService)
-	mockAuthService.On("Register", mock.Anything, "testuser", "wrongpass").Return("", errors.New("registration failed"))
+	mockAuthService.On("Register", mock.Anything, "testuser", "wrongpass").Return(nil, nil, errors.New("registration failed"))
 
 	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+	keyDeriver := new(mockKeyDeriver)
 
 	input := "testuser\nwrongpass\n"
 	reader := bytes.NewBufferString(input)
 	writer := &bytes.Buffer{}
 
-	cmd := NewRegisterCommand(mockAuthService, tokenHolder, reader, writer)
+	cmd := NewRegisterCommand(mockAuthService, keyDeriver, tokenHolder, keyHolder, reader, writer)
 
 	err := cmd.Execute()
 
@@ -68,11 +89,13 @@ func TestRegisterCommand_Execute_RegisterError(t *testing.T) {
 func TestRegisterCommand_Execute_InputError(t *testing.T) {
 	mockAuthService := new(MockAuthService)
 	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{}
+	keyDeriver := new(mockKeyDeriver)
 
 	reader := bytes.NewBuffer(nil)
 	writer := &bytes.Buffer{}
 
-	cmd := NewRegisterCommand(mockAuthService, tokenHolder, reader, writer)
+	cmd := NewRegisterCommand(mockAuthService, keyDeriver, tokenHolder, keyHolder, reader, writer)
 
 	err := cmd.Execute()
 
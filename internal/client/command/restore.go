@@ -0,0 +1,153 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type restoreDataService interface {
+	ListVersions(ctx context.Context, token string, id int32) (*datapb.ListVersionsResponse, error)
+	RestoreVersion(ctx context.Context, token string, id int32, version int64) (int64, error)
+}
+
+// RestoreCommand - делает прошлую версию записи текущей. Текущая версия
+// при этом сама уходит в историю, так что восстановление можно отменить.
+type RestoreCommand struct {
+	dataService restoreDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewRestoreCommand(
+	dataService restoreDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *RestoreCommand {
+	return &RestoreCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *RestoreCommand) Name() string {
+	return "restore"
+}
+
+// Execute - показывает сохранённые версии записи и восстанавливает выбранную.
+func (c *RestoreCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	idStr, err := prompter.field("Введите ID данных", "")
+	if err != nil {
+		return err
+	}
+	id64, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		return fmt.Errorf("некорректный ID: %w", err)
+	}
+	id := int32(id64)
+
+	res, err := c.dataService.ListVersions(context.Background(), c.tokenHolder.Token, id)
+	if err != nil {
+		return fmt.Errorf("ошибка получения истории записи: %w", err)
+	}
+	if len(res.Versions) == 0 {
+		_, err = fmt.Fprintln(c.writer, "У записи нет сохранённых версий.")
+		if err != nil {
+			return fmt.Errorf("ошибка вывода результата: %w", err)
+		}
+		return nil
+	}
+	if err := writeRecords(c.writer, FormatTable, versionColumns, versionRecords(res.Versions)); err != nil {
+		return err
+	}
+
+	versionStr, err := prompter.field("Какую версию восстановить", "")
+	if err != nil {
+		return err
+	}
+	version, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный номер версии: %w", err)
+	}
+
+	current, err := c.restore(id, version)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.writer, "Версия %d восстановлена, текущая версия записи: %d.\n", version, current)
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+func (c *RestoreCommand) Usage() string {
+	return "restore <id> <версия> [--output plain|table|json]"
+}
+
+// Run - восстанавливает версию записи и выводит ID и новую версию записи.
+// Номера версий выводит history <id>.
+func (c *RestoreCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	id, version, err := parseIDVersion(positional)
+	if err != nil {
+		return err
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	current, err := c.restore(id, version)
+	if err != nil {
+		return err
+	}
+
+	return writeRecord(c.writer, format, record{{"id", id}, {"version", current}})
+}
+
+func (c *RestoreCommand) restore(id int32, version int64) (int64, error) {
+	current, err := c.dataService.RestoreVersion(context.Background(), c.tokenHolder.Token, id, version)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка восстановления версии: %w", err)
+	}
+
+	return current, nil
+}
+
+// parseIDVersion - ID записи и номер её версии из аргументов командной строки.
+func parseIDVersion(positional []string) (int32, int64, error) {
+	if len(positional) != 2 {
+		return 0, 0, usageErrorf("ожидаются ID записи и номер версии, получено: %s", strings.Join(positional, " "))
+	}
+	id, err := parseID(positional[:1])
+	if err != nil {
+		return 0, 0, err
+	}
+	version, err := strconv.ParseInt(positional[1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, 0, usageErrorf("некорректный номер версии: %s", positional[1])
+	}
+
+	return id, version, nil
+}
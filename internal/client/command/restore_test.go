@@ -0,0 +1,101 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreCommand_Run(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		args           []string
+		mockSetup      func(m *MockVersionService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "версия восстановлена",
+			args: []string{"5", "2"},
+			mockSetup: func(m *MockVersionService) {
+				m.On("RestoreVersion", ctx, "token", int32(5), int64(2)).Return(int64(4), nil)
+			},
+			expectedOutput: "id\t5\nversion\t4\n",
+		},
+		{
+			name: "версии нет",
+			args: []string{"5", "9"},
+			mockSetup: func(m *MockVersionService) {
+				m.On("RestoreVersion", ctx, "token", int32(5), int64(9)).
+					Return(int64(0), errors.New("такой версии записи нет в истории"))
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "без версии",
+			args:         []string{"5"},
+			mockSetup:    func(m *MockVersionService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "нулевая версия",
+			args:         []string{"5", "0"},
+			mockSetup:    func(m *MockVersionService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockVersionService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			err := NewRestoreCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer).
+				Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	service := new(MockVersionService)
+	service.On("ListVersions", ctx, "token", int32(5)).Return(&datapb.ListVersionsResponse{
+		Versions: []*datapb.DataVersion{{Version: 2, InfoType: "text", Meta: "старая заметка"}},
+	}, nil)
+	service.On("RestoreVersion", ctx, "token", int32(5), int64(2)).Return(int64(4), nil)
+	writer := &bytes.Buffer{}
+
+	err := NewRestoreCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader("5\n2\n"), writer).Execute()
+
+	require.NoError(t, err)
+	assert.Contains(t, writer.String(), "старая заметка")
+	assert.Contains(t, writer.String(), "Версия 2 восстановлена, текущая версия записи: 4.")
+	service.AssertExpectations(t)
+}
+
+func TestRestoreCommand_ExecuteNoVersions(t *testing.T) {
+	ctx := context.Background()
+	service := new(MockVersionService)
+	service.On("ListVersions", ctx, "token", int32(5)).Return(&datapb.ListVersionsResponse{}, nil)
+	writer := &bytes.Buffer{}
+
+	err := NewRestoreCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader("5\n"), writer).Execute()
+
+	require.NoError(t, err)
+	assert.Equal(t, "Введите ID данных: У записи нет сохранённых версий.\n", writer.String())
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,161 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type searchDataService interface {
+	SearchData(
+		ctx context.Context, token, query string, req *datapb.SearchDataRequest,
+	) (*datapb.SearchDataResponse, error)
+	ListData(ctx context.Context, token string, req *datapb.ListDataRequest) (*datapb.ListDataResponse, error)
+	IndexData(ctx context.Context, token string, id, vaultID int32, meta string) error
+}
+
+type SearchCommand struct {
+	dataService searchDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewSearchCommand(
+	dataService searchDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *SearchCommand {
+	return &SearchCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *SearchCommand) Name() string {
+	return "search"
+}
+
+func (c *SearchCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	_, err := fmt.Fprint(c.writer, "Что найти в описании записей: ")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода запроса на ввод поиска: %w", err)
+	}
+	scanner := bufio.NewScanner(c.reader)
+	if !scanner.Scan() {
+		return fmt.Errorf("ошибка ввода поискового запроса")
+	}
+
+	rows, err := c.search(scanner.Text(), 0)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		_, err = fmt.Fprintln(c.writer, "Ничего не найдено.")
+		if err != nil {
+			return fmt.Errorf("ошибка вывода результата: %w", err)
+		}
+		return nil
+	}
+
+	return writeRecords(c.writer, FormatTable, listColumns, rows)
+}
+
+func (c *SearchCommand) Usage() string {
+	return "search <слова...> [--vault id] [--output plain|table|json] | search --reindex [--vault id]"
+}
+
+// Run - выводит записи, в описании которых есть все слова запроса
+// (слово запроса совпадает с началом слова описания). С --reindex
+// пересчитывает индекс всех записей хранилища, например сохранённых
+// до появления поиска.
+func (c *SearchCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	vaultID := flags.Int("vault", 0, "ID общего хранилища, 0 - личные данные")
+	reindex := flags.Bool("reindex", false, "пересчитать индекс поиска всех записей")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	query := strings.Join(positional, " ")
+	if *reindex && query != "" {
+		return usageErrorf("--reindex не принимает поисковый запрос")
+	}
+	if !*reindex && strings.TrimSpace(query) == "" {
+		return usageErrorf("не указан поисковый запрос")
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	if *reindex {
+		count, err := c.reindex(int32(*vaultID))
+		if err != nil {
+			return err
+		}
+		return writeRecord(c.writer, format, record{{"reindexed", count}})
+	}
+
+	rows, err := c.search(query, int32(*vaultID))
+	if err != nil {
+		return err
+	}
+
+	return writeRecords(c.writer, format, listColumns, rows)
+}
+
+func (c *SearchCommand) search(query string, vaultID int32) ([]record, error) {
+	req := &datapb.SearchDataRequest{VaultId: vaultID, PageSize: listPageSize}
+
+	rows := []record{}
+	for {
+		res, err := c.dataService.SearchData(context.Background(), c.tokenHolder.Token, query, req)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка поиска: %w", err)
+		}
+		for _, item := range res.Items {
+			rows = append(rows, listRecord(item))
+		}
+		if res.NextCursor == "" {
+			return rows, nil
+		}
+		req.Cursor = res.NextCursor
+	}
+}
+
+// reindex - пересчитывает токены поиска каждой записи хранилища vaultID
+// по её описанию и возвращает число записей.
+func (c *SearchCommand) reindex(vaultID int32) (int, error) {
+	ctx := context.Background()
+	req := &datapb.ListDataRequest{VaultId: vaultID, PageSize: listPageSize}
+
+	count := 0
+	for {
+		res, err := c.dataService.ListData(ctx, c.tokenHolder.Token, req)
+		if err != nil {
+			return count, fmt.Errorf("ошибка получения списка данных: %w", err)
+		}
+		for _, item := range res.Items {
+			if err := c.dataService.IndexData(ctx, c.tokenHolder.Token, item.Id, vaultID, item.Meta); err != nil {
+				return count, fmt.Errorf("ошибка индексации записи %d: %w", item.Id, err)
+			}
+			count++
+		}
+		if res.NextCursor == "" {
+			return count, nil
+		}
+		req.Cursor = res.NextCursor
+	}
+}
@@ -0,0 +1,141 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockSearchDataService struct {
+	MockListDataService
+}
+
+func (m *MockSearchDataService) SearchData(
+	ctx context.Context, token, query string, req *datapb.SearchDataRequest,
+) (*datapb.SearchDataResponse, error) {
+	args := m.Called(ctx, token, query, req)
+	res, _ := args.Get(0).(*datapb.SearchDataResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockSearchDataService) IndexData(ctx context.Context, token string, id, vaultID int32, meta string) error {
+	args := m.Called(ctx, token, id, vaultID, meta)
+	return args.Error(0)
+}
+
+func TestSearchCommand_Run(t *testing.T) {
+	created := timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	tests := []struct {
+		name           string
+		args           []string
+		mockSetup      func(m *MockSearchDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "все страницы",
+			args: []string{"git", "рабочий"},
+			mockSetup: func(m *MockSearchDataService) {
+				m.On("SearchData", mock.Anything, "token", "git рабочий", mock.MatchedBy(func(req *datapb.SearchDataRequest) bool {
+					return req.Cursor == ""
+				})).Return(&datapb.SearchDataResponse{
+					Items:      []*datapb.DataHeader{{Id: 1, InfoType: "text", Meta: "github рабочий", Created: created}},
+					NextCursor: "c1",
+				}, nil)
+				m.On("SearchData", mock.Anything, "token", "git рабочий", mock.MatchedBy(func(req *datapb.SearchDataRequest) bool {
+					return req.Cursor == "c1"
+				})).Return(&datapb.SearchDataResponse{
+					Items: []*datapb.DataHeader{{Id: 4, InfoType: "login_password", Meta: "gitlab рабочий", Created: created}},
+				}, nil)
+			},
+			expectedOutput: "1\ttext\t2024-01-02 03:04:05\tgithub рабочий\n" +
+				"4\tlogin_password\t2024-01-02 03:04:05\tgitlab рабочий\n",
+		},
+		{
+			name: "в хранилище, ничего не найдено",
+			args: []string{"почта", "--vault", "7", "--json"},
+			mockSetup: func(m *MockSearchDataService) {
+				m.On("SearchData", mock.Anything, "token", "почта", mock.MatchedBy(func(req *datapb.SearchDataRequest) bool {
+					return req.VaultId == 7
+				})).Return(&datapb.SearchDataResponse{}, nil)
+			},
+			expectedOutput: "[]\n",
+		},
+		{
+			name: "переиндексация",
+			args: []string{"--reindex"},
+			mockSetup: func(m *MockSearchDataService) {
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.Cursor == ""
+				})).Return(&datapb.ListDataResponse{
+					Items:      []*datapb.DataHeader{{Id: 1, Meta: "github"}},
+					NextCursor: "c1",
+				}, nil)
+				m.On("ListData", mock.Anything, "token", mock.MatchedBy(func(req *datapb.ListDataRequest) bool {
+					return req.Cursor == "c1"
+				})).Return(&datapb.ListDataResponse{Items: []*datapb.DataHeader{{Id: 2, Meta: "почта"}}}, nil)
+				m.On("IndexData", mock.Anything, "token", int32(1), int32(0), "github").Return(nil)
+				m.On("IndexData", mock.Anything, "token", int32(2), int32(0), "почта").Return(nil)
+			},
+			expectedOutput: "reindexed\t2\n",
+		},
+		{
+			name: "ошибка поиска",
+			args: []string{"git"},
+			mockSetup: func(m *MockSearchDataService) {
+				m.On("SearchData", mock.Anything, "token", "git", mock.Anything).Return(nil, errors.New("unavailable"))
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "без запроса",
+			args:         []string{"--vault", "7"},
+			mockSetup:    func(m *MockSearchDataService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "переиндексация с запросом",
+			args:         []string{"--reindex", "git"},
+			mockSetup:    func(m *MockSearchDataService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockSearchDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewSearchCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchCommand_Execute(t *testing.T) {
+	service := new(MockSearchDataService)
+	service.On("SearchData", mock.Anything, "token", "git", mock.Anything).Return(&datapb.SearchDataResponse{}, nil)
+	writer := &bytes.Buffer{}
+
+	cmd := NewSearchCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader("git\n"), writer)
+	err := cmd.Execute()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Что найти в описании записей: Ничего не найдено.\n", writer.String())
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type sessionKeeper interface {
+	Saved() (bool, error)
+	Restore(masterPassword string) (*entity.AuthTokens, *entity.KDFParams, []byte, error)
+	Save(tokens *entity.TokenHolder, keys *entity.KeyHolder) error
+}
+
+// SessionManager - переносит сессию между запусками клиента: восстанавливает
+// сохранённую при старте и сохраняет текущую после каждой команды.
+type SessionManager struct {
+	keeper      sessionKeeper
+	tokenHolder *entity.TokenHolder
+	keyHolder   *entity.KeyHolder
+	reader      io.Reader
+	writer      io.Writer
+	saved       entity.TokenHolder
+}
+
+func NewSessionManager(
+	keeper sessionKeeper,
+	tokenHolder *entity.TokenHolder,
+	keyHolder *entity.KeyHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *SessionManager {
+	return &SessionManager{
+		keeper:      keeper,
+		tokenHolder: tokenHolder,
+		keyHolder:   keyHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+// Restore - если есть сохранённая сессия, запрашивает мастер-пароль
+// и продолжает её. Пустой ввод - отказ, тогда нужен обычный вход.
+func (m *SessionManager) Restore() error {
+	saved, err := m.keeper.Saved()
+	if err != nil || !saved {
+		return err
+	}
+
+	_, err = fmt.Fprint(m.writer, "Найдена сохранённая сессия. Введите мастер-пароль (пусто - войти заново): ")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода запроса мастер-пароля: %w", err)
+	}
+	scanner := bufio.NewScanner(m.reader)
+	if !scanner.Scan() {
+		return fmt.Errorf("ошибка ввода мастер-пароля: %w", scanner.Err())
+	}
+	if scanner.Text() == "" {
+		return nil
+	}
+
+	restored, err := m.RestoreWith(scanner.Text())
+	if err != nil {
+		return err
+	}
+	if restored {
+		_, err = fmt.Fprintln(m.writer, "Сессия восстановлена.")
+	} else {
+		_, err = fmt.Fprintln(m.writer, "Сохранённая сессия истекла, выполните вход командой login.")
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreWith - продолжает сохранённую сессию без диалога. Возвращает false,
+// если сохранённой сессии нет или она истекла; истёкшая сессия удаляется.
+func (m *SessionManager) RestoreWith(masterPassword string) (bool, error) {
+	saved, err := m.keeper.Saved()
+	if err != nil || !saved {
+		return false, err
+	}
+
+	tokens, kdf, key, err := m.keeper.Restore(masterPassword)
+	if errors.Is(err, entity.ErrSessionExpired) {
+		return false, m.keeper.Save(&entity.TokenHolder{}, m.keyHolder)
+	}
+	if err != nil {
+		return false, fmt.Errorf("ошибка восстановления сессии: %w", err)
+	}
+
+	m.tokenHolder.Set(tokens)
+	m.keyHolder.Set(key, kdf)
+	m.saved = *m.tokenHolder
+
+	return true, nil
+}
+
+// Persist - сохраняет сессию, если токены изменились: после входа, выхода
+// или обновления токена. Если сервер завершил сессию, а ключ ещё в памяти,
+// сообщает, что нужно войти заново.
+func (m *SessionManager) Persist() error {
+	if *m.tokenHolder == m.saved {
+		return nil
+	}
+
+	expired := m.saved.Token != "" && m.tokenHolder.Token == "" && len(m.keyHolder.Key) != 0
+	if err := m.keeper.Save(m.tokenHolder, m.keyHolder); err != nil {
+		return fmt.Errorf("ошибка сохранения сессии: %w", err)
+	}
+	m.saved = *m.tokenHolder
+
+	if expired {
+		_, err := fmt.Fprintln(m.writer, "Сессия истекла, выполните вход командой login.")
+		if err != nil {
+			return fmt.Errorf("ошибка вывода результата: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSessionKeeper struct {
+	mock.Mock
+}
+
+func (m *MockSessionKeeper) Saved() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionKeeper) Restore(masterPassword string) (*entity.AuthTokens, *entity.KDFParams, []byte, error) {
+	args := m.Called(masterPassword)
+	tokens, _ := args.Get(0).(*entity.AuthTokens)
+	kdf, _ := args.Get(1).(*entity.KDFParams)
+	key, _ := args.Get(2).([]byte)
+	return tokens, kdf, key, args.Error(3)
+}
+
+func (m *MockSessionKeeper) Save(tokens *entity.TokenHolder, keys *entity.KeyHolder) error {
+	args := m.Called(*tokens, keys)
+	return args.Error(0)
+}
+
+func TestSessionManager_Restore(t *testing.T) {
+	tokens := &entity.AuthTokens{AccessToken: "access", RefreshToken: "refresh"}
+
+	tests := []struct {
+		name           string
+		input          string
+		setupMock      func(m *MockSessionKeeper)
+		expectedToken  string
+		expectedOutput string
+		expectedErr    string
+	}{
+		{
+			name:  "сессия восстановлена",
+			input: "master\n",
+			setupMock: func(m *MockSessionKeeper) {
+				m.On("Saved").Return(true, nil)
+				m.On("Restore", "master").Return(tokens, testKDF, []byte("key"), nil)
+			},
+			expectedToken:  "access",
+			expectedOutput: "Сессия восстановлена.",
+		},
+		{
+			name: "нет сохранённой сессии",
+			setupMock: func(m *MockSessionKeeper) {
+				m.On("Saved").Return(false, nil)
+			},
+		},
+		{
+			name:  "отказ от восстановления",
+			input: "\n",
+			setupMock: func(m *MockSessionKeeper) {
+				m.On("Saved").Return(true, nil)
+			},
+			expectedOutput: "Найдена сохранённая сессия",
+		},
+		{
+			name:  "сессия истекла",
+			input: "master\n",
+			setupMock: func(m *MockSessionKeeper) {
+				m.On("Saved").Return(true, nil)
+				m.On("Restore", "master").Return(nil, nil, nil, entity.ErrSessionExpired)
+				m.On("Save", entity.TokenHolder{}, mock.Anything).Return(nil)
+			},
+			expectedOutput: "выполните вход командой login",
+		},
+		{
+			name:  "неверный мастер-пароль",
+			input: "wrong\n",
+			setupMock: func(m *MockSessionKeeper) {
+				m.On("Saved").Return(true, nil)
+				m.On("Restore", "wrong").Return(nil, nil, nil, errors.New("неверный мастер-пароль"))
+			},
+			expectedErr: "ошибка восстановления сессии",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keeper := new(MockSessionKeeper)
+			tt.setupMock(keeper)
+			tokenHolder := &entity.TokenHolder{}
+			keyHolder := &entity.KeyHolder{}
+			writer := &bytes.Buffer{}
+
+			manager := NewSessionManager(keeper, tokenHolder, keyHolder, bytes.NewBufferString(tt.input), writer)
+			err := manager.Restore()
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedToken, tokenHolder.Token)
+			assert.Contains(t, writer.String(), tt.expectedOutput)
+			keeper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionManager_Persist(t *testing.T) {
+	keeper := new(MockSessionKeeper)
+	tokenHolder := &entity.TokenHolder{}
+	keyHolder := &entity.KeyHolder{Key: []byte("key"), Params: testKDF}
+	writer := &bytes.Buffer{}
+	manager := NewSessionManager(keeper, tokenHolder, keyHolder, &bytes.Buffer{}, writer)
+
+	// Токены не менялись - файл не трогаем.
+	assert.NoError(t, manager.Persist())
+
+	tokenHolder.Set(&entity.AuthTokens{AccessToken: "access", RefreshToken: "refresh"})
+	keeper.On("Save", *tokenHolder, keyHolder).Return(nil).Once()
+	assert.NoError(t, manager.Persist())
+	assert.NoError(t, manager.Persist())
+
+	// Перехватчик очистил токены: сервер завершил сессию.
+	tokenHolder.Clear()
+	keeper.On("Save", entity.TokenHolder{}, keyHolder).Return(nil).Once()
+	assert.NoError(t, manager.Persist())
+	assert.Contains(t, writer.String(), "Сессия истекла")
+
+	keeper.AssertExpectations(t)
+}
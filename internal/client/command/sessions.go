@@ -0,0 +1,98 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type sessionsService interface {
+	ListSessions(ctx context.Context, token string) ([]*entity.Session, error)
+	RevokeSession(ctx context.Context, token string, id int64) error
+}
+
+type SessionsCommand struct {
+	authService sessionsService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewSessionsCommand(
+	authService sessionsService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *SessionsCommand {
+	return &SessionsCommand{
+		authService: authService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *SessionsCommand) Name() string {
+	return "sessions"
+}
+
+// Execute - выводит активные сессии и предлагает отозвать одну из них.
+func (c *SessionsCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	sessions, err := c.authService.ListSessions(context.Background(), c.tokenHolder.Token)
+	if err != nil {
+		return fmt.Errorf("ошибка получения сессий: %w", err)
+	}
+
+	for _, s := range sessions {
+		current := ""
+		if s.Current {
+			current = "\t(текущая)"
+		}
+		_, err = fmt.Fprintf(c.writer, "%d\t%s\tвход %s\tактивность %s%s\n",
+			s.ID, s.DeviceName, s.CreatedAt.Local().Format(time.DateTime),
+			s.LastUsedAt.Local().Format(time.DateTime), current)
+		if err != nil {
+			return fmt.Errorf("ошибка вывода сессий: %w", err)
+		}
+	}
+
+	_, err = fmt.Fprint(c.writer, "ID сессии для отзыва (пусто - не отзывать): ")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода запроса: %w", err)
+	}
+	scanner := bufio.NewScanner(c.reader)
+	if !scanner.Scan() {
+		return fmt.Errorf("ошибка ввода ID сессии")
+	}
+	idStr := strings.TrimSpace(scanner.Text())
+	if idStr == "" {
+		return nil
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("некорректный ID сессии: %s", idStr)
+	}
+
+	err = c.authService.RevokeSession(context.Background(), c.tokenHolder.Token, id)
+	if err != nil {
+		return fmt.Errorf("ошибка отзыва сессии: %w", err)
+	}
+
+	_, err = fmt.Fprintln(c.writer, "Сессия отозвана.")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSessionsService struct {
+	mock.Mock
+}
+
+func (m *MockSessionsService) ListSessions(ctx context.Context, token string) ([]*entity.Session, error) {
+	args := m.Called(ctx, token)
+	sessions, _ := args.Get(0).([]*entity.Session)
+	return sessions, args.Error(1)
+}
+
+func (m *MockSessionsService) RevokeSession(ctx context.Context, token string, id int64) error {
+	args := m.Called(ctx, token, id)
+	return args.Error(0)
+}
+
+func TestSessionsCommand_Execute(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	sessions := []*entity.Session{
+		{ID: 2, DeviceName: "laptop", CreatedAt: created, LastUsedAt: created, Current: true},
+		{ID: 5, DeviceName: "phone", CreatedAt: created, LastUsedAt: created},
+	}
+	listing := "2\tlaptop\tвход 2024-01-02 03:04:05\tактивность 2024-01-02 03:04:05\t(текущая)\n" +
+		"5\tphone\tвход 2024-01-02 03:04:05\tактивность 2024-01-02 03:04:05\n" +
+		"ID сессии для отзыва (пусто - не отзывать): "
+
+	tests := []struct {
+		name           string
+		input          string
+		mockSetup      func(m *MockSessionsService)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:  "только просмотр",
+			input: "\n",
+			mockSetup: func(m *MockSessionsService) {
+				m.On("ListSessions", mock.Anything, "valid_token").Return(sessions, nil)
+			},
+			expectedOutput: listing,
+		},
+		{
+			name:  "отзыв сессии",
+			input: "5\n",
+			mockSetup: func(m *MockSessionsService) {
+				m.On("ListSessions", mock.Anything, "valid_token").Return(sessions, nil)
+				m.On("RevokeSession", mock.Anything, "valid_token", int64(5)).Return(nil)
+			},
+			expectedOutput: listing + "Сессия отозвана.\n",
+		},
+		{
+			name:  "некорректный ID",
+			input: "abc\n",
+			mockSetup: func(m *MockSessionsService) {
+				m.On("ListSessions", mock.Anything, "valid_token").Return(sessions, nil)
+			},
+			expectedOutput: listing,
+			expectedError:  "некорректный ID сессии: abc",
+		},
+		{
+			name:  "ошибка получения сессий",
+			input: "",
+			mockSetup: func(m *MockSessionsService) {
+				m.On("ListSessions", mock.Anything, "valid_token").Return(nil, errors.New("unavailable"))
+			},
+			expectedError: "ошибка получения сессий: unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSessionsService)
+			tt.mockSetup(mockService)
+			var output bytes.Buffer
+
+			cmd := NewSessionsCommand(
+				mockService, &entity.TokenHolder{Token: "valid_token"}, strings.NewReader(tt.input), &output,
+			)
+			err := cmd.Execute()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedOutput, output.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
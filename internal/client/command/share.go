@@ -0,0 +1,152 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type shareService interface {
+	CreateShare(
+		ctx context.Context, token string, item *datapb.DataItem, ttl time.Duration, maxViews int32,
+	) (string, time.Time, error)
+}
+
+// ShareCommand - одноразовая ссылка на запись для человека без учётной записи.
+type ShareCommand struct {
+	shareService shareService
+	dataService  getDataService
+	tokenHolder  *entity.TokenHolder
+	reader       io.Reader
+	writer       io.Writer
+}
+
+func NewShareCommand(
+	shareService shareService,
+	dataService getDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *ShareCommand {
+	return &ShareCommand{
+		shareService: shareService,
+		dataService:  dataService,
+		tokenHolder:  tokenHolder,
+		reader:       reader,
+		writer:       writer,
+	}
+}
+
+func (c *ShareCommand) Name() string {
+	return "share"
+}
+
+func (c *ShareCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	args, err := promptArgs(prompter, "ID записи", "Срок действия (например 24h, пусто - сутки)",
+		"Число просмотров (пусто - один)")
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(args[:1])
+	if err != nil {
+		return err
+	}
+	ttl, maxViews, err := parseShareLimits(args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	return c.share(id, ttl, maxViews, FormatTable)
+}
+
+func (c *ShareCommand) Usage() string {
+	return "share <id> [--expires 24h] [--views 1] [--output plain|table|json]"
+}
+
+// Run - создаёт ссылку на запись и выводит токен для получателя.
+func (c *ShareCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	expires := flags.String("expires", "", "срок действия ссылки, например 1h или 72h")
+	views := flags.String("views", "", "сколько раз можно открыть ссылку")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional)
+	if err != nil {
+		return err
+	}
+	ttl, maxViews, err := parseShareLimits(*expires, *views)
+	if err != nil {
+		return err
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	return c.share(id, ttl, maxViews, format)
+}
+
+func (c *ShareCommand) share(id int32, ttl time.Duration, maxViews int32, format Format) error {
+	ctx := context.Background()
+
+	item, err := c.dataService.GetData(ctx, c.tokenHolder.Token, id)
+	if err != nil {
+		return fmt.Errorf("ошибка получения данных: %w", err)
+	}
+
+	shareToken, expiresAt, err := c.shareService.CreateShare(ctx, c.tokenHolder.Token, item, ttl, maxViews)
+	if err != nil {
+		return fmt.Errorf("ошибка создания ссылки: %w", err)
+	}
+
+	// В plain выводится только токен, чтобы его можно было сохранить в переменную.
+	if format == FormatPlain {
+		if _, err := fmt.Fprintln(c.writer, shareToken); err != nil {
+			return fmt.Errorf("ошибка вывода: %w", err)
+		}
+		return nil
+	}
+
+	return writeRecord(c.writer, format, record{
+		{"token", shareToken},
+		{"expires", expiresAt.Local().Format(time.DateTime)},
+	})
+}
+
+// parseShareLimits - разбирает срок и число просмотров; пустые значения
+// оставляют выбор серверу.
+func parseShareLimits(expires, views string) (time.Duration, int32, error) {
+	var (
+		ttl      time.Duration
+		maxViews int64
+		err      error
+	)
+	if expires = strings.TrimSpace(expires); expires != "" {
+		ttl, err = time.ParseDuration(expires)
+		if err != nil || ttl <= 0 {
+			return 0, 0, usageErrorf("некорректный срок действия: %s", expires)
+		}
+	}
+	if views = strings.TrimSpace(views); views != "" {
+		maxViews, err = strconv.ParseInt(views, 10, 32)
+		if err != nil || maxViews <= 0 {
+			return 0, 0, usageErrorf("некорректное число просмотров: %s", views)
+		}
+	}
+
+	return ttl, int32(maxViews), nil
+}
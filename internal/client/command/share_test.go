@@ -0,0 +1,113 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockShareService struct {
+	mock.Mock
+}
+
+func (m *MockShareService) CreateShare(
+	ctx context.Context, token string, item *datapb.DataItem, ttl time.Duration, maxViews int32,
+) (string, time.Time, error) {
+	args := m.Called(ctx, token, item, ttl, maxViews)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func TestShareCommand_Run(t *testing.T) {
+	ctx := context.Background()
+	item := &datapb.DataItem{Id: 5, InfoType: "text", Payload: &datapb.DataItem_Text{Text: &datapb.Text{Content: "x"}}}
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(s *MockShareService, d *MockVaultDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "ссылка с ограничениями",
+			args: []string{"5", "--expires", "2h", "--views", "3"},
+			setupMock: func(s *MockShareService, d *MockVaultDataService) {
+				d.On("GetData", ctx, "token", int32(5)).Return(item, nil)
+				s.On("CreateShare", ctx, "token", item, 2*time.Hour, int32(3)).Return("abc.key", expiresAt, nil)
+			},
+			expectedOutput: "abc.key\n",
+		},
+		{
+			name: "значения по умолчанию",
+			args: []string{"5"},
+			setupMock: func(s *MockShareService, d *MockVaultDataService) {
+				d.On("GetData", ctx, "token", int32(5)).Return(item, nil)
+				s.On("CreateShare", ctx, "token", item, time.Duration(0), int32(0)).Return("abc.key", expiresAt, nil)
+			},
+			expectedOutput: "abc.key\n",
+		},
+		{
+			name: "чужая запись",
+			args: []string{"9"},
+			setupMock: func(s *MockShareService, d *MockVaultDataService) {
+				d.On("GetData", ctx, "token", int32(9)).Return(nil, status.Error(codes.NotFound, "данные не найдены"))
+			},
+			expectedCode: ExitNotFound,
+		},
+		{
+			name:         "некорректный срок",
+			args:         []string{"5", "--expires", "завтра"},
+			setupMock:    func(s *MockShareService, d *MockVaultDataService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "ноль просмотров",
+			args:         []string{"5", "--views", "0"},
+			setupMock:    func(s *MockShareService, d *MockVaultDataService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := new(MockShareService)
+			data := new(MockVaultDataService)
+			tt.setupMock(shares, data)
+			writer := &bytes.Buffer{}
+
+			cmd := NewShareCommand(shares, data, &entity.TokenHolder{Token: "token"}, &bytes.Buffer{}, writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			shares.AssertExpectations(t)
+			data.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShareCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	item := &datapb.DataItem{Id: 5, InfoType: "text"}
+	shares := new(MockShareService)
+	data := new(MockVaultDataService)
+	data.On("GetData", ctx, "token", int32(5)).Return(item, nil)
+	shares.On("CreateShare", ctx, "token", item, 24*time.Hour, int32(0)).
+		Return("abc.key", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+	writer := &bytes.Buffer{}
+
+	cmd := NewShareCommand(shares, data, &entity.TokenHolder{Token: "token"}, bytes.NewBufferString("5\n24h\n\n"), writer)
+
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, writer.String(), "abc.key")
+	shares.AssertExpectations(t)
+}
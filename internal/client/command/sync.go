@@ -0,0 +1,60 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type syncService interface {
+	Sync(ctx context.Context, token string) (*entity.SyncResult, error)
+}
+
+// SyncCommand - отправляет изменения, сделанные офлайн, и обновляет локальный кеш.
+type SyncCommand struct {
+	syncService syncService
+	tokenHolder *entity.TokenHolder
+	writer      io.Writer
+}
+
+func NewSyncCommand(syncService syncService, tokenHolder *entity.TokenHolder, writer io.Writer) *SyncCommand {
+	return &SyncCommand{
+		syncService: syncService,
+		tokenHolder: tokenHolder,
+		writer:      writer,
+	}
+}
+
+func (c *SyncCommand) Name() string {
+	return "sync"
+}
+
+func (c *SyncCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	result, err := c.syncService.Sync(context.Background(), c.tokenHolder.Token)
+	if err != nil {
+		return fmt.Errorf("ошибка синхронизации: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.writer, "Синхронизация завершена: отправлено изменений %d, получено изменений %d\n",
+		result.Pushed, result.Pulled)
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	if result.Conflicts > 0 {
+		_, err = fmt.Fprintf(c.writer,
+			"Конфликтов версий: %d. Локальные правки изменённых на сервере записей сохранены копиями.\n",
+			result.Conflicts)
+		if err != nil {
+			return fmt.Errorf("ошибка вывода результата: %w", err)
+		}
+	}
+
+	return nil
+}
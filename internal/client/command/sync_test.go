@@ -0,0 +1,88 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSyncService struct {
+	mock.Mock
+}
+
+func (m *MockSyncService) Sync(ctx context.Context, token string) (*entity.SyncResult, error) {
+	args := m.Called(ctx, token)
+	if result, ok := args.Get(0).(*entity.SyncResult); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestSyncCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		mockSetup      func(m *MockSyncService)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:  "Успешная синхронизация",
+			token: "valid_token",
+			mockSetup: func(m *MockSyncService) {
+				m.On("Sync", mock.Anything, "valid_token").Return(&entity.SyncResult{Pushed: 2, Pulled: 5}, nil)
+			},
+			expectedOutput: "Синхронизация завершена: отправлено изменений 2, получено изменений 5\n",
+		},
+		{
+			name:  "Синхронизация с конфликтами",
+			token: "valid_token",
+			mockSetup: func(m *MockSyncService) {
+				m.On("Sync", mock.Anything, "valid_token").
+					Return(&entity.SyncResult{Pushed: 1, Pulled: 1, Conflicts: 1}, nil)
+			},
+			expectedOutput: "Синхронизация завершена: отправлено изменений 1, получено изменений 1\n" +
+				"Конфликтов версий: 1. Локальные правки изменённых на сервере записей сохранены копиями.\n",
+		},
+		{
+			name:          "Не авторизован",
+			mockSetup:     func(m *MockSyncService) {},
+			expectedError: "вы должны войти в систему",
+		},
+		{
+			name:  "Сервер недоступен",
+			token: "valid_token",
+			mockSetup: func(m *MockSyncService) {
+				m.On("Sync", mock.Anything, "valid_token").Return(nil, errors.New("unavailable"))
+			},
+			expectedError: "ошибка синхронизации: unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSyncService)
+			tt.mockSetup(mockService)
+
+			var output bytes.Buffer
+			cmd := NewSyncCommand(mockService, &entity.TokenHolder{Token: tt.token}, &output)
+
+			err := cmd.Execute()
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, output.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/label"
+)
+
+// labelDataService - чтение и изменение записи для команд tag и mv.
+type labelDataService interface {
+	GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error)
+	UpdateData(ctx context.Context, token string, data *datapb.DataItem) error
+}
+
+type TagCommand struct {
+	dataService labelDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewTagCommand(
+	dataService labelDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *TagCommand {
+	return &TagCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *TagCommand) Name() string {
+	return "tag"
+}
+
+// Execute - спрашивает ID, новые теги через запятую ("-" - убрать все)
+// и избранное. Пустой ввод оставляет текущее значение.
+func (c *TagCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	_, err := fmt.Fprint(c.writer, "Введите ID данных: ")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода запроса на ввод ID: %w", err)
+	}
+	scanner := bufio.NewScanner(c.reader)
+	if !scanner.Scan() {
+		return fmt.Errorf("ошибка ввода ID")
+	}
+	id64, err := strconv.ParseInt(scanner.Text(), 10, 32)
+	if err != nil {
+		return fmt.Errorf("некорректный ID: %w", err)
+	}
+
+	dataItem, err := c.dataService.GetData(context.Background(), c.tokenHolder.Token, int32(id64))
+	if err != nil {
+		return fmt.Errorf("ошибка получения данных: %w", err)
+	}
+
+	prompter := &payloadPrompter{scanner: scanner, writer: c.writer}
+	tags, err := prompter.field("Теги через запятую, - убрать все", strings.Join(dataItem.Tags, ","))
+	if err != nil {
+		return err
+	}
+	if tags == "-" {
+		tags = ""
+	}
+	if dataItem.Tags, err = parseTags(tags); err != nil {
+		return err
+	}
+
+	favorite, err := prompter.field("В избранном (y/n)", yesNo(dataItem.Favorite))
+	if err != nil {
+		return err
+	}
+	dataItem.Favorite = strings.EqualFold(favorite, "y")
+
+	if err := c.dataService.UpdateData(context.Background(), c.tokenHolder.Token, dataItem); err != nil {
+		return fmt.Errorf("ошибка обновления данных: %w", err)
+	}
+
+	_, err = fmt.Fprintln(c.writer, "Теги обновлены.")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+func (c *TagCommand) Usage() string {
+	return "tag <id> [тег ...] [--remove a,b] [--clear] [--favorite=true|false] [--output plain|table|json]"
+}
+
+// Run - без изменений выводит теги записи. Теги из аргументов добавляются,
+// из --remove - убираются; --clear убирает все теги до добавления новых.
+func (c *TagCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	remove := flags.String("remove", "", "убрать теги, через запятую")
+	clearAll := flags.Bool("clear", false, "убрать все теги")
+	favorite := flags.Bool("favorite", false, "добавить в избранное или убрать из него")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return usageErrorf("ожидается ID записи")
+	}
+	id, err := parseID(positional[:1])
+	if err != nil {
+		return err
+	}
+	added, err := label.Tags(positional[1:])
+	if err != nil {
+		return &UsageError{Err: err}
+	}
+	removed, err := parseTags(*remove)
+	if err != nil {
+		return err
+	}
+	favoriteSet := isFlagSet(flags, "favorite")
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	dataItem, err := c.dataService.GetData(context.Background(), c.tokenHolder.Token, id)
+	if err != nil {
+		return fmt.Errorf("ошибка получения данных: %w", err)
+	}
+
+	if len(added) > 0 || len(removed) > 0 || *clearAll || favoriteSet {
+		tags := dataItem.Tags
+		if *clearAll {
+			tags = nil
+		}
+		tags = append(tags, added...)
+		kept := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if !slices.Contains(removed, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		if dataItem.Tags, err = label.Tags(kept); err != nil {
+			return &UsageError{Err: err}
+		}
+		if favoriteSet {
+			dataItem.Favorite = *favorite
+		}
+
+		if err := c.dataService.UpdateData(context.Background(), c.tokenHolder.Token, dataItem); err != nil {
+			return fmt.Errorf("ошибка обновления данных: %w", err)
+		}
+	}
+
+	return writeRecord(c.writer, format, record{
+		{"id", dataItem.Id},
+		{"tags", strings.Join(dataItem.Tags, ",")},
+		{"favorite", dataItem.Favorite},
+	})
+}
+
+// parseTags - разбирает теги через запятую, как в --tags и --remove.
+func parseTags(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	tags, err := label.Tags(strings.Split(value, ","))
+	if err != nil {
+		return nil, &UsageError{Err: err}
+	}
+	return tags, nil
+}
+
+// parseFolder - приводит путь папки к виду, в котором его хранит сервер.
+func parseFolder(value string) (string, error) {
+	folder, err := label.Folder(value)
+	if err != nil {
+		return "", &UsageError{Err: err}
+	}
+	return folder, nil
+}
+
+// isFlagSet - задан ли флаг явно: отличает --favorite=false от отсутствия флага.
+func isFlagSet(flags *cliFlags, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "y"
+	}
+	return "n"
+}
@@ -0,0 +1,160 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTagCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		mockSetup      func(m *MockUpdateDataService)
+		expectedOutput string
+		expectedError  bool
+	}{
+		{
+			name:  "замена тегов и избранное",
+			input: "3\nprod, db\ny\n",
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(3)).
+					Return(&datapb.DataItem{Id: 3, Version: 2, Tags: []string{"old"}}, nil)
+				m.On("UpdateData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					return strings.Join(item.Tags, ",") == "db,prod" && item.Favorite && item.Version == 2
+				})).Return(nil)
+			},
+			expectedOutput: "Введите ID данных: Теги через запятую, - убрать все (old): " +
+				"В избранном (y/n) (n): Теги обновлены.\n",
+		},
+		{
+			name:  "пустой ввод сохраняет значения",
+			input: "3\n\n\n",
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(3)).
+					Return(&datapb.DataItem{Id: 3, Tags: []string{"db"}, Favorite: true}, nil)
+				m.On("UpdateData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					return strings.Join(item.Tags, ",") == "db" && item.Favorite
+				})).Return(nil)
+			},
+			expectedOutput: "Введите ID данных: Теги через запятую, - убрать все (db): " +
+				"В избранном (y/n) (y): Теги обновлены.\n",
+		},
+		{
+			name:  "ошибка получения записи",
+			input: "3\n",
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(3)).Return(nil, errors.New("not found"))
+			},
+			expectedOutput: "Введите ID данных: ",
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockUpdateDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewTagCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(tt.input), writer)
+			err := cmd.Execute()
+
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagCommand_Run(t *testing.T) {
+	current := func() *datapb.DataItem {
+		return &datapb.DataItem{Id: 5, Version: 1, Tags: []string{"db", "old"}}
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		mockSetup      func(m *MockUpdateDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "показать теги",
+			args: []string{"5"},
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(5)).Return(current(), nil)
+			},
+			expectedOutput: "id\t5\ntags\tdb,old\nfavorite\tfalse\n",
+		},
+		{
+			name: "добавить и убрать",
+			args: []string{"5", "prod", "--remove", "old", "--favorite"},
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(5)).Return(current(), nil)
+				m.On("UpdateData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					return strings.Join(item.Tags, ",") == "db,prod" && item.Favorite
+				})).Return(nil)
+			},
+			expectedOutput: "id\t5\ntags\tdb,prod\nfavorite\ttrue\n",
+		},
+		{
+			name: "убрать все",
+			args: []string{"5", "--clear", "--json"},
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(5)).Return(current(), nil)
+				m.On("UpdateData", mock.Anything, "token", mock.MatchedBy(func(item *datapb.DataItem) bool {
+					return len(item.Tags) == 0
+				})).Return(nil)
+			},
+			expectedOutput: "{\n  \"favorite\": false,\n  \"id\": 5,\n  \"tags\": \"\"\n}\n",
+		},
+		{
+			name: "конфликт версий",
+			args: []string{"5", "prod"},
+			mockSetup: func(m *MockUpdateDataService) {
+				m.On("GetData", mock.Anything, "token", int32(5)).Return(current(), nil)
+				m.On("UpdateData", mock.Anything, "token", mock.Anything).
+					Return(&entity.VersionConflictError{ID: 5, CurrentVersion: 2})
+			},
+			expectedCode: ExitConflict,
+		},
+		{
+			name:         "без ID",
+			args:         []string{},
+			mockSetup:    func(m *MockUpdateDataService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "слишком длинный тег",
+			args:         []string{"5", strings.Repeat("x", 65)},
+			mockSetup:    func(m *MockUpdateDataService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockUpdateDataService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewTagCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type trashDataService interface {
+	ListTrash(ctx context.Context, token string, vaultID int32) (*datapb.ListTrashResponse, error)
+	RestoreData(ctx context.Context, token string, id int32) (int64, error)
+	PurgeData(ctx context.Context, token string, id int32) error
+}
+
+var trashColumns = []string{"id", "type", "deleted", "meta"}
+
+// TrashCommand - корзина: удалённые записи, их восстановление и окончательное
+// удаление до истечения срока хранения.
+type TrashCommand struct {
+	dataService trashDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewTrashCommand(
+	dataService trashDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *TrashCommand {
+	return &TrashCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *TrashCommand) Name() string {
+	return "trash"
+}
+
+func (c *TrashCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+
+	action, err := prompter.field("Действие (list/restore/purge)", "")
+	if err != nil {
+		return err
+	}
+
+	action = strings.TrimSpace(action)
+	if action == "list" {
+		return c.list(0, FormatTable)
+	}
+	if action != "restore" && action != "purge" {
+		return fmt.Errorf("неизвестное действие: %s", action)
+	}
+
+	args, err := promptArgs(prompter, "ID записи")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Запись %d удалена окончательно.", id)
+	if action == "restore" {
+		version, err := c.restore(id)
+		if err != nil {
+			return err
+		}
+		message = fmt.Sprintf("Запись %d восстановлена, текущая версия записи: %d.", id, version)
+	} else if err := c.purge(id); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(c.writer, message); err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+func (c *TrashCommand) Usage() string {
+	return "trash [--vault id] | trash restore <id> | trash purge <id> [--output plain|table|json]"
+}
+
+// Run - без действия выводит корзину личных данных или хранилища --vault,
+// restore возвращает запись из корзины, purge удаляет её окончательно.
+func (c *TrashCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	vaultID := flags.Int("vault", 0, "ID общего хранилища, 0 - личные данные")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 && *vaultID != 0 {
+		return usageErrorf("--vault используется только для просмотра корзины")
+	}
+	if len(positional) == 0 {
+		if c.tokenHolder.Token == "" {
+			return ErrNotLoggedIn
+		}
+		return c.list(int32(*vaultID), format)
+	}
+
+	action := positional[0]
+	if action != "restore" && action != "purge" {
+		return usageErrorf("неизвестное действие: %s", action)
+	}
+	id, err := parseID(positional[1:])
+	if err != nil {
+		return err
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	if action == "purge" {
+		if err := c.purge(id); err != nil {
+			return err
+		}
+		return writeID(c.writer, format, id)
+	}
+
+	version, err := c.restore(id)
+	if err != nil {
+		return err
+	}
+
+	return writeRecord(c.writer, format, record{{"id", id}, {"version", version}})
+}
+
+func (c *TrashCommand) restore(id int32) (int64, error) {
+	version, err := c.dataService.RestoreData(context.Background(), c.tokenHolder.Token, id)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка восстановления из корзины: %w", err)
+	}
+
+	return version, nil
+}
+
+func (c *TrashCommand) purge(id int32) error {
+	if err := c.dataService.PurgeData(context.Background(), c.tokenHolder.Token, id); err != nil {
+		return fmt.Errorf("ошибка удаления из корзины: %w", err)
+	}
+
+	return nil
+}
+
+func (c *TrashCommand) list(vaultID int32, format Format) error {
+	res, err := c.dataService.ListTrash(context.Background(), c.tokenHolder.Token, vaultID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения корзины: %w", err)
+	}
+
+	rows := make([]record, 0, len(res.Items))
+	for _, item := range res.Items {
+		rows = append(rows, record{
+			{"id", item.GetId()},
+			{"type", item.GetInfoType()},
+			{"deleted", item.GetDeletedAt().AsTime().Format(time.DateTime)},
+			{"meta", item.GetMeta()},
+		})
+	}
+
+	return writeRecords(c.writer, format, trashColumns, rows)
+}
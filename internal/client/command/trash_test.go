@@ -0,0 +1,133 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockTrashService struct {
+	mock.Mock
+}
+
+func (m *MockTrashService) ListTrash(ctx context.Context, token string, vaultID int32) (*datapb.ListTrashResponse, error) {
+	args := m.Called(ctx, token, vaultID)
+	res, _ := args.Get(0).(*datapb.ListTrashResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockTrashService) RestoreData(ctx context.Context, token string, id int32) (int64, error) {
+	args := m.Called(ctx, token, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTrashService) PurgeData(ctx context.Context, token string, id int32) error {
+	args := m.Called(ctx, token, id)
+	return args.Error(0)
+}
+
+func TestTrashCommand_Run(t *testing.T) {
+	ctx := context.Background()
+	deleted := timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	tests := []struct {
+		name           string
+		args           []string
+		mockSetup      func(m *MockTrashService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "корзина хранилища",
+			args: []string{"--vault", "7"},
+			mockSetup: func(m *MockTrashService) {
+				m.On("ListTrash", ctx, "token", int32(7)).Return(&datapb.ListTrashResponse{
+					Items: []*datapb.DataHeader{{Id: 5, InfoType: "text", Meta: "заметка", DeletedAt: deleted}},
+				}, nil)
+			},
+			expectedOutput: "5\ttext\t2024-01-02 03:04:05\tзаметка\n",
+		},
+		{
+			name: "запись восстановлена",
+			args: []string{"restore", "5"},
+			mockSetup: func(m *MockTrashService) {
+				m.On("RestoreData", ctx, "token", int32(5)).Return(int64(3), nil)
+			},
+			expectedOutput: "id\t5\nversion\t3\n",
+		},
+		{
+			name: "запись удалена окончательно",
+			args: []string{"purge", "5"},
+			mockSetup: func(m *MockTrashService) {
+				m.On("PurgeData", ctx, "token", int32(5)).Return(nil)
+			},
+			expectedOutput: "5\n",
+		},
+		{
+			name: "записи нет в корзине",
+			args: []string{"restore", "5"},
+			mockSetup: func(m *MockTrashService) {
+				m.On("RestoreData", ctx, "token", int32(5)).Return(int64(0), errors.New("данные не найдены"))
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "без ID",
+			args:         []string{"purge"},
+			mockSetup:    func(m *MockTrashService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "неизвестное действие",
+			args:         []string{"clear"},
+			mockSetup:    func(m *MockTrashService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name:         "хранилище при восстановлении",
+			args:         []string{"restore", "5", "--vault", "7"},
+			mockSetup:    func(m *MockTrashService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockTrashService)
+			tt.mockSetup(service)
+			writer := &bytes.Buffer{}
+
+			err := NewTrashCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader(""), writer).
+				Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrashCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	service := new(MockTrashService)
+	service.On("RestoreData", ctx, "token", int32(5)).Return(int64(3), nil)
+	writer := &bytes.Buffer{}
+
+	err := NewTrashCommand(service, &entity.TokenHolder{Token: "token"}, strings.NewReader("restore\n5\n"), writer).
+		Execute()
+
+	require.NoError(t, err)
+	assert.Equal(t, "Действие (list/restore/purge): ID записи: Запись 5 восстановлена, текущая версия записи: 3.\n",
+		writer.String())
+	service.AssertExpectations(t)
+}
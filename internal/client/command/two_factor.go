@@ -0,0 +1,127 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type twoFactorService interface {
+	EnrollTOTP(ctx context.Context, token string) (*entity.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, token, code string) error
+	DisableTOTP(ctx context.Context, token, code string) error
+}
+
+type TwoFactorCommand struct {
+	authService twoFactorService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewTwoFactorCommand(
+	authService twoFactorService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *TwoFactorCommand {
+	return &TwoFactorCommand{
+		authService: authService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *TwoFactorCommand) Name() string {
+	return "2fa"
+}
+
+// Execute - включает или выключает двухфакторную аутентификацию.
+func (c *TwoFactorCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	scanner := bufio.NewScanner(c.reader)
+	action, err := c.prompt(scanner, "Действие (enable/disable): ")
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "enable":
+		return c.enable(scanner)
+	case "disable":
+		return c.disable(scanner)
+	default:
+		return fmt.Errorf("неизвестное действие: %s", action)
+	}
+}
+
+func (c *TwoFactorCommand) enable(scanner *bufio.Scanner) error {
+	enrollment, err := c.authService.EnrollTOTP(context.Background(), c.tokenHolder.Token)
+	if err != nil {
+		return fmt.Errorf("ошибка подключения аутентификатора: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.writer,
+		"Добавьте ключ в приложение-аутентификатор:\n%s\nСекрет: %s\n"+
+			"Коды восстановления (сохраните, повторно они не показываются):\n%s\n",
+		enrollment.URI, enrollment.Secret, strings.Join(enrollment.RecoveryCodes, "\n"))
+	if err != nil {
+		return fmt.Errorf("ошибка вывода данных аутентификатора: %w", err)
+	}
+
+	code, err := c.prompt(scanner, "Введите код из приложения: ")
+	if err != nil {
+		return err
+	}
+
+	err = c.authService.ConfirmTOTP(context.Background(), c.tokenHolder.Token, code)
+	if err != nil {
+		return fmt.Errorf("ошибка включения 2FA: %w", err)
+	}
+
+	_, err = fmt.Fprintln(c.writer, "Двухфакторная аутентификация включена.")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+func (c *TwoFactorCommand) disable(scanner *bufio.Scanner) error {
+	code, err := c.prompt(scanner, "Введите код 2FA или код восстановления: ")
+	if err != nil {
+		return err
+	}
+
+	err = c.authService.DisableTOTP(context.Background(), c.tokenHolder.Token, code)
+	if err != nil {
+		return fmt.Errorf("ошибка выключения 2FA: %w", err)
+	}
+
+	_, err = fmt.Fprintln(c.writer, "Двухфакторная аутентификация выключена.")
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+func (c *TwoFactorCommand) prompt(scanner *bufio.Scanner, message string) (string, error) {
+	_, err := fmt.Fprint(c.writer, message)
+	if err != nil {
+		return "", fmt.Errorf("ошибка вывода запроса: %w", err)
+	}
+	if !scanner.Scan() {
+		return "", fmt.Errorf("ошибка ввода: %w", scanner.Err())
+	}
+
+	return strings.TrimSpace(scanner.Text()), nil
+}
@@ -0,0 +1,115 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTwoFactorService struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorService) EnrollTOTP(ctx context.Context, token string) (*entity.TOTPEnrollment, error) {
+	args := m.Called(ctx, token)
+	enrollment, _ := args.Get(0).(*entity.TOTPEnrollment)
+	return enrollment, args.Error(1)
+}
+
+func (m *MockTwoFactorService) ConfirmTOTP(ctx context.Context, token, code string) error {
+	args := m.Called(ctx, token, code)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorService) DisableTOTP(ctx context.Context, token, code string) error {
+	args := m.Called(ctx, token, code)
+	return args.Error(0)
+}
+
+func TestTwoFactorCommand_Execute(t *testing.T) {
+	enrollment := &entity.TOTPEnrollment{
+		URI:           "otpauth://totp/GophKeeper:user?secret=ABC",
+		Secret:        "ABC",
+		RecoveryCodes: []string{"aaaa-bbbb", "cccc-dddd"},
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		input          string
+		setupMock      func(m *MockTwoFactorService)
+		expectedOutput []string
+		expectedErr    string
+	}{
+		{
+			name:  "включение",
+			token: "token",
+			input: "enable\n123456\n",
+			setupMock: func(m *MockTwoFactorService) {
+				m.On("EnrollTOTP", mock.Anything, "token").Return(enrollment, nil)
+				m.On("ConfirmTOTP", mock.Anything, "token", "123456").Return(nil)
+			},
+			expectedOutput: []string{enrollment.URI, "Секрет: ABC", "aaaa-bbbb\ncccc-dddd", "включена"},
+		},
+		{
+			name:  "неверный код подтверждения",
+			token: "token",
+			input: "enable\n000000\n",
+			setupMock: func(m *MockTwoFactorService) {
+				m.On("EnrollTOTP", mock.Anything, "token").Return(enrollment, nil)
+				m.On("ConfirmTOTP", mock.Anything, "token", "000000").Return(errors.New("неверный код подтверждения"))
+			},
+			expectedErr: "ошибка включения 2FA",
+		},
+		{
+			name:  "выключение кодом восстановления",
+			token: "token",
+			input: "disable\naaaa-bbbb\n",
+			setupMock: func(m *MockTwoFactorService) {
+				m.On("DisableTOTP", mock.Anything, "token", "aaaa-bbbb").Return(nil)
+			},
+			expectedOutput: []string{"выключена"},
+		},
+		{
+			name:        "неизвестное действие",
+			token:       "token",
+			input:       "toggle\n",
+			setupMock:   func(m *MockTwoFactorService) {},
+			expectedErr: "неизвестное действие",
+		},
+		{
+			name:        "без входа",
+			input:       "enable\n",
+			setupMock:   func(m *MockTwoFactorService) {},
+			expectedErr: "вы должны войти в систему",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockTwoFactorService)
+			tt.setupMock(service)
+			writer := &bytes.Buffer{}
+
+			cmd := NewTwoFactorCommand(
+				service, &entity.TokenHolder{Token: tt.token}, bytes.NewBufferString(tt.input), writer,
+			)
+			err := cmd.Execute()
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, out := range tt.expectedOutput {
+				assert.Contains(t, writer.String(), out)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -80,16 +81,25 @@ func (c *UpdateCommand) Execute() error {
 		infoType = dataItem.InfoType
 	}
 
-	_, err = fmt.Fprintf(c.writer, "Текущие данные (%s): ", dataItem.Info)
-	if err != nil {
-		return fmt.Errorf("ошибка вывода текущих данных: %w", err)
+	updatedData := &datapb.DataItem{
+		Id:       id,
+		InfoType: infoType,
+		Created:  dataItem.Created,
+		Version:  dataItem.Version,
+		VaultId:  dataItem.VaultId,
+		Tags:     dataItem.Tags,
+		Folder:   dataItem.Folder,
+		Favorite: dataItem.Favorite,
 	}
-	if !scanner.Scan() {
-		return fmt.Errorf("ошибка ввода текущих данных")
+
+	// При смене типа старые значения полей не подходят новому payload.
+	current := dataItem
+	if infoType != dataItem.InfoType {
+		current = nil
 	}
-	info := scanner.Text()
-	if info == "" {
-		info = dataItem.Info
+	prompter := &payloadPrompter{scanner: scanner, writer: c.writer}
+	if err := prompter.fill(updatedData, current); err != nil {
+		return err
 	}
 
 	_, err = fmt.Fprintf(c.writer, "Текущая мета (%s): ", dataItem.Meta)
@@ -104,15 +114,21 @@ func (c *UpdateCommand) Execute() error {
 		meta = dataItem.Meta
 	}
 
-	updatedData := &datapb.DataItem{
-		Id:       id,
-		InfoType: infoType,
-		Info:     info,
-		Meta:     meta,
-		Created:  dataItem.Created,
-	}
+	updatedData.Meta = meta
 
 	err = c.dataService.UpdateData(context.Background(), c.tokenHolder.Token, updatedData)
+	var conflict *entity.VersionConflictError
+	if errors.As(err, &conflict) {
+		var overwrite bool
+		overwrite, err = c.resolveConflict(scanner, updatedData)
+		if err == nil && !overwrite {
+			_, err = fmt.Fprintln(c.writer, "Изменения отменены, сохранена версия сервера.")
+			if err != nil {
+				return fmt.Errorf("ошибка вывода результата: %w", err)
+			}
+			return nil
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("ошибка обновления данных: %w", err)
 	}
@@ -124,3 +140,36 @@ func (c *UpdateCommand) Execute() error {
 
 	return nil
 }
+
+// resolveConflict - показывает пользователю актуальную версию записи с сервера
+// и спрашивает, перезаписать ли её своими изменениями. При перезаписи
+// изменения отправляются повторно с актуальной версией.
+func (c *UpdateCommand) resolveConflict(scanner *bufio.Scanner, updatedData *datapb.DataItem) (bool, error) {
+	fresh, err := c.dataService.GetData(context.Background(), c.tokenHolder.Token, updatedData.Id)
+	if err != nil {
+		return false, fmt.Errorf("ошибка получения актуальной версии: %w", err)
+	}
+
+	_, err = fmt.Fprintln(c.writer, "\nЗапись изменена на другом устройстве. Версия сервера:")
+	if err != nil {
+		return false, fmt.Errorf("ошибка вывода конфликта: %w", err)
+	}
+	printPayload(c.writer, fresh)
+	_, err = fmt.Fprintf(c.writer, "Мета: %s\n1 - перезаписать своими изменениями, 2 - оставить версию сервера: ", fresh.Meta)
+	if err != nil {
+		return false, fmt.Errorf("ошибка вывода конфликта: %w", err)
+	}
+	if !scanner.Scan() {
+		return false, fmt.Errorf("ошибка ввода выбора")
+	}
+
+	switch scanner.Text() {
+	case "1":
+		updatedData.Version = fresh.Version
+		return true, c.dataService.UpdateData(context.Background(), c.tokenHolder.Token, updatedData)
+	case "2":
+		return false, nil
+	default:
+		return false, fmt.Errorf("некорректный выбор: %s", scanner.Text())
+	}
+}
@@ -57,29 +57,62 @@ func TestUpdateCommand_Execute(t *testing.T) {
 		{
 			name:  "Успешное обновление всех полей",
 			token: "valid_token",
-			input: "1\nnew_type\nnew_info\nnew_meta\n",
+			input: "1\ntext\nnew_info\nnew_meta\n",
 			mockSetup: func(m *MockUpdateDataService) {
 				originalData := &datapb.DataItem{
 					Id:       1,
 					InfoType: "login_password",
-					Info:     "user123",
 					Meta:     "meta_info",
 					Created:  fixedTimestamp,
+					Payload: &datapb.DataItem_LoginPassword{
+						LoginPassword: &datapb.LoginPassword{Login: "user123", Password: "pass"},
+					},
 				}
 				m.On("GetData", mock.Anything, "valid_token", int32(1)).Return(originalData, nil)
 
 				updatedData := &datapb.DataItem{
 					Id:       1,
-					InfoType: "new_type",
-					Info:     "new_info",
+					InfoType: "text",
 					Meta:     "new_meta",
 					Created:  fixedTimestamp,
+					Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "new_info"}},
 				}
 				m.On("UpdateData", mock.Anything, "valid_token", updatedData).Return(nil)
 			},
-			expectedOutput: "Введите ID данных: Текущий тип (login_password): Текущие данные (user123): Текущая мета (meta_info): Данные успешно обновлены.\n",
+			expectedOutput: "Введите ID данных: Текущий тип (login_password): Текст: Текущая мета (meta_info): Данные успешно обновлены.\n",
 			expectedError:  nil,
 		},
+		{
+			name:  "Обновление отдельных полей записи",
+			token: "valid_token",
+			input: "5\n\n\nnewpass\n\n\n\n",
+			mockSetup: func(m *MockUpdateDataService) {
+				originalData := &datapb.DataItem{
+					Id:       5,
+					InfoType: "login_password",
+					Meta:     "meta_info",
+					Created:  fixedTimestamp,
+					Payload: &datapb.DataItem_LoginPassword{
+						LoginPassword: &datapb.LoginPassword{Login: "user123", Password: "pass", Url: "https://a.b"},
+					},
+				}
+				m.On("GetData", mock.Anything, "valid_token", int32(5)).Return(originalData, nil)
+
+				updatedData := &datapb.DataItem{
+					Id:       5,
+					InfoType: "login_password",
+					Meta:     "meta_info",
+					Created:  fixedTimestamp,
+					Payload: &datapb.DataItem_LoginPassword{
+						LoginPassword: &datapb.LoginPassword{Login: "user123", Password: "newpass", Url: "https://a.b"},
+					},
+				}
+				m.On("UpdateData", mock.Anything, "valid_token", updatedData).Return(nil)
+			},
+			expectedOutput: "Введите ID данных: Текущий тип (login_password): Логин (user123): Пароль (pass): " +
+				"URL (https://a.b): TOTP-секрет или ссылка otpauth://: Текущая мета (meta_info): Данные успешно обновлены.\n",
+			expectedError: nil,
+		},
 		{
 			name:           "Без токена",
 			token:          "",
@@ -125,11 +158,11 @@ func TestUpdateCommand_Execute(t *testing.T) {
 		{
 			name:  "Ошибка при обновлении данных",
 			token: "valid_token",
-			input: "3\nnew_type\nnew_info\nnew_meta\n",
+			input: "3\ntext\nnew_info\nnew_meta\n",
 			mockSetup: func(m *MockUpdateDataService) {
 				originalData := &datapb.DataItem{
 					Id:       3,
-					InfoType: "original_type",
+					InfoType: "text",
 					Info:     "original_info",
 					Meta:     "original_meta",
 					Created:  fixedTimestamp,
@@ -138,16 +171,67 @@ func TestUpdateCommand_Execute(t *testing.T) {
 
 				updatedData := &datapb.DataItem{
 					Id:       3,
-					InfoType: "new_type",
-					Info:     "new_info",
+					InfoType: "text",
 					Meta:     "new_meta",
 					Created:  fixedTimestamp,
+					Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "new_info"}},
 				}
 				m.On("UpdateData", mock.Anything, "valid_token", updatedData).Return(fmt.Errorf("update error"))
 			},
-			expectedOutput: "Введите ID данных: Текущий тип (original_type): Текущие данные (original_info): Текущая мета (original_meta): ",
+			expectedOutput: "Введите ID данных: Текущий тип (text): Текст (original_info): Текущая мета (original_meta): ",
 			expectedError:  errors.New("ошибка обновления данных: update error"),
 		},
+		{
+			name:  "Конфликт версий: перезапись своими изменениями",
+			token: "valid_token",
+			input: "6\n\nmine\n\n1\n",
+			mockSetup: func(m *MockUpdateDataService) {
+				originalData := &datapb.DataItem{
+					Id: 6, InfoType: "text", Info: "v1", Meta: "m", Created: fixedTimestamp, Version: 1,
+				}
+				freshData := &datapb.DataItem{
+					Id: 6, InfoType: "text", Meta: "m", Created: fixedTimestamp, Version: 2,
+					Payload: &datapb.DataItem_Text{Text: &datapb.Text{Content: "theirs"}},
+				}
+				m.On("GetData", mock.Anything, "valid_token", int32(6)).Return(originalData, nil).Once()
+				m.On("GetData", mock.Anything, "valid_token", int32(6)).Return(freshData, nil).Once()
+
+				m.On("UpdateData", mock.Anything, "valid_token", mock.MatchedBy(func(d *datapb.DataItem) bool {
+					return d.Version == 1
+				})).Return(&entity.VersionConflictError{ID: 6, CurrentVersion: 2}).Once()
+				m.On("UpdateData", mock.Anything, "valid_token", &datapb.DataItem{
+					Id: 6, InfoType: "text", Meta: "m", Created: fixedTimestamp, Version: 2,
+					Payload: &datapb.DataItem_Text{Text: &datapb.Text{Content: "mine"}},
+				}).Return(nil).Once()
+			},
+			expectedOutput: "Введите ID данных: Текущий тип (text): Текст (v1): Текущая мета (m): \n" +
+				"Запись изменена на другом устройстве. Версия сервера:\nТекст: theirs\nМета: m\n" +
+				"1 - перезаписать своими изменениями, 2 - оставить версию сервера: Данные успешно обновлены.\n",
+			expectedError: nil,
+		},
+		{
+			name:  "Конфликт версий: сохранение версии сервера",
+			token: "valid_token",
+			input: "7\n\nmine\n\n2\n",
+			mockSetup: func(m *MockUpdateDataService) {
+				originalData := &datapb.DataItem{
+					Id: 7, InfoType: "text", Info: "v1", Meta: "m", Created: fixedTimestamp, Version: 1,
+				}
+				freshData := &datapb.DataItem{
+					Id: 7, InfoType: "text", Meta: "m", Created: fixedTimestamp, Version: 2,
+					Payload: &datapb.DataItem_Text{Text: &datapb.Text{Content: "theirs"}},
+				}
+				m.On("GetData", mock.Anything, "valid_token", int32(7)).Return(originalData, nil).Once()
+				m.On("GetData", mock.Anything, "valid_token", int32(7)).Return(freshData, nil).Once()
+				m.On("UpdateData", mock.Anything, "valid_token", mock.Anything).
+					Return(&entity.VersionConflictError{ID: 7, CurrentVersion: 2}).Once()
+			},
+			expectedOutput: "Введите ID данных: Текущий тип (text): Текст (v1): Текущая мета (m): \n" +
+				"Запись изменена на другом устройстве. Версия сервера:\nТекст: theirs\nМета: m\n" +
+				"1 - перезаписать своими изменениями, 2 - оставить версию сервера: " +
+				"Изменения отменены, сохранена версия сервера.\n",
+			expectedError: nil,
+		},
 		{
 			name:  "Пустые поля сохраняют исходные значения",
 			token: "valid_token",
@@ -155,7 +239,7 @@ func TestUpdateCommand_Execute(t *testing.T) {
 			mockSetup: func(m *MockUpdateDataService) {
 				originalData := &datapb.DataItem{
 					Id:       4,
-					InfoType: "original_type",
+					InfoType: "text",
 					Info:     "original_info",
 					Meta:     "original_meta",
 					Created:  fixedTimestamp,
@@ -164,14 +248,14 @@ func TestUpdateCommand_Execute(t *testing.T) {
 
 				updatedData := &datapb.DataItem{
 					Id:       4,
-					InfoType: "original_type",
-					Info:     "original_info",
+					InfoType: "text",
 					Meta:     "original_meta",
 					Created:  fixedTimestamp,
+					Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "original_info"}},
 				}
 				m.On("UpdateData", mock.Anything, "valid_token", updatedData).Return(nil)
 			},
-			expectedOutput: "Введите ID данных: Текущий тип (original_type): Текущие данные (original_info): Текущая мета (original_meta): Данные успешно обновлены.\n",
+			expectedOutput: "Введите ID данных: Текущий тип (text): Текст (original_info): Текущая мета (original_meta): Данные успешно обновлены.\n",
 			expectedError:  nil,
 		},
 	}
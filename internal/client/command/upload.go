@@ -0,0 +1,87 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+)
+
+type uploadDataService interface {
+	UploadBinary(ctx context.Context, token string, item *datapb.DataItem, src io.ReadSeeker) (int32, error)
+}
+
+// UploadCommand - загружает файл стримом, не читая его в память целиком.
+type UploadCommand struct {
+	dataService uploadDataService
+	tokenHolder *entity.TokenHolder
+	reader      io.Reader
+	writer      io.Writer
+}
+
+func NewUploadCommand(
+	dataService uploadDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *UploadCommand {
+	return &UploadCommand{
+		dataService: dataService,
+		tokenHolder: tokenHolder,
+		reader:      reader,
+		writer:      writer,
+	}
+}
+
+func (c *UploadCommand) Name() string {
+	return "upload"
+}
+
+func (c *UploadCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	prompter := &payloadPrompter{scanner: bufio.NewScanner(c.reader), writer: c.writer}
+	path, err := prompter.field("Путь к файлу", "")
+	if err != nil {
+		return err
+	}
+	meta, err := prompter.field("Метаинформация", "")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+
+	item := &datapb.DataItem{
+		InfoType: payload.TypeBinary,
+		Meta:     meta,
+		Payload: &datapb.DataItem_Binary{Binary: &datapb.Binary{
+			Filename: filepath.Base(path),
+			Mime:     mimeByPath(path),
+		}},
+	}
+
+	id, err := c.dataService.UploadBinary(context.Background(), c.tokenHolder.Token, item, file)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки файла: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.writer, "Файл успешно загружен с ID: %d\n", id)
+	if err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
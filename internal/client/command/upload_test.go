@@ -0,0 +1,111 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUploadDataService struct {
+	mock.Mock
+}
+
+func (m *MockUploadDataService) UploadBinary(
+	ctx context.Context, token string, item *datapb.DataItem, src io.ReadSeeker,
+) (int32, error) {
+	args := m.Called(ctx, token, item, src)
+	return args.Get(0).(int32), args.Error(1)
+}
+
+func TestUploadCommand_Execute(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.pdf")
+	require.NoError(t, os.WriteFile(path, []byte("%PDF"), 0o600))
+
+	expectedItem := &datapb.DataItem{
+		InfoType: "binary",
+		Meta:     "паспорт",
+		Payload: &datapb.DataItem_Binary{Binary: &datapb.Binary{
+			Filename: "scan.pdf",
+			Mime:     "application/pdf",
+		}},
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		input          string
+		mockSetup      func(m *MockUploadDataService)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:  "Успешная загрузка",
+			token: "valid_token",
+			input: path + "\nпаспорт\n",
+			mockSetup: func(m *MockUploadDataService) {
+				m.On("UploadBinary", mock.Anything, "valid_token", expectedItem, mock.Anything).Return(int32(3), nil)
+			},
+			expectedOutput: "Путь к файлу: Метаинформация: Файл успешно загружен с ID: 3\n",
+		},
+		{
+			name:          "Не авторизован",
+			token:         "",
+			mockSetup:     func(m *MockUploadDataService) {},
+			expectedError: "вы должны войти в систему",
+		},
+		{
+			name:          "Файл не найден",
+			token:         "valid_token",
+			input:         filepath.Join(t.TempDir(), "missing") + "\n\n",
+			mockSetup:     func(m *MockUploadDataService) {},
+			expectedError: "ошибка открытия файла",
+		},
+		{
+			name:  "Ошибка сервиса",
+			token: "valid_token",
+			input: path + "\nпаспорт\n",
+			mockSetup: func(m *MockUploadDataService) {
+				m.On("UploadBinary", mock.Anything, "valid_token", expectedItem, mock.Anything).
+					Return(int32(0), errors.New("stream error"))
+			},
+			expectedError: "ошибка загрузки файла: stream error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUploadDataService)
+			tt.mockSetup(mockService)
+
+			var output bytes.Buffer
+			cmd := NewUploadCommand(
+				mockService,
+				&entity.TokenHolder{Token: tt.token},
+				strings.NewReader(tt.input),
+				&output,
+			)
+
+			err := cmd.Execute()
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, output.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type vaultService interface {
+	CreateVault(ctx context.Context, token, name string) (int32, error)
+	ListVaults(ctx context.Context, token string) ([]*vaultpb.Vault, error)
+	AddMember(ctx context.Context, token string, vaultID int32, login, role string) error
+	RemoveMember(ctx context.Context, token string, vaultID int32, login string) error
+	ListMembers(ctx context.Context, token string, vaultID int32) ([]*vaultpb.Member, error)
+}
+
+type vaultDataService interface {
+	GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error)
+	ListData(ctx context.Context, token string, req *datapb.ListDataRequest) (*datapb.ListDataResponse, error)
+	MoveData(ctx context.Context, token string, data *datapb.DataItem, vaultID int32) (int64, error)
+}
+
+// VaultCommand - общие хранилища: создание, участники и перенос записей.
+type VaultCommand struct {
+	vaultService vaultService
+	dataService  vaultDataService
+	tokenHolder  *entity.TokenHolder
+	reader       io.Reader
+	writer       io.Writer
+}
+
+func NewVaultCommand(
+	vaultService vaultService,
+	dataService vaultDataService,
+	tokenHolder *entity.TokenHolder,
+	reader io.Reader,
+	writer io.Writer,
+) *VaultCommand {
+	return &VaultCommand{
+		vaultService: vaultService,
+		dataService:  dataService,
+		tokenHolder:  tokenHolder,
+		reader:       reader,
+		writer:       writer,
+	}
+}
+
+func (c *VaultCommand) Name() string {
+	return "vault"
+}
+
+func (c *VaultCommand) Execute() error {
+	if c.tokenHolder.Token == "" {
+		return fmt.Errorf("вы должны войти в систему")
+	}
+
+	scanner := bufio.NewScanner(c.reader)
+	prompter := &payloadPrompter{scanner: scanner, writer: c.writer}
+
+	action, err := prompter.field("Действие (create/list/members/invite/remove/items/move)", "")
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	switch strings.TrimSpace(action) {
+	case "create":
+		args, err = promptArgs(prompter, "Название хранилища")
+	case "list":
+	case "members", "items":
+		args, err = promptArgs(prompter, "ID хранилища")
+	case "invite":
+		args, err = promptArgs(prompter, "ID хранилища", "Логин", "Роль (read/write)")
+	case "remove":
+		args, err = promptArgs(prompter, "ID хранилища", "Логин")
+	case "move":
+		args, err = promptArgs(prompter, "ID записи", "ID хранилища (0 - личные данные)")
+	default:
+		return fmt.Errorf("неизвестное действие: %s", action)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.run(strings.TrimSpace(action), args, "", FormatTable)
+}
+
+func (c *VaultCommand) Usage() string {
+	return "vault create <название> | vault list | vault members <id> | " +
+		"vault invite <id> <логин> [--role read|write] | vault remove <id> <логин> | vault items <id> | " +
+		"vault move <id записи> <id хранилища, 0 - личные данные> [--output plain|table|json]"
+}
+
+func (c *VaultCommand) Run(args []string) error {
+	flags := newCLIFlags(c.Name())
+	role := flags.String("role", "read", "роль участника: read или write")
+	positional, format, err := flags.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return usageErrorf("не указано действие")
+	}
+	if c.tokenHolder.Token == "" {
+		return ErrNotLoggedIn
+	}
+
+	return c.run(positional[0], positional[1:], *role, format)
+}
+
+func (c *VaultCommand) run(action string, args []string, role string, format Format) error {
+	ctx := context.Background()
+	token := c.tokenHolder.Token
+
+	switch {
+	case action == "create" && len(args) == 1:
+		id, err := c.vaultService.CreateVault(ctx, token, args[0])
+		if err != nil {
+			return fmt.Errorf("ошибка создания хранилища: %w", err)
+		}
+		return writeID(c.writer, format, id)
+	case action == "list" && len(args) == 0:
+		return c.list(ctx, format)
+	case action == "members" && len(args) == 1:
+		vaultID, err := parseVaultID(args[0])
+		if err != nil {
+			return err
+		}
+		return c.members(ctx, vaultID, format)
+	case action == "invite" && len(args) >= 2 && len(args) <= 3:
+		vaultID, err := parseVaultID(args[0])
+		if err != nil {
+			return err
+		}
+		if len(args) == 3 {
+			role = args[2]
+		}
+		if role != "read" && role != "write" {
+			return usageErrorf("некорректная роль %q, допустимы: read, write", role)
+		}
+		if err := c.vaultService.AddMember(ctx, token, vaultID, args[1], role); err != nil {
+			return fmt.Errorf("ошибка приглашения участника: %w", err)
+		}
+		return c.done(fmt.Sprintf("Пользователь %s добавлен в хранилище %d с ролью %s.", args[1], vaultID, role))
+	case action == "remove" && len(args) == 2:
+		vaultID, err := parseVaultID(args[0])
+		if err != nil {
+			return err
+		}
+		if err := c.vaultService.RemoveMember(ctx, token, vaultID, args[1]); err != nil {
+			return fmt.Errorf("ошибка исключения участника: %w", err)
+		}
+		return c.done(fmt.Sprintf("Пользователь %s исключён из хранилища %d.", args[1], vaultID))
+	case action == "items" && len(args) == 1:
+		vaultID, err := parseVaultID(args[0])
+		if err != nil {
+			return err
+		}
+		return c.items(ctx, vaultID, format)
+	case action == "move" && len(args) == 2:
+		return c.move(ctx, args[0], args[1])
+	default:
+		return usageErrorf("некорректные аргументы: %s", strings.Join(append([]string{action}, args...), " "))
+	}
+}
+
+func (c *VaultCommand) list(ctx context.Context, format Format) error {
+	vaults, err := c.vaultService.ListVaults(ctx, c.tokenHolder.Token)
+	if err != nil {
+		return fmt.Errorf("ошибка получения списка хранилищ: %w", err)
+	}
+
+	rows := make([]record, 0, len(vaults))
+	for _, vault := range vaults {
+		rows = append(rows, record{
+			{"id", vault.Id},
+			{"name", vault.Name},
+			{"role", vault.Role},
+			{"created", vault.GetCreated().AsTime().Format(time.DateTime)},
+		})
+	}
+
+	return writeRecords(c.writer, format, []string{"id", "name", "role", "created"}, rows)
+}
+
+func (c *VaultCommand) members(ctx context.Context, vaultID int32, format Format) error {
+	members, err := c.vaultService.ListMembers(ctx, c.tokenHolder.Token, vaultID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения участников: %w", err)
+	}
+
+	rows := make([]record, 0, len(members))
+	for _, member := range members {
+		rows = append(rows, record{{"login", member.Login}, {"role", member.Role}})
+	}
+
+	return writeRecords(c.writer, format, []string{"login", "role"}, rows)
+}
+
+func (c *VaultCommand) items(ctx context.Context, vaultID int32, format Format) error {
+	req := &datapb.ListDataRequest{VaultId: vaultID, PageSize: listPageSize}
+	rows := []record{}
+	for {
+		res, err := c.dataService.ListData(ctx, c.tokenHolder.Token, req)
+		if err != nil {
+			return fmt.Errorf("ошибка получения записей хранилища: %w", err)
+		}
+		for _, item := range res.Items {
+			rows = append(rows, listRecord(item))
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		req.Cursor = res.NextCursor
+	}
+
+	return writeRecords(c.writer, format, listColumns, rows)
+}
+
+// move - переносит запись: она перешифровывается ключом целевого хранилища
+// на клиенте и отправляется с версией, прочитанной перед переносом.
+func (c *VaultCommand) move(ctx context.Context, idArg, vaultArg string) error {
+	id, err := strconv.ParseInt(idArg, 10, 32)
+	if err != nil || id <= 0 {
+		return usageErrorf("некорректный ID записи: %s", idArg)
+	}
+	vaultID, err := strconv.ParseInt(vaultArg, 10, 32)
+	if err != nil || vaultID < 0 {
+		return usageErrorf("некорректный ID хранилища: %s", vaultArg)
+	}
+
+	data, err := c.dataService.GetData(ctx, c.tokenHolder.Token, int32(id))
+	if err != nil {
+		return fmt.Errorf("ошибка получения данных: %w", err)
+	}
+	if data.VaultId == int32(vaultID) {
+		return fmt.Errorf("запись %d уже находится в этом хранилище", id)
+	}
+
+	if _, err := c.dataService.MoveData(ctx, c.tokenHolder.Token, data, int32(vaultID)); err != nil {
+		return fmt.Errorf("ошибка переноса записи: %w", err)
+	}
+
+	if vaultID == 0 {
+		return c.done(fmt.Sprintf("Запись %d перенесена в личные данные.", id))
+	}
+	return c.done(fmt.Sprintf("Запись %d перенесена в хранилище %d.", id, vaultID))
+}
+
+func (c *VaultCommand) done(message string) error {
+	if _, err := fmt.Fprintln(c.writer, message); err != nil {
+		return fmt.Errorf("ошибка вывода результата: %w", err)
+	}
+
+	return nil
+}
+
+func parseVaultID(value string) (int32, error) {
+	id, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || id <= 0 {
+		return 0, usageErrorf("некорректный ID хранилища: %s", value)
+	}
+
+	return int32(id), nil
+}
+
+// promptArgs - запрашивает аргументы действия по очереди.
+func promptArgs(prompter *payloadPrompter, labels ...string) ([]string, error) {
+	args := make([]string, 0, len(labels))
+	for _, label := range labels {
+		value, err := prompter.field(label, "")
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSpace(value))
+	}
+
+	return args, nil
+}
@@ -0,0 +1,195 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockVaultService struct {
+	mock.Mock
+}
+
+func (m *MockVaultService) CreateVault(ctx context.Context, token, name string) (int32, error) {
+	args := m.Called(ctx, token, name)
+	return args.Get(0).(int32), args.Error(1)
+}
+
+func (m *MockVaultService) ListVaults(ctx context.Context, token string) ([]*vaultpb.Vault, error) {
+	args := m.Called(ctx, token)
+	vaults, _ := args.Get(0).([]*vaultpb.Vault)
+	return vaults, args.Error(1)
+}
+
+func (m *MockVaultService) AddMember(ctx context.Context, token string, vaultID int32, login, role string) error {
+	args := m.Called(ctx, token, vaultID, login, role)
+	return args.Error(0)
+}
+
+func (m *MockVaultService) RemoveMember(ctx context.Context, token string, vaultID int32, login string) error {
+	args := m.Called(ctx, token, vaultID, login)
+	return args.Error(0)
+}
+
+func (m *MockVaultService) ListMembers(ctx context.Context, token string, vaultID int32) ([]*vaultpb.Member, error) {
+	args := m.Called(ctx, token, vaultID)
+	members, _ := args.Get(0).([]*vaultpb.Member)
+	return members, args.Error(1)
+}
+
+type MockVaultDataService struct {
+	mock.Mock
+}
+
+func (m *MockVaultDataService) GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error) {
+	args := m.Called(ctx, token, id)
+	data, _ := args.Get(0).(*datapb.DataItem)
+	return data, args.Error(1)
+}
+
+func (m *MockVaultDataService) ListData(
+	ctx context.Context, token string, req *datapb.ListDataRequest,
+) (*datapb.ListDataResponse, error) {
+	args := m.Called(ctx, token, req)
+	res, _ := args.Get(0).(*datapb.ListDataResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockVaultDataService) MoveData(
+	ctx context.Context, token string, data *datapb.DataItem, vaultID int32,
+) (int64, error) {
+	args := m.Called(ctx, token, data, vaultID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestVaultCommand_Run(t *testing.T) {
+	ctx := context.Background()
+	item := &datapb.DataItem{Id: 3, InfoType: "text", Info: "secret", Version: 2}
+
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(v *MockVaultService, d *MockVaultDataService)
+		expectedOutput string
+		expectedCode   int
+	}{
+		{
+			name: "создание",
+			args: []string{"create", "семья"},
+			setupMock: func(v *MockVaultService, d *MockVaultDataService) {
+				v.On("CreateVault", ctx, "token", "семья").Return(int32(4), nil)
+			},
+			expectedOutput: "4\n",
+		},
+		{
+			name: "список хранилищ",
+			args: []string{"list"},
+			setupMock: func(v *MockVaultService, d *MockVaultDataService) {
+				v.On("ListVaults", ctx, "token").Return([]*vaultpb.Vault{{Id: 4, Name: "семья", Role: "owner"}}, nil)
+			},
+			expectedOutput: "4\tсемья\towner\t1970-01-01 00:00:00\n",
+		},
+		{
+			name: "участники",
+			args: []string{"members", "4"},
+			setupMock: func(v *MockVaultService, d *MockVaultDataService) {
+				v.On("ListMembers", ctx, "token", int32(4)).
+					Return([]*vaultpb.Member{{Login: "alice", Role: "owner"}, {Login: "bob", Role: "read"}}, nil)
+			},
+			expectedOutput: "alice\towner\nbob\tread\n",
+		},
+		{
+			name: "приглашение с правом записи",
+			args: []string{"invite", "4", "bob", "--role", "write"},
+			setupMock: func(v *MockVaultService, d *MockVaultDataService) {
+				v.On("AddMember", ctx, "token", int32(4), "bob", "write").Return(nil)
+			},
+			expectedOutput: "Пользователь bob добавлен в хранилище 4 с ролью write.\n",
+		},
+		{
+			name:         "приглашение владельцем",
+			args:         []string{"invite", "4", "bob", "--role", "owner"},
+			setupMock:    func(v *MockVaultService, d *MockVaultDataService) {},
+			expectedCode: ExitUsage,
+		},
+		{
+			name: "исключение без прав",
+			args: []string{"remove", "4", "carol"},
+			setupMock: func(v *MockVaultService, d *MockVaultDataService) {
+				v.On("RemoveMember", ctx, "token", int32(4), "carol").
+					Return(status.Error(codes.PermissionDenied, "недостаточно прав"))
+			},
+			expectedCode: ExitAuth,
+		},
+		{
+			name: "записи хранилища",
+			args: []string{"items", "4"},
+			setupMock: func(v *MockVaultService, d *MockVaultDataService) {
+				d.On("ListData", ctx, "token", &datapb.ListDataRequest{VaultId: 4, PageSize: listPageSize}).
+					Return(&datapb.ListDataResponse{Items: []*datapb.DataHeader{{Id: 3, InfoType: "text", Meta: "m"}}}, nil)
+			},
+			expectedOutput: "3\ttext\t1970-01-01 00:00:00\tm\n",
+		},
+		{
+			name: "перенос в хранилище",
+			args: []string{"move", "3", "4"},
+			setupMock: func(v *MockVaultService, d *MockVaultDataService) {
+				d.On("GetData", ctx, "token", int32(3)).Return(item, nil)
+				d.On("MoveData", ctx, "token", item, int32(4)).Return(int64(3), nil)
+			},
+			expectedOutput: "Запись 3 перенесена в хранилище 4.\n",
+		},
+		{
+			name: "запись уже в личных данных",
+			args: []string{"move", "3", "0"},
+			setupMock: func(v *MockVaultService, d *MockVaultDataService) {
+				d.On("GetData", ctx, "token", int32(3)).Return(item, nil)
+			},
+			expectedCode: ExitFailure,
+		},
+		{
+			name:         "некорректный ID",
+			args:         []string{"members", "x"},
+			setupMock:    func(v *MockVaultService, d *MockVaultDataService) {},
+			expectedCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaults := new(MockVaultService)
+			data := new(MockVaultDataService)
+			tt.setupMock(vaults, data)
+			writer := &bytes.Buffer{}
+
+			cmd := NewVaultCommand(vaults, data, &entity.TokenHolder{Token: "token"}, &bytes.Buffer{}, writer)
+			err := cmd.Run(tt.args)
+
+			assert.Equal(t, tt.expectedCode, ExitCode(err), "ошибка: %v", err)
+			assert.Equal(t, tt.expectedOutput, writer.String())
+			vaults.AssertExpectations(t)
+			data.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVaultCommand_Execute(t *testing.T) {
+	vaults := new(MockVaultService)
+	vaults.On("AddMember", context.Background(), "token", int32(4), "bob", "read").Return(nil)
+	writer := &bytes.Buffer{}
+
+	cmd := NewVaultCommand(vaults, new(MockVaultDataService), &entity.TokenHolder{Token: "token"},
+		bytes.NewBufferString("invite\n4\nbob\nread\n"), writer)
+
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, writer.String(), "Пользователь bob добавлен в хранилище 4 с ролью read.")
+	vaults.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// Виды операций, отложенных до синхронизации.
+const (
+	OperationAdd    = "add"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// CachedItem - запись в локальном кеше в том виде, в каком её хранит сервер:
+// info и meta зашифрованы ключом пользователя, теги и папка открыты.
+// SearchTokens есть только у записей из очереди: сервер токены не отдаёт.
+type CachedItem struct {
+	ID       int32     `json:"id"`
+	InfoType string    `json:"info_type"`
+	Info     string    `json:"info"`
+	Meta     string    `json:"meta"`
+	Created  time.Time `json:"created"`
+	Version  int64     `json:"version"`
+	Tags     []string  `json:"tags,omitempty"`
+	Folder   string    `json:"folder,omitempty"`
+	Favorite bool      `json:"favorite,omitempty"`
+
+	SearchTokens []string `json:"search_tokens,omitempty"`
+}
+
+// PendingOperation - изменение, сделанное без связи с сервером.
+// Item.Version - версия записи, которую видел клиент до изменения.
+type PendingOperation struct {
+	Kind string     `json:"kind"`
+	Item CachedItem `json:"item"`
+}
+
+// Cache - локальная копия записей пользователя и очередь несинхронизированных изменений.
+// Записи, добавленные офлайн, получают отрицательные ID до синхронизации.
+// Revision - ревизия сервера, до которой кеш уже получил изменения.
+type Cache struct {
+	Items       map[int32]CachedItem `json:"items"`
+	Queue       []PendingOperation   `json:"queue"`
+	Revision    int64                `json:"revision"`
+	LastLocalID int32                `json:"last_local_id"`
+}
+
+// SyncResult - итог синхронизации: сколько изменений отправлено, сколько получено
+// и сколько офлайн-изменений разошлись с версией на сервере.
+type SyncResult struct {
+	Pushed    int
+	Pulled    int
+	Conflicts int
+}
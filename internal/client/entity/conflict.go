@@ -0,0 +1,14 @@
+package entity
+
+import "fmt"
+
+// VersionConflictError - сервер отклонил изменение: запись успели изменить
+// на другом устройстве. CurrentVersion - версия записи на сервере.
+type VersionConflictError struct {
+	ID             int32
+	CurrentVersion int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("запись %d изменена на другом устройстве, текущая версия %d", e.ID, e.CurrentVersion)
+}
@@ -0,0 +1,21 @@
+package entity
+
+// Состояния записи в отчёте импорта.
+const (
+	// ImportAdded - запись добавлена.
+	ImportAdded = "added"
+	// ImportNew - запись будет добавлена; только при пробном импорте.
+	ImportNew = "new"
+	// ImportDuplicate - такая запись уже есть, она пропущена.
+	ImportDuplicate = "duplicate"
+	// ImportSkipped - запись не удалось перенести, причина в Reason.
+	ImportSkipped = "skipped"
+)
+
+// ImportEntry - строка отчёта импорта: одна запись исходного файла.
+type ImportEntry struct {
+	Status   string
+	InfoType string
+	Title    string
+	Reason   string
+}
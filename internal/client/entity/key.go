@@ -0,0 +1,41 @@
+package entity
+
+import "errors"
+
+// ErrWrongMasterPassword - ключ из введённого мастер-пароля не открывает
+// проверку, сохранённую на сервере.
+var ErrWrongMasterPassword = errors.New("неверный мастер-пароль")
+
+// KDFParams - параметры Argon2id, которые сервер возвращает при входе и регистрации.
+// KeyCheck - шифртекст проверки мастер-пароля, пуст до первого сохранения.
+type KDFParams struct {
+	Salt     []byte
+	KeyCheck []byte
+	Time     uint32
+	Memory   uint32
+	Threads  uint32
+}
+
+// KeyHolder - ключ шифрования, выведенный из мастер-пароля, и параметры KDF,
+// с которыми он получен: по ним ключ выводится заново при восстановлении сессии.
+// PrivateKey и VaultKeys - расшифрованные закрытый ключ X25519 пользователя
+// и ключи общих хранилищ по id; они подгружаются по мере надобности.
+type KeyHolder struct {
+	Params     *KDFParams
+	VaultKeys  map[int32][]byte
+	Key        []byte
+	PrivateKey []byte
+}
+
+// Set - запоминает ключ нового входа. Ключи хранилищ прежнего входа забываются.
+func (h *KeyHolder) Set(key []byte, params *KDFParams) {
+	h.Key = key
+	h.Params = params
+	h.PrivateKey = nil
+	h.VaultKeys = nil
+}
+
+// Clear - забывает все ключи, например после выхода.
+func (h *KeyHolder) Clear() {
+	h.Set(nil, nil)
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "regexp"
+
+// profileNamePattern - имя профиля входит в пути к файлу сессии и каталогу
+// кеша, поэтому в нём только латиница, цифры, "_" и "-".
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidProfileName - можно ли использовать name как имя профиля.
+func ValidProfileName(name string) bool {
+	return profileNamePattern.MatchString(name)
+}
+
+// Profile - настройки подключения к одному серверу под одной учётной записью.
+// Пустой CacheDir означает отдельный каталог профиля рядом с файлом профилей.
+type Profile struct {
+	Address      string `json:"address"`
+	RootCertPath string `json:"ca"`
+	Login        string `json:"login,omitempty"`
+	CacheDir     string `json:"cache_dir,omitempty"`
+}
+
+// Profiles - именованные профили клиента и профиль по умолчанию.
+type Profiles struct {
+	Items   map[string]Profile `json:"profiles"`
+	Current string             `json:"current"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// Session - сессия пользователя на одном из устройств.
+type Session struct {
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	DeviceName string
+	ID         int64
+	Current    bool
+}
+
+// SessionFile - сессия, сохранённая на диске между запусками клиента.
+// Параметры KDF лежат открыто, чтобы вывести ключ из мастер-пароля,
+// токены - только в Sealed, зашифрованными этим ключом.
+type SessionFile struct {
+	ServerAddress string    `json:"server_address"`
+	Sealed        string    `json:"sealed"`
+	KDF           KDFParams `json:"kdf"`
+}
+
+// SavedSession - расшифрованное содержимое SessionFile.Sealed.
+type SavedSession struct {
+	ExpiresAt     time.Time `json:"expires_at"`
+	ServerAddress string    `json:"server_address"`
+	AccessToken   string    `json:"access_token"`
+	RefreshToken  string    `json:"refresh_token"`
+}
+
+// ErrSessionExpired - сохранённую сессию нельзя продолжить: access-токен истёк,
+// а refresh-токена нет.
+var ErrSessionExpired = errors.New("сохранённая сессия истекла, войдите заново")
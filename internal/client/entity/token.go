@@ -1,5 +1,31 @@
 package entity
 
+import "time"
+
+// AuthTokens - токены сессии, выданные сервером при входе, регистрации
+// или обновлении. ExpiresAt - срок действия access-токена.
+type AuthTokens struct {
+	ExpiresAt    time.Time
+	AccessToken  string
+	RefreshToken string
+}
+
+// TokenHolder - токены текущей сессии клиента. Token передаётся в запросах,
+// RefreshToken и ExpiresAt нужны, чтобы обновлять access-токен.
 type TokenHolder struct {
-	Token string
+	ExpiresAt    time.Time
+	Token        string
+	RefreshToken string
+}
+
+// Set - сохраняет новую пару токенов.
+func (h *TokenHolder) Set(tokens *AuthTokens) {
+	h.Token = tokens.AccessToken
+	h.RefreshToken = tokens.RefreshToken
+	h.ExpiresAt = tokens.ExpiresAt
+}
+
+// Clear - забывает токены сессии, например после выхода.
+func (h *TokenHolder) Clear() {
+	h.Set(&AuthTokens{})
 }
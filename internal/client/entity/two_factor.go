@@ -0,0 +1,18 @@
+package entity
+
+// TwoFactorRequiredError - пароль принят, но для входа нужен код второго
+// фактора. Challenge передаётся серверу вместе с кодом.
+type TwoFactorRequiredError struct {
+	Challenge string
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "требуется код двухфакторной аутентификации"
+}
+
+// TOTPEnrollment - данные для подключения аутентификатора.
+type TOTPEnrollment struct {
+	URI           string
+	Secret        string
+	RecoveryCodes []string
+}
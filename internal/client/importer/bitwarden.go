@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Типы записей Bitwarden.
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+	bitwardenCard       = 3
+)
+
+// bitwardenFieldLinked - поле-ссылка на другое поле записи, значения у него нет.
+const bitwardenFieldLinked = 3
+
+// bitwardenExport - JSON-экспорт Bitwarden без шифрования.
+type bitwardenExport struct {
+	Encrypted bool            `json:"encrypted"`
+	Items     []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type  int    `json:"type"`
+	Name  string `json:"name"`
+	Notes string `json:"notes"`
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Totp     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Fields []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  int    `json:"type"`
+	} `json:"fields"`
+}
+
+func parseBitwarden(r io.Reader) (*Result, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("%w: Bitwarden JSON: %v", ErrFormat, err)
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("%w: зашифрованный экспорт Bitwarden не поддерживается, выгрузите JSON без шифрования",
+			ErrFormat)
+	}
+
+	result := &Result{}
+	for i, it := range export.Items {
+		title := untitled(it.Name, i+1)
+		switch it.Type {
+		case bitwardenLogin, bitwardenSecureNote:
+			item, err := bitwardenEntry(it).item()
+			result.add(title, item, err)
+		case bitwardenCard:
+			if it.Card == nil {
+				result.skip(title, "нет данных карты")
+				continue
+			}
+			c := it.Card
+			result.add(title, cardItem(it.Name, c.Number, c.CardholderName, c.ExpMonth, c.ExpYear, c.Code), nil)
+		default:
+			result.skip(title, "тип записи Bitwarden %d не поддерживается", it.Type)
+		}
+	}
+
+	return result, nil
+}
+
+func bitwardenEntry(it bitwardenItem) *entry {
+	en := &entry{title: it.Name, notes: it.Notes}
+	if it.Login != nil {
+		en.login = it.Login.Username
+		en.password = it.Login.Password
+		en.totp = it.Login.Totp
+		for i, u := range it.Login.URIs {
+			if i == 0 {
+				en.url = u.URI
+				continue
+			}
+			en.fields = append(en.fields, field{name: "URL", value: u.URI})
+		}
+	}
+	for _, f := range it.Fields {
+		if f.Type != bitwardenFieldLinked {
+			en.fields = append(en.fields, field{name: f.Name, value: f.Value})
+		}
+	}
+
+	return en
+}
@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const bitwardenJSON = `{
+  "encrypted": false,
+  "folders": [],
+  "items": [
+    {
+      "type": 1, "name": "github", "notes": null,
+      "login": {
+        "username": "octocat", "password": "hunter2", "totp": "JBSWY3DPEHPK3PXP",
+        "uris": [{"match": null, "uri": "https://github.com"}, {"uri": "https://gist.github.com"}]
+      },
+      "fields": [
+        {"name": "recovery", "value": "abc-def", "type": 1},
+        {"name": "link", "value": null, "type": 3, "linkedId": 100}
+      ]
+    },
+    {"type": 2, "name": "заметка", "notes": "код домофона 42", "secureNote": {"type": 0}},
+    {
+      "type": 3, "name": "visa",
+      "card": {
+        "cardholderName": "IVAN IVANOV", "brand": "Visa", "number": "4111111111111111",
+        "expMonth": "3", "expYear": "2031", "code": "123"
+      }
+    },
+    {"type": 3, "name": "битая карта", "card": {"number": "1234", "expMonth": "1", "expYear": "2030"}},
+    {"type": 4, "name": "паспорт", "identity": {"firstName": "Иван"}}
+  ]
+}`
+
+func TestParse_Bitwarden(t *testing.T) {
+	result, err := Parse(FormatBitwarden, strings.NewReader(bitwardenJSON))
+	require.NoError(t, err)
+
+	expected := []*datapb.DataItem{
+		{
+			InfoType: "login_password",
+			Meta:     "github\n\nURL: https://gist.github.com\nrecovery: abc-def",
+			Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{
+				Login: "octocat", Password: "hunter2", Url: "https://github.com", TotpSecret: "JBSWY3DPEHPK3PXP",
+			}},
+		},
+		{
+			InfoType: "text",
+			Meta:     "заметка",
+			Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "код домофона 42"}},
+		},
+		{
+			InfoType: "bank_card",
+			Meta:     "visa",
+			Payload: &datapb.DataItem_BankCard{BankCard: &datapb.BankCard{
+				Number: "4111111111111111", Holder: "IVAN IVANOV", Expiry: "03/31", Cvv: "123",
+			}},
+		},
+	}
+	require.Len(t, result.Items, len(expected))
+	for i := range expected {
+		assert.True(t, proto.Equal(expected[i], result.Items[i]), "запись %d: %v", i, result.Items[i])
+	}
+	require.Len(t, result.Skipped, 2)
+	assert.Equal(t, "битая карта", result.Skipped[0].Title)
+	assert.Contains(t, result.Skipped[0].Reason, "Луна")
+	assert.Equal(t, Skipped{Title: "паспорт", Reason: "тип записи Bitwarden 4 не поддерживается"}, result.Skipped[1])
+}
+
+func TestParse_BitwardenEncrypted(t *testing.T) {
+	_, err := Parse(FormatBitwarden, strings.NewReader(`{"encrypted": true, "items": []}`))
+	assert.ErrorIs(t, err, ErrFormat)
+}
@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+)
+
+// csvColumns - названия колонок, которые понимает импорт, по полю записи.
+// Совпадают с экспортом 1Password 7 и 8, Bitwarden CSV и большинства браузеров.
+var csvColumns = map[string][]string{
+	"title":    {"title", "name"},
+	"type":     {"type"},
+	"login":    {"username", "login", "login_username", "user"},
+	"password": {"password", "login_password"},
+	"url":      {"url", "website", "login_uri", "uri"},
+	"totp":     {"totp", "otp", "otpauth", "login_totp"},
+	"notes":    {"notes", "note", "notesplain"},
+	"number":   {"number", "card_number"},
+	"holder":   {"holder", "cardholder", "cardholder_name"},
+	"expiry":   {"expiry", "expiration"},
+	"cvv":      {"cvv", "code", "security_code"},
+}
+
+// csvIgnored - служебные колонки, которые не переносятся.
+var csvIgnored = map[string]bool{
+	"favorite": true, "archived": true, "tags": true, "folder": true, "reprompt": true,
+}
+
+// parseCSV - CSV с заголовком. onePassword - экспорт 1Password:
+// записи из архива 1Password пропускаются.
+func parseCSV(r io.Reader, onePassword bool) (*Result, error) {
+	// Excel добавляет BOM в начало файла, csv.Reader его не пропускает.
+	buffered := bufio.NewReader(r)
+	if first, _, err := buffered.ReadRune(); err == nil && first != '\ufeff' {
+		_ = buffered.UnreadRune()
+	}
+	reader := csv.NewReader(buffered)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: CSV без заголовка: %v", ErrFormat, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	names := make([]string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		names[i] = name
+		for key, aliases := range csvColumns {
+			for _, alias := range aliases {
+				if _, ok := columns[key]; !ok && name == alias {
+					columns[key] = i
+				}
+			}
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("%w: в заголовке CSV нет колонки title или name", ErrFormat)
+	}
+
+	result := &Result{}
+	for n := 1; ; n++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+
+		value := func(key string) string {
+			if i, ok := columns[key]; ok {
+				return row[i]
+			}
+			return ""
+		}
+		title := untitled(value("title"), n)
+		if onePassword && strings.EqualFold(rowValue(names, row, "archived"), "true") {
+			result.skip(title, "запись в архиве 1Password")
+			continue
+		}
+
+		switch csvType(value("type"), value("number")) {
+		case payload.TypeBankCard:
+			month, year, _ := strings.Cut(value("expiry"), "/")
+			result.add(title, cardItem(value("title"), value("number"), value("holder"), month, year, value("cvv")), nil)
+		case payload.TypeLoginPassword, payload.TypeText:
+			en := &entry{
+				title:    value("title"),
+				login:    value("login"),
+				password: value("password"),
+				url:      value("url"),
+				totp:     value("totp"),
+				notes:    value("notes"),
+			}
+			for i, name := range names {
+				if !csvKnown(name) && row[i] != "" {
+					en.fields = append(en.fields, field{name: header[i], value: row[i]})
+				}
+			}
+			item, err := en.item()
+			result.add(title, item, err)
+		default:
+			result.skip(title, "тип записи %q не поддерживается", value("type"))
+		}
+	}
+}
+
+// csvType - тип записи по колонке type; без неё карта узнаётся по номеру.
+func csvType(declared, number string) string {
+	switch strings.ToLower(strings.TrimSpace(declared)) {
+	case "":
+		if number != "" {
+			return payload.TypeBankCard
+		}
+		return payload.TypeLoginPassword
+	case payload.TypeLoginPassword, "login":
+		return payload.TypeLoginPassword
+	case payload.TypeText, "note":
+		return payload.TypeText
+	case payload.TypeBankCard, "card":
+		return payload.TypeBankCard
+	default:
+		return ""
+	}
+}
+
+func csvKnown(name string) bool {
+	if csvIgnored[name] {
+		return true
+	}
+	for _, aliases := range csvColumns {
+		for _, alias := range aliases {
+			if name == alias {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// rowValue - значение колонки name, пусто, если такой колонки нет.
+func rowValue(names, row []string, name string) string {
+	for i, n := range names {
+		if n == name {
+			return row[i]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParse_1Password(t *testing.T) {
+	export := "\ufeff\"Title\",\"Url\",\"Username\",\"Password\",\"OTPAuth\",\"Favorite\",\"Archived\",\"Tags\",\"Notes\"\n" +
+		"\"банк\",\"https://bank.example.com\",\"client\",\"p@ss\",\"\",\"true\",\"false\",\"финансы\",\"\"\n" +
+		"\"старый\",\"\",\"old\",\"x\",\"\",\"false\",\"true\",\"\",\"\"\n"
+
+	result, err := Parse(Format1Password, strings.NewReader(export))
+	require.NoError(t, err)
+
+	require.Len(t, result.Items, 1)
+	assert.True(t, proto.Equal(&datapb.DataItem{
+		InfoType: "login_password",
+		Meta:     "банк",
+		Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{
+			Login: "client", Password: "p@ss", Url: "https://bank.example.com",
+		}},
+	}, result.Items[0]), "%v", result.Items[0])
+	assert.Equal(t, []Skipped{{Title: "старый", Reason: "запись в архиве 1Password"}}, result.Skipped)
+}
+
+func TestParse_GenericCSV(t *testing.T) {
+	export := "name,type,login,password,notes,number,holder,expiry,cvv,department\n" +
+		"vpn,,alice,s3cret,,,,,,ИТ\n" +
+		"инструкция,text,,,позвонить в поддержку,,,,,\n" +
+		"mastercard,bank_card,,,,5555555555554444,ALICE,12/29,321,\n" +
+		"паспорт,identity,,,,,,,,\n"
+
+	result, err := Parse(FormatGenericCSV, strings.NewReader(export))
+	require.NoError(t, err)
+
+	expected := []*datapb.DataItem{
+		{
+			InfoType: "login_password",
+			Meta:     "vpn\n\ndepartment: ИТ",
+			Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{
+				Login: "alice", Password: "s3cret",
+			}},
+		},
+		{
+			InfoType: "text",
+			Meta:     "инструкция",
+			Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "позвонить в поддержку"}},
+		},
+		{
+			InfoType: "bank_card",
+			Meta:     "mastercard",
+			Payload: &datapb.DataItem_BankCard{BankCard: &datapb.BankCard{
+				Number: "5555555555554444", Holder: "ALICE", Expiry: "12/29", Cvv: "321",
+			}},
+		},
+	}
+	require.Len(t, result.Items, len(expected))
+	for i := range expected {
+		assert.True(t, proto.Equal(expected[i], result.Items[i]), "запись %d: %v", i, result.Items[i])
+	}
+	assert.Equal(t, []Skipped{{Title: "паспорт", Reason: `тип записи "identity" не поддерживается`}}, result.Skipped)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{name: "неизвестный формат", format: "lastpass", input: ""},
+		{name: "нет колонки title", format: FormatGenericCSV, input: "login,password\nuser,secret\n"},
+		{name: "пустой CSV", format: FormatGenericCSV, input: ""},
+		{name: "лишняя колонка", format: FormatGenericCSV, input: "name,password\nvpn,secret,extra\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.format, strings.NewReader(tt.input))
+			assert.ErrorIs(t, err, ErrFormat)
+		})
+	}
+}
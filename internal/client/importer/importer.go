@@ -0,0 +1,180 @@
+// Package importer - разбор экспорта других менеджеров паролей: KeePass XML,
+// Bitwarden JSON, 1Password CSV и CSV с заголовком. Каждая запись
+// превращается в login_password, text или bank_card; то, что перенести
+// нельзя, попадает в список пропущенных с причиной.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+)
+
+// Поддерживаемые форматы.
+const (
+	FormatKeePass    = "keepass"
+	FormatBitwarden  = "bitwarden"
+	Format1Password  = "1password"
+	FormatGenericCSV = "csv"
+)
+
+// ErrFormat - неизвестный формат или файл ему не соответствует.
+var ErrFormat = errors.New("некорректный формат импорта")
+
+// Skipped - запись экспорта, которую не удалось перенести.
+type Skipped struct {
+	Title  string
+	Reason string
+}
+
+// Result - записи, готовые к добавлению, и пропущенные записи
+// в порядке следования в файле.
+type Result struct {
+	Items   []*datapb.DataItem
+	Skipped []Skipped
+}
+
+// Parse - разбирает экспорт в формате format.
+func Parse(format string, r io.Reader) (*Result, error) {
+	switch format {
+	case FormatKeePass:
+		return parseKeePass(r)
+	case FormatBitwarden:
+		return parseBitwarden(r)
+	case Format1Password:
+		return parseCSV(r, true)
+	case FormatGenericCSV:
+		return parseCSV(r, false)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrFormat, format)
+	}
+}
+
+// add - добавляет запись или, если она не прошла проверку, пропускает её.
+func (r *Result) add(title string, item *datapb.DataItem, err error) {
+	if err == nil {
+		err = payload.Validate(item)
+	}
+	if err != nil {
+		r.Skipped = append(r.Skipped, Skipped{Title: title, Reason: err.Error()})
+		return
+	}
+	r.Items = append(r.Items, item)
+}
+
+// skip - пропускает запись с причиной.
+func (r *Result) skip(title, format string, args ...any) {
+	r.Skipped = append(r.Skipped, Skipped{Title: title, Reason: fmt.Sprintf(format, args...)})
+}
+
+// field - дополнительное поле записи, для которого нет места в payload.
+type field struct {
+	name  string
+	value string
+}
+
+// entry - учётная запись в общем для всех форматов виде.
+type entry struct {
+	title    string
+	login    string
+	password string
+	url      string
+	totp     string
+	notes    string
+	fields   []field
+}
+
+// item - запись с логином становится login_password, заметки и поля
+// сохраняются в описании. Запись без логина становится text: все её
+// значения переносятся в содержимое, чтобы ничего не потерять.
+func (e *entry) item() (*datapb.DataItem, error) {
+	if strings.TrimSpace(e.login) != "" {
+		return &datapb.DataItem{
+			InfoType: payload.TypeLoginPassword,
+			Meta:     joinNonEmpty("\n\n", e.title, e.details()),
+			Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{
+				Login:      e.login,
+				Password:   e.password,
+				Url:        e.url,
+				TotpSecret: e.totp,
+			}},
+		}, nil
+	}
+
+	var lines []string
+	for _, f := range []field{{"Пароль", e.password}, {"URL", e.url}, {"TOTP", e.totp}} {
+		if f.value != "" {
+			lines = append(lines, f.name+": "+f.value)
+		}
+	}
+	content := joinNonEmpty("\n\n", strings.Join(lines, "\n"), e.details())
+	if strings.TrimSpace(content) == "" {
+		return nil, errors.New("пустая запись")
+	}
+
+	return &datapb.DataItem{
+		InfoType: payload.TypeText,
+		Meta:     e.title,
+		Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: content}},
+	}, nil
+}
+
+// details - дополнительные поля строками "имя: значение" и заметки.
+func (e *entry) details() string {
+	lines := make([]string, 0, len(e.fields))
+	for _, f := range e.fields {
+		if f.value != "" {
+			lines = append(lines, f.name+": "+f.value)
+		}
+	}
+
+	return joinNonEmpty("\n\n", strings.Join(lines, "\n"), e.notes)
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	nonEmpty := parts[:0:0]
+	for _, p := range parts {
+		if strings.TrimSpace(p) != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+
+	return strings.Join(nonEmpty, sep)
+}
+
+// cardItem - банковская карта. Срок действия приводится к MM/YY.
+func cardItem(title, number, holder, month, year, cvv string) *datapb.DataItem {
+	expiry := ""
+	if month != "" && year != "" {
+		if len(month) == 1 {
+			month = "0" + month
+		}
+		if len(year) > 2 {
+			year = year[len(year)-2:]
+		}
+		expiry = month + "/" + year
+	}
+
+	return &datapb.DataItem{
+		InfoType: payload.TypeBankCard,
+		Meta:     title,
+		Payload: &datapb.DataItem_BankCard{BankCard: &datapb.BankCard{
+			Number: number,
+			Holder: holder,
+			Expiry: expiry,
+			Cvv:    cvv,
+		}},
+	}
+}
+
+// untitled - имя записи без названия в отчёте.
+func untitled(title string, n int) string {
+	if strings.TrimSpace(title) != "" {
+		return title
+	}
+	return fmt.Sprintf("#%d", n)
+}
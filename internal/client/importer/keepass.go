@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// keePassFile - XML-экспорт KeePass 2.x и KeePassXC без шифрования.
+type keePassFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+// keePassEntry - запись. Прошлые версии лежат во вложенном History
+// и сюда не попадают.
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Text      string `xml:",chardata"`
+			Protected string `xml:"Protected,attr"`
+		} `xml:"Value"`
+	} `xml:"String"`
+}
+
+func parseKeePass(r io.Reader) (*Result, error) {
+	var file keePassFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: KeePass XML: %v", ErrFormat, err)
+	}
+
+	result := &Result{}
+	n := 0
+	var walk func(groups []keePassGroup)
+	walk = func(groups []keePassGroup) {
+		for _, g := range groups {
+			// Корзина не переносится: её содержимое пользователь уже удалил.
+			if file.Meta.RecycleBinUUID != "" && g.UUID == file.Meta.RecycleBinUUID {
+				continue
+			}
+			for _, e := range g.Entries {
+				n++
+				keePassAdd(result, e, n)
+			}
+			walk(g.Groups)
+		}
+	}
+	walk(file.Root.Groups)
+
+	return result, nil
+}
+
+func keePassAdd(result *Result, e keePassEntry, n int) {
+	var en entry
+	for _, s := range e.Strings {
+		if s.Key == "Title" {
+			en.title = s.Value.Text
+		}
+	}
+	title := untitled(en.title, n)
+
+	for _, s := range e.Strings {
+		// В XML-экспорте значения открыты; Protected="True" бывает только
+		// в дампе базы, где значение зашифровано потоком самой базы.
+		if strings.EqualFold(s.Value.Protected, "true") {
+			result.skip(title, "зашифрованное поле %q не поддерживается", s.Key)
+			return
+		}
+		value := s.Value.Text
+		switch s.Key {
+		case "Title":
+		case "UserName":
+			en.login = value
+		case "Password":
+			en.password = value
+		case "URL":
+			en.url = value
+		case "Notes":
+			en.notes = value
+		case "otp", "TimeOtp-Secret-Base32":
+			en.totp = value
+		default:
+			en.fields = append(en.fields, field{name: s.Key, value: value})
+		}
+	}
+
+	item, err := en.item()
+	result.add(title, item, err)
+}
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const keePassXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta><RecycleBinUUID>Ymlu</RecycleBinUUID></Meta>
+	<Root>
+		<Group>
+			<UUID>cm9vdA==</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<String><Key>Title</Key><Value>почта</Value></String>
+				<String><Key>UserName</Key><Value>user</Value></String>
+				<String><Key>Password</Key><Value ProtectInMemory="True">secret</Value></String>
+				<String><Key>URL</Key><Value>https://mail.example.com</Value></String>
+				<String><Key>otp</Key><Value>otpauth://totp/mail?secret=JBSWY3DPEHPK3PXP</Value></String>
+				<String><Key>Notes</Key><Value>рабочий ящик</Value></String>
+				<String><Key>PIN</Key><Value>1234</Value></String>
+				<History>
+					<Entry>
+						<String><Key>Title</Key><Value>почта</Value></String>
+						<String><Key>UserName</Key><Value>old</Value></String>
+					</Entry>
+				</History>
+			</Entry>
+			<Group>
+				<UUID>d2lmaQ==</UUID>
+				<Name>Дом</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>wi-fi</Value></String>
+					<String><Key>UserName</Key><Value></Value></String>
+					<String><Key>Password</Key><Value>qwerty</Value></String>
+				</Entry>
+				<Entry>
+					<String><Key>Title</Key><Value></Value></String>
+				</Entry>
+				<Entry>
+					<String><Key>Title</Key><Value>дамп</Value></String>
+					<String><Key>Password</Key><Value Protected="True">c2VjcmV0</Value></String>
+				</Entry>
+			</Group>
+			<Group>
+				<UUID>Ymlu</UUID>
+				<Name>Recycle Bin</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>удалённая</Value></String>
+					<String><Key>UserName</Key><Value>gone</Value></String>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+func TestParse_KeePass(t *testing.T) {
+	result, err := Parse(FormatKeePass, strings.NewReader(keePassXML))
+	require.NoError(t, err)
+
+	expected := []*datapb.DataItem{
+		{
+			InfoType: "login_password",
+			Meta:     "почта\n\nPIN: 1234\n\nрабочий ящик",
+			Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{
+				Login:      "user",
+				Password:   "secret",
+				Url:        "https://mail.example.com",
+				TotpSecret: "otpauth://totp/mail?secret=JBSWY3DPEHPK3PXP",
+			}},
+		},
+		{
+			InfoType: "text",
+			Meta:     "wi-fi",
+			Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "Пароль: qwerty"}},
+		},
+	}
+	require.Len(t, result.Items, len(expected))
+	for i := range expected {
+		assert.True(t, proto.Equal(expected[i], result.Items[i]), "запись %d: %v", i, result.Items[i])
+	}
+	assert.Equal(t, []Skipped{
+		{Title: "#3", Reason: "пустая запись"},
+		{Title: "дамп", Reason: `зашифрованное поле "Password" не поддерживается`},
+	}, result.Skipped)
+}
+
+func TestParse_KeePassInvalid(t *testing.T) {
+	_, err := Parse(FormatKeePass, strings.NewReader(`{"items": []}`))
+	assert.ErrorIs(t, err, ErrFormat)
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type fileStore struct {
+	dir string
+}
+
+// NewFileStore - конструктор хранилища кеша: каждый кеш лежит
+// в отдельном JSON-файле в каталоге dir.
+func NewFileStore(dir string) *fileStore {
+	return &fileStore{dir: dir}
+}
+
+// Load - читает кеш по имени. Отсутствующий файл означает пустой кеш.
+func (s *fileStore) Load(name string) (*entity.Cache, error) {
+	content, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return &entity.Cache{Items: make(map[int32]entity.CachedItem)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения кеша: %w", err)
+	}
+
+	c := &entity.Cache{}
+	if err := json.Unmarshal(content, c); err != nil {
+		return nil, fmt.Errorf("ошибка разбора кеша: %w", err)
+	}
+	if c.Items == nil {
+		c.Items = make(map[int32]entity.CachedItem)
+	}
+
+	return c, nil
+}
+
+// Save - атомарно перезаписывает кеш: пишет во временный файл и переименовывает его.
+func (s *fileStore) Save(name string, c *entity.Cache) error {
+	content, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации кеша: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("ошибка создания каталога кеша: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".cache-*")
+	if err != nil {
+		return fmt.Errorf("ошибка записи кеша: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ошибка записи кеша: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ошибка записи кеша: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(name)); err != nil {
+		return fmt.Errorf("ошибка записи кеша: %w", err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) path(name string) string {
+	return filepath.Join(s.dir, "cache-"+name+".json")
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_SaveLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gophkeeper")
+	store := NewFileStore(dir)
+
+	empty, err := store.Load("user")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Items)
+	assert.Empty(t, empty.Queue)
+
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	c := &entity.Cache{
+		Items: map[int32]entity.CachedItem{
+			1:  {ID: 1, InfoType: "text", Info: "enc-info", Meta: "enc-meta", Created: created},
+			-1: {ID: -1, InfoType: "text", Info: "enc-local"},
+		},
+		Queue: []entity.PendingOperation{
+			{Kind: entity.OperationAdd, Item: entity.CachedItem{ID: -1, InfoType: "text", Info: "enc-local"}},
+		},
+		LastLocalID: -1,
+	}
+	require.NoError(t, store.Save("user", c))
+
+	loaded, err := store.Load("user")
+	require.NoError(t, err)
+	assert.Equal(t, c, loaded)
+
+	info, err := os.Stat(filepath.Join(dir, "cache-user.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	other, err := store.Load("other")
+	require.NoError(t, err)
+	assert.Empty(t, other.Items)
+}
+
+func TestFileStore_LoadCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cache-user.json"), []byte("{"), 0o600))
+
+	_, err := NewFileStore(dir).Load("user")
+	assert.Error(t, err)
+}
@@ -4,13 +4,36 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/client/infrastructure/profile"
 	"github.com/caarlos0/env"
 )
 
+// defaultProfile - имя профиля, когда профили не настроены.
+const defaultProfile = "default"
+
 type config struct {
 	ServerAddress string `env:"RUN_ADDRESS"`
 	RootCertPath  string `env:"ROOT_CERT_PATH"`
+	CacheDir      string `env:"CACHE_DIR"`
+	SessionDir    string `env:"SESSION_DIR"`
+	Profile       string `env:"GOPHKEEPER_PROFILE"`
+	ProfilesPath  string `env:"PROFILES_FILE"`
+
+	// Учётные данные для неинтерактивного режима задаются только через окружение,
+	// чтобы не попадать в историю команд и список процессов.
+	Login          string `env:"GOPHKEEPER_LOGIN"`
+	Password       string `env:"GOPHKEEPER_PASSWORD"`
+	MasterPassword string `env:"GOPHKEEPER_MASTER_PASSWORD"`
+	OTP            string `env:"GOPHKEEPER_OTP"`
+	// ExportPassphrase - парольная фраза архивов export и import.
+	ExportPassphrase string `env:"GOPHKEEPER_EXPORT_PASSPHRASE"`
+
+	args     []string
+	explicit map[string]bool
 }
 
 func (c *config) initEnv() error {
@@ -25,7 +48,71 @@ func (c *config) initEnv() error {
 func (c *config) parseFlags() {
 	flag.StringVar(&c.ServerAddress, "a", "localhost:8080", "net address host:port")
 	flag.StringVar(&c.RootCertPath, "ca", "./ca.pem", "root cert path")
+	flag.StringVar(&c.CacheDir, "cache-dir", defaultConfigDir(), "local cache directory")
+	flag.StringVar(&c.SessionDir, "session-dir", defaultConfigDir(), "saved session directory")
+	flag.StringVar(&c.Profile, "profile", "", "profile name")
+	flag.StringVar(
+		&c.ProfilesPath, "profiles-file", filepath.Join(defaultConfigDir(), "profiles.json"), "profiles file",
+	)
 	flag.Parse()
+	c.args = flag.Args()
+
+	c.explicit = make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		c.explicit[f.Name] = true
+	})
+}
+
+// applyProfile - подставляет настройки выбранного профиля: из -profile
+// или GOPHKEEPER_PROFILE, иначе текущего в файле профилей. Явно заданные
+// флаги и переменные окружения важнее профиля.
+func (c *config) applyProfile(profiles *entity.Profiles, lookupEnv func(string) (string, bool)) error {
+	name := c.Profile
+	if name == "" {
+		name = profiles.Current
+	}
+	if name == "" {
+		c.Profile = defaultProfile
+		return nil
+	}
+
+	p, ok := profiles.Items[name]
+	if !ok {
+		return fmt.Errorf("профиль %q не найден в %s", name, c.ProfilesPath)
+	}
+	c.Profile = name
+
+	cacheDir := p.CacheDir
+	if cacheDir == "" {
+		// У каждого профиля свой кеш, даже если каталог не задан явно.
+		cacheDir = filepath.Join(c.CacheDir, "profiles", name)
+	}
+
+	override := func(dst *string, value, flagName, envName string) {
+		if value == "" || c.explicit[flagName] {
+			return
+		}
+		if _, ok := lookupEnv(envName); ok {
+			return
+		}
+		*dst = value
+	}
+	override(&c.ServerAddress, p.Address, "a", "RUN_ADDRESS")
+	override(&c.RootCertPath, p.RootCertPath, "ca", "ROOT_CERT_PATH")
+	override(&c.CacheDir, cacheDir, "cache-dir", "CACHE_DIR")
+	override(&c.Login, p.Login, "", "GOPHKEEPER_LOGIN")
+
+	return nil
+}
+
+// defaultConfigDir - каталог gophkeeper в пользовательском каталоге настроек
+// ($XDG_CONFIG_HOME на Linux).
+func defaultConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".gophkeeper"
+	}
+	return filepath.Join(dir, "gophkeeper")
 }
 
 // NewConfig конструктор конфига, в котором идёт инициализация флагов и env переменных.
@@ -37,6 +124,14 @@ func NewConfig() *config {
 		log.Fatalf("Ошибка при инициализации переменных окружения: %v", err)
 	}
 
+	profiles, err := profile.NewFileStore(cfg.ProfilesPath).Load()
+	if err != nil {
+		log.Fatalf("Ошибка при чтении профилей: %v", err)
+	}
+	if err := cfg.applyProfile(profiles, os.LookupEnv); err != nil {
+		log.Fatalf("Ошибка при выборе профиля: %v", err)
+	}
+
 	return cfg
 }
 
@@ -49,3 +144,53 @@ func (c config) GetServerAddress() string {
 func (c config) GetRootCertPath() string {
 	return c.RootCertPath
 }
+
+// GetCacheDir геттер для каталога локального кеша.
+func (c config) GetCacheDir() string {
+	return c.CacheDir
+}
+
+// GetSessionDir геттер для каталога сохранённых сессий.
+func (c config) GetSessionDir() string {
+	return c.SessionDir
+}
+
+// GetProfile геттер для имени выбранного профиля.
+func (c config) GetProfile() string {
+	return c.Profile
+}
+
+// GetProfilesPath геттер для пути к файлу профилей.
+func (c config) GetProfilesPath() string {
+	return c.ProfilesPath
+}
+
+// GetArgs геттер для аргументов после флагов: команда неинтерактивного режима.
+func (c config) GetArgs() []string {
+	return c.args
+}
+
+// GetLogin геттер для логина неинтерактивного режима.
+func (c config) GetLogin() string {
+	return c.Login
+}
+
+// GetPassword геттер для пароля неинтерактивного режима.
+func (c config) GetPassword() string {
+	return c.Password
+}
+
+// GetMasterPassword геттер для мастер-пароля неинтерактивного режима.
+func (c config) GetMasterPassword() string {
+	return c.MasterPassword
+}
+
+// GetOTP геттер для кода 2FA неинтерактивного режима.
+func (c config) GetOTP() string {
+	return c.OTP
+}
+
+// GetExportPassphrase геттер для парольной фразы архивов неинтерактивного режима.
+func (c config) GetExportPassphrase() string {
+	return c.ExportPassphrase
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"testing"
 
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,3 +43,82 @@ func TestConfig_GetServerAddress(t *testing.T) {
 
 	assert.Equal(t, "127.0.0.1:9090", cfg.GetServerAddress())
 }
+
+func TestConfig_GetCacheDir(t *testing.T) {
+	t.Setenv("CACHE_DIR", "/tmp/gophkeeper")
+
+	cfg := new(config)
+	err := cfg.initEnv()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/gophkeeper", cfg.GetCacheDir())
+}
+
+func TestConfig_GetSessionDir(t *testing.T) {
+	t.Setenv("SESSION_DIR", "/tmp/gophkeeper-sessions")
+
+	cfg := new(config)
+	err := cfg.initEnv()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/gophkeeper-sessions", cfg.GetSessionDir())
+}
+
+func TestConfig_applyProfile(t *testing.T) {
+	profiles := &entity.Profiles{
+		Current: "personal",
+		Items: map[string]entity.Profile{
+			"personal": {Address: "home:8080", RootCertPath: "/etc/home.pem"},
+			"team":     {Address: "vault.corp:443", RootCertPath: "/etc/corp.pem", Login: "alice", CacheDir: "/tmp/team"},
+		},
+	}
+	noEnv := func(string) (string, bool) { return "", false }
+	defaults := func() *config {
+		return &config{
+			ServerAddress: "localhost:8080", RootCertPath: "./ca.pem", CacheDir: "/cfg", explicit: map[string]bool{},
+		}
+	}
+
+	t.Run("текущий профиль", func(t *testing.T) {
+		cfg := defaults()
+		assert.NoError(t, cfg.applyProfile(profiles, noEnv))
+		assert.Equal(t, "personal", cfg.GetProfile())
+		assert.Equal(t, "home:8080", cfg.GetServerAddress())
+		assert.Equal(t, "/etc/home.pem", cfg.GetRootCertPath())
+		assert.Equal(t, "/cfg/profiles/personal", cfg.GetCacheDir())
+	})
+
+	t.Run("профиль из флага", func(t *testing.T) {
+		cfg := defaults()
+		cfg.Profile = "team"
+		assert.NoError(t, cfg.applyProfile(profiles, noEnv))
+		assert.Equal(t, "vault.corp:443", cfg.GetServerAddress())
+		assert.Equal(t, "/tmp/team", cfg.GetCacheDir())
+		assert.Equal(t, "alice", cfg.GetLogin())
+	})
+
+	t.Run("флаги и env важнее профиля", func(t *testing.T) {
+		cfg := defaults()
+		cfg.Profile = "team"
+		cfg.explicit["a"] = true
+		cfg.Login = "bob"
+		env := func(name string) (string, bool) { return "bob", name == "GOPHKEEPER_LOGIN" }
+		assert.NoError(t, cfg.applyProfile(profiles, env))
+		assert.Equal(t, "localhost:8080", cfg.GetServerAddress())
+		assert.Equal(t, "/etc/corp.pem", cfg.GetRootCertPath())
+		assert.Equal(t, "bob", cfg.GetLogin())
+	})
+
+	t.Run("без профилей", func(t *testing.T) {
+		cfg := defaults()
+		assert.NoError(t, cfg.applyProfile(&entity.Profiles{}, noEnv))
+		assert.Equal(t, "default", cfg.GetProfile())
+		assert.Equal(t, "localhost:8080", cfg.GetServerAddress())
+	})
+
+	t.Run("неизвестный профиль", func(t *testing.T) {
+		cfg := defaults()
+		cfg.Profile = "nope"
+		assert.ErrorContains(t, cfg.applyProfile(profiles, noEnv), "профиль \"nope\" не найден")
+	})
+}
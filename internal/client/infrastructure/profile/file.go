@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+type fileStore struct {
+	path string
+}
+
+// NewFileStore - конструктор хранилища профилей в JSON-файле path.
+func NewFileStore(path string) *fileStore {
+	return &fileStore{path: path}
+}
+
+// Load - читает профили. Отсутствующий файл означает, что профилей нет.
+func (s *fileStore) Load() (*entity.Profiles, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &entity.Profiles{Items: make(map[string]entity.Profile)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения профилей: %w", err)
+	}
+
+	p := &entity.Profiles{}
+	if err := json.Unmarshal(content, p); err != nil {
+		return nil, fmt.Errorf("ошибка разбора профилей %s: %w", s.path, err)
+	}
+	if p.Items == nil {
+		p.Items = make(map[string]entity.Profile)
+	}
+	for name := range p.Items {
+		if !entity.ValidProfileName(name) {
+			return nil, fmt.Errorf("некорректное имя профиля %q в %s", name, s.path)
+		}
+	}
+
+	return p, nil
+}
+
+// Save - атомарно перезаписывает файл профилей.
+func (s *fileStore) Save(p *entity.Profiles) error {
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации профилей: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("ошибка создания каталога профилей: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".profiles-*")
+	if err != nil {
+		return fmt.Errorf("ошибка записи профилей: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ошибка записи профилей: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ошибка записи профилей: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("ошибка записи профилей: %w", err)
+	}
+
+	return nil
+}
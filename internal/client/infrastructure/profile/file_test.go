@@ -0,0 +1,50 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_SaveLoad(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "gophkeeper", "profiles.json"))
+
+	empty, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, empty.Items)
+	assert.Empty(t, empty.Current)
+
+	p := &entity.Profiles{
+		Current: "team",
+		Items: map[string]entity.Profile{
+			"personal": {Address: "home:8080", RootCertPath: "/etc/home.pem"},
+			"team":     {Address: "vault.corp:443", RootCertPath: "/etc/corp.pem", Login: "alice", CacheDir: "/tmp/team"},
+		},
+	}
+	require.NoError(t, store.Save(p))
+
+	loaded, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, p, loaded)
+}
+
+func TestFileStore_LoadCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	require.NoError(t, os.WriteFile(path, []byte("["), 0o600))
+
+	_, err := NewFileStore(path).Load()
+	assert.ErrorContains(t, err, "ошибка разбора профилей")
+}
+
+func TestFileStore_LoadInvalidName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	content := `{"profiles": {"..": {"address": "x:1"}}, "current": ".."}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	_, err := NewFileStore(path).Load()
+	assert.ErrorContains(t, err, `некорректное имя профиля ".."`)
+}
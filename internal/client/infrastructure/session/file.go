@@ -0,0 +1,105 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+// filePerm - файл сессии доступен только владельцу.
+const filePerm = 0o600
+
+type fileStore struct {
+	dir string
+}
+
+// NewFileStore - конструктор хранилища сессий: сессия каждого профиля
+// лежит в отдельном файле в каталоге dir.
+func NewFileStore(dir string) *fileStore {
+	return &fileStore{dir: dir}
+}
+
+// Load - читает сессию профиля. Если файла нет, возвращает nil без ошибки.
+// Файл, доступный группе или другим пользователям, не читается.
+func (s *fileStore) Load(profile string) (*entity.SessionFile, error) {
+	path := s.path(profile)
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла сессии: %w", err)
+	}
+	if info.Mode().Perm()&^filePerm != 0 {
+		return nil, fmt.Errorf(
+			"небезопасные права на файл сессии %s: %o, ожидается %o", path, info.Mode().Perm(), filePerm,
+		)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла сессии: %w", err)
+	}
+
+	f := &entity.SessionFile{}
+	if err := json.Unmarshal(content, f); err != nil {
+		return nil, fmt.Errorf("ошибка разбора файла сессии: %w", err)
+	}
+
+	return f, nil
+}
+
+// Save - атомарно перезаписывает сессию профиля с правами 0600.
+func (s *fileStore) Save(profile string, f *entity.SessionFile) error {
+	content, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации сессии: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("ошибка создания каталога сессий: %w", err)
+	}
+
+	// CreateTemp создаёт файл с правами 0600, поэтому токены не бывают видны другим.
+	tmp, err := os.CreateTemp(s.dir, ".session-*")
+	if err != nil {
+		return fmt.Errorf("ошибка записи сессии: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ошибка записи сессии: %w", err)
+	}
+	if err := tmp.Chmod(filePerm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ошибка записи сессии: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ошибка записи сессии: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(profile)); err != nil {
+		return fmt.Errorf("ошибка записи сессии: %w", err)
+	}
+
+	return nil
+}
+
+// Remove - удаляет сессию профиля. Отсутствие файла не считается ошибкой.
+func (s *fileStore) Remove(profile string) error {
+	err := os.Remove(s.path(profile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("ошибка удаления файла сессии: %w", err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) path(profile string) string {
+	return filepath.Join(s.dir, "session-"+profile+".json")
+}
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_SaveLoadRemove(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gophkeeper")
+	store := NewFileStore(dir)
+
+	missing, err := store.Load("default")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	f := &entity.SessionFile{
+		ServerAddress: "localhost:8080",
+		Sealed:        "sealed",
+		KDF:           entity.KDFParams{Salt: []byte("salt"), Time: 1, Memory: 1024, Threads: 1},
+	}
+	require.NoError(t, store.Save("default", f))
+
+	loaded, err := store.Load("default")
+	require.NoError(t, err)
+	assert.Equal(t, f, loaded)
+
+	info, err := os.Stat(filepath.Join(dir, "session-default.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	require.NoError(t, store.Remove("default"))
+	require.NoError(t, store.Remove("default"))
+	missing, err = store.Load("default")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestFileStore_LoadRejectsOpenPermissions(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+	require.NoError(t, store.Save("default", &entity.SessionFile{Sealed: "sealed"}))
+	require.NoError(t, os.Chmod(filepath.Join(dir, "session-default.json"), 0o644))
+
+	_, err := store.Load("default")
+	assert.ErrorContains(t, err, "небезопасные права")
+}
+
+func TestFileStore_LoadCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "session-default.json"), []byte("{"), 0o600))
+
+	_, err := NewFileStore(dir).Load("default")
+	assert.ErrorContains(t, err, "ошибка разбора файла сессии")
+}
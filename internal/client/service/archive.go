@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/archive"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/client/importer"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	archivePageSize = 100
+	// inlineBinaryLimit - файлы больше этого при импорте загружаются стримом, как upload.
+	inlineBinaryLimit = 1 << 20
+)
+
+type archiveDataService interface {
+	ListData(ctx context.Context, token string, req *datapb.ListDataRequest) (*datapb.ListDataResponse, error)
+	GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error)
+	AddData(ctx context.Context, token string, data *datapb.DataItem) (int32, error)
+	DownloadBinary(ctx context.Context, token string, id int32, dst io.Writer) (*datapb.DataItem, error)
+	UploadBinary(ctx context.Context, token string, item *datapb.DataItem, src io.ReadSeeker) (int32, error)
+}
+
+type archiveService struct {
+	dataService archiveDataService
+	now         func() time.Time
+}
+
+// NewArchiveService - конструктор экспорта и импорта личных записей:
+// в зашифрованный паролем архив (формат описан в пакете archive)
+// и из экспорта других менеджеров паролей.
+func NewArchiveService(dataService archiveDataService) *archiveService {
+	return &archiveService{dataService: dataService, now: time.Now}
+}
+
+// Export - пишет в w архив со всеми личными записями, включая содержимое
+// файлов из upload, и возвращает число записей. Файлы держатся в памяти.
+func (s *archiveService) Export(ctx context.Context, token, passphrase string, w io.Writer) (int, error) {
+	items, err := s.allItems(ctx, token, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := archive.Write(w, passphrase, items, s.now()); err != nil {
+		return 0, err
+	}
+
+	return len(items), nil
+}
+
+// Import - добавляет записи из архива. Дубликат - запись с тем же типом,
+// описанием и содержимым, что уже есть на сервере или раньше в файле;
+// файлы сравниваются по имени и MIME. При dryRun ничего не добавляется,
+// отчёт показывает, что было бы добавлено.
+func (s *archiveService) Import(
+	ctx context.Context, token, passphrase string, r io.Reader, dryRun bool,
+) ([]entity.ImportEntry, error) {
+	contents, err := archive.Read(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importItems(ctx, token, contents.Items, nil, dryRun)
+}
+
+// ImportForeign - добавляет записи из экспорта другого менеджера паролей
+// в формате format (см. пакет importer). Дубликаты пропускаются, как в Import.
+func (s *archiveService) ImportForeign(
+	ctx context.Context, token, format string, r io.Reader, dryRun bool,
+) ([]entity.ImportEntry, error) {
+	result, err := importer.Parse(format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importItems(ctx, token, result.Items, result.Skipped, dryRun)
+}
+
+// importItems - добавляет items, которых ещё нет на сервере, и возвращает
+// отчёт по каждой записи; skipped дописываются в конец отчёта.
+func (s *archiveService) importItems(
+	ctx context.Context, token string, items []*datapb.DataItem, skipped []importer.Skipped, dryRun bool,
+) ([]entity.ImportEntry, error) {
+	existing, err := s.allItems(ctx, token, false)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[[sha256.Size]byte]bool, len(existing))
+	for _, item := range existing {
+		seen[fingerprint(item)] = true
+	}
+
+	report := make([]entity.ImportEntry, 0, len(items)+len(skipped))
+	for _, item := range items {
+		entry := entity.ImportEntry{InfoType: item.InfoType, Title: importTitle(item.Meta)}
+		key := fingerprint(item)
+		invalid := payload.Validate(item)
+		switch {
+		case invalid != nil:
+			entry.Status, entry.Reason = entity.ImportSkipped, invalid.Error()
+		case seen[key]:
+			entry.Status = entity.ImportDuplicate
+		case dryRun:
+			seen[key] = true
+			entry.Status = entity.ImportNew
+		default:
+			seen[key] = true
+			if err := s.add(ctx, token, item); err != nil {
+				return nil, fmt.Errorf("ошибка импорта записи %q: %w", entry.Title, err)
+			}
+			entry.Status = entity.ImportAdded
+		}
+		report = append(report, entry)
+	}
+	for _, skip := range skipped {
+		report = append(report, entity.ImportEntry{Status: entity.ImportSkipped, Title: skip.Title, Reason: skip.Reason})
+	}
+
+	return report, nil
+}
+
+// importTitle - название записи в отчёте: первая строка описания.
+func importTitle(meta string) string {
+	title, _, _ := strings.Cut(meta, "\n")
+	return title
+}
+
+// allItems - все личные записи целиком. withFiles - докачать содержимое
+// файлов, загруженных стримом: GetData возвращает только их описание.
+func (s *archiveService) allItems(ctx context.Context, token string, withFiles bool) ([]*datapb.DataItem, error) {
+	var items []*datapb.DataItem
+	req := &datapb.ListDataRequest{PageSize: archivePageSize}
+	for {
+		res, err := s.dataService.ListData(ctx, token, req)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения списка данных: %w", err)
+		}
+		for _, header := range res.Items {
+			item, err := s.dataService.GetData(ctx, token, header.Id)
+			if err != nil {
+				return nil, fmt.Errorf("ошибка получения записи %d: %w", header.Id, err)
+			}
+			if withFiles {
+				if err := s.fillFile(ctx, token, item); err != nil {
+					return nil, err
+				}
+			}
+			items = append(items, item)
+		}
+		if res.NextCursor == "" {
+			return items, nil
+		}
+		req.Cursor = res.NextCursor
+	}
+}
+
+// fillFile - скачивает содержимое файла, если запись - описание файла из upload.
+func (s *archiveService) fillFile(ctx context.Context, token string, item *datapb.DataItem) error {
+	file := item.GetBinary()
+	if file == nil || len(file.Bytes) > 0 {
+		return nil
+	}
+
+	var content bytes.Buffer
+	_, err := s.dataService.DownloadBinary(ctx, token, item.Id, &content)
+	if status.Code(err) == codes.NotFound {
+		// Обычная запись с пустым файлом: потока у неё нет.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка скачивания файла %d: %w", item.Id, err)
+	}
+	file.Bytes = content.Bytes()
+
+	return nil
+}
+
+func (s *archiveService) add(ctx context.Context, token string, item *datapb.DataItem) error {
+	if file := item.GetBinary(); file != nil && len(file.Bytes) > inlineBinaryLimit {
+		_, err := s.dataService.UploadBinary(ctx, token, item, bytes.NewReader(file.Bytes))
+		return err
+	}
+
+	_, err := s.dataService.AddData(ctx, token, &datapb.DataItem{
+		InfoType: item.InfoType,
+		Meta:     item.Meta,
+		Created:  item.Created,
+		Payload:  item.Payload,
+		Tags:     item.Tags,
+		Folder:   item.Folder,
+		Favorite: item.Favorite,
+	})
+	return err
+}
+
+// fingerprint - отпечаток записи для поиска дубликатов. Запись приводится
+// к виду, в котором она попадает в архив, чтобы записи с сервера и из
+// архива сравнивались одинаково.
+func fingerprint(item *datapb.DataItem) [sha256.Size]byte {
+	item = archive.ExportItem(item)
+	key := &datapb.DataItem{InfoType: item.InfoType, Meta: item.Meta, Payload: item.Payload}
+	if file := item.GetBinary(); file != nil {
+		key.Payload = &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: file.Filename, Mime: file.Mime}}
+	}
+	encoded, _ := proto.MarshalOptions{Deterministic: true}.Marshal(key)
+
+	return sha256.Sum256(encoded)
+}
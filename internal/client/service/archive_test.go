@@ -0,0 +1,206 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/client/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// memoryDataService - личные записи в памяти; files - содержимое файлов из upload.
+type memoryDataService struct {
+	items    []*datapb.DataItem
+	files    map[int32][]byte
+	uploaded int
+}
+
+func (m *memoryDataService) ListData(
+	_ context.Context, _ string, req *datapb.ListDataRequest,
+) (*datapb.ListDataResponse, error) {
+	page := m.items[:min(len(m.items), 1)]
+	if req.Cursor != "" {
+		page = m.items[1:]
+	}
+	res := &datapb.ListDataResponse{}
+	for _, item := range page {
+		res.Items = append(res.Items, &datapb.DataHeader{Id: item.Id, InfoType: item.InfoType, Meta: item.Meta})
+	}
+	if req.Cursor == "" && len(m.items) > 1 {
+		res.NextCursor = "next"
+	}
+	return res, nil
+}
+
+func (m *memoryDataService) GetData(_ context.Context, _ string, id int32) (*datapb.DataItem, error) {
+	for _, item := range m.items {
+		if item.Id == id {
+			return proto.Clone(item).(*datapb.DataItem), nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "не найдено")
+}
+
+func (m *memoryDataService) AddData(_ context.Context, _ string, data *datapb.DataItem) (int32, error) {
+	data.Id = int32(len(m.items) + 1)
+	m.items = append(m.items, data)
+	return data.Id, nil
+}
+
+func (m *memoryDataService) DownloadBinary(
+	_ context.Context, _ string, id int32, dst io.Writer,
+) (*datapb.DataItem, error) {
+	content, ok := m.files[id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "файл не найден")
+	}
+	_, err := dst.Write(content)
+	return nil, err
+}
+
+func (m *memoryDataService) UploadBinary(
+	_ context.Context, _ string, item *datapb.DataItem, src io.ReadSeeker,
+) (int32, error) {
+	content, err := io.ReadAll(src)
+	if err != nil {
+		return 0, err
+	}
+	m.uploaded++
+	id := int32(len(m.items) + 1)
+	m.items = append(m.items, &datapb.DataItem{
+		Id: id, InfoType: item.InfoType, Meta: item.Meta, Created: item.Created,
+		Payload: &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: item.GetBinary().GetFilename()}},
+	})
+	m.files[id] = content
+	return id, nil
+}
+
+func textItem(id int32, meta, content string) *datapb.DataItem {
+	return &datapb.DataItem{
+		Id: id, InfoType: "text", Meta: meta,
+		Payload: &datapb.DataItem_Text{Text: &datapb.Text{Content: content}},
+	}
+}
+
+func TestArchiveService_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	big := bytes.Repeat([]byte("x"), inlineBinaryLimit+1)
+	source := &memoryDataService{
+		items: []*datapb.DataItem{
+			textItem(1, "заметка", "код домофона"),
+			{
+				Id: 2, InfoType: "binary", Meta: "скан",
+				Payload: &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: "scan.pdf"}},
+			},
+			textItem(3, "пустая", ""),
+		},
+		files: map[int32][]byte{2: big},
+	}
+
+	var buf bytes.Buffer
+	exported, err := NewArchiveService(source).Export(ctx, "token", "пароль", &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 3, exported)
+	archived := buf.Bytes()
+
+	target := &memoryDataService{
+		items: []*datapb.DataItem{textItem(1, "заметка", "код домофона")},
+		files: map[int32][]byte{},
+	}
+	svc := NewArchiveService(target)
+
+	report, err := svc.Import(ctx, "token", "пароль", bytes.NewReader(archived), true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{entity.ImportDuplicate, entity.ImportNew, entity.ImportNew}, importStatuses(report))
+	assert.Len(t, target.items, 1, "dry-run ничего не добавляет")
+
+	report, err = svc.Import(ctx, "token", "пароль", bytes.NewReader(archived), false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{entity.ImportDuplicate, entity.ImportAdded, entity.ImportAdded}, importStatuses(report))
+	require.Len(t, target.items, 3)
+	assert.Equal(t, 1, target.uploaded, "большой файл загружается стримом")
+	assert.Equal(t, big, target.files[2])
+	assert.Equal(t, "пустая", target.items[2].Meta)
+
+	report, err = svc.Import(ctx, "token", "пароль", bytes.NewReader(archived), false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{entity.ImportDuplicate, entity.ImportDuplicate, entity.ImportDuplicate}, importStatuses(report))
+}
+
+func TestArchiveService_ImportLegacyAndCreated(t *testing.T) {
+	ctx := context.Background()
+	created := timestamppb.New(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	source := &memoryDataService{
+		items: []*datapb.DataItem{
+			{Id: 1, InfoType: "text", Meta: "старая заметка", Info: "старый текст", Created: created},
+			{
+				Id: 2, InfoType: "binary", Meta: "скан", Created: created,
+				Payload: &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: "scan.pdf"}},
+			},
+		},
+		files: map[int32][]byte{2: bytes.Repeat([]byte("x"), inlineBinaryLimit+1)},
+	}
+	svc := NewArchiveService(source)
+
+	var buf bytes.Buffer
+	_, err := svc.Export(ctx, "token", "пароль", &buf)
+	require.NoError(t, err)
+	archived := buf.Bytes()
+
+	report, err := svc.Import(ctx, "token", "пароль", bytes.NewReader(archived), false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{entity.ImportDuplicate, entity.ImportDuplicate}, importStatuses(report))
+	assert.Len(t, source.items, 2)
+
+	target := &memoryDataService{files: map[int32][]byte{}}
+	_, err = NewArchiveService(target).Import(ctx, "token", "пароль", bytes.NewReader(archived), false)
+	require.NoError(t, err)
+	require.Len(t, target.items, 2)
+	assert.Equal(t, "старый текст", target.items[0].GetText().GetContent())
+	for _, item := range target.items {
+		assert.True(t, proto.Equal(created, item.Created), "запись %q: %v", item.Meta, item.Created)
+	}
+}
+
+func TestArchiveService_ImportForeign(t *testing.T) {
+	export := "name,login,password,notes\n" +
+		"почта,user,secret,\"рабочий\nящик\"\n" +
+		"заметка,,,код домофона\n" +
+		"пустая,,,\n"
+	target := &memoryDataService{
+		items: []*datapb.DataItem{textItem(1, "заметка", "код домофона")},
+		files: map[int32][]byte{},
+	}
+
+	report, err := NewArchiveService(target).ImportForeign(
+		context.Background(), "token", importer.FormatGenericCSV, strings.NewReader(export), false,
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []entity.ImportEntry{
+		{Status: entity.ImportAdded, InfoType: "login_password", Title: "почта"},
+		{Status: entity.ImportDuplicate, InfoType: "text", Title: "заметка"},
+		{Status: entity.ImportSkipped, Title: "пустая", Reason: "пустая запись"},
+	}, report)
+	require.Len(t, target.items, 2)
+	assert.Equal(t, "user", target.items[1].GetLoginPassword().GetLogin())
+}
+
+func importStatuses(report []entity.ImportEntry) []string {
+	statuses := make([]string, len(report))
+	for i, entry := range report {
+		statuses[i] = entry.Status
+	}
+	return statuses
+}
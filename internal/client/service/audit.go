@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NikolosHGW/goph-keeper/api/auditpb"
+)
+
+type auditService struct {
+	client auditpb.AuditServiceClient
+}
+
+// NewAuditService - конструктор клиента журнала аудита.
+func NewAuditService(grpcClient *GRPCClient) *auditService {
+	return &auditService{client: grpcClient.AuditClient}
+}
+
+// ListEvents - возвращает до limit событий журнала от новых к старым.
+// beforeID > 0 - только события старше события с этим id.
+func (s *auditService) ListEvents(
+	ctx context.Context, token string, beforeID int64, limit int32,
+) ([]*auditpb.AuditEvent, error) {
+	res, err := s.client.ListAuditEvents(withToken(ctx, token), &auditpb.ListAuditEventsRequest{
+		Limit:    limit,
+		BeforeId: beforeID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении журнала: %w", err)
+	}
+
+	return res.Events, nil
+}
@@ -3,47 +3,205 @@ package service
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/NikolosHGW/goph-keeper/api/authpb"
 	"github.com/NikolosHGW/goph-keeper/api/registerpb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type authService struct {
 	registerClient registerpb.RegisterClient
 	authClient     authpb.AuthClient
 	logger         logger.CustomLogger
+	deviceName     string
 }
 
 func NewAuthService(grpcClient *GRPCClient, logger logger.CustomLogger) *authService {
+	deviceName, err := os.Hostname()
+	if err != nil {
+		deviceName = "unknown"
+	}
+
 	return &authService{
 		registerClient: grpcClient.RegisterClient,
 		authClient:     grpcClient.AuthClient,
 		logger:         logger,
+		deviceName:     deviceName,
 	}
 }
 
-func (s *authService) Register(ctx context.Context, login, password string) (string, error) {
+func (s *authService) Register(
+	ctx context.Context, login, password string,
+) (*entity.AuthTokens, *entity.KDFParams, error) {
 	req := &registerpb.RegisterUserRequest{
-		Login:    login,
-		Password: password,
+		Login:      login,
+		Password:   password,
+		DeviceName: s.deviceName,
 	}
 	resp, err := s.registerClient.RegisterUser(ctx, req)
 	if err != nil {
 		s.logger.LogInfo("Ошибка регистрации", err)
-		return "", fmt.Errorf("ошибка при регистрации: %w", err)
+		return nil, nil, fmt.Errorf("ошибка при регистрации: %w", err)
+	}
+	tokens := &entity.AuthTokens{
+		AccessToken:  resp.BearerToken,
+		RefreshToken: resp.RefreshToken,
+		ExpiresAt:    timeOrZero(resp.AccessExpiresAt),
 	}
-	return resp.BearerToken, nil
+	return tokens, registerKDFToEntity(resp.Kdf), nil
 }
 
-func (s *authService) Login(ctx context.Context, login, password string) (string, error) {
+func (s *authService) Login(ctx context.Context, login, password string) (*entity.AuthTokens, *entity.KDFParams, error) {
 	req := &authpb.LoginUserRequest{
-		Login:    login,
-		Password: password,
+		Login:      login,
+		Password:   password,
+		DeviceName: s.deviceName,
 	}
 	res, err := s.authClient.LoginUser(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("ошибка при логине: %w", err)
+		return nil, nil, fmt.Errorf("ошибка при логине: %w", err)
+	}
+	if res.Challenge != "" {
+		return nil, nil, &entity.TwoFactorRequiredError{Challenge: res.Challenge}
+	}
+
+	return loginResult(res)
+}
+
+// CompleteLogin - завершает вход с 2FA кодом из аутентификатора или кодом восстановления.
+func (s *authService) CompleteLogin(
+	ctx context.Context, challenge, code string,
+) (*entity.AuthTokens, *entity.KDFParams, error) {
+	res, err := s.authClient.CompleteLogin(ctx, &authpb.CompleteLoginRequest{Challenge: challenge, Code: code})
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка при проверке кода: %w", err)
+	}
+
+	return loginResult(res)
+}
+
+// EnrollTOTP - начинает подключение аутентификатора.
+func (s *authService) EnrollTOTP(ctx context.Context, token string) (*entity.TOTPEnrollment, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	resp, err := s.authClient.EnrollTotp(ctx, &authpb.EnrollTotpRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при подключении аутентификатора: %w", err)
+	}
+
+	return &entity.TOTPEnrollment{
+		URI:           resp.OtpauthUri,
+		Secret:        resp.Secret,
+		RecoveryCodes: resp.RecoveryCodes,
+	}, nil
+}
+
+// ConfirmTOTP - включает 2FA первым кодом из аутентификатора.
+func (s *authService) ConfirmTOTP(ctx context.Context, token, code string) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	_, err := s.authClient.ConfirmTotp(ctx, &authpb.ConfirmTotpRequest{Code: code})
+	if err != nil {
+		return fmt.Errorf("ошибка при включении 2FA: %w", err)
+	}
+
+	return nil
+}
+
+// DisableTOTP - выключает 2FA.
+func (s *authService) DisableTOTP(ctx context.Context, token, code string) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	_, err := s.authClient.DisableTotp(ctx, &authpb.DisableTotpRequest{Code: code})
+	if err != nil {
+		return fmt.Errorf("ошибка при выключении 2FA: %w", err)
+	}
+
+	return nil
+}
+
+// SetKeyCheck - сохраняет на сервере проверку мастер-пароля.
+func (s *authService) SetKeyCheck(ctx context.Context, token string, keyCheck []byte) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	_, err := s.authClient.SetKeyCheck(ctx, &authpb.SetKeyCheckRequest{KeyCheck: keyCheck})
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении проверки мастер-пароля: %w", err)
+	}
+
+	return nil
+}
+
+// ListSessions - возвращает активные сессии пользователя.
+func (s *authService) ListSessions(ctx context.Context, token string) ([]*entity.Session, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	resp, err := s.authClient.ListSessions(ctx, &authpb.ListSessionsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении сессий: %w", err)
+	}
+
+	sessions := make([]*entity.Session, 0, len(resp.Sessions))
+	for _, session := range resp.Sessions {
+		sessions = append(sessions, &entity.Session{
+			ID:         session.Id,
+			DeviceName: session.DeviceName,
+			CreatedAt:  session.CreatedAt.AsTime(),
+			LastUsedAt: session.LastUsedAt.AsTime(),
+			Current:    session.Current,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession - отзывает сессию по ID. id = 0 - текущая сессия.
+func (s *authService) RevokeSession(ctx context.Context, token string, id int64) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	_, err := s.authClient.RevokeSession(ctx, &authpb.RevokeSessionRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("ошибка при отзыве сессии: %w", err)
+	}
+
+	return nil
+}
+
+// timeOrZero - в отличие от AsTime, для отсутствующего значения возвращает
+// нулевое время, а не начало эпохи Unix.
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func loginResult(res *authpb.LoginUserResponse) (*entity.AuthTokens, *entity.KDFParams, error) {
+	tokens := &entity.AuthTokens{
+		AccessToken:  res.BearerToken,
+		RefreshToken: res.RefreshToken,
+		ExpiresAt:    timeOrZero(res.AccessExpiresAt),
+	}
+	return tokens, authKDFToEntity(res.Kdf), nil
+}
+
+func registerKDFToEntity(kdf *registerpb.KdfParams) *entity.KDFParams {
+	if kdf == nil {
+		return nil
+	}
+	return &entity.KDFParams{Salt: kdf.Salt, Time: kdf.Time, Memory: kdf.Memory, Threads: kdf.Threads}
+}
+
+func authKDFToEntity(kdf *authpb.KdfParams) *entity.KDFParams {
+	if kdf == nil {
+		return nil
+	}
+	return &entity.KDFParams{
+		Salt: kdf.Salt, KeyCheck: kdf.KeyCheck, Time: kdf.Time, Memory: kdf.Memory, Threads: kdf.Threads,
 	}
-	return res.BearerToken, nil
 }
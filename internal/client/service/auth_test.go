@@ -4,12 +4,19 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/NikolosHGW/goph-keeper/api/authpb"
 	"github.com/NikolosHGW/goph-keeper/api/registerpb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type MockRegisterClient struct {
@@ -35,6 +42,7 @@ func TestAuthService_Register(t *testing.T) {
 		password      string
 		mockRegister  func() *MockRegisterClient
 		expectedToken string
+		expectedKDF   *entity.KDFParams
 		expectedErr   error
 	}{
 		{
@@ -46,11 +54,15 @@ func TestAuthService_Register(t *testing.T) {
 					RegisterUserFunc: func(
 						ctx context.Context, req *registerpb.RegisterUserRequest, opts ...grpc.CallOption,
 					) (*registerpb.RegisterUserResponse, error) {
-						return &registerpb.RegisterUserResponse{BearerToken: "token123"}, nil
+						return &registerpb.RegisterUserResponse{
+							BearerToken: "token123",
+							Kdf:         &registerpb.KdfParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
+						}, nil
 					},
 				}
 			},
 			expectedToken: "token123",
+			expectedKDF:   &entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
 			expectedErr:   nil,
 		},
 		{
@@ -128,10 +140,12 @@ func TestAuthService_Register(t *testing.T) {
 			noOpLogger := &mockLogger{}
 
 			authSvc := NewAuthService(mockGRPCClient, noOpLogger)
+			authSvc.deviceName = ""
 
-			token, err := authSvc.Register(context.Background(), tt.login, tt.password)
+			tokens, kdf, err := authSvc.Register(context.Background(), tt.login, tt.password)
 
-			assert.Equal(t, tt.expectedToken, token)
+			assert.Equal(t, tt.expectedToken, accessToken(tokens))
+			assert.Equal(t, tt.expectedKDF, kdf)
 
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
@@ -158,6 +172,77 @@ func (m *MockAuthClient) LoginUser(
 	return resp, args.Error(1)
 }
 
+func (m *MockAuthClient) RefreshToken(
+	ctx context.Context, req *authpb.RefreshTokenRequest, opts ...grpc.CallOption,
+) (*authpb.RefreshTokenResponse, error) {
+	args := m.Called(ctx, req)
+	resp, _ := args.Get(0).(*authpb.RefreshTokenResponse)
+	return resp, args.Error(1)
+}
+
+func (m *MockAuthClient) ListSessions(
+	ctx context.Context, req *authpb.ListSessionsRequest, opts ...grpc.CallOption,
+) (*authpb.ListSessionsResponse, error) {
+	args := m.Called(ctx, req)
+	resp, _ := args.Get(0).(*authpb.ListSessionsResponse)
+	return resp, args.Error(1)
+}
+
+func (m *MockAuthClient) RevokeSession(
+	ctx context.Context, req *authpb.RevokeSessionRequest, opts ...grpc.CallOption,
+) (*authpb.RevokeSessionResponse, error) {
+	args := m.Called(ctx, req)
+	resp, _ := args.Get(0).(*authpb.RevokeSessionResponse)
+	return resp, args.Error(1)
+}
+
+func (m *MockAuthClient) CompleteLogin(
+	ctx context.Context, req *authpb.CompleteLoginRequest, opts ...grpc.CallOption,
+) (*authpb.LoginUserResponse, error) {
+	args := m.Called(ctx, req)
+	resp, _ := args.Get(0).(*authpb.LoginUserResponse)
+	return resp, args.Error(1)
+}
+
+func (m *MockAuthClient) EnrollTotp(
+	ctx context.Context, req *authpb.EnrollTotpRequest, opts ...grpc.CallOption,
+) (*authpb.EnrollTotpResponse, error) {
+	args := m.Called(ctx, req)
+	resp, _ := args.Get(0).(*authpb.EnrollTotpResponse)
+	return resp, args.Error(1)
+}
+
+func (m *MockAuthClient) ConfirmTotp(
+	ctx context.Context, req *authpb.ConfirmTotpRequest, opts ...grpc.CallOption,
+) (*authpb.ConfirmTotpResponse, error) {
+	args := m.Called(ctx, req)
+	resp, _ := args.Get(0).(*authpb.ConfirmTotpResponse)
+	return resp, args.Error(1)
+}
+
+func (m *MockAuthClient) SetKeyCheck(
+	ctx context.Context, req *authpb.SetKeyCheckRequest, opts ...grpc.CallOption,
+) (*authpb.SetKeyCheckResponse, error) {
+	args := m.Called(ctx, req)
+	resp, _ := args.Get(0).(*authpb.SetKeyCheckResponse)
+	return resp, args.Error(1)
+}
+
+func (m *MockAuthClient) DisableTotp(
+	ctx context.Context, req *authpb.DisableTotpRequest, opts ...grpc.CallOption,
+) (*authpb.DisableTotpResponse, error) {
+	args := m.Called(ctx, req)
+	resp, _ := args.Get(0).(*authpb.DisableTotpResponse)
+	return resp, args.Error(1)
+}
+
+func accessToken(tokens *entity.AuthTokens) string {
+	if tokens == nil {
+		return ""
+	}
+	return tokens.AccessToken
+}
+
 func TestAuthService_Login(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -165,6 +250,7 @@ func TestAuthService_Login(t *testing.T) {
 		password      string
 		mockAuth      func() *MockAuthClient
 		expectedToken string
+		expectedKDF   *entity.KDFParams
 		expectedErr   error
 	}{
 		{
@@ -178,10 +264,12 @@ func TestAuthService_Login(t *testing.T) {
 					Password: "pass1",
 				}, mock.Anything).Return(&authpb.LoginUserResponse{
 					BearerToken: "token123",
+					Kdf:         &authpb.KdfParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
 				}, nil)
 				return mockAuthClient
 			},
 			expectedToken: "token123",
+			expectedKDF:   &entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
 			expectedErr:   nil,
 		},
 		{
@@ -269,10 +357,11 @@ func TestAuthService_Login(t *testing.T) {
 			}
 
 			// Выполнение метода Login
-			token, err := authSvc.Login(context.Background(), tt.login, tt.password)
+			tokens, kdf, err := authSvc.Login(context.Background(), tt.login, tt.password)
 
 			// Проверка результатов
-			assert.Equal(t, tt.expectedToken, token)
+			assert.Equal(t, tt.expectedToken, accessToken(tokens))
+			assert.Equal(t, tt.expectedKDF, kdf)
 
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
@@ -286,3 +375,82 @@ func TestAuthService_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthService_Sessions(t *testing.T) {
+	ctx := context.Background()
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", "token")
+	created := time.Unix(1700000000, 0)
+
+	mockAuthClient := new(MockAuthClient)
+	mockAuthClient.On("ListSessions", ctxWithMetadata, &authpb.ListSessionsRequest{}).
+		Return(&authpb.ListSessionsResponse{Sessions: []*authpb.Session{{
+			Id: 3, DeviceName: "laptop", Current: true,
+			CreatedAt: timestamppb.New(created), LastUsedAt: timestamppb.New(created),
+		}}}, nil)
+	mockAuthClient.On("RevokeSession", ctxWithMetadata, &authpb.RevokeSessionRequest{Id: 4}).
+		Return(nil, status.Error(codes.NotFound, "сессия не найдена"))
+
+	authSvc := &authService{authClient: mockAuthClient, logger: &mockLogger{}}
+
+	sessions, err := authSvc.ListSessions(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.Session{{
+		ID: 3, DeviceName: "laptop", Current: true, CreatedAt: created.UTC(), LastUsedAt: created.UTC(),
+	}}, sessions)
+
+	err = authSvc.RevokeSession(ctx, "token", 4)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	mockAuthClient.AssertExpectations(t)
+}
+
+func TestAuthService_LoginWithTwoFactor(t *testing.T) {
+	ctx := context.Background()
+
+	mockAuthClient := new(MockAuthClient)
+	mockAuthClient.On("LoginUser", ctx, &authpb.LoginUserRequest{Login: "user", Password: "pass"}, mock.Anything).
+		Return(&authpb.LoginUserResponse{Challenge: "challenge"}, nil)
+	mockAuthClient.On("CompleteLogin", ctx, &authpb.CompleteLoginRequest{Challenge: "challenge", Code: "123456"}).
+		Return(&authpb.LoginUserResponse{
+			BearerToken: "token", RefreshToken: "refresh", Kdf: &authpb.KdfParams{Time: 3},
+		}, nil)
+
+	authSvc := &authService{authClient: mockAuthClient, logger: &mockLogger{}}
+
+	_, _, err := authSvc.Login(ctx, "user", "pass")
+	var required *entity.TwoFactorRequiredError
+	require.ErrorAs(t, err, &required)
+	assert.Equal(t, "challenge", required.Challenge)
+
+	tokens, kdf, err := authSvc.CompleteLogin(ctx, required.Challenge, "123456")
+	require.NoError(t, err)
+	assert.Equal(t, &entity.AuthTokens{AccessToken: "token", RefreshToken: "refresh"}, tokens)
+	assert.Equal(t, &entity.KDFParams{Time: 3}, kdf)
+	mockAuthClient.AssertExpectations(t)
+}
+
+func TestAuthService_TOTP(t *testing.T) {
+	ctx := context.Background()
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", "token")
+
+	mockAuthClient := new(MockAuthClient)
+	mockAuthClient.On("EnrollTotp", ctxWithMetadata, &authpb.EnrollTotpRequest{}).
+		Return(&authpb.EnrollTotpResponse{
+			OtpauthUri: "otpauth://totp/x", Secret: "ABC", RecoveryCodes: []string{"aaaa-bbbb"},
+		}, nil)
+	mockAuthClient.On("ConfirmTotp", ctxWithMetadata, &authpb.ConfirmTotpRequest{Code: "123456"}).
+		Return(&authpb.ConfirmTotpResponse{}, nil)
+	mockAuthClient.On("DisableTotp", ctxWithMetadata, &authpb.DisableTotpRequest{Code: "000000"}).
+		Return(nil, status.Error(codes.InvalidArgument, "неверный код подтверждения"))
+
+	authSvc := &authService{authClient: mockAuthClient, logger: &mockLogger{}}
+
+	enrollment, err := authSvc.EnrollTOTP(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, &entity.TOTPEnrollment{
+		URI: "otpauth://totp/x", Secret: "ABC", RecoveryCodes: []string{"aaaa-bbbb"},
+	}, enrollment)
+
+	assert.NoError(t, authSvc.ConfirmTOTP(ctx, "token", "123456"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(authSvc.DisableTOTP(ctx, "token", "000000")))
+	mockAuthClient.AssertExpectations(t)
+}
@@ -0,0 +1,673 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/label"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type remoteDataService interface {
+	plainDataService
+	GetChanges(ctx context.Context, token string, since int64) (*datapb.GetChangesResponse, error)
+}
+
+type cacheStore interface {
+	Load(name string) (*entity.Cache, error)
+	Save(name string, c *entity.Cache) error
+}
+
+type cachedDataService struct {
+	remote    remoteDataService
+	store     cacheStore
+	keyHolder *entity.KeyHolder
+	mu        sync.Mutex
+}
+
+// NewCachedDataService - конструктор сервиса данных с локальным кешем.
+// Стоит под шифрующей обёрткой, поэтому кеш хранит уже зашифрованные записи.
+// Без связи с сервером чтение идёт из кеша, а изменения копятся в очереди до Sync.
+// Записи общих хранилищ в кеш не попадают и доступны только онлайн.
+func NewCachedDataService(
+	remote remoteDataService, store cacheStore, keyHolder *entity.KeyHolder,
+) *cachedDataService {
+	return &cachedDataService{remote: remote, store: store, keyHolder: keyHolder}
+}
+
+func (s *cachedDataService) AddData(ctx context.Context, token string, data *datapb.DataItem) (int32, error) {
+	if data.VaultId != 0 {
+		return s.remote.AddData(ctx, token, data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, name, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.remote.AddData(ctx, token, data)
+	if err == nil {
+		item := cachedFromProto(data)
+		item.ID = id
+		item.Version = 1
+		c.Items[id] = item
+		return id, s.store.Save(name, c)
+	}
+	if !isOffline(err) {
+		return 0, err
+	}
+
+	c.LastLocalID--
+	item := cachedFromProto(data)
+	item.ID = c.LastLocalID
+	c.Items[item.ID] = item
+	c.Queue = append(c.Queue, entity.PendingOperation{Kind: entity.OperationAdd, Item: item})
+
+	return item.ID, s.store.Save(name, c)
+}
+
+func (s *cachedDataService) GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, name, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if id > 0 && !hasPending(c, id) {
+		data, err := s.remote.GetData(ctx, token, id)
+		if err == nil && data.VaultId != 0 {
+			return data, nil
+		}
+		if err == nil {
+			c.Items[id] = cachedFromProto(data)
+			return data, s.store.Save(name, c)
+		}
+		if !isOffline(err) {
+			return nil, err
+		}
+	}
+
+	item, ok := c.Items[id]
+	if !ok {
+		return nil, fmt.Errorf("запись %d не найдена в локальном кеше", id)
+	}
+
+	return cachedToProto(item), nil
+}
+
+func (s *cachedDataService) UpdateData(ctx context.Context, token string, data *datapb.DataItem) error {
+	if data.VaultId != 0 {
+		return s.remote.UpdateData(ctx, token, data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, name, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if data.Id > 0 && !hasPending(c, data.Id) {
+		err = s.remote.UpdateData(ctx, token, data)
+		if err == nil {
+			// Сервер увеличивает версию ровно на единицу при каждом изменении.
+			item := cachedFromProto(data)
+			item.Version++
+			c.Items[data.Id] = item
+			return s.store.Save(name, c)
+		}
+		if !isOffline(err) {
+			return err
+		}
+	}
+
+	current, ok := c.Items[data.Id]
+	if !ok {
+		return fmt.Errorf("запись %d не найдена в локальном кеше", data.Id)
+	}
+
+	item := cachedFromProto(data)
+	item.Created = current.Created
+	c.Items[data.Id] = item
+	c.Queue = enqueueUpdate(c.Queue, item)
+
+	return s.store.Save(name, c)
+}
+
+func (s *cachedDataService) DeleteData(ctx context.Context, token string, id int32, version int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, name, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if id > 0 && !hasPending(c, id) {
+		err = s.remote.DeleteData(ctx, token, id, version)
+		if err == nil {
+			delete(c.Items, id)
+			return s.store.Save(name, c)
+		}
+		if !isOffline(err) {
+			return err
+		}
+	}
+
+	if _, ok := c.Items[id]; !ok {
+		return fmt.Errorf("запись %d не найдена в локальном кеше", id)
+	}
+
+	delete(c.Items, id)
+	c.Queue = enqueueDelete(c.Queue, id, version)
+
+	return s.store.Save(name, c)
+}
+
+func (s *cachedDataService) ListData(
+	ctx context.Context, token string, req *datapb.ListDataRequest,
+) (*datapb.ListDataResponse, error) {
+	if req.VaultId != 0 {
+		return s.remote.ListData(ctx, token, req)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.Queue) == 0 {
+		res, err := s.remote.ListData(ctx, token, req)
+		if err == nil || !isOffline(err) {
+			return res, err
+		}
+	}
+
+	return listCached(c, req), nil
+}
+
+// ListFolders - без связи с сервером или с неотправленными изменениями
+// папки собираются по записям кеша.
+func (s *cachedDataService) ListFolders(ctx context.Context, token string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.Queue) == 0 {
+		folders, err := s.remote.ListFolders(ctx, token)
+		if err == nil || !isOffline(err) {
+			return folders, err
+		}
+	}
+
+	return foldersCached(c), nil
+}
+
+// MoveData - перенос между хранилищами выполняется только онлайн и только
+// для записей без неотправленных изменений. Запись, ушедшая в общее хранилище,
+// удаляется из кеша, вернувшаяся в личные данные - кешируется с новой версией.
+func (s *cachedDataService) MoveData(
+	ctx context.Context, token string, data *datapb.DataItem, vaultID int32,
+) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, name, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	if hasPending(c, data.Id) {
+		return 0, fmt.Errorf("у записи %d есть неотправленные изменения, выполните sync", data.Id)
+	}
+
+	version, err := s.remote.MoveData(ctx, token, data, vaultID)
+	if err != nil {
+		return 0, err
+	}
+
+	if vaultID != 0 {
+		delete(c.Items, data.Id)
+	} else {
+		item := cachedFromProto(data)
+		item.Version = version
+		c.Items[data.Id] = item
+	}
+
+	return version, s.store.Save(name, c)
+}
+
+// SearchData - индекс поиска хранится только на сервере, поэтому поиск
+// выполняется только онлайн.
+func (s *cachedDataService) SearchData(
+	ctx context.Context, token string, req *datapb.SearchDataRequest,
+) (*datapb.SearchDataResponse, error) {
+	return s.remote.SearchData(ctx, token, req)
+}
+
+// IndexData - токены поиска не меняют версию записи и в кеш не попадают.
+func (s *cachedDataService) IndexData(ctx context.Context, token string, id int32, tokens []string) error {
+	return s.remote.IndexData(ctx, token, id, tokens)
+}
+
+// ListVersions - история записей хранится только на сервере.
+func (s *cachedDataService) ListVersions(
+	ctx context.Context, token string, id int32,
+) (*datapb.ListVersionsResponse, error) {
+	return s.remote.ListVersions(ctx, token, id)
+}
+
+// RestoreVersion - восстанавливает версию только онлайн и только у записи без
+// неотправленных изменений, иначе очередь затёрла бы восстановленную версию.
+// Если обновить кеш не удалось, запись подтянется при следующем Sync.
+func (s *cachedDataService) RestoreVersion(ctx context.Context, token string, id int32, version int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, name, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	if hasPending(c, id) {
+		return 0, fmt.Errorf("у записи %d есть неотправленные изменения, выполните sync", id)
+	}
+
+	restored, err := s.remote.RestoreVersion(ctx, token, id, version)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := s.remote.GetData(ctx, token, id)
+	if err != nil || data.VaultId != 0 {
+		return restored, nil
+	}
+	c.Items[id] = cachedFromProto(data)
+
+	return restored, s.store.Save(name, c)
+}
+
+// ListTrash - корзина хранится только на сервере.
+func (s *cachedDataService) ListTrash(
+	ctx context.Context, token string, vaultID int32,
+) (*datapb.ListTrashResponse, error) {
+	return s.remote.ListTrash(ctx, token, vaultID)
+}
+
+// RestoreData - восстанавливает запись из корзины только онлайн и сразу
+// возвращает личную запись в кеш. Если это не удалось, запись подтянется
+// при следующем Sync.
+func (s *cachedDataService) RestoreData(ctx context.Context, token string, id int32) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, name, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	restored, err := s.remote.RestoreData(ctx, token, id)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := s.remote.GetData(ctx, token, id)
+	if err != nil || data.VaultId != 0 {
+		return restored, nil
+	}
+	c.Items[id] = cachedFromProto(data)
+
+	return restored, s.store.Save(name, c)
+}
+
+// PurgeData - записи из корзины в кеше уже нет, удаление выполняется только онлайн.
+func (s *cachedDataService) PurgeData(ctx context.Context, token string, id int32) error {
+	return s.remote.PurgeData(ctx, token, id)
+}
+
+// UploadBinary - файлы в кеш не попадают и загружаются только онлайн.
+func (s *cachedDataService) UploadBinary(
+	ctx context.Context, token string, header *datapb.BinaryHeader, r io.Reader,
+) (int32, error) {
+	return s.remote.UploadBinary(ctx, token, header, r)
+}
+
+// DownloadBinary - файлы в кеш не попадают и скачиваются только онлайн.
+func (s *cachedDataService) DownloadBinary(
+	ctx context.Context, token string, id int32, w io.Writer,
+) (*datapb.BinaryHeader, error) {
+	return s.remote.DownloadBinary(ctx, token, id, w)
+}
+
+// Sync - отправляет очередь офлайн-изменений по порядку, затем получает
+// изменения сервера после последней известной ревизии. Если отправка прервалась,
+// неотправленные операции остаются в очереди до следующего вызова.
+func (s *cachedDataService) Sync(ctx context.Context, token string) (*entity.SyncResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, name, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.SyncResult{}
+	for len(c.Queue) > 0 {
+		conflict, err := s.push(ctx, token, c, c.Queue[0])
+		if err != nil {
+			if saveErr := s.store.Save(name, c); saveErr != nil {
+				return nil, saveErr
+			}
+			return nil, fmt.Errorf("ошибка отправки изменений: %w", err)
+		}
+		c.Queue = c.Queue[1:]
+		result.Pushed++
+		if conflict {
+			result.Conflicts++
+		}
+	}
+
+	pulled, err := s.pull(ctx, token, c)
+	if err != nil {
+		if saveErr := s.store.Save(name, c); saveErr != nil {
+			return nil, saveErr
+		}
+		return nil, fmt.Errorf("ошибка получения изменений: %w", err)
+	}
+	result.Pulled = pulled
+
+	return result, s.store.Save(name, c)
+}
+
+// push - отправляет одну операцию из очереди. Если запись на сервере успели
+// изменить или удалить, офлайн-изменение не теряется: изменённая запись
+// сохраняется новой записью, а удаление изменённой записи отменяется.
+// В обоих случаях возвращается conflict = true.
+func (s *cachedDataService) push(
+	ctx context.Context, token string, c *entity.Cache, op entity.PendingOperation,
+) (conflict bool, err error) {
+	switch op.Kind {
+	case entity.OperationAdd:
+		id, err := s.addCopy(ctx, token, op.Item)
+		if err != nil {
+			return false, err
+		}
+		remapLocalID(c, op.Item.ID, id)
+		return false, nil
+	case entity.OperationUpdate:
+		err := s.remote.UpdateData(ctx, token, cachedToProto(op.Item))
+		var versionErr *entity.VersionConflictError
+		if errors.As(err, &versionErr) || status.Code(err) == codes.NotFound {
+			_, err = s.addCopy(ctx, token, op.Item)
+			return err == nil, err
+		}
+		return false, err
+	case entity.OperationDelete:
+		err := s.remote.DeleteData(ctx, token, op.Item.ID, op.Item.Version)
+		var versionErr *entity.VersionConflictError
+		if errors.As(err, &versionErr) {
+			return true, nil
+		}
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		return false, err
+	default:
+		return false, fmt.Errorf("неизвестная операция в очереди: %s", op.Kind)
+	}
+}
+
+// addCopy - создаёт на сервере новую запись с содержимым item.
+func (s *cachedDataService) addCopy(ctx context.Context, token string, item entity.CachedItem) (int32, error) {
+	data := cachedToProto(item)
+	data.Id = 0
+	data.Version = 0
+	return s.remote.AddData(ctx, token, data)
+}
+
+// pull - применяет к кешу изменения сервера порциями, пока они не закончатся.
+// Ревизия кеша сдвигается после каждой порции, поэтому прерванная загрузка
+// продолжится с того же места.
+func (s *cachedDataService) pull(ctx context.Context, token string, c *entity.Cache) (int, error) {
+	pulled := 0
+	for {
+		res, err := s.remote.GetChanges(ctx, token, c.Revision)
+		if err != nil {
+			return pulled, err
+		}
+
+		for _, data := range res.Upserts {
+			c.Items[data.Id] = cachedFromProto(data)
+		}
+		for _, tombstone := range res.Deletions {
+			delete(c.Items, tombstone.Id)
+		}
+		pulled += len(res.Upserts) + len(res.Deletions)
+		c.Revision = res.Revision
+
+		if !res.HasMore {
+			return pulled, nil
+		}
+	}
+}
+
+// load - читает кеш текущего пользователя. Имя кеша - отпечаток ключа шифрования,
+// поэтому у разных пользователей кеши не пересекаются.
+func (s *cachedDataService) load() (*entity.Cache, string, error) {
+	if len(s.keyHolder.Key) == 0 {
+		return nil, "", fmt.Errorf("ключ шифрования не задан, выполните вход")
+	}
+
+	sum := sha256.Sum256(s.keyHolder.Key)
+	name := hex.EncodeToString(sum[:8])
+
+	c, err := s.store.Load(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c, name, nil
+}
+
+// isOffline - ошибка означает, что сервер недоступен, а не что он отклонил запрос.
+func isOffline(err error) bool {
+	code := status.Code(err)
+	return code == codes.Unavailable || code == codes.DeadlineExceeded
+}
+
+// hasPending - по записи есть неотправленные изменения. Такие записи
+// читаются и меняются только локально, чтобы не потерять очередь.
+func hasPending(c *entity.Cache, id int32) bool {
+	for _, op := range c.Queue {
+		if op.Item.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueUpdate - изменение записи, добавленной офлайн, попадает прямо в операцию добавления,
+// повторное изменение заменяет предыдущее.
+func enqueueUpdate(queue []entity.PendingOperation, item entity.CachedItem) []entity.PendingOperation {
+	for i, op := range queue {
+		if op.Item.ID != item.ID {
+			continue
+		}
+		if op.Kind == entity.OperationAdd || op.Kind == entity.OperationUpdate {
+			queue[i].Item = item
+			return queue
+		}
+	}
+	return append(queue, entity.PendingOperation{Kind: entity.OperationUpdate, Item: item})
+}
+
+// enqueueDelete - удаление отменяет неотправленные операции над записью.
+// Для записи, добавленной офлайн, на сервер ничего не уходит.
+func enqueueDelete(queue []entity.PendingOperation, id int32, version int64) []entity.PendingOperation {
+	localOnly := false
+	kept := queue[:0]
+	for _, op := range queue {
+		if op.Item.ID != id {
+			kept = append(kept, op)
+			continue
+		}
+		if op.Kind == entity.OperationAdd {
+			localOnly = true
+		}
+	}
+
+	if localOnly {
+		return kept
+	}
+	return append(kept, entity.PendingOperation{
+		Kind: entity.OperationDelete,
+		Item: entity.CachedItem{ID: id, Version: version},
+	})
+}
+
+func remapLocalID(c *entity.Cache, localID, id int32) {
+	if item, ok := c.Items[localID]; ok {
+		delete(c.Items, localID)
+		item.ID = id
+		c.Items[id] = item
+	}
+	for i := range c.Queue {
+		if c.Queue[i].Item.ID == localID {
+			c.Queue[i].Item.ID = id
+		}
+	}
+}
+
+// listCached - список из кеша без постраничной выдачи: в кеше все записи пользователя.
+func listCached(c *entity.Cache, req *datapb.ListDataRequest) *datapb.ListDataResponse {
+	items := make([]entity.CachedItem, 0, len(c.Items))
+	for _, item := range c.Items {
+		if req.InfoType != "" && item.InfoType != req.InfoType {
+			continue
+		}
+		if req.CreatedFrom != nil && item.Created.Before(req.CreatedFrom.AsTime()) {
+			continue
+		}
+		if req.CreatedTo != nil && !item.Created.Before(req.CreatedTo.AsTime()) {
+			continue
+		}
+		if !matchesLabels(item, req) {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Created.Equal(items[j].Created) {
+			return items[i].ID < items[j].ID
+		}
+		if req.Descending {
+			return items[i].Created.After(items[j].Created)
+		}
+		return items[i].Created.Before(items[j].Created)
+	})
+
+	res := &datapb.ListDataResponse{Items: make([]*datapb.DataHeader, 0, len(items))}
+	for _, item := range items {
+		res.Items = append(res.Items, &datapb.DataHeader{
+			Id:       item.ID,
+			InfoType: item.InfoType,
+			Meta:     item.Meta,
+			Created:  timestamppb.New(item.Created),
+			Tags:     item.Tags,
+			Folder:   item.Folder,
+			Favorite: item.Favorite,
+		})
+	}
+
+	return res
+}
+
+// matchesLabels - фильтр по тегу, папке и избранному, как на сервере:
+// папка "/" выбирает записи вне папок.
+func matchesLabels(item entity.CachedItem, req *datapb.ListDataRequest) bool {
+	if req.Tag != "" && !slices.Contains(item.Tags, req.Tag) {
+		return false
+	}
+	if req.FavoritesOnly && !item.Favorite {
+		return false
+	}
+	if req.Folder == "" {
+		return true
+	}
+	if req.Folder == label.Root {
+		return item.Folder == ""
+	}
+	return item.Folder == req.Folder
+}
+
+func foldersCached(c *entity.Cache) []string {
+	folders := make([]string, 0)
+	for _, item := range c.Items {
+		if item.Folder != "" && !slices.Contains(folders, item.Folder) {
+			folders = append(folders, item.Folder)
+		}
+	}
+	sort.Strings(folders)
+	return folders
+}
+
+func cachedFromProto(data *datapb.DataItem) entity.CachedItem {
+	item := entity.CachedItem{
+		ID:           data.Id,
+		InfoType:     data.InfoType,
+		Info:         data.Info,
+		Meta:         data.Meta,
+		Created:      time.Now().UTC(),
+		Version:      data.Version,
+		Tags:         data.Tags,
+		Folder:       data.Folder,
+		Favorite:     data.Favorite,
+		SearchTokens: data.SearchTokens,
+	}
+	if data.Created != nil {
+		item.Created = data.Created.AsTime()
+	}
+	return item
+}
+
+func cachedToProto(item entity.CachedItem) *datapb.DataItem {
+	data := &datapb.DataItem{
+		Id:           item.ID,
+		InfoType:     item.InfoType,
+		Info:         item.Info,
+		Meta:         item.Meta,
+		Version:      item.Version,
+		Tags:         item.Tags,
+		Folder:       item.Folder,
+		Favorite:     item.Favorite,
+		SearchTokens: item.SearchTokens,
+	}
+	if !item.Created.IsZero() {
+		data.Created = timestamppb.New(item.Created)
+	}
+	return data
+}
@@ -0,0 +1,598 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// switchableRemote - сервер в памяти с ревизиями, который можно «отключить».
+// Изменения отдаются порциями по changesPage, чтобы проверить has_more.
+type switchableRemote struct {
+	*fakeDataStore
+	tombstones map[int32]int64
+	offline    bool
+	nextID     int32
+	revision   int64
+}
+
+const changesPage = 2
+
+func newSwitchableRemote() *switchableRemote {
+	return &switchableRemote{fakeDataStore: newFakeDataStore(), tombstones: make(map[int32]int64)}
+}
+
+func (r *switchableRemote) GetChanges(
+	ctx context.Context, token string, since int64,
+) (*datapb.GetChangesResponse, error) {
+	if r.offline {
+		return nil, errUnavailable
+	}
+
+	res := &datapb.GetChangesResponse{Revision: since}
+	for rev := since + 1; rev <= r.revision; rev++ {
+		if len(res.Upserts)+len(res.Deletions) == changesPage {
+			res.HasMore = true
+			break
+		}
+		for _, item := range r.items {
+			if item.Revision == rev {
+				res.Upserts = append(res.Upserts, item)
+				res.Revision = rev
+			}
+		}
+		for id, tombstoneRev := range r.tombstones {
+			if tombstoneRev == rev {
+				res.Deletions = append(res.Deletions, &datapb.Tombstone{Id: id, Revision: rev})
+				res.Revision = rev
+			}
+		}
+	}
+	return res, nil
+}
+
+var errUnavailable = status.Error(codes.Unavailable, "connection refused")
+
+func (r *switchableRemote) AddData(ctx context.Context, token string, data *datapb.DataItem) (int32, error) {
+	if r.offline {
+		return 0, errUnavailable
+	}
+	r.nextID++
+	r.revision++
+	stored := &datapb.DataItem{
+		Id: r.nextID, InfoType: data.InfoType, Info: data.Info, Meta: data.Meta, Revision: r.revision, Version: 1,
+		Tags: data.Tags, Folder: data.Folder, Favorite: data.Favorite, SearchTokens: data.SearchTokens,
+	}
+	r.items[r.nextID] = stored
+	return r.nextID, nil
+}
+
+func (r *switchableRemote) GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error) {
+	if r.offline {
+		return nil, errUnavailable
+	}
+	item, ok := r.items[id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return item, nil
+}
+
+func (r *switchableRemote) UpdateData(ctx context.Context, token string, data *datapb.DataItem) error {
+	if r.offline {
+		return errUnavailable
+	}
+	if err := r.checkVersion(data.Id, data.Version); err != nil {
+		return err
+	}
+	r.revision++
+	stored := &datapb.DataItem{
+		Id: data.Id, InfoType: data.InfoType, Info: data.Info, Meta: data.Meta,
+		Revision: r.revision, Version: data.Version + 1,
+		Tags: data.Tags, Folder: data.Folder, Favorite: data.Favorite, SearchTokens: data.SearchTokens,
+	}
+	return r.fakeDataStore.UpdateData(ctx, token, stored)
+}
+
+func (r *switchableRemote) DeleteData(ctx context.Context, token string, id int32, version int64) error {
+	if r.offline {
+		return errUnavailable
+	}
+	if err := r.checkVersion(id, version); err != nil {
+		return err
+	}
+	r.revision++
+	r.tombstones[id] = r.revision
+	return r.fakeDataStore.DeleteData(ctx, token, id, version)
+}
+
+func (r *switchableRemote) MoveData(
+	ctx context.Context, token string, data *datapb.DataItem, vaultID int32,
+) (int64, error) {
+	if r.offline {
+		return 0, errUnavailable
+	}
+	if err := r.checkVersion(data.Id, data.Version); err != nil {
+		return 0, err
+	}
+	r.revision++
+	if vaultID != 0 {
+		r.tombstones[data.Id] = r.revision
+	}
+	stored := &datapb.DataItem{
+		Id: data.Id, InfoType: data.InfoType, Info: data.Info, Meta: data.Meta,
+		Revision: r.revision, Version: data.Version + 1, VaultId: vaultID,
+	}
+	r.items[data.Id] = stored
+	return stored.Version, nil
+}
+
+func (r *switchableRemote) checkVersion(id int32, version int64) error {
+	item, ok := r.items[id]
+	if !ok {
+		return status.Error(codes.NotFound, "not found")
+	}
+	if item.Version != version {
+		return &entity.VersionConflictError{ID: id, CurrentVersion: item.Version}
+	}
+	return nil
+}
+
+func (r *switchableRemote) ListData(
+	ctx context.Context, token string, req *datapb.ListDataRequest,
+) (*datapb.ListDataResponse, error) {
+	if r.offline {
+		return nil, errUnavailable
+	}
+	return r.fakeDataStore.ListData(ctx, token, req)
+}
+
+func (r *switchableRemote) ListFolders(ctx context.Context, token string) ([]string, error) {
+	if r.offline {
+		return nil, errUnavailable
+	}
+	return r.fakeDataStore.ListFolders(ctx, token)
+}
+
+func (r *switchableRemote) SearchData(
+	ctx context.Context, token string, req *datapb.SearchDataRequest,
+) (*datapb.SearchDataResponse, error) {
+	if r.offline {
+		return nil, errUnavailable
+	}
+	return r.fakeDataStore.SearchData(ctx, token, req)
+}
+
+func (r *switchableRemote) ListVersions(
+	ctx context.Context, token string, id int32,
+) (*datapb.ListVersionsResponse, error) {
+	if r.offline {
+		return nil, errUnavailable
+	}
+	return r.fakeDataStore.ListVersions(ctx, token, id)
+}
+
+func (r *switchableRemote) RestoreVersion(ctx context.Context, token string, id int32, version int64) (int64, error) {
+	if r.offline {
+		return 0, errUnavailable
+	}
+	restored, err := r.fakeDataStore.RestoreVersion(ctx, token, id, version)
+	if err != nil {
+		return 0, err
+	}
+	r.revision++
+	r.items[id].Revision = r.revision
+	return restored, nil
+}
+
+func (r *switchableRemote) ListTrash(
+	ctx context.Context, token string, vaultID int32,
+) (*datapb.ListTrashResponse, error) {
+	if r.offline {
+		return nil, errUnavailable
+	}
+	return r.fakeDataStore.ListTrash(ctx, token, vaultID)
+}
+
+// RestoreData - восстановленная запись получает новую ревизию, а её надгробие удаляется.
+func (r *switchableRemote) RestoreData(ctx context.Context, token string, id int32) (int64, error) {
+	if r.offline {
+		return 0, errUnavailable
+	}
+	restored, err := r.fakeDataStore.RestoreData(ctx, token, id)
+	if err != nil {
+		return 0, err
+	}
+	r.revision++
+	r.items[id].Revision = r.revision
+	delete(r.tombstones, id)
+	return restored, nil
+}
+
+func (r *switchableRemote) PurgeData(ctx context.Context, token string, id int32) error {
+	if r.offline {
+		return errUnavailable
+	}
+	return r.fakeDataStore.PurgeData(ctx, token, id)
+}
+
+// memoryCacheStore - хранилище кеша в памяти.
+type memoryCacheStore struct {
+	caches map[string]*entity.Cache
+}
+
+func (m *memoryCacheStore) Load(name string) (*entity.Cache, error) {
+	if c, ok := m.caches[name]; ok {
+		return c, nil
+	}
+	return &entity.Cache{Items: make(map[int32]entity.CachedItem)}, nil
+}
+
+func (m *memoryCacheStore) Save(name string, c *entity.Cache) error {
+	m.caches[name] = c
+	return nil
+}
+
+func newCachedTestService(t *testing.T) (*cachedDataService, *switchableRemote, *memoryCacheStore) {
+	t.Helper()
+
+	remote := newSwitchableRemote()
+	store := &memoryCacheStore{caches: make(map[string]*entity.Cache)}
+	svc := NewCachedDataService(remote, store, &entity.KeyHolder{Key: []byte("0123456789abcdef0123456789abcdef")})
+
+	return svc, remote, store
+}
+
+func onlyCache(t *testing.T, store *memoryCacheStore) *entity.Cache {
+	t.Helper()
+
+	require.Len(t, store.caches, 1)
+	for _, c := range store.caches {
+		return c
+	}
+	return nil
+}
+
+func TestCachedDataService_OfflineRead(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, _ := newCachedTestService(t)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "enc", Meta: "m"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), id)
+
+	remote.offline = true
+
+	got, err := svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, "enc", got.Info)
+
+	list, err := svc.ListData(ctx, "token", &datapb.ListDataRequest{InfoType: "text"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "m", list.Items[0].Meta)
+
+	_, err = svc.GetData(ctx, "token", 42)
+	assert.Error(t, err)
+}
+
+func TestCachedDataService_OfflineLabels(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, _ := newCachedTestService(t)
+
+	items := []*datapb.DataItem{
+		{InfoType: "text", Info: "a", Tags: []string{"db", "prod"}, Folder: "work/db", Favorite: true},
+		{InfoType: "text", Info: "b", Tags: []string{"db"}, Folder: "home"},
+		{InfoType: "text", Info: "c"},
+	}
+	for _, item := range items {
+		_, err := svc.AddData(ctx, "token", item)
+		require.NoError(t, err)
+	}
+	_, err := svc.ListData(ctx, "token", &datapb.ListDataRequest{})
+	require.NoError(t, err)
+
+	remote.offline = true
+
+	tests := []struct {
+		name     string
+		request  *datapb.ListDataRequest
+		expected []int32
+	}{
+		{name: "по тегу", request: &datapb.ListDataRequest{Tag: "db"}, expected: []int32{1, 2}},
+		{name: "по папке", request: &datapb.ListDataRequest{Folder: "work/db"}, expected: []int32{1}},
+		{name: "вне папок", request: &datapb.ListDataRequest{Folder: "/"}, expected: []int32{3}},
+		{name: "избранное", request: &datapb.ListDataRequest{FavoritesOnly: true}, expected: []int32{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := svc.ListData(ctx, "token", tt.request)
+			require.NoError(t, err)
+
+			ids := make([]int32, 0, len(list.Items))
+			for _, item := range list.Items {
+				ids = append(ids, item.Id)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+
+	folders, err := svc.ListFolders(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"home", "work/db"}, folders)
+}
+
+func TestCachedDataService_SearchTokensSurviveOfflineQueue(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, _ := newCachedTestService(t)
+	remote.offline = true
+
+	_, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "enc", SearchTokens: []string{"t1"}})
+	require.NoError(t, err)
+
+	_, err = svc.SearchData(ctx, "token", &datapb.SearchDataRequest{Tokens: []string{"t1"}})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	remote.offline = false
+	_, err = svc.Sync(ctx, "token")
+	require.NoError(t, err)
+
+	res, err := svc.SearchData(ctx, "token", &datapb.SearchDataRequest{Tokens: []string{"t1"}})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+	assert.Equal(t, int32(1), res.Items[0].Id)
+}
+
+func TestCachedDataService_RemoteErrorIsNotMaskedByCache(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newCachedTestService(t)
+
+	_, err := svc.GetData(ctx, "token", 5)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCachedDataService_OfflineWritesAndSync(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, store := newCachedTestService(t)
+
+	keptID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "kept"})
+	require.NoError(t, err)
+	deletedID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "deleted"})
+	require.NoError(t, err)
+
+	remote.offline = true
+
+	localID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "offline"})
+	require.NoError(t, err)
+	assert.Less(t, localID, int32(0))
+
+	require.NoError(t, svc.UpdateData(ctx, "token", &datapb.DataItem{Id: localID, InfoType: "text", Info: "offline v2"}))
+	require.NoError(t, svc.UpdateData(ctx, "token", &datapb.DataItem{Id: keptID, InfoType: "text", Info: "kept v2", Version: 1}))
+	require.NoError(t, svc.DeleteData(ctx, "token", deletedID, 1))
+
+	droppedID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "dropped"})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteData(ctx, "token", droppedID, 0))
+
+	c := onlyCache(t, store)
+	require.Len(t, c.Queue, 3)
+	assert.Equal(t, entity.OperationAdd, c.Queue[0].Kind)
+	assert.Equal(t, "offline v2", c.Queue[0].Item.Info)
+
+	got, err := svc.GetData(ctx, "token", localID)
+	require.NoError(t, err)
+	assert.Equal(t, "offline v2", got.Info)
+
+	_, err = svc.Sync(ctx, "token")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Len(t, onlyCache(t, store).Queue, 3)
+
+	remote.offline = false
+
+	result, err := svc.Sync(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Pushed)
+	assert.Equal(t, 3, result.Pulled)
+	assert.Equal(t, 0, result.Conflicts)
+
+	assert.Equal(t, "kept v2", remote.items[keptID].Info)
+	assert.NotContains(t, remote.items, deletedID)
+	assert.Equal(t, "offline v2", remote.items[3].Info)
+
+	c = onlyCache(t, store)
+	assert.Empty(t, c.Queue)
+	assert.Contains(t, c.Items, int32(3))
+	assert.NotContains(t, c.Items, localID)
+	assert.NotContains(t, c.Items, deletedID)
+	assert.Equal(t, remote.revision, c.Revision)
+
+	result, err = svc.Sync(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Pushed)
+	assert.Equal(t, 0, result.Pulled)
+}
+
+func TestCachedDataService_SyncPullsChangesFromOtherDevices(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, store := newCachedTestService(t)
+
+	keptID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "v1"})
+	require.NoError(t, err)
+	removedID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "removed"})
+	require.NoError(t, err)
+	_, err = svc.Sync(ctx, "token")
+	require.NoError(t, err)
+
+	// Изменения, сделанные другим устройством напрямую на сервере.
+	require.NoError(t, remote.UpdateData(ctx, "token", &datapb.DataItem{Id: keptID, InfoType: "text", Info: "v2", Version: 1}))
+	require.NoError(t, remote.DeleteData(ctx, "token", removedID, 1))
+	otherID, err := remote.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "other"})
+	require.NoError(t, err)
+
+	result, err := svc.Sync(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Pulled)
+
+	c := onlyCache(t, store)
+	assert.Equal(t, "v2", c.Items[keptID].Info)
+	assert.NotContains(t, c.Items, removedID)
+	assert.Equal(t, "other", c.Items[otherID].Info)
+	assert.Equal(t, int64(2), c.Items[keptID].Version)
+}
+
+func TestCachedDataService_SyncConflicts(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, store := newCachedTestService(t)
+
+	editedID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "v1"})
+	require.NoError(t, err)
+	deletedID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "keep me"})
+	require.NoError(t, err)
+
+	remote.offline = true
+	require.NoError(t, svc.UpdateData(ctx, "token", &datapb.DataItem{Id: editedID, InfoType: "text", Info: "мой v2", Version: 1}))
+	require.NoError(t, svc.DeleteData(ctx, "token", deletedID, 1))
+	remote.offline = false
+
+	// Пока клиент был офлайн, другое устройство изменило обе записи.
+	require.NoError(t, remote.UpdateData(ctx, "token", &datapb.DataItem{Id: editedID, InfoType: "text", Info: "чужой v2", Version: 1}))
+	require.NoError(t, remote.UpdateData(ctx, "token", &datapb.DataItem{Id: deletedID, InfoType: "text", Info: "keep me v2", Version: 1}))
+
+	result, err := svc.Sync(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Pushed)
+	assert.Equal(t, 2, result.Conflicts)
+
+	assert.Equal(t, "чужой v2", remote.items[editedID].Info)
+	assert.Equal(t, "keep me v2", remote.items[deletedID].Info)
+	require.Len(t, remote.items, 3)
+
+	c := onlyCache(t, store)
+	assert.Empty(t, c.Queue)
+	assert.Len(t, c.Items, 3)
+	infos := make([]string, 0, len(c.Items))
+	for _, item := range c.Items {
+		infos = append(infos, item.Info)
+	}
+	assert.ElementsMatch(t, []string{"чужой v2", "keep me v2", "мой v2"}, infos)
+}
+
+func TestCachedDataService_PendingItemIsServedLocally(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, _ := newCachedTestService(t)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "v1"})
+	require.NoError(t, err)
+
+	remote.offline = true
+	require.NoError(t, svc.UpdateData(ctx, "token", &datapb.DataItem{Id: id, InfoType: "text", Info: "v2"}))
+	remote.offline = false
+
+	got, err := svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got.Info)
+	assert.Equal(t, "v1", remote.items[id].Info)
+}
+
+func TestCachedDataService_NoKey(t *testing.T) {
+	svc := NewCachedDataService(newSwitchableRemote(), &memoryCacheStore{}, &entity.KeyHolder{})
+
+	_, err := svc.GetData(context.Background(), "token", 1)
+	assert.Error(t, err)
+}
+
+func TestCachedDataService_MoveData(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, store := newCachedTestService(t)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "enc"})
+	require.NoError(t, err)
+
+	version, err := svc.MoveData(ctx, "token", &datapb.DataItem{Id: id, InfoType: "text", Info: "vault enc", Version: 1}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	assert.NotContains(t, onlyCache(t, store).Items, id)
+
+	// Записи хранилища читаются с сервера и в кеш не попадают.
+	got, err := svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), got.VaultId)
+	assert.NotContains(t, onlyCache(t, store).Items, id)
+
+	version, err = svc.MoveData(ctx, "token", &datapb.DataItem{Id: id, InfoType: "text", Info: "enc", Version: 2}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	assert.Equal(t, int64(3), onlyCache(t, store).Items[id].Version)
+
+	remote.offline = true
+	require.NoError(t, svc.UpdateData(ctx, "token", &datapb.DataItem{Id: id, InfoType: "text", Info: "v4", Version: 3}))
+	remote.offline = false
+
+	_, err = svc.MoveData(ctx, "token", &datapb.DataItem{Id: id, InfoType: "text", Info: "v4", Version: 3}, 5)
+	assert.ErrorContains(t, err, "неотправленные изменения")
+}
+
+func TestCachedDataService_RestoreVersion(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, store := newCachedTestService(t)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "v1"})
+	require.NoError(t, err)
+	require.NoError(t, svc.UpdateData(ctx, "token", &datapb.DataItem{Id: id, InfoType: "text", Info: "v2", Version: 1}))
+
+	history, err := svc.ListVersions(ctx, "token", id)
+	require.NoError(t, err)
+	require.Len(t, history.Versions, 1)
+
+	version, err := svc.RestoreVersion(ctx, "token", id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	assert.Equal(t, "v1", onlyCache(t, store).Items[id].Info)
+	assert.Equal(t, int64(3), onlyCache(t, store).Items[id].Version)
+
+	remote.offline = true
+	_, err = svc.RestoreVersion(ctx, "token", id, 2)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	require.NoError(t, svc.UpdateData(ctx, "token", &datapb.DataItem{Id: id, InfoType: "text", Info: "v4", Version: 3}))
+	remote.offline = false
+
+	_, err = svc.RestoreVersion(ctx, "token", id, 2)
+	assert.ErrorContains(t, err, "неотправленные изменения")
+}
+
+func TestCachedDataService_RestoreData(t *testing.T) {
+	ctx := context.Background()
+	svc, remote, store := newCachedTestService(t)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "v1"})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteData(ctx, "token", id, 1))
+	assert.NotContains(t, onlyCache(t, store).Items, id)
+
+	trash, err := svc.ListTrash(ctx, "token", 0)
+	require.NoError(t, err)
+	require.Len(t, trash.Items, 1)
+
+	remote.offline = true
+	_, err = svc.RestoreData(ctx, "token", id)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	remote.offline = false
+
+	version, err := svc.RestoreData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	assert.Equal(t, "v1", onlyCache(t, store).Items[id].Info)
+	assert.Equal(t, int64(2), onlyCache(t, store).Items[id].Version)
+
+	// Sync не удаляет восстановленную запись по старому надгробию.
+	_, err = svc.Sync(ctx, "token")
+	require.NoError(t, err)
+	assert.Contains(t, onlyCache(t, store).Items, id)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Формат зашифрованного файла: префикс nonce (16 байт), затем чанки
+// Seal(nonce = префикс || номер чанка, чанк открытого текста, AAD = признак последнего).
+// Номер в nonce не даёт переставить чанки, признак последнего - обрезать файл.
+// Префикс фиксирован для файла, поэтому повторное шифрование тем же префиксом
+// даёт те же байты: это позволяет посчитать SHA-256 до отправки.
+const (
+	plainChunkSize  = 64 * 1024
+	noncePrefixSize = chacha20poly1305.NonceSizeX - 8
+)
+
+var errTruncated = errors.New("зашифрованный файл обрезан или повреждён")
+
+func chunkNonce(prefix []byte, index uint64) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint64(nonce[noncePrefixSize:], index)
+	return nonce
+}
+
+func chunkAAD(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptingReader - шифрует поток открытого текста по чанкам.
+type encryptingReader struct {
+	aead   cipher.AEAD
+	prefix []byte
+	src    io.Reader
+	out    bytes.Buffer
+	next   []byte
+	index  uint64
+	done   bool
+	primed bool
+}
+
+func newEncryptingReader(key, prefix []byte, src io.Reader) (*encryptingReader, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка инициализации шифра: %w", err)
+	}
+
+	r := &encryptingReader{aead: aead, prefix: prefix, src: src}
+	r.out.Write(prefix)
+
+	return r, nil
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for r.out.Len() == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.sealNext(); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.out.Read(p)
+}
+
+// sealNext - шифрует очередной чанк. Чтобы пометить последний чанк,
+// следующий читается заранее.
+func (r *encryptingReader) sealNext() error {
+	if !r.primed {
+		chunk, err := readChunk(r.src)
+		if err != nil {
+			return err
+		}
+		r.next = chunk
+		r.primed = true
+	}
+
+	current := r.next
+	last := len(current) < plainChunkSize
+	if !last {
+		following, err := readChunk(r.src)
+		if err != nil {
+			return err
+		}
+		r.next = following
+		last = len(following) == 0
+	}
+
+	r.out.Write(r.aead.Seal(nil, chunkNonce(r.prefix, r.index), current, chunkAAD(last)))
+	r.index++
+	r.done = last
+
+	return nil
+}
+
+func readChunk(src io.Reader) ([]byte, error) {
+	chunk := make([]byte, plainChunkSize)
+	n, err := io.ReadFull(src, chunk)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	return chunk[:n], nil
+}
+
+// decryptingWriter - расшифровывает поток, записанный encryptingReader,
+// и пишет открытый текст в dst. Close обязателен: он проверяет последний чанк.
+type decryptingWriter struct {
+	aead   cipher.AEAD
+	dst    io.Writer
+	prefix []byte
+	buf    []byte
+	index  uint64
+}
+
+func newDecryptingWriter(key []byte, dst io.Writer) (*decryptingWriter, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка инициализации шифра: %w", err)
+	}
+
+	return &decryptingWriter{aead: aead, dst: dst}, nil
+}
+
+func (w *decryptingWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	if w.prefix == nil {
+		if len(w.buf) < noncePrefixSize {
+			return len(p), nil
+		}
+		w.prefix = append([]byte(nil), w.buf[:noncePrefixSize]...)
+		w.buf = w.buf[noncePrefixSize:]
+	}
+
+	// Чанк расшифровывается, только когда за ним есть ещё данные:
+	// иначе неизвестно, последний ли он.
+	sealedSize := plainChunkSize + w.aead.Overhead()
+	for len(w.buf) > sealedSize {
+		if err := w.open(w.buf[:sealedSize], false); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[sealedSize:]
+	}
+
+	return len(p), nil
+}
+
+func (w *decryptingWriter) Close() error {
+	if w.prefix == nil || len(w.buf) < w.aead.Overhead() {
+		return errTruncated
+	}
+	if err := w.open(w.buf, true); err != nil {
+		return err
+	}
+	w.buf = nil
+
+	return nil
+}
+
+func (w *decryptingWriter) open(sealed []byte, last bool) error {
+	plain, err := w.aead.Open(nil, chunkNonce(w.prefix, w.index), sealed, chunkAAD(last))
+	if err != nil {
+		if last {
+			return errTruncated
+		}
+		return ErrDecrypt
+	}
+	w.index++
+
+	if _, err := w.dst.Write(plain); err != nil {
+		return fmt.Errorf("ошибка записи файла: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Границы параметров KDF от сервера. Нижние совпадают с параметрами новых
+// пользователей на сервере и не дают подменённому ответу ослабить ключ из
+// мастер-пароля для перебора; верхние - занять всю память клиента
+// и зависнуть на выводе ключа.
+const (
+	minKDFSaltSize = 16
+	minKDFTime     = 3
+	minKDFMemory   = 64 * 1024
+	maxKDFTime     = 16
+	maxKDFMemory   = 1024 * 1024
+	maxKDFThreads  = 255
+)
+
+// sealedPrefix - метка версии формата перед результатом encryptField. По ней
+// шифртекст отличается от записей, сохранённых до появления шифрования.
+const sealedPrefix = "v1:"
+
+// ErrDecrypt - данные не удалось расшифровать текущим ключом.
+var ErrDecrypt = errors.New("неверный мастер-пароль или повреждённые данные")
+
+type cryptoService struct{}
+
+// NewCryptoService - конструктор сервиса клиентского шифрования.
+func NewCryptoService() *cryptoService {
+	return &cryptoService{}
+}
+
+// DeriveKey - выводит ключ шифрования из мастер-пароля по Argon2id.
+func (s *cryptoService) DeriveKey(masterPassword string, params *entity.KDFParams) ([]byte, error) {
+	if params == nil || len(params.Salt) == 0 {
+		return nil, fmt.Errorf("сервер не вернул параметры KDF")
+	}
+	if len(params.Salt) < minKDFSaltSize ||
+		params.Time < minKDFTime || params.Time > maxKDFTime ||
+		params.Memory < minKDFMemory || params.Memory > maxKDFMemory ||
+		params.Threads == 0 || params.Threads > maxKDFThreads {
+		return nil, fmt.Errorf("сервер вернул недопустимые параметры KDF")
+	}
+
+	return deriveKey(masterPassword, params), nil
+}
+
+// deriveKey - Argon2id без проверки параметров.
+func deriveKey(masterPassword string, params *entity.KDFParams) []byte {
+	return argon2.IDKey(
+		[]byte(masterPassword),
+		params.Salt,
+		params.Time,
+		params.Memory,
+		uint8(params.Threads),
+		chacha20poly1305.KeySize,
+	)
+}
+
+// keyCheckPlain - константа, шифртекст которой хранится на сервере как
+// проверка мастер-пароля: расшифровать его можно только верным ключом.
+const keyCheckPlain = "goph-keeper key check"
+
+// NewKeyCheck - шифрует константу проверки ключом key.
+func (s *cryptoService) NewKeyCheck(key []byte) ([]byte, error) {
+	encoded, err := encryptField(key, keyCheckPlain)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(strings.TrimPrefix(encoded, sealedPrefix))
+}
+
+// CheckKey - возвращает entity.ErrWrongMasterPassword, если keyCheck
+// зашифрован не ключом key.
+func (s *cryptoService) CheckKey(key, keyCheck []byte) error {
+	plain, err := decryptField(key, base64.StdEncoding.EncodeToString(keyCheck))
+	if err != nil || plain != keyCheckPlain {
+		return entity.ErrWrongMasterPassword
+	}
+
+	return nil
+}
+
+// encryptField - шифрует строку XChaCha20-Poly1305, результат: "v1:" + base64(nonce || ciphertext).
+func encryptField(key []byte, plain string) (string, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return "", fmt.Errorf("ошибка инициализации шифра: %w", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("ошибка генерации nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// isSealed - помечена ли строка как результат encryptField.
+func isSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// decryptField - расшифровывает строку, зашифрованную encryptField. Шифртекст
+// без метки версии, записанный прежними клиентами, тоже принимается: его
+// подлинность всё равно проверяет AEAD.
+func decryptField(key []byte, encoded string) (string, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return "", fmt.Errorf("ошибка инициализации шифра: %w", err)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encoded, sealedPrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+
+	return string(plain), nil
+}
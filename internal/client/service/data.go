@@ -2,10 +2,19 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
 
 	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type dataService struct {
@@ -45,18 +54,258 @@ func (s *dataService) UpdateData(ctx context.Context, token string, data *datapb
 	req := &datapb.UpdateDataRequest{Data: data}
 	_, err := s.client.UpdateData(ctx, req)
 	if err != nil {
-		return err
+		return conflictFromStatus(err)
 	}
 	return nil
 }
 
-func (s *dataService) DeleteData(ctx context.Context, token string, id int32) error {
+// DeleteData - удаляет запись, если на сервере она всё ещё версии version.
+func (s *dataService) DeleteData(ctx context.Context, token string, id int32, version int64) error {
 	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
 
-	req := &datapb.DeleteDataRequest{Id: id}
+	req := &datapb.DeleteDataRequest{Id: id, Version: version}
 	_, err := s.client.DeleteData(ctx, req)
 	if err != nil {
-		return err
+		return conflictFromStatus(err)
 	}
 	return nil
 }
+
+// MoveData - переносит запись в хранилище vaultID (0 - в личные данные)
+// и возвращает её новую версию. data уже зашифрована ключом целевого хранилища.
+func (s *dataService) MoveData(ctx context.Context, token string, data *datapb.DataItem, vaultID int32) (int64, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.MoveData(ctx, &datapb.MoveDataRequest{Data: data, VaultId: vaultID})
+	if err != nil {
+		return 0, conflictFromStatus(err)
+	}
+	return res.Version, nil
+}
+
+// conflictFromStatus - превращает Aborted с деталью VersionConflict
+// в *entity.VersionConflictError, остальные ошибки возвращает как есть.
+func conflictFromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Aborted {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		if conflict, ok := detail.(*datapb.VersionConflict); ok {
+			return &entity.VersionConflictError{ID: conflict.Id, CurrentVersion: conflict.CurrentVersion}
+		}
+	}
+
+	return err
+}
+
+func (s *dataService) ListData(
+	ctx context.Context, token string, req *datapb.ListDataRequest,
+) (*datapb.ListDataResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.ListData(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListFolders - возвращает папки, в которых есть личные записи.
+func (s *dataService) ListFolders(ctx context.Context, token string) ([]string, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.ListFolders(ctx, &datapb.ListFoldersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Folders, nil
+}
+
+// SearchData - возвращает страницу записей, у которых есть все токены req.Tokens.
+func (s *dataService) SearchData(
+	ctx context.Context, token string, req *datapb.SearchDataRequest,
+) (*datapb.SearchDataResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.SearchData(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// IndexData - заменяет токены поиска записи id.
+func (s *dataService) IndexData(ctx context.Context, token string, id int32, tokens []string) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	_, err := s.client.IndexData(ctx, &datapb.IndexDataRequest{Id: id, SearchTokens: tokens})
+	return err
+}
+
+// ListVersions - возвращает сохранённые прошлые версии записи id.
+func (s *dataService) ListVersions(ctx context.Context, token string, id int32) (*datapb.ListVersionsResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.ListVersions(ctx, &datapb.ListVersionsRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RestoreVersion - делает версию version записи id текущей и возвращает её новую версию.
+func (s *dataService) RestoreVersion(ctx context.Context, token string, id int32, version int64) (int64, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.RestoreVersion(ctx, &datapb.RestoreVersionRequest{Id: id, Version: version})
+	if err != nil {
+		return 0, err
+	}
+	return res.Version, nil
+}
+
+// ListTrash - возвращает записи из корзины личных данных или хранилища vaultID.
+func (s *dataService) ListTrash(ctx context.Context, token string, vaultID int32) (*datapb.ListTrashResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.ListTrash(ctx, &datapb.ListTrashRequest{VaultId: vaultID})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RestoreData - возвращает запись id из корзины и возвращает её новую версию.
+func (s *dataService) RestoreData(ctx context.Context, token string, id int32) (int64, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.RestoreData(ctx, &datapb.RestoreDataRequest{Id: id})
+	if err != nil {
+		return 0, err
+	}
+	return res.Version, nil
+}
+
+// PurgeData - окончательно удаляет запись id из корзины.
+func (s *dataService) PurgeData(ctx context.Context, token string, id int32) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	_, err := s.client.PurgeData(ctx, &datapb.PurgeDataRequest{Id: id})
+	return err
+}
+
+// GetChanges - возвращает изменения записей после ревизии since.
+func (s *dataService) GetChanges(ctx context.Context, token string, since int64) (*datapb.GetChangesResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	res, err := s.client.GetChanges(ctx, &datapb.GetChangesRequest{SinceRevision: since})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// transferChunkSize - размер чанка, которым файл передаётся по стриму.
+const transferChunkSize = 64 * 1024
+
+// ErrChecksumMismatch - полученный файл не совпал с заголовком.
+var ErrChecksumMismatch = errors.New("контрольная сумма файла не совпадает")
+
+// UploadBinary - отправляет заголовок, затем содержимое r чанками.
+func (s *dataService) UploadBinary(
+	ctx context.Context, token string, header *datapb.BinaryHeader, r io.Reader,
+) (int32, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	stream, err := s.client.UploadBinary(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	err = stream.Send(&datapb.UploadBinaryRequest{Part: &datapb.UploadBinaryRequest_Header{Header: header}})
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, transferChunkSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			chunk := &datapb.UploadBinaryRequest{Part: &datapb.UploadBinaryRequest_Chunk{Chunk: buf[:n]}}
+			if err := stream.Send(chunk); err != nil {
+				return 0, err
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return 0, fmt.Errorf("ошибка чтения файла: %w", readErr)
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, err
+	}
+	return res.Id, nil
+}
+
+// DownloadBinary - принимает заголовок и пишет содержимое в w,
+// проверяя размер и SHA-256 из заголовка.
+func (s *dataService) DownloadBinary(
+	ctx context.Context, token string, id int32, w io.Writer,
+) (*datapb.BinaryHeader, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	stream, err := s.client.DownloadBinary(ctx, &datapb.DownloadBinaryRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return nil, fmt.Errorf("сервер не прислал заголовок файла")
+	}
+
+	hasher := sha256.New()
+	size, err := receiveChunks(stream, io.MultiWriter(w, hasher))
+	if err != nil {
+		return nil, err
+	}
+
+	if size != header.Size || !checksumEqual(hasher, header.Sha256) {
+		return nil, ErrChecksumMismatch
+	}
+
+	return header, nil
+}
+
+func receiveChunks(stream datapb.DataService_DownloadBinaryClient, w io.Writer) (int64, error) {
+	var size int64
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		chunk := msg.GetChunk()
+		if _, err := w.Write(chunk); err != nil {
+			return 0, err
+		}
+		size += int64(len(chunk))
+	}
+}
+
+func checksumEqual(h hash.Hash, expected string) bool {
+	return hex.EncodeToString(h.Sum(nil)) == expected
+}
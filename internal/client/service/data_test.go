@@ -1,15 +1,23 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 type MockDataServiceClient struct {
@@ -36,6 +44,108 @@ func (m *MockDataServiceClient) DeleteData(ctx context.Context, in *datapb.Delet
 	return args.Get(0).(*datapb.DeleteDataResponse), args.Error(1)
 }
 
+func (m *MockDataServiceClient) ListData(ctx context.Context, in *datapb.ListDataRequest, opts ...grpc.CallOption) (*datapb.ListDataResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*datapb.ListDataResponse), args.Error(1)
+}
+
+func (m *MockDataServiceClient) UploadBinary(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[datapb.UploadBinaryRequest, datapb.UploadBinaryResponse], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(grpc.ClientStreamingClient[datapb.UploadBinaryRequest, datapb.UploadBinaryResponse]), args.Error(1)
+}
+
+func (m *MockDataServiceClient) DownloadBinary(ctx context.Context, in *datapb.DownloadBinaryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[datapb.DownloadBinaryResponse], error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(grpc.ServerStreamingClient[datapb.DownloadBinaryResponse]), args.Error(1)
+}
+
+func (m *MockDataServiceClient) GetChanges(ctx context.Context, in *datapb.GetChangesRequest, opts ...grpc.CallOption) (*datapb.GetChangesResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*datapb.GetChangesResponse), args.Error(1)
+}
+
+func (m *MockDataServiceClient) MoveData(ctx context.Context, in *datapb.MoveDataRequest, opts ...grpc.CallOption) (*datapb.MoveDataResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*datapb.MoveDataResponse), args.Error(1)
+}
+
+func (m *MockDataServiceClient) ListFolders(ctx context.Context, in *datapb.ListFoldersRequest, opts ...grpc.CallOption) (*datapb.ListFoldersResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*datapb.ListFoldersResponse), args.Error(1)
+}
+
+func (m *MockDataServiceClient) SearchData(ctx context.Context, in *datapb.SearchDataRequest, opts ...grpc.CallOption) (*datapb.SearchDataResponse, error) {
+	args := m.Called(ctx, in)
+	res, _ := args.Get(0).(*datapb.SearchDataResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockDataServiceClient) IndexData(ctx context.Context, in *datapb.IndexDataRequest, opts ...grpc.CallOption) (*datapb.IndexDataResponse, error) {
+	args := m.Called(ctx, in)
+	res, _ := args.Get(0).(*datapb.IndexDataResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockDataServiceClient) ListVersions(ctx context.Context, in *datapb.ListVersionsRequest, opts ...grpc.CallOption) (*datapb.ListVersionsResponse, error) {
+	args := m.Called(ctx, in)
+	res, _ := args.Get(0).(*datapb.ListVersionsResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockDataServiceClient) RestoreVersion(ctx context.Context, in *datapb.RestoreVersionRequest, opts ...grpc.CallOption) (*datapb.RestoreVersionResponse, error) {
+	args := m.Called(ctx, in)
+	res, _ := args.Get(0).(*datapb.RestoreVersionResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockDataServiceClient) ListTrash(ctx context.Context, in *datapb.ListTrashRequest, opts ...grpc.CallOption) (*datapb.ListTrashResponse, error) {
+	args := m.Called(ctx, in)
+	res, _ := args.Get(0).(*datapb.ListTrashResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockDataServiceClient) RestoreData(ctx context.Context, in *datapb.RestoreDataRequest, opts ...grpc.CallOption) (*datapb.RestoreDataResponse, error) {
+	args := m.Called(ctx, in)
+	res, _ := args.Get(0).(*datapb.RestoreDataResponse)
+	return res, args.Error(1)
+}
+
+func (m *MockDataServiceClient) PurgeData(ctx context.Context, in *datapb.PurgeDataRequest, opts ...grpc.CallOption) (*datapb.PurgeDataResponse, error) {
+	args := m.Called(ctx, in)
+	res, _ := args.Get(0).(*datapb.PurgeDataResponse)
+	return res, args.Error(1)
+}
+
+// fakeUploadStream - клиентский стрим загрузки, запоминающий отправленные сообщения.
+type fakeUploadStream struct {
+	grpc.ClientStream
+	sent []*datapb.UploadBinaryRequest
+}
+
+func (f *fakeUploadStream) Send(req *datapb.UploadBinaryRequest) error {
+	f.sent = append(f.sent, proto.Clone(req).(*datapb.UploadBinaryRequest))
+	return nil
+}
+
+func (f *fakeUploadStream) CloseAndRecv() (*datapb.UploadBinaryResponse, error) {
+	return &datapb.UploadBinaryResponse{Id: 7}, nil
+}
+
+// fakeDownloadStream - клиентский стрим скачивания, отдающий заранее заданные сообщения.
+type fakeDownloadStream struct {
+	grpc.ClientStream
+	messages []*datapb.DownloadBinaryResponse
+}
+
+func (f *fakeDownloadStream) Recv() (*datapb.DownloadBinaryResponse, error) {
+	if len(f.messages) == 0 {
+		return nil, io.EOF
+	}
+	msg := f.messages[0]
+	f.messages = f.messages[1:]
+	return msg, nil
+}
+
 func TestDataService_AddData(t *testing.T) {
 	mockClient := new(MockDataServiceClient)
 	mockLogger := new(mockLogger)
@@ -150,13 +260,13 @@ func TestDataService_DeleteData(t *testing.T) {
 
 	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
 
-	expectedRequest := &datapb.DeleteDataRequest{Id: dataID}
+	expectedRequest := &datapb.DeleteDataRequest{Id: dataID, Version: 3}
 
 	expectedResponse := &datapb.DeleteDataResponse{}
 
 	mockClient.On("DeleteData", ctxWithMetadata, expectedRequest).Return(expectedResponse, nil)
 
-	err := dataService.DeleteData(ctx, token, dataID)
+	err := dataService.DeleteData(ctx, token, dataID, 3)
 
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t)
@@ -191,3 +301,303 @@ func TestDataService_AddData_Error(t *testing.T) {
 	assert.Equal(t, int32(0), id)
 	mockClient.AssertExpectations(t)
 }
+
+func TestDataService_ListData(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	mockLogger := new(mockLogger)
+
+	dataService := &dataService{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	ctx := context.Background()
+	token := "test-token"
+
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	expectedRequest := &datapb.ListDataRequest{InfoType: "text", PageSize: 10}
+
+	expectedResponse := &datapb.ListDataResponse{
+		Items:      []*datapb.DataHeader{{Id: 1, InfoType: "text", Meta: "test meta"}},
+		NextCursor: "next",
+	}
+
+	mockClient.On("ListData", ctxWithMetadata, expectedRequest).Return(expectedResponse, nil)
+
+	res, err := dataService.ListData(ctx, token, expectedRequest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, res)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_ListData_Error(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	mockLogger := new(mockLogger)
+
+	dataService := &dataService{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	ctx := context.Background()
+	token := "test-token"
+
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	expectedRequest := &datapb.ListDataRequest{}
+
+	mockClient.On("ListData", ctxWithMetadata, expectedRequest).Return((*datapb.ListDataResponse)(nil), errors.New("test error"))
+
+	res, err := dataService.ListData(ctx, token, expectedRequest)
+
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_UploadBinary(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+	ctx := context.Background()
+	token := "test-token"
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	content := bytes.Repeat([]byte("ab"), transferChunkSize)
+	header := &datapb.BinaryHeader{Info: "info", Size: int64(len(content))}
+
+	stream := &fakeUploadStream{}
+	mockClient.On("UploadBinary", ctxWithMetadata).Return(stream, nil)
+
+	id, err := dataService.UploadBinary(ctx, token, header, bytes.NewReader(content))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), id)
+	assert.Len(t, stream.sent, 3)
+	assert.Equal(t, "info", stream.sent[0].GetHeader().GetInfo())
+
+	var received []byte
+	for _, msg := range stream.sent[1:] {
+		received = append(received, msg.GetChunk()...)
+	}
+	assert.Equal(t, content, received)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_DownloadBinary(t *testing.T) {
+	content := []byte("binary content")
+	sum := sha256.Sum256(content)
+
+	tests := []struct {
+		name    string
+		header  *datapb.BinaryHeader
+		wantErr error
+	}{
+		{
+			name:   "успешное скачивание",
+			header: &datapb.BinaryHeader{Id: 1, Size: int64(len(content)), Sha256: hex.EncodeToString(sum[:])},
+		},
+		{
+			name:    "не совпала контрольная сумма",
+			header:  &datapb.BinaryHeader{Id: 1, Size: int64(len(content)), Sha256: hex.EncodeToString(make([]byte, 32))},
+			wantErr: ErrChecksumMismatch,
+		},
+		{
+			name:    "не совпал размер",
+			header:  &datapb.BinaryHeader{Id: 1, Size: 1, Sha256: hex.EncodeToString(sum[:])},
+			wantErr: ErrChecksumMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockDataServiceClient)
+			dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+			ctx := context.Background()
+			token := "test-token"
+			ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+			stream := &fakeDownloadStream{messages: []*datapb.DownloadBinaryResponse{
+				{Part: &datapb.DownloadBinaryResponse_Header{Header: tt.header}},
+				{Part: &datapb.DownloadBinaryResponse_Chunk{Chunk: content[:6]}},
+				{Part: &datapb.DownloadBinaryResponse_Chunk{Chunk: content[6:]}},
+			}}
+			mockClient.On("DownloadBinary", ctxWithMetadata, &datapb.DownloadBinaryRequest{Id: 1}).Return(stream, nil)
+
+			var out bytes.Buffer
+			header, err := dataService.DownloadBinary(ctx, token, 1, &out)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.header, header)
+			assert.Equal(t, content, out.Bytes())
+		})
+	}
+}
+
+func TestDataService_GetChanges(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+	ctx := context.Background()
+	token := "test-token"
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	expectedResponse := &datapb.GetChangesResponse{
+		Upserts:   []*datapb.DataItem{{Id: 1, Revision: 5}},
+		Deletions: []*datapb.Tombstone{{Id: 2, Revision: 6}},
+		Revision:  6,
+	}
+	mockClient.On("GetChanges", ctxWithMetadata, &datapb.GetChangesRequest{SinceRevision: 4}).Return(expectedResponse, nil)
+
+	res, err := dataService.GetChanges(ctx, token, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, res)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_ListFolders(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+	ctx := context.Background()
+	token := "test-token"
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	mockClient.On("ListFolders", ctxWithMetadata, &datapb.ListFoldersRequest{}).
+		Return(&datapb.ListFoldersResponse{Folders: []string{"home", "work/db"}}, nil)
+
+	folders, err := dataService.ListFolders(ctx, token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"home", "work/db"}, folders)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_SearchAndIndex(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+	ctx := context.Background()
+	token := "test-token"
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	req := &datapb.SearchDataRequest{Tokens: []string{"t1"}, PageSize: 10}
+	expected := &datapb.SearchDataResponse{Items: []*datapb.DataHeader{{Id: 3}}, NextCursor: "c"}
+	mockClient.On("SearchData", ctxWithMetadata, req).Return(expected, nil)
+	mockClient.On("IndexData", ctxWithMetadata, &datapb.IndexDataRequest{Id: 3, SearchTokens: []string{"t1"}}).
+		Return(&datapb.IndexDataResponse{}, nil)
+
+	res, err := dataService.SearchData(ctx, token, req)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, res)
+
+	assert.NoError(t, dataService.IndexData(ctx, token, 3, []string{"t1"}))
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_Versions(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+	ctx := context.Background()
+	token := "test-token"
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	expected := &datapb.ListVersionsResponse{Versions: []*datapb.DataVersion{{Version: 2, Info: "old"}}, VaultId: 4}
+	mockClient.On("ListVersions", ctxWithMetadata, &datapb.ListVersionsRequest{Id: 3}).Return(expected, nil)
+	mockClient.On("RestoreVersion", ctxWithMetadata, &datapb.RestoreVersionRequest{Id: 3, Version: 2}).
+		Return(&datapb.RestoreVersionResponse{Version: 5}, nil)
+	mockClient.On("RestoreVersion", ctxWithMetadata, &datapb.RestoreVersionRequest{Id: 3, Version: 9}).
+		Return(nil, status.Error(codes.NotFound, "такой версии записи нет в истории"))
+
+	res, err := dataService.ListVersions(ctx, token, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, res)
+
+	version, err := dataService.RestoreVersion(ctx, token, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), version)
+
+	_, err = dataService.RestoreVersion(ctx, token, 3, 9)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_Trash(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+	ctx := context.Background()
+	token := "test-token"
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	expected := &datapb.ListTrashResponse{Items: []*datapb.DataHeader{{Id: 3, Meta: "meta"}}}
+	mockClient.On("ListTrash", ctxWithMetadata, &datapb.ListTrashRequest{VaultId: 4}).Return(expected, nil)
+	mockClient.On("RestoreData", ctxWithMetadata, &datapb.RestoreDataRequest{Id: 3}).
+		Return(&datapb.RestoreDataResponse{Version: 5}, nil)
+	mockClient.On("PurgeData", ctxWithMetadata, &datapb.PurgeDataRequest{Id: 3}).
+		Return(nil, status.Error(codes.NotFound, "данные не найдены"))
+
+	res, err := dataService.ListTrash(ctx, token, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, res)
+
+	version, err := dataService.RestoreData(ctx, token, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), version)
+
+	err = dataService.PurgeData(ctx, token, 3)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_UpdateData_VersionConflict(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+	ctx := context.Background()
+	token := "test-token"
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	st, err := status.New(codes.Aborted, "версия записи устарела").
+		WithDetails(&datapb.VersionConflict{Id: 1, CurrentVersion: 4})
+	assert.NoError(t, err)
+
+	dataItem := &datapb.DataItem{Id: 1, InfoType: "text", Version: 3}
+	mockClient.On("UpdateData", ctxWithMetadata, &datapb.UpdateDataRequest{Data: dataItem}).
+		Return((*datapb.UpdateDataResponse)(nil), st.Err())
+
+	err = dataService.UpdateData(ctx, token, dataItem)
+
+	var conflict *entity.VersionConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, &entity.VersionConflictError{ID: 1, CurrentVersion: 4}, conflict)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDataService_MoveData(t *testing.T) {
+	mockClient := new(MockDataServiceClient)
+	dataService := &dataService{client: mockClient, logger: new(mockLogger)}
+
+	ctx := context.Background()
+	token := "test-token"
+	ctxWithMetadata := metadata.AppendToOutgoingContext(ctx, "authorization", token)
+
+	dataItem := &datapb.DataItem{Id: 1, InfoType: "text", Info: "enc", Version: 2, VaultId: 5}
+	mockClient.On("MoveData", ctxWithMetadata, &datapb.MoveDataRequest{Data: dataItem, VaultId: 5}).
+		Return(&datapb.MoveDataResponse{Version: 3}, nil)
+
+	version, err := dataService.MoveData(ctx, token, dataItem, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	mockClient.AssertExpectations(t)
+}
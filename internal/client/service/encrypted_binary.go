@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+)
+
+// UploadBinary - шифрует файл по чанкам и отправляет его стримом.
+// Имя файла и MIME уходят в зашифрованном info, содержимое в info не попадает.
+// Файл читается дважды: первый проход считает размер и SHA-256 шифротекста для заголовка.
+func (s *encryptedDataService) UploadBinary(
+	ctx context.Context, token string, item *datapb.DataItem, src io.ReadSeeker,
+) (int32, error) {
+	key, err := s.key()
+	if err != nil {
+		return 0, err
+	}
+
+	file := item.GetBinary()
+	if file == nil {
+		return 0, fmt.Errorf("%w: ожидается бинарный payload", payload.ErrInvalid)
+	}
+	described := &datapb.DataItem{
+		InfoType: payload.TypeBinary,
+		Meta:     item.Meta,
+		Payload: &datapb.DataItem_Binary{
+			Binary: &datapb.Binary{Filename: file.Filename, Mime: file.Mime},
+		},
+	}
+	encrypted, err := encryptItem(key, described)
+	if err != nil {
+		return 0, err
+	}
+
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return 0, fmt.Errorf("ошибка генерации nonce: %w", err)
+	}
+
+	size, sum, err := sealedChecksum(key, prefix, src)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+
+	sealed, err := newEncryptingReader(key, prefix, src)
+	if err != nil {
+		return 0, err
+	}
+
+	header := &datapb.BinaryHeader{
+		Info:         encrypted.Info,
+		Meta:         encrypted.Meta,
+		Size:         size,
+		Sha256:       sum,
+		SearchTokens: encrypted.SearchTokens,
+		Created:      item.Created,
+	}
+
+	return s.dataService.UploadBinary(ctx, token, header, sealed)
+}
+
+// DownloadBinary - скачивает файл, расшифровывает его в dst
+// и возвращает запись с именем файла и MIME.
+func (s *encryptedDataService) DownloadBinary(
+	ctx context.Context, token string, id int32, dst io.Writer,
+) (*datapb.DataItem, error) {
+	key, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := newDecryptingWriter(key, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := s.dataService.DownloadBinary(ctx, token, id, plain)
+	if err != nil {
+		return nil, err
+	}
+	if err := plain.Close(); err != nil {
+		return nil, err
+	}
+
+	info, err := decryptField(key, header.Info)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := decryptField(key, header.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &datapb.DataItem{
+		Id:       header.Id,
+		InfoType: payload.TypeBinary,
+		Info:     info,
+		Meta:     meta,
+	}
+	payload.Decode(info, item)
+
+	return item, nil
+}
+
+func sealedChecksum(key, prefix []byte, src io.Reader) (int64, string, error) {
+	sealed, err := newEncryptingReader(key, prefix, src)
+	if err != nil {
+		return 0, "", err
+	}
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, sealed)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hasher.Sum(nil)), nil
+}
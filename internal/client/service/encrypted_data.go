@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/blindindex"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+)
+
+type plainDataService interface {
+	AddData(ctx context.Context, token string, data *datapb.DataItem) (int32, error)
+	GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error)
+	UpdateData(ctx context.Context, token string, data *datapb.DataItem) error
+	DeleteData(ctx context.Context, token string, id int32, version int64) error
+	ListData(ctx context.Context, token string, req *datapb.ListDataRequest) (*datapb.ListDataResponse, error)
+	ListFolders(ctx context.Context, token string) ([]string, error)
+	UploadBinary(ctx context.Context, token string, header *datapb.BinaryHeader, r io.Reader) (int32, error)
+	DownloadBinary(ctx context.Context, token string, id int32, w io.Writer) (*datapb.BinaryHeader, error)
+	MoveData(ctx context.Context, token string, data *datapb.DataItem, vaultID int32) (int64, error)
+	SearchData(ctx context.Context, token string, req *datapb.SearchDataRequest) (*datapb.SearchDataResponse, error)
+	IndexData(ctx context.Context, token string, id int32, tokens []string) error
+	ListVersions(ctx context.Context, token string, id int32) (*datapb.ListVersionsResponse, error)
+	RestoreVersion(ctx context.Context, token string, id int32, version int64) (int64, error)
+	ListTrash(ctx context.Context, token string, vaultID int32) (*datapb.ListTrashResponse, error)
+	RestoreData(ctx context.Context, token string, id int32) (int64, error)
+	PurgeData(ctx context.Context, token string, id int32) error
+}
+
+// legacyPageSize - размер страницы при поиске открытых записей.
+const legacyPageSize = 100
+
+type vaultKeyProvider interface {
+	VaultKey(ctx context.Context, token string, vaultID int32) ([]byte, error)
+}
+
+type encryptedDataService struct {
+	dataService plainDataService
+	keyHolder   *entity.KeyHolder
+	vaultKeys   vaultKeyProvider
+}
+
+// NewEncryptedDataService - конструктор обёртки над сервисом данных,
+// которая шифрует info и meta до отправки на сервер и расшифровывает после получения.
+// Личные записи шифруются ключом из мастер-пароля, записи общих хранилищ -
+// ключом хранилища из vaultKeys.
+func NewEncryptedDataService(
+	dataService plainDataService, keyHolder *entity.KeyHolder, vaultKeys vaultKeyProvider,
+) *encryptedDataService {
+	return &encryptedDataService{dataService: dataService, keyHolder: keyHolder, vaultKeys: vaultKeys}
+}
+
+func (s *encryptedDataService) AddData(ctx context.Context, token string, data *datapb.DataItem) (int32, error) {
+	key, err := s.keyFor(ctx, token, data.VaultId)
+	if err != nil {
+		return 0, err
+	}
+
+	encrypted, err := encryptItem(key, data)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.dataService.AddData(ctx, token, encrypted)
+}
+
+func (s *encryptedDataService) GetData(ctx context.Context, token string, id int32) (*datapb.DataItem, error) {
+	if _, err := s.key(); err != nil {
+		return nil, err
+	}
+
+	data, err := s.dataService.GetData(ctx, token, id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyFor(ctx, token, data.VaultId)
+	if err != nil {
+		return nil, err
+	}
+
+	item, _, err := openItem(key, data)
+	return item, err
+}
+
+// LegacyItems - личные записи, которые сервер хранит открытым текстом,
+// потому что они сохранены до появления шифрования. Их содержимое не
+// защищено ключом и могло быть изменено на сервере, поэтому клиент не
+// перешифровывает их сам: пользователь проверяет записи и сохраняет их
+// через UpdateData. Записи в корзине не проверяются.
+func (s *encryptedDataService) LegacyItems(ctx context.Context, token string) ([]*datapb.DataItem, error) {
+	key, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*datapb.DataItem
+	req := &datapb.ListDataRequest{PageSize: legacyPageSize}
+	for {
+		res, err := s.dataService.ListData(ctx, token, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, header := range res.Items {
+			data, err := s.dataService.GetData(ctx, token, header.Id)
+			if err != nil {
+				return nil, err
+			}
+			item, legacy, err := openItem(key, data)
+			if err != nil {
+				return nil, err
+			}
+			if legacy {
+				items = append(items, item)
+			}
+		}
+		if res.NextCursor == "" {
+			return items, nil
+		}
+		req.Cursor = res.NextCursor
+	}
+}
+
+// UpdateData - data.VaultId должен совпадать с хранилищем записи: по нему
+// выбирается ключ, а сервер отклонит изменение с другим хранилищем.
+func (s *encryptedDataService) UpdateData(ctx context.Context, token string, data *datapb.DataItem) error {
+	key, err := s.keyFor(ctx, token, data.VaultId)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := encryptItem(key, data)
+	if err != nil {
+		return err
+	}
+
+	return s.dataService.UpdateData(ctx, token, encrypted)
+}
+
+// MoveData - перешифровывает запись ключом хранилища vaultID (0 - личные данные)
+// и переносит её туда. data - расшифрованная запись текущей версии.
+func (s *encryptedDataService) MoveData(
+	ctx context.Context, token string, data *datapb.DataItem, vaultID int32,
+) (int64, error) {
+	key, err := s.keyFor(ctx, token, vaultID)
+	if err != nil {
+		return 0, err
+	}
+
+	encrypted, err := encryptItem(key, data)
+	if err != nil {
+		return 0, err
+	}
+	encrypted.VaultId = vaultID
+
+	return s.dataService.MoveData(ctx, token, encrypted, vaultID)
+}
+
+func (s *encryptedDataService) DeleteData(ctx context.Context, token string, id int32, version int64) error {
+	return s.dataService.DeleteData(ctx, token, id, version)
+}
+
+func (s *encryptedDataService) ListData(
+	ctx context.Context, token string, req *datapb.ListDataRequest,
+) (*datapb.ListDataResponse, error) {
+	key, err := s.keyFor(ctx, token, req.VaultId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.dataService.ListData(ctx, token, req)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := decryptHeaders(key, res.Items, req.VaultId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &datapb.ListDataResponse{Items: items, NextCursor: res.NextCursor}, nil
+}
+
+// SearchData - ищет записи хранилища req.VaultId (0 - личные), в описании
+// которых есть все слова query. Слова уходят на сервер только токенами
+// слепого индекса, посчитанными ключом этого хранилища.
+func (s *encryptedDataService) SearchData(
+	ctx context.Context, token, query string, req *datapb.SearchDataRequest,
+) (*datapb.SearchDataResponse, error) {
+	key, err := s.keyFor(ctx, token, req.VaultId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := blindindex.Query(blindindex.Key(key), query)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.dataService.SearchData(ctx, token, &datapb.SearchDataRequest{
+		Tokens:   tokens,
+		VaultId:  req.VaultId,
+		PageSize: req.PageSize,
+		Cursor:   req.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := decryptHeaders(key, res.Items, req.VaultId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &datapb.SearchDataResponse{Items: items, NextCursor: res.NextCursor}, nil
+}
+
+// IndexData - пересчитывает токены поиска записи id по её расшифрованному
+// описанию meta. Нужен для записей, сохранённых до появления поиска.
+func (s *encryptedDataService) IndexData(ctx context.Context, token string, id, vaultID int32, meta string) error {
+	key, err := s.keyFor(ctx, token, vaultID)
+	if err != nil {
+		return err
+	}
+
+	return s.dataService.IndexData(ctx, token, id, blindindex.Tokens(blindindex.Key(key), meta))
+}
+
+// ListVersions - прошлые версии записи id с расшифрованными info и meta.
+// Ключ выбирается по хранилищу записи из ответа сервера.
+func (s *encryptedDataService) ListVersions(
+	ctx context.Context, token string, id int32,
+) (*datapb.ListVersionsResponse, error) {
+	if _, err := s.key(); err != nil {
+		return nil, err
+	}
+
+	res, err := s.dataService.ListVersions(ctx, token, id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyFor(ctx, token, res.VaultId)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*datapb.DataVersion, 0, len(res.Versions))
+	for _, v := range res.Versions {
+		info, _, err := openField(key, v.Info, res.VaultId)
+		if err != nil {
+			return nil, err
+		}
+		meta, _, err := openField(key, v.Meta, res.VaultId)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, &datapb.DataVersion{
+			Version:  v.Version,
+			InfoType: v.InfoType,
+			Info:     info,
+			Meta:     meta,
+			SavedAt:  v.SavedAt,
+		})
+	}
+
+	return &datapb.ListVersionsResponse{Versions: versions, VaultId: res.VaultId}, nil
+}
+
+// RestoreVersion - восстанавливает версию version записи id и пересчитывает
+// токены поиска по восстановленному описанию: история хранит только
+// зашифрованное содержимое, и сервер сам посчитать их не может.
+func (s *encryptedDataService) RestoreVersion(
+	ctx context.Context, token string, id int32, version int64,
+) (int64, error) {
+	restored, err := s.dataService.RestoreVersion(ctx, token, id, version)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := s.GetData(ctx, token, id)
+	if err != nil {
+		return restored, fmt.Errorf("версия восстановлена, но индекс поиска не обновлён: %w", err)
+	}
+	if err := s.IndexData(ctx, token, id, data.VaultId, data.Meta); err != nil {
+		return restored, fmt.Errorf("версия восстановлена, но индекс поиска не обновлён: %w", err)
+	}
+
+	return restored, nil
+}
+
+// ListTrash - записи из корзины хранилища vaultID (0 - личные) с расшифрованной meta.
+func (s *encryptedDataService) ListTrash(
+	ctx context.Context, token string, vaultID int32,
+) (*datapb.ListTrashResponse, error) {
+	key, err := s.keyFor(ctx, token, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.dataService.ListTrash(ctx, token, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := decryptHeaders(key, res.Items, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &datapb.ListTrashResponse{Items: items}, nil
+}
+
+// RestoreData - токены поиска записи хранятся, пока она в корзине,
+// поэтому после восстановления индекс пересчитывать не нужно.
+func (s *encryptedDataService) RestoreData(ctx context.Context, token string, id int32) (int64, error) {
+	return s.dataService.RestoreData(ctx, token, id)
+}
+
+func (s *encryptedDataService) PurgeData(ctx context.Context, token string, id int32) error {
+	return s.dataService.PurgeData(ctx, token, id)
+}
+
+// ListFolders - пути папок не шифруются, поэтому запрос передаётся как есть.
+func (s *encryptedDataService) ListFolders(ctx context.Context, token string) ([]string, error) {
+	return s.dataService.ListFolders(ctx, token)
+}
+
+// encryptItem - возвращает копию записи с зашифрованными info и meta.
+// Типизированный payload проверяется и упаковывается в info до шифрования,
+// поэтому сервер его не видит. Тип информации, теги, папка и избранное
+// остаются открытыми, чтобы сервер мог по ним фильтровать; по meta
+// считаются токены слепого индекса для поиска.
+func encryptItem(key []byte, data *datapb.DataItem) (*datapb.DataItem, error) {
+	if err := payload.Validate(data); err != nil {
+		return nil, err
+	}
+
+	var err error
+	plainInfo := data.Info
+	if data.Payload != nil {
+		plainInfo, err = payload.Encode(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := encryptField(key, plainInfo)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := encryptField(key, data.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &datapb.DataItem{
+		Id:           data.Id,
+		InfoType:     data.InfoType,
+		Info:         info,
+		Meta:         meta,
+		Created:      data.Created,
+		Version:      data.Version,
+		VaultId:      data.VaultId,
+		Tags:         data.Tags,
+		Folder:       data.Folder,
+		Favorite:     data.Favorite,
+		SearchTokens: blindindex.Tokens(blindindex.Key(key), data.Meta),
+	}, nil
+}
+
+// decryptHeaders - копии заголовков с расшифрованной meta записей хранилища vaultID.
+func decryptHeaders(key []byte, headers []*datapb.DataHeader, vaultID int32) ([]*datapb.DataHeader, error) {
+	items := make([]*datapb.DataHeader, 0, len(headers))
+	for _, item := range headers {
+		meta, _, err := openField(key, item.Meta, vaultID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &datapb.DataHeader{
+			Id:        item.Id,
+			InfoType:  item.InfoType,
+			Meta:      meta,
+			Created:   item.Created,
+			Tags:      item.Tags,
+			Folder:    item.Folder,
+			Favorite:  item.Favorite,
+			DeletedAt: item.DeletedAt,
+		})
+	}
+
+	return items, nil
+}
+
+// openItem - расшифрованная копия записи data. legacy - запись сохранена
+// до появления шифрования и пришла открытым текстом.
+func openItem(key []byte, data *datapb.DataItem) (item *datapb.DataItem, legacy bool, err error) {
+	info, legacyInfo, err := openField(key, data.Info, data.VaultId)
+	if err != nil {
+		return nil, false, err
+	}
+	meta, legacyMeta, err := openField(key, data.Meta, data.VaultId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	item = &datapb.DataItem{
+		Id:       data.Id,
+		InfoType: data.InfoType,
+		Info:     info,
+		Meta:     meta,
+		Created:  data.Created,
+		Version:  data.Version,
+		VaultId:  data.VaultId,
+		Tags:     data.Tags,
+		Folder:   data.Folder,
+		Favorite: data.Favorite,
+	}
+	payload.Decode(info, item)
+
+	return item, legacyInfo || legacyMeta, nil
+}
+
+// openField - расшифровывает поле записи хранилища vaultID. Поле с меткой
+// версии обязано расшифроваться. Поле без метки - либо шифртекст прежних
+// клиентов, который проверяет AEAD, либо открытый текст личной записи,
+// сохранённой до появления шифрования: тогда оно возвращается как есть с
+// legacy = true. В общих хранилищах открытых записей не бывает.
+func openField(key []byte, value string, vaultID int32) (plain string, legacy bool, err error) {
+	if isSealed(value) {
+		plain, err = decryptField(key, value)
+		return plain, false, err
+	}
+
+	if plain, err = decryptField(key, value); err == nil {
+		return plain, false, nil
+	}
+	if vaultID != 0 {
+		return "", false, err
+	}
+
+	return value, true, nil
+}
+
+func (s *encryptedDataService) key() ([]byte, error) {
+	if len(s.keyHolder.Key) == 0 {
+		return nil, fmt.Errorf("ключ шифрования не задан, выполните вход")
+	}
+
+	return s.keyHolder.Key, nil
+}
+
+// keyFor - ключ личных данных для vaultID 0, иначе ключ общего хранилища.
+func (s *encryptedDataService) keyFor(ctx context.Context, token string, vaultID int32) ([]byte, error) {
+	if vaultID == 0 || s.vaultKeys == nil {
+		if vaultID != 0 {
+			return nil, fmt.Errorf("общие хранилища не поддерживаются")
+		}
+		return s.key()
+	}
+
+	return s.vaultKeys.VaultKey(ctx, token, vaultID)
+}
@@ -0,0 +1,687 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeDataStore - хранит записи в памяти так, как их видит сервер.
+type fakeDataStore struct {
+	items    map[int32]*datapb.DataItem
+	headers  map[int32]*datapb.BinaryHeader
+	blobs    map[int32][]byte
+	versions map[int32][]*datapb.DataVersion
+	trash    map[int32]*datapb.DataItem
+}
+
+func newFakeDataStore() *fakeDataStore {
+	return &fakeDataStore{
+		items:    make(map[int32]*datapb.DataItem),
+		headers:  make(map[int32]*datapb.BinaryHeader),
+		blobs:    make(map[int32][]byte),
+		versions: make(map[int32][]*datapb.DataVersion),
+		trash:    make(map[int32]*datapb.DataItem),
+	}
+}
+
+func (f *fakeDataStore) AddData(_ context.Context, _ string, data *datapb.DataItem) (int32, error) {
+	id := int32(len(f.items) + 1)
+	data.Id = id
+	f.items[id] = data
+	return id, nil
+}
+
+func (f *fakeDataStore) GetData(_ context.Context, _ string, id int32) (*datapb.DataItem, error) {
+	return f.items[id], nil
+}
+
+// UpdateData - как сервер, кладёт прежнее содержимое записи в историю.
+func (f *fakeDataStore) UpdateData(_ context.Context, _ string, data *datapb.DataItem) error {
+	f.archive(data.Id)
+	f.items[data.Id] = data
+	return nil
+}
+
+func (f *fakeDataStore) archive(id int32) {
+	if old, ok := f.items[id]; ok {
+		f.versions[id] = append([]*datapb.DataVersion{{
+			Version: old.Version, InfoType: old.InfoType, Info: old.Info, Meta: old.Meta,
+		}}, f.versions[id]...)
+	}
+}
+
+// DeleteData - как сервер, переносит запись в корзину.
+func (f *fakeDataStore) DeleteData(_ context.Context, _ string, id int32, _ int64) error {
+	f.trash[id] = f.items[id]
+	delete(f.items, id)
+	return nil
+}
+
+func (f *fakeDataStore) ListTrash(_ context.Context, _ string, _ int32) (*datapb.ListTrashResponse, error) {
+	res := &datapb.ListTrashResponse{}
+	for _, id := range slices.Sorted(maps.Keys(f.trash)) {
+		item := f.trash[id]
+		res.Items = append(res.Items, &datapb.DataHeader{
+			Id: item.Id, InfoType: item.InfoType, Meta: item.Meta, DeletedAt: timestamppb.Now(),
+		})
+	}
+	return res, nil
+}
+
+func (f *fakeDataStore) RestoreData(_ context.Context, _ string, id int32) (int64, error) {
+	item, ok := f.trash[id]
+	if !ok {
+		return 0, errors.New("записи нет в корзине")
+	}
+	delete(f.trash, id)
+	item.Version++
+	f.items[id] = item
+	return item.Version, nil
+}
+
+func (f *fakeDataStore) PurgeData(_ context.Context, _ string, id int32) error {
+	if _, ok := f.trash[id]; !ok {
+		return errors.New("записи нет в корзине")
+	}
+	delete(f.trash, id)
+	delete(f.versions, id)
+	return nil
+}
+
+func (f *fakeDataStore) ListData(
+	_ context.Context, _ string, _ *datapb.ListDataRequest,
+) (*datapb.ListDataResponse, error) {
+	res := &datapb.ListDataResponse{}
+	for _, item := range f.items {
+		res.Items = append(res.Items, &datapb.DataHeader{Id: item.Id, InfoType: item.InfoType, Meta: item.Meta})
+	}
+	return res, nil
+}
+
+func (f *fakeDataStore) ListFolders(_ context.Context, _ string) ([]string, error) {
+	folders := make([]string, 0)
+	for _, item := range f.items {
+		if item.Folder != "" && !slices.Contains(folders, item.Folder) {
+			folders = append(folders, item.Folder)
+		}
+	}
+	sort.Strings(folders)
+	return folders, nil
+}
+
+// SearchData - записи, у которых есть все токены запроса, по возрастанию ID.
+func (f *fakeDataStore) SearchData(
+	_ context.Context, _ string, req *datapb.SearchDataRequest,
+) (*datapb.SearchDataResponse, error) {
+	res := &datapb.SearchDataResponse{}
+	ids := slices.Sorted(maps.Keys(f.items))
+	for _, id := range ids {
+		item := f.items[id]
+		found := true
+		for _, token := range req.Tokens {
+			found = found && slices.Contains(item.SearchTokens, token)
+		}
+		if found {
+			res.Items = append(res.Items, &datapb.DataHeader{Id: item.Id, InfoType: item.InfoType, Meta: item.Meta})
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeDataStore) IndexData(_ context.Context, _ string, id int32, tokens []string) error {
+	f.items[id].SearchTokens = tokens
+	return nil
+}
+
+func (f *fakeDataStore) ListVersions(_ context.Context, _ string, id int32) (*datapb.ListVersionsResponse, error) {
+	return &datapb.ListVersionsResponse{Versions: f.versions[id], VaultId: f.items[id].VaultId}, nil
+}
+
+func (f *fakeDataStore) RestoreVersion(_ context.Context, _ string, id int32, version int64) (int64, error) {
+	for _, v := range f.versions[id] {
+		if v.Version == version {
+			f.archive(id)
+			item := f.items[id]
+			item.InfoType, item.Info, item.Meta = v.InfoType, v.Info, v.Meta
+			item.Version++
+			return item.Version, nil
+		}
+	}
+	return 0, errors.New("такой версии записи нет в истории")
+}
+
+func (f *fakeDataStore) MoveData(_ context.Context, _ string, data *datapb.DataItem, vaultID int32) (int64, error) {
+	data.VaultId = vaultID
+	data.Version++
+	f.items[data.Id] = data
+	return data.Version, nil
+}
+
+func (f *fakeDataStore) UploadBinary(
+	_ context.Context, _ string, header *datapb.BinaryHeader, r io.Reader,
+) (int32, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	sum := sha256.Sum256(content)
+	if int64(len(content)) != header.Size || hex.EncodeToString(sum[:]) != header.Sha256 {
+		return 0, ErrChecksumMismatch
+	}
+
+	id := int32(len(f.items) + len(f.headers) + 1)
+	header.Id = id
+	f.headers[id] = header
+	f.blobs[id] = content
+	return id, nil
+}
+
+func (f *fakeDataStore) DownloadBinary(
+	_ context.Context, _ string, id int32, w io.Writer,
+) (*datapb.BinaryHeader, error) {
+	if _, err := w.Write(f.blobs[id]); err != nil {
+		return nil, err
+	}
+	return f.headers[id], nil
+}
+
+// testKey - ключ с облегчёнными параметрами Argon2id, чтобы тесты шли быстро;
+// DeriveKey такие параметры отклоняет.
+func testKey(t *testing.T, masterPassword string) []byte {
+	t.Helper()
+
+	params := &entity.KDFParams{Salt: []byte("0123456789abcdef"), Time: 1, Memory: 1024, Threads: 1}
+	return deriveKey(masterPassword, params)
+}
+
+func TestCryptoService_DeriveKey(t *testing.T) {
+	params := &entity.KDFParams{
+		Salt: []byte("0123456789abcdef"), Time: minKDFTime, Memory: minKDFMemory, Threads: 4,
+	}
+	first, err := NewCryptoService().DeriveKey("master", params)
+	require.NoError(t, err)
+	second, err := NewCryptoService().DeriveKey("master", params)
+	require.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, testKey(t, "master"))
+
+	_, err = NewCryptoService().DeriveKey("master", nil)
+	assert.Error(t, err)
+}
+
+func TestCryptoService_DeriveKey_Bounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		params entity.KDFParams
+	}{
+		{"нулевое время", entity.KDFParams{Time: 0, Memory: minKDFMemory, Threads: 1}},
+		{"одна итерация", entity.KDFParams{Time: 1, Memory: minKDFMemory, Threads: 1}},
+		{"слишком долго", entity.KDFParams{Time: maxKDFTime + 1, Memory: minKDFMemory, Threads: 1}},
+		{"нет памяти", entity.KDFParams{Time: minKDFTime, Memory: 0, Threads: 1}},
+		{"мало памяти", entity.KDFParams{Time: minKDFTime, Memory: 8 * 1024, Threads: 1}},
+		{"слишком много памяти", entity.KDFParams{Time: minKDFTime, Memory: maxKDFMemory + 1, Threads: 1}},
+		{"нет потоков", entity.KDFParams{Time: minKDFTime, Memory: minKDFMemory, Threads: 0}},
+		{"потоки не влезают в uint8", entity.KDFParams{Time: minKDFTime, Memory: minKDFMemory, Threads: 256}},
+		{"короткая соль", entity.KDFParams{Salt: []byte("salt"), Time: minKDFTime, Memory: minKDFMemory, Threads: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if params.Salt == nil {
+				params.Salt = []byte("0123456789abcdef")
+			}
+
+			_, err := NewCryptoService().DeriveKey("master", &params)
+
+			assert.ErrorContains(t, err, "недопустимые параметры KDF")
+		})
+	}
+}
+
+func TestCryptoService_KeyCheck(t *testing.T) {
+	crypto := NewCryptoService()
+	key := testKey(t, "master")
+
+	keyCheck, err := crypto.NewKeyCheck(key)
+	require.NoError(t, err)
+
+	assert.NoError(t, crypto.CheckKey(key, keyCheck))
+	assert.ErrorIs(t, crypto.CheckKey(testKey(t, "other"), keyCheck), entity.ErrWrongMasterPassword)
+	assert.ErrorIs(t, crypto.CheckKey(key, []byte("garbage")), entity.ErrWrongMasterPassword)
+}
+
+func TestEncryptedDataService_LegacyPlaintext(t *testing.T) {
+	ctx := context.Background()
+	key := testKey(t, "master")
+	store := newFakeDataStore()
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: key}, nil)
+
+	// Открытый текст, который сам по себе - корректный base64 длиннее nonce и тега.
+	legacyInfo := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 48)))
+	store.items[1] = &datapb.DataItem{Id: 1, InfoType: "text", Info: legacyInfo, Meta: "note", Version: 3}
+	_, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "новая", Meta: "new"})
+	require.NoError(t, err)
+	oldFormat, err := encryptField(key, "до метки версии")
+	require.NoError(t, err)
+	store.items[3] = &datapb.DataItem{
+		Id: 3, InfoType: "text", Info: strings.TrimPrefix(oldFormat, sealedPrefix), Meta: oldFormat,
+	}
+
+	got, err := svc.GetData(ctx, "token", 1)
+	require.NoError(t, err)
+	assert.Equal(t, legacyInfo, got.Info)
+	assert.Equal(t, "note", got.Meta)
+	assert.Equal(t, legacyInfo, store.items[1].Info, "чтение не должно перезаписывать запись")
+
+	old, err := svc.GetData(ctx, "token", 3)
+	require.NoError(t, err)
+	assert.Equal(t, "до метки версии", old.Info)
+
+	legacy, err := svc.LegacyItems(ctx, "token")
+	require.NoError(t, err)
+	require.Len(t, legacy, 1)
+	assert.Equal(t, int32(1), legacy[0].Id)
+	assert.Equal(t, int64(3), legacy[0].Version)
+
+	require.NoError(t, svc.UpdateData(ctx, "token", legacy[0]))
+	assert.True(t, isSealed(store.items[1].Info))
+	assert.True(t, isSealed(store.items[1].Meta))
+	assert.NotEmpty(t, store.items[1].SearchTokens)
+
+	legacy, err = svc.LegacyItems(ctx, "token")
+	require.NoError(t, err)
+	assert.Empty(t, legacy)
+}
+
+func TestEncryptedDataService_SealedFieldMustDecrypt(t *testing.T) {
+	store := newFakeDataStore()
+	store.items[1] = &datapb.DataItem{Id: 1, InfoType: "text", Info: sealedPrefix + "подмена", Meta: "note"}
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: testKey(t, "master")}, nil)
+
+	_, err := svc.GetData(context.Background(), "token", 1)
+
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestEncryptedDataService_VaultRejectsPlaintext(t *testing.T) {
+	store := newFakeDataStore()
+	store.items[1] = &datapb.DataItem{Id: 1, InfoType: "text", Info: "открыто", Meta: "note", VaultId: 7}
+	svc := NewEncryptedDataService(
+		store, &entity.KeyHolder{Key: testKey(t, "master")}, staticVaultKeys{7: testKey(t, "vault")},
+	)
+
+	_, err := svc.GetData(context.Background(), "token", 1)
+
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestEncryptedDataService_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	keyHolder := &entity.KeyHolder{Key: testKey(t, "master")}
+	svc := NewEncryptedDataService(store, keyHolder, nil)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "secret", Meta: "note"})
+	require.NoError(t, err)
+
+	stored := store.items[id]
+	assert.Equal(t, "text", stored.InfoType)
+	assert.NotEqual(t, "secret", stored.Info)
+	assert.NotEqual(t, "note", stored.Meta)
+
+	got, err := svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", got.Info)
+	assert.Equal(t, "note", got.Meta)
+
+	err = svc.UpdateData(ctx, "token", &datapb.DataItem{Id: id, InfoType: "text", Info: "new", Meta: "new note"})
+	require.NoError(t, err)
+
+	got, err = svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, "new", got.Info)
+
+	list, err := svc.ListData(ctx, "token", &datapb.ListDataRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "new note", list.Items[0].Meta)
+}
+
+func TestEncryptedDataService_LabelsStayOpen(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: testKey(t, "master")}, nil)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{
+		InfoType: "text", Info: "secret", Tags: []string{"db"}, Folder: "work", Favorite: true,
+	})
+	require.NoError(t, err)
+
+	stored := store.items[id]
+	assert.Equal(t, []string{"db"}, stored.Tags)
+	assert.Equal(t, "work", stored.Folder)
+	assert.True(t, stored.Favorite)
+
+	got, err := svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db"}, got.Tags)
+	assert.Equal(t, "work", got.Folder)
+	assert.True(t, got.Favorite)
+
+	folders, err := svc.ListFolders(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work"}, folders)
+}
+
+func TestEncryptedDataService_Search(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: testKey(t, "master")}, nil)
+
+	githubID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "a", Meta: "GitHub рабочий"})
+	require.NoError(t, err)
+	mailID, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "b", Meta: "почта"})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, store.items[githubID].SearchTokens)
+	assert.NotContains(t, store.items[githubID].SearchTokens, "github")
+
+	res, err := svc.SearchData(ctx, "token", "git РАБ", &datapb.SearchDataRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+	assert.Equal(t, githubID, res.Items[0].Id)
+	assert.Equal(t, "GitHub рабочий", res.Items[0].Meta)
+
+	// Запись без индекса находится только после переиндексации.
+	store.items[mailID].SearchTokens = nil
+	res, err = svc.SearchData(ctx, "token", "почта", &datapb.SearchDataRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.Items)
+
+	require.NoError(t, svc.IndexData(ctx, "token", mailID, 0, "почта"))
+	res, err = svc.SearchData(ctx, "token", "почта", &datapb.SearchDataRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+	assert.Equal(t, mailID, res.Items[0].Id)
+
+	_, err = svc.SearchData(ctx, "token", "  ", &datapb.SearchDataRequest{})
+	assert.Error(t, err)
+}
+
+func TestEncryptedDataService_Versions(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: testKey(t, "master")}, nil)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{
+		InfoType: "login_password", Meta: "github",
+		Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{Login: "alice", Password: "old"}},
+	})
+	require.NoError(t, err)
+	store.items[id].Version = 1
+	require.NoError(t, svc.UpdateData(ctx, "token", &datapb.DataItem{
+		Id: id, InfoType: "login_password", Meta: "gitlab", Version: 1,
+		Payload: &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{Login: "alice", Password: "new"}},
+	}))
+	store.items[id].Version = 2
+
+	history, err := svc.ListVersions(ctx, "token", id)
+	require.NoError(t, err)
+	require.Len(t, history.Versions, 1)
+	assert.NotEqual(t, "github", store.versions[id][0].Meta)
+	assert.Equal(t, int64(1), history.Versions[0].Version)
+	assert.Equal(t, "github", history.Versions[0].Meta)
+
+	old := &datapb.DataItem{}
+	require.True(t, payload.Decode(history.Versions[0].Info, old))
+	assert.Equal(t, "old", old.GetLoginPassword().GetPassword())
+
+	version, err := svc.RestoreVersion(ctx, "token", id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+
+	got, err := svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, "github", got.Meta)
+	assert.Equal(t, "old", got.GetLoginPassword().GetPassword())
+
+	// Индекс поиска пересчитан по восстановленному описанию.
+	res, err := svc.SearchData(ctx, "token", "github", &datapb.SearchDataRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+	res, err = svc.SearchData(ctx, "token", "gitlab", &datapb.SearchDataRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.Items)
+
+	_, err = svc.RestoreVersion(ctx, "token", id, 9)
+	assert.Error(t, err)
+}
+
+func TestEncryptedDataService_Trash(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: testKey(t, "master")}, nil)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "secret", Meta: "github"})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteData(ctx, "token", id, 0))
+
+	trash, err := svc.ListTrash(ctx, "token", 0)
+	require.NoError(t, err)
+	require.Len(t, trash.Items, 1)
+	assert.NotEqual(t, "github", store.trash[id].Meta)
+	assert.Equal(t, "github", trash.Items[0].Meta)
+	assert.NotNil(t, trash.Items[0].DeletedAt)
+
+	_, err = svc.RestoreData(ctx, "token", id)
+	require.NoError(t, err)
+
+	// Токены поиска пережили корзину.
+	res, err := svc.SearchData(ctx, "token", "github", &datapb.SearchDataRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+
+	require.NoError(t, svc.DeleteData(ctx, "token", id, 1))
+	require.NoError(t, svc.PurgeData(ctx, "token", id))
+	trash, err = svc.ListTrash(ctx, "token", 0)
+	require.NoError(t, err)
+	assert.Empty(t, trash.Items)
+}
+
+func TestEncryptedDataService_WrongKey(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	keyHolder := &entity.KeyHolder{Key: testKey(t, "master")}
+	svc := NewEncryptedDataService(store, keyHolder, nil)
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "secret"})
+	require.NoError(t, err)
+
+	keyHolder.Key = testKey(t, "other")
+
+	_, err = svc.GetData(ctx, "token", id)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = svc.ListData(ctx, "token", &datapb.ListDataRequest{})
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestEncryptedDataService_NoKey(t *testing.T) {
+	svc := NewEncryptedDataService(newFakeDataStore(), &entity.KeyHolder{}, nil)
+
+	_, err := svc.AddData(context.Background(), "token", &datapb.DataItem{Info: "secret"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ключ шифрования не задан")
+}
+
+func TestEncryptedDataService_TypedPayload(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: testKey(t, "master")}, nil)
+
+	lp := &datapb.LoginPassword{Login: "user", Password: "secret", Url: "https://example.com"}
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{
+		InfoType: "login_password",
+		Payload:  &datapb.DataItem_LoginPassword{LoginPassword: lp},
+	})
+	require.NoError(t, err)
+
+	stored := store.items[id]
+	assert.Nil(t, stored.Payload)
+	assert.NotContains(t, stored.Info, "secret")
+
+	got, err := svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, "user", got.GetLoginPassword().GetLogin())
+	assert.Equal(t, "secret", got.GetLoginPassword().GetPassword())
+	assert.Empty(t, got.Info)
+
+	_, err = svc.AddData(ctx, "token", &datapb.DataItem{
+		InfoType: "bank_card",
+		Payload: &datapb.DataItem_BankCard{
+			BankCard: &datapb.BankCard{Number: "4111111111111112", Expiry: "12/29"},
+		},
+	})
+	assert.ErrorIs(t, err, payload.ErrInvalid)
+	assert.Len(t, store.items, 1)
+}
+
+func TestEncryptedDataService_Binary(t *testing.T) {
+	ctx := context.Background()
+	svc := NewEncryptedDataService(newFakeDataStore(), &entity.KeyHolder{Key: testKey(t, "master")}, nil)
+
+	sizes := []int{0, 1, plainChunkSize, plainChunkSize + 1, 3*plainChunkSize - 7}
+	for _, size := range sizes {
+		store := newFakeDataStore()
+		svc.dataService = store
+
+		content := make([]byte, size)
+		for i := range content {
+			content[i] = byte(i * 7)
+		}
+
+		id, err := svc.UploadBinary(ctx, "token", &datapb.DataItem{
+			Meta:    "скан",
+			Payload: &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: "scan.pdf", Mime: "application/pdf"}},
+		}, bytes.NewReader(content))
+		require.NoError(t, err, size)
+
+		assert.NotContains(t, store.headers[id].Info, "scan.pdf")
+		if size >= 64 {
+			assert.False(t, bytes.Contains(store.blobs[id], content[:64]))
+		}
+
+		var out bytes.Buffer
+		item, err := svc.DownloadBinary(ctx, "token", id, &out)
+		require.NoError(t, err, size)
+		assert.True(t, bytes.Equal(content, out.Bytes()), size)
+		assert.Equal(t, "scan.pdf", item.GetBinary().GetFilename())
+		assert.Equal(t, "application/pdf", item.GetBinary().GetMime())
+		assert.Equal(t, "скан", item.Meta)
+	}
+}
+
+func TestEncryptedDataService_BinaryTampered(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: testKey(t, "master")}, nil)
+
+	content := bytes.Repeat([]byte("x"), 2*plainChunkSize+10)
+	id, err := svc.UploadBinary(ctx, "token", &datapb.DataItem{
+		Payload: &datapb.DataItem_Binary{Binary: &datapb.Binary{Filename: "a.bin"}},
+	}, bytes.NewReader(content))
+	require.NoError(t, err)
+
+	sealed := store.blobs[id]
+	sealedChunk := plainChunkSize + 16
+
+	tests := []struct {
+		name    string
+		blob    []byte
+		wantErr error
+	}{
+		{
+			name:    "обрезан по границе чанка",
+			blob:    sealed[:noncePrefixSize+2*sealedChunk],
+			wantErr: errTruncated,
+		},
+		{
+			name: "чанки переставлены",
+			blob: append(append(append([]byte{}, sealed[:noncePrefixSize]...),
+				sealed[noncePrefixSize+sealedChunk:noncePrefixSize+2*sealedChunk]...),
+				sealed[noncePrefixSize:noncePrefixSize+sealedChunk]...),
+			wantErr: ErrDecrypt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store.blobs[id] = tt.blob
+
+			_, err := svc.DownloadBinary(ctx, "token", id, io.Discard)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// staticVaultKeys - ключи хранилищ, известные клиенту.
+type staticVaultKeys map[int32][]byte
+
+func (k staticVaultKeys) VaultKey(_ context.Context, _ string, vaultID int32) ([]byte, error) {
+	key, ok := k[vaultID]
+	if !ok {
+		return nil, errors.New("хранилище не найдено")
+	}
+	return key, nil
+}
+
+func TestEncryptedDataService_Vault(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeDataStore()
+	personalKey, vaultKey := testKey(t, "master"), testKey(t, "vault")
+	svc := NewEncryptedDataService(store, &entity.KeyHolder{Key: personalKey}, staticVaultKeys{5: vaultKey})
+
+	id, err := svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "secret", VaultId: 5})
+	require.NoError(t, err)
+
+	_, err = decryptField(personalKey, store.items[id].Info)
+	assert.ErrorIs(t, err, ErrDecrypt)
+	info, err := decryptField(vaultKey, store.items[id].Info)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", info)
+
+	got, err := svc.GetData(ctx, "token", id)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", got.Info)
+	assert.Equal(t, int32(5), got.VaultId)
+
+	_, err = svc.MoveData(ctx, "token", got, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), store.items[id].VaultId)
+	info, err = decryptField(personalKey, store.items[id].Info)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", info)
+
+	_, err = svc.AddData(ctx, "token", &datapb.DataItem{InfoType: "text", Info: "secret", VaultId: 6})
+	assert.Error(t, err)
+}
@@ -2,10 +2,15 @@ package service
 
 import (
 	"fmt"
+	"runtime"
 
+	"github.com/NikolosHGW/goph-keeper/api/auditpb"
 	"github.com/NikolosHGW/goph-keeper/api/authpb"
 	"github.com/NikolosHGW/goph-keeper/api/datapb"
 	"github.com/NikolosHGW/goph-keeper/api/registerpb"
+	"github.com/NikolosHGW/goph-keeper/api/sharepb"
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
 	"github.com/NikolosHGW/goph-keeper/pkg/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -16,16 +21,34 @@ type GRPCClient struct {
 	RegisterClient registerpb.RegisterClient
 	AuthClient     authpb.AuthClient
 	DataClient     datapb.DataServiceClient
+	VaultClient    vaultpb.VaultServiceClient
+	ShareClient    sharepb.ShareServiceClient
+	AuditClient    auditpb.AuditServiceClient
 }
 
-func NewGRPCClient(serverAddress string, logger logger.CustomLogger, rootCertPath string) (*GRPCClient, error) {
+// NewGRPCClient - создаёт соединение с сервером. Access-токен из tokenHolder
+// обновляется по refresh-токену прозрачно для вызывающего кода.
+func NewGRPCClient(
+	serverAddress string,
+	logger logger.CustomLogger,
+	rootCertPath string,
+	tokenHolder *entity.TokenHolder,
+) (*GRPCClient, error) {
 	creds, err := credentials.NewClientTLSFromFile(rootCertPath, "")
 	if err != nil {
 		logger.LogInfo("не удалось загрузить корневой CA сертификат", err)
 		return nil, fmt.Errorf("ошибка при загрузке CA сертификата: %w", err)
 	}
 
-	conn, err := grpc.NewClient(serverAddress, grpc.WithTransportCredentials(creds))
+	refresher := newTokenRefresher(tokenHolder)
+	conn, err := grpc.NewClient(
+		serverAddress,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(refresher.unary),
+		grpc.WithChainStreamInterceptor(refresher.stream),
+		// Сервер записывает user-agent в журнал аудита: по нему видно, с какого клиента был вызов.
+		grpc.WithUserAgent("gophkeeper-client/"+runtime.GOOS),
+	)
 	if err != nil {
 		logger.LogInfo("не удалось инициализировать клиент gRPC", err)
 
@@ -34,6 +57,7 @@ func NewGRPCClient(serverAddress string, logger logger.CustomLogger, rootCertPat
 
 	registerClient := registerpb.NewRegisterClient(conn)
 	authClient := authpb.NewAuthClient(conn)
+	refresher.client = authClient
 	dataClient := datapb.NewDataServiceClient(conn)
 
 	return &GRPCClient{
@@ -41,6 +65,9 @@ func NewGRPCClient(serverAddress string, logger logger.CustomLogger, rootCertPat
 		RegisterClient: registerClient,
 		AuthClient:     authClient,
 		DataClient:     dataClient,
+		VaultClient:    vaultpb.NewVaultServiceClient(conn),
+		ShareClient:    sharepb.NewShareServiceClient(conn),
+		AuditClient:    auditpb.NewAuditServiceClient(conn),
 	}, nil
 }
 
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+)
+
+// sessionKeyLabel - метка, которой ключ файла сессии отделяется от ключа
+// шифрования записей: утечка одного не раскрывает шифртексты другого.
+const sessionKeyLabel = "gophkeeper session"
+
+type sessionStore interface {
+	Load(profile string) (*entity.SessionFile, error)
+	Save(profile string, f *entity.SessionFile) error
+	Remove(profile string) error
+}
+
+type sessionService struct {
+	store         sessionStore
+	crypto        *cryptoService
+	now           func() time.Time
+	profile       string
+	serverAddress string
+}
+
+// NewSessionService - конструктор сервиса сохранённой сессии профиля profile
+// для сервера serverAddress.
+func NewSessionService(store sessionStore, profile, serverAddress string) *sessionService {
+	return &sessionService{
+		store:         store,
+		crypto:        NewCryptoService(),
+		now:           time.Now,
+		profile:       profile,
+		serverAddress: serverAddress,
+	}
+}
+
+// Saved - есть ли сохранённая сессия для текущего сервера.
+func (s *sessionService) Saved() (bool, error) {
+	f, err := s.store.Load(s.profile)
+	if err != nil {
+		return false, err
+	}
+
+	return f != nil && f.ServerAddress == s.serverAddress, nil
+}
+
+// Restore - расшифровывает сохранённую сессию ключом из мастер-пароля.
+// Возвращает ErrDecrypt при неверном мастер-пароле и entity.ErrSessionExpired,
+// если access-токен истёк, а обновить его нечем.
+func (s *sessionService) Restore(
+	masterPassword string,
+) (*entity.AuthTokens, *entity.KDFParams, []byte, error) {
+	f, err := s.store.Load(s.profile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if f == nil || f.ServerAddress != s.serverAddress {
+		return nil, nil, nil, fmt.Errorf("нет сохранённой сессии для %s", s.serverAddress)
+	}
+
+	kdf := f.KDF
+	key, err := s.crypto.DeriveKey(masterPassword, &kdf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	plain, err := decryptField(sessionKey(key), f.Sealed)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var saved entity.SavedSession
+	if err := json.Unmarshal([]byte(plain), &saved); err != nil {
+		return nil, nil, nil, fmt.Errorf("ошибка разбора сессии: %w", err)
+	}
+	// Адрес внутри шифртекста защищает от подмены открытого поля в файле.
+	if saved.ServerAddress != s.serverAddress {
+		return nil, nil, nil, ErrDecrypt
+	}
+	if saved.RefreshToken == "" && !saved.ExpiresAt.IsZero() && !saved.ExpiresAt.After(s.now()) {
+		return nil, nil, nil, entity.ErrSessionExpired
+	}
+
+	tokens := &entity.AuthTokens{
+		AccessToken:  saved.AccessToken,
+		RefreshToken: saved.RefreshToken,
+		ExpiresAt:    saved.ExpiresAt,
+	}
+
+	return tokens, &kdf, key, nil
+}
+
+// Save - шифрует текущие токены и сохраняет их. Без токена или ключа
+// сохранённая сессия удаляется: пользователь вышел или сессия истекла.
+func (s *sessionService) Save(tokens *entity.TokenHolder, keys *entity.KeyHolder) error {
+	if tokens.Token == "" || len(keys.Key) == 0 || keys.Params == nil {
+		return s.store.Remove(s.profile)
+	}
+
+	plain, err := json.Marshal(entity.SavedSession{
+		ServerAddress: s.serverAddress,
+		AccessToken:   tokens.Token,
+		RefreshToken:  tokens.RefreshToken,
+		ExpiresAt:     tokens.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации сессии: %w", err)
+	}
+
+	sealed, err := encryptField(sessionKey(keys.Key), string(plain))
+	if err != nil {
+		return err
+	}
+
+	return s.store.Save(s.profile, &entity.SessionFile{
+		ServerAddress: s.serverAddress,
+		Sealed:        sealed,
+		KDF:           *keys.Params,
+	})
+}
+
+func sessionKey(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(sessionKeyLabel))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memorySessionStore struct {
+	files map[string]*entity.SessionFile
+}
+
+func (s *memorySessionStore) Load(profile string) (*entity.SessionFile, error) {
+	return s.files[profile], nil
+}
+
+func (s *memorySessionStore) Save(profile string, f *entity.SessionFile) error {
+	s.files[profile] = f
+	return nil
+}
+
+func (s *memorySessionStore) Remove(profile string) error {
+	delete(s.files, profile)
+	return nil
+}
+
+func newSessionTestKeys(t *testing.T, masterPassword string) *entity.KeyHolder {
+	t.Helper()
+
+	params := &entity.KDFParams{Salt: []byte("0123456789abcdef"), Time: minKDFTime, Memory: minKDFMemory, Threads: 4}
+	return &entity.KeyHolder{Key: deriveKey(masterPassword, params), Params: params}
+}
+
+func TestSessionService_SaveRestore(t *testing.T) {
+	store := &memorySessionStore{files: map[string]*entity.SessionFile{}}
+	service := NewSessionService(store, "default", "localhost:8080")
+	keys := newSessionTestKeys(t, "master")
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, service.Save(
+		&entity.TokenHolder{Token: "access", RefreshToken: "refresh", ExpiresAt: expiresAt}, keys,
+	))
+	assert.NotContains(t, store.files["default"].Sealed, "access")
+
+	saved, err := service.Saved()
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	tokens, kdf, key, err := service.Restore("master")
+	require.NoError(t, err)
+	assert.Equal(t, &entity.AuthTokens{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: expiresAt}, tokens)
+	assert.Equal(t, keys.Params, kdf)
+	assert.Equal(t, keys.Key, key)
+
+	_, _, _, err = service.Restore("wrong")
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	other, err := NewSessionService(store, "default", "prod:443").Saved()
+	require.NoError(t, err)
+	assert.False(t, other)
+
+	require.NoError(t, service.Save(&entity.TokenHolder{}, keys))
+	assert.Empty(t, store.files)
+}
+
+func TestSessionService_RestoreExpired(t *testing.T) {
+	store := &memorySessionStore{files: map[string]*entity.SessionFile{}}
+	service := NewSessionService(store, "default", "localhost:8080")
+	keys := newSessionTestKeys(t, "master")
+	past := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, service.Save(&entity.TokenHolder{Token: "access", ExpiresAt: past}, keys))
+	_, _, _, err := service.Restore("master")
+	assert.ErrorIs(t, err, entity.ErrSessionExpired)
+
+	// С refresh-токеном истёкший access-токен обновит перехватчик.
+	require.NoError(t, service.Save(&entity.TokenHolder{Token: "access", RefreshToken: "refresh", ExpiresAt: past}, keys))
+	_, _, _, err = service.Restore("master")
+	assert.NoError(t, err)
+}
+
+func TestSessionService_RestoreRejectsSwappedServer(t *testing.T) {
+	store := &memorySessionStore{files: map[string]*entity.SessionFile{}}
+	keys := newSessionTestKeys(t, "master")
+	require.NoError(t, NewSessionService(store, "default", "evil:443").Save(&entity.TokenHolder{Token: "access"}, keys))
+	store.files["default"].ServerAddress = "localhost:8080"
+
+	_, _, _, err := NewSessionService(store, "default", "localhost:8080").Restore("master")
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
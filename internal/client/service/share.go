@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/api/sharepb"
+	"golang.org/x/crypto/chacha20poly1305"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// ErrInvalidShareToken - токен ссылки повреждён или обрезан.
+var ErrInvalidShareToken = errors.New("некорректный токен ссылки")
+
+// shareTokenSeparator - разделяет в токене идентификатор ссылки и ключ.
+const shareTokenSeparator = "."
+
+type shareService struct {
+	client sharepb.ShareServiceClient
+}
+
+// NewShareService - конструктор клиента одноразовых ссылок.
+//
+// Снимок записи шифруется случайным ключом, который на сервер не уходит:
+// он передаётся получателю в токене вместе с идентификатором ссылки.
+func NewShareService(grpcClient *GRPCClient) *shareService {
+	return &shareService{client: grpcClient.ShareClient}
+}
+
+// CreateShare - делится расшифрованной записью item и возвращает токен
+// для получателя и срок действия ссылки. ttl 0 и maxViews 0 - значения сервера
+// по умолчанию: сутки и один просмотр.
+func (s *shareService) CreateShare(
+	ctx context.Context, token string, item *datapb.DataItem, ttl time.Duration, maxViews int32,
+) (string, time.Time, error) {
+	snapshot, err := protojson.Marshal(&datapb.DataItem{
+		InfoType: item.InfoType,
+		Info:     item.Info,
+		Meta:     item.Meta,
+		Payload:  item.Payload,
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ошибка сериализации записи: %w", err)
+	}
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", time.Time{}, fmt.Errorf("ошибка генерации ключа ссылки: %w", err)
+	}
+	sealed, err := encryptField(key, string(snapshot))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	res, err := s.client.CreateShare(withToken(ctx, token), &sharepb.CreateShareRequest{
+		DataId:     item.Id,
+		Payload:    sealed,
+		TtlSeconds: int64(ttl / time.Second),
+		MaxViews:   maxViews,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	shareToken := res.ShareId + shareTokenSeparator + base64.RawURLEncoding.EncodeToString(key)
+	return shareToken, res.ExpiresAt.AsTime(), nil
+}
+
+// RedeemShare - открывает ссылку по токену без входа и возвращает запись
+// и число оставшихся просмотров.
+func (s *shareService) RedeemShare(ctx context.Context, shareToken string) (*datapb.DataItem, int32, error) {
+	id, encodedKey, ok := strings.Cut(strings.TrimSpace(shareToken), shareTokenSeparator)
+	if !ok || id == "" {
+		return nil, 0, ErrInvalidShareToken
+	}
+	key, err := base64.RawURLEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != chacha20poly1305.KeySize {
+		return nil, 0, ErrInvalidShareToken
+	}
+
+	res, err := s.client.RedeemShare(ctx, &sharepb.RedeemShareRequest{ShareId: id})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	snapshot, err := decryptField(key, res.Payload)
+	if err != nil {
+		return nil, 0, ErrInvalidShareToken
+	}
+	item := &datapb.DataItem{}
+	if err := protojson.Unmarshal([]byte(snapshot), item); err != nil {
+		return nil, 0, fmt.Errorf("ошибка чтения снимка: %w", err)
+	}
+
+	return item, res.ViewsLeft, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/api/sharepb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeShareServer - сервер ссылок в памяти с учётом просмотров.
+type fakeShareServer struct {
+	created *sharepb.CreateShareRequest
+	views   int32
+}
+
+func (f *fakeShareServer) CreateShare(
+	_ context.Context, in *sharepb.CreateShareRequest, _ ...grpc.CallOption,
+) (*sharepb.CreateShareResponse, error) {
+	f.created = in
+	f.views = in.MaxViews
+	return &sharepb.CreateShareResponse{ShareId: "abc", ExpiresAt: timestamppb.New(time.Unix(100, 0))}, nil
+}
+
+func (f *fakeShareServer) RedeemShare(
+	_ context.Context, in *sharepb.RedeemShareRequest, _ ...grpc.CallOption,
+) (*sharepb.RedeemShareResponse, error) {
+	if in.ShareId != "abc" || f.views == 0 {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	f.views--
+	return &sharepb.RedeemShareResponse{Payload: f.created.Payload, ViewsLeft: f.views}, nil
+}
+
+func TestShareService_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	server := &fakeShareServer{}
+	svc := NewShareService(&GRPCClient{ShareClient: server})
+
+	item := &datapb.DataItem{
+		Id:       5,
+		InfoType: "login_password",
+		Meta:     "почта",
+		Version:  3,
+		Payload: &datapb.DataItem_LoginPassword{
+			LoginPassword: &datapb.LoginPassword{Login: "user", Password: "secret"},
+		},
+	}
+	shareToken, expiresAt, err := svc.CreateShare(ctx, "token", item, time.Hour, 2)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(shareToken, "abc."))
+	assert.Equal(t, time.Unix(100, 0).UTC(), expiresAt)
+	assert.Equal(t, int64(3600), server.created.TtlSeconds)
+	assert.Equal(t, int32(5), server.created.DataId)
+	assert.NotContains(t, server.created.Payload, "secret")
+
+	got, viewsLeft, err := svc.RedeemShare(ctx, shareToken)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), viewsLeft)
+	assert.True(t, proto.Equal(&datapb.DataItem{
+		InfoType: "login_password",
+		Meta:     "почта",
+		Payload:  item.Payload,
+	}, got))
+
+	_, _, err = svc.RedeemShare(ctx, "abc.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+	assert.ErrorIs(t, err, ErrInvalidShareToken)
+
+	_, _, err = svc.RedeemShare(ctx, shareToken)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, _, err = svc.RedeemShare(ctx, "abc")
+	assert.ErrorIs(t, err, ErrInvalidShareToken)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/authpb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// refreshMargin - за сколько до истечения access-токен обновляется заранее.
+const refreshMargin = 30 * time.Second
+
+// tokenRefresher - клиентский перехватчик, который обновляет access-токен
+// по refresh-токену: заранее, если срок вот-вот истечёт, и повторно после
+// ответа Unauthenticated на обычный запрос.
+type tokenRefresher struct {
+	holder *entity.TokenHolder
+	client authpb.AuthClient
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+func newTokenRefresher(holder *entity.TokenHolder) *tokenRefresher {
+	return &tokenRefresher{holder: holder, now: time.Now}
+}
+
+func (r *tokenRefresher) unary(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	used, ok := r.authorization(ctx, method)
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	ctx, used, err := r.ensureFresh(ctx, used)
+	if err != nil {
+		return err
+	}
+
+	err = invoker(ctx, method, req, reply, cc, opts...)
+	if status.Code(err) != codes.Unauthenticated || r.holder.RefreshToken == "" {
+		return err
+	}
+
+	ctx, err = r.refresh(ctx, used)
+	if err != nil {
+		return err
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// stream - для потоков токен обновляется только заранее: повторить поток
+// с уже отправленными данными перехватчик не может.
+func (r *tokenRefresher) stream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	if used, ok := r.authorization(ctx, method); ok {
+		var err error
+		ctx, _, err = r.ensureFresh(ctx, used)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+// authorization - возвращает токен из метаданных запроса. Запросы без токена
+// (вход, регистрация) и само обновление токена не перехватываются.
+func (r *tokenRefresher) authorization(ctx context.Context, method string) (string, bool) {
+	if method == authpb.Auth_RefreshToken_FullMethodName {
+		return "", false
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok || len(md.Get("authorization")) == 0 {
+		return "", false
+	}
+
+	return strings.TrimPrefix(md.Get("authorization")[0], "Bearer "), true
+}
+
+func (r *tokenRefresher) ensureFresh(ctx context.Context, used string) (context.Context, string, error) {
+	expiresAt := r.holder.ExpiresAt
+	if r.holder.RefreshToken == "" || expiresAt.IsZero() || expiresAt.Sub(r.now()) > refreshMargin {
+		return ctx, used, nil
+	}
+
+	ctx, err := r.refresh(ctx, used)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return ctx, r.holder.Token, nil
+}
+
+// refresh - обменивает refresh-токен на новую пару и подставляет новый
+// access-токен в метаданные запроса. Если токен уже обновил другой запрос,
+// повторный обмен не выполняется.
+func (r *tokenRefresher) refresh(ctx context.Context, used string) (context.Context, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.holder.Token == used {
+		resp, err := r.client.RefreshToken(ctx, &authpb.RefreshTokenRequest{RefreshToken: r.holder.RefreshToken})
+		if err != nil {
+			if status.Code(err) == codes.Unauthenticated {
+				r.holder.Clear()
+				return nil, fmt.Errorf("сессия завершена, войдите заново: %w", err)
+			}
+			return nil, fmt.Errorf("ошибка обновления токена: %w", err)
+		}
+		r.holder.Set(&entity.AuthTokens{
+			AccessToken:  resp.BearerToken,
+			RefreshToken: resp.RefreshToken,
+			ExpiresAt:    timeOrZero(resp.AccessExpiresAt),
+		})
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set("authorization", r.holder.Token)
+
+	return metadata.NewOutgoingContext(ctx, md), nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/authpb"
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recordingInvoker - запоминает токены, с которыми выполнялись запросы,
+// и отвечает Unauthenticated на токены из rejected.
+type recordingInvoker struct {
+	rejected map[string]bool
+	tokens   []string
+}
+
+func (i *recordingInvoker) invoke(
+	ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption,
+) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	token := ""
+	if values := md.Get("authorization"); len(values) > 0 {
+		token = values[0]
+	}
+	i.tokens = append(i.tokens, token)
+	if i.rejected[token] {
+		return status.Error(codes.Unauthenticated, "недействительный токен доступа")
+	}
+	return nil
+}
+
+func TestTokenRefresher_Unary(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	refreshed := &authpb.RefreshTokenResponse{
+		BearerToken:     "access-2",
+		RefreshToken:    "refresh-2",
+		AccessExpiresAt: timestamppb.New(now.Add(15 * time.Minute)),
+	}
+
+	tests := []struct {
+		name           string
+		holder         *entity.TokenHolder
+		rejected       map[string]bool
+		setupMock      func(m *MockAuthClient)
+		expectedTokens []string
+		expectedCode   codes.Code
+		expectedHolder entity.TokenHolder
+	}{
+		{
+			name:           "токен действителен",
+			holder:         &entity.TokenHolder{Token: "access-1", RefreshToken: "refresh-1", ExpiresAt: now.Add(time.Hour)},
+			setupMock:      func(m *MockAuthClient) {},
+			expectedTokens: []string{"access-1"},
+			expectedHolder: entity.TokenHolder{Token: "access-1", RefreshToken: "refresh-1", ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:   "токен скоро истечёт",
+			holder: &entity.TokenHolder{Token: "access-1", RefreshToken: "refresh-1", ExpiresAt: now.Add(10 * time.Second)},
+			setupMock: func(m *MockAuthClient) {
+				m.On("RefreshToken", mock.Anything, &authpb.RefreshTokenRequest{RefreshToken: "refresh-1"}).
+					Return(refreshed, nil).Once()
+			},
+			expectedTokens: []string{"access-2"},
+			expectedHolder: entity.TokenHolder{
+				Token: "access-2", RefreshToken: "refresh-2", ExpiresAt: now.Add(15 * time.Minute).UTC(),
+			},
+		},
+		{
+			name:     "сервер отклонил токен",
+			holder:   &entity.TokenHolder{Token: "access-1", RefreshToken: "refresh-1", ExpiresAt: now.Add(time.Hour)},
+			rejected: map[string]bool{"access-1": true},
+			setupMock: func(m *MockAuthClient) {
+				m.On("RefreshToken", mock.Anything, &authpb.RefreshTokenRequest{RefreshToken: "refresh-1"}).
+					Return(refreshed, nil).Once()
+			},
+			expectedTokens: []string{"access-1", "access-2"},
+			expectedHolder: entity.TokenHolder{
+				Token: "access-2", RefreshToken: "refresh-2", ExpiresAt: now.Add(15 * time.Minute).UTC(),
+			},
+		},
+		{
+			name:     "сессия отозвана",
+			holder:   &entity.TokenHolder{Token: "access-1", RefreshToken: "refresh-1", ExpiresAt: now.Add(time.Hour)},
+			rejected: map[string]bool{"access-1": true},
+			setupMock: func(m *MockAuthClient) {
+				m.On("RefreshToken", mock.Anything, &authpb.RefreshTokenRequest{RefreshToken: "refresh-1"}).
+					Return(nil, status.Error(codes.Unauthenticated, "недействительный refresh-токен")).Once()
+			},
+			expectedTokens: []string{"access-1"},
+			expectedCode:   codes.Unauthenticated,
+			expectedHolder: entity.TokenHolder{},
+		},
+		{
+			name:           "без refresh-токена ошибка возвращается как есть",
+			holder:         &entity.TokenHolder{Token: "access-1"},
+			rejected:       map[string]bool{"access-1": true},
+			setupMock:      func(m *MockAuthClient) {},
+			expectedTokens: []string{"access-1"},
+			expectedCode:   codes.Unauthenticated,
+			expectedHolder: entity.TokenHolder{Token: "access-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authClient := new(MockAuthClient)
+			tt.setupMock(authClient)
+
+			refresher := newTokenRefresher(tt.holder)
+			refresher.client = authClient
+			refresher.now = func() time.Time { return now }
+
+			invoker := &recordingInvoker{rejected: tt.rejected}
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", tt.holder.Token)
+
+			err := refresher.unary(ctx, datapb.DataService_GetData_FullMethodName, nil, nil, nil, invoker.invoke)
+
+			if tt.expectedCode == codes.OK {
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+			}
+			assert.Equal(t, tt.expectedTokens, invoker.tokens)
+			assert.Equal(t, tt.expectedHolder, *tt.holder)
+			authClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenRefresher_SkipsUnauthenticatedCalls(t *testing.T) {
+	holder := &entity.TokenHolder{RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(-time.Minute)}
+	refresher := newTokenRefresher(holder)
+	refresher.client = new(MockAuthClient)
+
+	invoker := &recordingInvoker{}
+	err := refresher.unary(context.Background(), authpb.Auth_LoginUser_FullMethodName, nil, nil, nil, invoker.invoke)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, invoker.tokens)
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const x25519KeySize = 32
+
+type vaultService struct {
+	client    vaultpb.VaultServiceClient
+	keyHolder *entity.KeyHolder
+}
+
+// NewVaultService - конструктор клиента общих хранилищ.
+//
+// Записи хранилища шифруются его собственным случайным ключом. Каждый
+// участник получает копию этого ключа, запечатанную его открытым ключом X25519
+// (nacl/box SealAnonymous), а закрытый ключ участника хранится на сервере
+// зашифрованным ключом из мастер-пароля. Так сервер не видит ни ключей,
+// ни содержимого записей.
+func NewVaultService(grpcClient *GRPCClient, keyHolder *entity.KeyHolder) *vaultService {
+	return &vaultService{client: grpcClient.VaultClient, keyHolder: keyHolder}
+}
+
+// CreateVault - создаёт хранилище со свежим ключом и возвращает его id.
+func (s *vaultService) CreateVault(ctx context.Context, token, name string) (int32, error) {
+	publicKey, _, err := s.keyPair(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	vaultKey := make([]byte, chacha20poly1305.KeySize)
+	if _, err := rand.Read(vaultKey); err != nil {
+		return 0, fmt.Errorf("ошибка генерации ключа хранилища: %w", err)
+	}
+	wrapped, err := wrapVaultKey(vaultKey, publicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	req := &vaultpb.CreateVaultRequest{Name: name, WrappedKey: wrapped}
+	res, err := s.client.CreateVault(withToken(ctx, token), req)
+	if err != nil {
+		return 0, err
+	}
+	s.rememberVaultKey(res.Id, vaultKey)
+
+	return res.Id, nil
+}
+
+// ListVaults - возвращает хранилища пользователя и запоминает их ключи.
+// Заодно публикует ключевую пару пользователя, если её ещё нет: без открытого
+// ключа пригласить пользователя в хранилище нельзя.
+func (s *vaultService) ListVaults(ctx context.Context, token string) ([]*vaultpb.Vault, error) {
+	publicKey, privateKey, err := s.keyPair(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.client.ListVaults(withToken(ctx, token), &vaultpb.ListVaultsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, vault := range res.Vaults {
+		vaultKey, err := unwrapVaultKey(vault.WrappedKey, publicKey, privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("ключ хранилища %d: %w", vault.Id, err)
+		}
+		s.rememberVaultKey(vault.Id, vaultKey)
+	}
+
+	return res.Vaults, nil
+}
+
+// VaultKey - возвращает ключ хранилища, при необходимости запрашивая его у сервера.
+func (s *vaultService) VaultKey(ctx context.Context, token string, vaultID int32) ([]byte, error) {
+	if key, ok := s.keyHolder.VaultKeys[vaultID]; ok {
+		return key, nil
+	}
+
+	if _, err := s.ListVaults(ctx, token); err != nil {
+		return nil, err
+	}
+	key, ok := s.keyHolder.VaultKeys[vaultID]
+	if !ok {
+		return nil, fmt.Errorf("хранилище %d не найдено", vaultID)
+	}
+
+	return key, nil
+}
+
+// AddMember - приглашает пользователя login: ключ хранилища запечатывается
+// его открытым ключом. Открытый ключ приходит с сервера, поэтому клиент
+// доверяет серверу в том, что ключ действительно принадлежит login.
+func (s *vaultService) AddMember(ctx context.Context, token string, vaultID int32, login, role string) error {
+	vaultKey, err := s.VaultKey(ctx, token, vaultID)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.client.GetPublicKey(withToken(ctx, token), &vaultpb.GetPublicKeyRequest{Login: login})
+	if err != nil {
+		return err
+	}
+	publicKey, err := decodeX25519(res.PublicKey)
+	if err != nil {
+		return fmt.Errorf("открытый ключ %s: %w", login, err)
+	}
+
+	wrapped, err := wrapVaultKey(vaultKey, publicKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.AddMember(withToken(ctx, token), &vaultpb.AddMemberRequest{
+		VaultId:    vaultID,
+		Login:      login,
+		Role:       role,
+		WrappedKey: wrapped,
+	})
+	return err
+}
+
+// RemoveMember - исключает участника или, если login - сам пользователь, выходит из хранилища.
+func (s *vaultService) RemoveMember(ctx context.Context, token string, vaultID int32, login string) error {
+	req := &vaultpb.RemoveMemberRequest{VaultId: vaultID, Login: login}
+	_, err := s.client.RemoveMember(withToken(ctx, token), req)
+	if err != nil {
+		return err
+	}
+
+	delete(s.keyHolder.VaultKeys, vaultID)
+	return nil
+}
+
+// ListMembers - возвращает участников хранилища.
+func (s *vaultService) ListMembers(ctx context.Context, token string, vaultID int32) ([]*vaultpb.Member, error) {
+	res, err := s.client.ListMembers(withToken(ctx, token), &vaultpb.ListMembersRequest{VaultId: vaultID})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Members, nil
+}
+
+// keyPair - возвращает ключевую пару пользователя. При первом обращении
+// пара создаётся и сохраняется на сервере.
+func (s *vaultService) keyPair(
+	ctx context.Context, token string,
+) (*[x25519KeySize]byte, *[x25519KeySize]byte, error) {
+	if len(s.keyHolder.Key) == 0 {
+		return nil, nil, fmt.Errorf("ключ шифрования не задан, выполните вход")
+	}
+	if len(s.keyHolder.PrivateKey) == x25519KeySize {
+		return derivePublicKey(s.keyHolder.PrivateKey)
+	}
+
+	res, err := s.client.GetKeyPair(withToken(ctx, token), &vaultpb.GetKeyPairRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.GetKeyPair().GetPublicKey() == "" {
+		return s.createKeyPair(ctx, token)
+	}
+
+	return s.openKeyPair(res.KeyPair)
+}
+
+func (s *vaultService) createKeyPair(
+	ctx context.Context, token string,
+) (*[x25519KeySize]byte, *[x25519KeySize]byte, error) {
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка генерации ключевой пары: %w", err)
+	}
+
+	sealed, err := encryptField(s.keyHolder.Key, base64.StdEncoding.EncodeToString(privateKey[:]))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pair := &vaultpb.KeyPair{PublicKey: base64.StdEncoding.EncodeToString(publicKey[:]), SealedPrivateKey: sealed}
+	_, err = s.client.SetKeyPair(withToken(ctx, token), &vaultpb.SetKeyPairRequest{KeyPair: pair})
+	if status.Code(err) == codes.AlreadyExists {
+		// Пару успел создать другой клиент этого пользователя - берём её.
+		return s.keyPair(ctx, token)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	s.keyHolder.PrivateKey = privateKey[:]
+
+	return publicKey, privateKey, nil
+}
+
+func (s *vaultService) openKeyPair(pair *vaultpb.KeyPair) (*[x25519KeySize]byte, *[x25519KeySize]byte, error) {
+	encoded, err := decryptField(s.keyHolder.Key, pair.SealedPrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != x25519KeySize {
+		return nil, nil, ErrDecrypt
+	}
+
+	publicKey, privateKey, err := derivePublicKey(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Открытый ключ на сервере должен соответствовать закрытому, иначе
+	// приглашающие запечатали бы ключи хранилищ чужим ключом.
+	if base64.StdEncoding.EncodeToString(publicKey[:]) != pair.PublicKey {
+		return nil, nil, fmt.Errorf("открытый ключ на сервере не соответствует закрытому")
+	}
+	s.keyHolder.PrivateKey = raw
+
+	return publicKey, privateKey, nil
+}
+
+func derivePublicKey(raw []byte) (*[x25519KeySize]byte, *[x25519KeySize]byte, error) {
+	public, err := curve25519.X25519(raw, curve25519.Basepoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("некорректный закрытый ключ: %w", err)
+	}
+
+	publicKey, privateKey := new([x25519KeySize]byte), new([x25519KeySize]byte)
+	copy(publicKey[:], public)
+	copy(privateKey[:], raw)
+
+	return publicKey, privateKey, nil
+}
+
+func (s *vaultService) rememberVaultKey(vaultID int32, key []byte) {
+	if s.keyHolder.VaultKeys == nil {
+		s.keyHolder.VaultKeys = make(map[int32][]byte)
+	}
+	s.keyHolder.VaultKeys[vaultID] = key
+}
+
+func wrapVaultKey(vaultKey []byte, publicKey *[x25519KeySize]byte) (string, error) {
+	sealed, err := box.SealAnonymous(nil, vaultKey, publicKey, rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("ошибка шифрования ключа хранилища: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func unwrapVaultKey(wrapped string, publicKey, privateKey *[x25519KeySize]byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	key, ok := box.OpenAnonymous(nil, sealed, publicKey, privateKey)
+	if !ok || len(key) != chacha20poly1305.KeySize {
+		return nil, ErrDecrypt
+	}
+
+	return key, nil
+}
+
+func decodeX25519(encoded string) (*[x25519KeySize]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != x25519KeySize {
+		return nil, fmt.Errorf("некорректный ключ X25519")
+	}
+
+	key := new([x25519KeySize]byte)
+	copy(key[:], raw)
+
+	return key, nil
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", token)
+}
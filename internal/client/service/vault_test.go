@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/client/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeVaultServer - сервер хранилищ в памяти. Токен запроса - логин пользователя.
+type fakeVaultServer struct {
+	vaultpb.VaultServiceClient
+	pairs   map[string]*vaultpb.KeyPair
+	members map[int32]map[string]*vaultpb.Vault
+	nextID  int32
+}
+
+func newFakeVaultServer() *fakeVaultServer {
+	return &fakeVaultServer{
+		pairs:   make(map[string]*vaultpb.KeyPair),
+		members: make(map[int32]map[string]*vaultpb.Vault),
+	}
+}
+
+func callerLogin(ctx context.Context) string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return md.Get("authorization")[0]
+}
+
+func (f *fakeVaultServer) SetKeyPair(
+	ctx context.Context, in *vaultpb.SetKeyPairRequest, _ ...grpc.CallOption,
+) (*vaultpb.SetKeyPairResponse, error) {
+	if _, ok := f.pairs[callerLogin(ctx)]; ok {
+		return nil, status.Error(codes.AlreadyExists, "exists")
+	}
+	f.pairs[callerLogin(ctx)] = in.KeyPair
+	return &vaultpb.SetKeyPairResponse{}, nil
+}
+
+func (f *fakeVaultServer) GetKeyPair(
+	ctx context.Context, _ *vaultpb.GetKeyPairRequest, _ ...grpc.CallOption,
+) (*vaultpb.GetKeyPairResponse, error) {
+	pair, ok := f.pairs[callerLogin(ctx)]
+	if !ok {
+		pair = &vaultpb.KeyPair{}
+	}
+	return &vaultpb.GetKeyPairResponse{KeyPair: pair}, nil
+}
+
+func (f *fakeVaultServer) GetPublicKey(
+	_ context.Context, in *vaultpb.GetPublicKeyRequest, _ ...grpc.CallOption,
+) (*vaultpb.GetPublicKeyResponse, error) {
+	pair, ok := f.pairs[in.Login]
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "no key")
+	}
+	return &vaultpb.GetPublicKeyResponse{PublicKey: pair.PublicKey}, nil
+}
+
+func (f *fakeVaultServer) CreateVault(
+	ctx context.Context, in *vaultpb.CreateVaultRequest, _ ...grpc.CallOption,
+) (*vaultpb.CreateVaultResponse, error) {
+	f.nextID++
+	f.members[f.nextID] = map[string]*vaultpb.Vault{
+		callerLogin(ctx): {Id: f.nextID, Name: in.Name, Role: "owner", WrappedKey: in.WrappedKey},
+	}
+	return &vaultpb.CreateVaultResponse{Id: f.nextID}, nil
+}
+
+func (f *fakeVaultServer) ListVaults(
+	ctx context.Context, _ *vaultpb.ListVaultsRequest, _ ...grpc.CallOption,
+) (*vaultpb.ListVaultsResponse, error) {
+	res := &vaultpb.ListVaultsResponse{}
+	for id := int32(1); id <= f.nextID; id++ {
+		if vault, ok := f.members[id][callerLogin(ctx)]; ok {
+			res.Vaults = append(res.Vaults, vault)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeVaultServer) AddMember(
+	_ context.Context, in *vaultpb.AddMemberRequest, _ ...grpc.CallOption,
+) (*vaultpb.AddMemberResponse, error) {
+	f.members[in.VaultId][in.Login] = &vaultpb.Vault{Id: in.VaultId, Role: in.Role, WrappedKey: in.WrappedKey}
+	return &vaultpb.AddMemberResponse{}, nil
+}
+
+func (f *fakeVaultServer) RemoveMember(
+	_ context.Context, in *vaultpb.RemoveMemberRequest, _ ...grpc.CallOption,
+) (*vaultpb.RemoveMemberResponse, error) {
+	delete(f.members[in.VaultId], in.Login)
+	return &vaultpb.RemoveMemberResponse{}, nil
+}
+
+func newTestVaultService(server *fakeVaultServer, masterPassword string) *vaultService {
+	keyHolder := &entity.KeyHolder{}
+	keyHolder.Set(testKeyBytes(masterPassword), nil)
+	return NewVaultService(&GRPCClient{VaultClient: server}, keyHolder)
+}
+
+func testKeyBytes(masterPassword string) []byte {
+	key := make([]byte, 32)
+	copy(key, masterPassword)
+	return key
+}
+
+func TestVaultService_ShareKey(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeVaultServer()
+	alice := newTestVaultService(server, "alice master")
+	bob := newTestVaultService(server, "bob master")
+
+	vaultID, err := alice.CreateVault(ctx, "alice", "семья")
+	require.NoError(t, err)
+	aliceKey, err := alice.VaultKey(ctx, "alice", vaultID)
+	require.NoError(t, err)
+
+	// Пока у Боба нет ключевой пары, пригласить его нельзя.
+	err = alice.AddMember(ctx, "alice", vaultID, "bob", "read")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = bob.ListVaults(ctx, "bob")
+	require.NoError(t, err)
+	require.NoError(t, alice.AddMember(ctx, "alice", vaultID, "bob", "read"))
+
+	bobKey, err := bob.VaultKey(ctx, "bob", vaultID)
+	require.NoError(t, err)
+	assert.Equal(t, aliceKey, bobKey)
+
+	// Новый клиент Алисы расшифровывает свой закрытый ключ с сервера.
+	aliceAgain := newTestVaultService(server, "alice master")
+	key, err := aliceAgain.VaultKey(ctx, "alice", vaultID)
+	require.NoError(t, err)
+	assert.Equal(t, aliceKey, key)
+
+	wrongPassword := newTestVaultService(server, "wrong")
+	_, err = wrongPassword.VaultKey(ctx, "alice", vaultID)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	require.NoError(t, bob.RemoveMember(ctx, "bob", vaultID, "bob"))
+	assert.Empty(t, bob.keyHolder.VaultKeys)
+	_, err = bob.VaultKey(ctx, "bob", vaultID)
+	assert.Error(t, err)
+}
+
+func TestVaultService_TamperedPublicKey(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeVaultServer()
+	alice := newTestVaultService(server, "alice master")
+
+	_, err := alice.CreateVault(ctx, "alice", "личное")
+	require.NoError(t, err)
+
+	other, _, err := newTestVaultService(newFakeVaultServer(), "x").keyPair(ctx, "mallory")
+	require.NoError(t, err)
+	server.pairs["alice"].PublicKey = base64.StdEncoding.EncodeToString(other[:])
+
+	_, err = newTestVaultService(server, "alice master").ListVaults(ctx, "alice")
+	assert.ErrorContains(t, err, "не соответствует")
+}
@@ -0,0 +1,94 @@
+// Package label - теги и папки записей: приведение к единому виду
+// и проверка. Используется и сервером, и клиентом, чтобы "work/ db"
+// и "/work/db/" считались одной папкой.
+package label
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTags - сколько тегов может быть у записи.
+	MaxTags = 32
+	// MaxNameLength - длина тега или имени одной папки в символах.
+	MaxNameLength = 64
+	// MaxFolderDepth - вложенность папок.
+	MaxFolderDepth = 16
+	// Separator - разделитель папок в пути.
+	Separator = "/"
+	// Root - фильтр списка по записям вне папок.
+	Root = Separator
+)
+
+// ErrInvalid - некорректный тег или путь папки.
+var ErrInvalid = errors.New("некорректный тег или папка")
+
+// Tags - теги без пробелов по краям, без пустых и повторов, по алфавиту.
+func Tags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if err := checkName(tag); err != nil {
+			return nil, fmt.Errorf("тег %q: %w", tag, err)
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > MaxTags {
+		return nil, fmt.Errorf("%w: больше %d тегов", ErrInvalid, MaxTags)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// Folder - путь папки без пустых частей и пробелов по краям:
+// " /work// db/" становится "work/db". Пустой путь - запись вне папок.
+func Folder(path string) (string, error) {
+	parts := make([]string, 0, strings.Count(path, Separator)+1)
+	for _, part := range strings.Split(path, Separator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if err := checkName(part); err != nil {
+			return "", fmt.Errorf("папка %q: %w", part, err)
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) > MaxFolderDepth {
+		return "", fmt.Errorf("%w: вложенность папок больше %d", ErrInvalid, MaxFolderDepth)
+	}
+
+	return strings.Join(parts, Separator), nil
+}
+
+// Parent - папка, в которой лежит folder; для папки верхнего уровня - пусто.
+func Parent(folder string) string {
+	i := strings.LastIndex(folder, Separator)
+	if i < 0 {
+		return ""
+	}
+	return folder[:i]
+}
+
+func checkName(name string) error {
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return fmt.Errorf("%w: длиннее %d символов", ErrInvalid, MaxNameLength)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: управляющие символы недопустимы", ErrInvalid)
+		}
+	}
+	return nil
+}
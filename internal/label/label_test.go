@@ -0,0 +1,80 @@
+package label
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "пусто", tags: nil, expected: []string{}},
+		{name: "повторы и пробелы", tags: []string{" work", "db", "work ", ""}, expected: []string{"db", "work"}},
+		{name: "слишком длинный", tags: []string{strings.Repeat("я", MaxNameLength+1)}, wantErr: true},
+		{name: "управляющий символ", tags: []string{"a\tb"}, wantErr: true},
+		{
+			name:     "повторы не считаются в лимите",
+			tags:     strings.Split(strings.Repeat("a,", MaxTags)+"b,c", ","),
+			expected: []string{"a", "b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := Tags(tt.tags)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tags)
+		})
+	}
+
+	many := make([]string, MaxTags+1)
+	for i := range many {
+		many[i] = strings.Repeat("x", i+1)
+	}
+	_, err := Tags(many)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestFolder(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		wantErr  bool
+	}{
+		{path: "", expected: ""},
+		{path: "/", expected: ""},
+		{path: " /work// db/", expected: "work/db"},
+		{path: "личное", expected: "личное"},
+		{path: strings.Repeat("a/", MaxFolderDepth+1), wantErr: true},
+		{path: "work/\x00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			folder, err := Folder(tt.path)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, folder)
+		})
+	}
+}
+
+func TestParent(t *testing.T) {
+	assert.Equal(t, "", Parent("work"))
+	assert.Equal(t, "work", Parent("work/db"))
+	assert.Equal(t, "work/db", Parent("work/db/prod"))
+}
@@ -0,0 +1,156 @@
+// Package payload описывает типизированное содержимое записей: проверку полей
+// и упаковку в строку info. Используется и сервером, и клиентом: при сквозном
+// шифровании сервер видит только шифротекст, поэтому клиент проверяет данные
+// до шифрования.
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Типы информации, допустимые в user_data.info_type.
+const (
+	TypeLoginPassword = "login_password"
+	TypeText          = "text"
+	TypeBinary        = "binary"
+	TypeBankCard      = "bank_card"
+)
+
+// ErrInvalid - данные записи не прошли проверку.
+var ErrInvalid = errors.New("некорректные данные")
+
+var (
+	expiryPattern = regexp.MustCompile(`^(0[1-9]|1[0-2])/\d{2}$`)
+	cvvPattern    = regexp.MustCompile(`^\d{3,4}$`)
+)
+
+// IsKnownType - является ли строка допустимым типом информации.
+func IsKnownType(infoType string) bool {
+	switch infoType {
+	case TypeLoginPassword, TypeText, TypeBinary, TypeBankCard:
+		return true
+	}
+	return false
+}
+
+// Validate - проверяет тип записи и, если payload заполнен, его поля.
+// Запись без payload считается непрозрачной: её info проверить нельзя.
+func Validate(item *datapb.DataItem) error {
+	if !IsKnownType(item.InfoType) {
+		return fmt.Errorf("%w: неизвестный тип информации %q", ErrInvalid, item.InfoType)
+	}
+
+	switch p := item.Payload.(type) {
+	case nil:
+		return nil
+	case *datapb.DataItem_LoginPassword:
+		return validateLoginPassword(item.InfoType, p.LoginPassword)
+	case *datapb.DataItem_Text:
+		return checkType(item.InfoType, TypeText)
+	case *datapb.DataItem_Binary:
+		return validateBinary(item.InfoType, p.Binary)
+	case *datapb.DataItem_BankCard:
+		return validateBankCard(item.InfoType, p.BankCard)
+	default:
+		return fmt.Errorf("%w: неизвестный payload", ErrInvalid)
+	}
+}
+
+func checkType(infoType, expected string) error {
+	if infoType != expected {
+		return fmt.Errorf("%w: тип %q не совпадает с содержимым %q", ErrInvalid, infoType, expected)
+	}
+	return nil
+}
+
+func validateLoginPassword(infoType string, lp *datapb.LoginPassword) error {
+	if err := checkType(infoType, TypeLoginPassword); err != nil {
+		return err
+	}
+	if strings.TrimSpace(lp.GetLogin()) == "" {
+		return fmt.Errorf("%w: логин не может быть пустым", ErrInvalid)
+	}
+	return nil
+}
+
+func validateBinary(infoType string, b *datapb.Binary) error {
+	if err := checkType(infoType, TypeBinary); err != nil {
+		return err
+	}
+	if strings.TrimSpace(b.GetFilename()) == "" {
+		return fmt.Errorf("%w: имя файла не может быть пустым", ErrInvalid)
+	}
+	return nil
+}
+
+func validateBankCard(infoType string, card *datapb.BankCard) error {
+	if err := checkType(infoType, TypeBankCard); err != nil {
+		return err
+	}
+	if !Luhn(card.GetNumber()) {
+		return fmt.Errorf("%w: номер карты не проходит проверку Луна", ErrInvalid)
+	}
+	if !expiryPattern.MatchString(card.GetExpiry()) {
+		return fmt.Errorf("%w: срок действия должен быть в формате MM/YY", ErrInvalid)
+	}
+	if card.GetCvv() != "" && !cvvPattern.MatchString(card.GetCvv()) {
+		return fmt.Errorf("%w: CVV должен состоять из 3-4 цифр", ErrInvalid)
+	}
+	return nil
+}
+
+// Luhn - проверяет номер карты по алгоритму Луна. Пробелы и дефисы игнорируются.
+func Luhn(number string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(number)
+	if len(digits) < 12 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// Encode - упаковывает payload записи в строку для поля info.
+func Encode(item *datapb.DataItem) (string, error) {
+	encoded, err := protojson.Marshal(&datapb.DataItem{Payload: item.Payload})
+	if err != nil {
+		return "", fmt.Errorf("ошибка упаковки payload: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// Decode - восстанавливает payload из строки info. Если info не является
+// упакованным payload (например, запись создана до появления типов), запись
+// не меняется и возвращается false.
+func Decode(info string, item *datapb.DataItem) bool {
+	var decoded datapb.DataItem
+	if err := protojson.Unmarshal([]byte(info), &decoded); err != nil || decoded.Payload == nil {
+		return false
+	}
+
+	item.Payload = decoded.Payload
+	item.Info = ""
+	return true
+}
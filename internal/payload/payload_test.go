@@ -0,0 +1,140 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLuhn(t *testing.T) {
+	tests := []struct {
+		number   string
+		expected bool
+	}{
+		{"4111111111111111", true},
+		{"4111 1111 1111 1111", true},
+		{"5555-5555-5555-4444", true},
+		{"4111111111111112", false},
+		{"41111111111a1111", false},
+		{"4111", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Luhn(tt.number))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	validCard := &datapb.BankCard{Number: "4111111111111111", Holder: "IVAN IVANOV", Expiry: "12/29", Cvv: "123"}
+
+	tests := []struct {
+		name    string
+		item    *datapb.DataItem
+		wantErr bool
+	}{
+		{
+			name: "непрозрачные данные без payload",
+			item: &datapb.DataItem{InfoType: TypeText, Info: "шифротекст"},
+		},
+		{
+			name:    "неизвестный тип",
+			item:    &datapb.DataItem{InfoType: "unknown"},
+			wantErr: true,
+		},
+		{
+			name: "логин и пароль",
+			item: &datapb.DataItem{
+				InfoType: TypeLoginPassword,
+				Payload:  &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{Login: "user"}},
+			},
+		},
+		{
+			name: "пустой логин",
+			item: &datapb.DataItem{
+				InfoType: TypeLoginPassword,
+				Payload:  &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{Login: " ", Password: "p"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "тип не совпадает с payload",
+			item: &datapb.DataItem{
+				InfoType: TypeBankCard,
+				Payload:  &datapb.DataItem_Text{Text: &datapb.Text{Content: "текст"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "файл без имени",
+			item: &datapb.DataItem{
+				InfoType: TypeBinary,
+				Payload:  &datapb.DataItem_Binary{Binary: &datapb.Binary{Bytes: []byte{1}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "корректная карта",
+			item:    &datapb.DataItem{InfoType: TypeBankCard, Payload: &datapb.DataItem_BankCard{BankCard: validCard}},
+			wantErr: false,
+		},
+		{
+			name: "номер карты не проходит Луна",
+			item: &datapb.DataItem{InfoType: TypeBankCard, Payload: &datapb.DataItem_BankCard{
+				BankCard: &datapb.BankCard{Number: "4111111111111112", Expiry: "12/29"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "некорректный срок действия",
+			item: &datapb.DataItem{InfoType: TypeBankCard, Payload: &datapb.DataItem_BankCard{
+				BankCard: &datapb.BankCard{Number: "4111111111111111", Expiry: "13/29"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "некорректный CVV",
+			item: &datapb.DataItem{InfoType: TypeBankCard, Payload: &datapb.DataItem_BankCard{
+				BankCard: &datapb.BankCard{Number: "4111111111111111", Expiry: "01/30", Cvv: "12"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.item)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalid)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	item := &datapb.DataItem{
+		InfoType: TypeBinary,
+		Payload: &datapb.DataItem_Binary{
+			Binary: &datapb.Binary{Filename: "a.png", Mime: "image/png", Bytes: []byte{0, 1, 2}},
+		},
+	}
+
+	encoded, err := Encode(item)
+	assert.NoError(t, err)
+
+	decoded := &datapb.DataItem{InfoType: TypeBinary, Info: encoded}
+	assert.True(t, Decode(encoded, decoded))
+	assert.Empty(t, decoded.Info)
+	assert.True(t, proto.Equal(item, decoded))
+
+	legacy := &datapb.DataItem{InfoType: TypeText, Info: "старый текст"}
+	assert.False(t, Decode(legacy.Info, legacy))
+	assert.Equal(t, "старый текст", legacy.Info)
+	assert.Nil(t, legacy.Payload)
+}
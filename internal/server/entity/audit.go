@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// AuditEvent - запись журнала аудита об одном вызове сервера. UserID 0 -
+// вызывающий неизвестен, DataID 0 - вызов не касается конкретной записи.
+// Hash = sha256(PrevHash || содержимое события) связывает события в цепочку.
+type AuditEvent struct {
+	CreatedAt time.Time `db:"created_at"`
+	Login     string    `db:"login"`
+	Action    string    `db:"action"`
+	ClientIP  string    `db:"client_ip"`
+	UserAgent string    `db:"user_agent"`
+	Outcome   string    `db:"outcome"`
+	PrevHash  []byte    `db:"prev_hash"`
+	Hash      []byte    `db:"hash"`
+	ID        int64     `db:"id"`
+	UserID    int       `db:"user_id"`
+	DataID    int       `db:"data_id"`
+}
+
+// AuditCheckpoint - подписанная отметка о том, что событие EventID
+// имело хеш Hash. Хранится вне базы и позволяет обнаружить подмену
+// цепочки целиком или удаление её хвоста.
+type AuditCheckpoint struct {
+	CreatedAt time.Time `json:"created_at"`
+	Hash      []byte    `json:"hash"`
+	Signature []byte    `json:"signature"`
+	EventID   int64     `json:"event_id"`
+}
+
+// AuditBreak - первое нарушение цепочки журнала аудита.
+type AuditBreak struct {
+	Reason  string
+	EventID int64
+}
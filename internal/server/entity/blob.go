@@ -0,0 +1,10 @@
+package entity
+
+// BinaryBlob - содержимое двоичной записи, хранящееся вне строки user_data.
+// Key - имя объекта в хранилище, SHA256 - hex-хеш байт, полученных от клиента.
+type BinaryBlob struct {
+	Key    string
+	SHA256 string
+	DataID int
+	Size   int64
+}
@@ -4,5 +4,6 @@ import "github.com/golang-jwt/jwt/v4"
 
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID int
+	UserID    int
+	SessionID int
 }
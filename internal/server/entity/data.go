@@ -3,10 +3,77 @@ package entity
 import "time"
 
 type UserData struct {
-	ID       int
-	UserID   int
+	Created   time.Time
+	UpdatedAt time.Time
+	// DeletedAt - когда запись попала в корзину; заполняется только в ListTrash.
+	DeletedAt time.Time
+	InfoType  string
+	Info      string
+	Meta      string
+	// Folder - путь папки через "/", пусто - вне папок.
+	Folder string
+	Tags   []string
+	// SearchTokens - токены слепого индекса; при чтении записи не заполняются.
+	SearchTokens []string
+	ID           int
+	UserID       int
+	VaultID      int
+	Revision     int64
+	Version      int64
+	Favorite     bool
+}
+
+// DataVersion - прежнее содержимое записи из истории изменений.
+// SavedAt - когда эта версия была записана.
+type DataVersion struct {
+	SavedAt  time.Time
 	InfoType string
 	Info     string
 	Meta     string
-	Created  time.Time
+	// ID - строка истории, нужен только для ротации ключей.
+	ID      int
+	DataID  int
+	Version int64
+}
+
+// Tombstone - след удалённой записи, по которому клиенты узнают об удалении.
+type Tombstone struct {
+	DeletedAt time.Time
+	DataID    int
+	Revision  int64
+}
+
+// DataChanges - изменения записей пользователя после некоторой ревизии.
+// Revision - ревизия, с которой нужно запрашивать следующую порцию.
+type DataChanges struct {
+	Upserts   []*UserData
+	Deletions []*Tombstone
+	Revision  int64
+	HasMore   bool
+}
+
+// DataFilter - параметры выборки списка данных пользователя.
+type DataFilter struct {
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	InfoType    string
+	Cursor      string
+	Tag         string
+	// Folder - записи прямо в этой папке; RootFolder - записи вне папок.
+	Folder string
+	// SearchTokens - записи, у которых есть все эти токены слепого индекса.
+	SearchTokens  []string
+	Limit         int
+	VaultID       int
+	Descending    bool
+	FavoritesOnly bool
+}
+
+// RootFolder - значение DataFilter.Folder для записей вне папок.
+const RootFolder = "/"
+
+// DataCursor - позиция последней выданной записи для курсорной пагинации.
+type DataCursor struct {
+	Created time.Time
+	ID      int
 }
@@ -0,0 +1,9 @@
+package entity
+
+// EncryptionKey - ключ шифрования данных на сервере вместе с его идентификатором.
+// Идентификатор записывается перед шифротекстом, чтобы при расшифровке
+// выбрать нужный ключ из связки.
+type EncryptionKey struct {
+	ID  string `json:"id"`
+	Key []byte `json:"key"`
+}
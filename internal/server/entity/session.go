@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Session - вход пользователя с одного устройства. Сессия живёт, пока
+// её refresh-токен не истёк и не отозван; access-токены выдаются в её рамках.
+type Session struct {
+	CreatedAt  time.Time `db:"created_at"`
+	LastUsedAt time.Time `db:"last_used_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+	DeviceName string    `db:"device_name"`
+	ID         int       `db:"id"`
+	UserID     int       `db:"user_id"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Share - снимок записи, открытый по ссылке без входа. Payload зашифрован
+// на клиенте ключом, которого у сервера нет.
+type Share struct {
+	ExpiresAt time.Time `db:"expires_at"`
+	Payload   string    `db:"payload"`
+	UserID    int       `db:"user_id"`
+	DataID    int       `db:"data_id"`
+	ViewsLeft int       `db:"views_left"`
+}
+
+// SharePayload - снимок ссылки с хешем её идентификатора IDHash для ротации
+// ключа, которым сервер дополнительно шифрует Payload.
+type SharePayload struct {
+	Payload string
+	IDHash  []byte
+}
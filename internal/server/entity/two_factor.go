@@ -0,0 +1,36 @@
+package entity
+
+import "github.com/golang-jwt/jwt/v4"
+
+// TwoFactorState - состояние второго фактора пользователя. Secret хранится
+// зашифрованным; пустой Secret означает, что подключение не начиналось.
+type TwoFactorState struct {
+	Login    string
+	Secret   string
+	LastStep int64
+	Enabled  bool
+}
+
+// TOTPSecret - зашифрованный TOTP-секрет пользователя UserID для ротации ключей.
+type TOTPSecret struct {
+	Secret string
+	UserID int
+}
+
+// TOTPEnrollment - данные для подключения аутентификатора. Коды восстановления
+// показываются пользователю один раз, сервер хранит только их хеши.
+type TOTPEnrollment struct {
+	URI           string
+	Secret        string
+	RecoveryCodes []string
+}
+
+// ChallengeClaims - клеймы промежуточного токена входа: пароль проверен,
+// осталось подтвердить вход кодом второго фактора.
+type ChallengeClaims struct {
+	jwt.RegisteredClaims
+	Purpose    string
+	Login      string
+	DeviceName string
+	UserID     int
+}
@@ -1,7 +1,36 @@
 package entity
 
+import "time"
+
 type User struct {
 	Login    string `json:"login" db:"login"`
 	Password string `json:"password" db:"password"`
-	ID       int    `json:"id" db:"id"`
+	KDFParams
+	ID int `json:"id" db:"id"`
+	// TOTPEnabled - для входа нужен второй фактор.
+	TOTPEnabled bool `json:"totp_enabled" db:"totp_enabled"`
+}
+
+// KDFParams - соль и параметры Argon2id, по которым клиент получает
+// ключ шифрования из мастер-пароля. Сам мастер-пароль сервер не видит.
+type KDFParams struct {
+	Salt    []byte `json:"kdf_salt" db:"kdf_salt"`
+	Time    uint32 `json:"kdf_time" db:"kdf_time"`
+	Memory  uint32 `json:"kdf_memory" db:"kdf_memory"`
+	Threads uint32 `json:"kdf_threads" db:"kdf_threads"`
+	// KeyCheck - шифртекст константы под ключом пользователя, по нему клиент
+	// проверяет мастер-пароль. Пуст, пока клиент его не сохранил.
+	KeyCheck []byte `json:"kdf_key_check" db:"kdf_key_check"`
+}
+
+// AuthResult - результат успешной регистрации, входа или обновления токенов.
+// Token - короткоживущий access-токен, RefreshToken - токен для получения
+// следующей пары. Если у пользователя включён второй фактор, после проверки
+// пароля заполнен только Challenge, который обменивается на токены вместе с кодом.
+type AuthResult struct {
+	AccessExpiresAt time.Time
+	Token           string
+	RefreshToken    string
+	Challenge       string
+	KDF             KDFParams
 }
@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// Роли участников общего хранилища.
+const (
+	RoleOwner = "owner"
+	RoleWrite = "write"
+	RoleRead  = "read"
+)
+
+// Vault - общее хранилище с точки зрения одного участника: Role и WrappedKey
+// относятся к нему.
+type Vault struct {
+	Created    time.Time
+	Name       string
+	Role       string
+	WrappedKey string
+	ID         int
+	OwnerID    int
+}
+
+// VaultMember - участник общего хранилища.
+type VaultMember struct {
+	Login  string
+	Role   string
+	UserID int
+}
+
+// KeyPair - ключевая пара пользователя для обмена ключами хранилищ.
+// Закрытый ключ зашифрован клиентом.
+type KeyPair struct {
+	PublicKey        string
+	SealedPrivateKey string
+}
+
+// DataAccess - кому принадлежит запись и какие права на неё у пользователя.
+// OwnerID - значение user_data.user_id, VaultID - 0 для личной записи.
+type DataAccess struct {
+	Role    string
+	OwnerID int
+	VaultID int
+}
+
+// CanWrite - может ли пользователь изменять запись.
+func (a *DataAccess) CanWrite() bool {
+	return CanWrite(a.Role)
+}
+
+// CanWrite - разрешает ли роль изменять записи хранилища.
+func CanWrite(role string) bool {
+	return role == RoleOwner || role == RoleWrite
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/NikolosHGW/goph-keeper/api/auditpb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type auditLister interface {
+	ListEvents(ctx context.Context, userID int, beforeID int64, limit int) ([]*entity.AuditEvent, error)
+}
+
+type AuditServer struct {
+	auditpb.UnimplementedAuditServiceServer
+	auditService auditLister
+	logger       logger.CustomLogger
+}
+
+func NewAuditServer(auditService auditLister, logger logger.CustomLogger) *AuditServer {
+	return &AuditServer{auditService: auditService, logger: logger}
+}
+
+// ListAuditEvents - возвращает журнал вызовов текущего пользователя,
+// включая неудачные попытки входа под его логином.
+func (h *AuditServer) ListAuditEvents(
+	ctx context.Context, req *auditpb.ListAuditEventsRequest,
+) (*auditpb.ListAuditEventsResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+	if req.Limit < 0 || req.BeforeId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "размер страницы и before_id не могут быть отрицательными")
+	}
+
+	events, err := h.auditService.ListEvents(ctx, userID, req.BeforeId, int(req.Limit))
+	if err != nil {
+		h.logger.LogInfo("не удалось получить журнал аудита", err)
+		return nil, status.Error(codes.Internal, "не удалось получить журнал аудита")
+	}
+
+	resp := &auditpb.ListAuditEventsResponse{Events: make([]*auditpb.AuditEvent, 0, len(events))}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &auditpb.AuditEvent{
+			Id:        event.ID,
+			Action:    event.Action,
+			DataId:    int32(event.DataID),
+			ClientIp:  event.ClientIP,
+			UserAgent: event.UserAgent,
+			Outcome:   event.Outcome,
+			CreatedAt: timestamppb.New(event.CreatedAt),
+		})
+	}
+
+	return resp, nil
+}
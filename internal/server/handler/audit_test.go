@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/auditpb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockAuditLister struct {
+	mock.Mock
+}
+
+func (m *MockAuditLister) ListEvents(
+	ctx context.Context, userID int, beforeID int64, limit int,
+) ([]*entity.AuditEvent, error) {
+	args := m.Called(ctx, userID, beforeID, limit)
+	events, _ := args.Get(0).([]*entity.AuditEvent)
+	return events, args.Error(1)
+}
+
+func TestAuditServer_ListAuditEvents(t *testing.T) {
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		req          *auditpb.ListAuditEventsRequest
+		setupMock    func(m *MockAuditLister, ctx context.Context)
+		expected     []*auditpb.AuditEvent
+		expectedCode codes.Code
+	}{
+		{
+			name: "журнал пользователя",
+			req:  &auditpb.ListAuditEventsRequest{Limit: 10, BeforeId: 100},
+			setupMock: func(m *MockAuditLister, ctx context.Context) {
+				m.On("ListEvents", ctx, 1, int64(100), 10).Return([]*entity.AuditEvent{{
+					ID: 99, UserID: 1, Action: "/data.DataService/GetData", DataID: 5,
+					ClientIP: "10.0.0.1", UserAgent: "grpc-go", Outcome: "OK", CreatedAt: created,
+				}}, nil)
+			},
+			expected: []*auditpb.AuditEvent{{
+				Id: 99, Action: "/data.DataService/GetData", DataId: 5, ClientIp: "10.0.0.1",
+				UserAgent: "grpc-go", Outcome: "OK", CreatedAt: timestamppb.New(created),
+			}},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "отрицательный размер страницы",
+			req:          &auditpb.ListAuditEventsRequest{Limit: -1},
+			setupMock:    func(m *MockAuditLister, ctx context.Context) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "ошибка базы",
+			req:  &auditpb.ListAuditEventsRequest{},
+			setupMock: func(m *MockAuditLister, ctx context.Context) {
+				m.On("ListEvents", ctx, 1, int64(0), 0).Return(nil, errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := contextWithUserID(1)
+			lister := new(MockAuditLister)
+			tt.setupMock(lister, ctx)
+
+			res, err := NewAuditServer(lister, &mockLogger{}).ListAuditEvents(ctx, tt.req)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, res.Events)
+			}
+			lister.AssertExpectations(t)
+		})
+	}
+}
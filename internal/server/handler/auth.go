@@ -2,26 +2,47 @@ package handler
 
 import (
 	"context"
+	"errors"
 
 	pb "github.com/NikolosHGW/goph-keeper/api/authpb"
+	"github.com/NikolosHGW/goph-keeper/internal/contextkey"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type auth interface {
-	Handle(context.Context, *pb.LoginUserRequest) (string, error)
+	Handle(context.Context, *pb.LoginUserRequest) (*entity.AuthResult, error)
+	Complete(ctx context.Context, challenge, code string) (*entity.AuthResult, error)
+	SetKeyCheck(ctx context.Context, userID int, keyCheck []byte) error
+}
+
+type sessionManager interface {
+	Refresh(ctx context.Context, refreshToken string) (*entity.AuthResult, error)
+	List(ctx context.Context, userID int) ([]*entity.Session, error)
+	Revoke(ctx context.Context, userID, sessionID int) error
+}
+
+type twoFactorManager interface {
+	Enroll(ctx context.Context, userID int) (*entity.TOTPEnrollment, error)
+	Confirm(ctx context.Context, userID int, code string) error
+	Disable(ctx context.Context, userID int, code string) error
 }
 
 // AuthServer - структура gRPC сервера для авторизации пользователя.
 type AuthServer struct {
 	pb.UnimplementedAuthServer
 
-	authUseCase auth
+	authUseCase      auth
+	sessionService   sessionManager
+	twoFactorService twoFactorManager
 }
 
 // NewAuthServer - конструктор gRPC сервера для авторизации пользователя.
-func NewAuthServer(authUseCase auth) *AuthServer {
-	return &AuthServer{authUseCase: authUseCase}
+func NewAuthServer(authUseCase auth, sessionService sessionManager, twoFactorService twoFactorManager) *AuthServer {
+	return &AuthServer{authUseCase: authUseCase, sessionService: sessionService, twoFactorService: twoFactorService}
 }
 
 // LoginUser - реализация RPC сервиса.
@@ -34,12 +55,244 @@ func (s *AuthServer) LoginUser(
 		return nil, status.Errorf(codes.InvalidArgument, "неправильный запрос: %v", err)
 	}
 
-	token, err := s.authUseCase.Handle(ctx, req)
+	result, err := s.authUseCase.Handle(ctx, req)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "ошибка при авторизации: %v", err)
+		switch {
+		case errors.Is(err, helper.ErrInvalidCredentials):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, helper.ErrAccountLocked):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, "ошибка при авторизации: %v", err)
+		}
 	}
 
-	return &pb.LoginUserResponse{
-		BearerToken: token,
+	if result.Challenge != "" {
+		return &pb.LoginUserResponse{Challenge: result.Challenge}, nil
+	}
+
+	return loginResponse(result), nil
+}
+
+// CompleteLogin - второй шаг входа: обменивает challenge и код 2FA на токены.
+func (s *AuthServer) CompleteLogin(
+	ctx context.Context,
+	req *pb.CompleteLoginRequest,
+) (*pb.LoginUserResponse, error) {
+	if req.Challenge == "" || req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge и код обязательны")
+	}
+
+	result, err := s.authUseCase.Complete(ctx, req.Challenge, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, helper.ErrInvalidOTP), errors.Is(err, helper.ErrInvalidChallenge):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, helper.ErrAccountLocked):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, "ошибка при авторизации: %v", err)
+		}
+	}
+
+	return loginResponse(result), nil
+}
+
+// RefreshToken - выдаёт новую пару токенов в обмен на refresh-токен.
+func (s *AuthServer) RefreshToken(
+	ctx context.Context,
+	req *pb.RefreshTokenRequest,
+) (*pb.RefreshTokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh-токен не передан")
+	}
+
+	result, err := s.sessionService.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, helper.ErrInvalidRefresh) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "ошибка при обновлении токена")
+	}
+
+	return &pb.RefreshTokenResponse{
+		BearerToken:     result.Token,
+		RefreshToken:    result.RefreshToken,
+		AccessExpiresAt: timestamppb.New(result.AccessExpiresAt),
 	}, nil
 }
+
+// ListSessions - возвращает активные сессии пользователя.
+func (s *AuthServer) ListSessions(
+	ctx context.Context,
+	_ *pb.ListSessionsRequest,
+) (*pb.ListSessionsResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+	currentID, _ := ctx.Value(contextkey.SessionIDKey).(int)
+
+	sessions, err := s.sessionService.List(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить сессии")
+	}
+
+	resp := &pb.ListSessionsResponse{Sessions: make([]*pb.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, &pb.Session{
+			Id:         int64(session.ID),
+			DeviceName: session.DeviceName,
+			CreatedAt:  timestamppb.New(session.CreatedAt),
+			LastUsedAt: timestamppb.New(session.LastUsedAt),
+			Current:    session.ID == currentID,
+		})
+	}
+
+	return resp, nil
+}
+
+// RevokeSession - отзывает сессию пользователя. id = 0 - текущая сессия.
+func (s *AuthServer) RevokeSession(
+	ctx context.Context,
+	req *pb.RevokeSessionRequest,
+) (*pb.RevokeSessionResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	sessionID := int(req.Id)
+	if sessionID == 0 {
+		sessionID, _ = ctx.Value(contextkey.SessionIDKey).(int)
+	}
+
+	err = s.sessionService.Revoke(ctx, userID, sessionID)
+	if err != nil {
+		if errors.Is(err, helper.ErrSessionNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "не удалось отозвать сессию")
+	}
+
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+// EnrollTotp - начинает подключение аутентификатора.
+func (s *AuthServer) EnrollTotp(
+	ctx context.Context,
+	_ *pb.EnrollTotpRequest,
+) (*pb.EnrollTotpResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	enrollment, err := s.twoFactorService.Enroll(ctx, userID)
+	if err != nil {
+		if errors.Is(err, helper.ErrTwoFactorEnabled) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "не удалось подключить аутентификатор")
+	}
+
+	return &pb.EnrollTotpResponse{
+		OtpauthUri:    enrollment.URI,
+		Secret:        enrollment.Secret,
+		RecoveryCodes: enrollment.RecoveryCodes,
+	}, nil
+}
+
+// ConfirmTotp - включает 2FA после проверки первого кода из аутентификатора.
+func (s *AuthServer) ConfirmTotp(
+	ctx context.Context,
+	req *pb.ConfirmTotpRequest,
+) (*pb.ConfirmTotpResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	err = s.twoFactorService.Confirm(ctx, userID, req.Code)
+	if err != nil {
+		return nil, twoFactorStatus(err, "не удалось включить 2FA")
+	}
+
+	return &pb.ConfirmTotpResponse{}, nil
+}
+
+// DisableTotp - выключает 2FA. Требует код из аутентификатора или код восстановления.
+func (s *AuthServer) DisableTotp(
+	ctx context.Context,
+	req *pb.DisableTotpRequest,
+) (*pb.DisableTotpResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	err = s.twoFactorService.Disable(ctx, userID, req.Code)
+	if err != nil {
+		return nil, twoFactorStatus(err, "не удалось выключить 2FA")
+	}
+
+	return &pb.DisableTotpResponse{}, nil
+}
+
+// maxKeyCheckSize - с запасом больше шифртекста проверки: nonce, константа и тег.
+const maxKeyCheckSize = 256
+
+// SetKeyCheck - сохраняет проверку мастер-пароля. Задать её можно только один раз.
+func (s *AuthServer) SetKeyCheck(
+	ctx context.Context,
+	req *pb.SetKeyCheckRequest,
+) (*pb.SetKeyCheckResponse, error) {
+	if len(req.KeyCheck) == 0 || len(req.KeyCheck) > maxKeyCheckSize {
+		return nil, status.Error(codes.InvalidArgument, "некорректная проверка мастер-пароля")
+	}
+
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	err = s.authUseCase.SetKeyCheck(ctx, userID, req.KeyCheck)
+	if err != nil {
+		if errors.Is(err, helper.ErrKeyCheckExists) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "не удалось сохранить проверку мастер-пароля")
+	}
+
+	return &pb.SetKeyCheckResponse{}, nil
+}
+
+func twoFactorStatus(err error, internalMessage string) error {
+	switch {
+	case errors.Is(err, helper.ErrInvalidOTP):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, helper.ErrAccountLocked):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, helper.ErrTwoFactorEnabled),
+		errors.Is(err, helper.ErrTwoFactorDisabled),
+		errors.Is(err, helper.ErrTOTPNotEnrolled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, internalMessage)
+	}
+}
+
+func loginResponse(result *entity.AuthResult) *pb.LoginUserResponse {
+	return &pb.LoginUserResponse{
+		BearerToken: result.Token,
+		Kdf: &pb.KdfParams{
+			Salt:     result.KDF.Salt,
+			Time:     result.KDF.Time,
+			Memory:   result.KDF.Memory,
+			Threads:  result.KDF.Threads,
+			KeyCheck: result.KDF.KeyCheck,
+		},
+		RefreshToken:    result.RefreshToken,
+		AccessExpiresAt: timestamppb.New(result.AccessExpiresAt),
+	}
+}
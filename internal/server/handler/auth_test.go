@@ -3,22 +3,81 @@ package handler
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	pb "github.com/NikolosHGW/goph-keeper/api/authpb"
+	"github.com/NikolosHGW/goph-keeper/internal/contextkey"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type MockAuthUseCase struct {
 	mock.Mock
 }
 
-func (m *MockAuthUseCase) Handle(ctx context.Context, req *pb.LoginUserRequest) (string, error) {
+func (m *MockAuthUseCase) Handle(ctx context.Context, req *pb.LoginUserRequest) (*entity.AuthResult, error) {
 	args := m.Called(ctx, req)
-	return args.String(0), args.Error(1)
+	result, _ := args.Get(0).(*entity.AuthResult)
+	return result, args.Error(1)
+}
+
+func (m *MockAuthUseCase) Complete(ctx context.Context, challenge, code string) (*entity.AuthResult, error) {
+	args := m.Called(ctx, challenge, code)
+	result, _ := args.Get(0).(*entity.AuthResult)
+	return result, args.Error(1)
+}
+
+func (m *MockAuthUseCase) SetKeyCheck(ctx context.Context, userID int, keyCheck []byte) error {
+	args := m.Called(ctx, userID, keyCheck)
+	return args.Error(0)
+}
+
+type MockTwoFactorManager struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorManager) Enroll(ctx context.Context, userID int) (*entity.TOTPEnrollment, error) {
+	args := m.Called(ctx, userID)
+	enrollment, _ := args.Get(0).(*entity.TOTPEnrollment)
+	return enrollment, args.Error(1)
+}
+
+func (m *MockTwoFactorManager) Confirm(ctx context.Context, userID int, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorManager) Disable(ctx context.Context, userID int, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+type MockSessionManager struct {
+	mock.Mock
+}
+
+func (m *MockSessionManager) Refresh(ctx context.Context, refreshToken string) (*entity.AuthResult, error) {
+	args := m.Called(ctx, refreshToken)
+	result, _ := args.Get(0).(*entity.AuthResult)
+	return result, args.Error(1)
+}
+
+func (m *MockSessionManager) List(ctx context.Context, userID int) ([]*entity.Session, error) {
+	args := m.Called(ctx, userID)
+	sessions, _ := args.Get(0).([]*entity.Session)
+	return sessions, args.Error(1)
+}
+
+func (m *MockSessionManager) Revoke(ctx context.Context, userID, sessionID int) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
 }
 
 func TestAuthServer_Login(t *testing.T) {
@@ -40,13 +99,34 @@ func TestAuthServer_Login(t *testing.T) {
 				Password: "password123",
 			},
 			setupMock: func(m *MockAuthUseCase) {
-				m.On("Handle", ctx, mock.AnythingOfType("*authpb.LoginUserRequest")).Return("testtoken", nil)
+				m.On("Handle", ctx, mock.AnythingOfType("*authpb.LoginUserRequest")).Return(&entity.AuthResult{
+					Token:           "testtoken",
+					RefreshToken:    "refreshtoken",
+					AccessExpiresAt: time.Unix(1700000000, 0),
+					KDF:             entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
+				}, nil)
 			},
 			expectedResp: &pb.LoginUserResponse{
-				BearerToken: "testtoken",
+				BearerToken:     "testtoken",
+				Kdf:             &pb.KdfParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
+				RefreshToken:    "refreshtoken",
+				AccessExpiresAt: &timestamppb.Timestamp{Seconds: 1700000000},
 			},
 			expectedErrCode: codes.OK,
 		},
+		{
+			name: "Включена 2FA",
+			req: &pb.LoginUserRequest{
+				Login:    "testuser",
+				Password: "password123",
+			},
+			setupMock: func(m *MockAuthUseCase) {
+				m.On("Handle", ctx, mock.AnythingOfType("*authpb.LoginUserRequest")).
+					Return(&entity.AuthResult{Challenge: "challenge"}, nil)
+			},
+			expectedResp:    &pb.LoginUserResponse{Challenge: "challenge"},
+			expectedErrCode: codes.OK,
+		},
 		{
 			name: "Ошибка валидации - пустой логин",
 			req: &pb.LoginUserRequest{
@@ -67,6 +147,16 @@ func TestAuthServer_Login(t *testing.T) {
 			expectedResp:    nil,
 			expectedErrCode: codes.InvalidArgument,
 		},
+		{
+			name: "Слишком длинный логин",
+			req: &pb.LoginUserRequest{
+				Login:    strings.Repeat("a", maxLoginLength+1),
+				Password: "password123",
+			},
+			setupMock:       func(m *MockAuthUseCase) {},
+			expectedResp:    nil,
+			expectedErrCode: codes.InvalidArgument,
+		},
 		{
 			name: "Слишком длинный пароль",
 			req: &pb.LoginUserRequest{
@@ -84,11 +174,35 @@ func TestAuthServer_Login(t *testing.T) {
 				Password: "password123",
 			},
 			setupMock: func(m *MockAuthUseCase) {
-				m.On("Handle", ctx, mock.AnythingOfType("*authpb.LoginUserRequest")).Return("", errors.New("some internal error"))
+				m.On("Handle", ctx, mock.AnythingOfType("*authpb.LoginUserRequest")).Return(nil, errors.New("some internal error"))
 			},
 			expectedResp:    nil,
 			expectedErrCode: codes.Internal,
 		},
+		{
+			name: "Неверная пара логин/пароль",
+			req: &pb.LoginUserRequest{
+				Login:    "testuser",
+				Password: "wrongpassword",
+			},
+			setupMock: func(m *MockAuthUseCase) {
+				m.On("Handle", ctx, mock.AnythingOfType("*authpb.LoginUserRequest")).Return(nil, helper.ErrInvalidCredentials)
+			},
+			expectedResp:    nil,
+			expectedErrCode: codes.Unauthenticated,
+		},
+		{
+			name: "Учётная запись заблокирована",
+			req: &pb.LoginUserRequest{
+				Login:    "testuser",
+				Password: "password123",
+			},
+			setupMock: func(m *MockAuthUseCase) {
+				m.On("Handle", ctx, mock.AnythingOfType("*authpb.LoginUserRequest")).Return(nil, helper.ErrAccountLocked)
+			},
+			expectedResp:    nil,
+			expectedErrCode: codes.PermissionDenied,
+		},
 	}
 
 	for _, tt := range tests {
@@ -98,7 +212,7 @@ func TestAuthServer_Login(t *testing.T) {
 				tt.setupMock(mockAuthUseCase)
 			}
 
-			server := NewAuthServer(mockAuthUseCase)
+			server := NewAuthServer(mockAuthUseCase, new(MockSessionManager), new(MockTwoFactorManager))
 
 			resp, err := server.LoginUser(ctx, tt.req)
 
@@ -116,3 +230,310 @@ func TestAuthServer_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthServer_RefreshToken(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		req          *pb.RefreshTokenRequest
+		setupMock    func(m *MockSessionManager)
+		expectedResp *pb.RefreshTokenResponse
+		expectedCode codes.Code
+	}{
+		{
+			name: "успешное обновление",
+			req:  &pb.RefreshTokenRequest{RefreshToken: "old"},
+			setupMock: func(m *MockSessionManager) {
+				m.On("Refresh", ctx, "old").Return(&entity.AuthResult{
+					Token:           "access",
+					RefreshToken:    "new",
+					AccessExpiresAt: time.Unix(1700000000, 0),
+				}, nil)
+			},
+			expectedResp: &pb.RefreshTokenResponse{
+				BearerToken:     "access",
+				RefreshToken:    "new",
+				AccessExpiresAt: &timestamppb.Timestamp{Seconds: 1700000000},
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "пустой токен",
+			req:          &pb.RefreshTokenRequest{},
+			setupMock:    func(m *MockSessionManager) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "недействительный токен",
+			req:  &pb.RefreshTokenRequest{RefreshToken: "reused"},
+			setupMock: func(m *MockSessionManager) {
+				m.On("Refresh", ctx, "reused").Return(nil, helper.ErrInvalidRefresh)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "внутренняя ошибка",
+			req:  &pb.RefreshTokenRequest{RefreshToken: "old"},
+			setupMock: func(m *MockSessionManager) {
+				m.On("Refresh", ctx, "old").Return(nil, errors.New("db error"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := new(MockSessionManager)
+			tt.setupMock(sessions)
+			server := NewAuthServer(new(MockAuthUseCase), sessions, new(MockTwoFactorManager))
+
+			resp, err := server.RefreshToken(ctx, tt.req)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedResp, resp)
+			sessions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthServer_ListSessions(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.UserIDKey, 1)
+	ctx = context.WithValue(ctx, contextkey.SessionIDKey, 2)
+
+	created := time.Unix(1700000000, 0)
+	used := time.Unix(1700003600, 0)
+
+	sessions := new(MockSessionManager)
+	sessions.On("List", ctx, 1).Return([]*entity.Session{
+		{ID: 2, UserID: 1, DeviceName: "laptop", CreatedAt: created, LastUsedAt: used},
+		{ID: 3, UserID: 1, DeviceName: "phone", CreatedAt: created, LastUsedAt: created},
+	}, nil)
+	server := NewAuthServer(new(MockAuthUseCase), sessions, new(MockTwoFactorManager))
+
+	resp, err := server.ListSessions(ctx, &pb.ListSessionsRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.Session{
+		{
+			Id: 2, DeviceName: "laptop", Current: true,
+			CreatedAt: timestamppb.New(created), LastUsedAt: timestamppb.New(used),
+		},
+		{
+			Id: 3, DeviceName: "phone",
+			CreatedAt: timestamppb.New(created), LastUsedAt: timestamppb.New(created),
+		},
+	}, resp.Sessions)
+	sessions.AssertExpectations(t)
+}
+
+func TestAuthServer_RevokeSession(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.UserIDKey, 1)
+	ctx = context.WithValue(ctx, contextkey.SessionIDKey, 2)
+
+	tests := []struct {
+		name         string
+		req          *pb.RevokeSessionRequest
+		setupMock    func(m *MockSessionManager)
+		expectedCode codes.Code
+	}{
+		{
+			name: "отзыв другой сессии",
+			req:  &pb.RevokeSessionRequest{Id: 5},
+			setupMock: func(m *MockSessionManager) {
+				m.On("Revoke", ctx, 1, 5).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "выход из текущей сессии",
+			req:  &pb.RevokeSessionRequest{},
+			setupMock: func(m *MockSessionManager) {
+				m.On("Revoke", ctx, 1, 2).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "чужая или уже отозванная сессия",
+			req:  &pb.RevokeSessionRequest{Id: 9},
+			setupMock: func(m *MockSessionManager) {
+				m.On("Revoke", ctx, 1, 9).Return(helper.ErrSessionNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := new(MockSessionManager)
+			tt.setupMock(sessions)
+			server := NewAuthServer(new(MockAuthUseCase), sessions, new(MockTwoFactorManager))
+
+			_, err := server.RevokeSession(ctx, tt.req)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			sessions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthServer_CompleteLogin(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		req          *pb.CompleteLoginRequest
+		setupMock    func(m *MockAuthUseCase)
+		expectedResp *pb.LoginUserResponse
+		expectedCode codes.Code
+	}{
+		{
+			name: "код подходит",
+			req:  &pb.CompleteLoginRequest{Challenge: "challenge", Code: "123456"},
+			setupMock: func(m *MockAuthUseCase) {
+				m.On("Complete", ctx, "challenge", "123456").Return(&entity.AuthResult{
+					Token:           "access",
+					RefreshToken:    "refresh",
+					AccessExpiresAt: time.Unix(1700000000, 0),
+					KDF:             entity.KDFParams{Salt: []byte("salt"), Time: 3},
+				}, nil)
+			},
+			expectedResp: &pb.LoginUserResponse{
+				BearerToken:     "access",
+				Kdf:             &pb.KdfParams{Salt: []byte("salt"), Time: 3},
+				RefreshToken:    "refresh",
+				AccessExpiresAt: &timestamppb.Timestamp{Seconds: 1700000000},
+			},
+		},
+		{
+			name:         "пустой код",
+			req:          &pb.CompleteLoginRequest{Challenge: "challenge"},
+			setupMock:    func(m *MockAuthUseCase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "неверный код",
+			req:  &pb.CompleteLoginRequest{Challenge: "challenge", Code: "000000"},
+			setupMock: func(m *MockAuthUseCase) {
+				m.On("Complete", ctx, "challenge", "000000").Return(nil, helper.ErrInvalidOTP)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "challenge истёк",
+			req:  &pb.CompleteLoginRequest{Challenge: "old", Code: "123456"},
+			setupMock: func(m *MockAuthUseCase) {
+				m.On("Complete", ctx, "old", "123456").Return(nil, helper.ErrInvalidChallenge)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "учётная запись заблокирована",
+			req:  &pb.CompleteLoginRequest{Challenge: "challenge", Code: "000000"},
+			setupMock: func(m *MockAuthUseCase) {
+				m.On("Complete", ctx, "challenge", "000000").Return(nil, helper.ErrAccountLocked)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authUseCase := new(MockAuthUseCase)
+			tt.setupMock(authUseCase)
+			server := NewAuthServer(authUseCase, new(MockSessionManager), new(MockTwoFactorManager))
+
+			resp, err := server.CompleteLogin(ctx, tt.req)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedResp, resp)
+			authUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthServer_EnrollTotp(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.UserIDKey, 1)
+
+	twoFactor := new(MockTwoFactorManager)
+	twoFactor.On("Enroll", ctx, 1).Return(&entity.TOTPEnrollment{
+		URI: "otpauth://totp/x", Secret: "ABC", RecoveryCodes: []string{"aaaa-bbbb"},
+	}, nil).Once()
+	twoFactor.On("Enroll", ctx, 1).Return(nil, helper.ErrTwoFactorEnabled).Once()
+	server := NewAuthServer(new(MockAuthUseCase), new(MockSessionManager), twoFactor)
+
+	resp, err := server.EnrollTotp(ctx, &pb.EnrollTotpRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.EnrollTotpResponse{
+		OtpauthUri: "otpauth://totp/x", Secret: "ABC", RecoveryCodes: []string{"aaaa-bbbb"},
+	}, resp)
+
+	_, err = server.EnrollTotp(ctx, &pb.EnrollTotpRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	twoFactor.AssertExpectations(t)
+}
+
+func TestAuthServer_ConfirmAndDisableTotp(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.UserIDKey, 1)
+
+	tests := []struct {
+		err          error
+		name         string
+		expectedCode codes.Code
+	}{
+		{name: "успех", expectedCode: codes.OK},
+		{name: "неверный код", err: helper.ErrInvalidOTP, expectedCode: codes.InvalidArgument},
+		{name: "аутентификатор не подключён", err: helper.ErrTOTPNotEnrolled, expectedCode: codes.FailedPrecondition},
+		{name: "вход заблокирован", err: helper.ErrAccountLocked, expectedCode: codes.PermissionDenied},
+		{name: "2FA не включена", err: helper.ErrTwoFactorDisabled, expectedCode: codes.FailedPrecondition},
+		{name: "ошибка базы", err: helper.ErrInternalServer, expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactor := new(MockTwoFactorManager)
+			twoFactor.On("Confirm", ctx, 1, "123456").Return(tt.err)
+			twoFactor.On("Disable", ctx, 1, "123456").Return(tt.err)
+			server := NewAuthServer(new(MockAuthUseCase), new(MockSessionManager), twoFactor)
+
+			_, err := server.ConfirmTotp(ctx, &pb.ConfirmTotpRequest{Code: "123456"})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+
+			_, err = server.DisableTotp(ctx, &pb.DisableTotpRequest{Code: "123456"})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			twoFactor.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthServer_SetKeyCheck(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.UserIDKey, 1)
+
+	tests := []struct {
+		err          error
+		name         string
+		keyCheck     []byte
+		expectedCode codes.Code
+	}{
+		{name: "успех", keyCheck: []byte("check"), expectedCode: codes.OK},
+		{name: "пустая проверка", expectedCode: codes.InvalidArgument},
+		{name: "слишком длинная", keyCheck: make([]byte, maxKeyCheckSize+1), expectedCode: codes.InvalidArgument},
+		{
+			name: "уже задана", keyCheck: []byte("check"),
+			err: helper.ErrKeyCheckExists, expectedCode: codes.AlreadyExists,
+		},
+		{name: "ошибка базы", keyCheck: []byte("check"), err: helper.ErrInternalServer, expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authUseCase := new(MockAuthUseCase)
+			authUseCase.On("SetKeyCheck", ctx, 1, tt.keyCheck).Return(tt.err).Maybe()
+			server := NewAuthServer(authUseCase, new(MockSessionManager), new(MockTwoFactorManager))
+
+			_, err := server.SetKeyCheck(ctx, &pb.SetKeyCheckRequest{KeyCheck: tt.keyCheck})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"regexp"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/blindindex"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const downloadChunkSize = 64 * 1024
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+var errUnexpectedHeader = errors.New("повторный заголовок в потоке")
+
+// UploadBinary - принимает поток: первым сообщением заголовок, затем чанки содержимого.
+func (h *DataServer) UploadBinary(stream datapb.DataService_UploadBinaryServer) error {
+	ctx := stream.Context()
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "поток пуст")
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "первое сообщение должно содержать заголовок")
+	}
+	if header.Size < 0 || !sha256Pattern.MatchString(header.Sha256) {
+		return status.Error(codes.InvalidArgument, "некорректный размер или SHA-256 в заголовке")
+	}
+	tokens, err := validatedSearchTokens(header.SearchTokens, blindindex.MaxTokens)
+	if err != nil {
+		return err
+	}
+
+	id, err := h.dataService.UploadBinary(ctx, userID,
+		&entity.UserData{
+			Info: header.Info, Meta: header.Meta, SearchTokens: tokens, Created: requestCreated(header.Created),
+		},
+		&entity.BinaryBlob{Size: header.Size, SHA256: header.Sha256},
+		&uploadReader{stream: stream},
+	)
+	switch {
+	case err == nil:
+	case errors.Is(err, helper.ErrChecksumMismatch):
+		return status.Error(codes.DataLoss, "размер или контрольная сумма не совпадают с заголовком")
+	case errors.Is(err, errUnexpectedHeader):
+		return status.Error(codes.InvalidArgument, errUnexpectedHeader.Error())
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	default:
+		h.logger.LogInfo("Ошибка при загрузке файла", err)
+		return status.Error(codes.Internal, "ошибка при загрузке файла")
+	}
+
+	return stream.SendAndClose(&datapb.UploadBinaryResponse{Id: int32(id)})
+}
+
+// DownloadBinary - отдаёт заголовок файла, затем его содержимое чанками.
+func (h *DataServer) DownloadBinary(
+	req *datapb.DownloadBinaryRequest, stream datapb.DataService_DownloadBinaryServer,
+) error {
+	ctx := stream.Context()
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	data, blob, content, err := h.dataService.DownloadBinary(ctx, userID, int(req.Id))
+	if errors.Is(err, helper.ErrDataNotFound) {
+		return status.Error(codes.NotFound, "данные не найдены")
+	}
+	if err != nil {
+		h.logger.LogInfo("Ошибка при получении файла", err)
+		return status.Error(codes.Internal, "ошибка при получении файла")
+	}
+	defer func() {
+		if closeErr := content.Close(); closeErr != nil {
+			h.logger.LogInfo("ошибка при закрытии блоба", closeErr)
+		}
+	}()
+
+	err = stream.Send(&datapb.DownloadBinaryResponse{Part: &datapb.DownloadBinaryResponse_Header{
+		Header: &datapb.BinaryHeader{
+			Id:     int32(data.ID),
+			Info:   data.Info,
+			Meta:   data.Meta,
+			Size:   blob.Size,
+			Sha256: blob.SHA256,
+		},
+	}})
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := content.Read(buf)
+		if n > 0 {
+			chunk := &datapb.DownloadBinaryResponse{Part: &datapb.DownloadBinaryResponse_Chunk{Chunk: buf[:n]}}
+			if sendErr := stream.Send(chunk); sendErr != nil {
+				return sendErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			h.logger.LogInfo("Ошибка чтения блоба", err)
+			return status.Error(codes.Internal, "ошибка при чтении файла")
+		}
+	}
+}
+
+// uploadReader - представляет чанки входящего потока как io.Reader.
+type uploadReader struct {
+	stream datapb.DataService_UploadBinaryServer
+	buf    []byte
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if msg.GetHeader() != nil {
+			return 0, errUnexpectedHeader
+		}
+		r.buf = msg.GetChunk()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
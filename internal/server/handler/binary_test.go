@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeUploadStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*datapb.UploadBinaryRequest
+	response *datapb.UploadBinaryResponse
+}
+
+func (s *fakeUploadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeUploadStream) Recv() (*datapb.UploadBinaryRequest, error) {
+	if len(s.messages) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return msg, nil
+}
+
+func (s *fakeUploadStream) SendAndClose(resp *datapb.UploadBinaryResponse) error {
+	s.response = resp
+	return nil
+}
+
+type fakeDownloadStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*datapb.DownloadBinaryResponse
+}
+
+func (s *fakeDownloadStream) Context() context.Context {
+	return s.ctx
+}
+
+// Send - копирует сообщение, как это делает gRPC при сериализации:
+// обработчик переиспользует буфер чанка между отправками.
+func (s *fakeDownloadStream) Send(resp *datapb.DownloadBinaryResponse) error {
+	s.sent = append(s.sent, proto.Clone(resp).(*datapb.DownloadBinaryResponse))
+	return nil
+}
+
+func headerMsg(h *datapb.BinaryHeader) *datapb.UploadBinaryRequest {
+	return &datapb.UploadBinaryRequest{Part: &datapb.UploadBinaryRequest_Header{Header: h}}
+}
+
+func chunkMsg(chunk string) *datapb.UploadBinaryRequest {
+	return &datapb.UploadBinaryRequest{Part: &datapb.UploadBinaryRequest_Chunk{Chunk: []byte(chunk)}}
+}
+
+func TestUploadBinary(t *testing.T) {
+	sum := sha256.Sum256([]byte("hello world"))
+	validHeader := &datapb.BinaryHeader{Info: "info", Meta: "meta", Size: 11, Sha256: hex.EncodeToString(sum[:])}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		messages     []*datapb.UploadBinaryRequest
+		serviceErr   error
+		expectedCode codes.Code
+		expectedBody string
+	}{
+		{
+			name:         "успешная загрузка",
+			ctx:          contextWithUserID(1),
+			messages:     []*datapb.UploadBinaryRequest{headerMsg(validHeader), chunkMsg("hello "), chunkMsg("world")},
+			expectedCode: codes.OK,
+			expectedBody: "hello world",
+		},
+		{
+			name:         "без userID",
+			ctx:          context.Background(),
+			expectedCode: codes.Internal,
+		},
+		{
+			name:         "пустой поток",
+			ctx:          contextWithUserID(1),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "первым пришёл чанк",
+			ctx:          contextWithUserID(1),
+			messages:     []*datapb.UploadBinaryRequest{chunkMsg("hello")},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "некорректный SHA-256",
+			ctx:          contextWithUserID(1),
+			messages:     []*datapb.UploadBinaryRequest{headerMsg(&datapb.BinaryHeader{Size: 1, Sha256: "abc"})},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "повторный заголовок",
+			ctx:          contextWithUserID(1),
+			messages:     []*datapb.UploadBinaryRequest{headerMsg(validHeader), headerMsg(validHeader)},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "контрольная сумма не совпала",
+			ctx:          contextWithUserID(1),
+			messages:     []*datapb.UploadBinaryRequest{headerMsg(validHeader), chunkMsg("hello")},
+			serviceErr:   helper.ErrChecksumMismatch,
+			expectedCode: codes.DataLoss,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			mockService := &mockDataService{
+				UploadBinaryFunc: func(
+					ctx context.Context, userID int, data *entity.UserData, expected *entity.BinaryBlob, r io.Reader,
+				) (int, error) {
+					content, err := io.ReadAll(r)
+					if err != nil {
+						return 0, err
+					}
+					received = string(content)
+					if tt.serviceErr != nil {
+						return 0, tt.serviceErr
+					}
+					if data.Info != "info" || expected.Size != 11 {
+						t.Errorf("Unexpected header: %+v %+v", data, expected)
+					}
+					return 9, nil
+				},
+			}
+			server := NewDataServer(mockService, &mockLogger{})
+			stream := &fakeUploadStream{ctx: tt.ctx, messages: tt.messages}
+
+			err := server.UploadBinary(stream)
+
+			if status.Code(err) != tt.expectedCode {
+				t.Fatalf("Expected code %v, got: %v", tt.expectedCode, err)
+			}
+			if tt.expectedCode == codes.OK {
+				if stream.response.GetId() != 9 {
+					t.Errorf("Expected id 9, got %v", stream.response)
+				}
+				if received != tt.expectedBody {
+					t.Errorf("Expected body %q, got %q", tt.expectedBody, received)
+				}
+			}
+		})
+	}
+}
+
+func TestDownloadBinary(t *testing.T) {
+	content := strings.Repeat("x", downloadChunkSize) + strings.Repeat("y", 10)
+
+	mockService := &mockDataService{
+		DownloadBinaryFunc: func(
+			ctx context.Context, userID, dataID int,
+		) (*entity.UserData, *entity.BinaryBlob, io.ReadCloser, error) {
+			if dataID != 5 {
+				return nil, nil, nil, helper.ErrDataNotFound
+			}
+			return &entity.UserData{ID: 5, Info: "info", Meta: "meta"},
+				&entity.BinaryBlob{Size: int64(len(content)), SHA256: "hash"},
+				io.NopCloser(strings.NewReader(content)), nil
+		},
+	}
+	server := NewDataServer(mockService, &mockLogger{})
+
+	stream := &fakeDownloadStream{ctx: contextWithUserID(1)}
+	if err := server.DownloadBinary(&datapb.DownloadBinaryRequest{Id: 5}, stream); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(stream.sent) != 3 {
+		t.Fatalf("Expected header and 2 chunks, got %d messages", len(stream.sent))
+	}
+	header := stream.sent[0].GetHeader()
+	if header.GetId() != 5 || header.GetInfo() != "info" || header.GetSha256() != "hash" {
+		t.Errorf("Unexpected header: %v", header)
+	}
+	var body bytes.Buffer
+	for _, msg := range stream.sent[1:] {
+		body.Write(msg.GetChunk())
+	}
+	if body.String() != content {
+		t.Errorf("Downloaded content does not match")
+	}
+
+	err := server.DownloadBinary(&datapb.DownloadBinaryRequest{Id: 6}, &fakeDownloadStream{ctx: contextWithUserID(1)})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GetChanges - отдаёт изменения и удаления записей после since_revision,
+// чтобы клиент мог синхронизироваться без полной загрузки.
+func (h *DataServer) GetChanges(ctx context.Context, req *datapb.GetChangesRequest) (*datapb.GetChangesResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	if req.SinceRevision < 0 || req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ревизия и лимит не могут быть отрицательными")
+	}
+
+	changes, err := h.dataService.GetChanges(ctx, userID, req.SinceRevision, int(req.Limit))
+	if err != nil {
+		h.logger.LogInfo("Ошибка при получении изменений", err)
+		return nil, status.Error(codes.Internal, "ошибка при получении изменений")
+	}
+
+	res := &datapb.GetChangesResponse{
+		Upserts:   make([]*datapb.DataItem, 0, len(changes.Upserts)),
+		Deletions: make([]*datapb.Tombstone, 0, len(changes.Deletions)),
+		Revision:  changes.Revision,
+		HasMore:   changes.HasMore,
+	}
+	for _, item := range changes.Upserts {
+		res.Upserts = append(res.Upserts, dataItemFromEntity(item))
+	}
+	for _, tombstone := range changes.Deletions {
+		res.Deletions = append(res.Deletions, &datapb.Tombstone{
+			Id:        int32(tombstone.DataID),
+			Revision:  tombstone.Revision,
+			DeletedAt: timestamppb.New(tombstone.DeletedAt),
+		})
+	}
+
+	return res, nil
+}
+
+// dataItemFromEntity - собирает DataItem из записи и раскрывает типизированный payload.
+func dataItemFromEntity(data *entity.UserData) *datapb.DataItem {
+	item := &datapb.DataItem{
+		Id:       int32(data.ID),
+		InfoType: data.InfoType,
+		Info:     data.Info,
+		Meta:     data.Meta,
+		Created:  timestamppb.New(data.Created),
+		Revision: data.Revision,
+		Version:  data.Version,
+		VaultId:  int32(data.VaultID),
+		Tags:     data.Tags,
+		Folder:   data.Folder,
+		Favorite: data.Favorite,
+	}
+	if !data.UpdatedAt.IsZero() {
+		item.UpdatedAt = timestamppb.New(data.UpdatedAt)
+	}
+	payload.Decode(data.Info, item)
+
+	return item
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (m *mockDataService) GetChanges(ctx context.Context, userID int, since int64, limit int) (*entity.DataChanges, error) {
+	return m.GetChangesFunc(ctx, userID, since, limit)
+}
+
+func TestGetChanges(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		req          *datapb.GetChangesRequest
+		changes      *entity.DataChanges
+		serviceErr   error
+		expectedCode codes.Code
+		expectedRes  *datapb.GetChangesResponse
+	}{
+		{
+			name: "изменения и удаления",
+			ctx:  contextWithUserID(1),
+			req:  &datapb.GetChangesRequest{SinceRevision: 3, Limit: 10},
+			changes: &entity.DataChanges{
+				Upserts: []*entity.UserData{{
+					ID: 5, InfoType: "text", Info: "info", Meta: "meta",
+					Created: created, UpdatedAt: updated, Revision: 4,
+				}},
+				Deletions: []*entity.Tombstone{{DataID: 2, Revision: 6, DeletedAt: updated}},
+				Revision:  6,
+				HasMore:   true,
+			},
+			expectedCode: codes.OK,
+			expectedRes: &datapb.GetChangesResponse{
+				Upserts: []*datapb.DataItem{{
+					Id: 5, InfoType: "text", Info: "info", Meta: "meta",
+					Created: timestamppb.New(created), UpdatedAt: timestamppb.New(updated), Revision: 4,
+				}},
+				Deletions: []*datapb.Tombstone{{Id: 2, Revision: 6, DeletedAt: timestamppb.New(updated)}},
+				Revision:  6,
+				HasMore:   true,
+			},
+		},
+		{
+			name:         "отрицательная ревизия",
+			ctx:          contextWithUserID(1),
+			req:          &datapb.GetChangesRequest{SinceRevision: -1},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "ошибка сервиса",
+			ctx:          contextWithUserID(1),
+			req:          &datapb.GetChangesRequest{},
+			serviceErr:   errors.New("db error"),
+			expectedCode: codes.Internal,
+		},
+		{
+			name:         "нет userID в контексте",
+			ctx:          context.Background(),
+			req:          &datapb.GetChangesRequest{},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				GetChangesFunc: func(ctx context.Context, userID int, since int64, limit int) (*entity.DataChanges, error) {
+					assert.Equal(t, 1, userID)
+					assert.Equal(t, tt.req.SinceRevision, since)
+					assert.Equal(t, int(tt.req.Limit), limit)
+					return tt.changes, tt.serviceErr
+				},
+			}
+			server := NewDataServer(mockService, &mockLogger{})
+
+			res, err := server.GetChanges(tt.ctx, tt.req)
+
+			if tt.expectedCode != codes.OK {
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(tt.expectedRes, res), "ответ: %v", res)
+		})
+	}
+}
@@ -3,10 +3,17 @@ package handler
 import (
 	"context"
 	"errors"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/blindindex"
 	"github.com/NikolosHGW/goph-keeper/internal/contextkey"
+	"github.com/NikolosHGW/goph-keeper/internal/label"
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
 	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
 	"github.com/NikolosHGW/goph-keeper/pkg/logger"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -16,8 +23,22 @@ import (
 type dataService interface {
 	AddData(ctx context.Context, userID int, data *entity.UserData) (int, error)
 	GetDataByID(ctx context.Context, userID, dataID int) (*entity.UserData, error)
-	UpdateData(ctx context.Context, userID int, data *entity.UserData) error
-	DeleteData(ctx context.Context, userID, dataID int) error
+	UpdateData(ctx context.Context, userID int, data *entity.UserData) (int64, error)
+	DeleteData(ctx context.Context, userID, dataID int, version int64) error
+	ListData(ctx context.Context, userID int, filter *entity.DataFilter) ([]*entity.UserData, string, error)
+	GetChanges(ctx context.Context, userID int, since int64, limit int) (*entity.DataChanges, error)
+	UploadBinary(
+		ctx context.Context, userID int, data *entity.UserData, expected *entity.BinaryBlob, r io.Reader,
+	) (int, error)
+	DownloadBinary(ctx context.Context, userID, dataID int) (*entity.UserData, *entity.BinaryBlob, io.ReadCloser, error)
+	MoveData(ctx context.Context, userID int, data *entity.UserData) (int64, error)
+	ListFolders(ctx context.Context, userID int) ([]string, error)
+	IndexData(ctx context.Context, userID, dataID int, tokens []string) error
+	ListVersions(ctx context.Context, userID, dataID int) ([]*entity.DataVersion, int, error)
+	RestoreVersion(ctx context.Context, userID, dataID int, version int64) (int64, error)
+	ListTrash(ctx context.Context, userID, vaultID int) ([]*entity.UserData, error)
+	RestoreData(ctx context.Context, userID, dataID int) (int64, error)
+	PurgeData(ctx context.Context, userID, dataID int) error
 }
 
 type DataServer struct {
@@ -39,14 +60,36 @@ func (h *DataServer) AddData(ctx context.Context, req *datapb.AddDataRequest) (*
 		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
 	}
 
+	info, err := validatedInfo(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	tags, folder, err := validatedLabels(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := validatedSearchTokens(req.Data.SearchTokens, blindindex.MaxTokens)
+	if err != nil {
+		return nil, err
+	}
+
 	data := &entity.UserData{
-		InfoType: req.Data.InfoType,
-		Info:     req.Data.Info,
-		Meta:     req.Data.Meta,
+		InfoType:     req.Data.InfoType,
+		Info:         info,
+		Meta:         req.Data.Meta,
+		VaultID:      int(req.Data.VaultId),
+		Tags:         tags,
+		Folder:       folder,
+		Favorite:     req.Data.Favorite,
+		SearchTokens: tokens,
+		Created:      requestCreated(req.Data.Created),
 	}
 
 	id, err := h.dataService.AddData(ctx, userID, data)
 	if err != nil {
+		if st := accessStatus(err); st != nil {
+			return nil, st
+		}
 		return nil, status.Error(codes.Internal, "ошибка при добавлении данных")
 	}
 
@@ -64,15 +107,7 @@ func (h *DataServer) GetData(ctx context.Context, req *datapb.GetDataRequest) (*
 		return nil, status.Error(codes.NotFound, "данные не найдены")
 	}
 
-	return &datapb.GetDataResponse{
-		Data: &datapb.DataItem{
-			Id:       int32(data.ID),
-			InfoType: data.InfoType,
-			Info:     data.Info,
-			Meta:     data.Meta,
-			Created:  timestamppb.New(data.Created),
-		},
-	}, nil
+	return &datapb.GetDataResponse{Data: dataItemFromEntity(data)}, nil
 }
 
 func (h *DataServer) UpdateData(ctx context.Context, req *datapb.UpdateDataRequest) (*datapb.UpdateDataResponse, error) {
@@ -82,22 +117,40 @@ func (h *DataServer) UpdateData(ctx context.Context, req *datapb.UpdateDataReque
 		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
 	}
 
+	info, err := validatedInfo(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	tags, folder, err := validatedLabels(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := validatedSearchTokens(req.Data.SearchTokens, blindindex.MaxTokens)
+	if err != nil {
+		return nil, err
+	}
+
 	data := &entity.UserData{
-		ID:       int(req.Data.Id),
-		UserID:   userID,
-		InfoType: req.Data.InfoType,
-		Info:     req.Data.Info,
-		Meta:     req.Data.Meta,
-		Created:  req.Data.Created.AsTime(),
+		ID:           int(req.Data.Id),
+		UserID:       userID,
+		InfoType:     req.Data.InfoType,
+		Info:         info,
+		Meta:         req.Data.Meta,
+		Created:      req.Data.Created.AsTime(),
+		Version:      req.Data.Version,
+		VaultID:      int(req.Data.VaultId),
+		Tags:         tags,
+		Folder:       folder,
+		Favorite:     req.Data.Favorite,
+		SearchTokens: tokens,
 	}
 
-	err = h.dataService.UpdateData(ctx, userID, data)
+	version, err := h.dataService.UpdateData(ctx, userID, data)
 	if err != nil {
-		h.logger.LogInfo("Ошибка при обновлении данных", err)
-		return nil, status.Error(codes.Internal, "ошибка при обновлении данных")
+		return nil, h.writeError(err, req.Data.Id, "Ошибка при обновлении данных", "ошибка при обновлении данных")
 	}
 
-	return &datapb.UpdateDataResponse{}, nil
+	return &datapb.UpdateDataResponse{Version: version}, nil
 }
 
 func (h *DataServer) DeleteData(ctx context.Context, req *datapb.DeleteDataRequest) (*datapb.DeleteDataResponse, error) {
@@ -107,15 +160,338 @@ func (h *DataServer) DeleteData(ctx context.Context, req *datapb.DeleteDataReque
 		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
 	}
 
-	err = h.dataService.DeleteData(ctx, userID, int(req.Id))
+	err = h.dataService.DeleteData(ctx, userID, int(req.Id), req.Version)
 	if err != nil {
-		h.logger.LogInfo("Ошибка при удалении данных", err)
-		return nil, status.Error(codes.Internal, "ошибка при удалении данных")
+		return nil, h.writeError(err, req.Id, "Ошибка при удалении данных", "ошибка при удалении данных")
 	}
 
 	return &datapb.DeleteDataResponse{}, nil
 }
 
+func (h *DataServer) ListData(ctx context.Context, req *datapb.ListDataRequest) (*datapb.ListDataResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	if req.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "размер страницы не может быть отрицательным")
+	}
+
+	filter := &entity.DataFilter{
+		InfoType:      req.InfoType,
+		Cursor:        req.Cursor,
+		Limit:         int(req.PageSize),
+		Descending:    req.Descending,
+		VaultID:       int(req.VaultId),
+		Tag:           strings.TrimSpace(req.Tag),
+		FavoritesOnly: req.FavoritesOnly,
+	}
+	if req.Folder != "" {
+		folder, err := label.Folder(req.Folder)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		// Путь из одних разделителей, например "/", - записи вне папок.
+		filter.Folder = folder
+		if folder == "" {
+			filter.Folder = entity.RootFolder
+		}
+	}
+	if req.CreatedFrom != nil {
+		filter.CreatedFrom = req.CreatedFrom.AsTime()
+	}
+	if req.CreatedTo != nil {
+		filter.CreatedTo = req.CreatedTo.AsTime()
+	}
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return nil, status.Error(codes.InvalidArgument, "created_from должен быть раньше created_to")
+	}
+
+	items, nextCursor, err := h.dataService.ListData(ctx, userID, filter)
+	if err != nil {
+		if errors.Is(err, helper.ErrInvalidCursor) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if st := accessStatus(err); st != nil {
+			return nil, st
+		}
+		h.logger.LogInfo("Ошибка при получении списка данных", err)
+		return nil, status.Error(codes.Internal, "ошибка при получении списка данных")
+	}
+
+	return &datapb.ListDataResponse{Items: dataHeaders(items), NextCursor: nextCursor}, nil
+}
+
+// SearchData - записи, у которых есть все токены слепого индекса из запроса.
+// Токены считает клиент, сервер сравнивает их, не зная слов.
+func (h *DataServer) SearchData(
+	ctx context.Context, req *datapb.SearchDataRequest,
+) (*datapb.SearchDataResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	if len(req.Tokens) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "пустой поисковый запрос")
+	}
+	tokens, err := validatedSearchTokens(req.Tokens, blindindex.MaxQueryTokens)
+	if err != nil {
+		return nil, err
+	}
+	if req.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "размер страницы не может быть отрицательным")
+	}
+
+	filter := &entity.DataFilter{
+		Cursor:       req.Cursor,
+		Limit:        int(req.PageSize),
+		VaultID:      int(req.VaultId),
+		SearchTokens: tokens,
+	}
+
+	items, nextCursor, err := h.dataService.ListData(ctx, userID, filter)
+	if err != nil {
+		if errors.Is(err, helper.ErrInvalidCursor) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if st := accessStatus(err); st != nil {
+			return nil, st
+		}
+		h.logger.LogInfo("Ошибка при поиске данных", err)
+		return nil, status.Error(codes.Internal, "ошибка при поиске данных")
+	}
+
+	return &datapb.SearchDataResponse{Items: dataHeaders(items), NextCursor: nextCursor}, nil
+}
+
+// IndexData - заменяет токены слепого индекса записи без изменения её версии.
+func (h *DataServer) IndexData(ctx context.Context, req *datapb.IndexDataRequest) (*datapb.IndexDataResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	tokens, err := validatedSearchTokens(req.SearchTokens, blindindex.MaxTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.dataService.IndexData(ctx, userID, int(req.Id), tokens); err != nil {
+		return nil, h.writeError(err, req.Id, "Ошибка при индексации данных", "ошибка при индексации данных")
+	}
+
+	return &datapb.IndexDataResponse{}, nil
+}
+
+// ListVersions - сохранённые прошлые версии записи, от новых к старым.
+func (h *DataServer) ListVersions(
+	ctx context.Context, req *datapb.ListVersionsRequest,
+) (*datapb.ListVersionsResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	versions, vaultID, err := h.dataService.ListVersions(ctx, userID, int(req.Id))
+	if err != nil {
+		if st := accessStatus(err); st != nil {
+			return nil, st
+		}
+		h.logger.LogInfo("Ошибка при получении истории записи", err)
+		return nil, status.Error(codes.Internal, "ошибка при получении истории записи")
+	}
+
+	res := &datapb.ListVersionsResponse{
+		Versions: make([]*datapb.DataVersion, 0, len(versions)),
+		VaultId:  int32(vaultID),
+	}
+	for _, version := range versions {
+		res.Versions = append(res.Versions, &datapb.DataVersion{
+			Version:  version.Version,
+			InfoType: version.InfoType,
+			Info:     version.Info,
+			Meta:     version.Meta,
+			SavedAt:  timestamppb.New(version.SavedAt),
+		})
+	}
+
+	return res, nil
+}
+
+// RestoreVersion - делает версию из истории текущей. Текущая версия при этом
+// сама уходит в историю, поэтому восстановление можно отменить.
+func (h *DataServer) RestoreVersion(
+	ctx context.Context, req *datapb.RestoreVersionRequest,
+) (*datapb.RestoreVersionResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	version, err := h.dataService.RestoreVersion(ctx, userID, int(req.Id), req.Version)
+	if err != nil {
+		if errors.Is(err, helper.ErrVersionNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, h.writeError(err, req.Id, "Ошибка при восстановлении версии", "ошибка при восстановлении версии")
+	}
+
+	return &datapb.RestoreVersionResponse{Version: version}, nil
+}
+
+// ListTrash - записи из корзины личных данных или общего хранилища
+// с временем удаления, от недавно удалённых к давним.
+func (h *DataServer) ListTrash(ctx context.Context, req *datapb.ListTrashRequest) (*datapb.ListTrashResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	items, err := h.dataService.ListTrash(ctx, userID, int(req.VaultId))
+	if err != nil {
+		if st := accessStatus(err); st != nil {
+			return nil, st
+		}
+		h.logger.LogInfo("Ошибка при получении корзины", err)
+		return nil, status.Error(codes.Internal, "ошибка при получении корзины")
+	}
+
+	return &datapb.ListTrashResponse{Items: dataHeaders(items)}, nil
+}
+
+// RestoreData - возвращает запись из корзины с новой версией.
+func (h *DataServer) RestoreData(
+	ctx context.Context, req *datapb.RestoreDataRequest,
+) (*datapb.RestoreDataResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	version, err := h.dataService.RestoreData(ctx, userID, int(req.Id))
+	if err != nil {
+		return nil, h.writeError(err, req.Id, "Ошибка при восстановлении из корзины", "ошибка при восстановлении из корзины")
+	}
+
+	return &datapb.RestoreDataResponse{Version: version}, nil
+}
+
+// PurgeData - окончательно удаляет запись из корзины, не дожидаясь очистки по сроку.
+func (h *DataServer) PurgeData(ctx context.Context, req *datapb.PurgeDataRequest) (*datapb.PurgeDataResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	if err := h.dataService.PurgeData(ctx, userID, int(req.Id)); err != nil {
+		return nil, h.writeError(err, req.Id, "Ошибка при удалении из корзины", "ошибка при удалении из корзины")
+	}
+
+	return &datapb.PurgeDataResponse{}, nil
+}
+
+// ListFolders - папки, в которых есть личные записи пользователя.
+func (h *DataServer) ListFolders(
+	ctx context.Context, _ *datapb.ListFoldersRequest,
+) (*datapb.ListFoldersResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	folders, err := h.dataService.ListFolders(ctx, userID)
+	if err != nil {
+		h.logger.LogInfo("Ошибка при получении списка папок", err)
+		return nil, status.Error(codes.Internal, "ошибка при получении списка папок")
+	}
+
+	return &datapb.ListFoldersResponse{Folders: folders}, nil
+}
+
+// MoveData - переносит запись между личными данными и общими хранилищами.
+func (h *DataServer) MoveData(ctx context.Context, req *datapb.MoveDataRequest) (*datapb.MoveDataResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		h.logger.LogInfo("Не удалось получить userID из контекста", err)
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	info, err := validatedInfo(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := validatedSearchTokens(req.Data.SearchTokens, blindindex.MaxTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &entity.UserData{
+		ID:           int(req.Data.Id),
+		InfoType:     req.Data.InfoType,
+		Info:         info,
+		Meta:         req.Data.Meta,
+		Version:      req.Data.Version,
+		VaultID:      int(req.VaultId),
+		SearchTokens: tokens,
+	}
+
+	version, err := h.dataService.MoveData(ctx, userID, data)
+	if err != nil {
+		return nil, h.writeError(err, req.Data.Id, "Ошибка при переносе данных", "ошибка при переносе данных")
+	}
+
+	return &datapb.MoveDataResponse{Version: version}, nil
+}
+
+// writeError - переводит ошибку изменения записи в статус gRPC. При конфликте версий
+// в детали ошибки кладётся текущая версия, чтобы клиент мог предложить решение.
+func (h *DataServer) writeError(err error, id int32, logMsg, clientMsg string) error {
+	var conflict *helper.VersionConflictError
+	if errors.As(err, &conflict) {
+		st, detailErr := status.New(codes.Aborted, conflict.Error()).
+			WithDetails(&datapb.VersionConflict{Id: id, CurrentVersion: conflict.Current})
+		if detailErr != nil {
+			return status.Error(codes.Aborted, conflict.Error())
+		}
+		return st.Err()
+	}
+	if st := accessStatus(err); st != nil {
+		return st
+	}
+	if errors.Is(err, helper.ErrBlobNotMovable) || errors.Is(err, helper.ErrVaultMismatch) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	h.logger.LogInfo(logMsg, err)
+	return status.Error(codes.Internal, clientMsg)
+}
+
+// accessStatus - переводит отказ в доступе к записи или хранилищу в статус gRPC.
+// Для остальных ошибок возвращает nil.
+func accessStatus(err error) error {
+	switch {
+	case errors.Is(err, helper.ErrDataNotFound):
+		return status.Error(codes.NotFound, "данные не найдены")
+	case errors.Is(err, helper.ErrVaultNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, helper.ErrAccessDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return nil
+	}
+}
+
 func getUserIDFromContext(ctx context.Context) (int, error) {
 	userIDValue := ctx.Value(contextkey.UserIDKey)
 	if userIDValue == nil {
@@ -127,3 +503,81 @@ func getUserIDFromContext(ctx context.Context) (int, error) {
 	}
 	return userID, nil
 }
+
+// validatedLabels - приводит теги и папку записи к единому виду.
+func validatedLabels(item *datapb.DataItem) ([]string, string, error) {
+	tags, err := label.Tags(item.Tags)
+	if err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	folder, err := label.Folder(item.Folder)
+	if err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return tags, folder, nil
+}
+
+// validatedSearchTokens - проверяет, что токенов не больше limit и все они
+// похожи на токены слепого индекса.
+func validatedSearchTokens(tokens []string, limit int) ([]string, error) {
+	if err := blindindex.Validate(tokens, limit); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return tokens, nil
+}
+
+// dataHeaders - заголовки записей для ответов со списками.
+func dataHeaders(items []*entity.UserData) []*datapb.DataHeader {
+	headers := make([]*datapb.DataHeader, 0, len(items))
+	for _, item := range items {
+		header := &datapb.DataHeader{
+			Id:       int32(item.ID),
+			InfoType: item.InfoType,
+			Meta:     item.Meta,
+			Created:  timestamppb.New(item.Created),
+			Tags:     item.Tags,
+			Folder:   item.Folder,
+			Favorite: item.Favorite,
+		}
+		if !item.DeletedAt.IsZero() {
+			header.DeletedAt = timestamppb.New(item.DeletedAt)
+		}
+		headers = append(headers, header)
+	}
+
+	return headers
+}
+
+// requestCreated - время создания из запроса; нулевое, если клиент его не задал.
+func requestCreated(created *timestamppb.Timestamp) time.Time {
+	if created == nil {
+		return time.Time{}
+	}
+	return created.AsTime()
+}
+
+// validatedInfo - проверяет запись и возвращает значение для поля info.
+// Типизированный payload упаковывается в info; запись без payload
+// (зашифрованная клиентом) сохраняется как есть.
+func validatedInfo(item *datapb.DataItem) (string, error) {
+	if item == nil {
+		return "", status.Error(codes.InvalidArgument, "данные не переданы")
+	}
+
+	if err := payload.Validate(item); err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if item.Payload == nil {
+		return item.Info, nil
+	}
+
+	info, err := payload.Encode(item)
+	if err != nil {
+		return "", status.Error(codes.Internal, "ошибка упаковки данных")
+	}
+
+	return info, nil
+}
@@ -3,14 +3,24 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/internal/blindindex"
 	"github.com/NikolosHGW/goph-keeper/internal/contextkey"
 	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -21,8 +31,27 @@ func (l *mockLogger) LogInfo(message string, err error) {}
 type mockDataService struct {
 	AddDataFunc     func(ctx context.Context, userID int, data *entity.UserData) (int, error)
 	GetDataByIDFunc func(ctx context.Context, userID, dataID int) (*entity.UserData, error)
-	UpdateDataFunc  func(ctx context.Context, userID int, data *entity.UserData) error
-	DeleteDataFunc  func(ctx context.Context, userID, dataID int) error
+	UpdateDataFunc  func(ctx context.Context, userID int, data *entity.UserData) (int64, error)
+	DeleteDataFunc  func(ctx context.Context, userID, dataID int, version int64) error
+	ListDataFunc    func(ctx context.Context, userID int, filter *entity.DataFilter) ([]*entity.UserData, string, error)
+	GetChangesFunc  func(ctx context.Context, userID int, since int64, limit int) (*entity.DataChanges, error)
+	MoveDataFunc    func(ctx context.Context, userID int, data *entity.UserData) (int64, error)
+	ListFoldersFunc func(ctx context.Context, userID int) ([]string, error)
+	IndexDataFunc   func(ctx context.Context, userID, dataID int, tokens []string) error
+
+	ListVersionsFunc   func(ctx context.Context, userID, dataID int) ([]*entity.DataVersion, int, error)
+	RestoreVersionFunc func(ctx context.Context, userID, dataID int, version int64) (int64, error)
+
+	ListTrashFunc   func(ctx context.Context, userID, vaultID int) ([]*entity.UserData, error)
+	RestoreDataFunc func(ctx context.Context, userID, dataID int) (int64, error)
+	PurgeDataFunc   func(ctx context.Context, userID, dataID int) error
+
+	UploadBinaryFunc func(
+		ctx context.Context, userID int, data *entity.UserData, expected *entity.BinaryBlob, r io.Reader,
+	) (int, error)
+	DownloadBinaryFunc func(
+		ctx context.Context, userID, dataID int,
+	) (*entity.UserData, *entity.BinaryBlob, io.ReadCloser, error)
 }
 
 func (m *mockDataService) AddData(ctx context.Context, userID int, data *entity.UserData) (int, error) {
@@ -33,12 +62,62 @@ func (m *mockDataService) GetDataByID(ctx context.Context, userID, dataID int) (
 	return m.GetDataByIDFunc(ctx, userID, dataID)
 }
 
-func (m *mockDataService) UpdateData(ctx context.Context, userID int, data *entity.UserData) error {
+func (m *mockDataService) UpdateData(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
 	return m.UpdateDataFunc(ctx, userID, data)
 }
 
-func (m *mockDataService) DeleteData(ctx context.Context, userID, dataID int) error {
-	return m.DeleteDataFunc(ctx, userID, dataID)
+func (m *mockDataService) DeleteData(ctx context.Context, userID, dataID int, version int64) error {
+	return m.DeleteDataFunc(ctx, userID, dataID, version)
+}
+
+func (m *mockDataService) ListData(
+	ctx context.Context, userID int, filter *entity.DataFilter,
+) ([]*entity.UserData, string, error) {
+	return m.ListDataFunc(ctx, userID, filter)
+}
+
+func (m *mockDataService) UploadBinary(
+	ctx context.Context, userID int, data *entity.UserData, expected *entity.BinaryBlob, r io.Reader,
+) (int, error) {
+	return m.UploadBinaryFunc(ctx, userID, data, expected, r)
+}
+
+func (m *mockDataService) DownloadBinary(
+	ctx context.Context, userID, dataID int,
+) (*entity.UserData, *entity.BinaryBlob, io.ReadCloser, error) {
+	return m.DownloadBinaryFunc(ctx, userID, dataID)
+}
+
+func (m *mockDataService) MoveData(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
+	return m.MoveDataFunc(ctx, userID, data)
+}
+
+func (m *mockDataService) ListFolders(ctx context.Context, userID int) ([]string, error) {
+	return m.ListFoldersFunc(ctx, userID)
+}
+
+func (m *mockDataService) IndexData(ctx context.Context, userID, dataID int, tokens []string) error {
+	return m.IndexDataFunc(ctx, userID, dataID, tokens)
+}
+
+func (m *mockDataService) ListVersions(ctx context.Context, userID, dataID int) ([]*entity.DataVersion, int, error) {
+	return m.ListVersionsFunc(ctx, userID, dataID)
+}
+
+func (m *mockDataService) RestoreVersion(ctx context.Context, userID, dataID int, version int64) (int64, error) {
+	return m.RestoreVersionFunc(ctx, userID, dataID, version)
+}
+
+func (m *mockDataService) ListTrash(ctx context.Context, userID, vaultID int) ([]*entity.UserData, error) {
+	return m.ListTrashFunc(ctx, userID, vaultID)
+}
+
+func (m *mockDataService) RestoreData(ctx context.Context, userID, dataID int) (int64, error) {
+	return m.RestoreDataFunc(ctx, userID, dataID)
+}
+
+func (m *mockDataService) PurgeData(ctx context.Context, userID, dataID int) error {
+	return m.PurgeDataFunc(ctx, userID, dataID)
 }
 
 func contextWithUserID(userID int) context.Context {
@@ -64,7 +143,7 @@ func TestAddData(t *testing.T) {
 			ctx:  contextWithUserID(1),
 			request: &datapb.AddDataRequest{
 				Data: &datapb.DataItem{
-					InfoType: "password",
+					InfoType: "login_password",
 					Info:     "mypassword",
 					Meta:     "meta",
 				},
@@ -74,7 +153,7 @@ func TestAddData(t *testing.T) {
 					if userID != 1 {
 						t.Errorf("Expected userID 1, got %d", userID)
 					}
-					if data.InfoType != "password" || data.Info != "mypassword" || data.Meta != "meta" {
+					if data.InfoType != "login_password" || data.Info != "mypassword" || data.Meta != "meta" {
 						t.Errorf("Unexpected data: %+v", data)
 					}
 					return 123, nil
@@ -96,7 +175,7 @@ func TestAddData(t *testing.T) {
 			ctx:  contextWithUserID(1),
 			request: &datapb.AddDataRequest{
 				Data: &datapb.DataItem{
-					InfoType: "password",
+					InfoType: "login_password",
 					Info:     "mypassword",
 					Meta:     "meta",
 				},
@@ -125,6 +204,128 @@ func TestAddData(t *testing.T) {
 	}
 }
 
+func TestAddData_TypedPayload(t *testing.T) {
+	mockService := &mockDataService{}
+	server := NewDataServer(mockService, &mockLogger{})
+
+	var stored *entity.UserData
+	mockService.AddDataFunc = func(ctx context.Context, userID int, data *entity.UserData) (int, error) {
+		stored = data
+		return 7, nil
+	}
+
+	card := &datapb.BankCard{Number: "4111111111111111", Holder: "IVAN IVANOV", Expiry: "12/29", Cvv: "123"}
+	resp, err := server.AddData(contextWithUserID(1), &datapb.AddDataRequest{
+		Data: &datapb.DataItem{InfoType: "bank_card", Payload: &datapb.DataItem_BankCard{BankCard: card}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Id != 7 {
+		t.Errorf("Expected id 7, got %d", resp.Id)
+	}
+
+	mockService.GetDataByIDFunc = func(ctx context.Context, userID, dataID int) (*entity.UserData, error) {
+		return stored, nil
+	}
+	got, err := server.GetData(contextWithUserID(1), &datapb.GetDataRequest{Id: 7})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !proto.Equal(got.Data.GetBankCard(), card) || got.Data.Info != "" {
+		t.Errorf("Expected decoded bank card, got: %v", got.Data)
+	}
+}
+
+func TestAddData_Created(t *testing.T) {
+	mockService := &mockDataService{}
+	server := NewDataServer(mockService, &mockLogger{})
+	created := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	var stored []time.Time
+	mockService.AddDataFunc = func(ctx context.Context, userID int, data *entity.UserData) (int, error) {
+		stored = append(stored, data.Created)
+		return 1, nil
+	}
+
+	for _, item := range []*datapb.DataItem{
+		{InfoType: "text", Info: "импорт", Created: timestamppb.New(created)},
+		{InfoType: "text", Info: "новая"},
+	} {
+		if _, err := server.AddData(contextWithUserID(1), &datapb.AddDataRequest{Data: item}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(stored) != 2 || !stored[0].Equal(created) || !stored[1].IsZero() {
+		t.Errorf("Expected created %v and zero time, got: %v", created, stored)
+	}
+}
+
+func TestAddData_Labels(t *testing.T) {
+	mockService := &mockDataService{}
+	server := NewDataServer(mockService, &mockLogger{})
+
+	var stored *entity.UserData
+	mockService.AddDataFunc = func(ctx context.Context, userID int, data *entity.UserData) (int, error) {
+		stored = data
+		return 7, nil
+	}
+
+	_, err := server.AddData(contextWithUserID(1), &datapb.AddDataRequest{Data: &datapb.DataItem{
+		InfoType: "text", Info: "secret", Tags: []string{" work", "db", "work"}, Folder: "/work// db/", Favorite: true,
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(stored.Tags, []string{"db", "work"}) || stored.Folder != "work/db" || !stored.Favorite {
+		t.Errorf("Expected normalized labels, got: %+v", stored)
+	}
+
+	_, err = server.AddData(contextWithUserID(1), &datapb.AddDataRequest{Data: &datapb.DataItem{
+		InfoType: "text", Info: "secret", Tags: []string{"a\x00b"},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for invalid tag, got: %v", err)
+	}
+}
+
+func TestAddData_InvalidPayload(t *testing.T) {
+	mockService := &mockDataService{}
+	server := NewDataServer(mockService, &mockLogger{})
+	mockService.AddDataFunc = func(ctx context.Context, userID int, data *entity.UserData) (int, error) {
+		t.Errorf("AddData should not be called")
+		return 0, nil
+	}
+
+	tests := []struct {
+		name string
+		data *datapb.DataItem
+	}{
+		{name: "NoData", data: nil},
+		{name: "UnknownType", data: &datapb.DataItem{InfoType: "password", Info: "x"}},
+		{name: "BadLuhn", data: &datapb.DataItem{
+			InfoType: "bank_card",
+			Payload: &datapb.DataItem_BankCard{
+				BankCard: &datapb.BankCard{Number: "4111111111111112", Expiry: "12/29"},
+			},
+		}},
+		{name: "EmptyLogin", data: &datapb.DataItem{
+			InfoType: "login_password",
+			Payload:  &datapb.DataItem_LoginPassword{LoginPassword: &datapb.LoginPassword{Password: "p"}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.AddData(contextWithUserID(1), &datapb.AddDataRequest{Data: tt.data})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got: %v", err)
+			}
+		})
+	}
+}
+
 func TestGetData(t *testing.T) {
 	mockService := &mockDataService{}
 	mockLogger := &mockLogger{}
@@ -153,7 +354,7 @@ func TestGetData(t *testing.T) {
 					return &entity.UserData{
 						ID:       123,
 						UserID:   1,
-						InfoType: "password",
+						InfoType: "login_password",
 						Info:     "mypassword",
 						Meta:     "meta",
 						Created:  time.Now(),
@@ -163,7 +364,7 @@ func TestGetData(t *testing.T) {
 			expectedResp: &datapb.GetDataResponse{
 				Data: &datapb.DataItem{
 					Id:       123,
-					InfoType: "password",
+					InfoType: "login_password",
 					Info:     "mypassword",
 					Meta:     "meta",
 				},
@@ -239,21 +440,22 @@ func TestUpdateData(t *testing.T) {
 			request: &datapb.UpdateDataRequest{
 				Data: &datapb.DataItem{
 					Id:       123,
-					InfoType: "password",
+					InfoType: "login_password",
 					Info:     "newpassword",
 					Meta:     "newmeta",
 					Created:  timestamppb.New(time.Now()),
+					Version:  2,
 				},
 			},
 			setupMocks: func() {
-				mockService.UpdateDataFunc = func(ctx context.Context, userID int, data *entity.UserData) error {
-					if userID != 1 || data.ID != 123 || data.Info != "newpassword" {
+				mockService.UpdateDataFunc = func(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
+					if userID != 1 || data.ID != 123 || data.Info != "newpassword" || data.Version != 2 {
 						t.Errorf("Unexpected data in UpdateData")
 					}
-					return nil
+					return 3, nil
 				}
 			},
-			expectedResp:  &datapb.UpdateDataResponse{},
+			expectedResp:  &datapb.UpdateDataResponse{Version: 3},
 			expectedError: nil,
 		},
 		{
@@ -270,20 +472,34 @@ func TestUpdateData(t *testing.T) {
 			request: &datapb.UpdateDataRequest{
 				Data: &datapb.DataItem{
 					Id:       123,
-					InfoType: "password",
+					InfoType: "login_password",
 					Info:     "newpassword",
 					Meta:     "newmeta",
 					Created:  timestamppb.New(time.Now()),
 				},
 			},
 			setupMocks: func() {
-				mockService.UpdateDataFunc = func(ctx context.Context, userID int, data *entity.UserData) error {
-					return errors.New("update failed")
+				mockService.UpdateDataFunc = func(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
+					return 0, errors.New("update failed")
 				}
 			},
 			expectedResp:  nil,
 			expectedError: statusError(codes.Internal, "ошибка при обновлении данных"),
 		},
+		{
+			name: "NotFound",
+			ctx:  contextWithUserID(1),
+			request: &datapb.UpdateDataRequest{
+				Data: &datapb.DataItem{Id: 123, InfoType: "text", Info: "x", Version: 1},
+			},
+			setupMocks: func() {
+				mockService.UpdateDataFunc = func(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
+					return 0, helper.ErrDataNotFound
+				}
+			},
+			expectedResp:  nil,
+			expectedError: statusError(codes.NotFound, "данные не найдены"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -320,12 +536,13 @@ func TestDeleteData(t *testing.T) {
 			name: "Success",
 			ctx:  contextWithUserID(1),
 			request: &datapb.DeleteDataRequest{
-				Id: 123,
+				Id:      123,
+				Version: 4,
 			},
 			setupMocks: func() {
-				mockService.DeleteDataFunc = func(ctx context.Context, userID, dataID int) error {
-					if userID != 1 || dataID != 123 {
-						t.Errorf("Unexpected userID or dataID in DeleteData")
+				mockService.DeleteDataFunc = func(ctx context.Context, userID, dataID int, version int64) error {
+					if userID != 1 || dataID != 123 || version != 4 {
+						t.Errorf("Unexpected userID, dataID or version in DeleteData")
 					}
 					return nil
 				}
@@ -348,13 +565,37 @@ func TestDeleteData(t *testing.T) {
 				Id: 123,
 			},
 			setupMocks: func() {
-				mockService.DeleteDataFunc = func(ctx context.Context, userID, dataID int) error {
+				mockService.DeleteDataFunc = func(ctx context.Context, userID, dataID int, version int64) error {
 					return errors.New("delete failed")
 				}
 			},
 			expectedResp:  nil,
 			expectedError: statusError(codes.Internal, "ошибка при удалении данных"),
 		},
+		{
+			name:    "NotFound",
+			ctx:     contextWithUserID(1),
+			request: &datapb.DeleteDataRequest{Id: 123, Version: 1},
+			setupMocks: func() {
+				mockService.DeleteDataFunc = func(ctx context.Context, userID, dataID int, version int64) error {
+					return helper.ErrDataNotFound
+				}
+			},
+			expectedResp:  nil,
+			expectedError: statusError(codes.NotFound, "данные не найдены"),
+		},
+		{
+			name:    "ReadOnlyMember",
+			ctx:     contextWithUserID(1),
+			request: &datapb.DeleteDataRequest{Id: 123, Version: 1},
+			setupMocks: func() {
+				mockService.DeleteDataFunc = func(ctx context.Context, userID, dataID int, version int64) error {
+					return helper.ErrAccessDenied
+				}
+			},
+			expectedResp:  nil,
+			expectedError: statusError(codes.PermissionDenied, helper.ErrAccessDenied.Error()),
+		},
 	}
 
 	for _, tt := range tests {
@@ -373,6 +614,127 @@ func TestDeleteData(t *testing.T) {
 	}
 }
 
+func TestListData(t *testing.T) {
+	mockService := &mockDataService{}
+	mockLogger := &mockLogger{}
+
+	server := NewDataServer(mockService, mockLogger)
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		request       *datapb.ListDataRequest
+		setupMocks    func()
+		expectedResp  *datapb.ListDataResponse
+		expectedError error
+	}{
+		{
+			name: "Success",
+			ctx:  contextWithUserID(1),
+			request: &datapb.ListDataRequest{
+				InfoType:    "text",
+				CreatedFrom: timestamppb.New(created.Add(-time.Hour)),
+				PageSize:    10,
+				Cursor:      "cursor",
+			},
+			setupMocks: func() {
+				mockService.ListDataFunc = func(
+					ctx context.Context, userID int, filter *entity.DataFilter,
+				) ([]*entity.UserData, string, error) {
+					if userID != 1 || filter.InfoType != "text" || filter.Limit != 10 || filter.Cursor != "cursor" {
+						t.Errorf("Unexpected filter: %+v", filter)
+					}
+					if !filter.CreatedFrom.Equal(created.Add(-time.Hour)) || !filter.CreatedTo.IsZero() {
+						t.Errorf("Unexpected created range: %+v", filter)
+					}
+					return []*entity.UserData{
+						{ID: 7, InfoType: "text", Meta: "meta", Created: created},
+					}, "next", nil
+				}
+			},
+			expectedResp: &datapb.ListDataResponse{
+				Items: []*datapb.DataHeader{
+					{Id: 7, InfoType: "text", Meta: "meta", Created: timestamppb.New(created)},
+				},
+				NextCursor: "next",
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "NoUserID",
+			ctx:           context.Background(),
+			request:       &datapb.ListDataRequest{},
+			setupMocks:    func() {},
+			expectedResp:  nil,
+			expectedError: statusError(codes.Internal, "не удалось получить userID из контекста"),
+		},
+		{
+			name:          "NegativePageSize",
+			ctx:           contextWithUserID(1),
+			request:       &datapb.ListDataRequest{PageSize: -1},
+			setupMocks:    func() {},
+			expectedResp:  nil,
+			expectedError: statusError(codes.InvalidArgument, "размер страницы не может быть отрицательным"),
+		},
+		{
+			name: "InvalidCreatedRange",
+			ctx:  contextWithUserID(1),
+			request: &datapb.ListDataRequest{
+				CreatedFrom: timestamppb.New(created),
+				CreatedTo:   timestamppb.New(created.Add(-time.Hour)),
+			},
+			setupMocks:    func() {},
+			expectedResp:  nil,
+			expectedError: statusError(codes.InvalidArgument, "created_from должен быть раньше created_to"),
+		},
+		{
+			name:    "InvalidCursor",
+			ctx:     contextWithUserID(1),
+			request: &datapb.ListDataRequest{Cursor: "bad"},
+			setupMocks: func() {
+				mockService.ListDataFunc = func(
+					ctx context.Context, userID int, filter *entity.DataFilter,
+				) ([]*entity.UserData, string, error) {
+					return nil, "", helper.ErrInvalidCursor
+				}
+			},
+			expectedResp:  nil,
+			expectedError: statusError(codes.InvalidArgument, helper.ErrInvalidCursor.Error()),
+		},
+		{
+			name:    "DataServiceError",
+			ctx:     contextWithUserID(1),
+			request: &datapb.ListDataRequest{},
+			setupMocks: func() {
+				mockService.ListDataFunc = func(
+					ctx context.Context, userID int, filter *entity.DataFilter,
+				) ([]*entity.UserData, string, error) {
+					return nil, "", errors.New("database error")
+				}
+			},
+			expectedResp:  nil,
+			expectedError: statusError(codes.Internal, "ошибка при получении списка данных"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+			resp, err := server.ListData(tt.ctx, tt.request)
+
+			if !compareErrors(err, tt.expectedError) {
+				t.Errorf("Expected error: %v, got: %v", tt.expectedError, err)
+			}
+
+			if !proto.Equal(resp, tt.expectedResp) {
+				t.Errorf("Expected response: %v, got: %v", tt.expectedResp, resp)
+			}
+		})
+	}
+}
+
 func compareErrors(got, want error) bool {
 	if got == nil && want == nil {
 		return true
@@ -404,7 +766,7 @@ func compareUpdateDataResponse(got, want *datapb.UpdateDataResponse) bool {
 	if got == nil || want == nil {
 		return false
 	}
-	return true
+	return got.Version == want.Version
 }
 
 func compareDeleteDataResponse(got, want *datapb.DeleteDataResponse) bool {
@@ -416,3 +778,446 @@ func compareDeleteDataResponse(got, want *datapb.DeleteDataResponse) bool {
 	}
 	return true
 }
+
+func TestListData_Labels(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *datapb.ListDataRequest
+		expected entity.DataFilter
+	}{
+		{
+			name:     "тег и избранное",
+			request:  &datapb.ListDataRequest{Tag: " work ", FavoritesOnly: true},
+			expected: entity.DataFilter{Tag: "work", FavoritesOnly: true},
+		},
+		{
+			name:     "папка",
+			request:  &datapb.ListDataRequest{Folder: "work/ db/"},
+			expected: entity.DataFilter{Folder: "work/db"},
+		},
+		{
+			name:     "вне папок",
+			request:  &datapb.ListDataRequest{Folder: "/"},
+			expected: entity.DataFilter{Folder: entity.RootFolder},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{}
+			var got *entity.DataFilter
+			mockService.ListDataFunc = func(
+				ctx context.Context, userID int, filter *entity.DataFilter,
+			) ([]*entity.UserData, string, error) {
+				got = filter
+				return []*entity.UserData{{ID: 7, Tags: []string{"work"}, Folder: "work/db", Favorite: true}}, "", nil
+			}
+
+			resp, err := NewDataServer(mockService, &mockLogger{}).ListData(contextWithUserID(1), tt.request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got.Tag != tt.expected.Tag || got.Folder != tt.expected.Folder ||
+				got.FavoritesOnly != tt.expected.FavoritesOnly {
+				t.Errorf("Expected filter %+v, got %+v", tt.expected, got)
+			}
+			header := resp.Items[0]
+			if header.Folder != "work/db" || !header.Favorite || !reflect.DeepEqual(header.Tags, []string{"work"}) {
+				t.Errorf("Expected labels in header, got: %v", header)
+			}
+		})
+	}
+}
+
+func TestListFolders(t *testing.T) {
+	mockService := &mockDataService{}
+	mockService.ListFoldersFunc = func(ctx context.Context, userID int) ([]string, error) {
+		if userID != 1 {
+			t.Errorf("Unexpected userID: %d", userID)
+		}
+		return []string{"home", "work/db"}, nil
+	}
+
+	resp, err := NewDataServer(mockService, &mockLogger{}).ListFolders(contextWithUserID(1), &datapb.ListFoldersRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Folders, []string{"home", "work/db"}) {
+		t.Errorf("Unexpected folders: %v", resp.Folders)
+	}
+}
+
+func TestSearchData(t *testing.T) {
+	token := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name         string
+		request      *datapb.SearchDataRequest
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{
+			name:         "найдены записи",
+			request:      &datapb.SearchDataRequest{Tokens: []string{token}, VaultId: 7, PageSize: 10, Cursor: "c"},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "пустой запрос",
+			request:      &datapb.SearchDataRequest{},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "не токен",
+			request:      &datapb.SearchDataRequest{Tokens: []string{"github"}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "слишком много слов",
+			request:      &datapb.SearchDataRequest{Tokens: slices.Repeat([]string{token}, blindindex.MaxQueryTokens+1)},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "чужое хранилище",
+			request:      &datapb.SearchDataRequest{Tokens: []string{token}, VaultId: 9},
+			serviceErr:   helper.ErrVaultNotFound,
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				ListDataFunc: func(
+					ctx context.Context, userID int, filter *entity.DataFilter,
+				) ([]*entity.UserData, string, error) {
+					assert.Equal(t, tt.request.Tokens, filter.SearchTokens)
+					assert.Equal(t, int(tt.request.VaultId), filter.VaultID)
+					assert.Equal(t, tt.request.Cursor, filter.Cursor)
+					if tt.serviceErr != nil {
+						return nil, "", tt.serviceErr
+					}
+					return []*entity.UserData{{ID: 3, InfoType: "text", Meta: "github", Tags: []string{"dev"}}}, "next", nil
+				},
+			}
+
+			resp, err := NewDataServer(mockService, &mockLogger{}).SearchData(contextWithUserID(1), tt.request)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				require.Len(t, resp.Items, 1)
+				assert.Equal(t, int32(3), resp.Items[0].Id)
+				assert.Equal(t, []string{"dev"}, resp.Items[0].Tags)
+				assert.Equal(t, "next", resp.NextCursor)
+			}
+		})
+	}
+}
+
+func TestIndexData(t *testing.T) {
+	token := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name         string
+		tokens       []string
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{name: "индекс обновлён", tokens: []string{token}, expectedCode: codes.OK},
+		{name: "индекс очищен", expectedCode: codes.OK},
+		{name: "не токен", tokens: []string{"github"}, expectedCode: codes.InvalidArgument},
+		{name: "нет прав", tokens: []string{token}, serviceErr: helper.ErrAccessDenied, expectedCode: codes.PermissionDenied},
+		{name: "записи нет", tokens: []string{token}, serviceErr: helper.ErrDataNotFound, expectedCode: codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				IndexDataFunc: func(ctx context.Context, userID, dataID int, tokens []string) error {
+					assert.Equal(t, 5, dataID)
+					assert.Equal(t, tt.tokens, tokens)
+					return tt.serviceErr
+				},
+			}
+
+			_, err := NewDataServer(mockService, &mockLogger{}).
+				IndexData(contextWithUserID(1), &datapb.IndexDataRequest{Id: 5, SearchTokens: tt.tokens})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	savedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		versions     []*entity.DataVersion
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{
+			name:         "история записи",
+			versions:     []*entity.DataVersion{{Version: 2, InfoType: "text", Info: "old", Meta: "m", SavedAt: savedAt}},
+			expectedCode: codes.OK,
+		},
+		{name: "истории нет", versions: []*entity.DataVersion{}, expectedCode: codes.OK},
+		{name: "нет прав", serviceErr: helper.ErrAccessDenied, expectedCode: codes.PermissionDenied},
+		{name: "записи нет", serviceErr: helper.ErrDataNotFound, expectedCode: codes.NotFound},
+		{name: "ошибка базы", serviceErr: errors.New("db error"), expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				ListVersionsFunc: func(ctx context.Context, userID, dataID int) ([]*entity.DataVersion, int, error) {
+					assert.Equal(t, 5, dataID)
+					return tt.versions, 7, tt.serviceErr
+				},
+			}
+
+			res, err := NewDataServer(mockService, &mockLogger{}).
+				ListVersions(contextWithUserID(1), &datapb.ListVersionsRequest{Id: 5})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode != codes.OK {
+				return
+			}
+			assert.Equal(t, int32(7), res.VaultId)
+			require.Len(t, res.Versions, len(tt.versions))
+			for i, version := range tt.versions {
+				assert.Equal(t, version.Version, res.Versions[i].Version)
+				assert.Equal(t, version.Info, res.Versions[i].Info)
+				assert.Equal(t, version.Meta, res.Versions[i].Meta)
+				assert.Equal(t, version.SavedAt, res.Versions[i].SavedAt.AsTime())
+			}
+		})
+	}
+}
+
+func TestRestoreVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{name: "версия восстановлена", expectedCode: codes.OK},
+		{name: "версии нет", serviceErr: fmt.Errorf("обёртка: %w", helper.ErrVersionNotFound), expectedCode: codes.NotFound},
+		{name: "нет прав", serviceErr: helper.ErrAccessDenied, expectedCode: codes.PermissionDenied},
+		{name: "ошибка базы", serviceErr: errors.New("db error"), expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				RestoreVersionFunc: func(ctx context.Context, userID, dataID int, version int64) (int64, error) {
+					assert.Equal(t, 5, dataID)
+					assert.Equal(t, int64(2), version)
+					if tt.serviceErr != nil {
+						return 0, tt.serviceErr
+					}
+					return 4, nil
+				},
+			}
+
+			res, err := NewDataServer(mockService, &mockLogger{}).
+				RestoreVersion(contextWithUserID(1), &datapb.RestoreVersionRequest{Id: 5, Version: 2})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.Equal(t, int64(4), res.Version)
+			}
+		})
+	}
+}
+
+func TestListTrash(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deleted := created.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{name: "корзина", expectedCode: codes.OK},
+		{name: "не участник хранилища", serviceErr: helper.ErrVaultNotFound, expectedCode: codes.NotFound},
+		{name: "ошибка базы", serviceErr: errors.New("db error"), expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				ListTrashFunc: func(ctx context.Context, userID, vaultID int) ([]*entity.UserData, error) {
+					assert.Equal(t, 7, vaultID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return []*entity.UserData{{ID: 5, InfoType: "text", Meta: "почта", Created: created, DeletedAt: deleted}}, nil
+				},
+			}
+
+			res, err := NewDataServer(mockService, &mockLogger{}).
+				ListTrash(contextWithUserID(1), &datapb.ListTrashRequest{VaultId: 7})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				require.Len(t, res.Items, 1)
+				assert.Equal(t, int32(5), res.Items[0].Id)
+				assert.Equal(t, deleted, res.Items[0].DeletedAt.AsTime())
+			}
+		})
+	}
+}
+
+func TestRestoreData(t *testing.T) {
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{name: "запись восстановлена", expectedCode: codes.OK},
+		{name: "записи нет в корзине", serviceErr: helper.ErrDataNotFound, expectedCode: codes.NotFound},
+		{name: "нет прав", serviceErr: helper.ErrAccessDenied, expectedCode: codes.PermissionDenied},
+		{name: "ошибка базы", serviceErr: errors.New("db error"), expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				RestoreDataFunc: func(ctx context.Context, userID, dataID int) (int64, error) {
+					assert.Equal(t, 5, dataID)
+					if tt.serviceErr != nil {
+						return 0, tt.serviceErr
+					}
+					return 3, nil
+				},
+			}
+
+			res, err := NewDataServer(mockService, &mockLogger{}).
+				RestoreData(contextWithUserID(1), &datapb.RestoreDataRequest{Id: 5})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.Equal(t, int64(3), res.Version)
+			}
+		})
+	}
+}
+
+func TestPurgeData(t *testing.T) {
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{name: "запись удалена", expectedCode: codes.OK},
+		{name: "записи нет в корзине", serviceErr: helper.ErrDataNotFound, expectedCode: codes.NotFound},
+		{name: "ошибка базы", serviceErr: errors.New("db error"), expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				PurgeDataFunc: func(ctx context.Context, userID, dataID int) error {
+					assert.Equal(t, 5, dataID)
+					return tt.serviceErr
+				},
+			}
+
+			_, err := NewDataServer(mockService, &mockLogger{}).
+				PurgeData(contextWithUserID(1), &datapb.PurgeDataRequest{Id: 5})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestWriteData_SearchTokens(t *testing.T) {
+	token := strings.Repeat("ab", 32)
+	var saved []string
+	mockService := &mockDataService{
+		AddDataFunc: func(ctx context.Context, userID int, data *entity.UserData) (int, error) {
+			saved = data.SearchTokens
+			return 1, nil
+		},
+	}
+	server := NewDataServer(mockService, &mockLogger{})
+
+	_, err := server.AddData(contextWithUserID(1), &datapb.AddDataRequest{
+		Data: &datapb.DataItem{InfoType: "text", Info: "x", SearchTokens: []string{token}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{token}, saved)
+
+	_, err = server.AddData(contextWithUserID(1), &datapb.AddDataRequest{
+		Data: &datapb.DataItem{InfoType: "text", Info: "x", SearchTokens: []string{"github"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWriteData_VersionConflict(t *testing.T) {
+	mockService := &mockDataService{
+		UpdateDataFunc: func(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
+			return 0, &helper.VersionConflictError{Current: 7}
+		},
+		DeleteDataFunc: func(ctx context.Context, userID, dataID int, version int64) error {
+			return fmt.Errorf("обёртка: %w", &helper.VersionConflictError{Current: 7})
+		},
+	}
+	server := NewDataServer(mockService, &mockLogger{})
+
+	_, updateErr := server.UpdateData(contextWithUserID(1), &datapb.UpdateDataRequest{
+		Data: &datapb.DataItem{Id: 5, InfoType: "text", Info: "x", Version: 6},
+	})
+	_, deleteErr := server.DeleteData(contextWithUserID(1), &datapb.DeleteDataRequest{Id: 5, Version: 6})
+
+	for _, err := range []error{updateErr, deleteErr} {
+		st := status.Convert(err)
+		assert.Equal(t, codes.Aborted, st.Code())
+		require.Len(t, st.Details(), 1)
+		conflict, ok := st.Details()[0].(*datapb.VersionConflict)
+		require.True(t, ok)
+		assert.Equal(t, int32(5), conflict.Id)
+		assert.Equal(t, int64(7), conflict.CurrentVersion)
+	}
+}
+
+func TestMoveData(t *testing.T) {
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{name: "запись перенесена", expectedCode: codes.OK},
+		{name: "нет прав", serviceErr: helper.ErrAccessDenied, expectedCode: codes.PermissionDenied},
+		{name: "чужое хранилище", serviceErr: helper.ErrVaultNotFound, expectedCode: codes.NotFound},
+		{name: "файл из потока", serviceErr: helper.ErrBlobNotMovable, expectedCode: codes.FailedPrecondition},
+		{name: "конфликт версий", serviceErr: &helper.VersionConflictError{Current: 3}, expectedCode: codes.Aborted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDataService{
+				MoveDataFunc: func(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
+					assert.Equal(t, 1, userID)
+					assert.Equal(t, &entity.UserData{
+						ID: 5, InfoType: "text", Info: "sealed", Meta: "meta", Version: 2, VaultID: 7,
+					}, data)
+					return 3, tt.serviceErr
+				},
+			}
+			server := NewDataServer(mockService, &mockLogger{})
+
+			resp, err := server.MoveData(contextWithUserID(1), &datapb.MoveDataRequest{
+				Data:    &datapb.DataItem{Id: 5, InfoType: "text", Info: "sealed", Meta: "meta", Version: 2},
+				VaultId: 7,
+			})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.Equal(t, int64(3), resp.Version)
+			}
+		})
+	}
+}
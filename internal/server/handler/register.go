@@ -4,17 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"unicode/utf8"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/NikolosHGW/goph-keeper/api/registerpb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
 )
 
-const maxPasswordLength = 72
+const (
+	maxPasswordLength = 72
+	// maxLoginLength - длина столбцов login в users и login_attempts.
+	maxLoginLength = 50
+)
 
 type register interface {
-	Handle(context.Context, *pb.RegisterUserRequest) (string, error)
+	Handle(context.Context, *pb.RegisterUserRequest) (*entity.AuthResult, error)
 }
 
 // RegisterServer - структура gRPC сервера для регистрации пользователя.
@@ -39,13 +46,21 @@ func (s *RegisterServer) RegisterUser(
 		return nil, status.Errorf(codes.InvalidArgument, "неправильный запрос: %v", err)
 	}
 
-	token, err := s.registerUseCase.Handle(ctx, req)
+	result, err := s.registerUseCase.Handle(ctx, req)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "ошибка при регистрации пользователя: %v", err)
 	}
 
 	return &pb.RegisterUserResponse{
-		BearerToken: token,
+		BearerToken: result.Token,
+		Kdf: &pb.KdfParams{
+			Salt:    result.KDF.Salt,
+			Time:    result.KDF.Time,
+			Memory:  result.KDF.Memory,
+			Threads: result.KDF.Threads,
+		},
+		RefreshToken:    result.RefreshToken,
+		AccessExpiresAt: timestamppb.New(result.AccessExpiresAt),
 	}, nil
 }
 
@@ -53,6 +68,9 @@ func validateLoginPasswordRequest(login, password string) error {
 	if login == "" || password == "" {
 		return errors.New("пустые логин и/или пароль")
 	}
+	if utf8.RuneCountInString(login) > maxLoginLength {
+		return fmt.Errorf("логин не может быть длиннее чем %d символов", maxLoginLength)
+	}
 	if len([]byte(password)) > maxPasswordLength {
 		return fmt.Errorf("пароль не может быть длиннее чем %d символов", maxPasswordLength)
 	}
@@ -3,19 +3,21 @@ package handler
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	pb "github.com/NikolosHGW/goph-keeper/api/registerpb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type registerUseCaseMock struct {
-	handleFunc func(ctx context.Context, req *pb.RegisterUserRequest) (string, error)
+	handleFunc func(ctx context.Context, req *pb.RegisterUserRequest) (*entity.AuthResult, error)
 }
 
-func (m *registerUseCaseMock) Handle(ctx context.Context, req *pb.RegisterUserRequest) (string, error) {
+func (m *registerUseCaseMock) Handle(ctx context.Context, req *pb.RegisterUserRequest) (*entity.AuthResult, error) {
 	return m.handleFunc(ctx, req)
 }
 
@@ -37,8 +39,11 @@ func TestRegisterServer_RegisterUser(t *testing.T) {
 			},
 			setupMock: func() *registerUseCaseMock {
 				return &registerUseCaseMock{
-					handleFunc: func(ctx context.Context, req *pb.RegisterUserRequest) (string, error) {
-						return "testtoken", nil
+					handleFunc: func(ctx context.Context, req *pb.RegisterUserRequest) (*entity.AuthResult, error) {
+						return &entity.AuthResult{
+							Token: "testtoken",
+							KDF:   entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
+						}, nil
 					},
 				}
 			},
@@ -62,8 +67,8 @@ func TestRegisterServer_RegisterUser(t *testing.T) {
 			},
 			setupMock: func() *registerUseCaseMock {
 				return &registerUseCaseMock{
-					handleFunc: func(ctx context.Context, req *pb.RegisterUserRequest) (string, error) {
-						return "", errors.New("some error")
+					handleFunc: func(ctx context.Context, req *pb.RegisterUserRequest) (*entity.AuthResult, error) {
+						return nil, errors.New("some error")
 					},
 				}
 			},
@@ -91,6 +96,8 @@ func TestRegisterServer_RegisterUser(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedToken, resp.BearerToken)
+				assert.Equal(t, []byte("salt"), resp.Kdf.Salt)
+				assert.Equal(t, uint32(65536), resp.Kdf.Memory)
 			}
 		})
 	}
@@ -126,6 +133,21 @@ func TestValidateRegisterUserRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Слишком длинный логин",
+			req: &pb.RegisterUserRequest{
+				Login:    strings.Repeat("л", maxLoginLength+1),
+				Password: "password",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Логин предельной длины",
+			req: &pb.RegisterUserRequest{
+				Login:    strings.Repeat("л", maxLoginLength),
+				Password: "password",
+			},
+		},
 		{
 			name: "Слишком длинный пароль",
 			req: &pb.RegisterUserRequest{
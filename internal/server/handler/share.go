@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/sharepb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type shareManager interface {
+	CreateShare(
+		ctx context.Context, userID, dataID int, payload string, ttl time.Duration, maxViews int,
+	) (string, time.Time, error)
+	RedeemShare(ctx context.Context, id string) (*entity.Share, error)
+}
+
+type ShareServer struct {
+	sharepb.UnimplementedShareServiceServer
+	shareService shareManager
+	logger       logger.CustomLogger
+}
+
+func NewShareServer(shareService shareManager, logger logger.CustomLogger) *ShareServer {
+	return &ShareServer{shareService: shareService, logger: logger}
+}
+
+func (h *ShareServer) CreateShare(
+	ctx context.Context, req *sharepb.CreateShareRequest,
+) (*sharepb.CreateShareResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	id, expiresAt, err := h.shareService.CreateShare(
+		ctx, userID, int(req.DataId), req.Payload, time.Duration(req.TtlSeconds)*time.Second, int(req.MaxViews),
+	)
+	if err != nil {
+		return nil, h.shareError(err, "не удалось создать ссылку")
+	}
+
+	return &sharepb.CreateShareResponse{ShareId: id, ExpiresAt: timestamppb.New(expiresAt)}, nil
+}
+
+// RedeemShare - вызывается без входа, поэтому на любую неудачу, кроме
+// внутренней ошибки, отвечает одинаково: ссылки нет.
+func (h *ShareServer) RedeemShare(
+	ctx context.Context, req *sharepb.RedeemShareRequest,
+) (*sharepb.RedeemShareResponse, error) {
+	share, err := h.shareService.RedeemShare(ctx, req.ShareId)
+	if err != nil {
+		return nil, h.shareError(err, "не удалось открыть ссылку")
+	}
+
+	return &sharepb.RedeemShareResponse{
+		Payload:   share.Payload,
+		ViewsLeft: int32(share.ViewsLeft),
+		ExpiresAt: timestamppb.New(share.ExpiresAt),
+	}, nil
+}
+
+// shareError - переводит ошибку сервиса ссылок в статус gRPC.
+func (h *ShareServer) shareError(err error, internalMessage string) error {
+	if st := accessStatus(err); st != nil {
+		return st
+	}
+
+	switch {
+	case errors.Is(err, helper.ErrShareNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, helper.ErrInvalidShare):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		h.logger.LogInfo(internalMessage, err)
+		return status.Error(codes.Internal, internalMessage)
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/sharepb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockShareManager struct {
+	mock.Mock
+}
+
+func (m *MockShareManager) CreateShare(
+	ctx context.Context, userID, dataID int, payload string, ttl time.Duration, maxViews int,
+) (string, time.Time, error) {
+	args := m.Called(ctx, userID, dataID, payload, ttl, maxViews)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockShareManager) RedeemShare(ctx context.Context, id string) (*entity.Share, error) {
+	args := m.Called(ctx, id)
+	share, _ := args.Get(0).(*entity.Share)
+	return share, args.Error(1)
+}
+
+func TestShareServer_CreateShare(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{name: "ссылка создана", expectedCode: codes.OK},
+		{name: "чужая запись", serviceErr: helper.ErrDataNotFound, expectedCode: codes.NotFound},
+		{name: "некорректный срок", serviceErr: helper.ErrInvalidShare, expectedCode: codes.InvalidArgument},
+		{name: "ошибка базы", serviceErr: errors.New("db down"), expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := contextWithUserID(1)
+			manager := new(MockShareManager)
+			manager.On("CreateShare", ctx, 1, 5, "sealed", time.Hour, 3).Return("share-id", expiresAt, tt.serviceErr)
+
+			res, err := NewShareServer(manager, &mockLogger{}).CreateShare(ctx, &sharepb.CreateShareRequest{
+				DataId: 5, Payload: "sealed", TtlSeconds: 3600, MaxViews: 3,
+			})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				require.NoError(t, err)
+				assert.Equal(t, "share-id", res.ShareId)
+				assert.Equal(t, timestamppb.New(expiresAt), res.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestShareServer_RedeemShare(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager := new(MockShareManager)
+	manager.On("RedeemShare", ctx, "share-id").
+		Return(&entity.Share{Payload: "sealed", ViewsLeft: 2, ExpiresAt: expiresAt}, nil)
+	manager.On("RedeemShare", ctx, "used").Return(nil, helper.ErrShareNotFound)
+	server := NewShareServer(manager, &mockLogger{})
+
+	res, err := server.RedeemShare(ctx, &sharepb.RedeemShareRequest{ShareId: "share-id"})
+	require.NoError(t, err)
+	assert.Equal(t, "sealed", res.Payload)
+	assert.Equal(t, int32(2), res.ViewsLeft)
+
+	_, err = server.RedeemShare(ctx, &sharepb.RedeemShareRequest{ShareId: "used"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type vaultManager interface {
+	SetKeyPair(ctx context.Context, userID int, pair *entity.KeyPair) error
+	KeyPair(ctx context.Context, userID int) (*entity.KeyPair, error)
+	PublicKey(ctx context.Context, login string) (string, error)
+	CreateVault(ctx context.Context, userID int, name, wrappedKey string) (int, error)
+	ListVaults(ctx context.Context, userID int) ([]*entity.Vault, error)
+	AddMember(ctx context.Context, userID, vaultID int, login, role, wrappedKey string) error
+	RemoveMember(ctx context.Context, userID, vaultID int, login string) error
+	ListMembers(ctx context.Context, userID, vaultID int) ([]*entity.VaultMember, error)
+}
+
+type VaultServer struct {
+	vaultpb.UnimplementedVaultServiceServer
+	vaultService vaultManager
+	logger       logger.CustomLogger
+}
+
+func NewVaultServer(vaultService vaultManager, logger logger.CustomLogger) *VaultServer {
+	return &VaultServer{vaultService: vaultService, logger: logger}
+}
+
+func (h *VaultServer) SetKeyPair(
+	ctx context.Context, req *vaultpb.SetKeyPairRequest,
+) (*vaultpb.SetKeyPairResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	pair := req.GetKeyPair()
+	if pair.GetPublicKey() == "" || pair.GetSealedPrivateKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "ключевая пара не передана")
+	}
+
+	err = h.vaultService.SetKeyPair(ctx, userID, &entity.KeyPair{
+		PublicKey:        pair.PublicKey,
+		SealedPrivateKey: pair.SealedPrivateKey,
+	})
+	if err != nil {
+		return nil, h.vaultError(err, "не удалось сохранить ключевую пару")
+	}
+
+	return &vaultpb.SetKeyPairResponse{}, nil
+}
+
+func (h *VaultServer) GetKeyPair(
+	ctx context.Context, _ *vaultpb.GetKeyPairRequest,
+) (*vaultpb.GetKeyPairResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	pair, err := h.vaultService.KeyPair(ctx, userID)
+	if err != nil {
+		return nil, h.vaultError(err, "не удалось получить ключевую пару")
+	}
+
+	return &vaultpb.GetKeyPairResponse{KeyPair: &vaultpb.KeyPair{
+		PublicKey:        pair.PublicKey,
+		SealedPrivateKey: pair.SealedPrivateKey,
+	}}, nil
+}
+
+func (h *VaultServer) GetPublicKey(
+	ctx context.Context, req *vaultpb.GetPublicKeyRequest,
+) (*vaultpb.GetPublicKeyResponse, error) {
+	if req.Login == "" {
+		return nil, status.Error(codes.InvalidArgument, "логин не передан")
+	}
+
+	publicKey, err := h.vaultService.PublicKey(ctx, req.Login)
+	if err != nil {
+		return nil, h.vaultError(err, "не удалось получить открытый ключ")
+	}
+
+	return &vaultpb.GetPublicKeyResponse{PublicKey: publicKey}, nil
+}
+
+func (h *VaultServer) CreateVault(
+	ctx context.Context, req *vaultpb.CreateVaultRequest,
+) (*vaultpb.CreateVaultResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || req.WrappedKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "имя хранилища и ключ обязательны")
+	}
+
+	id, err := h.vaultService.CreateVault(ctx, userID, name, req.WrappedKey)
+	if err != nil {
+		return nil, h.vaultError(err, "не удалось создать хранилище")
+	}
+
+	return &vaultpb.CreateVaultResponse{Id: int32(id)}, nil
+}
+
+func (h *VaultServer) ListVaults(
+	ctx context.Context, _ *vaultpb.ListVaultsRequest,
+) (*vaultpb.ListVaultsResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	vaults, err := h.vaultService.ListVaults(ctx, userID)
+	if err != nil {
+		return nil, h.vaultError(err, "не удалось получить список хранилищ")
+	}
+
+	res := &vaultpb.ListVaultsResponse{Vaults: make([]*vaultpb.Vault, 0, len(vaults))}
+	for _, vault := range vaults {
+		res.Vaults = append(res.Vaults, &vaultpb.Vault{
+			Id:         int32(vault.ID),
+			Name:       vault.Name,
+			Role:       vault.Role,
+			WrappedKey: vault.WrappedKey,
+			Created:    timestamppb.New(vault.Created),
+		})
+	}
+
+	return res, nil
+}
+
+func (h *VaultServer) AddMember(
+	ctx context.Context, req *vaultpb.AddMemberRequest,
+) (*vaultpb.AddMemberResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	if req.Login == "" || req.WrappedKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "логин и ключ обязательны")
+	}
+
+	err = h.vaultService.AddMember(ctx, userID, int(req.VaultId), req.Login, req.Role, req.WrappedKey)
+	if err != nil {
+		return nil, h.vaultError(err, "не удалось добавить участника")
+	}
+
+	return &vaultpb.AddMemberResponse{}, nil
+}
+
+func (h *VaultServer) RemoveMember(
+	ctx context.Context, req *vaultpb.RemoveMemberRequest,
+) (*vaultpb.RemoveMemberResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	if err := h.vaultService.RemoveMember(ctx, userID, int(req.VaultId), req.Login); err != nil {
+		return nil, h.vaultError(err, "не удалось исключить участника")
+	}
+
+	return &vaultpb.RemoveMemberResponse{}, nil
+}
+
+func (h *VaultServer) ListMembers(
+	ctx context.Context, req *vaultpb.ListMembersRequest,
+) (*vaultpb.ListMembersResponse, error) {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "не удалось получить userID из контекста")
+	}
+
+	members, err := h.vaultService.ListMembers(ctx, userID, int(req.VaultId))
+	if err != nil {
+		return nil, h.vaultError(err, "не удалось получить участников")
+	}
+
+	res := &vaultpb.ListMembersResponse{Members: make([]*vaultpb.Member, 0, len(members))}
+	for _, member := range members {
+		res.Members = append(res.Members, &vaultpb.Member{Login: member.Login, Role: member.Role})
+	}
+
+	return res, nil
+}
+
+// vaultError - переводит ошибку сервиса хранилищ в статус gRPC.
+func (h *VaultServer) vaultError(err error, internalMessage string) error {
+	if st := accessStatus(err); st != nil {
+		return st
+	}
+
+	switch {
+	case errors.Is(err, helper.ErrUserNotFound), errors.Is(err, helper.ErrMemberNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, helper.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, helper.ErrNoPublicKey):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, helper.ErrKeyPairExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		h.logger.LogInfo(internalMessage, err)
+		return status.Error(codes.Internal, internalMessage)
+	}
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockVaultManager struct {
+	mock.Mock
+}
+
+func (m *MockVaultManager) SetKeyPair(ctx context.Context, userID int, pair *entity.KeyPair) error {
+	return m.Called(ctx, userID, pair).Error(0)
+}
+
+func (m *MockVaultManager) KeyPair(ctx context.Context, userID int) (*entity.KeyPair, error) {
+	args := m.Called(ctx, userID)
+	pair, _ := args.Get(0).(*entity.KeyPair)
+	return pair, args.Error(1)
+}
+
+func (m *MockVaultManager) PublicKey(ctx context.Context, login string) (string, error) {
+	args := m.Called(ctx, login)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockVaultManager) CreateVault(ctx context.Context, userID int, name, wrappedKey string) (int, error) {
+	args := m.Called(ctx, userID, name, wrappedKey)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockVaultManager) ListVaults(ctx context.Context, userID int) ([]*entity.Vault, error) {
+	args := m.Called(ctx, userID)
+	vaults, _ := args.Get(0).([]*entity.Vault)
+	return vaults, args.Error(1)
+}
+
+func (m *MockVaultManager) AddMember(ctx context.Context, userID, vaultID int, login, role, wrappedKey string) error {
+	return m.Called(ctx, userID, vaultID, login, role, wrappedKey).Error(0)
+}
+
+func (m *MockVaultManager) RemoveMember(ctx context.Context, userID, vaultID int, login string) error {
+	return m.Called(ctx, userID, vaultID, login).Error(0)
+}
+
+func (m *MockVaultManager) ListMembers(ctx context.Context, userID, vaultID int) ([]*entity.VaultMember, error) {
+	args := m.Called(ctx, userID, vaultID)
+	members, _ := args.Get(0).([]*entity.VaultMember)
+	return members, args.Error(1)
+}
+
+func TestVaultServer_ListVaults(t *testing.T) {
+	ctx := contextWithUserID(1)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	manager := new(MockVaultManager)
+	manager.On("ListVaults", ctx, 1).Return([]*entity.Vault{
+		{ID: 7, Name: "staging", Role: entity.RoleRead, WrappedKey: "wrapped", Created: created, OwnerID: 2},
+	}, nil)
+
+	res, err := NewVaultServer(manager, &mockLogger{}).ListVaults(ctx, &vaultpb.ListVaultsRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []*vaultpb.Vault{{
+		Id: 7, Name: "staging", Role: "read", WrappedKey: "wrapped", Created: timestamppb.New(created),
+	}}, res.Vaults)
+}
+
+func TestVaultServer_AddMember(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *vaultpb.AddMemberRequest
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{
+			name:         "участник добавлен",
+			req:          &vaultpb.AddMemberRequest{VaultId: 7, Login: "bob", Role: "read", WrappedKey: "wrapped"},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "без ключа",
+			req:          &vaultpb.AddMemberRequest{VaultId: 7, Login: "bob", Role: "read"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "не владелец",
+			req:          &vaultpb.AddMemberRequest{VaultId: 7, Login: "bob", Role: "read", WrappedKey: "wrapped"},
+			serviceErr:   helper.ErrAccessDenied,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "нет пользователя",
+			req:          &vaultpb.AddMemberRequest{VaultId: 7, Login: "bob", Role: "read", WrappedKey: "wrapped"},
+			serviceErr:   helper.ErrUserNotFound,
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "у пользователя нет ключей",
+			req:          &vaultpb.AddMemberRequest{VaultId: 7, Login: "bob", Role: "read", WrappedKey: "wrapped"},
+			serviceErr:   helper.ErrNoPublicKey,
+			expectedCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := contextWithUserID(1)
+			manager := new(MockVaultManager)
+			manager.On("AddMember", ctx, 1, 7, "bob", "read", "wrapped").Return(tt.serviceErr).Maybe()
+
+			_, err := NewVaultServer(manager, &mockLogger{}).AddMember(ctx, tt.req)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestVaultServer_SetKeyPair_AlreadyExists(t *testing.T) {
+	ctx := contextWithUserID(1)
+	manager := new(MockVaultManager)
+	manager.On("SetKeyPair", ctx, 1, &entity.KeyPair{PublicKey: "pub", SealedPrivateKey: "sealed"}).
+		Return(helper.ErrKeyPairExists)
+
+	_, err := NewVaultServer(manager, &mockLogger{}).SetKeyPair(ctx, &vaultpb.SetKeyPairRequest{
+		KeyPair: &vaultpb.KeyPair{PublicKey: "pub", SealedPrivateKey: "sealed"},
+	})
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
@@ -1,9 +1,50 @@
 package helper
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrLoginAlreadyExists = errors.New("логин уже существует")
 	ErrInvalidCredentials = errors.New("неверная пара логин/пароль")
 	ErrInternalServer     = errors.New("внутренняя ошибка сервера")
+	ErrAccountLocked      = errors.New("учётная запись временно заблокирована")
+	ErrInvalidCursor      = errors.New("некорректный курсор пагинации")
+	ErrDataNotFound       = errors.New("данные не найдены")
+	ErrChecksumMismatch   = errors.New("контрольная сумма не совпадает")
+	ErrVersionConflict    = errors.New("версия записи устарела")
+	ErrInvalidRefresh     = errors.New("недействительный refresh-токен")
+	ErrSessionNotFound    = errors.New("сессия не найдена")
+	ErrInvalidOTP         = errors.New("неверный код подтверждения")
+	ErrInvalidChallenge   = errors.New("вход не начат или время на ввод кода истекло")
+	ErrTwoFactorEnabled   = errors.New("двухфакторная аутентификация уже включена")
+	ErrTwoFactorDisabled  = errors.New("двухфакторная аутентификация не включена")
+	ErrTOTPNotEnrolled    = errors.New("аутентификатор не подключён")
+	ErrAccessDenied       = errors.New("недостаточно прав")
+	ErrVaultNotFound      = errors.New("хранилище не найдено")
+	ErrUserNotFound       = errors.New("пользователь не найден")
+	ErrNoPublicKey        = errors.New("пользователь ещё не создал ключи для общих хранилищ")
+	ErrKeyPairExists      = errors.New("ключевая пара уже создана")
+	ErrMemberNotFound     = errors.New("участник не найден")
+	ErrInvalidRole        = errors.New("некорректная роль участника")
+	ErrBlobNotMovable     = errors.New("файл, загруженный потоком, нельзя перенести в другое хранилище")
+	ErrVaultMismatch      = errors.New("запись находится в другом хранилище, для переноса используйте MoveData")
+	ErrShareNotFound      = errors.New("ссылка не найдена, истекла или уже использована")
+	ErrInvalidShare       = errors.New("некорректные параметры ссылки")
+	ErrVersionNotFound    = errors.New("такой версии записи нет в истории")
+	ErrKeyCheckExists     = errors.New("проверка мастер-пароля уже сохранена")
 )
+
+// VersionConflictError - запись изменили после того, как клиент прочитал её версию.
+type VersionConflictError struct {
+	Current int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: текущая версия %d", ErrVersionConflict, e.Current)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
@@ -0,0 +1,98 @@
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var keyPattern = regexp.MustCompile(`^[0-9a-f]{16,64}$`)
+
+type fileSystem struct {
+	dir string
+}
+
+// NewFileSystem - конструктор хранилища блобов в каталоге на диске.
+func NewFileSystem(dir string) (*fileSystem, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("не удалось создать каталог блобов: %w", err)
+	}
+
+	return &fileSystem{dir: dir}, nil
+}
+
+// Put - записывает блоб целиком. Файл сначала пишется во временный,
+// поэтому оборванная загрузка не оставляет частично записанный блоб.
+func (fs *fileSystem) Put(key string, r io.Reader) (int64, error) {
+	path, err := fs.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(fs.dir, "upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("не удалось создать временный файл: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		_ = tmp.Close()
+		return 0, fmt.Errorf("ошибка записи блоба: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return 0, fmt.Errorf("ошибка сброса блоба на диск: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("ошибка закрытия блоба: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("ошибка сохранения блоба: %w", err)
+	}
+
+	return n, nil
+}
+
+// Open - открывает блоб на чтение.
+func (fs *fileSystem) Open(key string) (io.ReadCloser, error) {
+	path, err := fs.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть блоб: %w", err)
+	}
+
+	return f, nil
+}
+
+// Delete - удаляет блоб. Отсутствующий блоб ошибкой не считается.
+func (fs *fileSystem) Delete(key string) error {
+	path, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("не удалось удалить блоб: %w", err)
+	}
+
+	return nil
+}
+
+func (fs *fileSystem) path(key string) (string, error) {
+	if !keyPattern.MatchString(key) {
+		return "", fmt.Errorf("некорректный ключ блоба: %q", key)
+	}
+
+	return filepath.Join(fs.dir, key), nil
+}
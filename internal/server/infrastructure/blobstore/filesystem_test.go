@@ -0,0 +1,73 @@
+package blobstore
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+func TestFileSystem_PutOpenDelete(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "blobs")
+	fs, err := NewFileSystem(dir)
+	require.NoError(t, err)
+
+	n, err := fs.Put(testKey, strings.NewReader("содержимое"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("содержимое")), n)
+
+	r, err := fs.Open(testKey)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "содержимое", string(content))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "временные файлы должны быть удалены")
+
+	require.NoError(t, fs.Delete(testKey))
+	_, err = fs.Open(testKey)
+	assert.Error(t, err)
+
+	assert.NoError(t, fs.Delete(testKey), "повторное удаление не ошибка")
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestFileSystem_PutFailure(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := NewFileSystem(dir)
+	require.NoError(t, err)
+
+	_, err = fs.Put(testKey, failingReader{})
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFileSystem_InvalidKey(t *testing.T) {
+	fs, err := NewFileSystem(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../etc/passwd", "ABCDEF0123456789", "short"} {
+		_, err := fs.Put(key, strings.NewReader("x"))
+		assert.Error(t, err, key)
+		_, err = fs.Open(key)
+		assert.Error(t, err, key)
+		assert.Error(t, fs.Delete(key), key)
+	}
+}
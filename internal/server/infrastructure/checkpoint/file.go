@@ -0,0 +1,66 @@
+package checkpoint
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+)
+
+// Append - дописывает контрольную точку аудита в файл, по одной JSON-строке
+// на точку. Файл стоит хранить вне сервера базы, иначе он не защищает от
+// того, у кого есть доступ к базе.
+func Append(path string, cp *entity.AuditCheckpoint) error {
+	line, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать контрольную точку: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть файл контрольных точек: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("не удалось записать контрольную точку: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("не удалось закрыть файл контрольных точек: %w", err)
+	}
+
+	return nil
+}
+
+// Load - читает контрольные точки из файла. Отсутствующий файл - точек нет.
+func Load(path string) ([]*entity.AuditCheckpoint, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл контрольных точек: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var checkpoints []*entity.AuditCheckpoint
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var cp entity.AuditCheckpoint
+		if err := json.Unmarshal(scanner.Bytes(), &cp); err != nil {
+			return nil, fmt.Errorf("строка %d файла контрольных точек повреждена: %w", line, err)
+		}
+		checkpoints = append(checkpoints, &cp)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл контрольных точек: %w", err)
+	}
+
+	return checkpoints, nil
+}
@@ -0,0 +1,46 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.jsonl")
+
+	checkpoints, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, checkpoints)
+
+	first := &entity.AuditCheckpoint{
+		EventID: 10, Hash: []byte("hash-10"), Signature: []byte("sig"), CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	second := &entity.AuditCheckpoint{
+		EventID: 20, Hash: []byte("hash-20"), Signature: []byte("sig"), CreatedAt: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, Append(path, first))
+	require.NoError(t, Append(path, second))
+
+	checkpoints, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.AuditCheckpoint{first, second}, checkpoints)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestLoad_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"event_id\": 1}\nне json\n"), 0o600))
+
+	_, err := Load(path)
+
+	assert.ErrorContains(t, err, "строка 2")
+}
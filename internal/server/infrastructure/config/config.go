@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/caarlos0/env"
 )
@@ -15,6 +16,28 @@ type config struct {
 	CryptoKey     string `env:"CRYPTO_KEY"`
 	ServerKeyPath string `env:"SERVER_KEY_PATH"`
 	ServerCrtPath string `env:"SERVER_CRT_PATH"`
+
+	LoginMaxAttempts  int           `env:"LOGIN_MAX_ATTEMPTS"`
+	LoginLockDuration time.Duration `env:"LOGIN_LOCK_DURATION"`
+
+	AccessTokenTTL  time.Duration `env:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL"`
+
+	RotateBatchSize int `env:"ROTATE_BATCH_SIZE"`
+
+	BlobDir string `env:"BLOB_DIR"`
+
+	HistoryRetention int `env:"HISTORY_RETENTION"`
+
+	TrashRetention     time.Duration `env:"TRASH_RETENTION"`
+	TrashPurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL"`
+
+	AuditKeyPath            string        `env:"AUDIT_KEY_PATH"`
+	AuditCheckpointPath     string        `env:"AUDIT_CHECKPOINT_PATH"`
+	AuditCheckpointInterval time.Duration `env:"AUDIT_CHECKPOINT_INTERVAL"`
+
+	// Command - подкоманда сервера, первый позиционный аргумент после флагов.
+	Command string
 }
 
 func (c *config) initEnv() error {
@@ -35,10 +58,27 @@ func (c *config) parseFlags() {
 			"sslmode=disable",
 		"data source name for connection")
 	flag.StringVar(&c.SecretKey, "k", "abc", "secret key for hash")
-	flag.StringVar(&c.CryptoKey, "crypto-key", "01234567890123456789012345678901", "crypto key")
+	flag.StringVar(&c.CryptoKey, "crypto-key", "./keyring.json", "path to encryption keyring file")
 	flag.StringVar(&c.ServerKeyPath, "server-key", "./server.key", "path to server key")
 	flag.StringVar(&c.ServerCrtPath, "server-crt", "./server.crt", "path to server crt")
+	flag.IntVar(&c.LoginMaxAttempts, "login-max-attempts", 5, "failed login attempts before lockout")
+	flag.DurationVar(&c.LoginLockDuration, "login-lock-duration", 15*time.Minute, "login lockout duration")
+	flag.DurationVar(&c.AccessTokenTTL, "access-token-ttl", 15*time.Minute, "access token lifetime")
+	flag.DurationVar(&c.RefreshTokenTTL, "refresh-token-ttl", 30*24*time.Hour, "refresh token (session) lifetime")
+	flag.IntVar(&c.RotateBatchSize, "rotate-batch-size", 500, "rows per batch for rotate-keys")
+	flag.StringVar(&c.BlobDir, "blob-dir", "./blobs", "directory for binary blobs")
+	flag.IntVar(&c.HistoryRetention, "history-retention", 10, "previous versions kept per item, 0 - no history")
+	flag.DurationVar(&c.TrashRetention, "trash-retention", 30*24*time.Hour,
+		"how long deleted items stay in the trash, 0 - until purged by the user")
+	flag.DurationVar(&c.TrashPurgeInterval, "trash-purge-interval", time.Hour, "how often to purge expired trash")
+	flag.StringVar(&c.AuditKeyPath, "audit-key", "./audit.key", "path to audit checkpoint signing key")
+	flag.StringVar(&c.AuditCheckpointPath, "audit-checkpoints", "./audit-checkpoints.jsonl",
+		"file for signed audit checkpoints")
+	flag.DurationVar(&c.AuditCheckpointInterval, "audit-checkpoint-interval", 0,
+		"how often to write audit checkpoints, 0 - only by audit-checkpoint command")
 	flag.Parse()
+
+	c.Command = flag.Arg(0)
 }
 
 // NewConfig конструктор конфига, в котором идёт инициализация флагов и env переменных.
@@ -68,7 +108,7 @@ func (c config) GetSecretKey() string {
 	return c.SecretKey
 }
 
-// GetCryptoKeyPath геттер для ключа шифрования.
+// GetCryptoKeyPath геттер для пути к файлу связки ключей шифрования.
 func (c config) GetCryptoKeyPath() string {
 	return c.CryptoKey
 }
@@ -82,3 +122,68 @@ func (c config) GetServerKeyPath() string {
 func (c config) GetServerCrtPath() string {
 	return c.ServerCrtPath
 }
+
+// GetLoginMaxAttempts геттер для количества неудачных попыток входа до блокировки.
+func (c config) GetLoginMaxAttempts() int {
+	return c.LoginMaxAttempts
+}
+
+// GetLoginLockDuration геттер для длительности блокировки входа.
+func (c config) GetLoginLockDuration() time.Duration {
+	return c.LoginLockDuration
+}
+
+// GetAccessTokenTTL геттер для времени жизни access-токена.
+func (c config) GetAccessTokenTTL() time.Duration {
+	return c.AccessTokenTTL
+}
+
+// GetRefreshTokenTTL геттер для времени жизни refresh-токена и сессии.
+func (c config) GetRefreshTokenTTL() time.Duration {
+	return c.RefreshTokenTTL
+}
+
+// GetRotateBatchSize геттер для размера пачки при ротации ключей.
+func (c config) GetRotateBatchSize() int {
+	return c.RotateBatchSize
+}
+
+// GetBlobDir геттер для каталога хранения двоичных файлов.
+func (c config) GetBlobDir() string {
+	return c.BlobDir
+}
+
+// GetHistoryRetention геттер для числа хранимых прежних версий записи. 0 - история не ведётся.
+func (c config) GetHistoryRetention() int {
+	return c.HistoryRetention
+}
+
+// GetTrashRetention геттер для срока хранения удалённых записей в корзине. 0 - хранить до ручной очистки.
+func (c config) GetTrashRetention() time.Duration {
+	return c.TrashRetention
+}
+
+// GetTrashPurgeInterval геттер для периода очистки корзины от просроченных записей.
+func (c config) GetTrashPurgeInterval() time.Duration {
+	return c.TrashPurgeInterval
+}
+
+// GetAuditKeyPath геттер для пути к ключу подписи контрольных точек аудита.
+func (c config) GetAuditKeyPath() string {
+	return c.AuditKeyPath
+}
+
+// GetAuditCheckpointPath геттер для файла контрольных точек аудита.
+func (c config) GetAuditCheckpointPath() string {
+	return c.AuditCheckpointPath
+}
+
+// GetAuditCheckpointInterval геттер для периода записи контрольных точек. 0 - не записывать.
+func (c config) GetAuditCheckpointInterval() time.Duration {
+	return c.AuditCheckpointInterval
+}
+
+// GetCommand геттер для подкоманды сервера. Пустая строка - запуск gRPC сервера.
+func (c config) GetCommand() string {
+	return c.Command
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"flag"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -61,10 +62,42 @@ func TestConfig_Getters(t *testing.T) {
 		DatabaseURI: "user=test password=test dbname=testdb sslmode=disable",
 		SecretKey:   "supersecret",
 		CryptoKey:   "/path/to/crypto.key",
+
+		LoginMaxAttempts:  3,
+		LoginLockDuration: time.Minute,
+
+		AccessTokenTTL:  5 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+
+		RotateBatchSize: 100,
+		BlobDir:         "/var/lib/gophkeeper/blobs",
+		Command:         "rotate-keys",
+
+		HistoryRetention: 7,
+
+		TrashRetention:     7 * 24 * time.Hour,
+		TrashPurgeInterval: 10 * time.Minute,
+
+		AuditKeyPath:            "/etc/gophkeeper/audit.key",
+		AuditCheckpointPath:     "/mnt/audit/checkpoints.jsonl",
+		AuditCheckpointInterval: time.Hour,
 	}
 
 	assert.Equal(t, "127.0.0.1:9090", cfg.GetRunAddress())
 	assert.Equal(t, "user=test password=test dbname=testdb sslmode=disable", cfg.GetDatabaseURI())
 	assert.Equal(t, "supersecret", cfg.GetSecretKey())
 	assert.Equal(t, "/path/to/crypto.key", cfg.GetCryptoKeyPath())
+	assert.Equal(t, 3, cfg.GetLoginMaxAttempts())
+	assert.Equal(t, time.Minute, cfg.GetLoginLockDuration())
+	assert.Equal(t, 5*time.Minute, cfg.GetAccessTokenTTL())
+	assert.Equal(t, time.Hour, cfg.GetRefreshTokenTTL())
+	assert.Equal(t, 100, cfg.GetRotateBatchSize())
+	assert.Equal(t, "/var/lib/gophkeeper/blobs", cfg.GetBlobDir())
+	assert.Equal(t, 7, cfg.GetHistoryRetention())
+	assert.Equal(t, 7*24*time.Hour, cfg.GetTrashRetention())
+	assert.Equal(t, 10*time.Minute, cfg.GetTrashPurgeInterval())
+	assert.Equal(t, "rotate-keys", cfg.GetCommand())
+	assert.Equal(t, "/etc/gophkeeper/audit.key", cfg.GetAuditKeyPath())
+	assert.Equal(t, "/mnt/audit/checkpoints.jsonl", cfg.GetAuditCheckpointPath())
+	assert.Equal(t, time.Hour, cfg.GetAuditCheckpointInterval())
 }
@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS login_attempts(
+    login VARCHAR (50) PRIMARY KEY,
+    failed_count INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+COMMIT;
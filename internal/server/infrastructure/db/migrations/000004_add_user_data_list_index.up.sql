@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+CREATE INDEX IF NOT EXISTS user_data_user_id_created_id_idx ON user_data (user_id, created, id);
+
+COMMIT;
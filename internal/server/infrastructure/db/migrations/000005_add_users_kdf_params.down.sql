@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS kdf_salt,
+    DROP COLUMN IF EXISTS kdf_time,
+    DROP COLUMN IF EXISTS kdf_memory,
+    DROP COLUMN IF EXISTS kdf_threads;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN TRANSACTION;
+
+-- Соль и параметры Argon2id, по которым клиент получает ключ шифрования
+-- из мастер-пароля. Сервер хранит их открыто и отдаёт при входе.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS kdf_salt BYTEA NOT NULL DEFAULT decode(md5(random()::text || clock_timestamp()::text), 'hex'),
+    ADD COLUMN IF NOT EXISTS kdf_time INT NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS kdf_memory INT NOT NULL DEFAULT 65536,
+    ADD COLUMN IF NOT EXISTS kdf_threads INT NOT NULL DEFAULT 4;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS user_blobs(
+    data_id INT PRIMARY KEY REFERENCES user_data (id) ON DELETE CASCADE,
+    blob_key VARCHAR (64) NOT NULL UNIQUE,
+    size BIGINT NOT NULL,
+    sha256 CHAR (64) NOT NULL
+);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN TRANSACTION;
+
+DROP TABLE IF EXISTS user_data_tombstones;
+
+DROP INDEX IF EXISTS user_data_user_id_revision_idx;
+
+ALTER TABLE user_data
+    DROP COLUMN IF EXISTS revision,
+    DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS revision;
+
+COMMIT;
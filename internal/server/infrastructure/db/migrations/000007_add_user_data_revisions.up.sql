@@ -0,0 +1,36 @@
+BEGIN TRANSACTION;
+
+-- Ревизия - счётчик изменений пользователя. Каждое добавление, изменение
+-- и удаление записи увеличивает его на единицу, и запись получает новое значение.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE user_data
+    ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+
+UPDATE user_data d
+SET revision = numbered.revision, updated_at = d.created
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY id) AS revision
+    FROM user_data
+) numbered
+WHERE numbered.id = d.id;
+
+UPDATE users u
+SET revision = counts.revision
+FROM (SELECT user_id, MAX(revision) AS revision FROM user_data GROUP BY user_id) counts
+WHERE counts.user_id = u.id;
+
+CREATE INDEX IF NOT EXISTS user_data_user_id_revision_idx ON user_data (user_id, revision);
+
+CREATE TABLE IF NOT EXISTS user_data_tombstones(
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    data_id INT NOT NULL,
+    revision BIGINT NOT NULL,
+    deleted_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, data_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_data_tombstones_user_id_revision_idx ON user_data_tombstones (user_id, revision);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+-- Версия записи для оптимистичной блокировки: изменение и удаление
+-- применяются, только если клиент видел текущую версию.
+ALTER TABLE user_data ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMIT;
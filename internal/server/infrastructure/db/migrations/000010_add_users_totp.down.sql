@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_secret,
+    DROP COLUMN IF EXISTS totp_enabled,
+    DROP COLUMN IF EXISTS totp_last_step;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN TRANSACTION;
+
+-- Второй фактор входа. Секрет хранится зашифрованным ключом сервера,
+-- totp_last_step - последний принятый шаг, чтобы код нельзя было
+-- предъявить повторно.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS totp_secret TEXT,
+    ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- Одноразовые коды восстановления на случай потери аутентификатора.
+CREATE TABLE IF NOT EXISTS user_recovery_codes(
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash BYTEA NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS user_recovery_codes_user_id_idx ON user_recovery_codes (user_id);
+
+COMMIT;
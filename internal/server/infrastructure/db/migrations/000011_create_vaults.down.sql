@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+DROP INDEX IF EXISTS user_data_vault_id_idx;
+
+ALTER TABLE user_data DROP COLUMN IF EXISTS vault_id;
+
+DROP TABLE IF EXISTS vault_members;
+
+DROP TABLE IF EXISTS vaults;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS public_key,
+    DROP COLUMN IF EXISTS sealed_private_key;
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN TRANSACTION;
+
+-- Ключевая пара X25519 для обмена ключами общих хранилищ. Закрытый ключ
+-- зашифрован клиентом ключом из мастер-пароля, сервер хранит его как есть.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS public_key TEXT,
+    ADD COLUMN IF NOT EXISTS sealed_private_key TEXT;
+
+CREATE TABLE IF NOT EXISTS vaults(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Участники хранилища. wrapped_key - ключ хранилища, зашифрованный
+-- открытым ключом участника.
+CREATE TABLE IF NOT EXISTS vault_members(
+    vault_id INT NOT NULL REFERENCES vaults (id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'write', 'read')),
+    wrapped_key TEXT NOT NULL,
+    PRIMARY KEY (vault_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS vault_members_user_id_idx ON vault_members (user_id);
+
+-- NULL - личная запись пользователя user_id, иначе запись общего хранилища.
+ALTER TABLE user_data ADD COLUMN IF NOT EXISTS vault_id INT REFERENCES vaults (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS user_data_vault_id_idx ON user_data (vault_id, created, id);
+
+COMMIT;
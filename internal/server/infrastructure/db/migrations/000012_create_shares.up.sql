@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS shares(
+    id_hash BYTEA PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    data_id INT NOT NULL REFERENCES user_data (id) ON DELETE CASCADE,
+    payload TEXT NOT NULL,
+    views_left INT NOT NULL CHECK (views_left > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS shares_expires_at_idx ON shares (expires_at);
+
+COMMIT;
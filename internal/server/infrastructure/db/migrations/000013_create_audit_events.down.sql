@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN TRANSACTION;
+
+-- audit_events - журнал вызовов сервера. Внешнего ключа на users нет,
+-- чтобы события переживали удаление пользователя; user_id пуст, если
+-- вызывающего не удалось определить.
+CREATE TABLE IF NOT EXISTS audit_events(
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT,
+    login TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL,
+    data_id INT,
+    client_ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    outcome TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_user_id_idx ON audit_events (user_id, id);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events: изменение и удаление событий запрещены';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+COMMIT;
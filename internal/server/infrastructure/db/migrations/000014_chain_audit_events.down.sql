@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+DROP TABLE IF EXISTS audit_chain_head;
+
+ALTER TABLE audit_events
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS hash;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN TRANSACTION;
+
+-- Каждое событие хранит хеш предыдущего и свой: hash = sha256(prev_hash || содержимое).
+-- События, записанные до этой миграции, остаются без хешей и в цепочку не входят.
+ALTER TABLE audit_events
+    ADD COLUMN IF NOT EXISTS prev_hash BYTEA,
+    ADD COLUMN IF NOT EXISTS hash BYTEA;
+
+-- audit_chain_head - хеш последнего события. Обновление этой единственной строки
+-- упорядочивает параллельные записи в журнал.
+CREATE TABLE IF NOT EXISTS audit_chain_head(
+    id INT PRIMARY KEY CHECK (id = 1),
+    prev_hash BYTEA,
+    hash BYTEA NOT NULL
+);
+
+INSERT INTO audit_chain_head (id, hash) VALUES (1, decode(repeat('00', 32), 'hex'))
+ON CONFLICT (id) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN TRANSACTION;
+
+DROP INDEX IF EXISTS user_data_folder_id_idx;
+
+ALTER TABLE user_data
+    DROP COLUMN IF EXISTS favorite,
+    DROP COLUMN IF EXISTS folder_id;
+
+DROP TABLE IF EXISTS user_data_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS folders;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS folders(
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    UNIQUE (user_id, path)
+);
+
+CREATE TABLE IF NOT EXISTS tags(
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS user_data_tags(
+    data_id INT NOT NULL REFERENCES user_data (id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (data_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_data_tags_tag_id_idx ON user_data_tags (tag_id);
+
+ALTER TABLE user_data
+    ADD COLUMN IF NOT EXISTS folder_id INT REFERENCES folders (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS favorite BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS user_data_folder_id_idx ON user_data (folder_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS user_data_search_tokens(
+    data_id INT NOT NULL REFERENCES user_data (id) ON DELETE CASCADE,
+    token CHAR(64) NOT NULL,
+    PRIMARY KEY (token, data_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_data_search_tokens_data_id_idx ON user_data_search_tokens (data_id);
+
+COMMIT;
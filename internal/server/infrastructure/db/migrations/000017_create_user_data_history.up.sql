@@ -0,0 +1,16 @@
+BEGIN TRANSACTION;
+
+-- Прежние версии записей: изменение и восстановление кладут сюда содержимое,
+-- которое заменяют. Для каждой записи хранится не больше заданного числа версий.
+CREATE TABLE IF NOT EXISTS user_data_history(
+    id SERIAL PRIMARY KEY,
+    data_id INT NOT NULL REFERENCES user_data (id) ON DELETE CASCADE,
+    version BIGINT NOT NULL,
+    info_type VARCHAR(50) NOT NULL,
+    info TEXT,
+    meta TEXT,
+    saved_at TIMESTAMP NOT NULL,
+    UNIQUE (data_id, version)
+);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+DELETE FROM user_data WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS user_data_deleted_at_idx;
+
+ALTER TABLE user_data DROP COLUMN IF EXISTS deleted_at;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+-- Удалённая запись остаётся в корзине до окончательного удаления: пользователем
+-- или фоновой очисткой после срока хранения.
+ALTER TABLE user_data ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS user_data_deleted_at_idx ON user_data (deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
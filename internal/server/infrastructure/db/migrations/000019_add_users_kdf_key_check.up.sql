@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+-- Шифртекст константы под ключом, выведенным из мастер-пароля. Клиент
+-- расшифровывает его при входе и так узнаёт, что мастер-пароль введён верно.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kdf_key_check BYTEA;
+
+COMMIT;
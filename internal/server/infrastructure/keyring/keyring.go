@@ -0,0 +1,74 @@
+package keyring
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+)
+
+// keyringFile - формат файла связки ключей:
+//
+//	{"keys": [{"id": "2024-01", "key": "<base64 32 байт>"}, ...]}
+//
+// Ключи перечисляются от старого к новому, последний считается активным.
+type keyringFile struct {
+	Keys []entity.EncryptionKey `json:"keys"`
+}
+
+// publicKey - ключ из файла, который раньше лежал в репозитории. Он известен
+// всем, поэтому шифровать им новые данные нельзя. Среди старых ключей он
+// допустим: иначе данные, зашифрованные им, не перешифровать командой rotate-keys.
+var publicKey = []byte("01234567890123456789012345678901")
+
+// Load - читает связку ключей шифрования из JSON-файла.
+func Load(path string) ([]entity.EncryptionKey, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("файл ключей %s не найден: создайте его по образцу keyring.example.json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл ключей: %w", err)
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("не удалось разобрать файл ключей: %w", err)
+	}
+	if len(file.Keys) == 0 {
+		return nil, fmt.Errorf("в файле %s нет ключей", path)
+	}
+	if active := file.Keys[len(file.Keys)-1]; bytes.Equal(active.Key, publicKey) {
+		return nil, fmt.Errorf(
+			"активный ключ %s опубликован в репозитории: добавьте новый ключ в конец %s и выполните rotate-keys",
+			active.ID, path,
+		)
+	}
+
+	return file.Keys, nil
+}
+
+// LoadSigningKey - читает ключ подписи Ed25519: файл содержит seed
+// из 32 байт в base64, например результат `head -c 32 /dev/urandom | base64`.
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать ключ подписи: %w", err)
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("ключ подписи %s не в base64: %w", path, err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("ключ подписи %s должен быть %d байт, получено %d", path, ed25519.SeedSize, len(seed))
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
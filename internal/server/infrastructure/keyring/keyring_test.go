@@ -0,0 +1,119 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKeyring(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("не удалось записать файл: %v", err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         func(t *testing.T) string
+		expectedKeys []entity.EncryptionKey
+		expectedErr  string
+	}{
+		{
+			name: "успешная загрузка",
+			path: func(t *testing.T) string {
+				return writeKeyring(t, `{"keys": [
+					{"id": "v1", "key": "MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="},
+					{"id": "v2", "key": "MTA5ODc2NTQzMjEwOTg3NjU0MzIxMDk4NzY1NDMyMTA="}
+				]}`)
+			},
+			expectedKeys: []entity.EncryptionKey{
+				{ID: "v1", Key: []byte("01234567890123456789012345678901")},
+				{ID: "v2", Key: []byte("10987654321098765432109876543210")},
+			},
+		},
+		{
+			name:        "файл не найден",
+			path:        func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing.json") },
+			expectedErr: "не найден: создайте его по образцу keyring.example.json",
+		},
+		{
+			name:        "файл не читается",
+			path:        func(t *testing.T) string { return t.TempDir() },
+			expectedErr: "не удалось прочитать файл ключей",
+		},
+		{
+			name: "активен опубликованный ключ",
+			path: func(t *testing.T) string {
+				return writeKeyring(t, `{"keys": [{"id": "v1", "key": "MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="}]}`)
+			},
+			expectedErr: "активный ключ v1 опубликован в репозитории",
+		},
+		{
+			name:        "некорректный JSON",
+			path:        func(t *testing.T) string { return writeKeyring(t, `{"keys": [`) },
+			expectedErr: "не удалось разобрать файл ключей",
+		},
+		{
+			name:        "некорректный base64",
+			path:        func(t *testing.T) string { return writeKeyring(t, `{"keys": [{"id": "v1", "key": "!!!"}]}`) },
+			expectedErr: "не удалось разобрать файл ключей",
+		},
+		{
+			name:        "пустая связка",
+			path:        func(t *testing.T) string { return writeKeyring(t, `{"keys": []}`) },
+			expectedErr: "нет ключей",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := Load(tt.path(t))
+
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedKeys, keys)
+		})
+	}
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	seed := "MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="
+
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{name: "успешная загрузка", content: seed + "\n"},
+		{name: "некорректный base64", content: "!!!", expectedErr: "не в base64"},
+		{name: "неверная длина", content: "MDEy", expectedErr: "должен быть 32 байт"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadSigningKey(writeKeyring(t, tt.content))
+
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, ed25519.NewKeyFromSeed([]byte("01234567890123456789012345678901")), key)
+		})
+	}
+}
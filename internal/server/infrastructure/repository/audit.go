@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+)
+
+type auditRepository struct {
+	db     dataStorager
+	logger logger.CustomLogger
+}
+
+// NewAuditRepository - конструктор журнала аудита. Журнал только
+// дополняется: изменить или удалить событие не даёт триггер в базе,
+// а обход триггера обнаруживается по цепочке хешей.
+func NewAuditRepository(db dataStorager, logger logger.CustomLogger) *auditRepository {
+	return &auditRepository{db: db, logger: logger}
+}
+
+// UserIDByLogin - id пользователя с логином login; 0, если такого нет.
+// Нужен, чтобы приписать неудачный вход владельцу логина.
+func (r *auditRepository) UserIDByLogin(ctx context.Context, login string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM users WHERE login = $1`, login).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		r.logger.LogInfo("ошибка при поиске пользователя для аудита", err)
+		return 0, helper.ErrInternalServer
+	}
+
+	return userID, nil
+}
+
+// Append - сохраняет событие и связывает его с предыдущим: хеш считается
+// в базе от хеша головы цепочки и payload - канонического содержимого события.
+// Обновление audit_chain_head блокирует строку, поэтому параллельные записи
+// выстраиваются в одну цепочку. Заполняет ID, PrevHash и Hash события.
+func (r *auditRepository) Append(ctx context.Context, event *entity.AuditEvent, payload []byte) error {
+	query := `
+        WITH head AS (
+            UPDATE audit_chain_head h
+            SET prev_hash = h.hash, hash = sha256(h.hash || $8::bytea)
+            WHERE h.id = 1
+            RETURNING h.prev_hash, h.hash
+        )
+        INSERT INTO audit_events (
+            user_id, login, action, data_id, client_ip, user_agent, outcome, created_at, prev_hash, hash
+        )
+        SELECT $1::int, $2::text, $3::text, $4::int, $5::text, $6::text, $7::text, $9::timestamptz,
+            head.prev_hash, head.hash
+        FROM head
+        RETURNING id, prev_hash, hash
+    `
+	err := r.db.QueryRowContext(
+		ctx, query, nullableID(event.UserID), event.Login, event.Action, nullableID(event.DataID),
+		event.ClientIP, event.UserAgent, event.Outcome, payload, event.CreatedAt,
+	).Scan(&event.ID, &event.PrevHash, &event.Hash)
+	if err != nil {
+		r.logger.LogInfo("ошибка при записи события аудита", err)
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
+
+// List - возвращает до limit событий пользователя от новых к старым.
+// beforeID > 0 - только события старше события с этим id.
+func (r *auditRepository) List(
+	ctx context.Context, userID int, beforeID int64, limit int,
+) ([]*entity.AuditEvent, error) {
+	query := `
+        SELECT id, user_id, login, action, COALESCE(data_id, 0), client_ip, user_agent, outcome, created_at
+        FROM audit_events
+        WHERE user_id = $1 AND ($2 = 0 OR id < $2)
+        ORDER BY id DESC
+        LIMIT $3
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID, beforeID, limit)
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении событий аудита", err)
+		return nil, helper.ErrInternalServer
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	var events []*entity.AuditEvent
+	for rows.Next() {
+		var e entity.AuditEvent
+		err := rows.Scan(
+			&e.ID, &e.UserID, &e.Login, &e.Action, &e.DataID, &e.ClientIP, &e.UserAgent, &e.Outcome, &e.CreatedAt,
+		)
+		if err != nil {
+			r.logger.LogInfo("ошибка при сканировании события аудита", err)
+			return nil, helper.ErrInternalServer
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.LogInfo("ошибка при обходе событий аудита", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return events, nil
+}
+
+// ListChain - возвращает до limit событий с id больше afterID по возрастанию id
+// вместе с хешами цепочки. У событий, записанных до появления цепочки, хешей нет.
+func (r *auditRepository) ListChain(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEvent, error) {
+	query := `
+        SELECT id, COALESCE(user_id, 0), login, action, COALESCE(data_id, 0), client_ip, user_agent,
+            outcome, created_at, prev_hash, hash
+        FROM audit_events
+        WHERE id > $1
+        ORDER BY id
+        LIMIT $2
+    `
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		r.logger.LogInfo("ошибка при чтении цепочки аудита", err)
+		return nil, helper.ErrInternalServer
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	var events []*entity.AuditEvent
+	for rows.Next() {
+		var e entity.AuditEvent
+		err := rows.Scan(
+			&e.ID, &e.UserID, &e.Login, &e.Action, &e.DataID, &e.ClientIP, &e.UserAgent,
+			&e.Outcome, &e.CreatedAt, &e.PrevHash, &e.Hash,
+		)
+		if err != nil {
+			r.logger.LogInfo("ошибка при сканировании события аудита", err)
+			return nil, helper.ErrInternalServer
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.LogInfo("ошибка при обходе цепочки аудита", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return events, nil
+}
+
+// ChainHead - хеш последнего события цепочки по данным audit_chain_head.
+func (r *auditRepository) ChainHead(ctx context.Context) ([]byte, error) {
+	var hash []byte
+	err := r.db.QueryRowContext(ctx, `SELECT hash FROM audit_chain_head WHERE id = 1`).Scan(&hash)
+	if err != nil {
+		r.logger.LogInfo("ошибка при чтении головы цепочки аудита", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return hash, nil
+}
+
+// LastChained - последнее событие, вошедшее в цепочку; nil, если таких нет.
+// Заполнены только ID и Hash.
+func (r *auditRepository) LastChained(ctx context.Context) (*entity.AuditEvent, error) {
+	query := `SELECT id, hash FROM audit_events WHERE hash IS NOT NULL ORDER BY id DESC LIMIT 1`
+	var e entity.AuditEvent
+	err := r.db.QueryRowContext(ctx, query).Scan(&e.ID, &e.Hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.LogInfo("ошибка при чтении последнего события аудита", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return &e, nil
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit_UserIDByLogin(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id FROM users WHERE login = \$1`).WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(`SELECT id FROM users WHERE login = \$1`).WithArgs("nobody").
+		WillReturnError(sql.ErrNoRows)
+	repo := NewAuditRepository(db, new(mockLogger))
+
+	userID, err := repo.UserIDByLogin(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 3, userID)
+
+	userID, err = repo.UserIDByLogin(context.Background(), "nobody")
+	require.NoError(t, err)
+	assert.Equal(t, 0, userID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAudit_Append(t *testing.T) {
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		event       *entity.AuditEvent
+		args        []driver.Value
+		queryErr    error
+		expectedErr error
+	}{
+		{
+			name: "вызов вошедшего пользователя",
+			event: &entity.AuditEvent{
+				UserID: 1, Action: "/data.DataService/GetData", DataID: 5,
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go", Outcome: "OK", CreatedAt: created,
+			},
+			args: []driver.Value{
+				sql.NullInt64{Int64: 1, Valid: true}, "", "/data.DataService/GetData",
+				sql.NullInt64{Int64: 5, Valid: true}, "10.0.0.1", "grpc-go", "OK", []byte("payload"), created,
+			},
+		},
+		{
+			name: "неизвестный пользователь",
+			event: &entity.AuditEvent{
+				Login: "mallory", Action: "/auth.Auth/LoginUser", ClientIP: "10.0.0.1",
+				Outcome: "Unauthenticated", CreatedAt: created,
+			},
+			args: []driver.Value{
+				sql.NullInt64{}, "mallory", "/auth.Auth/LoginUser", sql.NullInt64{}, "10.0.0.1", "",
+				"Unauthenticated", []byte("payload"), created,
+			},
+		},
+		{
+			name:  "ошибка базы",
+			event: &entity.AuditEvent{UserID: 1, Action: "/data.DataService/ListData", Outcome: "OK", CreatedAt: created},
+			args: []driver.Value{
+				sql.NullInt64{Int64: 1, Valid: true}, "", "/data.DataService/ListData", sql.NullInt64{}, "", "", "OK",
+				[]byte("payload"), created,
+			},
+			queryErr:    errors.New("db down"),
+			expectedErr: helper.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			query := mock.ExpectQuery(`WITH head AS \(\s+UPDATE audit_chain_head h\s+` +
+				`SET prev_hash = h.hash, hash = sha256\(h.hash \|\| \$8::bytea\).+INSERT INTO audit_events`).
+				WithArgs(tt.args...)
+			if tt.queryErr != nil {
+				query.WillReturnError(tt.queryErr)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows([]string{"id", "prev_hash", "hash"}).
+					AddRow(10, []byte("prev"), []byte("hash")))
+			}
+
+			err = NewAuditRepository(db, new(mockLogger)).Append(context.Background(), tt.event, []byte("payload"))
+
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expectedErr == nil {
+				assert.Equal(t, int64(10), tt.event.ID)
+				assert.Equal(t, []byte("prev"), tt.event.PrevHash)
+				assert.Equal(t, []byte("hash"), tt.event.Hash)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAudit_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{
+		"id", "user_id", "login", "action", "data_id", "client_ip", "user_agent", "outcome", "created_at",
+	}
+	mock.ExpectQuery(`FROM audit_events\s+WHERE user_id = \$1 AND \(\$2 = 0 OR id < \$2\)\s+ORDER BY id DESC\s+LIMIT \$3`).
+		WithArgs(1, int64(100), 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(99, 1, "", "/data.DataService/GetData", 5, "10.0.0.1", "grpc-go", "OK", created).
+			AddRow(98, 1, "alice", "/auth.Auth/LoginUser", 0, "10.0.0.2", "", "Unauthenticated", created))
+
+	events, err := NewAuditRepository(db, new(mockLogger)).List(context.Background(), 1, 100, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.AuditEvent{
+		{
+			ID: 99, UserID: 1, Action: "/data.DataService/GetData", DataID: 5,
+			ClientIP: "10.0.0.1", UserAgent: "grpc-go", Outcome: "OK", CreatedAt: created,
+		},
+		{
+			ID: 98, UserID: 1, Login: "alice", Action: "/auth.Auth/LoginUser",
+			ClientIP: "10.0.0.2", Outcome: "Unauthenticated", CreatedAt: created,
+		},
+	}, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAudit_ListChain(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{
+		"id", "user_id", "login", "action", "data_id", "client_ip", "user_agent",
+		"outcome", "created_at", "prev_hash", "hash",
+	}
+	mock.ExpectQuery(`FROM audit_events\s+WHERE id > \$1\s+ORDER BY id\s+LIMIT \$2`).
+		WithArgs(int64(4), 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, 0, "", "/auth.Auth/LoginUser", 0, "", "", "OK", created, nil, nil).
+			AddRow(6, 1, "", "/data.DataService/GetData", 5, "", "", "OK", created, []byte("a"), []byte("b")))
+
+	events, err := NewAuditRepository(db, new(mockLogger)).ListChain(context.Background(), 4, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.AuditEvent{
+		{ID: 5, Action: "/auth.Auth/LoginUser", Outcome: "OK", CreatedAt: created},
+		{
+			ID: 6, UserID: 1, Action: "/data.DataService/GetData", DataID: 5, Outcome: "OK", CreatedAt: created,
+			PrevHash: []byte("a"), Hash: []byte("b"),
+		},
+	}, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAudit_ChainHeadAndLastChained(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT hash FROM audit_chain_head WHERE id = 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow([]byte("head")))
+	mock.ExpectQuery(`SELECT id, hash FROM audit_events WHERE hash IS NOT NULL ORDER BY id DESC LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hash"}).AddRow(9, []byte("head")))
+	mock.ExpectQuery(`SELECT id, hash FROM audit_events WHERE hash IS NOT NULL`).
+		WillReturnError(sql.ErrNoRows)
+	repo := NewAuditRepository(db, new(mockLogger))
+
+	head, err := repo.ChainHead(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("head"), head)
+
+	last, err := repo.LastChained(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &entity.AuditEvent{ID: 9, Hash: []byte("head")}, last)
+
+	last, err = repo.LastChained(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, last)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
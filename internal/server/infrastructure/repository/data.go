@@ -3,70 +3,978 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
 	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"github.com/lib/pq"
 )
 
 type dataStorager interface {
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 }
 
 type dataRepository struct {
-	db     dataStorager
-	logger logger.CustomLogger
+	db               dataStorager
+	logger           logger.CustomLogger
+	historyRetention int
 }
 
-// NewDataRepository - конструктор data repo.
-func NewDataRepository(db dataStorager, logger logger.CustomLogger) *dataRepository {
-	return &dataRepository{db: db, logger: logger}
+// NewDataRepository - конструктор data repo. У каждой записи хранится
+// до historyRetention прежних версий; 0 - история не ведётся.
+func NewDataRepository(db dataStorager, logger logger.CustomLogger, historyRetention int) *dataRepository {
+	return &dataRepository{db: db, logger: logger, historyRetention: historyRetention}
 }
 
+// AddData - добавляет запись и увеличивает ревизию пользователя.
+// Если data.VaultID не 0, запись создаётся в общем хранилище.
+// Блокировка строки users упорядочивает изменения одного пользователя,
+// поэтому ревизии его записей фиксируются строго по возрастанию.
+// Папка и теги записи создаются у пользователя data.UserID, если их ещё нет;
+// DO UPDATE нужен, чтобы RETURNING вернул id и уже существующих строк.
+// Вместе с записью сохраняются токены слепого индекса data.SearchTokens.
+// data.Created задаёт время создания, например при импорте; пустое или из
+// будущего заменяется текущим: LEAST пропускает NULL.
 func (r *dataRepository) AddData(ctx context.Context, data *entity.UserData) (int, error) {
 	query := `
-        INSERT INTO user_data (user_id, info_type, info, meta, created)
-        VALUES ($1, $2, $3, $4, NOW())
-        RETURNING id
+        WITH rev AS (
+            UPDATE users SET revision = revision + 1 WHERE id = $1 RETURNING revision
+        ), folder AS (
+            INSERT INTO folders (user_id, path)
+            SELECT $1, $6::text WHERE $6::text <> ''
+            ON CONFLICT (user_id, path) DO UPDATE SET path = EXCLUDED.path
+            RETURNING id
+        ), inserted AS (
+            INSERT INTO user_data (
+                user_id, info_type, info, meta, vault_id, folder_id, favorite, created, updated_at, revision
+            )
+            SELECT $1, $2, $3, $4, $5, (SELECT id FROM folder), $7, LEAST($10::timestamptz, NOW()), NOW(), revision
+            FROM rev
+            RETURNING id
+        ), tagged AS (
+            INSERT INTO tags (user_id, name)
+            SELECT $1, unnest($8::text[])
+            ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+            RETURNING id
+        ), linked AS (
+            INSERT INTO user_data_tags (data_id, tag_id)
+            SELECT inserted.id, tagged.id FROM inserted, tagged
+        ), indexed AS (
+            INSERT INTO user_data_search_tokens (data_id, token)
+            SELECT inserted.id, unnest($9::text[]) FROM inserted
+        )
+        SELECT id FROM inserted
     `
 	var id int
-	err := r.db.QueryRowContext(ctx, query, data.UserID, data.InfoType, data.Info, data.Meta).Scan(&id)
+	err := r.db.QueryRowContext(ctx, query,
+		data.UserID, data.InfoType, data.Info, data.Meta, nullableID(data.VaultID),
+		data.Folder, data.Favorite, pq.Array(data.Tags), tokenArray(data.SearchTokens), nullableTime(data.Created),
+	).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
+// labelColumns - папка, избранное и теги записи user_data для SELECT.
+const labelColumns = `COALESCE((SELECT path FROM folders WHERE folders.id = user_data.folder_id), ''), favorite,
+            ARRAY(
+                SELECT t.name FROM user_data_tags dt JOIN tags t ON t.id = dt.tag_id
+                WHERE dt.data_id = user_data.id ORDER BY t.name
+            )`
+
 func (r *dataRepository) GetDataByID(ctx context.Context, userID, dataID int) (*entity.UserData, error) {
 	query := `
-        SELECT id, user_id, info_type, info, meta, created
+        SELECT id, user_id, COALESCE(vault_id, 0), info_type, info, meta, created, updated_at, revision, version,
+            ` + labelColumns + `
         FROM user_data
-        WHERE id = $1 AND user_id = $2
+        WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
     `
 	row := r.db.QueryRowContext(ctx, query, dataID, userID)
 	data := &entity.UserData{}
-	err := row.Scan(&data.ID, &data.UserID, &data.InfoType, &data.Info, &data.Meta, &data.Created)
+	err := row.Scan(
+		&data.ID, &data.UserID, &data.VaultID, &data.InfoType, &data.Info, &data.Meta,
+		&data.Created, &data.UpdatedAt, &data.Revision, &data.Version,
+		&data.Folder, &data.Favorite, pq.Array(&data.Tags),
+	)
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *dataRepository) UpdateData(ctx context.Context, data *entity.UserData) error {
+// UpdateData - обновляет запись, если её версия совпадает с data.Version,
+// присваивает ей новую ревизию пользователя и возвращает новую версию.
+// Прежнее содержимое уходит в историю, самые старые версии сверх
+// historyRetention из неё удаляются.
+// Папка, избранное, теги и токены поиска заменяются значениями из data, как в AddData.
+// Если записи нет - helper.ErrDataNotFound, если версия другая - *helper.VersionConflictError.
+func (r *dataRepository) UpdateData(ctx context.Context, data *entity.UserData) (int64, error) {
 	query := `
-        UPDATE user_data
-        SET info_type = $1, info = $2, meta = $3
-        WHERE id = $4 AND user_id = $5
+        WITH rev AS (
+            UPDATE users SET revision = revision + 1
+            WHERE id = $5 AND EXISTS (
+                SELECT 1 FROM user_data WHERE id = $4 AND user_id = $5 AND version = $6 AND deleted_at IS NULL
+            )
+            RETURNING revision
+        ), folder AS (
+            INSERT INTO folders (user_id, path)
+            SELECT $5, $7::text FROM rev WHERE $7::text <> ''
+            ON CONFLICT (user_id, path) DO UPDATE SET path = EXCLUDED.path
+            RETURNING id
+        ), archived AS (
+            INSERT INTO user_data_history (data_id, version, info_type, info, meta, saved_at)
+            SELECT d.id, d.version, d.info_type, d.info, d.meta, COALESCE(d.updated_at, d.created, NOW())
+            FROM user_data d, rev
+            WHERE d.id = $4 AND d.user_id = $5 AND d.version = $6 AND $11 > 0
+        ), pruned AS (
+            DELETE FROM user_data_history
+            WHERE data_id = $4 AND version <= $6 - $11 AND EXISTS (SELECT 1 FROM rev)
+        ), updated AS (
+            UPDATE user_data
+            SET info_type = $1, info = $2, meta = $3, folder_id = (SELECT id FROM folder), favorite = $8,
+                updated_at = NOW(), revision = rev.revision, version = version + 1
+            FROM rev
+            WHERE user_data.id = $4 AND user_data.user_id = $5 AND user_data.version = $6
+            RETURNING user_data.id, user_data.version
+        ), tagged AS (
+            INSERT INTO tags (user_id, name)
+            SELECT $5, unnest($9::text[]) FROM rev
+            ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+            RETURNING id
+        ), untagged AS (
+            DELETE FROM user_data_tags
+            WHERE data_id IN (SELECT id FROM updated) AND tag_id NOT IN (SELECT id FROM tagged)
+        ), linked AS (
+            INSERT INTO user_data_tags (data_id, tag_id)
+            SELECT updated.id, tagged.id FROM updated, tagged
+            ON CONFLICT DO NOTHING
+        ), unindexed AS (
+            DELETE FROM user_data_search_tokens
+            WHERE data_id IN (SELECT id FROM updated) AND token <> ALL($10::text[])
+        ), indexed AS (
+            INSERT INTO user_data_search_tokens (data_id, token)
+            SELECT updated.id, unnest($10::text[]) FROM updated
+            ON CONFLICT DO NOTHING
+        )
+        SELECT version FROM updated
+    `
+	var version int64
+	err := r.db.QueryRowContext(ctx, query,
+		data.InfoType, data.Info, data.Meta, data.ID, data.UserID, data.Version,
+		data.Folder, data.Favorite, pq.Array(data.Tags), tokenArray(data.SearchTokens), r.historyRetention,
+	).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, r.versionMismatch(ctx, data.UserID, data.ID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// DeleteData - переносит запись в корзину, если её версия совпадает с version,
+// и оставляет tombstone с новой ревизией, чтобы запись пропала с других устройств.
+// Содержимое, блоб, токены поиска и история сохраняются до PurgeData или PurgeTrash,
+// а ссылки на запись удаляются сразу. Ошибки несовпадения - как у UpdateData.
+func (r *dataRepository) DeleteData(ctx context.Context, userID, dataID int, version int64) error {
+	query := `
+        WITH rev AS (
+            UPDATE users SET revision = revision + 1
+            WHERE id = $2 AND EXISTS (
+                SELECT 1 FROM user_data WHERE id = $1 AND user_id = $2 AND version = $3 AND deleted_at IS NULL
+            )
+            RETURNING revision
+        ), deleted AS (
+            UPDATE user_data
+            SET deleted_at = NOW(), revision = rev.revision
+            FROM rev
+            WHERE user_data.id = $1 AND user_data.user_id = $2 AND user_data.version = $3
+            RETURNING user_data.id, rev.revision
+        ), unshared AS (
+            DELETE FROM shares WHERE data_id IN (SELECT id FROM deleted)
+        )
+        INSERT INTO user_data_tombstones (user_id, data_id, revision, deleted_at)
+        SELECT $2, id, revision, NOW() FROM deleted
+        ON CONFLICT (user_id, data_id) DO UPDATE
+        SET revision = EXCLUDED.revision, deleted_at = EXCLUDED.deleted_at
     `
-	_, err := r.db.ExecContext(ctx, query, data.InfoType, data.Info, data.Meta, data.ID, data.UserID)
+	res, err := r.db.ExecContext(ctx, query, dataID, userID, version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return r.versionMismatch(ctx, userID, dataID)
+	}
+
+	return nil
+}
+
+// MoveData - переносит запись той же версии в хранилище data.VaultID (0 - в личные
+// данные пользователя data.UserID) и записывает её новое содержимое. ownerID -
+// текущий владелец записи. Перенос из личных данных оставляет tombstone в ленте
+// изменений пользователя, перенос в личные данные снимает его. Токены поиска
+// заменяются: клиент считает их ключом целевого хранилища. История записи
+// удаляется: прежние версии зашифрованы ключом исходного хранилища.
+// Ошибки несовпадения - как у UpdateData.
+func (r *dataRepository) MoveData(ctx context.Context, ownerID int, data *entity.UserData) (int64, error) {
+	query := `
+        WITH rev AS (
+            UPDATE users SET revision = revision + 1
+            WHERE id = $6 AND EXISTS (
+                SELECT 1 FROM user_data WHERE id = $4 AND user_id = $5 AND version = $7 AND deleted_at IS NULL
+            )
+            RETURNING revision
+        ), moved AS (
+            UPDATE user_data
+            SET info_type = $1, info = $2, meta = $3, user_id = $6, vault_id = $8,
+                updated_at = NOW(), revision = rev.revision, version = version + 1
+            FROM rev
+            WHERE user_data.id = $4 AND user_data.user_id = $5 AND user_data.version = $7
+            RETURNING user_data.id, user_data.version, user_data.vault_id, rev.revision
+        ), hidden AS (
+            INSERT INTO user_data_tombstones (user_id, data_id, revision, deleted_at)
+            SELECT $6, id, revision, NOW() FROM moved WHERE vault_id IS NOT NULL
+            ON CONFLICT (user_id, data_id) DO UPDATE
+            SET revision = EXCLUDED.revision, deleted_at = EXCLUDED.deleted_at
+        ), restored AS (
+            DELETE FROM user_data_tombstones
+            WHERE user_id = $6 AND data_id IN (SELECT id FROM moved WHERE vault_id IS NULL)
+        ), unindexed AS (
+            DELETE FROM user_data_search_tokens
+            WHERE data_id IN (SELECT id FROM moved) AND token <> ALL($9::text[])
+        ), indexed AS (
+            INSERT INTO user_data_search_tokens (data_id, token)
+            SELECT moved.id, unnest($9::text[]) FROM moved
+            ON CONFLICT DO NOTHING
+        ), forgotten AS (
+            DELETE FROM user_data_history WHERE data_id IN (SELECT id FROM moved)
+        )
+        SELECT version FROM moved
+    `
+	var version int64
+	err := r.db.QueryRowContext(ctx, query,
+		data.InfoType, data.Info, data.Meta, data.ID, ownerID, data.UserID, data.Version, nullableID(data.VaultID),
+		tokenArray(data.SearchTokens),
+	).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, r.versionMismatch(ctx, ownerID, data.ID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// versionMismatch - объясняет, почему условное изменение не применилось:
+// записи нет (или она в корзине) или у неё другая версия.
+func (r *dataRepository) versionMismatch(ctx context.Context, userID, dataID int) error {
+	query := `
+        SELECT version
+        FROM user_data
+        WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    `
+	var current int64
+	err := r.db.QueryRowContext(ctx, query, dataID, userID).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return helper.ErrDataNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return &helper.VersionConflictError{Current: current}
+}
+
+// ListData - возвращает заголовки личных записей пользователя или, если задан
+// filter.VaultID, записей общего хранилища (без поля info), отсортированные
+// по (created, id). Если after не nil, выборка начинается со следующей за ним записи.
+// Записи из корзины не выводятся.
+func (r *dataRepository) ListData(
+	ctx context.Context,
+	userID int,
+	filter *entity.DataFilter,
+	after *entity.DataCursor,
+) ([]*entity.UserData, error) {
+	conditions := []string{"user_id = $1 AND vault_id IS NULL", "deleted_at IS NULL"}
+	args := []any{userID}
+	if filter.VaultID != 0 {
+		conditions = []string{"vault_id = $1", "deleted_at IS NULL"}
+		args = []any{filter.VaultID}
+	}
+
+	addCondition := func(format string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(format, placeholders...))
+	}
+
+	if filter.InfoType != "" {
+		addCondition("info_type = $%d", filter.InfoType)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		addCondition("created >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		addCondition("created < $%d", filter.CreatedTo)
+	}
+	if filter.Tag != "" {
+		addCondition(`EXISTS (
+            SELECT 1 FROM user_data_tags dt JOIN tags t ON t.id = dt.tag_id
+            WHERE dt.data_id = user_data.id AND t.name = $%d
+        )`, filter.Tag)
+	}
+	switch filter.Folder {
+	case "":
+	case entity.RootFolder:
+		conditions = append(conditions, "folder_id IS NULL")
+	default:
+		addCondition("folder_id IN (SELECT id FROM folders WHERE path = $%d)", filter.Folder)
+	}
+	if filter.FavoritesOnly {
+		conditions = append(conditions, "favorite")
+	}
+	// Отдельное условие на каждый токен, чтобы каждый искался по первичному ключу индекса.
+	for _, token := range filter.SearchTokens {
+		addCondition("id IN (SELECT data_id FROM user_data_search_tokens WHERE token = $%d)", token)
+	}
+
+	order := "ASC"
+	cursorOp := ">"
+	if filter.Descending {
+		order = "DESC"
+		cursorOp = "<"
+	}
+	if after != nil {
+		addCondition("(created, id) "+cursorOp+" ($%d, $%d)", after.Created, after.ID)
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+        SELECT id, user_id, info_type, meta, created, %s
+        FROM user_data
+        WHERE %s
+        ORDER BY created %s, id %s
+        LIMIT $%d
+    `, labelColumns, strings.Join(conditions, " AND "), order, order, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.UserData, 0, filter.Limit)
+	for rows.Next() {
+		data := &entity.UserData{}
+		err := rows.Scan(
+			&data.ID, &data.UserID, &data.InfoType, &data.Meta, &data.Created,
+			&data.Folder, &data.Favorite, pq.Array(&data.Tags),
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// IndexData - заменяет токены слепого индекса записи пользователя userID,
+// не меняя её версию и ревизию. Если записи нет - helper.ErrDataNotFound.
+func (r *dataRepository) IndexData(ctx context.Context, userID, dataID int, tokens []string) error {
+	query := `
+        WITH target AS (
+            SELECT id FROM user_data WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+        ), unindexed AS (
+            DELETE FROM user_data_search_tokens
+            WHERE data_id IN (SELECT id FROM target) AND token <> ALL($3::text[])
+        ), indexed AS (
+            INSERT INTO user_data_search_tokens (data_id, token)
+            SELECT target.id, unnest($3::text[]) FROM target
+            ON CONFLICT DO NOTHING
+        )
+        SELECT id FROM target
+    `
+	var id int
+	err := r.db.QueryRowContext(ctx, query, dataID, userID, tokenArray(tokens)).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return helper.ErrDataNotFound
+	}
 	return err
 }
 
-func (r *dataRepository) DeleteData(ctx context.Context, userID, dataID int) error {
+// tokenArray - токены поиска как массив PostgreSQL. nil стал бы NULL,
+// и условие token <> ALL(...) не удалило бы старые токены.
+func tokenArray(tokens []string) any {
+	if tokens == nil {
+		tokens = []string{}
+	}
+	return pq.Array(tokens)
+}
+
+// ListForRotation - возвращает записи, у которых info или meta зашифрованы
+// не ключом с префиксом activePrefix. Уже перешифрованные записи в выборку
+// не попадают, поэтому прерванную ротацию можно просто запустить заново.
+func (r *dataRepository) ListForRotation(
+	ctx context.Context, activePrefix string, afterID, limit int,
+) ([]*entity.UserData, error) {
+	query := `
+        SELECT id, info, meta
+        FROM user_data
+        WHERE id > $1 AND (left(info, length($2)) <> $2 OR left(meta, length($2)) <> $2)
+        ORDER BY id
+        LIMIT $3
+    `
+	rows, err := r.db.QueryContext(ctx, query, afterID, activePrefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.UserData, 0, limit)
+	for rows.Next() {
+		data := &entity.UserData{}
+		if err := rows.Scan(&data.ID, &data.Info, &data.Meta); err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReplaceEncrypted - записывает перешифрованные info и meta, только если
+// запись не изменилась с момента чтения. Возвращает false, если запись
+// успели изменить или удалить.
+func (r *dataRepository) ReplaceEncrypted(ctx context.Context, old, updated *entity.UserData) (bool, error) {
+	query := `
+        UPDATE user_data
+        SET info = $1, meta = $2
+        WHERE id = $3 AND info = $4 AND meta = $5
+    `
+	res, err := r.db.ExecContext(ctx, query, updated.Info, updated.Meta, old.ID, old.Info, old.Meta)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// AddBinary - одним запросом создаёт запись user_data, привязанный к ней блоб
+// и токены поиска. data.Created - как в AddData.
+func (r *dataRepository) AddBinary(ctx context.Context, data *entity.UserData, blob *entity.BinaryBlob) (int, error) {
+	query := `
+        WITH rev AS (
+            UPDATE users SET revision = revision + 1 WHERE id = $1 RETURNING revision
+        ), inserted AS (
+            INSERT INTO user_data (user_id, info_type, info, meta, created, updated_at, revision)
+            SELECT $1, $2, $3, $4, LEAST($9::timestamptz, NOW()), NOW(), revision FROM rev
+            RETURNING id
+        ), indexed AS (
+            INSERT INTO user_data_search_tokens (data_id, token)
+            SELECT inserted.id, unnest($8::text[]) FROM inserted
+        )
+        INSERT INTO user_blobs (data_id, blob_key, size, sha256)
+        SELECT id, $5, $6, $7 FROM inserted
+        RETURNING data_id
+    `
+	var id int
+	err := r.db.QueryRowContext(ctx, query,
+		data.UserID, data.InfoType, data.Info, data.Meta, blob.Key, blob.Size, blob.SHA256, tokenArray(data.SearchTokens),
+		nullableTime(data.Created),
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetBinary - возвращает запись пользователя вместе с описанием её блоба.
+func (r *dataRepository) GetBinary(ctx context.Context, userID, dataID int) (*entity.UserData, *entity.BinaryBlob, error) {
+	query := `
+        SELECT d.id, d.user_id, d.info_type, d.info, d.meta, d.created, b.blob_key, b.size, b.sha256
+        FROM user_data d
+        JOIN user_blobs b ON b.data_id = d.id
+        WHERE d.id = $1 AND d.user_id = $2 AND d.deleted_at IS NULL
+    `
+	data := &entity.UserData{}
+	blob := &entity.BinaryBlob{}
+	err := r.db.QueryRowContext(ctx, query, dataID, userID).Scan(
+		&data.ID, &data.UserID, &data.InfoType, &data.Info, &data.Meta, &data.Created,
+		&blob.Key, &blob.Size, &blob.SHA256,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	blob.DataID = data.ID
+
+	return data, blob, nil
+}
+
+// BlobKey - возвращает ключ блоба записи или пустую строку, если блоба нет.
+func (r *dataRepository) BlobKey(ctx context.Context, userID, dataID int) (string, error) {
+	query := `
+        SELECT b.blob_key
+        FROM user_blobs b
+        JOIN user_data d ON d.id = b.data_id
+        WHERE d.id = $1 AND d.user_id = $2
+    `
+	var key string
+	err := r.db.QueryRowContext(ctx, query, dataID, userID).Scan(&key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// ListChangedData - возвращает личные записи пользователя, изменённые после ревизии since,
+// в порядке возрастания ревизии. Записи общих хранилищ в ленту изменений не попадают.
+func (r *dataRepository) ListChangedData(
+	ctx context.Context, userID int, since int64, limit int,
+) ([]*entity.UserData, error) {
+	query := `
+        SELECT id, user_id, info_type, info, meta, created, updated_at, revision, version,
+            ` + labelColumns + `
+        FROM user_data
+        WHERE user_id = $1 AND vault_id IS NULL AND deleted_at IS NULL AND revision > $2
+        ORDER BY revision
+        LIMIT $3
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.UserData, 0, limit)
+	for rows.Next() {
+		data := &entity.UserData{}
+		err := rows.Scan(
+			&data.ID, &data.UserID, &data.InfoType, &data.Info, &data.Meta,
+			&data.Created, &data.UpdatedAt, &data.Revision, &data.Version,
+			&data.Folder, &data.Favorite, pq.Array(&data.Tags),
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListTombstones - возвращает удаления после ревизии since в порядке возрастания ревизии.
+func (r *dataRepository) ListTombstones(
+	ctx context.Context, userID int, since int64, limit int,
+) ([]*entity.Tombstone, error) {
+	query := `
+        SELECT data_id, revision, deleted_at
+        FROM user_data_tombstones
+        WHERE user_id = $1 AND revision > $2
+        ORDER BY revision
+        LIMIT $3
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.Tombstone, 0, limit)
+	for rows.Next() {
+		tombstone := &entity.Tombstone{}
+		if err := rows.Scan(&tombstone.DataID, &tombstone.Revision, &tombstone.DeletedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, tombstone)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// nullableID - 0 означает отсутствие ссылки и записывается как NULL.
+func nullableID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// nullableTime - нулевое время означает «не задано» и записывается как NULL.
+func nullableTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// ListFolders - пути папок, в которых лежат личные записи пользователя, по алфавиту.
+func (r *dataRepository) ListFolders(ctx context.Context, userID int) ([]string, error) {
+	query := `
+        SELECT DISTINCT f.path
+        FROM folders f
+        JOIN user_data d ON d.folder_id = f.id
+        WHERE f.user_id = $1 AND d.user_id = $1 AND d.vault_id IS NULL AND d.deleted_at IS NULL
+        ORDER BY f.path
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	var folders []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		folders = append(folders, path)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// ListTrash - записи в корзине: личные записи пользователя или, если vaultID
+// не 0, записи общего хранилища, от недавно удалённых к давним.
+func (r *dataRepository) ListTrash(ctx context.Context, userID, vaultID int) ([]*entity.UserData, error) {
+	condition, arg := "user_id = $1 AND vault_id IS NULL", userID
+	if vaultID != 0 {
+		condition, arg = "vault_id = $1", vaultID
+	}
+	query := `
+        SELECT id, user_id, info_type, meta, created, deleted_at, ` + labelColumns + `
+        FROM user_data
+        WHERE ` + condition + ` AND deleted_at IS NOT NULL
+        ORDER BY deleted_at DESC, id DESC
+    `
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.UserData, 0)
+	for rows.Next() {
+		data := &entity.UserData{}
+		err := rows.Scan(
+			&data.ID, &data.UserID, &data.InfoType, &data.Meta, &data.Created, &data.DeletedAt,
+			&data.Folder, &data.Favorite, pq.Array(&data.Tags),
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RestoreData - возвращает запись пользователя userID из корзины с новой
+// ревизией и версией. Tombstone личной записи снимается, и она снова
+// приходит на устройства в ленте изменений. Если записи в корзине нет -
+// helper.ErrDataNotFound.
+func (r *dataRepository) RestoreData(ctx context.Context, userID, dataID int) (int64, error) {
+	query := `
+        WITH rev AS (
+            UPDATE users SET revision = revision + 1
+            WHERE id = $2 AND EXISTS (
+                SELECT 1 FROM user_data WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+            )
+            RETURNING revision
+        ), restored AS (
+            UPDATE user_data
+            SET deleted_at = NULL, updated_at = NOW(), revision = rev.revision, version = version + 1
+            FROM rev
+            WHERE user_data.id = $1 AND user_data.user_id = $2
+            RETURNING user_data.id, user_data.version, user_data.vault_id
+        ), untombstoned AS (
+            DELETE FROM user_data_tombstones
+            WHERE user_id = $2 AND data_id IN (SELECT id FROM restored WHERE vault_id IS NULL)
+        )
+        SELECT version FROM restored
+    `
+	var version int64
+	err := r.db.QueryRowContext(ctx, query, dataID, userID).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, helper.ErrDataNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// PurgeData - окончательно удаляет запись пользователя userID из корзины
+// вместе с токенами поиска и историей. Tombstone остаётся для устройств,
+// которые ещё не получили удаление. Если записи в корзине нет -
+// helper.ErrDataNotFound.
+func (r *dataRepository) PurgeData(ctx context.Context, userID, dataID int) error {
 	query := `
         DELETE FROM user_data
-        WHERE id = $1 AND user_id = $2
+        WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
     `
-	_, err := r.db.ExecContext(ctx, query, dataID, userID)
-	return err
+	res, err := r.db.ExecContext(ctx, query, dataID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return helper.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// PurgeTrash - окончательно удаляет до limit записей, попавших в корзину
+// раньше before, и возвращает их число и ключи их блобов. Блобы нужно
+// удалить из хранилища файлов отдельно. SKIP LOCKED не даёт нескольким
+// экземплярам сервера ждать друг друга на одних и тех же строках.
+func (r *dataRepository) PurgeTrash(ctx context.Context, before time.Time, limit int) (int, []string, error) {
+	query := `
+        WITH expired AS (
+            SELECT id FROM user_data
+            WHERE deleted_at < $1
+            ORDER BY deleted_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        ), blobs AS (
+            SELECT b.data_id, b.blob_key FROM user_blobs b JOIN expired e ON e.id = b.data_id
+        ), purged AS (
+            DELETE FROM user_data WHERE id IN (SELECT id FROM expired)
+            RETURNING id
+        )
+        SELECT COALESCE(blobs.blob_key, '')
+        FROM purged
+        LEFT JOIN blobs ON blobs.data_id = purged.id
+    `
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	purged := 0
+	var blobKeys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return purged, blobKeys, err
+		}
+		purged++
+		if key != "" {
+			blobKeys = append(blobKeys, key)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return purged, blobKeys, err
+	}
+
+	return purged, blobKeys, nil
+}
+
+// ListVersions - прежние версии записи пользователя userID от новых к старым.
+// Запись без истории или чужая запись дают пустой список.
+func (r *dataRepository) ListVersions(ctx context.Context, userID, dataID int) ([]*entity.DataVersion, error) {
+	query := `
+        SELECT h.version, h.info_type, h.info, h.meta, h.saved_at
+        FROM user_data_history h
+        JOIN user_data d ON d.id = h.data_id
+        WHERE h.data_id = $1 AND d.user_id = $2
+        ORDER BY h.version DESC
+    `
+	rows, err := r.db.QueryContext(ctx, query, dataID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	versions := make([]*entity.DataVersion, 0)
+	for rows.Next() {
+		v := &entity.DataVersion{DataID: dataID}
+		if err := rows.Scan(&v.Version, &v.InfoType, &v.Info, &v.Meta, &v.SavedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// RestoreVersion - заменяет содержимое записи версией version из истории
+// и возвращает новую версию записи. Текущее содержимое уходит в историю,
+// как при UpdateData. Папка, теги и избранное не меняются.
+// Если такой версии в истории нет - helper.ErrVersionNotFound.
+func (r *dataRepository) RestoreVersion(ctx context.Context, userID, dataID int, version int64) (int64, error) {
+	query := `
+        WITH source AS (
+            SELECT h.info_type, h.info, h.meta
+            FROM user_data_history h
+            JOIN user_data d ON d.id = h.data_id
+            WHERE h.data_id = $1 AND d.user_id = $2 AND h.version = $3 AND d.deleted_at IS NULL
+        ), rev AS (
+            UPDATE users SET revision = revision + 1
+            WHERE id = $2 AND EXISTS (SELECT 1 FROM source)
+            RETURNING revision
+        ), archived AS (
+            INSERT INTO user_data_history (data_id, version, info_type, info, meta, saved_at)
+            SELECT d.id, d.version, d.info_type, d.info, d.meta, COALESCE(d.updated_at, d.created, NOW())
+            FROM user_data d, rev
+            WHERE d.id = $1 AND $4 > 0
+        ), pruned AS (
+            DELETE FROM user_data_history h
+            USING user_data d
+            WHERE h.data_id = $1 AND d.id = $1 AND h.version <= d.version - $4 AND EXISTS (SELECT 1 FROM rev)
+        ), restored AS (
+            UPDATE user_data
+            SET info_type = source.info_type, info = source.info, meta = source.meta,
+                updated_at = NOW(), revision = rev.revision, version = version + 1
+            FROM rev, source
+            WHERE user_data.id = $1
+            RETURNING user_data.version
+        )
+        SELECT version FROM restored
+    `
+	var restored int64
+	err := r.db.QueryRowContext(ctx, query, dataID, userID, version, r.historyRetention).Scan(&restored)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, helper.ErrVersionNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return restored, nil
+}
+
+// ListVersionsForRotation - версии из истории, у которых info или meta
+// зашифрованы не ключом с префиксом activePrefix, как ListForRotation.
+func (r *dataRepository) ListVersionsForRotation(
+	ctx context.Context, activePrefix string, afterID, limit int,
+) ([]*entity.DataVersion, error) {
+	query := `
+        SELECT id, info, meta
+        FROM user_data_history
+        WHERE id > $1 AND (left(info, length($2)) <> $2 OR left(meta, length($2)) <> $2)
+        ORDER BY id
+        LIMIT $3
+    `
+	rows, err := r.db.QueryContext(ctx, query, afterID, activePrefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.DataVersion, 0, limit)
+	for rows.Next() {
+		v := &entity.DataVersion{}
+		if err := rows.Scan(&v.ID, &v.Info, &v.Meta); err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReplaceEncryptedVersion - записывает перешифрованную версию из истории,
+// если её не успели удалить. Версии не меняются, поэтому проверять
+// содержимое, как в ReplaceEncrypted, не нужно.
+func (r *dataRepository) ReplaceEncryptedVersion(ctx context.Context, updated *entity.DataVersion) (bool, error) {
+	query := `
+        UPDATE user_data_history
+        SET info = $1, meta = $2
+        WHERE id = $3
+    `
+	res, err := r.db.ExecContext(ctx, query, updated.Info, updated.Meta, updated.ID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
 }
@@ -0,0 +1,898 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+// historyRetention - сколько прежних версий записи хранит репозиторий в тестах.
+const historyRetention = 5
+
+func TestDataRepository_ListData(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	from := created.Add(-time.Hour)
+	to := created.Add(time.Hour)
+	after := &entity.DataCursor{Created: from, ID: 3}
+
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(5, 1, "text", "meta1", created, "", false, "{}").
+		AddRow(6, 1, "text", "meta2", created.Add(time.Minute), "work/db", true, "{prod,sql}")
+
+	mock.ExpectQuery(`(?s)SELECT id, user_id, info_type, meta, created, .+\s+FROM user_data\s+`+
+		`WHERE user_id = \$1 AND vault_id IS NULL AND deleted_at IS NULL AND info_type = \$2 AND created >= \$3 AND created < \$4 `+
+		`AND \(created, id\) > \(\$5, \$6\)\s+ORDER BY created ASC, id ASC\s+LIMIT \$7`).
+		WithArgs(1, "text", from, to, from, 3, 3).
+		WillReturnRows(rows)
+
+	items, err := repo.ListData(context.Background(), 1, &entity.DataFilter{
+		InfoType:    "text",
+		CreatedFrom: from,
+		CreatedTo:   to,
+		Limit:       3,
+	}, after)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.UserData{
+		{ID: 5, UserID: 1, InfoType: "text", Meta: "meta1", Created: created, Tags: []string{}},
+		{
+			ID: 6, UserID: 1, InfoType: "text", Meta: "meta2", Created: created.Add(time.Minute),
+			Folder: "work/db", Favorite: true, Tags: []string{"prod", "sql"},
+		},
+	}, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// listColumns - колонки выборки ListData.
+var listColumns = []string{"id", "user_id", "info_type", "meta", "created", "folder", "favorite", "tags"}
+
+func TestDataRepository_ListData_Labels(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    *entity.DataFilter
+		condition string
+		args      []driver.Value
+	}{
+		{
+			name:   "тег",
+			filter: &entity.DataFilter{Limit: 21, Tag: "work"},
+			condition: `AND EXISTS \(\s+SELECT 1 FROM user_data_tags dt JOIN tags t ON t.id = dt.tag_id\s+` +
+				`WHERE dt.data_id = user_data.id AND t.name = \$2\s+\)\s+ORDER BY`,
+			args: []driver.Value{1, "work", 21},
+		},
+		{
+			name:      "папка",
+			filter:    &entity.DataFilter{Limit: 21, Folder: "work/db"},
+			condition: `AND folder_id IN \(SELECT id FROM folders WHERE path = \$2\)\s+ORDER BY`,
+			args:      []driver.Value{1, "work/db", 21},
+		},
+		{
+			name:      "вне папок и избранное",
+			filter:    &entity.DataFilter{Limit: 21, Folder: entity.RootFolder, FavoritesOnly: true},
+			condition: `AND folder_id IS NULL AND favorite\s+ORDER BY`,
+			args:      []driver.Value{1, 21},
+		},
+		{
+			name:   "токены поиска",
+			filter: &entity.DataFilter{Limit: 21, SearchTokens: []string{"t1", "t2"}},
+			condition: `AND id IN \(SELECT data_id FROM user_data_search_tokens WHERE token = \$2\) ` +
+				`AND id IN \(SELECT data_id FROM user_data_search_tokens WHERE token = \$3\)\s+ORDER BY`,
+			args: []driver.Value{1, "t1", "t2", 21},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`WHERE user_id = \$1 AND vault_id IS NULL AND deleted_at IS NULL ` + tt.condition).
+				WithArgs(tt.args...).
+				WillReturnRows(sqlmock.NewRows(listColumns))
+
+			_, err = NewDataRepository(db, new(mockLogger), historyRetention).ListData(context.Background(), 1, tt.filter, nil)
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_IndexData(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokens      []string
+		tokensArg   string
+		rows        *sqlmock.Rows
+		expectedErr error
+	}{
+		{
+			name:      "замена токенов",
+			tokens:    []string{"t1"},
+			tokensArg: `{"t1"}`,
+			rows:      sqlmock.NewRows([]string{"id"}).AddRow(3),
+		},
+		{
+			name:      "без токенов",
+			tokensArg: "{}",
+			rows:      sqlmock.NewRows([]string{"id"}).AddRow(3),
+		},
+		{
+			name:        "записи нет",
+			tokensArg:   "{}",
+			rows:        sqlmock.NewRows([]string{"id"}),
+			expectedErr: helper.ErrDataNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`WITH target AS \(\s+SELECT id FROM user_data WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL\s+\), `+
+				`unindexed AS \(\s+DELETE FROM user_data_search_tokens\s+`+
+				`WHERE data_id IN \(SELECT id FROM target\) AND token <> ALL\(\$3::text\[\]\)\s+\), `+
+				`indexed AS \(.+ON CONFLICT DO NOTHING\s+\)\s+SELECT id FROM target`).
+				WithArgs(3, 1, tt.tokensArg).
+				WillReturnRows(tt.rows)
+
+			err = NewDataRepository(db, new(mockLogger), historyRetention).IndexData(context.Background(), 1, 3, tt.tokens)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_ListData_Descending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+	mock.ExpectQuery(`WHERE user_id = \$1 AND vault_id IS NULL AND deleted_at IS NULL\s+ORDER BY created DESC, id DESC\s+LIMIT \$2`).
+		WithArgs(1, 21).
+		WillReturnRows(sqlmock.NewRows(listColumns))
+
+	items, err := repo.ListData(context.Background(), 1, &entity.DataFilter{Limit: 21, Descending: true}, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_ListData_Vault(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+	mock.ExpectQuery(`WHERE vault_id = \$1 AND deleted_at IS NULL\s+ORDER BY created ASC, id ASC\s+LIMIT \$2`).
+		WithArgs(7, 21).
+		WillReturnRows(sqlmock.NewRows(listColumns))
+
+	_, err = repo.ListData(context.Background(), 1, &entity.DataFilter{Limit: 21, VaultID: 7}, nil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_ListData_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+	mock.ExpectQuery("SELECT id, user_id, info_type, meta, created").
+		WillReturnError(errors.New("database error"))
+
+	items, err := repo.ListData(context.Background(), 1, &entity.DataFilter{Limit: 21}, nil)
+
+	assert.Error(t, err)
+	assert.Nil(t, items)
+}
+
+func TestDataRepository_ListForRotation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+	rows := sqlmock.NewRows([]string{"id", "info", "meta"}).
+		AddRow(11, "v1:info", "v1:meta").
+		AddRow(12, "legacy-info", "v2:meta")
+
+	mock.ExpectQuery(`SELECT id, info, meta\s+FROM user_data\s+`+
+		`WHERE id > \$1 AND \(left\(info, length\(\$2\)\) <> \$2 OR left\(meta, length\(\$2\)\) <> \$2\)\s+`+
+		`ORDER BY id\s+LIMIT \$3`).
+		WithArgs(10, "v2:", 2).
+		WillReturnRows(rows)
+
+	items, err := repo.ListForRotation(context.Background(), "v2:", 10, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.UserData{
+		{ID: 11, Info: "v1:info", Meta: "v1:meta"},
+		{ID: 12, Info: "legacy-info", Meta: "v2:meta"},
+	}, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_ReplaceEncrypted(t *testing.T) {
+	old := &entity.UserData{ID: 11, Info: "v1:info", Meta: "v1:meta"}
+	updated := &entity.UserData{ID: 11, Info: "v2:info", Meta: "v2:meta"}
+
+	tests := []struct {
+		name          string
+		result        error
+		rowsAffected  int64
+		expectedOK    bool
+		expectedError bool
+	}{
+		{name: "запись обновлена", rowsAffected: 1, expectedOK: true},
+		{name: "запись изменилась после чтения", rowsAffected: 0, expectedOK: false},
+		{name: "ошибка базы", result: errors.New("db error"), expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+			exec := mock.ExpectExec(`UPDATE user_data\s+SET info = \$1, meta = \$2\s+`+
+				`WHERE id = \$3 AND info = \$4 AND meta = \$5`).
+				WithArgs("v2:info", "v2:meta", 11, "v1:info", "v1:meta")
+			if tt.result != nil {
+				exec.WillReturnError(tt.result)
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			}
+
+			ok, err := repo.ReplaceEncrypted(context.Background(), old, updated)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_AddBinary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+	data := &entity.UserData{UserID: 1, InfoType: "binary", Info: "info", Meta: "meta", SearchTokens: []string{"t1"}}
+	blob := &entity.BinaryBlob{Key: "abc", Size: 42, SHA256: "hash"}
+
+	mock.ExpectQuery(`WITH rev AS \(\s+UPDATE users SET revision = revision \+ 1 WHERE id = \$1 RETURNING revision\s+\), `+
+		`inserted AS \(\s+INSERT INTO user_data .+FROM rev\s+RETURNING id\s+\), `+
+		`indexed AS \(\s+INSERT INTO user_data_search_tokens \(data_id, token\)\s+`+
+		`SELECT inserted.id, unnest\(\$8::text\[\]\) FROM inserted\s+\)\s+`+
+		`INSERT INTO user_blobs \(data_id, blob_key, size, sha256\)\s+SELECT id, \$5, \$6, \$7 FROM inserted`).
+		WithArgs(1, "binary", "info", "meta", "abc", int64(42), "hash", `{"t1"}`, sql.NullTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"data_id"}).AddRow(7))
+
+	id, err := repo.AddBinary(context.Background(), data, blob)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_GetBinary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM user_data d\s+JOIN user_blobs b ON b.data_id = d.id\s+WHERE d.id = \$1 AND d.user_id = \$2`).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "info_type", "info", "meta", "created", "blob_key", "size", "sha256",
+		}).AddRow(7, 1, "binary", "info", "meta", created, "abc", 42, "hash"))
+
+	data, blob, err := repo.GetBinary(context.Background(), 1, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.UserData{
+		ID: 7, UserID: 1, InfoType: "binary", Info: "info", Meta: "meta", Created: created,
+	}, data)
+	assert.Equal(t, &entity.BinaryBlob{DataID: 7, Key: "abc", Size: 42, SHA256: "hash"}, blob)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_BlobKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		queryErr    error
+		expectedKey string
+		expectedErr bool
+	}{
+		{name: "блоб есть", rows: sqlmock.NewRows([]string{"blob_key"}).AddRow("abc"), expectedKey: "abc"},
+		{name: "блоба нет", rows: sqlmock.NewRows([]string{"blob_key"}), expectedKey: ""},
+		{name: "ошибка базы", queryErr: errors.New("db error"), expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+			query := mock.ExpectQuery(`SELECT b.blob_key\s+FROM user_blobs b`).WithArgs(7, 1)
+			if tt.queryErr != nil {
+				query.WillReturnError(tt.queryErr)
+			} else {
+				query.WillReturnRows(tt.rows)
+			}
+
+			key, err := repo.BlobKey(context.Background(), 1, 7)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedKey, key)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_AddData(t *testing.T) {
+	created := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+	mock.ExpectQuery(`WITH rev AS \(\s+UPDATE users SET revision = revision \+ 1 WHERE id = \$1 RETURNING revision\s+\), `+
+		`folder AS \(\s+INSERT INTO folders \(user_id, path\)\s+SELECT \$1, \$6::text WHERE \$6::text <> ''\s+`+
+		`ON CONFLICT \(user_id, path\) DO UPDATE SET path = EXCLUDED.path\s+RETURNING id\s+\), inserted AS \(\s+`+
+		`INSERT INTO user_data \(\s+user_id, info_type, info, meta, vault_id, folder_id, favorite, created, updated_at, `+
+		`revision\s+\)\s+SELECT \$1, \$2, \$3, \$4, \$5, \(SELECT id FROM folder\), \$7, `+
+		`LEAST\(\$10::timestamptz, NOW\(\)\), NOW\(\), revision\s+FROM rev\s+RETURNING id\s+\), tagged AS \(\s+INSERT INTO tags \(user_id, name\)\s+`+
+		`SELECT \$1, unnest\(\$8::text\[\]\)\s+ON CONFLICT \(user_id, name\) DO UPDATE SET name = EXCLUDED.name\s+`+
+		`RETURNING id\s+\), linked AS \(\s+INSERT INTO user_data_tags \(data_id, tag_id\)\s+`+
+		`SELECT inserted.id, tagged.id FROM inserted, tagged\s+\), indexed AS \(\s+`+
+		`INSERT INTO user_data_search_tokens \(data_id, token\)\s+SELECT inserted.id, unnest\(\$9::text\[\]\) `+
+		`FROM inserted\s+\)\s+SELECT id FROM inserted`).
+		WithArgs(1, "text", "info", "meta", sql.NullInt64{}, "work", true, `{"db","prod"}`, `{"t1","t2"}`,
+			sql.NullTime{Time: created, Valid: true}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	id, err := repo.AddData(context.Background(), &entity.UserData{
+		UserID: 1, InfoType: "text", Info: "info", Meta: "meta", Created: created,
+		Folder: "work", Favorite: true, Tags: []string{"db", "prod"}, SearchTokens: []string{"t1", "t2"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_UpdateData(t *testing.T) {
+	tests := []struct {
+		name            string
+		updateRows      *sqlmock.Rows
+		versionRows     *sqlmock.Rows
+		expectedVersion int64
+		expectedErr     error
+	}{
+		{
+			name:            "версия совпала",
+			updateRows:      sqlmock.NewRows([]string{"version"}).AddRow(3),
+			expectedVersion: 3,
+		},
+		{
+			name:        "версия устарела",
+			updateRows:  sqlmock.NewRows([]string{"version"}),
+			versionRows: sqlmock.NewRows([]string{"version"}).AddRow(5),
+			expectedErr: &helper.VersionConflictError{Current: 5},
+		},
+		{
+			name:        "записи нет",
+			updateRows:  sqlmock.NewRows([]string{"version"}),
+			versionRows: sqlmock.NewRows([]string{"version"}),
+			expectedErr: helper.ErrDataNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+			mock.ExpectQuery(`WITH rev AS \(\s+UPDATE users SET revision = revision \+ 1\s+`+
+				`WHERE id = \$5 AND EXISTS \(\s+SELECT 1 FROM user_data WHERE id = \$4 AND user_id = \$5 AND version = \$6 AND deleted_at IS NULL\s+\)\s+`+
+				`RETURNING revision\s+\), folder AS \(\s+INSERT INTO folders \(user_id, path\)\s+`+
+				`SELECT \$5, \$7::text FROM rev WHERE \$7::text <> ''.+`+
+				`\), archived AS \(\s+INSERT INTO user_data_history \(data_id, version, info_type, info, meta, saved_at\)\s+`+
+				`SELECT d.id, d.version, d.info_type, d.info, d.meta, COALESCE\(d.updated_at, d.created, NOW\(\)\)\s+`+
+				`FROM user_data d, rev\s+WHERE d.id = \$4 AND d.user_id = \$5 AND d.version = \$6 AND \$11 > 0\s+\), `+
+				`pruned AS \(\s+DELETE FROM user_data_history\s+`+
+				`WHERE data_id = \$4 AND version <= \$6 - \$11 AND EXISTS \(SELECT 1 FROM rev\)\s+`+
+				`\), updated AS \(\s+UPDATE user_data\s+SET info_type = \$1, info = \$2, meta = \$3, `+
+				`folder_id = \(SELECT id FROM folder\), favorite = \$8,\s+`+
+				`updated_at = NOW\(\), revision = rev.revision, version = version \+ 1\s+FROM rev\s+`+
+				`WHERE user_data.id = \$4 AND user_data.user_id = \$5 AND user_data.version = \$6\s+`+
+				`RETURNING user_data.id, user_data.version\s+\), tagged AS \(\s+INSERT INTO tags \(user_id, name\)\s+`+
+				`SELECT \$5, unnest\(\$9::text\[\]\) FROM rev.+\), untagged AS \(\s+DELETE FROM user_data_tags\s+`+
+				`WHERE data_id IN \(SELECT id FROM updated\) AND tag_id NOT IN \(SELECT id FROM tagged\)\s+\), `+
+				`linked AS \(.+ON CONFLICT DO NOTHING\s+\), unindexed AS \(\s+DELETE FROM user_data_search_tokens\s+`+
+				`WHERE data_id IN \(SELECT id FROM updated\) AND token <> ALL\(\$10::text\[\]\)\s+\), `+
+				`indexed AS \(.+ON CONFLICT DO NOTHING\s+\)\s+SELECT version FROM updated`).
+				WithArgs("text", "info", "meta", 3, 1, int64(2), "", false, `{"work"}`, "{}", historyRetention).
+				WillReturnRows(tt.updateRows)
+			if tt.versionRows != nil {
+				mock.ExpectQuery(`SELECT version\s+FROM user_data\s+WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
+					WithArgs(3, 1).
+					WillReturnRows(tt.versionRows)
+			}
+
+			version, err := repo.UpdateData(context.Background(), &entity.UserData{
+				ID: 3, UserID: 1, InfoType: "text", Info: "info", Meta: "meta", Version: 2, Tags: []string{"work"},
+			})
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedVersion, version)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_DeleteData(t *testing.T) {
+	tests := []struct {
+		name        string
+		affected    int64
+		versionRows *sqlmock.Rows
+		expectedErr error
+	}{
+		{name: "версия совпала", affected: 1},
+		{
+			name:        "версия устарела",
+			versionRows: sqlmock.NewRows([]string{"version"}).AddRow(4),
+			expectedErr: &helper.VersionConflictError{Current: 4},
+		},
+		{
+			name:        "записи нет",
+			versionRows: sqlmock.NewRows([]string{"version"}),
+			expectedErr: helper.ErrDataNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+			mock.ExpectExec(`WITH rev AS \(.+version = \$3 AND deleted_at IS NULL.+\), deleted AS \(\s+UPDATE user_data\s+`+
+				`SET deleted_at = NOW\(\), revision = rev.revision\s+FROM rev\s+`+
+				`WHERE user_data.id = \$1 AND user_data.user_id = \$2 AND user_data.version = \$3\s+`+
+				`RETURNING user_data.id, rev.revision\s+\), unshared AS \(\s+`+
+				`DELETE FROM shares WHERE data_id IN \(SELECT id FROM deleted\)\s+\)\s+`+
+				`INSERT INTO user_data_tombstones \(user_id, data_id, revision, deleted_at\)\s+`+
+				`SELECT \$2, id, revision, NOW\(\) FROM deleted\s+ON CONFLICT \(user_id, data_id\) DO UPDATE`).
+				WithArgs(3, 1, int64(2)).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			if tt.versionRows != nil {
+				mock.ExpectQuery(`SELECT version\s+FROM user_data`).
+					WithArgs(3, 1).
+					WillReturnRows(tt.versionRows)
+			}
+
+			err = repo.DeleteData(context.Background(), 1, 3, 2)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_MoveData(t *testing.T) {
+	tests := []struct {
+		name        string
+		vaultID     int
+		moveRows    *sqlmock.Rows
+		versionRows *sqlmock.Rows
+		expectedErr error
+	}{
+		{
+			name:     "в хранилище",
+			vaultID:  7,
+			moveRows: sqlmock.NewRows([]string{"version"}).AddRow(3),
+		},
+		{
+			name:     "в личные данные",
+			moveRows: sqlmock.NewRows([]string{"version"}).AddRow(3),
+		},
+		{
+			name:        "версия устарела",
+			vaultID:     7,
+			moveRows:    sqlmock.NewRows([]string{"version"}),
+			versionRows: sqlmock.NewRows([]string{"version"}).AddRow(5),
+			expectedErr: &helper.VersionConflictError{Current: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+			mock.ExpectQuery(`WITH rev AS \(.+\), moved AS \(\s+UPDATE user_data\s+`+
+				`SET info_type = \$1, info = \$2, meta = \$3, user_id = \$6, vault_id = \$8,.+`+
+				`\), hidden AS \(\s+INSERT INTO user_data_tombstones.+WHERE vault_id IS NOT NULL.+`+
+				`\), restored AS \(\s+DELETE FROM user_data_tombstones.+\), unindexed AS \(\s+`+
+				`DELETE FROM user_data_search_tokens.+\), forgotten AS \(\s+`+
+				`DELETE FROM user_data_history WHERE data_id IN \(SELECT id FROM moved\)\s+\)\s+SELECT version FROM moved`).
+				WithArgs("text", "info", "meta", 3, 2, 1, int64(2), nullableID(tt.vaultID), `{"t1"}`).
+				WillReturnRows(tt.moveRows)
+			if tt.versionRows != nil {
+				mock.ExpectQuery(`SELECT version\s+FROM user_data`).
+					WithArgs(3, 2).
+					WillReturnRows(tt.versionRows)
+			}
+
+			version, err := repo.MoveData(context.Background(), 2, &entity.UserData{
+				ID: 3, UserID: 1, VaultID: tt.vaultID, InfoType: "text", Info: "info", Meta: "meta", Version: 2,
+				SearchTokens: []string{"t1"},
+			})
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), version)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_ListChangedData(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+
+	mock.ExpectQuery(`(?s)SELECT id, user_id, info_type, info, meta, created, updated_at, revision, version,.+`+
+		`FROM user_data\s+WHERE user_id = \$1 AND vault_id IS NULL AND deleted_at IS NULL AND revision > \$2\s+ORDER BY revision\s+LIMIT \$3`).
+		WithArgs(1, int64(4), 10).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "info_type", "info", "meta", "created", "updated_at", "revision", "version",
+			"folder", "favorite", "tags",
+		}).AddRow(3, 1, "text", "info", "meta", created, updated, 5, 2, "work", false, "{db}"))
+
+	items, err := repo.ListChangedData(context.Background(), 1, 4, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.UserData{{
+		ID: 3, UserID: 1, InfoType: "text", Info: "info", Meta: "meta",
+		Created: created, UpdatedAt: updated, Revision: 5, Version: 2, Folder: "work", Tags: []string{"db"},
+	}}, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_ListTombstones(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+	deleted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT data_id, revision, deleted_at\s+FROM user_data_tombstones\s+`+
+		`WHERE user_id = \$1 AND revision > \$2\s+ORDER BY revision\s+LIMIT \$3`).
+		WithArgs(1, int64(4), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"data_id", "revision", "deleted_at"}).AddRow(2, 6, deleted))
+
+	tombstones, err := repo.ListTombstones(context.Background(), 1, 4, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.Tombstone{{DataID: 2, Revision: 6, DeletedAt: deleted}}, tombstones)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_ListFolders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT DISTINCT f.path\s+FROM folders f\s+JOIN user_data d ON d.folder_id = f.id\s+` +
+		`WHERE f.user_id = \$1 AND d.user_id = \$1 AND d.vault_id IS NULL AND d.deleted_at IS NULL\s+ORDER BY f.path`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("home").AddRow("work/db"))
+
+	folders, err := NewDataRepository(db, new(mockLogger), historyRetention).ListFolders(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"home", "work/db"}, folders)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_ListVersions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	saved := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT h.version, h.info_type, h.info, h.meta, h.saved_at\s+FROM user_data_history h\s+`+
+		`JOIN user_data d ON d.id = h.data_id\s+WHERE h.data_id = \$1 AND d.user_id = \$2\s+ORDER BY h.version DESC`).
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "info_type", "info", "meta", "saved_at"}).
+			AddRow(2, "text", "info2", "meta2", saved).
+			AddRow(1, "text", "info1", "meta1", saved))
+
+	versions, err := NewDataRepository(db, new(mockLogger), historyRetention).ListVersions(context.Background(), 1, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.DataVersion{
+		{DataID: 3, Version: 2, InfoType: "text", Info: "info2", Meta: "meta2", SavedAt: saved},
+		{DataID: 3, Version: 1, InfoType: "text", Info: "info1", Meta: "meta1", SavedAt: saved},
+	}, versions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_RestoreVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		rows            *sqlmock.Rows
+		expectedVersion int64
+		expectedErr     error
+	}{
+		{
+			name:            "версия восстановлена",
+			rows:            sqlmock.NewRows([]string{"version"}).AddRow(8),
+			expectedVersion: 8,
+		},
+		{
+			name:        "версии нет в истории",
+			rows:        sqlmock.NewRows([]string{"version"}),
+			expectedErr: helper.ErrVersionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`WITH source AS \(\s+SELECT h.info_type, h.info, h.meta\s+FROM user_data_history h\s+`+
+				`JOIN user_data d ON d.id = h.data_id\s+WHERE h.data_id = \$1 AND d.user_id = \$2 AND h.version = \$3 AND d.deleted_at IS NULL\s+`+
+				`\), rev AS \(.+WHERE id = \$2 AND EXISTS \(SELECT 1 FROM source\).+`+
+				`\), archived AS \(\s+INSERT INTO user_data_history .+WHERE d.id = \$1 AND \$4 > 0\s+`+
+				`\), pruned AS \(.+h.version <= d.version - \$4 AND EXISTS \(SELECT 1 FROM rev\)\s+`+
+				`\), restored AS \(\s+UPDATE user_data\s+`+
+				`SET info_type = source.info_type, info = source.info, meta = source.meta,.+`+
+				`RETURNING user_data.version\s+\)\s+SELECT version FROM restored`).
+				WithArgs(3, 1, int64(2), historyRetention).
+				WillReturnRows(tt.rows)
+
+			version, err := NewDataRepository(db, new(mockLogger), historyRetention).
+				RestoreVersion(context.Background(), 1, 3, 2)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedVersion, version)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_VersionsRotation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDataRepository(db, new(mockLogger), historyRetention)
+
+	mock.ExpectQuery(`SELECT id, info, meta\s+FROM user_data_history\s+`+
+		`WHERE id > \$1 AND \(left\(info, length\(\$2\)\) <> \$2 OR left\(meta, length\(\$2\)\) <> \$2\)\s+`+
+		`ORDER BY id\s+LIMIT \$3`).
+		WithArgs(0, "v2:", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "info", "meta"}).AddRow(7, "v1:info", "v1:meta"))
+	mock.ExpectExec(`UPDATE user_data_history\s+SET info = \$1, meta = \$2\s+WHERE id = \$3`).
+		WithArgs("v2:info", "v2:meta", 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	versions, err := repo.ListVersionsForRotation(context.Background(), "v2:", 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.DataVersion{{ID: 7, Info: "v1:info", Meta: "v1:meta"}}, versions)
+
+	ok, err := repo.ReplaceEncryptedVersion(context.Background(), &entity.DataVersion{ID: 7, Info: "v2:info", Meta: "v2:meta"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataRepository_ListTrash(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deleted := created.Add(time.Hour)
+	trashColumns := []string{"id", "user_id", "info_type", "meta", "created", "deleted_at", "folder", "favorite", "tags"}
+
+	tests := []struct {
+		name      string
+		vaultID   int
+		condition string
+		arg       int
+	}{
+		{name: "личные записи", condition: `user_id = \$1 AND vault_id IS NULL`, arg: 1},
+		{name: "общее хранилище", vaultID: 7, condition: `vault_id = \$1`, arg: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`(?s)SELECT id, user_id, info_type, meta, created, deleted_at, .+FROM user_data\s+` +
+				`WHERE ` + tt.condition + ` AND deleted_at IS NOT NULL\s+ORDER BY deleted_at DESC, id DESC`).
+				WithArgs(tt.arg).
+				WillReturnRows(sqlmock.NewRows(trashColumns).AddRow(3, 1, "text", "meta", created, deleted, "work", false, "{db}"))
+
+			items, err := NewDataRepository(db, new(mockLogger), historyRetention).
+				ListTrash(context.Background(), 1, tt.vaultID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []*entity.UserData{{
+				ID: 3, UserID: 1, InfoType: "text", Meta: "meta", Created: created, DeletedAt: deleted,
+				Folder: "work", Tags: []string{"db"},
+			}}, items)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_RestoreData(t *testing.T) {
+	tests := []struct {
+		name            string
+		rows            *sqlmock.Rows
+		expectedVersion int64
+		expectedErr     error
+	}{
+		{
+			name:            "запись восстановлена",
+			rows:            sqlmock.NewRows([]string{"version"}).AddRow(4),
+			expectedVersion: 4,
+		},
+		{
+			name:        "в корзине записи нет",
+			rows:        sqlmock.NewRows([]string{"version"}),
+			expectedErr: helper.ErrDataNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`WITH rev AS \(.+WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NOT NULL.+`+
+				`\), restored AS \(\s+UPDATE user_data\s+`+
+				`SET deleted_at = NULL, updated_at = NOW\(\), revision = rev.revision, version = version \+ 1.+`+
+				`\), untombstoned AS \(\s+DELETE FROM user_data_tombstones\s+`+
+				`WHERE user_id = \$2 AND data_id IN \(SELECT id FROM restored WHERE vault_id IS NULL\)\s+\)\s+`+
+				`SELECT version FROM restored`).
+				WithArgs(3, 1).
+				WillReturnRows(tt.rows)
+
+			version, err := NewDataRepository(db, new(mockLogger), historyRetention).
+				RestoreData(context.Background(), 1, 3)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedVersion, version)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_PurgeData(t *testing.T) {
+	tests := []struct {
+		name        string
+		affected    int64
+		expectedErr error
+	}{
+		{name: "запись удалена", affected: 1},
+		{name: "в корзине записи нет", expectedErr: helper.ErrDataNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(`DELETE FROM user_data\s+WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NOT NULL`).
+				WithArgs(3, 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			err = NewDataRepository(db, new(mockLogger), historyRetention).PurgeData(context.Background(), 1, 3)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDataRepository_PurgeTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	before := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`WITH expired AS \(\s+SELECT id FROM user_data\s+WHERE deleted_at < \$1\s+`+
+		`ORDER BY deleted_at\s+LIMIT \$2\s+FOR UPDATE SKIP LOCKED\s+\), blobs AS \(.+`+
+		`\), purged AS \(\s+DELETE FROM user_data WHERE id IN \(SELECT id FROM expired\)\s+RETURNING id\s+\)\s+`+
+		`SELECT COALESCE\(blobs.blob_key, ''\)\s+FROM purged\s+LEFT JOIN blobs ON blobs.data_id = purged.id`).
+		WithArgs(before, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"blob_key"}).AddRow("").AddRow("ab/cdef").AddRow(""))
+
+	purged, blobKeys, err := NewDataRepository(db, new(mockLogger), historyRetention).
+		PurgeTrash(context.Background(), before, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, purged)
+	assert.Equal(t, []string{"ab/cdef"}, blobKeys)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
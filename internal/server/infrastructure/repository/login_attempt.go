@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+)
+
+type loginAttemptRepository struct {
+	db           dataStorager
+	logger       logger.CustomLogger
+	maxAttempts  int
+	lockDuration time.Duration
+}
+
+// NewLoginAttemptRepository - конструктор репозитория неудачных попыток входа.
+// После maxAttempts неудачных попыток подряд логин блокируется на lockDuration.
+func NewLoginAttemptRepository(
+	db dataStorager,
+	logger logger.CustomLogger,
+	maxAttempts int,
+	lockDuration time.Duration,
+) *loginAttemptRepository {
+	return &loginAttemptRepository{
+		db:           db,
+		logger:       logger,
+		maxAttempts:  maxAttempts,
+		lockDuration: lockDuration,
+	}
+}
+
+// IsLocked - проверяет, заблокирован ли вход для логина в данный момент.
+func (r *loginAttemptRepository) IsLocked(ctx context.Context, login string) (bool, error) {
+	query := `
+        SELECT EXISTS(
+            SELECT 1 FROM login_attempts
+            WHERE login = $1 AND locked_until > NOW()
+        )
+    `
+	var locked bool
+	err := r.db.QueryRowContext(ctx, query, login).Scan(&locked)
+	if err != nil {
+		r.logger.LogInfo("ошибка при проверке блокировки логина", err)
+		return false, helper.ErrInternalServer
+	}
+
+	return locked, nil
+}
+
+// RegisterFailure - увеличивает счётчик неудачных попыток и блокирует логин
+// при достижении лимита. После блокировки счётчик начинается заново.
+func (r *loginAttemptRepository) RegisterFailure(ctx context.Context, login string) error {
+	query := `
+        INSERT INTO login_attempts AS la (login, failed_count, locked_until)
+        VALUES (
+            $1,
+            CASE WHEN 1 >= $2 THEN 0 ELSE 1 END,
+            CASE WHEN 1 >= $2 THEN NOW() + $3 * INTERVAL '1 second' END
+        )
+        ON CONFLICT (login) DO UPDATE SET
+            failed_count = CASE
+                WHEN la.failed_count + 1 >= $2 THEN 0
+                ELSE la.failed_count + 1
+            END,
+            locked_until = CASE
+                WHEN la.failed_count + 1 >= $2 THEN NOW() + $3 * INTERVAL '1 second'
+                ELSE la.locked_until
+            END
+    `
+	_, err := r.db.ExecContext(ctx, query, login, r.maxAttempts, r.lockDuration.Seconds())
+	if err != nil {
+		r.logger.LogInfo("ошибка при сохранении неудачной попытки входа", err)
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
+
+// Reset - сбрасывает счётчик неудачных попыток после успешного входа.
+func (r *loginAttemptRepository) Reset(ctx context.Context, login string) error {
+	query := `DELETE FROM login_attempts WHERE login = $1`
+	_, err := r.db.ExecContext(ctx, query, login)
+	if err != nil {
+		r.logger.LogInfo("ошибка при сбросе счётчика попыток входа", err)
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
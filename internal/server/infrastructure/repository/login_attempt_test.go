@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttempt_IsLocked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db, new(mockLogger), 5, 15*time.Minute)
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("testuser").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	locked, err := repo.IsLocked(context.Background(), "testuser")
+
+	assert.NoError(t, err)
+	assert.True(t, locked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttempt_IsLocked_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db, new(mockLogger), 5, 15*time.Minute)
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("testuser").
+		WillReturnError(errors.New("database error"))
+
+	locked, err := repo.IsLocked(context.Background(), "testuser")
+
+	assert.False(t, locked)
+	assert.Equal(t, helper.ErrInternalServer, err)
+}
+
+func TestLoginAttempt_RegisterFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db, new(mockLogger), 5, 15*time.Minute)
+
+	mock.ExpectExec("INSERT INTO login_attempts").
+		WithArgs("testuser", 5, float64(900)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.RegisterFailure(context.Background(), "testuser")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttempt_RegisterFailure_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db, new(mockLogger), 5, 15*time.Minute)
+
+	mock.ExpectExec("INSERT INTO login_attempts").
+		WillReturnError(errors.New("database error"))
+
+	err = repo.RegisterFailure(context.Background(), "testuser")
+
+	assert.Equal(t, helper.ErrInternalServer, err)
+}
+
+func TestLoginAttempt_Reset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db, new(mockLogger), 5, 15*time.Minute)
+
+	mock.ExpectExec("DELETE FROM login_attempts").
+		WithArgs("testuser").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Reset(context.Background(), "testuser")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+)
+
+type sessionRepository struct {
+	db     dataStorager
+	logger logger.CustomLogger
+}
+
+// NewSessionRepository - конструктор репозитория сессий.
+func NewSessionRepository(db dataStorager, logger logger.CustomLogger) *sessionRepository {
+	return &sessionRepository{db: db, logger: logger}
+}
+
+// Create - сохраняет новую сессию и заполняет её ID и время создания.
+func (r *sessionRepository) Create(ctx context.Context, session *entity.Session, refreshHash []byte) error {
+	query := `
+        INSERT INTO sessions (user_id, device_name, refresh_hash, expires_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, last_used_at
+    `
+	err := r.db.QueryRowContext(
+		ctx, query, session.UserID, session.DeviceName, refreshHash, session.ExpiresAt,
+	).Scan(&session.ID, &session.CreatedAt, &session.LastUsedAt)
+	if err != nil {
+		r.logger.LogInfo("ошибка при создании сессии", err)
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
+
+// Rotate - заменяет refresh-токен активной сессии на новый и продлевает её.
+// Прежний хеш сохраняется, чтобы распознать повторное использование токена.
+func (r *sessionRepository) Rotate(
+	ctx context.Context, oldHash, newHash []byte, expiresAt time.Time,
+) (*entity.Session, error) {
+	query := `
+        UPDATE sessions
+        SET previous_hash = refresh_hash,
+            refresh_hash = $2,
+            last_used_at = NOW(),
+            expires_at = $3
+        WHERE refresh_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()
+        RETURNING id, user_id, device_name, created_at, last_used_at, expires_at
+    `
+	var s entity.Session
+	err := r.db.QueryRowContext(ctx, query, oldHash, newHash, expiresAt).
+		Scan(&s.ID, &s.UserID, &s.DeviceName, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helper.ErrInvalidRefresh
+		}
+		r.logger.LogInfo("ошибка при обновлении сессии", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return &s, nil
+}
+
+// RevokeByPreviousHash - отзывает сессию, чей предыдущий refresh-токен предъявлен повторно.
+func (r *sessionRepository) RevokeByPreviousHash(ctx context.Context, hash []byte) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE previous_hash = $1 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, hash)
+	if err != nil {
+		r.logger.LogInfo("ошибка при отзыве сессии по старому токену", err)
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
+
+// ListActive - возвращает неотозванные и неистёкшие сессии пользователя,
+// начиная с последней использованной.
+func (r *sessionRepository) ListActive(ctx context.Context, userID int) ([]*entity.Session, error) {
+	query := `
+        SELECT id, user_id, device_name, created_at, last_used_at, expires_at
+        FROM sessions
+        WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+        ORDER BY last_used_at DESC, id DESC
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении сессий", err)
+		return nil, helper.ErrInternalServer
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	var sessions []*entity.Session
+	for rows.Next() {
+		var s entity.Session
+		if err := rows.Scan(&s.ID, &s.UserID, &s.DeviceName, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt); err != nil {
+			r.logger.LogInfo("ошибка при сканировании сессии", err)
+			return nil, helper.ErrInternalServer
+		}
+		sessions = append(sessions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.LogInfo("ошибка при обходе сессий", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return sessions, nil
+}
+
+// Revoke - отзывает активную сессию пользователя.
+func (r *sessionRepository) Revoke(ctx context.Context, userID, sessionID int) error {
+	query := `
+        UPDATE sessions SET revoked_at = NOW()
+        WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+    `
+	res, err := r.db.ExecContext(ctx, query, sessionID, userID)
+	if err != nil {
+		r.logger.LogInfo("ошибка при отзыве сессии", err)
+		return helper.ErrInternalServer
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении количества отозванных сессий", err)
+		return helper.ErrInternalServer
+	}
+	if affected == 0 {
+		return helper.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// IsActive - проверяет, что сессия не отозвана и не истекла.
+func (r *sessionRepository) IsActive(ctx context.Context, sessionID int) (bool, error) {
+	query := `
+        SELECT EXISTS(
+            SELECT 1 FROM sessions
+            WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+        )
+    `
+	var active bool
+	err := r.db.QueryRowContext(ctx, query, sessionID).Scan(&active)
+	if err != nil {
+		r.logger.LogInfo("ошибка при проверке сессии", err)
+		return false, helper.ErrInternalServer
+	}
+
+	return active, nil
+}
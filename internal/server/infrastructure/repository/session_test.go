@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sessionColumns = []string{"id", "user_id", "device_name", "created_at", "last_used_at", "expires_at"}
+
+func TestSession_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db, new(mockLogger))
+	now := time.Now()
+	session := &entity.Session{UserID: 1, DeviceName: "laptop", ExpiresAt: now.Add(time.Hour)}
+
+	mock.ExpectQuery("INSERT INTO sessions").
+		WithArgs(1, "laptop", []byte("hash"), session.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "last_used_at"}).AddRow(7, now, now))
+
+	err = repo.Create(context.Background(), session, []byte("hash"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, session.ID)
+	assert.Equal(t, now, session.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSession_Rotate(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		setup       func(mock sqlmock.Sqlmock)
+		expected    *entity.Session
+		expectedErr error
+	}{
+		{
+			name: "успешная ротация",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("UPDATE sessions").
+					WithArgs([]byte("old"), []byte("new"), expiresAt).
+					WillReturnRows(sqlmock.NewRows(sessionColumns).AddRow(7, 1, "laptop", now, now, expiresAt))
+			},
+			expected: &entity.Session{
+				ID: 7, UserID: 1, DeviceName: "laptop", CreatedAt: now, LastUsedAt: now, ExpiresAt: expiresAt,
+			},
+		},
+		{
+			name: "токен не найден, отозван или истёк",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("UPDATE sessions").WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: helper.ErrInvalidRefresh,
+		},
+		{
+			name: "ошибка базы",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("UPDATE sessions").WillReturnError(errors.New("database error"))
+			},
+			expectedErr: helper.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			repo := NewSessionRepository(db, new(mockLogger))
+			tt.setup(mock)
+
+			session, err := repo.Rotate(context.Background(), []byte("old"), []byte("new"), expiresAt)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, session)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSession_ListActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db, new(mockLogger))
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, user_id, device_name").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow(8, 1, "phone", now, now, now.Add(time.Hour)).
+			AddRow(7, 1, "laptop", now, now, now.Add(time.Hour)))
+
+	sessions, err := repo.ListActive(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "phone", sessions[0].DeviceName)
+	assert.Equal(t, 7, sessions[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSession_Revoke(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      sql.Result
+		execErr     error
+		expectedErr error
+	}{
+		{name: "сессия отозвана", result: sqlmock.NewResult(0, 1)},
+		{name: "сессия не найдена", result: sqlmock.NewResult(0, 0), expectedErr: helper.ErrSessionNotFound},
+		{name: "ошибка базы", execErr: errors.New("database error"), expectedErr: helper.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			repo := NewSessionRepository(db, new(mockLogger))
+			exp := mock.ExpectExec("UPDATE sessions SET revoked_at").WithArgs(2, 1)
+			if tt.execErr != nil {
+				exp.WillReturnError(tt.execErr)
+			} else {
+				exp.WillReturnResult(tt.result)
+			}
+
+			err = repo.Revoke(context.Background(), 1, 2)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSession_RevokeByPreviousHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db, new(mockLogger))
+
+	mock.ExpectExec("UPDATE sessions SET revoked_at").
+		WithArgs([]byte("old")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.RevokeByPreviousHash(context.Background(), []byte("old")))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSession_IsActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db, new(mockLogger))
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(8).
+		WillReturnError(errors.New("database error"))
+
+	active, err := repo.IsActive(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	active, err = repo.IsActive(context.Background(), 8)
+	assert.False(t, active)
+	assert.Equal(t, helper.ErrInternalServer, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
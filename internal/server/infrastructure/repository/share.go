@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+)
+
+type shareRepository struct {
+	db     dataStorager
+	logger logger.CustomLogger
+}
+
+// NewShareRepository - конструктор репозитория ссылок на записи.
+func NewShareRepository(db dataStorager, logger logger.CustomLogger) *shareRepository {
+	return &shareRepository{db: db, logger: logger}
+}
+
+// Create - сохраняет снимок записи. Вместо идентификатора ссылки хранится
+// его хеш, чтобы утечка таблицы не позволяла открыть ссылки.
+func (r *shareRepository) Create(ctx context.Context, share *entity.Share, idHash []byte) error {
+	query := `
+        INSERT INTO shares (id_hash, user_id, data_id, payload, views_left, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := r.db.ExecContext(
+		ctx, query, idHash, share.UserID, share.DataID, share.Payload, share.ViewsLeft, share.ExpiresAt,
+	)
+	if err != nil {
+		r.logger.LogInfo("ошибка при создании ссылки", err)
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
+
+// Redeem - списывает один просмотр и возвращает снимок с оставшимся числом
+// просмотров. Снимок с последним просмотром удаляется тем же запросом,
+// истёкший - тоже, и для него возвращается helper.ErrShareNotFound.
+func (r *shareRepository) Redeem(ctx context.Context, idHash []byte) (*entity.Share, error) {
+	query := `
+        WITH expired AS (
+            DELETE FROM shares WHERE id_hash = $1 AND expires_at <= NOW()
+        ), target AS (
+            SELECT id_hash, user_id, data_id, payload, views_left, expires_at
+            FROM shares
+            WHERE id_hash = $1 AND expires_at > NOW()
+            FOR UPDATE
+        ), used AS (
+            UPDATE shares s SET views_left = s.views_left - 1
+            FROM target t
+            WHERE s.id_hash = t.id_hash AND t.views_left > 1
+        ), exhausted AS (
+            DELETE FROM shares s
+            USING target t
+            WHERE s.id_hash = t.id_hash AND t.views_left = 1
+        )
+        SELECT user_id, data_id, payload, views_left - 1, expires_at FROM target
+    `
+	share := &entity.Share{}
+	err := r.db.QueryRowContext(ctx, query, idHash).
+		Scan(&share.UserID, &share.DataID, &share.Payload, &share.ViewsLeft, &share.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, helper.ErrShareNotFound
+	}
+	if err != nil {
+		r.logger.LogInfo("ошибка при открытии ссылки", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return share, nil
+}
+
+// DeleteExpired - удаляет истёкшие снимки, которые так и не открыли.
+func (r *shareRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM shares WHERE expires_at <= NOW()`)
+	if err != nil {
+		r.logger.LogInfo("ошибка при удалении истёкших ссылок", err)
+		return 0, helper.ErrInternalServer
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении числа удалённых строк", err)
+		return 0, helper.ErrInternalServer
+	}
+
+	return deleted, nil
+}
+
+// ListSharesForRotation - снимки, зашифрованные не ключом с префиксом
+// activePrefix, по возрастанию хеша идентификатора после afterHash.
+func (r *shareRepository) ListSharesForRotation(
+	ctx context.Context, activePrefix string, afterHash []byte, limit int,
+) ([]*entity.SharePayload, error) {
+	query := `
+        SELECT id_hash, payload
+        FROM shares
+        WHERE id_hash > $1 AND left(payload, length($2)) <> $2
+        ORDER BY id_hash
+        LIMIT $3
+    `
+	rows, err := r.db.QueryContext(ctx, query, afterHash, activePrefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.SharePayload, 0, limit)
+	for rows.Next() {
+		share := &entity.SharePayload{}
+		if err := rows.Scan(&share.IDHash, &share.Payload); err != nil {
+			return nil, err
+		}
+		result = append(result, share)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReplaceEncryptedShare - записывает перешифрованный снимок, если ссылку
+// не успели открыть последний раз. Снимки не меняются, поэтому проверять
+// содержимое не нужно.
+func (r *shareRepository) ReplaceEncryptedShare(ctx context.Context, updated *entity.SharePayload) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE shares SET payload = $1 WHERE id_hash = $2`, updated.Payload, updated.IDHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
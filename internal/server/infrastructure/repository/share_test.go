@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShare_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`INSERT INTO shares \(id_hash, user_id, data_id, payload, views_left, expires_at\)`).
+		WithArgs([]byte("hash"), 1, 5, "sealed", 3, expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewShareRepository(db, new(mockLogger)).Create(context.Background(), &entity.Share{
+		UserID: 1, DataID: 5, Payload: "sealed", ViewsLeft: 3, ExpiresAt: expiresAt,
+	}, []byte("hash"))
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShare_Redeem(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"user_id", "data_id", "payload", "views_left", "expires_at"}
+
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		queryErr    error
+		expected    *entity.Share
+		expectedErr error
+	}{
+		{
+			name:     "просмотр списан",
+			rows:     sqlmock.NewRows(columns).AddRow(1, 5, "sealed", 2, expiresAt),
+			expected: &entity.Share{UserID: 1, DataID: 5, Payload: "sealed", ViewsLeft: 2, ExpiresAt: expiresAt},
+		},
+		{
+			name:        "ссылки нет или она истекла",
+			rows:        sqlmock.NewRows(columns),
+			expectedErr: helper.ErrShareNotFound,
+		},
+		{
+			name:        "ошибка базы",
+			queryErr:    errors.New("db down"),
+			expectedErr: helper.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			query := mock.ExpectQuery(`WITH expired AS \(\s+DELETE FROM shares WHERE id_hash = \$1 AND expires_at <= NOW\(\)` +
+				`.+FOR UPDATE.+UPDATE shares s SET views_left = s.views_left - 1.+t.views_left > 1` +
+				`.+DELETE FROM shares s\s+USING target t.+t.views_left = 1`).
+				WithArgs([]byte("hash"))
+			if tt.queryErr != nil {
+				query.WillReturnError(tt.queryErr)
+			} else {
+				query.WillReturnRows(tt.rows)
+			}
+
+			share, err := NewShareRepository(db, new(mockLogger)).Redeem(context.Background(), []byte("hash"))
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, share)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestShare_DeleteExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM shares WHERE expires_at <= NOW\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	deleted, err := NewShareRepository(db, new(mockLogger)).DeleteExpired(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShare_Rotation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewShareRepository(db, new(mockLogger))
+
+	mock.ExpectQuery(`SELECT id_hash, payload\s+FROM shares\s+`+
+		`WHERE id_hash > \$1 AND left\(payload, length\(\$2\)\) <> \$2\s+ORDER BY id_hash\s+LIMIT \$3`).
+		WithArgs([]byte{}, "v2:", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id_hash", "payload"}).AddRow([]byte("hash"), "v1:payload"))
+	mock.ExpectExec(`UPDATE shares SET payload = \$1 WHERE id_hash = \$2`).
+		WithArgs("v2:payload", []byte("hash")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	shares, err := repo.ListSharesForRotation(context.Background(), "v2:", []byte{}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.SharePayload{{IDHash: []byte("hash"), Payload: "v1:payload"}}, shares)
+
+	ok, err := repo.ReplaceEncryptedShare(
+		context.Background(), &entity.SharePayload{IDHash: []byte("hash"), Payload: "v2:payload"},
+	)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"github.com/lib/pq"
+)
+
+type twoFactorRepository struct {
+	db     dataStorager
+	logger logger.CustomLogger
+}
+
+// NewTwoFactorRepository - конструктор репозитория второго фактора.
+func NewTwoFactorRepository(db dataStorager, logger logger.CustomLogger) *twoFactorRepository {
+	return &twoFactorRepository{db: db, logger: logger}
+}
+
+// TOTPState - возвращает состояние второго фактора пользователя.
+func (r *twoFactorRepository) TOTPState(ctx context.Context, userID int) (*entity.TwoFactorState, error) {
+	query := `
+        SELECT login, COALESCE(totp_secret, ''), totp_enabled, totp_last_step
+        FROM users WHERE id = $1
+    `
+	var state entity.TwoFactorState
+	err := r.db.QueryRowContext(ctx, query, userID).
+		Scan(&state.Login, &state.Secret, &state.Enabled, &state.LastStep)
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении состояния 2FA", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return &state, nil
+}
+
+// SaveTOTPSecret - сохраняет новый секрет и заменяет коды восстановления.
+// Если второй фактор уже включён, ничего не меняется.
+func (r *twoFactorRepository) SaveTOTPSecret(
+	ctx context.Context, userID int, secret string, recoveryHashes [][]byte,
+) error {
+	query := `
+        WITH updated AS (
+            UPDATE users SET totp_secret = $2, totp_last_step = 0
+            WHERE id = $1 AND NOT totp_enabled
+            RETURNING id
+        ), removed AS (
+            DELETE FROM user_recovery_codes WHERE user_id IN (SELECT id FROM updated)
+        )
+        INSERT INTO user_recovery_codes (user_id, code_hash)
+        SELECT updated.id, hash FROM updated, unnest($3::bytea[]) AS hash
+    `
+	result, err := r.db.ExecContext(ctx, query, userID, secret, pq.ByteaArray(recoveryHashes))
+	if err != nil {
+		r.logger.LogInfo("ошибка при сохранении TOTP-секрета", err)
+		return helper.ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении числа сохранённых кодов", err)
+		return helper.ErrInternalServer
+	}
+	if affected == 0 {
+		return helper.ErrTwoFactorEnabled
+	}
+
+	return nil
+}
+
+// EnableTOTP - включает второй фактор и запоминает шаг подтверждающего кода.
+func (r *twoFactorRepository) EnableTOTP(ctx context.Context, userID int, step int64) error {
+	query := `
+        UPDATE users SET totp_enabled = TRUE, totp_last_step = $2
+        WHERE id = $1 AND totp_secret IS NOT NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		r.logger.LogInfo("ошибка при включении 2FA", err)
+		return helper.ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении числа обновлённых строк", err)
+		return helper.ErrInternalServer
+	}
+	if affected == 0 {
+		return helper.ErrTOTPNotEnrolled
+	}
+
+	return nil
+}
+
+// DisableTOTP - выключает второй фактор, удаляет секрет и коды восстановления.
+func (r *twoFactorRepository) DisableTOTP(ctx context.Context, userID int) error {
+	query := `
+        WITH removed AS (
+            DELETE FROM user_recovery_codes WHERE user_id = $1
+        )
+        UPDATE users SET totp_enabled = FALSE, totp_secret = NULL, totp_last_step = 0
+        WHERE id = $1
+    `
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		r.logger.LogInfo("ошибка при выключении 2FA", err)
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
+
+// UseTOTPStep - помечает шаг TOTP использованным. Возвращает false, если
+// этот или более поздний шаг уже принят.
+func (r *twoFactorRepository) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	query := `
+        UPDATE users SET totp_last_step = $2
+        WHERE id = $1 AND totp_enabled AND totp_last_step < $2
+    `
+	result, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		r.logger.LogInfo("ошибка при сохранении шага TOTP", err)
+		return false, helper.ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении числа обновлённых строк", err)
+		return false, helper.ErrInternalServer
+	}
+
+	return affected > 0, nil
+}
+
+// UseRecoveryCode - погашает неиспользованный код восстановления с данным хешем.
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, hash []byte) (bool, error) {
+	query := `
+        UPDATE user_recovery_codes SET used_at = NOW()
+        WHERE id = (
+            SELECT id FROM user_recovery_codes
+            WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+            LIMIT 1
+        ) AND used_at IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, userID, hash)
+	if err != nil {
+		r.logger.LogInfo("ошибка при использовании кода восстановления", err)
+		return false, helper.ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении числа обновлённых строк", err)
+		return false, helper.ErrInternalServer
+	}
+
+	return affected > 0, nil
+}
+
+// ListTOTPSecretsForRotation - TOTP-секреты, зашифрованные не ключом с
+// префиксом activePrefix, как ListForRotation для записей.
+func (r *twoFactorRepository) ListTOTPSecretsForRotation(
+	ctx context.Context, activePrefix string, afterID, limit int,
+) ([]*entity.TOTPSecret, error) {
+	query := `
+        SELECT id, totp_secret
+        FROM users
+        WHERE id > $1 AND totp_secret IS NOT NULL AND left(totp_secret, length($2)) <> $2
+        ORDER BY id
+        LIMIT $3
+    `
+	rows, err := r.db.QueryContext(ctx, query, afterID, activePrefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.TOTPSecret, 0, limit)
+	for rows.Next() {
+		secret := &entity.TOTPSecret{}
+		if err := rows.Scan(&secret.UserID, &secret.Secret); err != nil {
+			return nil, err
+		}
+		result = append(result, secret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReplaceTOTPSecret - записывает перешифрованный секрет, только если его не
+// заменили новым подключением аутентификатора с момента чтения.
+func (r *twoFactorRepository) ReplaceTOTPSecret(ctx context.Context, old, updated *entity.TOTPSecret) (bool, error) {
+	query := `
+        UPDATE users SET totp_secret = $1
+        WHERE id = $2 AND totp_secret = $3
+    `
+	res, err := r.db.ExecContext(ctx, query, updated.Secret, old.UserID, old.Secret)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
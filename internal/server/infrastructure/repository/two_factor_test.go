@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactor_TOTPState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTwoFactorRepository(db, new(mockLogger))
+
+	mock.ExpectQuery("SELECT login, COALESCE\\(totp_secret, ''\\), totp_enabled, totp_last_step").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"login", "totp_secret", "totp_enabled", "totp_last_step"}).
+			AddRow("user", "v1:secret", true, 42))
+
+	state, err := repo.TOTPState(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, &entity.TwoFactorState{Login: "user", Secret: "v1:secret", Enabled: true, LastStep: 42}, state)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactor_SaveTOTPSecret(t *testing.T) {
+	hashes := [][]byte{[]byte("a"), []byte("b")}
+
+	tests := []struct {
+		name        string
+		setup       func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "секрет сохранён",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("WITH updated AS").
+					WithArgs(1, "v1:secret", pq.ByteaArray(hashes)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "2FA уже включена",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("WITH updated AS").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: helper.ErrTwoFactorEnabled,
+		},
+		{
+			name: "ошибка базы",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("WITH updated AS").WillReturnError(errors.New("database error"))
+			},
+			expectedErr: helper.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			repo := NewTwoFactorRepository(db, new(mockLogger))
+			tt.setup(mock)
+
+			err = repo.SaveTOTPSecret(context.Background(), 1, "v1:secret", hashes)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTwoFactor_EnableTOTP(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTwoFactorRepository(db, new(mockLogger))
+
+	mock.ExpectExec("UPDATE users SET totp_enabled = TRUE").
+		WithArgs(1, int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE users SET totp_enabled = TRUE").
+		WithArgs(2, int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.EnableTOTP(context.Background(), 1, 10))
+	assert.Equal(t, helper.ErrTOTPNotEnrolled, repo.EnableTOTP(context.Background(), 2, 10))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactor_DisableTOTP(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTwoFactorRepository(db, new(mockLogger))
+
+	mock.ExpectExec("DELETE FROM user_recovery_codes").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.DisableTOTP(context.Background(), 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactor_UseTOTPStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTwoFactorRepository(db, new(mockLogger))
+
+	mock.ExpectExec("UPDATE users SET totp_last_step").
+		WithArgs(1, int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE users SET totp_last_step").
+		WithArgs(1, int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE users SET totp_last_step").
+		WithArgs(1, int64(11)).
+		WillReturnError(errors.New("database error"))
+
+	used, err := repo.UseTOTPStep(context.Background(), 1, 10)
+	assert.NoError(t, err)
+	assert.True(t, used)
+
+	used, err = repo.UseTOTPStep(context.Background(), 1, 10)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	_, err = repo.UseTOTPStep(context.Background(), 1, 11)
+	assert.Equal(t, helper.ErrInternalServer, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactor_UseRecoveryCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTwoFactorRepository(db, new(mockLogger))
+
+	mock.ExpectExec("UPDATE user_recovery_codes SET used_at").
+		WithArgs(1, []byte("hash")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE user_recovery_codes SET used_at").
+		WithArgs(1, []byte("hash")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	used, err := repo.UseRecoveryCode(context.Background(), 1, []byte("hash"))
+	assert.NoError(t, err)
+	assert.True(t, used)
+
+	used, err = repo.UseRecoveryCode(context.Background(), 1, []byte("hash"))
+	assert.NoError(t, err)
+	assert.False(t, used)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactor_SecretsRotation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTwoFactorRepository(db, new(mockLogger))
+
+	mock.ExpectQuery(`SELECT id, totp_secret\s+FROM users\s+`+
+		`WHERE id > \$1 AND totp_secret IS NOT NULL AND left\(totp_secret, length\(\$2\)\) <> \$2\s+`+
+		`ORDER BY id\s+LIMIT \$3`).
+		WithArgs(0, "v2:", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "totp_secret"}).AddRow(3, "v1:secret"))
+	mock.ExpectExec(`UPDATE users SET totp_secret = \$1\s+WHERE id = \$2 AND totp_secret = \$3`).
+		WithArgs("v2:secret", 3, "v1:secret").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	secrets, err := repo.ListTOTPSecretsForRotation(context.Background(), "v2:", 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.TOTPSecret{{UserID: 3, Secret: "v1:secret"}}, secrets)
+
+	ok, err := repo.ReplaceTOTPSecret(context.Background(), secrets[0], &entity.TOTPSecret{UserID: 3, Secret: "v2:secret"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -27,8 +27,14 @@ func NewUser(db storager, logger logger.CustomLogger) *User {
 }
 
 func (r *User) Save(ctx context.Context, user *entity.User) error {
-	query := `INSERT INTO users (login, password) VALUES ($1, $2) RETURNING id`
-	err := r.db.QueryRowxContext(ctx, query, user.Login, user.Password).Scan(&user.ID)
+	query := `
+        INSERT INTO users (login, password, kdf_salt, kdf_time, kdf_memory, kdf_threads)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `
+	err := r.db.QueryRowxContext(
+		ctx, query, user.Login, user.Password, user.Salt, user.Time, user.Memory, user.Threads,
+	).Scan(&user.ID)
 	if err != nil {
 		r.logger.LogInfo("ошибка при сохранении пользователя", err)
 		return helper.ErrInternalServer
@@ -50,7 +56,10 @@ func (r *User) ExistsByLogin(ctx context.Context, login string) (bool, error) {
 
 func (r *User) User(ctx context.Context, login string) (*entity.User, error) {
 	var user entity.User
-	query := `SELECT id, login, password FROM users WHERE login = $1`
+	query := `
+        SELECT id, login, password, kdf_salt, kdf_time, kdf_memory, kdf_threads, kdf_key_check, totp_enabled
+        FROM users WHERE login = $1
+    `
 	err := r.db.GetContext(ctx, &user, query, login)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -63,3 +72,27 @@ func (r *User) User(ctx context.Context, login string) (*entity.User, error) {
 	}
 	return &user, nil
 }
+
+// SetKeyCheck - сохраняет шифртекст проверки мастер-пароля. Заданную проверку
+// не перезаписывает: иначе украденный токен позволил бы подменить её и выдать
+// клиенту чужой пароль за верный.
+func (r *User) SetKeyCheck(ctx context.Context, userID int, keyCheck []byte) error {
+	query := `
+        UPDATE users SET kdf_key_check = $2
+        WHERE id = $1 AND kdf_key_check IS NULL
+        RETURNING id
+    `
+	var id int
+	err := r.db.QueryRowxContext(ctx, query, userID, keyCheck).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return helper.ErrKeyCheckExists
+		}
+
+		r.logger.LogInfo("ошибка при сохранении проверки мастер-пароля", err)
+
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
@@ -27,12 +27,13 @@ func TestUser_Save_Success(t *testing.T) {
 	repo := NewUser(sqlxDB, mockLogger)
 
 	user := &entity.User{
-		Login:    "testuser",
-		Password: "password123",
+		Login:     "testuser",
+		Password:  "password123",
+		KDFParams: entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
 	}
 
 	mock.ExpectQuery("INSERT INTO users").
-		WithArgs("testuser", "password123").
+		WithArgs("testuser", "password123", []byte("salt"), uint32(3), uint32(65536), uint32(4)).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 	err = repo.Save(context.Background(), user)
@@ -51,12 +52,13 @@ func TestUser_Save_Error(t *testing.T) {
 	repo := NewUser(sqlxDB, mockLogger)
 
 	user := &entity.User{
-		Login:    "testuser",
-		Password: "password123",
+		Login:     "testuser",
+		Password:  "password123",
+		KDFParams: entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
 	}
 
 	mock.ExpectQuery("INSERT INTO users").
-		WithArgs("testuser", "password123").
+		WithArgs("testuser", "password123", []byte("salt"), uint32(3), uint32(65536), uint32(4)).
 		WillReturnError(errors.New("some error"))
 
 	err = repo.Save(context.Background(), user)
@@ -119,15 +121,20 @@ func TestUser_User_Success(t *testing.T) {
 
 	login := "testuser"
 	expectedUser := &entity.User{
-		ID:       1,
-		Login:    "testuser",
-		Password: "password123",
+		ID:        1,
+		Login:     "testuser",
+		Password:  "password123",
+		KDFParams: entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4, KeyCheck: []byte("check")},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "login", "password"}).
-		AddRow(expectedUser.ID, expectedUser.Login, expectedUser.Password)
+	rows := sqlmock.NewRows([]string{
+		"id", "login", "password", "kdf_salt", "kdf_time", "kdf_memory", "kdf_threads", "kdf_key_check", "totp_enabled",
+	}).AddRow(
+		expectedUser.ID, expectedUser.Login, expectedUser.Password,
+		expectedUser.Salt, expectedUser.Time, expectedUser.Memory, expectedUser.Threads, expectedUser.KeyCheck, false,
+	)
 
-	mock.ExpectQuery("SELECT id, login, password FROM users WHERE login = \\$1").
+	mock.ExpectQuery("SELECT id, login, password, kdf_salt, kdf_time, kdf_memory, kdf_threads, kdf_key_check, totp_enabled\\s+FROM users WHERE login = \\$1").
 		WithArgs(login).
 		WillReturnRows(rows)
 
@@ -148,7 +155,7 @@ func TestUser_User_NotFound(t *testing.T) {
 
 	login := "nonexistentuser"
 
-	mock.ExpectQuery("SELECT id, login, password FROM users WHERE login = \\$1").
+	mock.ExpectQuery("SELECT id, login, password, kdf_salt, kdf_time, kdf_memory, kdf_threads, kdf_key_check, totp_enabled\\s+FROM users WHERE login = \\$1").
 		WithArgs(login).
 		WillReturnError(sql.ErrNoRows)
 
@@ -170,7 +177,7 @@ func TestUser_User_Error(t *testing.T) {
 
 	login := "testuser"
 
-	mock.ExpectQuery("SELECT id, login, password FROM users WHERE login = \\$1").
+	mock.ExpectQuery("SELECT id, login, password, kdf_salt, kdf_time, kdf_memory, kdf_threads, kdf_key_check, totp_enabled\\s+FROM users WHERE login = \\$1").
 		WithArgs(login).
 		WillReturnError(errors.New("database error"))
 
@@ -180,3 +187,56 @@ func TestUser_User_Error(t *testing.T) {
 	assert.Nil(t, user)
 	assert.EqualError(t, err, "ошибка при поиске пользователя")
 }
+
+func TestUser_SetKeyCheck(t *testing.T) {
+	const query = "UPDATE users SET kdf_key_check = \\$2 WHERE id = \\$1 AND kdf_key_check IS NULL RETURNING id"
+
+	tests := []struct {
+		name      string
+		setupMock func(mock sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "сохранена",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(1, []byte("check")).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+		},
+		{
+			name: "уже задана",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(1, []byte("check")).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: helper.ErrKeyCheckExists,
+		},
+		{
+			name: "ошибка базы",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(1, []byte("check")).
+					WillReturnError(errors.New("database error"))
+			},
+			wantErr: helper.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+			repo := NewUser(sqlx.NewDb(db, "sqlmock"), new(mockLogger))
+
+			err = repo.SetKeyCheck(context.Background(), 1, []byte("check"))
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
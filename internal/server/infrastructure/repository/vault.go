@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+)
+
+type vaultRepository struct {
+	db     dataStorager
+	logger logger.CustomLogger
+}
+
+// NewVaultRepository - конструктор репозитория общих хранилищ.
+func NewVaultRepository(db dataStorager, logger logger.CustomLogger) *vaultRepository {
+	return &vaultRepository{db: db, logger: logger}
+}
+
+// SetKeyPair - сохраняет ключевую пару пользователя. Заменить уже созданную
+// пару нельзя: ею зашифрованы ключи хранилищ, выданные пользователю.
+func (r *vaultRepository) SetKeyPair(ctx context.Context, userID int, pair *entity.KeyPair) error {
+	query := `
+        UPDATE users SET public_key = $2, sealed_private_key = $3
+        WHERE id = $1 AND public_key IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, userID, pair.PublicKey, pair.SealedPrivateKey)
+	if err != nil {
+		r.logger.LogInfo("ошибка при сохранении ключевой пары", err)
+		return helper.ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении числа обновлённых строк", err)
+		return helper.ErrInternalServer
+	}
+	if affected == 0 {
+		return helper.ErrKeyPairExists
+	}
+
+	return nil
+}
+
+// KeyPair - возвращает ключевую пару пользователя; пустую, если её ещё нет.
+func (r *vaultRepository) KeyPair(ctx context.Context, userID int) (*entity.KeyPair, error) {
+	query := `
+        SELECT COALESCE(public_key, ''), COALESCE(sealed_private_key, '')
+        FROM users WHERE id = $1
+    `
+	var pair entity.KeyPair
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&pair.PublicKey, &pair.SealedPrivateKey)
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении ключевой пары", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return &pair, nil
+}
+
+// PublicKey - возвращает id пользователя с логином login и его открытый ключ.
+func (r *vaultRepository) PublicKey(ctx context.Context, login string) (int, string, error) {
+	query := `
+        SELECT id, COALESCE(public_key, '')
+        FROM users WHERE login = $1
+    `
+	var (
+		userID    int
+		publicKey string
+	)
+	err := r.db.QueryRowContext(ctx, query, login).Scan(&userID, &publicKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", helper.ErrUserNotFound
+	}
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении открытого ключа", err)
+		return 0, "", helper.ErrInternalServer
+	}
+	if publicKey == "" {
+		return 0, "", helper.ErrNoPublicKey
+	}
+
+	return userID, publicKey, nil
+}
+
+// CreateVault - создаёт хранилище и делает владельца его первым участником.
+func (r *vaultRepository) CreateVault(ctx context.Context, vault *entity.Vault) (int, error) {
+	query := `
+        WITH created AS (
+            INSERT INTO vaults (name, owner_id, created)
+            VALUES ($1, $2, NOW())
+            RETURNING id
+        )
+        INSERT INTO vault_members (vault_id, user_id, role, wrapped_key)
+        SELECT id, $2, 'owner', $3 FROM created
+        RETURNING vault_id
+    `
+	var id int
+	err := r.db.QueryRowContext(ctx, query, vault.Name, vault.OwnerID, vault.WrappedKey).Scan(&id)
+	if err != nil {
+		r.logger.LogInfo("ошибка при создании хранилища", err)
+		return 0, helper.ErrInternalServer
+	}
+
+	return id, nil
+}
+
+// ListVaults - возвращает хранилища, в которых состоит пользователь,
+// с его ролью и его копией ключа хранилища.
+func (r *vaultRepository) ListVaults(ctx context.Context, userID int) ([]*entity.Vault, error) {
+	query := `
+        SELECT v.id, v.name, v.owner_id, v.created, m.role, m.wrapped_key
+        FROM vaults v
+        JOIN vault_members m ON m.vault_id = v.id
+        WHERE m.user_id = $1
+        ORDER BY v.id
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении списка хранилищ", err)
+		return nil, helper.ErrInternalServer
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.Vault, 0)
+	for rows.Next() {
+		vault := &entity.Vault{}
+		err := rows.Scan(&vault.ID, &vault.Name, &vault.OwnerID, &vault.Created, &vault.Role, &vault.WrappedKey)
+		if err != nil {
+			r.logger.LogInfo("ошибка при чтении хранилища", err)
+			return nil, helper.ErrInternalServer
+		}
+		result = append(result, vault)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.LogInfo("ошибка при получении списка хранилищ", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return result, nil
+}
+
+// VaultRole - возвращает роль пользователя в хранилище или
+// helper.ErrVaultNotFound, если он в нём не состоит.
+func (r *vaultRepository) VaultRole(ctx context.Context, userID, vaultID int) (string, error) {
+	query := `
+        SELECT role FROM vault_members
+        WHERE vault_id = $1 AND user_id = $2
+    `
+	var role string
+	err := r.db.QueryRowContext(ctx, query, vaultID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", helper.ErrVaultNotFound
+	}
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении роли в хранилище", err)
+		return "", helper.ErrInternalServer
+	}
+
+	return role, nil
+}
+
+// DataAccess - возвращает владельца записи и права пользователя на неё.
+// Личная запись доступна только своему владельцу, запись хранилища - его
+// участникам. Если доступа нет или запись в корзине, ответ тот же, что и
+// для несуществующей записи: helper.ErrDataNotFound.
+func (r *vaultRepository) DataAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error) {
+	return r.dataAccess(ctx, userID, dataID, "d.deleted_at IS NULL")
+}
+
+// TrashAccess - то же, что DataAccess, но для записи в корзине.
+func (r *vaultRepository) TrashAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error) {
+	return r.dataAccess(ctx, userID, dataID, "d.deleted_at IS NOT NULL")
+}
+
+func (r *vaultRepository) dataAccess(
+	ctx context.Context, userID, dataID int, deleted string,
+) (*entity.DataAccess, error) {
+	query := `
+        SELECT d.user_id, COALESCE(d.vault_id, 0), COALESCE(m.role, 'owner')
+        FROM user_data d
+        LEFT JOIN vault_members m ON m.vault_id = d.vault_id AND m.user_id = $2
+        WHERE d.id = $1 AND ` + deleted + ` AND (
+            (d.vault_id IS NULL AND d.user_id = $2) OR m.user_id IS NOT NULL
+        )
+    `
+	var access entity.DataAccess
+	err := r.db.QueryRowContext(ctx, query, dataID, userID).Scan(&access.OwnerID, &access.VaultID, &access.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, helper.ErrDataNotFound
+	}
+	if err != nil {
+		r.logger.LogInfo("ошибка при проверке доступа к записи", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return &access, nil
+}
+
+// AddMember - добавляет участника или меняет роль и ключ уже добавленного.
+// Роль владельца не меняется: для него возвращается helper.ErrAccessDenied.
+func (r *vaultRepository) AddMember(
+	ctx context.Context, vaultID int, member *entity.VaultMember, wrappedKey string,
+) error {
+	query := `
+        INSERT INTO vault_members (vault_id, user_id, role, wrapped_key)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (vault_id, user_id) DO UPDATE
+        SET role = EXCLUDED.role, wrapped_key = EXCLUDED.wrapped_key
+        WHERE vault_members.role <> 'owner'
+    `
+	result, err := r.db.ExecContext(ctx, query, vaultID, member.UserID, member.Role, wrappedKey)
+	if err != nil {
+		r.logger.LogInfo("ошибка при добавлении участника хранилища", err)
+		return helper.ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении числа обновлённых строк", err)
+		return helper.ErrInternalServer
+	}
+	if affected == 0 {
+		return helper.ErrAccessDenied
+	}
+
+	return nil
+}
+
+// Member - возвращает участника хранилища по логину или helper.ErrMemberNotFound.
+func (r *vaultRepository) Member(ctx context.Context, vaultID int, login string) (*entity.VaultMember, error) {
+	query := `
+        SELECT u.id, u.login, m.role
+        FROM vault_members m
+        JOIN users u ON u.id = m.user_id
+        WHERE m.vault_id = $1 AND u.login = $2
+    `
+	member := &entity.VaultMember{}
+	err := r.db.QueryRowContext(ctx, query, vaultID, login).Scan(&member.UserID, &member.Login, &member.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, helper.ErrMemberNotFound
+	}
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении участника хранилища", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return member, nil
+}
+
+// RemoveMember - исключает участника из хранилища. Владельца исключить нельзя.
+func (r *vaultRepository) RemoveMember(ctx context.Context, vaultID, userID int) error {
+	query := `
+        DELETE FROM vault_members
+        WHERE vault_id = $1 AND user_id = $2 AND role <> 'owner'
+    `
+	result, err := r.db.ExecContext(ctx, query, vaultID, userID)
+	if err != nil {
+		r.logger.LogInfo("ошибка при исключении участника хранилища", err)
+		return helper.ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении числа удалённых строк", err)
+		return helper.ErrInternalServer
+	}
+	if affected == 0 {
+		return helper.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// ListMembers - возвращает участников хранилища, начиная с владельца.
+func (r *vaultRepository) ListMembers(ctx context.Context, vaultID int) ([]*entity.VaultMember, error) {
+	query := `
+        SELECT u.id, u.login, m.role
+        FROM vault_members m
+        JOIN users u ON u.id = m.user_id
+        WHERE m.vault_id = $1
+        ORDER BY m.role = 'owner' DESC, u.login
+    `
+	rows, err := r.db.QueryContext(ctx, query, vaultID)
+	if err != nil {
+		r.logger.LogInfo("ошибка при получении участников хранилища", err)
+		return nil, helper.ErrInternalServer
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.LogInfo("ошибка при закрытии rows", closeErr)
+		}
+	}()
+
+	result := make([]*entity.VaultMember, 0)
+	for rows.Next() {
+		member := &entity.VaultMember{}
+		if err := rows.Scan(&member.UserID, &member.Login, &member.Role); err != nil {
+			r.logger.LogInfo("ошибка при чтении участника хранилища", err)
+			return nil, helper.ErrInternalServer
+		}
+		result = append(result, member)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.LogInfo("ошибка при получении участников хранилища", err)
+		return nil, helper.ErrInternalServer
+	}
+
+	return result, nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVault_DataAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		expected    *entity.DataAccess
+		expectedErr error
+	}{
+		{
+			name:     "запись хранилища",
+			rows:     sqlmock.NewRows([]string{"user_id", "vault_id", "role"}).AddRow(2, 7, "read"),
+			expected: &entity.DataAccess{Role: entity.RoleRead, OwnerID: 2, VaultID: 7},
+		},
+		{
+			name:        "чужая запись",
+			rows:        sqlmock.NewRows([]string{"user_id", "vault_id", "role"}),
+			expectedErr: helper.ErrDataNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`FROM user_data d\s+LEFT JOIN vault_members m ON m.vault_id = d.vault_id AND m.user_id = \$2\s+`+
+				`WHERE d.id = \$1 AND d.deleted_at IS NULL AND \(\s+\(d.vault_id IS NULL AND d.user_id = \$2\) OR m.user_id IS NOT NULL`).
+				WithArgs(5, 1).
+				WillReturnRows(tt.rows)
+
+			access, err := NewVaultRepository(db, new(mockLogger)).DataAccess(context.Background(), 1, 5)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, access)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestVault_TrashAccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM user_data d\s+LEFT JOIN vault_members m .+WHERE d.id = \$1 AND d.deleted_at IS NOT NULL AND`).
+		WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "vault_id", "role"}).AddRow(1, 0, "owner"))
+
+	access, err := NewVaultRepository(db, new(mockLogger)).TrashAccess(context.Background(), 1, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, &entity.DataAccess{Role: entity.RoleOwner, OwnerID: 1}, access)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVault_CreateVault(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`WITH created AS \(\s+INSERT INTO vaults \(name, owner_id, created\).+`+
+		`INSERT INTO vault_members \(vault_id, user_id, role, wrapped_key\)\s+SELECT id, \$2, 'owner', \$3 FROM created`).
+		WithArgs("staging", 1, "wrapped").
+		WillReturnRows(sqlmock.NewRows([]string{"vault_id"}).AddRow(7))
+
+	id, err := NewVaultRepository(db, new(mockLogger)).
+		CreateVault(context.Background(), &entity.Vault{Name: "staging", OwnerID: 1, WrappedKey: "wrapped"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVault_AddMember(t *testing.T) {
+	tests := []struct {
+		name        string
+		affected    int64
+		expectedErr error
+	}{
+		{name: "участник добавлен", affected: 1},
+		{name: "роль владельца не меняется", affected: 0, expectedErr: helper.ErrAccessDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(`INSERT INTO vault_members .+ON CONFLICT \(vault_id, user_id\) DO UPDATE.+`+
+				`WHERE vault_members.role <> 'owner'`).
+				WithArgs(7, 2, "write", "wrapped").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			err = NewVaultRepository(db, new(mockLogger)).AddMember(
+				context.Background(), 7, &entity.VaultMember{UserID: 2, Role: entity.RoleWrite}, "wrapped",
+			)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestVault_PublicKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		expectedID  int
+		expectedKey string
+		expectedErr error
+	}{
+		{
+			name:        "ключ есть",
+			rows:        sqlmock.NewRows([]string{"id", "public_key"}).AddRow(2, "pub"),
+			expectedID:  2,
+			expectedKey: "pub",
+		},
+		{
+			name:        "ключей ещё нет",
+			rows:        sqlmock.NewRows([]string{"id", "public_key"}).AddRow(2, ""),
+			expectedErr: helper.ErrNoPublicKey,
+		},
+		{
+			name:        "нет пользователя",
+			rows:        sqlmock.NewRows([]string{"id", "public_key"}),
+			expectedErr: helper.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`SELECT id, COALESCE\(public_key, ''\)\s+FROM users WHERE login = \$1`).
+				WithArgs("bob").
+				WillReturnRows(tt.rows)
+
+			id, key, err := NewVaultRepository(db, new(mockLogger)).PublicKey(context.Background(), "bob")
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedID, id)
+			assert.Equal(t, tt.expectedKey, key)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/api/authpb"
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/api/sharepb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type auditRecorder interface {
+	Record(ctx context.Context, event *entity.AuditEvent) error
+}
+
+// auditTokenParser - разбор токенов, по которым событие приписывается пользователю.
+type auditTokenParser interface {
+	ValidateToken(tokenString string) (*entity.Claims, error)
+	ParseChallenge(challenge string) (*entity.ChallengeClaims, error)
+}
+
+// AuditInterceptor - записывает в журнал аудита каждый вызов сервера.
+// Ставится перед AuthInterceptor, чтобы в журнал попадали и вызовы,
+// отклонённые из-за отсутствующего, просроченного или отозванного токена.
+// Пользователь берётся из access-токена, если он разбирается; до входа -
+// из логина LoginUser или challenge CompleteLogin.
+type AuditInterceptor struct {
+	auditService auditRecorder
+	tokens       auditTokenParser
+	logger       logger.CustomLogger
+}
+
+func NewAuditInterceptor(
+	auditService auditRecorder, tokens auditTokenParser, logger logger.CustomLogger,
+) *AuditInterceptor {
+	return &AuditInterceptor{auditService: auditService, tokens: tokens, logger: logger}
+}
+
+func (ai *AuditInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+
+		event := ai.event(ctx, info.FullMethod, err)
+		if event.UserID == 0 {
+			event.UserID, event.Login = ai.requestUser(req)
+		}
+		event.DataID = auditDataID(req)
+		if event.DataID == 0 {
+			event.DataID = auditDataID(resp)
+		}
+		ai.record(ctx, event)
+
+		return resp, err
+	}
+}
+
+// Stream - записывает потоковый вызов после его завершения. ID записи
+// берётся из первого сообщения потока, в котором он есть.
+func (ai *AuditInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		stream := &auditStream{ServerStream: ss}
+		err := handler(srv, stream)
+
+		event := ai.event(ss.Context(), info.FullMethod, err)
+		event.DataID = stream.dataID
+		ai.record(ss.Context(), event)
+
+		return err
+	}
+}
+
+// auditStream - серверный поток, запоминающий ID записи из сообщений.
+type auditStream struct {
+	grpc.ServerStream
+	dataID int
+}
+
+func (s *auditStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.dataID == 0 {
+		s.dataID = auditDataID(m)
+	}
+	return err
+}
+
+func (s *auditStream) SendMsg(m interface{}) error {
+	if s.dataID == 0 {
+		s.dataID = auditDataID(m)
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// event - заполняет общие для всех вызовов поля события.
+func (ai *AuditInterceptor) event(ctx context.Context, method string, err error) *entity.AuditEvent {
+	event := &entity.AuditEvent{Action: method, Outcome: status.Code(err).String()}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.ClientIP = p.Addr.String()
+		if host, _, splitErr := net.SplitHostPort(event.ClientIP); splitErr == nil {
+			event.ClientIP = host
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			event.UserAgent = values[0]
+		}
+		if values := md.Get("authorization"); len(values) > 0 {
+			if claims, err := ai.tokens.ValidateToken(strings.TrimPrefix(values[0], "Bearer ")); err == nil {
+				event.UserID = claims.UserID
+			}
+		}
+	}
+
+	return event
+}
+
+// record - сохраняет событие. Сбой журнала не должен ломать ответ клиенту,
+// поэтому ошибка только логируется; отмена запроса клиентом запись не прерывает.
+func (ai *AuditInterceptor) record(ctx context.Context, event *entity.AuditEvent) {
+	if err := ai.auditService.Record(context.WithoutCancel(ctx), event); err != nil {
+		ai.logger.LogInfo("не удалось записать событие аудита", err)
+	}
+}
+
+// requestUser - пользователь из запроса входа, пока токена ещё нет:
+// логин из LoginUser или пользователь из подписанного challenge
+// CompleteLogin. Логин из других запросов, например из RegisterUser
+// с занятым логином, не учитывается: он не подтверждает, кто вызывает.
+func (ai *AuditInterceptor) requestUser(req interface{}) (userID int, login string) {
+	switch r := req.(type) {
+	case *authpb.LoginUserRequest:
+		return 0, r.GetLogin()
+	case *authpb.CompleteLoginRequest:
+		if claims, err := ai.tokens.ParseChallenge(r.GetChallenge()); err == nil {
+			return claims.UserID, ""
+		}
+	}
+	return 0, ""
+}
+
+// auditDataID - ID записи, которой касается сообщение; 0, если такой нет.
+func auditDataID(msg interface{}) int {
+	switch m := msg.(type) {
+	case *datapb.GetDataRequest:
+		return int(m.GetId())
+	case *datapb.DeleteDataRequest:
+		return int(m.GetId())
+	case *datapb.DownloadBinaryRequest:
+		return int(m.GetId())
+	case *datapb.UpdateDataRequest:
+		return int(m.GetData().GetId())
+	case *datapb.MoveDataRequest:
+		return int(m.GetData().GetId())
+	case *datapb.IndexDataRequest:
+		return int(m.GetId())
+	case *datapb.ListVersionsRequest:
+		return int(m.GetId())
+	case *datapb.RestoreVersionRequest:
+		return int(m.GetId())
+	case *datapb.RestoreDataRequest:
+		return int(m.GetId())
+	case *datapb.PurgeDataRequest:
+		return int(m.GetId())
+	case *datapb.AddDataResponse:
+		return int(m.GetId())
+	case *datapb.UploadBinaryResponse:
+		return int(m.GetId())
+	case *sharepb.CreateShareRequest:
+		return int(m.GetDataId())
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,281 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/api/authpb"
+	"github.com/NikolosHGW/goph-keeper/api/datapb"
+	"github.com/NikolosHGW/goph-keeper/api/registerpb"
+	"github.com/NikolosHGW/goph-keeper/api/vaultpb"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type MockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAuditRecorder) Record(ctx context.Context, event *entity.AuditEvent) error {
+	return m.Called(ctx, event).Error(0)
+}
+
+type mockLogger struct{}
+
+func (l *mockLogger) LogInfo(message string, err error) {}
+
+// fakeAuditTokens - токены вида "user-<id>" и challenge вида "challenge-<id>".
+type fakeAuditTokens struct{}
+
+func (fakeAuditTokens) ValidateToken(tokenString string) (*entity.Claims, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(tokenString, "user-"))
+	if err != nil || !strings.HasPrefix(tokenString, "user-") {
+		return nil, errors.New("invalid token")
+	}
+	return &entity.Claims{UserID: id, SessionID: id}, nil
+}
+
+func (fakeAuditTokens) ParseChallenge(challenge string) (*entity.ChallengeClaims, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(challenge, "challenge-"))
+	if err != nil || !strings.HasPrefix(challenge, "challenge-") {
+		return nil, errors.New("invalid challenge")
+	}
+	return &entity.ChallengeClaims{UserID: id}, nil
+}
+
+// clientContext - входящий контекст вызова с адресом клиента, user-agent
+// и access-токеном пользователя userID, если он не 0.
+func clientContext(userID int) context.Context {
+	token := ""
+	if userID != 0 {
+		token = "Bearer user-" + strconv.Itoa(userID)
+	}
+	return tokenContext(token)
+}
+
+// tokenContext - входящий контекст вызова с заголовком authorization, если он задан.
+func tokenContext(authorization string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 50000},
+	})
+	md := metadata.Pairs("user-agent", "grpc-go/1.0")
+	if authorization != "" {
+		md.Set("authorization", authorization)
+	}
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+func TestAuditInterceptor_Unary(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		method    string
+		req       interface{}
+		resp      interface{}
+		handleErr error
+		recordErr error
+		expected  *entity.AuditEvent
+	}{
+		{
+			name:   "чтение записи",
+			userID: 1,
+			method: "/data.DataService/GetData",
+			req:    &datapb.GetDataRequest{Id: 5},
+			resp:   &datapb.GetDataResponse{},
+			expected: &entity.AuditEvent{
+				UserID: 1, Action: "/data.DataService/GetData", DataID: 5,
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "OK",
+			},
+		},
+		{
+			name:   "ID новой записи из ответа",
+			userID: 1,
+			method: "/data.DataService/AddData",
+			req:    &datapb.AddDataRequest{},
+			resp:   &datapb.AddDataResponse{Id: 8},
+			expected: &entity.AuditEvent{
+				UserID: 1, Action: "/data.DataService/AddData", DataID: 8,
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "OK",
+			},
+		},
+		{
+			name:      "неудачный вход",
+			method:    "/auth.Auth/LoginUser",
+			req:       &authpb.LoginUserRequest{Login: "alice", Password: "wrong"},
+			handleErr: status.Error(codes.Unauthenticated, "неверный логин или пароль"),
+			expected: &entity.AuditEvent{
+				Login: "alice", Action: "/auth.Auth/LoginUser",
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "Unauthenticated",
+			},
+		},
+		{
+			name:   "логин в запросе вошедшего пользователя не учитывается",
+			userID: 1,
+			method: "/vault.VaultService/GetPublicKey",
+			req:    &vaultpb.GetPublicKeyRequest{Login: "bob"},
+			resp:   &vaultpb.GetPublicKeyResponse{},
+			expected: &entity.AuditEvent{
+				UserID: 1, Action: "/vault.VaultService/GetPublicKey",
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "OK",
+			},
+		},
+		{
+			name:      "регистрация занятого логина не приписывается его владельцу",
+			method:    "/register.Register/RegisterUser",
+			req:       &registerpb.RegisterUserRequest{Login: "alice", Password: "secret"},
+			handleErr: status.Error(codes.AlreadyExists, "логин уже занят"),
+			expected: &entity.AuditEvent{
+				Action:   "/register.Register/RegisterUser",
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "AlreadyExists",
+			},
+		},
+		{
+			name:      "неверный код 2FA приписывается пользователю из challenge",
+			method:    "/auth.Auth/CompleteLogin",
+			req:       &authpb.CompleteLoginRequest{Challenge: "challenge-7", Code: "000000"},
+			handleErr: status.Error(codes.Unauthenticated, "неверный код"),
+			expected: &entity.AuditEvent{
+				UserID: 7, Action: "/auth.Auth/CompleteLogin",
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "Unauthenticated",
+			},
+		},
+		{
+			name:      "поддельный challenge не приписывается",
+			method:    "/auth.Auth/CompleteLogin",
+			req:       &authpb.CompleteLoginRequest{Challenge: "forged", Code: "000000"},
+			handleErr: status.Error(codes.Unauthenticated, "недействительный challenge"),
+			expected: &entity.AuditEvent{
+				Action:   "/auth.Auth/CompleteLogin",
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "Unauthenticated",
+			},
+		},
+		{
+			name:      "сбой журнала не ломает ответ",
+			userID:    1,
+			method:    "/data.DataService/DeleteData",
+			req:       &datapb.DeleteDataRequest{Id: 3},
+			resp:      &datapb.DeleteDataResponse{},
+			recordErr: errors.New("db down"),
+			expected: &entity.AuditEvent{
+				UserID: 1, Action: "/data.DataService/DeleteData", DataID: 3,
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "OK",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := new(MockAuditRecorder)
+			recorder.On("Record", mock.Anything, tt.expected).Return(tt.recordErr)
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return tt.resp, tt.handleErr
+			}
+
+			resp, err := NewAuditInterceptor(recorder, fakeAuditTokens{}, &mockLogger{}).Unary()(
+				clientContext(tt.userID), tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler,
+			)
+
+			assert.Equal(t, tt.resp, resp)
+			assert.Equal(t, tt.handleErr, err)
+			recorder.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuditInterceptor_RecordsRejectedByAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		active        bool
+		expected      *entity.AuditEvent
+	}{
+		{
+			name: "без токена",
+			expected: &entity.AuditEvent{
+				Action: "/data.DataService/GetData", DataID: 5,
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "Unauthenticated",
+			},
+		},
+		{
+			name:          "недействительный токен",
+			authorization: "Bearer expired",
+			expected: &entity.AuditEvent{
+				Action: "/data.DataService/GetData", DataID: 5,
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "Unauthenticated",
+			},
+		},
+		{
+			name:          "отозванная сессия",
+			authorization: "Bearer user-3",
+			expected: &entity.AuditEvent{
+				UserID: 3, Action: "/data.DataService/GetData", DataID: 5,
+				ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "Unauthenticated",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := new(MockAuditRecorder)
+			recorder.On("Record", mock.Anything, tt.expected).Return(nil)
+			sessions := new(MockSessionChecker)
+			sessions.On("IsActive", mock.Anything, mock.Anything).Return(tt.active, nil).Maybe()
+			auth := NewAuthInterceptor(fakeAuditTokens{}, sessions, nil).Unary()
+			info := &grpc.UnaryServerInfo{FullMethod: "/data.DataService/GetData"}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				t.Fatal("запрос не должен дойти до обработчика")
+				return nil, nil
+			}
+
+			_, err := NewAuditInterceptor(recorder, fakeAuditTokens{}, &mockLogger{}).Unary()(
+				tokenContext(tt.authorization), &datapb.GetDataRequest{Id: 5}, info,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return auth(ctx, req, info, handler)
+				},
+			)
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			recorder.AssertExpectations(t)
+		})
+	}
+}
+
+// sendingStream - поток, принимающий отправленные сообщения.
+type sendingStream struct {
+	mockServerStream
+}
+
+func (s *sendingStream) SendMsg(m interface{}) error {
+	return nil
+}
+
+func TestAuditInterceptor_Stream(t *testing.T) {
+	recorder := new(MockAuditRecorder)
+	recorder.On("Record", mock.Anything, &entity.AuditEvent{
+		UserID: 1, Action: "/data.DataService/UploadBinary", DataID: 12,
+		ClientIP: "10.0.0.1", UserAgent: "grpc-go/1.0", Outcome: "OK",
+	}).Return(nil)
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return stream.SendMsg(&datapb.UploadBinaryResponse{Id: 12})
+	}
+
+	err := NewAuditInterceptor(recorder, fakeAuditTokens{}, &mockLogger{}).Stream()(
+		nil,
+		&sendingStream{mockServerStream{ctx: clientContext(1)}},
+		&grpc.StreamServerInfo{FullMethod: "/data.DataService/UploadBinary"},
+		handler,
+	)
+
+	assert.NoError(t, err)
+	recorder.AssertExpectations(t)
+}
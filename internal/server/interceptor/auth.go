@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/NikolosHGW/goph-keeper/internal/contextkey"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -12,22 +13,32 @@ import (
 )
 
 type tokenValidator interface {
-	ValidateToken(tokenString string) (int, error)
+	ValidateToken(tokenString string) (*entity.Claims, error)
+}
+
+type sessionChecker interface {
+	IsActive(ctx context.Context, sessionID int) (bool, error)
 }
 
 type AuthInterceptor struct {
-	tokenService  tokenValidator
-	noAuthMethods map[string]bool
+	tokenService   tokenValidator
+	sessionService sessionChecker
+	noAuthMethods  map[string]bool
 }
 
-func NewAuthInterceptor(tokenService tokenValidator, noAuthMethods []string) *AuthInterceptor {
+func NewAuthInterceptor(
+	tokenService tokenValidator,
+	sessionService sessionChecker,
+	noAuthMethods []string,
+) *AuthInterceptor {
 	m := make(map[string]bool)
 	for _, method := range noAuthMethods {
 		m[method] = true
 	}
 	return &AuthInterceptor{
-		tokenService:  tokenService,
-		noAuthMethods: m,
+		tokenService:   tokenService,
+		sessionService: sessionService,
+		noAuthMethods:  m,
 	}
 }
 
@@ -43,35 +54,78 @@ func (ai *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 			return handler(ctx, req)
 		}
 
-		userID, err := ai.authorize(ctx)
+		claims, err := ai.authorize(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		ctx = context.WithValue(ctx, contextkey.UserIDKey, userID)
+		return handler(withClaims(ctx, claims), req)
+	}
+}
+
+// Stream - проверка токена для потоковых методов.
+func (ai *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if ai.noAuthMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		claims, err := ai.authorize(ss.Context())
+		if err != nil {
+			return err
+		}
 
-		return handler(ctx, req)
+		return handler(srv, &authStream{ServerStream: ss, ctx: withClaims(ss.Context(), claims)})
 	}
 }
 
-func (ai *AuthInterceptor) authorize(ctx context.Context) (int, error) {
+// authStream - серверный поток с контекстом, в который добавлен userID.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize - проверяет access-токен и то, что его сессия не отозвана.
+func (ai *AuthInterceptor) authorize(ctx context.Context) (*entity.Claims, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return 0, status.Error(codes.Unauthenticated, "метаданные не предоставлены")
+		return nil, status.Error(codes.Unauthenticated, "метаданные не предоставлены")
 	}
 
 	values := md["authorization"]
 	if len(values) == 0 {
-		return 0, status.Error(codes.Unauthenticated, "токен авторизации не предоставлен")
+		return nil, status.Error(codes.Unauthenticated, "токен авторизации не предоставлен")
 	}
 
 	accessToken := values[0]
 	accessToken = strings.TrimPrefix(accessToken, "Bearer ")
 
-	userID, err := ai.tokenService.ValidateToken(accessToken)
+	claims, err := ai.tokenService.ValidateToken(accessToken)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "недействительный токен доступа")
+	}
+
+	active, err := ai.sessionService.IsActive(ctx, claims.SessionID)
 	if err != nil {
-		return 0, status.Error(codes.Unauthenticated, "недействительный токен доступа")
+		return nil, status.Error(codes.Internal, "ошибка проверки сессии")
+	}
+	if !active {
+		return nil, status.Error(codes.Unauthenticated, "сессия отозвана или истекла")
 	}
 
-	return userID, nil
+	return claims, nil
+}
+
+func withClaims(ctx context.Context, claims *entity.Claims) context.Context {
+	ctx = context.WithValue(ctx, contextkey.UserIDKey, claims.UserID)
+	return context.WithValue(ctx, contextkey.SessionIDKey, claims.SessionID)
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/NikolosHGW/goph-keeper/internal/contextkey"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
@@ -18,15 +19,26 @@ type MockTokenValidator struct {
 	mock.Mock
 }
 
-func (m *MockTokenValidator) ValidateToken(tokenString string) (int, error) {
+func (m *MockTokenValidator) ValidateToken(tokenString string) (*entity.Claims, error) {
 	args := m.Called(tokenString)
-	return args.Int(0), args.Error(1)
+	claims, _ := args.Get(0).(*entity.Claims)
+	return claims, args.Error(1)
+}
+
+type MockSessionChecker struct {
+	mock.Mock
+}
+
+func (m *MockSessionChecker) IsActive(ctx context.Context, sessionID int) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
 }
 
 func TestAuthInterceptor_Unary(t *testing.T) {
 	mockValidator := new(MockTokenValidator)
+	mockSessions := new(MockSessionChecker)
 	noAuthMethods := []string{"/package.Service/NoAuthMethod"}
-	interceptor := NewAuthInterceptor(mockValidator, noAuthMethods)
+	interceptor := NewAuthInterceptor(mockValidator, mockSessions, noAuthMethods)
 
 	tests := []struct {
 		name           string
@@ -57,16 +69,53 @@ func TestAuthInterceptor_Unary(t *testing.T) {
 				"authorization": "Bearer validtoken",
 			}),
 			mockSetup: func() {
-				mockValidator.On("ValidateToken", "validtoken").Return(123, nil)
+				mockValidator.On("ValidateToken", "validtoken").Return(&entity.Claims{UserID: 123, SessionID: 7}, nil)
+				mockSessions.On("IsActive", mock.Anything, 7).Return(true, nil)
 			},
-			expectedResult: 123,
+			expectedResult: [2]int{123, 7},
 			expectedError:  nil,
 			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
 				userID, ok := ctx.Value(contextkey.UserIDKey).(int)
 				if !ok {
 					return nil, errors.New("userID не найден в контексте")
 				}
-				return userID, nil
+				sessionID, ok := ctx.Value(contextkey.SessionIDKey).(int)
+				if !ok {
+					return nil, errors.New("sessionID не найден в контексте")
+				}
+				return [2]int{userID, sessionID}, nil
+			},
+		},
+		{
+			name:   "Отозванная сессия",
+			method: "/package.Service/AuthMethod",
+			metadata: metadata.New(map[string]string{
+				"authorization": "Bearer revokedtoken",
+			}),
+			mockSetup: func() {
+				mockValidator.On("ValidateToken", "revokedtoken").Return(&entity.Claims{UserID: 123, SessionID: 8}, nil)
+				mockSessions.On("IsActive", mock.Anything, 8).Return(false, nil)
+			},
+			expectedResult: nil,
+			expectedError:  status.Error(codes.Unauthenticated, "сессия отозвана или истекла"),
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Ошибка проверки сессии",
+			method: "/package.Service/AuthMethod",
+			metadata: metadata.New(map[string]string{
+				"authorization": "Bearer dbdown",
+			}),
+			mockSetup: func() {
+				mockValidator.On("ValidateToken", "dbdown").Return(&entity.Claims{UserID: 123, SessionID: 9}, nil)
+				mockSessions.On("IsActive", mock.Anything, 9).Return(false, errors.New("db error"))
+			},
+			expectedResult: nil,
+			expectedError:  status.Error(codes.Internal, "ошибка проверки сессии"),
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
 			},
 		},
 		{
@@ -100,7 +149,7 @@ func TestAuthInterceptor_Unary(t *testing.T) {
 				"authorization": "Bearer invalidtoken",
 			}),
 			mockSetup: func() {
-				mockValidator.On("ValidateToken", "invalidtoken").Return(0, errors.New("invalid token"))
+				mockValidator.On("ValidateToken", "invalidtoken").Return(nil, errors.New("invalid token"))
 			},
 			expectedResult: nil,
 			expectedError:  status.Error(codes.Unauthenticated, "недействительный токен доступа"),
@@ -138,6 +187,79 @@ func TestAuthInterceptor_Unary(t *testing.T) {
 			}
 
 			mockValidator.AssertExpectations(t)
+			mockSessions.AssertExpectations(t)
+		})
+	}
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestAuthInterceptor_Stream(t *testing.T) {
+	mockValidator := new(MockTokenValidator)
+	mockValidator.On("ValidateToken", "valid_token").Return(&entity.Claims{UserID: 42, SessionID: 1}, nil)
+	mockValidator.On("ValidateToken", "invalid_token").Return(nil, errors.New("invalid"))
+	mockSessions := new(MockSessionChecker)
+	mockSessions.On("IsActive", mock.Anything, 1).Return(true, nil)
+
+	interceptor := NewAuthInterceptor(mockValidator, mockSessions, []string{"/package.Service/NoAuthStream"}).Stream()
+
+	tests := []struct {
+		name           string
+		method         string
+		md             metadata.MD
+		expectedUserID any
+		expectedCode   codes.Code
+	}{
+		{
+			name:           "валидный токен",
+			method:         "/package.Service/Stream",
+			md:             metadata.Pairs("authorization", "Bearer valid_token"),
+			expectedUserID: 42,
+			expectedCode:   codes.OK,
+		},
+		{
+			name:         "невалидный токен",
+			method:       "/package.Service/Stream",
+			md:           metadata.Pairs("authorization", "invalid_token"),
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "без метаданных",
+			method:       "/package.Service/Stream",
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:           "метод без аутентификации",
+			method:         "/package.Service/NoAuthStream",
+			expectedUserID: nil,
+			expectedCode:   codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var gotUserID any
+			handler := func(srv interface{}, stream grpc.ServerStream) error {
+				gotUserID = stream.Context().Value(contextkey.UserIDKey)
+				return nil
+			}
+
+			err := interceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedUserID, gotUserID)
 		})
 	}
 }
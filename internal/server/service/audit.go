@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+type auditRepo interface {
+	UserIDByLogin(ctx context.Context, login string) (int, error)
+	Append(ctx context.Context, event *entity.AuditEvent, payload []byte) error
+	List(ctx context.Context, userID int, beforeID int64, limit int) ([]*entity.AuditEvent, error)
+}
+
+type auditService struct {
+	repo auditRepo
+	now  func() time.Time
+}
+
+// NewAuditService - конструктор сервиса журнала аудита.
+func NewAuditService(repo auditRepo) *auditService {
+	return &auditService{repo: repo, now: time.Now}
+}
+
+// Record - добавляет событие в конец цепочки журнала. Если пользователь
+// не вошёл, но назвал логин, событие приписывается владельцу логина:
+// так он увидит неудачные попытки входа.
+func (s *auditService) Record(ctx context.Context, event *entity.AuditEvent) error {
+	if event.UserID == 0 && event.Login != "" {
+		userID, err := s.repo.UserIDByLogin(ctx, event.Login)
+		if err != nil {
+			return err
+		}
+		event.UserID = userID
+	}
+	// Время округляется до точности PostgreSQL, чтобы хеш совпал при проверке.
+	event.CreatedAt = s.now().UTC().Truncate(time.Microsecond)
+
+	payload, err := auditPayload(event)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Append(ctx, event, payload)
+}
+
+// ListEvents - возвращает страницу событий пользователя от новых к старым.
+// limit 0 - значение по умолчанию; больше maxAuditLimit не отдаётся.
+func (s *auditService) ListEvents(
+	ctx context.Context, userID int, beforeID int64, limit int,
+) ([]*entity.AuditEvent, error) {
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+
+	return s.repo.List(ctx, userID, beforeID, limit)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+)
+
+const auditVerifyBatchSize = 1000
+
+// auditGenesisHash - prev_hash первого события цепочки.
+var auditGenesisHash = make([]byte, sha256.Size)
+
+// ErrAuditSigningKey - ключ подписи контрольных точек не загружен.
+var ErrAuditSigningKey = errors.New("не задан ключ подписи контрольных точек аудита")
+
+type auditChainRepo interface {
+	ListChain(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEvent, error)
+	ChainHead(ctx context.Context) ([]byte, error)
+	LastChained(ctx context.Context) (*entity.AuditEvent, error)
+}
+
+type auditChain struct {
+	repo       auditChainRepo
+	signingKey ed25519.PrivateKey
+	batchSize  int
+	now        func() time.Time
+}
+
+// NewAuditChain - конструктор проверки цепочки журнала аудита. signingKey
+// подписывает контрольные точки; без него цепочку можно проверить только
+// саму по себе, без сверки с точками.
+func NewAuditChain(repo auditChainRepo, signingKey ed25519.PrivateKey) *auditChain {
+	return &auditChain{repo: repo, signingKey: signingKey, batchSize: auditVerifyBatchSize, now: time.Now}
+}
+
+// Checkpoint - подписывает текущую голову цепочки.
+func (c *auditChain) Checkpoint(ctx context.Context) (*entity.AuditCheckpoint, error) {
+	if c.signingKey == nil {
+		return nil, ErrAuditSigningKey
+	}
+
+	last, err := c.repo.LastChained(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, errors.New("в журнале аудита ещё нет событий с хешами")
+	}
+
+	checkpoint := &entity.AuditCheckpoint{
+		EventID:   last.ID,
+		Hash:      last.Hash,
+		CreatedAt: c.now().UTC(),
+	}
+	checkpoint.Signature = ed25519.Sign(c.signingKey, checkpointMessage(checkpoint))
+
+	return checkpoint, nil
+}
+
+// Verify - проходит цепочку от начала и сверяет её с контрольными точками.
+// Возвращает число проверенных событий и первое нарушение; nil - цепочка цела.
+func (c *auditChain) Verify(
+	ctx context.Context, checkpoints []*entity.AuditCheckpoint,
+) (int, *entity.AuditBreak, error) {
+	if brk, err := c.verifySignatures(checkpoints); brk != nil || err != nil {
+		return 0, brk, err
+	}
+	pending := append([]*entity.AuditCheckpoint(nil), checkpoints...)
+	sort.Slice(pending, func(i, j int) bool { return pending[i].EventID < pending[j].EventID })
+
+	checked, prev, chained := 0, auditGenesisHash, false
+	var afterID int64
+	for {
+		batch, err := c.repo.ListChain(ctx, afterID, c.batchSize)
+		if err != nil {
+			return checked, nil, fmt.Errorf("ошибка чтения журнала аудита: %w", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, event := range batch {
+			if len(pending) > 0 && pending[0].EventID < event.ID {
+				return checked, &entity.AuditBreak{
+					EventID: pending[0].EventID, Reason: "событие из контрольной точки удалено",
+				}, nil
+			}
+
+			// События, записанные до появления цепочки, пропускаются.
+			if event.Hash == nil && !chained {
+				continue
+			}
+			chained = true
+
+			if brk := checkEvent(event, prev); brk != nil {
+				return checked, brk, nil
+			}
+			if len(pending) > 0 && pending[0].EventID == event.ID {
+				if !bytes.Equal(pending[0].Hash, event.Hash) {
+					return checked, &entity.AuditBreak{
+						EventID: event.ID, Reason: "хеш не совпадает с контрольной точкой: цепочка пересобрана",
+					}, nil
+				}
+				pending = pending[1:]
+			}
+
+			prev = event.Hash
+			checked++
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+
+	if len(pending) > 0 {
+		return checked, &entity.AuditBreak{
+			EventID: pending[0].EventID, Reason: "журнал усечён: событие из контрольной точки отсутствует",
+		}, nil
+	}
+
+	head, err := c.repo.ChainHead(ctx)
+	if err != nil {
+		return checked, nil, fmt.Errorf("ошибка чтения головы цепочки: %w", err)
+	}
+	if !bytes.Equal(head, prev) {
+		return checked, &entity.AuditBreak{
+			EventID: afterID, Reason: "голова цепочки не совпадает с последним событием: журнал усечён",
+		}, nil
+	}
+
+	return checked, nil, nil
+}
+
+// verifySignatures - проверяет подписи контрольных точек открытым ключом сервера.
+func (c *auditChain) verifySignatures(checkpoints []*entity.AuditCheckpoint) (*entity.AuditBreak, error) {
+	if len(checkpoints) == 0 {
+		return nil, nil
+	}
+	if c.signingKey == nil {
+		return nil, ErrAuditSigningKey
+	}
+
+	publicKey, _ := c.signingKey.Public().(ed25519.PublicKey)
+	for _, cp := range checkpoints {
+		if !ed25519.Verify(publicKey, checkpointMessage(cp), cp.Signature) {
+			return &entity.AuditBreak{EventID: cp.EventID, Reason: "подпись контрольной точки неверна"}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// checkEvent - проверяет связь события с предыдущим и его содержимое.
+func checkEvent(event *entity.AuditEvent, prev []byte) *entity.AuditBreak {
+	if event.Hash == nil {
+		return &entity.AuditBreak{EventID: event.ID, Reason: "у события нет хеша"}
+	}
+	if !bytes.Equal(event.PrevHash, prev) {
+		return &entity.AuditBreak{
+			EventID: event.ID, Reason: "prev_hash не совпадает с хешем предыдущего события: событие удалено или вставлено",
+		}
+	}
+
+	payload, err := auditPayload(event)
+	if err != nil || !bytes.Equal(auditHash(prev, payload), event.Hash) {
+		return &entity.AuditBreak{EventID: event.ID, Reason: "содержимое события изменено"}
+	}
+
+	return nil
+}
+
+// auditPayload - каноническое содержимое события, от которого считается хеш.
+// ID в него не входит: порядок событий задаёт сама цепочка.
+func auditPayload(event *entity.AuditEvent) ([]byte, error) {
+	payload, err := json.Marshal(struct {
+		UserID    int    `json:"user_id"`
+		Login     string `json:"login"`
+		Action    string `json:"action"`
+		DataID    int    `json:"data_id"`
+		ClientIP  string `json:"client_ip"`
+		UserAgent string `json:"user_agent"`
+		Outcome   string `json:"outcome"`
+		CreatedAt string `json:"created_at"`
+	}{
+		UserID:    event.UserID,
+		Login:     event.Login,
+		Action:    event.Action,
+		DataID:    event.DataID,
+		ClientIP:  event.ClientIP,
+		UserAgent: event.UserAgent,
+		Outcome:   event.Outcome,
+		CreatedAt: event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации события аудита: %w", err)
+	}
+
+	return payload, nil
+}
+
+// auditHash - хеш события; в базе то же самое считает sha256(prev_hash || payload).
+func auditHash(prev, payload []byte) []byte {
+	sum := sha256.Sum256(append(append([]byte{}, prev...), payload...))
+	return sum[:]
+}
+
+// checkpointMessage - подписываемое содержимое контрольной точки.
+func checkpointMessage(cp *entity.AuditCheckpoint) []byte {
+	message := []byte("goph-keeper audit checkpoint\n")
+	message = strconv.AppendInt(message, cp.EventID, 10)
+	message = append(message, '\n')
+	message = append(message, cp.Hash...)
+	message = append(message, '\n')
+	return append(message, cp.CreatedAt.UTC().Format(time.RFC3339Nano)...)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAuditChain - журнал в памяти, хеши считаются так же, как в базе.
+type memoryAuditChain struct {
+	events []*entity.AuditEvent
+	head   []byte
+}
+
+func newMemoryAuditChain(t *testing.T, legacy, chained int) *memoryAuditChain {
+	t.Helper()
+
+	m := &memoryAuditChain{head: auditGenesisHash}
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < legacy+chained; i++ {
+		event := &entity.AuditEvent{
+			ID: int64(i + 1), UserID: 1, Action: "/data.DataService/GetData", DataID: i, Outcome: "OK",
+			CreatedAt: created.Add(time.Duration(i) * time.Second),
+		}
+		if i >= legacy {
+			payload, err := auditPayload(event)
+			require.NoError(t, err)
+			event.PrevHash = m.head
+			event.Hash = auditHash(m.head, payload)
+			m.head = event.Hash
+		}
+		m.events = append(m.events, event)
+	}
+	return m
+}
+
+func (m *memoryAuditChain) ListChain(_ context.Context, afterID int64, limit int) ([]*entity.AuditEvent, error) {
+	var batch []*entity.AuditEvent
+	for _, event := range m.events {
+		if event.ID > afterID && len(batch) < limit {
+			batch = append(batch, event)
+		}
+	}
+	return batch, nil
+}
+
+func (m *memoryAuditChain) ChainHead(context.Context) ([]byte, error) {
+	return m.head, nil
+}
+
+func (m *memoryAuditChain) LastChained(context.Context) (*entity.AuditEvent, error) {
+	for i := len(m.events) - 1; i >= 0; i-- {
+		if m.events[i].Hash != nil {
+			return &entity.AuditEvent{ID: m.events[i].ID, Hash: m.events[i].Hash}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryAuditChain) remove(id int64) {
+	for i, event := range m.events {
+		if event.ID == id {
+			m.events = append(m.events[:i], m.events[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestAuditChain_Verify(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		tamper          func(t *testing.T, m *memoryAuditChain, chain *auditChain) []*entity.AuditCheckpoint
+		expectedChecked int
+		expectedBreak   int64
+	}{
+		{
+			name: "цепочка цела",
+			tamper: func(t *testing.T, m *memoryAuditChain, chain *auditChain) []*entity.AuditCheckpoint {
+				cp, err := chain.Checkpoint(context.Background())
+				require.NoError(t, err)
+				return []*entity.AuditCheckpoint{cp}
+			},
+			expectedChecked: 5,
+		},
+		{
+			name: "изменено содержимое",
+			tamper: func(t *testing.T, m *memoryAuditChain, chain *auditChain) []*entity.AuditCheckpoint {
+				m.events[4].Outcome = "NotFound"
+				return nil
+			},
+			expectedChecked: 2,
+			expectedBreak:   5,
+		},
+		{
+			name: "удалено событие из середины",
+			tamper: func(t *testing.T, m *memoryAuditChain, chain *auditChain) []*entity.AuditCheckpoint {
+				m.remove(4)
+				return nil
+			},
+			expectedChecked: 1,
+			expectedBreak:   5,
+		},
+		{
+			name: "удалён хвост",
+			tamper: func(t *testing.T, m *memoryAuditChain, chain *auditChain) []*entity.AuditCheckpoint {
+				m.remove(7)
+				return nil
+			},
+			expectedChecked: 4,
+			expectedBreak:   6,
+		},
+		{
+			name: "удалён хвост вместе с головой, но есть контрольная точка",
+			tamper: func(t *testing.T, m *memoryAuditChain, chain *auditChain) []*entity.AuditCheckpoint {
+				cp, err := chain.Checkpoint(context.Background())
+				require.NoError(t, err)
+				m.remove(7)
+				m.head = m.events[len(m.events)-1].Hash
+				return []*entity.AuditCheckpoint{cp}
+			},
+			expectedChecked: 4,
+			expectedBreak:   7,
+		},
+		{
+			name: "цепочка пересобрана после правки",
+			tamper: func(t *testing.T, m *memoryAuditChain, chain *auditChain) []*entity.AuditCheckpoint {
+				cp, err := chain.Checkpoint(context.Background())
+				require.NoError(t, err)
+				m.events[3].Outcome = "NotFound"
+				prev := m.events[2].Hash
+				for _, event := range m.events[3:] {
+					payload, err := auditPayload(event)
+					require.NoError(t, err)
+					event.PrevHash, event.Hash = prev, auditHash(prev, payload)
+					prev = event.Hash
+				}
+				m.head = prev
+				return []*entity.AuditCheckpoint{cp}
+			},
+			expectedChecked: 4,
+			expectedBreak:   7,
+		},
+		{
+			name: "поддельная контрольная точка",
+			tamper: func(t *testing.T, m *memoryAuditChain, chain *auditChain) []*entity.AuditCheckpoint {
+				cp, err := chain.Checkpoint(context.Background())
+				require.NoError(t, err)
+				cp.EventID = 6
+				return []*entity.AuditCheckpoint{cp}
+			},
+			expectedBreak: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryAuditChain(t, 2, 5)
+			chain := NewAuditChain(repo, key)
+			chain.batchSize = 2
+			checkpoints := tt.tamper(t, repo, chain)
+
+			checked, brk, err := chain.Verify(context.Background(), checkpoints)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedChecked, checked)
+			if tt.expectedBreak == 0 {
+				assert.Nil(t, brk)
+			} else {
+				require.NotNil(t, brk)
+				assert.Equal(t, tt.expectedBreak, brk.EventID, brk.Reason)
+			}
+		})
+	}
+}
+
+func TestAuditChain_WithoutKey(t *testing.T) {
+	repo := newMemoryAuditChain(t, 0, 3)
+	chain := NewAuditChain(repo, nil)
+
+	checked, brk, err := chain.Verify(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Nil(t, brk)
+	assert.Equal(t, 3, checked)
+
+	_, err = chain.Checkpoint(context.Background())
+	assert.ErrorIs(t, err, ErrAuditSigningKey)
+
+	_, _, err = chain.Verify(context.Background(), []*entity.AuditCheckpoint{{EventID: 1}})
+	assert.ErrorIs(t, err, ErrAuditSigningKey)
+}
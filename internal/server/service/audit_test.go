@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type AuditRepoMock struct {
+	mock.Mock
+}
+
+func (m *AuditRepoMock) UserIDByLogin(ctx context.Context, login string) (int, error) {
+	args := m.Called(ctx, login)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *AuditRepoMock) Append(ctx context.Context, event *entity.AuditEvent, payload []byte) error {
+	return m.Called(ctx, event, payload).Error(0)
+}
+
+func (m *AuditRepoMock) List(
+	ctx context.Context, userID int, beforeID int64, limit int,
+) ([]*entity.AuditEvent, error) {
+	args := m.Called(ctx, userID, beforeID, limit)
+	events, _ := args.Get(0).([]*entity.AuditEvent)
+	return events, args.Error(1)
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		expectedLimit int
+	}{
+		{name: "значение по умолчанию", limit: 0, expectedLimit: defaultAuditLimit},
+		{name: "указанный размер", limit: 10, expectedLimit: 10},
+		{name: "слишком большой размер", limit: 10000, expectedLimit: maxAuditLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			events := []*entity.AuditEvent{{ID: 3, UserID: 1, Action: "/data.DataService/GetData"}}
+			repo := new(AuditRepoMock)
+			repo.On("List", ctx, 1, int64(7), tt.expectedLimit).Return(events, nil)
+
+			result, err := NewAuditService(repo).ListEvents(ctx, 1, 7, tt.limit)
+
+			require.NoError(t, err)
+			assert.Equal(t, events, result)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuditService_Record(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2030, 1, 1, 12, 0, 0, 123456789, time.UTC)
+	repo := new(AuditRepoMock)
+	repo.On("UserIDByLogin", ctx, "alice").Return(3, nil)
+	repo.On("Append", ctx, mock.Anything, mock.Anything).Return(nil)
+	svc := NewAuditService(repo)
+	svc.now = func() time.Time { return now }
+
+	event := &entity.AuditEvent{Login: "alice", Action: "/auth.Auth/LoginUser", Outcome: "Unauthenticated"}
+	require.NoError(t, svc.Record(ctx, event))
+
+	assert.Equal(t, 3, event.UserID)
+	assert.Equal(t, now.Truncate(time.Microsecond), event.CreatedAt)
+	payload, err := auditPayload(event)
+	require.NoError(t, err)
+	repo.AssertCalled(t, "Append", ctx, event, payload)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NikolosHGW/goph-keeper/internal/payload"
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+)
+
+const blobKeySize = 16
+
+type blobStore interface {
+	Put(key string, r io.Reader) (int64, error)
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// UploadBinary - сохраняет поток байт в хранилище блобов и создаёт запись типа binary.
+// Содержимое уже зашифровано клиентом по чанкам, поэтому сервер хранит его как есть
+// и только сверяет размер и SHA-256 с заявленными в заголовке.
+func (s *dataService) UploadBinary(
+	ctx context.Context,
+	userID int,
+	data *entity.UserData,
+	expected *entity.BinaryBlob,
+	r io.Reader,
+) (int, error) {
+	key, err := newBlobKey()
+	if err != nil {
+		return 0, err
+	}
+
+	hasher := sha256.New()
+	size, err := s.blobStore.Put(key, io.TeeReader(r, hasher))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка сохранения блоба: %w", err)
+	}
+
+	blob := &entity.BinaryBlob{Key: key, Size: size, SHA256: hex.EncodeToString(hasher.Sum(nil))}
+	if blob.Size != expected.Size || blob.SHA256 != expected.SHA256 {
+		s.deleteBlob(key)
+		return 0, helper.ErrChecksumMismatch
+	}
+
+	record := &entity.UserData{
+		UserID: userID, InfoType: payload.TypeBinary, SearchTokens: data.SearchTokens, Created: data.Created,
+	}
+	if record.Info, err = s.encryptionService.Encrypt(data.Info); err != nil {
+		s.deleteBlob(key)
+		return 0, fmt.Errorf("ошибка шифрования Info: %w", err)
+	}
+	if record.Meta, err = s.encryptionService.Encrypt(data.Meta); err != nil {
+		s.deleteBlob(key)
+		return 0, fmt.Errorf("ошибка шифрования Meta: %w", err)
+	}
+
+	id, err := s.dataRepo.AddBinary(ctx, record, blob)
+	if err != nil {
+		s.deleteBlob(key)
+		return 0, fmt.Errorf("ошибка сохранения записи: %w", err)
+	}
+
+	return id, nil
+}
+
+// DownloadBinary - возвращает запись, описание блоба и поток его содержимого.
+// Поток должен закрыть вызывающий.
+func (s *dataService) DownloadBinary(
+	ctx context.Context, userID, dataID int,
+) (*entity.UserData, *entity.BinaryBlob, io.ReadCloser, error) {
+	access, err := s.access.DataAccess(ctx, userID, dataID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	data, blob, err := s.dataRepo.GetBinary(ctx, access.OwnerID, dataID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil, helper.ErrDataNotFound
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("ошибка получения блоба из репозитория: %w", err)
+	}
+
+	if data.Info, err = s.encryptionService.Decrypt(data.Info); err != nil {
+		return nil, nil, nil, fmt.Errorf("ошибка расшифровки Info: %w", err)
+	}
+	if data.Meta, err = s.encryptionService.Decrypt(data.Meta); err != nil {
+		return nil, nil, nil, fmt.Errorf("ошибка расшифровки Meta: %w", err)
+	}
+
+	content, err := s.blobStore.Open(blob.Key)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("ошибка открытия блоба: %w", err)
+	}
+
+	return data, blob, content, nil
+}
+
+// deleteBlob - удаляет блоб, который не удалось привязать к записи.
+// Ошибка удаления не важнее исходной, поэтому не возвращается.
+func (s *dataService) deleteBlob(key string) {
+	_ = s.blobStore.Delete(key)
+}
+
+func newBlobKey() (string, error) {
+	raw := make([]byte, blobKeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("ошибка генерации ключа блоба: %w", err)
+	}
+
+	return hex.EncodeToString(raw), nil
+}
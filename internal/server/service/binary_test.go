@@ -0,0 +1,225 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *DataRepoMock) AddBinary(ctx context.Context, data *entity.UserData, blob *entity.BinaryBlob) (int, error) {
+	args := m.Called(ctx, data, blob)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *DataRepoMock) GetBinary(
+	ctx context.Context, userID, dataID int,
+) (*entity.UserData, *entity.BinaryBlob, error) {
+	args := m.Called(ctx, userID, dataID)
+	data, _ := args.Get(0).(*entity.UserData)
+	blob, _ := args.Get(1).(*entity.BinaryBlob)
+	return data, blob, args.Error(2)
+}
+
+func (m *DataRepoMock) BlobKey(ctx context.Context, userID, dataID int) (string, error) {
+	args := m.Called(ctx, userID, dataID)
+	return args.String(0), args.Error(1)
+}
+
+// memoryBlobStore - хранилище блобов в памяти для тестов.
+type memoryBlobStore struct {
+	blobs map[string][]byte
+}
+
+func newMemoryBlobStore() *memoryBlobStore {
+	return &memoryBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (s *memoryBlobStore) Put(key string, r io.Reader) (int64, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s.blobs[key] = content
+	return int64(len(content)), nil
+}
+
+func (s *memoryBlobStore) Open(key string) (io.ReadCloser, error) {
+	content, ok := s.blobs[key]
+	if !ok {
+		return nil, fmt.Errorf("блоб %s не найден", key)
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (s *memoryBlobStore) Delete(key string) error {
+	delete(s.blobs, key)
+	return nil
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDataService_UploadBinary(t *testing.T) {
+	ctx := context.Background()
+	content := "зашифрованные чанки"
+
+	t.Run("успешная загрузка", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		store := newMemoryBlobStore()
+		svc := NewDataService(repo, personalAccess{}, newTestEncryptionService(t), store)
+
+		var savedBlob *entity.BinaryBlob
+		repo.On("AddBinary", ctx, mock.AnythingOfType("*entity.UserData"), mock.AnythingOfType("*entity.BinaryBlob")).
+			Return(5, nil).
+			Run(func(args mock.Arguments) {
+				data := args.Get(1).(*entity.UserData)
+				assert.Equal(t, 1, data.UserID)
+				assert.Equal(t, "binary", data.InfoType)
+				assert.NotEqual(t, "info", data.Info)
+				assert.Equal(t, []string{"t1"}, data.SearchTokens)
+				savedBlob = args.Get(2).(*entity.BinaryBlob)
+			})
+
+		id, err := svc.UploadBinary(ctx, 1,
+			&entity.UserData{Info: "info", Meta: "meta", SearchTokens: []string{"t1"}},
+			&entity.BinaryBlob{Size: int64(len(content)), SHA256: sha256Hex(content)},
+			strings.NewReader(content),
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, id)
+		assert.Equal(t, sha256Hex(content), savedBlob.SHA256)
+		assert.Equal(t, content, string(store.blobs[savedBlob.Key]))
+	})
+
+	t.Run("контрольная сумма не совпадает", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		store := newMemoryBlobStore()
+		svc := NewDataService(repo, personalAccess{}, newTestEncryptionService(t), store)
+
+		_, err := svc.UploadBinary(ctx, 1,
+			&entity.UserData{},
+			&entity.BinaryBlob{Size: int64(len(content)), SHA256: sha256Hex("другое")},
+			strings.NewReader(content),
+		)
+
+		assert.ErrorIs(t, err, helper.ErrChecksumMismatch)
+		assert.Empty(t, store.blobs)
+		repo.AssertNotCalled(t, "AddBinary", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ошибка репозитория удаляет блоб", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		store := newMemoryBlobStore()
+		svc := NewDataService(repo, personalAccess{}, newTestEncryptionService(t), store)
+
+		repo.On("AddBinary", ctx, mock.Anything, mock.Anything).Return(0, errors.New("db error"))
+
+		_, err := svc.UploadBinary(ctx, 1,
+			&entity.UserData{},
+			&entity.BinaryBlob{Size: int64(len(content)), SHA256: sha256Hex(content)},
+			strings.NewReader(content),
+		)
+
+		assert.Error(t, err)
+		assert.Empty(t, store.blobs)
+	})
+}
+
+func TestDataService_DownloadBinary(t *testing.T) {
+	ctx := context.Background()
+	es := newTestEncryptionService(t)
+	store := newMemoryBlobStore()
+	store.blobs["abc"] = []byte("содержимое")
+
+	encInfo, err := es.Encrypt("info")
+	require.NoError(t, err)
+	encMeta, err := es.Encrypt("meta")
+	require.NoError(t, err)
+
+	repo := new(DataRepoMock)
+	repo.On("GetBinary", ctx, 1, 5).Return(
+		&entity.UserData{ID: 5, UserID: 1, InfoType: "binary", Info: encInfo, Meta: encMeta},
+		&entity.BinaryBlob{DataID: 5, Key: "abc", Size: 20, SHA256: "hash"},
+		nil,
+	)
+	repo.On("GetBinary", ctx, 1, 6).Return(nil, nil, sql.ErrNoRows)
+
+	svc := NewDataService(repo, personalAccess{}, es, store)
+
+	data, blob, content, err := svc.DownloadBinary(ctx, 1, 5)
+	require.NoError(t, err)
+	defer content.Close()
+
+	assert.Equal(t, "info", data.Info)
+	assert.Equal(t, "meta", data.Meta)
+	assert.Equal(t, "hash", blob.SHA256)
+	raw, err := io.ReadAll(content)
+	require.NoError(t, err)
+	assert.Equal(t, "содержимое", string(raw))
+
+	_, _, _, err = svc.DownloadBinary(ctx, 1, 6)
+	assert.ErrorIs(t, err, helper.ErrDataNotFound)
+}
+
+func TestDataService_DeleteData_KeepsBlob(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryBlobStore()
+	store.blobs["abc"] = []byte("содержимое")
+
+	repo := new(DataRepoMock)
+	repo.On("DeleteData", ctx, 1, 5, int64(1)).Return(nil)
+
+	err := NewDataService(repo, personalAccess{}, newTestEncryptionService(t), store).DeleteData(ctx, 1, 5, 1)
+
+	assert.NoError(t, err)
+	assert.Contains(t, store.blobs, "abc", "блоб нужен, пока запись в корзине")
+	repo.AssertExpectations(t)
+}
+
+func TestDataService_PurgeData_WithBlob(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryBlobStore()
+	store.blobs["abc"] = []byte("содержимое")
+
+	repo := new(DataRepoMock)
+	repo.On("BlobKey", ctx, 1, 5).Return("abc", nil)
+	repo.On("PurgeData", ctx, 1, 5).Return(nil)
+
+	err := NewDataService(repo, personalAccess{}, newTestEncryptionService(t), store).PurgeData(ctx, 1, 5)
+
+	assert.NoError(t, err)
+	assert.Empty(t, store.blobs)
+	repo.AssertExpectations(t)
+}
+
+func TestDataService_PurgeData_NotInTrashKeepsBlob(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryBlobStore()
+	store.blobs["abc"] = []byte("содержимое")
+
+	repo := new(DataRepoMock)
+	repo.On("BlobKey", ctx, 1, 5).Return("abc", nil)
+	repo.On("PurgeData", ctx, 1, 5).Return(helper.ErrDataNotFound)
+
+	err := NewDataService(repo, personalAccess{}, newTestEncryptionService(t), store).PurgeData(ctx, 1, 5)
+
+	assert.ErrorIs(t, err, helper.ErrDataNotFound)
+	assert.Contains(t, store.blobs, "abc")
+	repo.AssertExpectations(t)
+}
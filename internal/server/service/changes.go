@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+)
+
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 500
+)
+
+// GetChanges - возвращает изменения и удаления записей пользователя после ревизии since,
+// не больше limit штук, в порядке возрастания ревизии. Записи и tombstone'ы хранятся
+// в разных таблицах, поэтому из каждой берётся по limit+1 строке и результаты сливаются.
+func (s *dataService) GetChanges(ctx context.Context, userID int, since int64, limit int) (*entity.DataChanges, error) {
+	if limit <= 0 {
+		limit = defaultChangesLimit
+	}
+	if limit > maxChangesLimit {
+		limit = maxChangesLimit
+	}
+
+	upserts, err := s.dataRepo.ListChangedData(ctx, userID, since, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения изменённых данных из репозитория: %w", err)
+	}
+	deletions, err := s.dataRepo.ListTombstones(ctx, userID, since, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения удалённых данных из репозитория: %w", err)
+	}
+
+	changes := &entity.DataChanges{Revision: since}
+	var i, j int
+	for i+j < limit && (i < len(upserts) || j < len(deletions)) {
+		if j == len(deletions) || (i < len(upserts) && upserts[i].Revision < deletions[j].Revision) {
+			changes.Upserts = append(changes.Upserts, upserts[i])
+			changes.Revision = upserts[i].Revision
+			i++
+		} else {
+			changes.Deletions = append(changes.Deletions, deletions[j])
+			changes.Revision = deletions[j].Revision
+			j++
+		}
+	}
+	changes.HasMore = i < len(upserts) || j < len(deletions)
+
+	for _, item := range changes.Upserts {
+		if item.Info, err = s.encryptionService.Decrypt(item.Info); err != nil {
+			return nil, fmt.Errorf("ошибка расшифровки Info: %w", err)
+		}
+		if item.Meta, err = s.encryptionService.Decrypt(item.Meta); err != nil {
+			return nil, fmt.Errorf("ошибка расшифровки Meta: %w", err)
+		}
+	}
+
+	return changes, nil
+}
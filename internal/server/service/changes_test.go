@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *DataRepoMock) ListChangedData(ctx context.Context, userID int, since int64, limit int) ([]*entity.UserData, error) {
+	args := m.Called(ctx, userID, since, limit)
+	items, _ := args.Get(0).([]*entity.UserData)
+	return items, args.Error(1)
+}
+
+func (m *DataRepoMock) ListTombstones(ctx context.Context, userID int, since int64, limit int) ([]*entity.Tombstone, error) {
+	args := m.Called(ctx, userID, since, limit)
+	items, _ := args.Get(0).([]*entity.Tombstone)
+	return items, args.Error(1)
+}
+
+func TestDataService_GetChanges(t *testing.T) {
+	encryptionService := newTestEncryptionService(t)
+
+	encrypt := func(plain string) string {
+		encrypted, err := encryptionService.Encrypt(plain)
+		require.NoError(t, err)
+		return encrypted
+	}
+	upsert := func(id int, revision int64) *entity.UserData {
+		return &entity.UserData{ID: id, Info: encrypt("info"), Meta: encrypt("meta"), Revision: revision}
+	}
+
+	tests := []struct {
+		name              string
+		since             int64
+		limit             int
+		repoLimit         int
+		upserts           []*entity.UserData
+		deletions         []*entity.Tombstone
+		expectedUpserts   []int
+		expectedDeletions []int
+		expectedRevision  int64
+		expectedHasMore   bool
+	}{
+		{
+			name:              "изменения и удаления сливаются по ревизии",
+			since:             2,
+			limit:             10,
+			repoLimit:         11,
+			upserts:           []*entity.UserData{upsert(1, 3), upsert(4, 6)},
+			deletions:         []*entity.Tombstone{{DataID: 2, Revision: 5}},
+			expectedUpserts:   []int{1, 4},
+			expectedDeletions: []int{2},
+			expectedRevision:  6,
+		},
+		{
+			name:              "порция обрезается по limit",
+			since:             0,
+			limit:             2,
+			repoLimit:         3,
+			upserts:           []*entity.UserData{upsert(1, 1), upsert(4, 4)},
+			deletions:         []*entity.Tombstone{{DataID: 2, Revision: 2}, {DataID: 3, Revision: 3}},
+			expectedUpserts:   []int{1},
+			expectedDeletions: []int{2},
+			expectedRevision:  2,
+			expectedHasMore:   true,
+		},
+		{
+			name:             "изменений нет",
+			since:            7,
+			limit:            0,
+			repoLimit:        defaultChangesLimit + 1,
+			expectedRevision: 7,
+		},
+		{
+			name:             "limit ограничен сверху",
+			since:            7,
+			limit:            10000,
+			repoLimit:        maxChangesLimit + 1,
+			expectedRevision: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(DataRepoMock)
+			service := NewDataService(repo, personalAccess{}, encryptionService, newMemoryBlobStore())
+			ctx := context.Background()
+
+			repo.On("ListChangedData", ctx, 1, tt.since, tt.repoLimit).Return(tt.upserts, nil)
+			repo.On("ListTombstones", ctx, 1, tt.since, tt.repoLimit).Return(tt.deletions, nil)
+
+			changes, err := service.GetChanges(ctx, 1, tt.since, tt.limit)
+			require.NoError(t, err)
+
+			upsertIDs := make([]int, 0, len(changes.Upserts))
+			for _, item := range changes.Upserts {
+				upsertIDs = append(upsertIDs, item.ID)
+				assert.Equal(t, "info", item.Info)
+				assert.Equal(t, "meta", item.Meta)
+			}
+			deletionIDs := make([]int, 0, len(changes.Deletions))
+			for _, tombstone := range changes.Deletions {
+				deletionIDs = append(deletionIDs, tombstone.DataID)
+			}
+
+			assert.ElementsMatch(t, tt.expectedUpserts, upsertIDs)
+			assert.ElementsMatch(t, tt.expectedDeletions, deletionIDs)
+			assert.Equal(t, tt.expectedRevision, changes.Revision)
+			assert.Equal(t, tt.expectedHasMore, changes.HasMore)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDataService_GetChanges_RepoError(t *testing.T) {
+	repo := new(DataRepoMock)
+	service := NewDataService(repo, personalAccess{}, newTestEncryptionService(t), newMemoryBlobStore())
+	ctx := context.Background()
+
+	repo.On("ListChangedData", ctx, 1, int64(0), defaultChangesLimit+1).Return(nil, errors.New("db error"))
+
+	_, err := service.GetChanges(ctx, 1, 0, 0)
+	assert.Error(t, err)
+}
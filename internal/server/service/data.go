@@ -2,32 +2,79 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
 )
 
 type dataRepo interface {
 	AddData(ctx context.Context, data *entity.UserData) (int, error)
 	GetDataByID(ctx context.Context, userID, dataID int) (*entity.UserData, error)
-	UpdateData(ctx context.Context, data *entity.UserData) error
-	DeleteData(ctx context.Context, userID, dataID int) error
+	UpdateData(ctx context.Context, data *entity.UserData) (int64, error)
+	DeleteData(ctx context.Context, userID, dataID int, version int64) error
+	ListData(
+		ctx context.Context, userID int, filter *entity.DataFilter, after *entity.DataCursor,
+	) ([]*entity.UserData, error)
+	AddBinary(ctx context.Context, data *entity.UserData, blob *entity.BinaryBlob) (int, error)
+	GetBinary(ctx context.Context, userID, dataID int) (*entity.UserData, *entity.BinaryBlob, error)
+	BlobKey(ctx context.Context, userID, dataID int) (string, error)
+	ListChangedData(ctx context.Context, userID int, since int64, limit int) ([]*entity.UserData, error)
+	ListTombstones(ctx context.Context, userID int, since int64, limit int) ([]*entity.Tombstone, error)
+	MoveData(ctx context.Context, ownerID int, data *entity.UserData) (int64, error)
+	ListFolders(ctx context.Context, userID int) ([]string, error)
+	IndexData(ctx context.Context, userID, dataID int, tokens []string) error
+	ListVersions(ctx context.Context, userID, dataID int) ([]*entity.DataVersion, error)
+	RestoreVersion(ctx context.Context, userID, dataID int, version int64) (int64, error)
+	ListTrash(ctx context.Context, userID, vaultID int) ([]*entity.UserData, error)
+	RestoreData(ctx context.Context, userID, dataID int) (int64, error)
+	PurgeData(ctx context.Context, userID, dataID int) error
+}
+
+// vaultAccess - права пользователя на общие хранилища и отдельные записи.
+type vaultAccess interface {
+	VaultRole(ctx context.Context, userID, vaultID int) (string, error)
+	DataAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error)
+	TrashAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error)
 }
 
 type dataService struct {
 	dataRepo          dataRepo
+	access            vaultAccess
 	encryptionService *EncryptionService
+	blobStore         blobStore
 }
 
-// NewDataService - конструктор data service.
-func NewDataService(dataRepo dataRepo, encryptionService *EncryptionService) *dataService {
+// NewDataService - конструктор data service. Права на записи проверяются
+// через access: репозиторий данных только фильтрует по владельцу записи.
+func NewDataService(
+	dataRepo dataRepo, access vaultAccess, encryptionService *EncryptionService, blobStore blobStore,
+) *dataService {
 	return &dataService{
 		dataRepo:          dataRepo,
+		access:            access,
 		encryptionService: encryptionService,
+		blobStore:         blobStore,
 	}
 }
 
+// AddData - создаёт личную запись или, если задан data.VaultID, запись
+// общего хранилища, в которое пользователь может писать.
 func (s *dataService) AddData(ctx context.Context, userID int, data *entity.UserData) (int, error) {
+	if data.VaultID != 0 {
+		if err := s.requireVaultWrite(ctx, userID, data.VaultID); err != nil {
+			return 0, err
+		}
+	}
 	data.UserID = userID
 
 	// Шифруем поля data.Info и data.Meta
@@ -47,7 +94,12 @@ func (s *dataService) AddData(ctx context.Context, userID int, data *entity.User
 }
 
 func (s *dataService) GetDataByID(ctx context.Context, userID, dataID int) (*entity.UserData, error) {
-	data, err := s.dataRepo.GetDataByID(ctx, userID, dataID)
+	access, err := s.access.DataAccess(ctx, userID, dataID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.dataRepo.GetDataByID(ctx, access.OwnerID, dataID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения данных из репозитория: %w", err)
 	}
@@ -68,25 +120,338 @@ func (s *dataService) GetDataByID(ctx context.Context, userID, dataID int) (*ent
 	return data, nil
 }
 
-func (s *dataService) UpdateData(ctx context.Context, userID int, data *entity.UserData) error {
-	data.UserID = userID
+// UpdateData - обновляет запись, если data.Version совпадает с версией в базе,
+// и возвращает новую версию.
+func (s *dataService) UpdateData(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
+	access, err := s.writableData(ctx, userID, data.ID)
+	if err != nil {
+		return 0, err
+	}
+	// Клиент шифрует запись ключом того хранилища, которое указал. Если оно
+	// другое, остальные участники не смогли бы её прочитать.
+	if data.VaultID != access.VaultID {
+		return 0, helper.ErrVaultMismatch
+	}
+	data.UserID = access.OwnerID
 
 	// Шифруем поля перед обновлением
 	encryptedInfo, err := s.encryptionService.Encrypt(data.Info)
 	if err != nil {
-		return fmt.Errorf("ошибка шифрования Info: %w", err)
+		return 0, fmt.Errorf("ошибка шифрования Info: %w", err)
 	}
 	data.Info = encryptedInfo
 
 	encryptedMeta, err := s.encryptionService.Encrypt(data.Meta)
 	if err != nil {
-		return fmt.Errorf("ошибка шифрования Meta: %w", err)
+		return 0, fmt.Errorf("ошибка шифрования Meta: %w", err)
 	}
 	data.Meta = encryptedMeta
 
 	return s.dataRepo.UpdateData(ctx, data)
 }
 
-func (s *dataService) DeleteData(ctx context.Context, userID, dataID int) error {
-	return s.dataRepo.DeleteData(ctx, userID, dataID)
+// DeleteData - переносит запись той же версии в корзину. Блоб записи
+// остаётся в хранилище до окончательного удаления.
+func (s *dataService) DeleteData(ctx context.Context, userID, dataID int, version int64) error {
+	access, err := s.writableData(ctx, userID, dataID)
+	if err != nil {
+		return err
+	}
+
+	return s.dataRepo.DeleteData(ctx, access.OwnerID, dataID, version)
+}
+
+// ListData - возвращает страницу заголовков данных с расшифрованной Meta
+// и курсор следующей страницы (пустой, если страниц больше нет).
+// Записи общего хранилища видны любому его участнику.
+func (s *dataService) ListData(
+	ctx context.Context,
+	userID int,
+	filter *entity.DataFilter,
+) ([]*entity.UserData, string, error) {
+	if filter.VaultID != 0 {
+		if _, err := s.access.VaultRole(ctx, userID, filter.VaultID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	after, err := decodeCursor(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница.
+	repoFilter := *filter
+	repoFilter.Limit = limit + 1
+
+	items, err := s.dataRepo.ListData(ctx, userID, &repoFilter, after)
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка получения списка данных из репозитория: %w", err)
+	}
+
+	var nextCursor string
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[len(items)-1]
+		nextCursor = encodeCursor(&entity.DataCursor{Created: last.Created, ID: last.ID})
+	}
+
+	for _, item := range items {
+		decryptedMeta, err := s.encryptionService.Decrypt(item.Meta)
+		if err != nil {
+			return nil, "", fmt.Errorf("ошибка расшифровки Meta: %w", err)
+		}
+		item.Meta = decryptedMeta
+	}
+
+	return items, nextCursor, nil
+}
+
+// MoveData - переносит запись в хранилище data.VaultID или, если он 0, в личные
+// данные пользователя. Писать нужно уметь и в исходное, и в целевое хранилище.
+// Клиент уже перешифровал содержимое ключом целевого хранилища, поэтому info и meta
+// заменяются целиком. Блоб, загруженный потоком, зашифрован прежним ключом по чанкам,
+// и такие записи не переносятся.
+func (s *dataService) MoveData(ctx context.Context, userID int, data *entity.UserData) (int64, error) {
+	access, err := s.writableData(ctx, userID, data.ID)
+	if err != nil {
+		return 0, err
+	}
+	if data.VaultID != 0 {
+		if err := s.requireVaultWrite(ctx, userID, data.VaultID); err != nil {
+			return 0, err
+		}
+	}
+
+	blobKey, err := s.dataRepo.BlobKey(ctx, access.OwnerID, data.ID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения блоба записи: %w", err)
+	}
+	if blobKey != "" {
+		return 0, helper.ErrBlobNotMovable
+	}
+
+	// Запись из хранилища переходит к тому, кто забрал её в личные данные.
+	data.UserID = access.OwnerID
+	if data.VaultID == 0 {
+		data.UserID = userID
+	}
+
+	if data.Info, err = s.encryptionService.Encrypt(data.Info); err != nil {
+		return 0, fmt.Errorf("ошибка шифрования Info: %w", err)
+	}
+	if data.Meta, err = s.encryptionService.Encrypt(data.Meta); err != nil {
+		return 0, fmt.Errorf("ошибка шифрования Meta: %w", err)
+	}
+
+	return s.dataRepo.MoveData(ctx, access.OwnerID, data)
+}
+
+// ListFolders - папки с личными записями пользователя.
+func (s *dataService) ListFolders(ctx context.Context, userID int) ([]string, error) {
+	folders, err := s.dataRepo.ListFolders(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка папок из репозитория: %w", err)
+	}
+
+	return folders, nil
+}
+
+// IndexData - заменяет токены слепого индекса записи, не меняя её версию:
+// так клиент индексирует записи, сохранённые до появления поиска.
+func (s *dataService) IndexData(ctx context.Context, userID, dataID int, tokens []string) error {
+	access, err := s.writableData(ctx, userID, dataID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.dataRepo.IndexData(ctx, access.OwnerID, dataID, tokens); err != nil {
+		return fmt.Errorf("ошибка обновления индекса записи: %w", err)
+	}
+
+	return nil
+}
+
+// ListVersions - прежние версии записи с расшифрованными info и meta
+// от новых к старым и хранилище записи (0 - личные данные).
+// Историю видит любой, кто может читать запись.
+func (s *dataService) ListVersions(ctx context.Context, userID, dataID int) ([]*entity.DataVersion, int, error) {
+	access, err := s.access.DataAccess(ctx, userID, dataID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	versions, err := s.dataRepo.ListVersions(ctx, access.OwnerID, dataID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка получения истории записи из репозитория: %w", err)
+	}
+
+	for _, v := range versions {
+		if v.Info, err = s.encryptionService.Decrypt(v.Info); err != nil {
+			return nil, 0, fmt.Errorf("ошибка расшифровки Info: %w", err)
+		}
+		if v.Meta, err = s.encryptionService.Decrypt(v.Meta); err != nil {
+			return nil, 0, fmt.Errorf("ошибка расшифровки Meta: %w", err)
+		}
+	}
+
+	return versions, access.VaultID, nil
+}
+
+// RestoreVersion - возвращает записи содержимое версии version из истории
+// и возвращает новую версию записи.
+func (s *dataService) RestoreVersion(ctx context.Context, userID, dataID int, version int64) (int64, error) {
+	access, err := s.writableData(ctx, userID, dataID)
+	if err != nil {
+		return 0, err
+	}
+
+	restored, err := s.dataRepo.RestoreVersion(ctx, access.OwnerID, dataID, version)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка восстановления версии записи: %w", err)
+	}
+
+	return restored, nil
+}
+
+// ListTrash - записи из корзины личных данных или общего хранилища vaultID
+// с расшифрованной Meta, от недавно удалённых к давним.
+func (s *dataService) ListTrash(ctx context.Context, userID, vaultID int) ([]*entity.UserData, error) {
+	if vaultID != 0 {
+		if _, err := s.access.VaultRole(ctx, userID, vaultID); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := s.dataRepo.ListTrash(ctx, userID, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения корзины из репозитория: %w", err)
+	}
+
+	for _, item := range items {
+		if item.Meta, err = s.encryptionService.Decrypt(item.Meta); err != nil {
+			return nil, fmt.Errorf("ошибка расшифровки Meta: %w", err)
+		}
+	}
+
+	return items, nil
+}
+
+// RestoreData - возвращает запись из корзины и возвращает её новую версию.
+func (s *dataService) RestoreData(ctx context.Context, userID, dataID int) (int64, error) {
+	access, err := s.writableTrash(ctx, userID, dataID)
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := s.dataRepo.RestoreData(ctx, access.OwnerID, dataID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка восстановления записи из корзины: %w", err)
+	}
+
+	return version, nil
+}
+
+// PurgeData - окончательно удаляет запись из корзины вместе с историей,
+// токенами поиска и, если он есть, блобом.
+func (s *dataService) PurgeData(ctx context.Context, userID, dataID int) error {
+	access, err := s.writableTrash(ctx, userID, dataID)
+	if err != nil {
+		return err
+	}
+
+	blobKey, err := s.dataRepo.BlobKey(ctx, access.OwnerID, dataID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения блоба записи: %w", err)
+	}
+
+	if err := s.dataRepo.PurgeData(ctx, access.OwnerID, dataID); err != nil {
+		return err
+	}
+
+	if blobKey != "" {
+		return s.blobStore.Delete(blobKey)
+	}
+
+	return nil
+}
+
+// writableData - возвращает доступ к записи, если пользователь может её изменять.
+func (s *dataService) writableData(ctx context.Context, userID, dataID int) (*entity.DataAccess, error) {
+	access, err := s.access.DataAccess(ctx, userID, dataID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanWrite() {
+		return nil, helper.ErrAccessDenied
+	}
+
+	return access, nil
+}
+
+// writableTrash - то же, что writableData, для записи в корзине.
+func (s *dataService) writableTrash(ctx context.Context, userID, dataID int) (*entity.DataAccess, error) {
+	access, err := s.access.TrashAccess(ctx, userID, dataID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanWrite() {
+		return nil, helper.ErrAccessDenied
+	}
+
+	return access, nil
+}
+
+func (s *dataService) requireVaultWrite(ctx context.Context, userID, vaultID int) error {
+	role, err := s.access.VaultRole(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+	if !entity.CanWrite(role) {
+		return helper.ErrAccessDenied
+	}
+
+	return nil
+}
+
+// encodeCursor - кодирует позицию записи в непрозрачную для клиента строку.
+func encodeCursor(cursor *entity.DataCursor) string {
+	raw := strconv.FormatInt(cursor.Created.UnixNano(), 10) + ":" + strconv.Itoa(cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*entity.DataCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, helper.ErrInvalidCursor
+	}
+
+	created, id, found := strings.Cut(string(raw), ":")
+	if !found {
+		return nil, helper.ErrInvalidCursor
+	}
+
+	createdNano, err := strconv.ParseInt(created, 10, 64)
+	if err != nil {
+		return nil, helper.ErrInvalidCursor
+	}
+
+	dataID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, helper.ErrInvalidCursor
+	}
+
+	return &entity.DataCursor{Created: time.Unix(0, createdNano).UTC(), ID: dataID}, nil
 }
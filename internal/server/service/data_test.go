@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type DataRepoMock struct {
@@ -23,22 +26,109 @@ func (m *DataRepoMock) GetDataByID(ctx context.Context, userID, dataID int) (*en
 	return args.Get(0).(*entity.UserData), args.Error(1)
 }
 
-func (m *DataRepoMock) UpdateData(ctx context.Context, data *entity.UserData) error {
+func (m *DataRepoMock) UpdateData(ctx context.Context, data *entity.UserData) (int64, error) {
 	args := m.Called(ctx, data)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *DataRepoMock) DeleteData(ctx context.Context, userID, dataID int, version int64) error {
+	args := m.Called(ctx, userID, dataID, version)
+	return args.Error(0)
+}
+
+func (m *DataRepoMock) ListData(
+	ctx context.Context, userID int, filter *entity.DataFilter, after *entity.DataCursor,
+) ([]*entity.UserData, error) {
+	args := m.Called(ctx, userID, filter, after)
+	items, _ := args.Get(0).([]*entity.UserData)
+	return items, args.Error(1)
+}
+
+func (m *DataRepoMock) MoveData(ctx context.Context, ownerID int, data *entity.UserData) (int64, error) {
+	args := m.Called(ctx, ownerID, data)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *DataRepoMock) ListFolders(ctx context.Context, userID int) ([]string, error) {
+	args := m.Called(ctx, userID)
+	folders, _ := args.Get(0).([]string)
+	return folders, args.Error(1)
+}
+
+func (m *DataRepoMock) IndexData(ctx context.Context, userID, dataID int, tokens []string) error {
+	args := m.Called(ctx, userID, dataID, tokens)
 	return args.Error(0)
 }
 
-func (m *DataRepoMock) DeleteData(ctx context.Context, userID, dataID int) error {
+func (m *DataRepoMock) ListVersions(ctx context.Context, userID, dataID int) ([]*entity.DataVersion, error) {
+	args := m.Called(ctx, userID, dataID)
+	versions, _ := args.Get(0).([]*entity.DataVersion)
+	return versions, args.Error(1)
+}
+
+func (m *DataRepoMock) RestoreVersion(ctx context.Context, userID, dataID int, version int64) (int64, error) {
+	args := m.Called(ctx, userID, dataID, version)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *DataRepoMock) ListTrash(ctx context.Context, userID, vaultID int) ([]*entity.UserData, error) {
+	args := m.Called(ctx, userID, vaultID)
+	items, _ := args.Get(0).([]*entity.UserData)
+	return items, args.Error(1)
+}
+
+func (m *DataRepoMock) RestoreData(ctx context.Context, userID, dataID int) (int64, error) {
+	args := m.Called(ctx, userID, dataID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *DataRepoMock) PurgeData(ctx context.Context, userID, dataID int) error {
 	args := m.Called(ctx, userID, dataID)
 	return args.Error(0)
 }
 
+// personalAccess - доступ без общих хранилищ: пользователь владеет любой
+// запрошенной записью и не состоит ни в одном хранилище.
+type personalAccess struct{}
+
+func (personalAccess) VaultRole(ctx context.Context, userID, vaultID int) (string, error) {
+	return "", helper.ErrVaultNotFound
+}
+
+func (personalAccess) DataAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error) {
+	return &entity.DataAccess{Role: entity.RoleOwner, OwnerID: userID}, nil
+}
+
+func (personalAccess) TrashAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error) {
+	return &entity.DataAccess{Role: entity.RoleOwner, OwnerID: userID}, nil
+}
+
+type VaultAccessMock struct {
+	mock.Mock
+}
+
+func (m *VaultAccessMock) VaultRole(ctx context.Context, userID, vaultID int) (string, error) {
+	args := m.Called(ctx, userID, vaultID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *VaultAccessMock) DataAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error) {
+	args := m.Called(ctx, userID, dataID)
+	access, _ := args.Get(0).(*entity.DataAccess)
+	return access, args.Error(1)
+}
+
+func (m *VaultAccessMock) TrashAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error) {
+	args := m.Called(ctx, userID, dataID)
+	access, _ := args.Get(0).(*entity.DataAccess)
+	return access, args.Error(1)
+}
+
 func TestDataService_AddData(t *testing.T) {
-	key := []byte("01234567890123456789012345678901")
-	encryptionService := NewEncryptionService(key)
+	encryptionService := newTestEncryptionService(t)
 
 	dataRepoMock := new(DataRepoMock)
-	dataService := NewDataService(dataRepoMock, encryptionService)
+	dataService := NewDataService(dataRepoMock, personalAccess{}, encryptionService, newMemoryBlobStore())
 
 	ctx := context.Background()
 	userID := 1
@@ -62,11 +152,10 @@ func TestDataService_AddData(t *testing.T) {
 }
 
 func TestDataService_GetDataByID(t *testing.T) {
-	key := []byte("01234567890123456789012345678901")
-	encryptionService := NewEncryptionService(key)
+	encryptionService := newTestEncryptionService(t)
 
 	dataRepoMock := new(DataRepoMock)
-	dataService := NewDataService(dataRepoMock, encryptionService)
+	dataService := NewDataService(dataRepoMock, personalAccess{}, encryptionService, newMemoryBlobStore())
 
 	ctx := context.Background()
 	userID := 1
@@ -94,11 +183,10 @@ func TestDataService_GetDataByID(t *testing.T) {
 }
 
 func TestDataService_UpdateData(t *testing.T) {
-	key := []byte("01234567890123456789012345678901")
-	encryptionService := NewEncryptionService(key)
+	encryptionService := newTestEncryptionService(t)
 
 	dataRepoMock := new(DataRepoMock)
-	dataService := NewDataService(dataRepoMock, encryptionService)
+	dataService := NewDataService(dataRepoMock, personalAccess{}, encryptionService, newMemoryBlobStore())
 
 	ctx := context.Background()
 	userID := 1
@@ -107,45 +195,46 @@ func TestDataService_UpdateData(t *testing.T) {
 		InfoType: "text",
 		Info:     "обновленная информация",
 		Meta:     "обновленные метаданные",
+		Version:  2,
 	}
 
-	dataRepoMock.On("UpdateData", ctx, mock.AnythingOfType("*entity.UserData")).Return(nil).Run(func(args mock.Arguments) {
+	dataRepoMock.On("UpdateData", ctx, mock.AnythingOfType("*entity.UserData")).Return(int64(3), nil).Run(func(args mock.Arguments) {
 		argData := args.Get(1).(*entity.UserData)
 		assert.NotEqual(t, "обновленная информация", argData.Info)
 		assert.NotEqual(t, "обновленные метаданные", argData.Meta)
+		assert.Equal(t, int64(2), argData.Version)
 	})
 
-	err := dataService.UpdateData(ctx, userID, data)
+	version, err := dataService.UpdateData(ctx, userID, data)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
 
 	dataRepoMock.AssertExpectations(t)
 }
 
 func TestDataService_DeleteData(t *testing.T) {
-	key := []byte("01234567890123456789012345678901")
-	encryptionService := NewEncryptionService(key)
+	encryptionService := newTestEncryptionService(t)
 
 	dataRepoMock := new(DataRepoMock)
-	dataService := NewDataService(dataRepoMock, encryptionService)
+	dataService := NewDataService(dataRepoMock, personalAccess{}, encryptionService, newMemoryBlobStore())
 
 	ctx := context.Background()
 	userID := 1
 	dataID := 1
 
-	dataRepoMock.On("DeleteData", ctx, userID, dataID).Return(nil)
+	dataRepoMock.On("DeleteData", ctx, userID, dataID, int64(2)).Return(nil)
 
-	err := dataService.DeleteData(ctx, userID, dataID)
+	err := dataService.DeleteData(ctx, userID, dataID, 2)
 	assert.NoError(t, err)
 
 	dataRepoMock.AssertExpectations(t)
 }
 
 func TestDataService_GetDataByID_DecryptionError(t *testing.T) {
-	key := []byte("01234567890123456789012345678901")
-	encryptionService := NewEncryptionService(key)
+	encryptionService := newTestEncryptionService(t)
 
 	dataRepoMock := new(DataRepoMock)
-	dataService := NewDataService(dataRepoMock, encryptionService)
+	dataService := NewDataService(dataRepoMock, personalAccess{}, encryptionService, newMemoryBlobStore())
 
 	ctx := context.Background()
 	userID := 1
@@ -167,3 +256,450 @@ func TestDataService_GetDataByID_DecryptionError(t *testing.T) {
 
 	dataRepoMock.AssertExpectations(t)
 }
+
+func TestDataService_ListData(t *testing.T) {
+	encryptionService := newTestEncryptionService(t)
+
+	dataRepoMock := new(DataRepoMock)
+	dataService := NewDataService(dataRepoMock, personalAccess{}, encryptionService, newMemoryBlobStore())
+
+	ctx := context.Background()
+	userID := 1
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	encryptedMeta1, _ := encryptionService.Encrypt("первая")
+	encryptedMeta2, _ := encryptionService.Encrypt("вторая")
+	encryptedMeta3, _ := encryptionService.Encrypt("третья")
+
+	dataRepoMock.On("ListData", ctx, userID, mock.MatchedBy(func(f *entity.DataFilter) bool {
+		return f.Limit == 3 && f.InfoType == "text"
+	}), (*entity.DataCursor)(nil)).Return([]*entity.UserData{
+		{ID: 1, InfoType: "text", Meta: encryptedMeta1, Created: created},
+		{ID: 2, InfoType: "text", Meta: encryptedMeta2, Created: created.Add(time.Minute)},
+		{ID: 3, InfoType: "text", Meta: encryptedMeta3, Created: created.Add(2 * time.Minute)},
+	}, nil)
+
+	items, nextCursor, err := dataService.ListData(ctx, userID, &entity.DataFilter{InfoType: "text", Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "первая", items[0].Meta)
+	assert.Equal(t, "вторая", items[1].Meta)
+	assert.NotEmpty(t, nextCursor)
+
+	cursor, err := decodeCursor(nextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cursor.ID)
+	assert.True(t, created.Add(time.Minute).Equal(cursor.Created))
+
+	dataRepoMock.On("ListData", ctx, userID, mock.MatchedBy(func(f *entity.DataFilter) bool {
+		return f.Limit == 3
+	}), cursor).Return([]*entity.UserData{
+		{ID: 3, InfoType: "text", Meta: encryptedMeta3, Created: created.Add(2 * time.Minute)},
+	}, nil)
+
+	items, nextCursor, err = dataService.ListData(ctx, userID, &entity.DataFilter{
+		InfoType: "text", Limit: 2, Cursor: nextCursor,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "третья", items[0].Meta)
+	assert.Empty(t, nextCursor)
+
+	dataRepoMock.AssertExpectations(t)
+}
+
+func TestDataService_ListData_PageSizeBounds(t *testing.T) {
+	encryptionService := newTestEncryptionService(t)
+
+	dataRepoMock := new(DataRepoMock)
+	dataService := NewDataService(dataRepoMock, personalAccess{}, encryptionService, newMemoryBlobStore())
+
+	ctx := context.Background()
+
+	dataRepoMock.On("ListData", ctx, 1, mock.MatchedBy(func(f *entity.DataFilter) bool {
+		return f.Limit == defaultPageSize+1
+	}), (*entity.DataCursor)(nil)).Return([]*entity.UserData{}, nil).Once()
+	dataRepoMock.On("ListData", ctx, 1, mock.MatchedBy(func(f *entity.DataFilter) bool {
+		return f.Limit == maxPageSize+1
+	}), (*entity.DataCursor)(nil)).Return([]*entity.UserData{}, nil).Once()
+
+	_, _, err := dataService.ListData(ctx, 1, &entity.DataFilter{})
+	assert.NoError(t, err)
+
+	_, _, err = dataService.ListData(ctx, 1, &entity.DataFilter{Limit: 1000})
+	assert.NoError(t, err)
+
+	dataRepoMock.AssertExpectations(t)
+}
+
+func TestDataService_ListData_InvalidCursor(t *testing.T) {
+	encryptionService := newTestEncryptionService(t)
+
+	dataRepoMock := new(DataRepoMock)
+	dataService := NewDataService(dataRepoMock, personalAccess{}, encryptionService, newMemoryBlobStore())
+
+	for _, cursor := range []string{"!!!", "bm8tY29sb24", "YWJjOjE", "MTIzOmFiYw"} {
+		_, _, err := dataService.ListData(context.Background(), 1, &entity.DataFilter{Cursor: cursor})
+		assert.ErrorIs(t, err, helper.ErrInvalidCursor, cursor)
+	}
+
+	dataRepoMock.AssertNotCalled(t, "ListData", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDataService_VaultAccess(t *testing.T) {
+	ctx := context.Background()
+	vaultItem := &entity.DataAccess{Role: entity.RoleRead, OwnerID: 2, VaultID: 7}
+
+	t.Run("чтение записи хранилища участником", func(t *testing.T) {
+		encryptionService := newTestEncryptionService(t)
+		repo := new(DataRepoMock)
+		access := new(VaultAccessMock)
+		info, _ := encryptionService.Encrypt("общий пароль")
+		meta, _ := encryptionService.Encrypt("staging")
+
+		access.On("DataAccess", ctx, 1, 5).Return(vaultItem, nil)
+		repo.On("GetDataByID", ctx, 2, 5).Return(&entity.UserData{ID: 5, UserID: 2, VaultID: 7, Info: info, Meta: meta}, nil)
+
+		data, err := NewDataService(repo, access, encryptionService, newMemoryBlobStore()).GetDataByID(ctx, 1, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "общий пароль", data.Info)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("участник с правом чтения не изменяет запись", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		access := new(VaultAccessMock)
+		access.On("DataAccess", ctx, 1, 5).Return(vaultItem, nil)
+		service := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore())
+
+		_, err := service.UpdateData(ctx, 1, &entity.UserData{ID: 5, InfoType: "text", Version: 1})
+		assert.ErrorIs(t, err, helper.ErrAccessDenied)
+		assert.ErrorIs(t, service.DeleteData(ctx, 1, 5, 1), helper.ErrAccessDenied)
+		repo.AssertNotCalled(t, "UpdateData", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "DeleteData", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("изменение записи хранилища идёт от имени её владельца", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		access := new(VaultAccessMock)
+		access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleWrite, OwnerID: 2, VaultID: 7}, nil)
+		repo.On("UpdateData", ctx, mock.MatchedBy(func(d *entity.UserData) bool {
+			return d.UserID == 2
+		})).Return(int64(2), nil)
+
+		version, err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).
+			UpdateData(ctx, 1, &entity.UserData{ID: 5, InfoType: "text", Info: "x", Version: 1, VaultID: 7})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("изменение с другим хранилищем", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		access := new(VaultAccessMock)
+		access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleWrite, OwnerID: 2, VaultID: 7}, nil)
+
+		_, err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).
+			UpdateData(ctx, 1, &entity.UserData{ID: 5, InfoType: "text", Info: "x", Version: 1})
+
+		assert.ErrorIs(t, err, helper.ErrVaultMismatch)
+		repo.AssertNotCalled(t, "UpdateData", mock.Anything, mock.Anything)
+	})
+
+	t.Run("добавление в хранилище без права записи", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		access := new(VaultAccessMock)
+		access.On("VaultRole", ctx, 1, 7).Return(entity.RoleRead, nil)
+
+		_, err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).
+			AddData(ctx, 1, &entity.UserData{InfoType: "text", VaultID: 7})
+
+		assert.ErrorIs(t, err, helper.ErrAccessDenied)
+		repo.AssertNotCalled(t, "AddData", mock.Anything, mock.Anything)
+	})
+
+	t.Run("список чужого хранилища", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		access := new(VaultAccessMock)
+		access.On("VaultRole", ctx, 1, 9).Return("", helper.ErrVaultNotFound)
+
+		_, _, err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).
+			ListData(ctx, 1, &entity.DataFilter{VaultID: 9})
+
+		assert.ErrorIs(t, err, helper.ErrVaultNotFound)
+	})
+}
+
+func TestDataService_MoveData(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		setup         func(repo *DataRepoMock, access *VaultAccessMock)
+		vaultID       int
+		expectedOwner int
+		expectedErr   error
+	}{
+		{
+			name:    "личная запись в хранилище",
+			vaultID: 7,
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleOwner, OwnerID: 1}, nil)
+				access.On("VaultRole", ctx, 1, 7).Return(entity.RoleWrite, nil)
+				repo.On("BlobKey", ctx, 1, 5).Return("", nil)
+			},
+			expectedOwner: 1,
+		},
+		{
+			name: "запись хранилища в личные данные",
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("DataAccess", ctx, 1, 5).
+					Return(&entity.DataAccess{Role: entity.RoleWrite, OwnerID: 2, VaultID: 7}, nil)
+				repo.On("BlobKey", ctx, 2, 5).Return("", nil)
+			},
+			expectedOwner: 1,
+		},
+		{
+			name:    "целевое хранилище только для чтения",
+			vaultID: 8,
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleOwner, OwnerID: 1}, nil)
+				access.On("VaultRole", ctx, 1, 8).Return(entity.RoleRead, nil)
+			},
+			expectedErr: helper.ErrAccessDenied,
+		},
+		{
+			name:    "запись с блобом",
+			vaultID: 7,
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleOwner, OwnerID: 1}, nil)
+				access.On("VaultRole", ctx, 1, 7).Return(entity.RoleOwner, nil)
+				repo.On("BlobKey", ctx, 1, 5).Return("blob", nil)
+			},
+			expectedErr: helper.ErrBlobNotMovable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(DataRepoMock)
+			access := new(VaultAccessMock)
+			tt.setup(repo, access)
+			if tt.expectedErr == nil {
+				repo.On("MoveData", ctx, mock.Anything, mock.MatchedBy(func(d *entity.UserData) bool {
+					return d.UserID == tt.expectedOwner && d.VaultID == tt.vaultID && d.Info != "sealed"
+				})).Return(int64(4), nil)
+			}
+
+			version, err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).
+				MoveData(ctx, 1, &entity.UserData{ID: 5, InfoType: "text", Info: "sealed", Version: 3, VaultID: tt.vaultID})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				repo.AssertNotCalled(t, "MoveData", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(4), version)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDataService_IndexData(t *testing.T) {
+	ctx := context.Background()
+	tokens := []string{"t1"}
+
+	t.Run("запись хранилища индексируется от имени владельца", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		access := new(VaultAccessMock)
+		access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleWrite, OwnerID: 2, VaultID: 7}, nil)
+		repo.On("IndexData", ctx, 2, 5, tokens).Return(nil)
+
+		err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).IndexData(ctx, 1, 5, tokens)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("только чтение", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		access := new(VaultAccessMock)
+		access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleRead, OwnerID: 2, VaultID: 7}, nil)
+
+		err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).IndexData(ctx, 1, 5, tokens)
+
+		assert.ErrorIs(t, err, helper.ErrAccessDenied)
+		repo.AssertNotCalled(t, "IndexData", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("записи нет", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		repo.On("IndexData", ctx, 1, 5, tokens).Return(helper.ErrDataNotFound)
+
+		err := NewDataService(repo, personalAccess{}, newTestEncryptionService(t), newMemoryBlobStore()).
+			IndexData(ctx, 1, 5, tokens)
+
+		assert.ErrorIs(t, err, helper.ErrDataNotFound)
+	})
+}
+
+func TestDataService_ListVersions(t *testing.T) {
+	ctx := context.Background()
+	es := newTestEncryptionService(t)
+
+	info, err := es.Encrypt("old password")
+	require.NoError(t, err)
+	meta, err := es.Encrypt("почта")
+	require.NoError(t, err)
+
+	repo := new(DataRepoMock)
+	access := new(VaultAccessMock)
+	access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleRead, OwnerID: 2, VaultID: 7}, nil)
+	repo.On("ListVersions", ctx, 2, 5).
+		Return([]*entity.DataVersion{{DataID: 5, Version: 3, InfoType: "text", Info: info, Meta: meta}}, nil)
+
+	versions, vaultID, err := NewDataService(repo, access, es, newMemoryBlobStore()).ListVersions(ctx, 1, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, vaultID)
+	require.Len(t, versions, 1)
+	assert.Equal(t, "old password", versions[0].Info)
+	assert.Equal(t, "почта", versions[0].Meta)
+}
+
+func TestDataService_RestoreVersion(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		setup           func(repo *DataRepoMock, access *VaultAccessMock)
+		expectedVersion int64
+		expectedErr     error
+	}{
+		{
+			name: "версия восстановлена",
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("DataAccess", ctx, 1, 5).
+					Return(&entity.DataAccess{Role: entity.RoleWrite, OwnerID: 2, VaultID: 7}, nil)
+				repo.On("RestoreVersion", ctx, 2, 5, int64(3)).Return(int64(6), nil)
+			},
+			expectedVersion: 6,
+		},
+		{
+			name: "только чтение",
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("DataAccess", ctx, 1, 5).
+					Return(&entity.DataAccess{Role: entity.RoleRead, OwnerID: 2, VaultID: 7}, nil)
+			},
+			expectedErr: helper.ErrAccessDenied,
+		},
+		{
+			name: "версии нет",
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleOwner, OwnerID: 1}, nil)
+				repo.On("RestoreVersion", ctx, 1, 5, int64(3)).Return(int64(0), helper.ErrVersionNotFound)
+			},
+			expectedErr: helper.ErrVersionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(DataRepoMock)
+			access := new(VaultAccessMock)
+			tt.setup(repo, access)
+
+			version, err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).
+				RestoreVersion(ctx, 1, 5, 3)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedVersion, version)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDataService_ListTrash(t *testing.T) {
+	ctx := context.Background()
+	encryptionService := newTestEncryptionService(t)
+	meta, err := encryptionService.Encrypt("почта")
+	require.NoError(t, err)
+	deleted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("личная корзина", func(t *testing.T) {
+		repo := new(DataRepoMock)
+		repo.On("ListTrash", ctx, 1, 0).Return([]*entity.UserData{{ID: 5, Meta: meta, DeletedAt: deleted}}, nil)
+
+		items, err := NewDataService(repo, personalAccess{}, encryptionService, newMemoryBlobStore()).
+			ListTrash(ctx, 1, 0)
+
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "почта", items[0].Meta)
+		assert.Equal(t, deleted, items[0].DeletedAt)
+	})
+
+	t.Run("чужое хранилище", func(t *testing.T) {
+		repo := new(DataRepoMock)
+
+		_, err := NewDataService(repo, personalAccess{}, encryptionService, newMemoryBlobStore()).
+			ListTrash(ctx, 1, 7)
+
+		assert.ErrorIs(t, err, helper.ErrVaultNotFound)
+		repo.AssertNotCalled(t, "ListTrash", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDataService_RestoreData(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		setup           func(repo *DataRepoMock, access *VaultAccessMock)
+		expectedVersion int64
+		expectedErr     error
+	}{
+		{
+			name: "запись восстановлена",
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("TrashAccess", ctx, 1, 5).
+					Return(&entity.DataAccess{Role: entity.RoleWrite, OwnerID: 2, VaultID: 7}, nil)
+				repo.On("RestoreData", ctx, 2, 5).Return(int64(4), nil)
+			},
+			expectedVersion: 4,
+		},
+		{
+			name: "только чтение",
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("TrashAccess", ctx, 1, 5).
+					Return(&entity.DataAccess{Role: entity.RoleRead, OwnerID: 2, VaultID: 7}, nil)
+			},
+			expectedErr: helper.ErrAccessDenied,
+		},
+		{
+			name: "записи нет в корзине",
+			setup: func(repo *DataRepoMock, access *VaultAccessMock) {
+				access.On("TrashAccess", ctx, 1, 5).Return(nil, helper.ErrDataNotFound)
+			},
+			expectedErr: helper.ErrDataNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(DataRepoMock)
+			access := new(VaultAccessMock)
+			tt.setup(repo, access)
+
+			version, err := NewDataService(repo, access, newTestEncryptionService(t), newMemoryBlobStore()).
+				RestoreData(ctx, 1, 5)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedVersion, version)
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -7,50 +7,94 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
 )
 
-type EncryptionService struct {
-	key []byte
-}
+// keyIDSeparator - отделяет идентификатор ключа от шифротекста.
+// В base64 этот символ не встречается, поэтому разбор однозначен.
+const keyIDSeparator = ":"
 
-func NewEncryptionService(key []byte) *EncryptionService {
-	return &EncryptionService{key: key}
+// EncryptionService - шифрует данные связкой ключей. Новые данные шифруются
+// последним (активным) ключом, при расшифровке ключ выбирается по префиксу.
+// Шифротексты без префикса, записанные до появления связки,
+// расшифровываются первым ключом.
+type EncryptionService struct {
+	ciphers  map[string]cipher.AEAD
+	activeID string
+	legacyID string
 }
 
-func (es *EncryptionService) Encrypt(plaintext string) (string, error) {
-	block, err := aes.NewCipher(es.key)
-	if err != nil {
-		return "", fmt.Errorf("ошибка создания шифра: %w", err)
+// NewEncryptionService - конструктор сервиса шифрования по связке ключей.
+func NewEncryptionService(keys []entity.EncryptionKey) (*EncryptionService, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("связка ключей пуста")
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", fmt.Errorf("ошибка создания GCM: %w", err)
+	ciphers := make(map[string]cipher.AEAD, len(keys))
+	for _, k := range keys {
+		if k.ID == "" || strings.Contains(k.ID, keyIDSeparator) {
+			return nil, fmt.Errorf("некорректный идентификатор ключа: %q", k.ID)
+		}
+		if _, ok := ciphers[k.ID]; ok {
+			return nil, fmt.Errorf("повторяющийся идентификатор ключа: %q", k.ID)
+		}
+
+		block, err := aes.NewCipher(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка создания шифра для ключа %q: %w", k.ID, err)
+		}
+		aesGCM, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка создания GCM для ключа %q: %w", k.ID, err)
+		}
+		ciphers[k.ID] = aesGCM
 	}
 
+	return &EncryptionService{
+		ciphers:  ciphers,
+		activeID: keys[len(keys)-1].ID,
+		legacyID: keys[0].ID,
+	}, nil
+}
+
+// ActiveKeyID - идентификатор ключа, которым шифруются новые данные.
+func (es *EncryptionService) ActiveKeyID() string {
+	return es.activeID
+}
+
+// IsActive - зашифрован ли шифротекст активным ключом.
+func (es *EncryptionService) IsActive(ciphertext string) bool {
+	return strings.HasPrefix(ciphertext, es.activeID+keyIDSeparator)
+}
+
+func (es *EncryptionService) Encrypt(plaintext string) (string, error) {
+	aesGCM := es.ciphers[es.activeID]
+
 	nonce := make([]byte, aesGCM.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return "", fmt.Errorf("ошибка генерации nonce: %w", err)
 	}
 
 	ciphertext := aesGCM.Seal(nonce, nonce, []byte(plaintext), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+	return es.activeID + keyIDSeparator + base64.StdEncoding.EncodeToString(ciphertext), nil
 }
 
 func (es *EncryptionService) Decrypt(ciphertext string) (string, error) {
-	data, err := base64.StdEncoding.DecodeString(ciphertext)
-	if err != nil {
-		return "", fmt.Errorf("ошибка декодирования base64: %w", err)
+	keyID, encoded, found := strings.Cut(ciphertext, keyIDSeparator)
+	if !found {
+		keyID, encoded = es.legacyID, ciphertext
 	}
 
-	block, err := aes.NewCipher(es.key)
-	if err != nil {
-		return "", fmt.Errorf("ошибка создания шифра: %w", err)
+	aesGCM, ok := es.ciphers[keyID]
+	if !ok {
+		return "", fmt.Errorf("неизвестный идентификатор ключа: %q", keyID)
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
+	data, err := base64.StdEncoding.DecodeString(encoded)
 	if err != nil {
-		return "", fmt.Errorf("ошибка создания GCM: %w", err)
+		return "", fmt.Errorf("ошибка декодирования base64: %w", err)
 	}
 
 	nonceSize := aesGCM.NonceSize()
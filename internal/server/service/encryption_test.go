@@ -1,45 +1,120 @@
 package service
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
 	"testing"
 
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var (
+	testKeyV1 = entity.EncryptionKey{ID: "v1", Key: []byte("01234567890123456789012345678901")}
+	testKeyV2 = entity.EncryptionKey{ID: "v2", Key: []byte("10987654321098765432109876543210")}
+)
+
+func newTestEncryptionService(t *testing.T, keys ...entity.EncryptionKey) *EncryptionService {
+	t.Helper()
+
+	if len(keys) == 0 {
+		keys = []entity.EncryptionKey{testKeyV1}
+	}
+	es, err := NewEncryptionService(keys)
+	require.NoError(t, err)
+
+	return es
+}
+
 func TestEncryptionService_EncryptDecrypt(t *testing.T) {
-	key := []byte("01234567890123456789012345678901")
-	es := NewEncryptionService(key)
+	es := newTestEncryptionService(t)
 
 	plaintext := "Тестовое сообщение для шифрования"
 
 	ciphertext, err := es.Encrypt(plaintext)
 	assert.NoError(t, err)
 	assert.NotEqual(t, plaintext, ciphertext)
-	assert.NotEmpty(t, ciphertext)
+	assert.True(t, strings.HasPrefix(ciphertext, "v1:"))
 
 	decryptedText, err := es.Decrypt(ciphertext)
 	assert.NoError(t, err)
 	assert.Equal(t, plaintext, decryptedText)
 }
 
-func TestEncryptionService_InvalidKey(t *testing.T) {
-	key := []byte("короткий ключ")
-	_, err := NewEncryptionService(key).Encrypt("текст")
+func TestNewEncryptionService_InvalidKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []entity.EncryptionKey
+	}{
+		{name: "пустая связка", keys: nil},
+		{name: "короткий ключ", keys: []entity.EncryptionKey{{ID: "v1", Key: []byte("короткий ключ")}}},
+		{name: "пустой идентификатор", keys: []entity.EncryptionKey{{ID: "", Key: testKeyV1.Key}}},
+		{name: "разделитель в идентификаторе", keys: []entity.EncryptionKey{{ID: "v:1", Key: testKeyV1.Key}}},
+		{name: "повторяющийся идентификатор", keys: []entity.EncryptionKey{testKeyV1, testKeyV1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEncryptionService(tt.keys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncryptionService_Rotation(t *testing.T) {
+	oldService := newTestEncryptionService(t, testKeyV1)
+	newService := newTestEncryptionService(t, testKeyV1, testKeyV2)
+
+	oldCiphertext, err := oldService.Encrypt("старые данные")
+	require.NoError(t, err)
+
+	decrypted, err := newService.Decrypt(oldCiphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "старые данные", decrypted)
+	assert.False(t, newService.IsActive(oldCiphertext))
+
+	newCiphertext, err := newService.Encrypt("новые данные")
+	require.NoError(t, err)
+	assert.True(t, newService.IsActive(newCiphertext))
+	assert.Equal(t, "v2", newService.ActiveKeyID())
+
+	_, err = oldService.Decrypt(newCiphertext)
 	assert.Error(t, err)
 }
 
-func TestEncryptionService_DecryptWithWrongKey(t *testing.T) {
-	key1 := []byte("01234567890123456789012345678901")
-	key2 := []byte("10987654321098765432109876543210")
+func TestEncryptionService_DecryptLegacy(t *testing.T) {
+	block, err := aes.NewCipher(testKeyV1.Key)
+	require.NoError(t, err)
+	aesGCM, err := cipher.NewGCM(block)
+	require.NoError(t, err)
 
-	es1 := NewEncryptionService(key1)
-	es2 := NewEncryptionService(key2)
+	nonce := make([]byte, aesGCM.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	legacy := base64.StdEncoding.EncodeToString(aesGCM.Seal(nonce, nonce, []byte("без префикса"), nil))
 
-	plaintext := "Тестовое сообщение"
+	es := newTestEncryptionService(t, testKeyV1, testKeyV2)
 
-	ciphertext, err := es1.Encrypt(plaintext)
+	decrypted, err := es.Decrypt(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, "без префикса", decrypted)
+	assert.False(t, es.IsActive(legacy))
+}
+
+func TestEncryptionService_DecryptWithWrongKey(t *testing.T) {
+	es1 := newTestEncryptionService(t, testKeyV1)
+	es2 := newTestEncryptionService(t, entity.EncryptionKey{ID: "v1", Key: testKeyV2.Key})
+
+	ciphertext, err := es1.Encrypt("Тестовое сообщение")
 	assert.NoError(t, err)
 
 	_, err = es2.Decrypt(ciphertext)
 	assert.Error(t, err)
+
+	_, err = newTestEncryptionService(t, testKeyV2).Decrypt(ciphertext)
+	assert.Error(t, err)
 }
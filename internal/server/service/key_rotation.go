@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+)
+
+type rotationRepo interface {
+	ListForRotation(ctx context.Context, activePrefix string, afterID, limit int) ([]*entity.UserData, error)
+	ReplaceEncrypted(ctx context.Context, old, updated *entity.UserData) (bool, error)
+	ListVersionsForRotation(ctx context.Context, activePrefix string, afterID, limit int) ([]*entity.DataVersion, error)
+	ReplaceEncryptedVersion(ctx context.Context, updated *entity.DataVersion) (bool, error)
+}
+
+type secretRotationRepo interface {
+	ListTOTPSecretsForRotation(ctx context.Context, activePrefix string, afterID, limit int) ([]*entity.TOTPSecret, error)
+	ReplaceTOTPSecret(ctx context.Context, old, updated *entity.TOTPSecret) (bool, error)
+}
+
+type shareRotationRepo interface {
+	ListSharesForRotation(
+		ctx context.Context, activePrefix string, afterHash []byte, limit int,
+	) ([]*entity.SharePayload, error)
+	ReplaceEncryptedShare(ctx context.Context, updated *entity.SharePayload) (bool, error)
+}
+
+type keyRotator struct {
+	repo              rotationRepo
+	secrets           secretRotationRepo
+	shares            shareRotationRepo
+	encryptionService *EncryptionService
+	batchSize         int
+}
+
+// NewKeyRotator - конструктор сервиса перешифрования активным ключом
+// user_data, истории записей, TOTP-секретов и снимков по ссылкам.
+func NewKeyRotator(
+	repo rotationRepo,
+	secrets secretRotationRepo,
+	shares shareRotationRepo,
+	encryptionService *EncryptionService,
+	batchSize int,
+) *keyRotator {
+	return &keyRotator{
+		repo:              repo,
+		secrets:           secrets,
+		shares:            shares,
+		encryptionService: encryptionService,
+		batchSize:         batchSize,
+	}
+}
+
+// Rotate - перешифровывает активным ключом все записи, их прежние версии,
+// TOTP-секреты и снимки по ссылкам, зашифрованные старыми ключами, пачками по batchSize.
+// Возвращает количество перешифрованных значений. Значения, изменённые во
+// время ротации, пропускаются: их уже записали активным ключом.
+func (r *keyRotator) Rotate(ctx context.Context) (int, error) {
+	activePrefix := r.encryptionService.ActiveKeyID() + keyIDSeparator
+
+	rotated, err := r.rotateData(ctx, activePrefix)
+	if err != nil {
+		return rotated, err
+	}
+
+	versions, err := r.rotateVersions(ctx, activePrefix)
+	rotated += versions
+	if err != nil {
+		return rotated, err
+	}
+
+	secrets, err := r.rotateTOTPSecrets(ctx, activePrefix)
+	rotated += secrets
+	if err != nil {
+		return rotated, err
+	}
+
+	shares, err := r.rotateShares(ctx, activePrefix)
+	return rotated + shares, err
+}
+
+func (r *keyRotator) rotateData(ctx context.Context, activePrefix string) (int, error) {
+	rotated, afterID := 0, 0
+	for {
+		batch, err := r.repo.ListForRotation(ctx, activePrefix, afterID, r.batchSize)
+		if err != nil {
+			return rotated, fmt.Errorf("ошибка выборки записей для ротации: %w", err)
+		}
+		if len(batch) == 0 {
+			return rotated, nil
+		}
+
+		for _, data := range batch {
+			updated := &entity.UserData{ID: data.ID}
+			updated.Info, updated.Meta, err = r.reencrypt(data.Info, data.Meta)
+			if err != nil {
+				return rotated, fmt.Errorf("ошибка перешифрования записи %d: %w", data.ID, err)
+			}
+
+			ok, err := r.repo.ReplaceEncrypted(ctx, data, updated)
+			if err != nil {
+				return rotated, fmt.Errorf("ошибка сохранения записи %d: %w", data.ID, err)
+			}
+			if ok {
+				rotated++
+			}
+		}
+
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+func (r *keyRotator) rotateVersions(ctx context.Context, activePrefix string) (int, error) {
+	rotated, afterID := 0, 0
+	for {
+		batch, err := r.repo.ListVersionsForRotation(ctx, activePrefix, afterID, r.batchSize)
+		if err != nil {
+			return rotated, fmt.Errorf("ошибка выборки истории для ротации: %w", err)
+		}
+		if len(batch) == 0 {
+			return rotated, nil
+		}
+
+		for _, v := range batch {
+			updated := &entity.DataVersion{ID: v.ID}
+			updated.Info, updated.Meta, err = r.reencrypt(v.Info, v.Meta)
+			if err != nil {
+				return rotated, fmt.Errorf("ошибка перешифрования версии %d из истории: %w", v.ID, err)
+			}
+
+			ok, err := r.repo.ReplaceEncryptedVersion(ctx, updated)
+			if err != nil {
+				return rotated, fmt.Errorf("ошибка сохранения версии %d из истории: %w", v.ID, err)
+			}
+			if ok {
+				rotated++
+			}
+		}
+
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+func (r *keyRotator) rotateTOTPSecrets(ctx context.Context, activePrefix string) (int, error) {
+	rotated, afterID := 0, 0
+	for {
+		batch, err := r.secrets.ListTOTPSecretsForRotation(ctx, activePrefix, afterID, r.batchSize)
+		if err != nil {
+			return rotated, fmt.Errorf("ошибка выборки TOTP-секретов для ротации: %w", err)
+		}
+		if len(batch) == 0 {
+			return rotated, nil
+		}
+
+		for _, secret := range batch {
+			updated := &entity.TOTPSecret{UserID: secret.UserID}
+			updated.Secret, err = r.reencryptField(secret.Secret)
+			if err != nil {
+				return rotated, fmt.Errorf("ошибка перешифрования TOTP-секрета пользователя %d: %w", secret.UserID, err)
+			}
+
+			ok, err := r.secrets.ReplaceTOTPSecret(ctx, secret, updated)
+			if err != nil {
+				return rotated, fmt.Errorf("ошибка сохранения TOTP-секрета пользователя %d: %w", secret.UserID, err)
+			}
+			if ok {
+				rotated++
+			}
+		}
+
+		afterID = batch[len(batch)-1].UserID
+	}
+}
+
+func (r *keyRotator) rotateShares(ctx context.Context, activePrefix string) (int, error) {
+	rotated, afterHash := 0, []byte{}
+	for {
+		batch, err := r.shares.ListSharesForRotation(ctx, activePrefix, afterHash, r.batchSize)
+		if err != nil {
+			return rotated, fmt.Errorf("ошибка выборки ссылок для ротации: %w", err)
+		}
+		if len(batch) == 0 {
+			return rotated, nil
+		}
+
+		for _, share := range batch {
+			updated := &entity.SharePayload{IDHash: share.IDHash}
+			updated.Payload, err = r.reencryptField(share.Payload)
+			if err != nil {
+				return rotated, fmt.Errorf("ошибка перешифрования ссылки: %w", err)
+			}
+
+			ok, err := r.shares.ReplaceEncryptedShare(ctx, updated)
+			if err != nil {
+				return rotated, fmt.Errorf("ошибка сохранения ссылки: %w", err)
+			}
+			if ok {
+				rotated++
+			}
+		}
+
+		afterHash = batch[len(batch)-1].IDHash
+	}
+}
+
+// reencrypt - перешифровывает активным ключом те из полей, что зашифрованы другим.
+func (r *keyRotator) reencrypt(info, meta string) (string, string, error) {
+	info, err := r.reencryptField(info)
+	if err != nil {
+		return "", "", err
+	}
+	meta, err = r.reencryptField(meta)
+	if err != nil {
+		return "", "", err
+	}
+
+	return info, meta, nil
+}
+
+// reencryptField - перешифровывает поле активным ключом, если оно зашифровано другим.
+func (r *keyRotator) reencryptField(field string) (string, error) {
+	if r.encryptionService.IsActive(field) {
+		return field, nil
+	}
+
+	plaintext, err := r.encryptionService.Decrypt(field)
+	if err != nil {
+		return "", err
+	}
+
+	return r.encryptionService.Encrypt(plaintext)
+}
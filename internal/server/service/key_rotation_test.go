@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type RotationRepoMock struct {
+	mock.Mock
+}
+
+func (m *RotationRepoMock) ListForRotation(
+	ctx context.Context, activePrefix string, afterID, limit int,
+) ([]*entity.UserData, error) {
+	args := m.Called(ctx, activePrefix, afterID, limit)
+	items, _ := args.Get(0).([]*entity.UserData)
+	return items, args.Error(1)
+}
+
+func (m *RotationRepoMock) ReplaceEncrypted(ctx context.Context, old, updated *entity.UserData) (bool, error) {
+	args := m.Called(ctx, old, updated)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *RotationRepoMock) ListVersionsForRotation(
+	ctx context.Context, activePrefix string, afterID, limit int,
+) ([]*entity.DataVersion, error) {
+	args := m.Called(ctx, activePrefix, afterID, limit)
+	versions, _ := args.Get(0).([]*entity.DataVersion)
+	return versions, args.Error(1)
+}
+
+func (m *RotationRepoMock) ReplaceEncryptedVersion(ctx context.Context, updated *entity.DataVersion) (bool, error) {
+	args := m.Called(ctx, updated)
+	return args.Bool(0), args.Error(1)
+}
+
+type ShareRotationRepoMock struct {
+	mock.Mock
+}
+
+func (m *ShareRotationRepoMock) ListSharesForRotation(
+	ctx context.Context, activePrefix string, afterHash []byte, limit int,
+) ([]*entity.SharePayload, error) {
+	args := m.Called(ctx, activePrefix, afterHash, limit)
+	shares, _ := args.Get(0).([]*entity.SharePayload)
+	return shares, args.Error(1)
+}
+
+func (m *ShareRotationRepoMock) ReplaceEncryptedShare(ctx context.Context, updated *entity.SharePayload) (bool, error) {
+	args := m.Called(ctx, updated)
+	return args.Bool(0), args.Error(1)
+}
+
+// emptyShares - ссылок для ротации нет.
+func emptyShares(ctx context.Context, limit int) *ShareRotationRepoMock {
+	shares := new(ShareRotationRepoMock)
+	shares.On("ListSharesForRotation", ctx, "v2:", []byte{}, limit).Return([]*entity.SharePayload{}, nil)
+	return shares
+}
+
+func TestKeyRotator_Rotate(t *testing.T) {
+	ctx := context.Background()
+	oldService := newTestEncryptionService(t, testKeyV1)
+	newService := newTestEncryptionService(t, testKeyV1, testKeyV2)
+
+	encrypt := func(es *EncryptionService, plaintext string) string {
+		ciphertext, err := es.Encrypt(plaintext)
+		require.NoError(t, err)
+		return ciphertext
+	}
+
+	first := &entity.UserData{ID: 1, Info: encrypt(oldService, "info1"), Meta: encrypt(oldService, "meta1")}
+	second := &entity.UserData{ID: 2, Info: encrypt(oldService, "info2"), Meta: encrypt(newService, "meta2")}
+	third := &entity.UserData{ID: 5, Info: encrypt(oldService, "info3"), Meta: encrypt(oldService, "meta3")}
+
+	repo := new(RotationRepoMock)
+	repo.On("ListForRotation", ctx, "v2:", 0, 2).Return([]*entity.UserData{first, second}, nil)
+	repo.On("ListForRotation", ctx, "v2:", 2, 2).Return([]*entity.UserData{third}, nil)
+	repo.On("ListForRotation", ctx, "v2:", 5, 2).Return([]*entity.UserData{}, nil)
+	repo.On("ListVersionsForRotation", ctx, "v2:", 0, 2).Return([]*entity.DataVersion{}, nil)
+	secrets := new(TwoFactorRepoMock)
+	secrets.On("ListTOTPSecretsForRotation", ctx, "v2:", 0, 2).Return([]*entity.TOTPSecret{}, nil)
+
+	var saved []*entity.UserData
+	repo.On("ReplaceEncrypted", ctx, mock.Anything, mock.Anything).
+		Return(true, nil).Once().
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(2).(*entity.UserData)) })
+	repo.On("ReplaceEncrypted", ctx, mock.Anything, mock.Anything).
+		Return(false, nil).Once()
+	repo.On("ReplaceEncrypted", ctx, mock.Anything, mock.Anything).
+		Return(true, nil).Once().
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(2).(*entity.UserData)) })
+
+	rotated, err := NewKeyRotator(repo, secrets, emptyShares(ctx, 2), newService, 2).Rotate(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rotated)
+	require.Len(t, saved, 2)
+	for _, data := range saved {
+		assert.True(t, newService.IsActive(data.Info))
+		assert.True(t, newService.IsActive(data.Meta))
+	}
+
+	info, err := newService.Decrypt(saved[1].Info)
+	assert.NoError(t, err)
+	assert.Equal(t, "info3", info)
+
+	repo.AssertExpectations(t)
+}
+
+func TestKeyRotator_Rotate_History(t *testing.T) {
+	ctx := context.Background()
+	oldService := newTestEncryptionService(t, testKeyV1)
+	newService := newTestEncryptionService(t, testKeyV1, testKeyV2)
+
+	info, err := oldService.Encrypt("old info")
+	require.NoError(t, err)
+	meta, err := newService.Encrypt("meta")
+	require.NoError(t, err)
+
+	repo := new(RotationRepoMock)
+	repo.On("ListForRotation", ctx, "v2:", 0, 10).Return([]*entity.UserData{}, nil)
+	repo.On("ListVersionsForRotation", ctx, "v2:", 0, 10).
+		Return([]*entity.DataVersion{{ID: 4, Info: info, Meta: meta}}, nil)
+	repo.On("ListVersionsForRotation", ctx, "v2:", 4, 10).Return([]*entity.DataVersion{}, nil)
+	secrets := new(TwoFactorRepoMock)
+	secrets.On("ListTOTPSecretsForRotation", ctx, "v2:", 0, 10).Return([]*entity.TOTPSecret{}, nil)
+
+	var saved *entity.DataVersion
+	repo.On("ReplaceEncryptedVersion", ctx, mock.Anything).
+		Return(true, nil).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(*entity.DataVersion) })
+
+	rotated, err := NewKeyRotator(repo, secrets, emptyShares(ctx, 10), newService, 10).Rotate(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotated)
+	assert.Equal(t, 4, saved.ID)
+	assert.True(t, newService.IsActive(saved.Info))
+	assert.Equal(t, meta, saved.Meta)
+
+	plaintext, err := newService.Decrypt(saved.Info)
+	assert.NoError(t, err)
+	assert.Equal(t, "old info", plaintext)
+	repo.AssertExpectations(t)
+}
+
+func TestKeyRotator_Rotate_TOTPSecret(t *testing.T) {
+	ctx := context.Background()
+	oldService := newTestEncryptionService(t, testKeyV1)
+	rotatingService := newTestEncryptionService(t, testKeyV1, testKeyV2)
+	newService := newTestEncryptionService(t, testKeyV2)
+
+	encrypted, err := oldService.Encrypt(testTOTPSecret)
+	require.NoError(t, err)
+	old := &entity.TOTPSecret{UserID: 1, Secret: encrypted}
+
+	repo := new(RotationRepoMock)
+	repo.On("ListForRotation", ctx, "v2:", 0, 10).Return([]*entity.UserData{}, nil)
+	repo.On("ListVersionsForRotation", ctx, "v2:", 0, 10).Return([]*entity.DataVersion{}, nil)
+
+	secrets := new(TwoFactorRepoMock)
+	secrets.On("ListTOTPSecretsForRotation", ctx, "v2:", 0, 10).Return([]*entity.TOTPSecret{old}, nil)
+	secrets.On("ListTOTPSecretsForRotation", ctx, "v2:", 1, 10).Return([]*entity.TOTPSecret{}, nil)
+	var saved *entity.TOTPSecret
+	secrets.On("ReplaceTOTPSecret", ctx, old, mock.Anything).
+		Return(true, nil).
+		Run(func(args mock.Arguments) { saved = args.Get(2).(*entity.TOTPSecret) })
+
+	rotated, err := NewKeyRotator(repo, secrets, emptyShares(ctx, 10), rotatingService, 10).Rotate(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotated)
+	require.NotNil(t, saved)
+	assert.Equal(t, 1, saved.UserID)
+	assert.True(t, rotatingService.IsActive(saved.Secret))
+	secrets.AssertExpectations(t)
+
+	// Старый ключ убран из связки: вход со вторым фактором работает на новом.
+	now := time.Unix(1700000000, 0)
+	step := totp.Step(now)
+	code, err := totp.Code(testTOTPSecret, step)
+	require.NoError(t, err)
+
+	twoFactorRepo := new(TwoFactorRepoMock)
+	twoFactorRepo.On("TOTPState", ctx, 1).Return(&entity.TwoFactorState{Secret: saved.Secret, Enabled: true}, nil)
+	twoFactorRepo.On("UseTOTPStep", ctx, 1, step).Return(true, nil)
+	twoFactor := NewTwoFactorService(twoFactorRepo, &fakeLoginAttempts{}, newService)
+	twoFactor.now = func() time.Time { return now }
+
+	assert.NoError(t, twoFactor.Verify(ctx, 1, code))
+	twoFactorRepo.AssertExpectations(t)
+}
+
+func TestKeyRotator_Rotate_Shares(t *testing.T) {
+	ctx := context.Background()
+	oldService := newTestEncryptionService(t, testKeyV1)
+	newService := newTestEncryptionService(t, testKeyV1, testKeyV2)
+
+	payload, err := oldService.Encrypt("snapshot")
+	require.NoError(t, err)
+
+	repo := new(RotationRepoMock)
+	repo.On("ListForRotation", ctx, "v2:", 0, 10).Return([]*entity.UserData{}, nil)
+	repo.On("ListVersionsForRotation", ctx, "v2:", 0, 10).Return([]*entity.DataVersion{}, nil)
+	secrets := new(TwoFactorRepoMock)
+	secrets.On("ListTOTPSecretsForRotation", ctx, "v2:", 0, 10).Return([]*entity.TOTPSecret{}, nil)
+
+	shares := new(ShareRotationRepoMock)
+	shares.On("ListSharesForRotation", ctx, "v2:", []byte{}, 10).
+		Return([]*entity.SharePayload{{IDHash: []byte("hash"), Payload: payload}}, nil)
+	shares.On("ListSharesForRotation", ctx, "v2:", []byte("hash"), 10).Return([]*entity.SharePayload{}, nil)
+	var saved *entity.SharePayload
+	shares.On("ReplaceEncryptedShare", ctx, mock.Anything).
+		Return(true, nil).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(*entity.SharePayload) })
+
+	rotated, err := NewKeyRotator(repo, secrets, shares, newService, 10).Rotate(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotated)
+	assert.Equal(t, []byte("hash"), saved.IDHash)
+	assert.True(t, newService.IsActive(saved.Payload))
+
+	// Снимок открывается без старого ключа.
+	plaintext, err := newTestEncryptionService(t, testKeyV2).Decrypt(saved.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "snapshot", plaintext)
+	shares.AssertExpectations(t)
+}
+
+func TestKeyRotator_Rotate_Errors(t *testing.T) {
+	ctx := context.Background()
+	es := newTestEncryptionService(t, testKeyV1, testKeyV2)
+
+	t.Run("ошибка выборки", func(t *testing.T) {
+		repo := new(RotationRepoMock)
+		repo.On("ListForRotation", ctx, "v2:", 0, 10).Return(nil, errors.New("db error"))
+
+		_, err := NewKeyRotator(repo, new(TwoFactorRepoMock), new(ShareRotationRepoMock), es, 10).Rotate(ctx)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ошибка выборки записей для ротации")
+	})
+
+	t.Run("неизвестный ключ", func(t *testing.T) {
+		repo := new(RotationRepoMock)
+		repo.On("ListForRotation", ctx, "v2:", 0, 10).
+			Return([]*entity.UserData{{ID: 1, Info: "v0:AAAA", Meta: "v0:AAAA"}}, nil)
+
+		_, err := NewKeyRotator(repo, new(TwoFactorRepoMock), new(ShareRotationRepoMock), es, 10).Rotate(ctx)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ошибка перешифрования записи 1")
+		repo.AssertNotCalled(t, "ReplaceEncrypted", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ошибка выборки истории", func(t *testing.T) {
+		repo := new(RotationRepoMock)
+		repo.On("ListForRotation", ctx, "v2:", 0, 10).Return([]*entity.UserData{}, nil)
+		repo.On("ListVersionsForRotation", ctx, "v2:", 0, 10).Return(nil, errors.New("db error"))
+
+		_, err := NewKeyRotator(repo, new(TwoFactorRepoMock), new(ShareRotationRepoMock), es, 10).Rotate(ctx)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ошибка выборки истории для ротации")
+	})
+
+	t.Run("ошибка выборки TOTP-секретов", func(t *testing.T) {
+		repo := new(RotationRepoMock)
+		repo.On("ListForRotation", ctx, "v2:", 0, 10).Return([]*entity.UserData{}, nil)
+		repo.On("ListVersionsForRotation", ctx, "v2:", 0, 10).Return([]*entity.DataVersion{}, nil)
+		secrets := new(TwoFactorRepoMock)
+		secrets.On("ListTOTPSecretsForRotation", ctx, "v2:", 0, 10).Return(nil, errors.New("db error"))
+
+		_, err := NewKeyRotator(repo, secrets, new(ShareRotationRepoMock), es, 10).Rotate(ctx)
+
+		assert.ErrorContains(t, err, "ошибка выборки TOTP-секретов для ротации")
+	})
+
+	t.Run("ошибка выборки ссылок", func(t *testing.T) {
+		repo := new(RotationRepoMock)
+		repo.On("ListForRotation", ctx, "v2:", 0, 10).Return([]*entity.UserData{}, nil)
+		repo.On("ListVersionsForRotation", ctx, "v2:", 0, 10).Return([]*entity.DataVersion{}, nil)
+		secrets := new(TwoFactorRepoMock)
+		secrets.On("ListTOTPSecretsForRotation", ctx, "v2:", 0, 10).Return([]*entity.TOTPSecret{}, nil)
+		shares := new(ShareRotationRepoMock)
+		shares.On("ListSharesForRotation", ctx, "v2:", []byte{}, 10).Return(nil, errors.New("db error"))
+
+		_, err := NewKeyRotator(repo, secrets, shares, es, 10).Rotate(ctx)
+
+		assert.ErrorContains(t, err, "ошибка выборки ссылок для ротации")
+	})
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type password struct {
+	log logger.CustomLogger
+}
+
+// NewPassword - конструктор сервиса проверки паролей.
+func NewPassword(log logger.CustomLogger) *password {
+	return &password{log: log}
+}
+
+// Compare - сверяет пароль с bcrypt-хешем из базы.
+func (p *password) Compare(hash, plain string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return helper.ErrInvalidCredentials
+	}
+
+	p.log.LogInfo("ошибка при сравнении хеша пароля: ", err)
+
+	return helper.ErrInternalServer
+}
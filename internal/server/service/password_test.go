@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPassword_Compare(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	passwordService := NewPassword(&mockLogger{})
+
+	tests := []struct {
+		name        string
+		hash        string
+		plain       string
+		expectedErr error
+	}{
+		{
+			name:        "верный пароль",
+			hash:        string(hash),
+			plain:       "password123",
+			expectedErr: nil,
+		},
+		{
+			name:        "неверный пароль",
+			hash:        string(hash),
+			plain:       "wrong",
+			expectedErr: helper.ErrInvalidCredentials,
+		},
+		{
+			name:        "битый хеш",
+			hash:        "not-a-hash",
+			plain:       "password123",
+			expectedErr: helper.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := passwordService.Compare(tt.hash, tt.plain)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
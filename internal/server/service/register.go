@@ -1,6 +1,8 @@
 package service
 
 import (
+	"crypto/rand"
+
 	pb "github.com/NikolosHGW/goph-keeper/api/registerpb"
 	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
 	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
@@ -8,6 +10,14 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Параметры Argon2id по умолчанию для новых пользователей.
+const (
+	kdfSaltSize = 16
+	kdfTime     = 3
+	kdfMemory   = 64 * 1024
+	kdfThreads  = 4
+)
+
 type register struct {
 	log logger.CustomLogger
 }
@@ -25,9 +35,21 @@ func (u *register) CreateUser(req *pb.RegisterUserRequest) (*entity.User, error)
 		return nil, helper.ErrInternalServer
 	}
 
+	salt := make([]byte, kdfSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		u.log.LogInfo("ошибка при генерации соли KDF: ", err)
+		return nil, helper.ErrInternalServer
+	}
+
 	user := &entity.User{
 		Login:    req.Login,
 		Password: string(passwordHash),
+		KDFParams: entity.KDFParams{
+			Salt:    salt,
+			Time:    kdfTime,
+			Memory:  kdfMemory,
+			Threads: kdfThreads,
+		},
 	}
 
 	return user, nil
@@ -42,6 +42,14 @@ func TestRegister_CreateUser_Success(t *testing.T) {
 	if err != nil {
 		t.Errorf("Хеш пароля не соответствует исходному паролю: %v", err)
 	}
+
+	if len(user.Salt) != kdfSaltSize {
+		t.Errorf("Ожидалась соль KDF длиной %d, но получена длиной %d", kdfSaltSize, len(user.Salt))
+	}
+
+	if user.Time == 0 || user.Memory == 0 || user.Threads == 0 {
+		t.Errorf("Ожидались заполненные параметры KDF, но получены %+v", user.KDFParams)
+	}
 }
 
 func TestRegister_CreateUser_HashError(t *testing.T) {
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+)
+
+const refreshTokenSize = 32
+
+type sessionRepo interface {
+	Create(ctx context.Context, session *entity.Session, refreshHash []byte) error
+	Rotate(ctx context.Context, oldHash, newHash []byte, expiresAt time.Time) (*entity.Session, error)
+	RevokeByPreviousHash(ctx context.Context, hash []byte) error
+	ListActive(ctx context.Context, userID int) ([]*entity.Session, error)
+	Revoke(ctx context.Context, userID, sessionID int) error
+	IsActive(ctx context.Context, sessionID int) (bool, error)
+}
+
+type accessTokenIssuer interface {
+	GenerateJWT(userID, sessionID int) (string, time.Time, error)
+}
+
+type sessionService struct {
+	repo       sessionRepo
+	tokens     accessTokenIssuer
+	refreshTTL time.Duration
+}
+
+// NewSessionService - конструктор сервиса сессий. refreshTTL - сколько живёт
+// refresh-токен; каждое обновление продлевает сессию на этот срок.
+func NewSessionService(repo sessionRepo, tokens accessTokenIssuer, refreshTTL time.Duration) *sessionService {
+	return &sessionService{repo: repo, tokens: tokens, refreshTTL: refreshTTL}
+}
+
+// Open - создаёт сессию пользователя на устройстве и выдаёт первую пару токенов.
+func (s *sessionService) Open(ctx context.Context, user *entity.User, deviceName string) (*entity.AuthResult, error) {
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &entity.Session{
+		UserID:     user.ID,
+		DeviceName: deviceName,
+		ExpiresAt:  time.Now().Add(s.refreshTTL),
+	}
+	if err := s.repo.Create(ctx, session, refreshHash); err != nil {
+		return nil, fmt.Errorf("ошибка создания сессии: %w", err)
+	}
+
+	result, err := s.issue(session, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	result.KDF = user.KDFParams
+
+	return result, nil
+}
+
+// Refresh - обменивает refresh-токен на новую пару токенов. Старый refresh-токен
+// после этого недействителен. Повторное предъявление уже использованного токена
+// означает, что он утёк, поэтому сессия, которой он принадлежал, отзывается.
+func (s *sessionService) Refresh(ctx context.Context, refreshToken string) (*entity.AuthResult, error) {
+	oldHash := hashRefreshToken(refreshToken)
+	newToken, newHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.repo.Rotate(ctx, oldHash, newHash, time.Now().Add(s.refreshTTL))
+	if errors.Is(err, helper.ErrInvalidRefresh) {
+		if revokeErr := s.repo.RevokeByPreviousHash(ctx, oldHash); revokeErr != nil {
+			return nil, fmt.Errorf("ошибка отзыва сессии: %w", revokeErr)
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка обновления сессии: %w", err)
+	}
+
+	return s.issue(session, newToken)
+}
+
+// List - возвращает активные сессии пользователя.
+func (s *sessionService) List(ctx context.Context, userID int) ([]*entity.Session, error) {
+	sessions, err := s.repo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения сессий из репозитория: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Revoke - отзывает сессию пользователя. Access-токены сессии перестают
+// приниматься сразу, не дожидаясь истечения срока.
+func (s *sessionService) Revoke(ctx context.Context, userID, sessionID int) error {
+	if err := s.repo.Revoke(ctx, userID, sessionID); err != nil {
+		return fmt.Errorf("ошибка отзыва сессии: %w", err)
+	}
+
+	return nil
+}
+
+// IsActive - проверяет, что сессия не отозвана и не истекла.
+func (s *sessionService) IsActive(ctx context.Context, sessionID int) (bool, error) {
+	active, err := s.repo.IsActive(ctx, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка проверки сессии: %w", err)
+	}
+
+	return active, nil
+}
+
+func (s *sessionService) issue(session *entity.Session, refreshToken string) (*entity.AuthResult, error) {
+	accessToken, expiresAt, err := s.tokens.GenerateJWT(session.UserID, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при генерации токена: %w", err)
+	}
+
+	return &entity.AuthResult{
+		Token:           accessToken,
+		RefreshToken:    refreshToken,
+		AccessExpiresAt: expiresAt,
+	}, nil
+}
+
+// newRefreshToken - генерирует случайный refresh-токен. В базе хранится
+// только его хеш, чтобы утечка таблицы сессий не давала доступ к аккаунтам.
+func newRefreshToken() (string, []byte, error) {
+	raw := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("ошибка генерации refresh-токена: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type SessionRepoMock struct {
+	mock.Mock
+}
+
+func (m *SessionRepoMock) Create(ctx context.Context, session *entity.Session, refreshHash []byte) error {
+	args := m.Called(ctx, session, refreshHash)
+	return args.Error(0)
+}
+
+func (m *SessionRepoMock) Rotate(
+	ctx context.Context, oldHash, newHash []byte, expiresAt time.Time,
+) (*entity.Session, error) {
+	args := m.Called(ctx, oldHash, newHash, expiresAt)
+	session, _ := args.Get(0).(*entity.Session)
+	return session, args.Error(1)
+}
+
+func (m *SessionRepoMock) RevokeByPreviousHash(ctx context.Context, hash []byte) error {
+	args := m.Called(ctx, hash)
+	return args.Error(0)
+}
+
+func (m *SessionRepoMock) ListActive(ctx context.Context, userID int) ([]*entity.Session, error) {
+	args := m.Called(ctx, userID)
+	sessions, _ := args.Get(0).([]*entity.Session)
+	return sessions, args.Error(1)
+}
+
+func (m *SessionRepoMock) Revoke(ctx context.Context, userID, sessionID int) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *SessionRepoMock) IsActive(ctx context.Context, sessionID int) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+const testRefreshTTL = 30 * 24 * time.Hour
+
+func TestSessionService_Open(t *testing.T) {
+	ctx := context.Background()
+	repo := new(SessionRepoMock)
+	svc := NewSessionService(repo, NewToken(&mockLogger{}, "secret", testTokenTTL), testRefreshTTL)
+
+	user := &entity.User{ID: 1, KDFParams: entity.KDFParams{Salt: []byte("salt"), Time: 3}}
+
+	var storedHash []byte
+	repo.On("Create", ctx, mock.MatchedBy(func(s *entity.Session) bool {
+		return s.UserID == 1 && s.DeviceName == "laptop" &&
+			time.Until(s.ExpiresAt) > testRefreshTTL-time.Minute
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.Session).ID = 10
+		storedHash = args.Get(2).([]byte)
+	}).Return(nil)
+
+	result, err := svc.Open(ctx, user, "laptop")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, result.RefreshToken)
+	assert.Equal(t, hashRefreshToken(result.RefreshToken), storedHash)
+	assert.Equal(t, user.KDFParams, result.KDF)
+
+	claims, err := NewToken(&mockLogger{}, "secret", testTokenTTL).ValidateToken(result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claims.UserID)
+	assert.Equal(t, 10, claims.SessionID)
+	repo.AssertExpectations(t)
+}
+
+func TestSessionService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	oldHash := hashRefreshToken("old")
+
+	t.Run("ротация токена", func(t *testing.T) {
+		repo := new(SessionRepoMock)
+		svc := NewSessionService(repo, NewToken(&mockLogger{}, "secret", testTokenTTL), testRefreshTTL)
+
+		var newHash []byte
+		repo.On("Rotate", ctx, oldHash, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			newHash = args.Get(2).([]byte)
+		}).Return(&entity.Session{ID: 10, UserID: 1}, nil)
+
+		result, err := svc.Refresh(ctx, "old")
+		require.NoError(t, err)
+
+		assert.NotEqual(t, "old", result.RefreshToken)
+		assert.Equal(t, hashRefreshToken(result.RefreshToken), newHash)
+		assert.NotEmpty(t, result.Token)
+		repo.AssertNotCalled(t, "RevokeByPreviousHash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("повторное использование отзывает сессию", func(t *testing.T) {
+		repo := new(SessionRepoMock)
+		svc := NewSessionService(repo, NewToken(&mockLogger{}, "secret", testTokenTTL), testRefreshTTL)
+
+		repo.On("Rotate", ctx, oldHash, mock.Anything, mock.Anything).Return(nil, helper.ErrInvalidRefresh)
+		repo.On("RevokeByPreviousHash", ctx, oldHash).Return(nil)
+
+		result, err := svc.Refresh(ctx, "old")
+		assert.ErrorIs(t, err, helper.ErrInvalidRefresh)
+		assert.Nil(t, result)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка репозитория", func(t *testing.T) {
+		repo := new(SessionRepoMock)
+		svc := NewSessionService(repo, NewToken(&mockLogger{}, "secret", testTokenTTL), testRefreshTTL)
+
+		repo.On("Rotate", ctx, oldHash, mock.Anything, mock.Anything).Return(nil, helper.ErrInternalServer)
+
+		_, err := svc.Refresh(ctx, "old")
+		assert.ErrorIs(t, err, helper.ErrInternalServer)
+		assert.NotErrorIs(t, err, helper.ErrInvalidRefresh)
+	})
+}
+
+func TestSessionService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	repo := new(SessionRepoMock)
+	svc := NewSessionService(repo, NewToken(&mockLogger{}, "secret", testTokenTTL), testRefreshTTL)
+
+	repo.On("Revoke", ctx, 1, 2).Return(helper.ErrSessionNotFound)
+	repo.On("IsActive", ctx, 3).Return(false, errors.New("db error"))
+
+	assert.ErrorIs(t, svc.Revoke(ctx, 1, 2), helper.ErrSessionNotFound)
+
+	_, err := svc.IsActive(ctx, 3)
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
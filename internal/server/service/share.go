@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+)
+
+const (
+	shareIDSize         = 16
+	defaultShareTTL     = 24 * time.Hour
+	maxShareTTL         = 30 * 24 * time.Hour
+	maxShareViews       = 100
+	maxSharePayloadSize = 1 << 20
+)
+
+type shareRepo interface {
+	Create(ctx context.Context, share *entity.Share, idHash []byte) error
+	Redeem(ctx context.Context, idHash []byte) (*entity.Share, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type shareAccess interface {
+	DataAccess(ctx context.Context, userID, dataID int) (*entity.DataAccess, error)
+}
+
+type shareService struct {
+	repo              shareRepo
+	access            shareAccess
+	encryptionService *EncryptionService
+	now               func() time.Time
+}
+
+// NewShareService - конструктор сервиса одноразовых ссылок. Снимок записи
+// шифруется на клиенте ключом из ссылки; сервер дополнительно шифрует его
+// своим ключом, как и остальные данные.
+func NewShareService(repo shareRepo, access shareAccess, encryptionService *EncryptionService) *shareService {
+	return &shareService{repo: repo, access: access, encryptionService: encryptionService, now: time.Now}
+}
+
+// CreateShare - сохраняет снимок записи dataID и возвращает идентификатор
+// ссылки. Поделиться можно любой записью, которую пользователь может прочитать.
+// ttl 0 - срок по умолчанию, maxViews 0 - один просмотр.
+func (s *shareService) CreateShare(
+	ctx context.Context, userID, dataID int, payload string, ttl time.Duration, maxViews int,
+) (string, time.Time, error) {
+	if ttl == 0 {
+		ttl = defaultShareTTL
+	}
+	if maxViews == 0 {
+		maxViews = 1
+	}
+	if ttl < 0 || ttl > maxShareTTL || maxViews < 0 || maxViews > maxShareViews ||
+		payload == "" || len(payload) > maxSharePayloadSize {
+		return "", time.Time{}, helper.ErrInvalidShare
+	}
+
+	if _, err := s.access.DataAccess(ctx, userID, dataID); err != nil {
+		return "", time.Time{}, err
+	}
+
+	// Истёкшие снимки, которые так и не открыли, чистятся при создании новых.
+	if _, err := s.repo.DeleteExpired(ctx); err != nil {
+		return "", time.Time{}, fmt.Errorf("ошибка удаления истёкших ссылок: %w", err)
+	}
+
+	encrypted, err := s.encryptionService.Encrypt(payload)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ошибка шифрования снимка: %w", err)
+	}
+
+	raw := make([]byte, shareIDSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, fmt.Errorf("ошибка генерации ссылки: %w", err)
+	}
+	id := base64.RawURLEncoding.EncodeToString(raw)
+
+	share := &entity.Share{
+		UserID:    userID,
+		DataID:    dataID,
+		Payload:   encrypted,
+		ViewsLeft: maxViews,
+		ExpiresAt: s.now().Add(ttl).UTC(),
+	}
+	if err := s.repo.Create(ctx, share, hashShareID(id)); err != nil {
+		return "", time.Time{}, fmt.Errorf("ошибка сохранения ссылки: %w", err)
+	}
+
+	return id, share.ExpiresAt, nil
+}
+
+// RedeemShare - открывает ссылку без входа: списывает просмотр и возвращает
+// снимок, всё ещё зашифрованный ключом из ссылки.
+func (s *shareService) RedeemShare(ctx context.Context, id string) (*entity.Share, error) {
+	if id == "" {
+		return nil, helper.ErrShareNotFound
+	}
+
+	share, err := s.repo.Redeem(ctx, hashShareID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	share.Payload, err = s.encryptionService.Decrypt(share.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расшифровки снимка: %w", err)
+	}
+
+	return share, nil
+}
+
+func hashShareID(id string) []byte {
+	sum := sha256.Sum256([]byte(id))
+	return sum[:]
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type ShareRepoMock struct {
+	mock.Mock
+}
+
+func (m *ShareRepoMock) Create(ctx context.Context, share *entity.Share, idHash []byte) error {
+	return m.Called(ctx, share, idHash).Error(0)
+}
+
+func (m *ShareRepoMock) Redeem(ctx context.Context, idHash []byte) (*entity.Share, error) {
+	args := m.Called(ctx, idHash)
+	share, _ := args.Get(0).(*entity.Share)
+	return share, args.Error(1)
+}
+
+func (m *ShareRepoMock) DeleteExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestShareService_CreateAndRedeem(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(ShareRepoMock)
+	access := new(VaultAccessMock)
+	svc := NewShareService(repo, access, newTestEncryptionService(t))
+	svc.now = func() time.Time { return now }
+
+	var (
+		stored *entity.Share
+		hash   []byte
+	)
+	access.On("DataAccess", ctx, 1, 5).Return(&entity.DataAccess{Role: entity.RoleRead, OwnerID: 2, VaultID: 7}, nil)
+	repo.On("DeleteExpired", ctx).Return(int64(0), nil)
+	repo.On("Create", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*entity.Share)
+		hash = args.Get(2).([]byte)
+	}).Return(nil)
+
+	id, expiresAt, err := svc.CreateShare(ctx, 1, 5, "sealed", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(defaultShareTTL), expiresAt)
+	assert.Equal(t, 1, stored.ViewsLeft)
+	assert.NotEqual(t, "sealed", stored.Payload)
+	assert.NotContains(t, string(hash), id)
+
+	repo.On("Redeem", ctx, hash).Return(&entity.Share{Payload: stored.Payload, ExpiresAt: expiresAt}, nil)
+
+	share, err := svc.RedeemShare(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "sealed", share.Payload)
+	assert.Equal(t, 0, share.ViewsLeft)
+}
+
+func TestShareService_CreateShare_Invalid(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		payload     string
+		ttl         time.Duration
+		maxViews    int
+		setup       func(repo *ShareRepoMock, access *VaultAccessMock)
+		expectedErr error
+	}{
+		{name: "слишком долгий срок", payload: "x", ttl: maxShareTTL + time.Second, expectedErr: helper.ErrInvalidShare},
+		{name: "слишком много просмотров", payload: "x", maxViews: maxShareViews + 1, expectedErr: helper.ErrInvalidShare},
+		{name: "пустой снимок", expectedErr: helper.ErrInvalidShare},
+		{
+			name:    "чужая запись",
+			payload: "x",
+			setup: func(repo *ShareRepoMock, access *VaultAccessMock) {
+				access.On("DataAccess", ctx, 1, 5).Return(nil, helper.ErrDataNotFound)
+			},
+			expectedErr: helper.ErrDataNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(ShareRepoMock)
+			access := new(VaultAccessMock)
+			if tt.setup != nil {
+				tt.setup(repo, access)
+			}
+
+			_, _, err := NewShareService(repo, access, newTestEncryptionService(t)).
+				CreateShare(ctx, 1, 5, tt.payload, tt.ttl, tt.maxViews)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestShareService_RedeemShare_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := new(ShareRepoMock)
+	repo.On("Redeem", ctx, hashShareID("gone")).Return(nil, helper.ErrShareNotFound)
+	svc := NewShareService(repo, new(VaultAccessMock), newTestEncryptionService(t))
+
+	_, err := svc.RedeemShare(ctx, "gone")
+	assert.ErrorIs(t, err, helper.ErrShareNotFound)
+
+	_, err = svc.RedeemShare(ctx, "")
+	assert.ErrorIs(t, err, helper.ErrShareNotFound)
+}
@@ -11,56 +11,109 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-const TokenExp = time.Hour * 5
+const (
+	// challengeTTL - сколько действует промежуточный токен входа с 2FA.
+	challengeTTL = 5 * time.Minute
+	// challengePurpose - отличает промежуточный токен от прочих JWT сервиса.
+	challengePurpose = "2fa"
+)
 
 type token struct {
 	log       logger.CustomLogger
 	secretKey string
+	ttl       time.Duration
 }
 
-// NewToken - конструктор создания токен-сервиса.
-func NewToken(log logger.CustomLogger, secretKey string) *token {
-	return &token{log: log, secretKey: secretKey}
+// NewToken - конструктор создания токен-сервиса. ttl - время жизни access-токена.
+func NewToken(log logger.CustomLogger, secretKey string, ttl time.Duration) *token {
+	return &token{log: log, secretKey: secretKey, ttl: ttl}
 }
 
-// GenerateJWT - генерирует токен на основе секретного ключа.
-func (t *token) GenerateJWT(user *entity.User) (string, error) {
+// GenerateJWT - генерирует access-токен сессии на основе секретного ключа.
+func (t *token) GenerateJWT(userID, sessionID int) (string, time.Time, error) {
+	expiresAt := time.Now().Add(t.ttl)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, entity.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExp)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
-		UserID: user.ID,
+		UserID:    userID,
+		SessionID: sessionID,
 	})
 
 	if t.secretKey == "" {
 		t.log.LogInfo("для создании подписи токена секретный ключ пустой", fmt.Errorf("пустой secretKey"))
-		return "", helper.ErrInternalServer
+		return "", time.Time{}, helper.ErrInternalServer
 	}
 	tokenString, err := token.SignedString([]byte(t.secretKey))
 	if err != nil {
 		t.log.LogInfo("ошибки при создании подписи токена: ", err)
-		return "", helper.ErrInternalServer
+		return "", time.Time{}, helper.ErrInternalServer
 	}
 
-	return tokenString, nil
+	return tokenString, expiresAt, nil
 }
 
-// ValidateToken валидирует токен.
-func (s *token) ValidateToken(tokenString string) (int, error) {
+// ValidateToken валидирует токен и возвращает его клеймы.
+func (s *token) ValidateToken(tokenString string) (*entity.Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &entity.Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(s.secretKey), nil
 	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*entity.Claims); ok && token.Valid {
-		if claims.UserID == 0 {
-			s.log.LogInfo("UserID отсутствует в клеймах токена", errors.New("invalid token: missing UserID"))
-			return 0, errors.New("недействительный токен")
+		if claims.UserID == 0 || claims.SessionID == 0 {
+			s.log.LogInfo("UserID или SessionID отсутствует в клеймах токена",
+				errors.New("invalid token: missing UserID or SessionID"))
+			return nil, errors.New("недействительный токен")
 		}
-		return claims.UserID, nil
+		return claims, nil
+	}
+
+	return nil, errors.New("недействительный токен")
+}
+
+// GenerateChallenge - выдаёт промежуточный токен входа для пользователя,
+// у которого включён второй фактор. Токен не содержит сессии, поэтому
+// как access-токен не принимается.
+func (t *token) GenerateChallenge(userID int, login, deviceName string) (string, error) {
+	if t.secretKey == "" {
+		t.log.LogInfo("для создании подписи токена секретный ключ пустой", fmt.Errorf("пустой secretKey"))
+		return "", helper.ErrInternalServer
+	}
+
+	challenge := jwt.NewWithClaims(jwt.SigningMethodHS256, entity.ChallengeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(challengeTTL)),
+		},
+		Purpose:    challengePurpose,
+		Login:      login,
+		DeviceName: deviceName,
+		UserID:     userID,
+	})
+	tokenString, err := challenge.SignedString([]byte(t.secretKey))
+	if err != nil {
+		t.log.LogInfo("ошибки при создании подписи токена: ", err)
+		return "", helper.ErrInternalServer
+	}
+
+	return tokenString, nil
+}
+
+// ParseChallenge - проверяет промежуточный токен входа и возвращает его клеймы.
+func (t *token) ParseChallenge(challenge string) (*entity.ChallengeClaims, error) {
+	parsed, err := jwt.ParseWithClaims(challenge, &entity.ChallengeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(t.secretKey), nil
+	})
+	if err != nil {
+		return nil, helper.ErrInvalidChallenge
+	}
+
+	claims, ok := parsed.Claims.(*entity.ChallengeClaims)
+	if !ok || !parsed.Valid || claims.Purpose != challengePurpose || claims.UserID == 0 {
+		return nil, helper.ErrInvalidChallenge
 	}
 
-	return 0, errors.New("недействительный токен")
+	return claims, nil
 }
@@ -10,19 +10,19 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+const testTokenTTL = 15 * time.Minute
+
 func TestToken_GenerateJWT_Success(t *testing.T) {
 	mockLogger := &mockLogger{}
 	secretKey := "supersecretkey"
-	tokenService := NewToken(mockLogger, secretKey)
-
-	user := &entity.User{
-		ID: 1,
-	}
+	tokenService := NewToken(mockLogger, secretKey, testTokenTTL)
 
-	tokenString, err := tokenService.GenerateJWT(user)
+	before := time.Now()
+	tokenString, expiresAt, err := tokenService.GenerateJWT(1, 7)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, tokenString)
+	assert.WithinDuration(t, before.Add(testTokenTTL), expiresAt, time.Second)
 
 	parsedToken, err := jwt.ParseWithClaims(tokenString, &entity.Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(secretKey), nil
@@ -32,18 +32,15 @@ func TestToken_GenerateJWT_Success(t *testing.T) {
 
 	claims, ok := parsedToken.Claims.(*entity.Claims)
 	assert.True(t, ok)
-	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, 1, claims.UserID)
+	assert.Equal(t, 7, claims.SessionID)
 }
 
 func TestToken_GenerateJWT_EmptySecretKey(t *testing.T) {
 	mockLogger := &mockLogger{}
-	tokenService := NewToken(mockLogger, "")
-
-	user := &entity.User{
-		ID: 1,
-	}
+	tokenService := NewToken(mockLogger, "", testTokenTTL)
 
-	tokenString, err := tokenService.GenerateJWT(user)
+	tokenString, _, err := tokenService.GenerateJWT(1, 1)
 
 	assert.Empty(t, tokenString)
 	assert.ErrorIs(t, err, helper.ErrInternalServer)
@@ -52,94 +49,127 @@ func TestToken_GenerateJWT_EmptySecretKey(t *testing.T) {
 func TestToken_ValidateToken_Success(t *testing.T) {
 	mockLogger := &mockLogger{}
 	secretKey := "supersecretkey"
-	tokenService := NewToken(mockLogger, secretKey)
-
-	user := &entity.User{
-		ID: 1,
-	}
+	tokenService := NewToken(mockLogger, secretKey, testTokenTTL)
 
-	tokenString, err := tokenService.GenerateJWT(user)
+	tokenString, _, err := tokenService.GenerateJWT(1, 7)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, tokenString)
 
-	userID, err := tokenService.ValidateToken(tokenString)
+	claims, err := tokenService.ValidateToken(tokenString)
 	assert.NoError(t, err)
-	assert.Equal(t, user.ID, userID)
+	assert.Equal(t, 1, claims.UserID)
+	assert.Equal(t, 7, claims.SessionID)
 }
 
 func TestToken_ValidateToken_InvalidSignature(t *testing.T) {
 	mockLogger := &mockLogger{}
 	secretKey := "supersecretkey"
-	tokenService := NewToken(mockLogger, secretKey)
-
-	user := &entity.User{
-		ID: 1,
-	}
+	tokenService := NewToken(mockLogger, secretKey, testTokenTTL)
 
-	tokenString, err := tokenService.GenerateJWT(user)
+	tokenString, _, err := tokenService.GenerateJWT(1, 1)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, tokenString)
 
 	anotherSecretKey := "anothersecretkey"
-	anotherTokenService := NewToken(mockLogger, anotherSecretKey)
+	anotherTokenService := NewToken(mockLogger, anotherSecretKey, testTokenTTL)
 
-	userID, err := anotherTokenService.ValidateToken(tokenString)
+	claims, err := anotherTokenService.ValidateToken(tokenString)
 	assert.Error(t, err)
-	assert.Equal(t, 0, userID)
+	assert.Nil(t, claims)
 }
 
 func TestToken_ValidateToken_ExpiredToken(t *testing.T) {
 	mockLogger := &mockLogger{}
 	secretKey := "supersecretkey"
-	tokenService := NewToken(mockLogger, secretKey)
-
-	user := &entity.User{
-		ID: 1,
-	}
+	tokenService := NewToken(mockLogger, secretKey, testTokenTTL)
 
 	expiredToken := jwt.NewWithClaims(jwt.SigningMethodHS256, entity.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)), // истек час назад
 		},
-		UserID: user.ID,
+		UserID:    1,
+		SessionID: 1,
 	})
 
 	tokenString, err := expiredToken.SignedString([]byte(secretKey))
 	assert.NoError(t, err)
 	assert.NotEmpty(t, tokenString)
 
-	userID, err := tokenService.ValidateToken(tokenString)
+	claims, err := tokenService.ValidateToken(tokenString)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "token is expired")
-	assert.Equal(t, 0, userID)
+	assert.Nil(t, claims)
 }
 
 func TestToken_ValidateToken_InvalidClaims(t *testing.T) {
 	mockLogger := &mockLogger{}
 	secretKey := "supersecretkey"
-	tokenService := NewToken(mockLogger, secretKey)
+	tokenService := NewToken(mockLogger, secretKey, testTokenTTL)
+
+	tests := []struct {
+		name   string
+		claims jwt.Claims
+	}{
+		{
+			name: "без UserID",
+			claims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(testTokenTTL)),
+			},
+		},
+		{
+			name: "токен без сессии",
+			claims: entity.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(testTokenTTL)),
+				},
+				UserID: 1,
+			},
+		},
+	}
 
-	invalidClaimsToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExp)),
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims).SignedString([]byte(secretKey))
+			assert.NoError(t, err)
 
-	tokenString, err := invalidClaimsToken.SignedString([]byte(secretKey))
-	assert.NoError(t, err)
-	assert.NotEmpty(t, tokenString)
-
-	userID, err := tokenService.ValidateToken(tokenString)
-	assert.Error(t, err)
-	assert.Equal(t, 0, userID)
+			claims, err := tokenService.ValidateToken(tokenString)
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+		})
+	}
 }
 
 func TestToken_ValidateToken_EmptyToken(t *testing.T) {
 	mockLogger := &mockLogger{}
 	secretKey := "supersecretkey"
-	tokenService := NewToken(mockLogger, secretKey)
+	tokenService := NewToken(mockLogger, secretKey, testTokenTTL)
 
-	tokenString := ""
-
-	userID, err := tokenService.ValidateToken(tokenString)
+	claims, err := tokenService.ValidateToken("")
 	assert.Error(t, err)
-	assert.Equal(t, 0, userID)
+	assert.Nil(t, claims)
+}
+
+func TestToken_Challenge(t *testing.T) {
+	tokenService := NewToken(&mockLogger{}, "supersecretkey", testTokenTTL)
+
+	challenge, err := tokenService.GenerateChallenge(1, "user", "laptop")
+	assert.NoError(t, err)
+
+	claims, err := tokenService.ParseChallenge(challenge)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, claims.UserID)
+	assert.Equal(t, "user", claims.Login)
+	assert.Equal(t, "laptop", claims.DeviceName)
+
+	accessClaims, err := tokenService.ValidateToken(challenge)
+	assert.Error(t, err, "промежуточный токен не должен приниматься как access-токен")
+	assert.Nil(t, accessClaims)
+
+	accessToken, _, err := tokenService.GenerateJWT(1, 7)
+	assert.NoError(t, err)
+	_, err = tokenService.ParseChallenge(accessToken)
+	assert.ErrorIs(t, err, helper.ErrInvalidChallenge)
+
+	_, err = NewToken(&mockLogger{}, "anothersecretkey", testTokenTTL).ParseChallenge(challenge)
+	assert.ErrorIs(t, err, helper.ErrInvalidChallenge)
 }
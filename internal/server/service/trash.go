@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// trashPurgeBatch - сколько записей удаляется из корзины за один запрос.
+const trashPurgeBatch = 500
+
+type trashRepo interface {
+	PurgeTrash(ctx context.Context, before time.Time, limit int) (int, []string, error)
+}
+
+type trashPurger struct {
+	repo      trashRepo
+	blobStore blobStore
+	retention time.Duration
+}
+
+// NewTrashPurger - конструктор сервиса, окончательно удаляющего записи,
+// которые пролежали в корзине дольше retention.
+func NewTrashPurger(repo trashRepo, blobStore blobStore, retention time.Duration) *trashPurger {
+	return &trashPurger{
+		repo:      repo,
+		blobStore: blobStore,
+		retention: retention,
+	}
+}
+
+// Purge - удаляет просроченные записи корзины пачками вместе с их блобами
+// и возвращает число удалённых записей. Блоб удаляется после записи, так что
+// при ошибке в хранилище может остаться файл без записи, но не наоборот.
+func (p *trashPurger) Purge(ctx context.Context) (int, error) {
+	before := time.Now().Add(-p.retention)
+
+	total := 0
+	for {
+		purged, blobKeys, err := p.repo.PurgeTrash(ctx, before, trashPurgeBatch)
+		if err != nil {
+			return total, fmt.Errorf("ошибка очистки корзины: %w", err)
+		}
+		total += purged
+
+		for _, key := range blobKeys {
+			if err := p.blobStore.Delete(key); err != nil {
+				return total, fmt.Errorf("ошибка удаления блоба %s: %w", key, err)
+			}
+		}
+
+		if purged < trashPurgeBatch {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TrashRepoMock struct {
+	mock.Mock
+}
+
+func (m *TrashRepoMock) PurgeTrash(ctx context.Context, before time.Time, limit int) (int, []string, error) {
+	args := m.Called(ctx, before, limit)
+	keys, _ := args.Get(1).([]string)
+	return args.Int(0), keys, args.Error(2)
+}
+
+func TestTrashPurger_Purge(t *testing.T) {
+	ctx := context.Background()
+	retention := 24 * time.Hour
+	expired := mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= retention && time.Since(before) < retention+time.Minute
+	})
+
+	t.Run("несколько пачек", func(t *testing.T) {
+		store := newMemoryBlobStore()
+		store.blobs["a"] = []byte("a")
+		store.blobs["b"] = []byte("b")
+		store.blobs["live"] = []byte("live")
+
+		repo := new(TrashRepoMock)
+		repo.On("PurgeTrash", ctx, expired, trashPurgeBatch).Return(trashPurgeBatch, []string{"a"}, nil).Once()
+		repo.On("PurgeTrash", ctx, expired, trashPurgeBatch).Return(3, []string{"b"}, nil).Once()
+
+		purged, err := NewTrashPurger(repo, store, retention).Purge(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, trashPurgeBatch+3, purged)
+		assert.Equal(t, map[string][]byte{"live": []byte("live")}, store.blobs)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка репозитория", func(t *testing.T) {
+		repo := new(TrashRepoMock)
+		repo.On("PurgeTrash", ctx, expired, trashPurgeBatch).Return(0, nil, errors.New("db"))
+
+		purged, err := NewTrashPurger(repo, newMemoryBlobStore(), retention).Purge(ctx)
+
+		assert.Error(t, err)
+		assert.Zero(t, purged)
+	})
+}
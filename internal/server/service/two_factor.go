@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/internal/totp"
+)
+
+const (
+	totpIssuer = "GophKeeper"
+	// totpSkew - сколько соседних шагов принимается из-за расхождения часов.
+	totpSkew          = 1
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type twoFactorRepo interface {
+	TOTPState(ctx context.Context, userID int) (*entity.TwoFactorState, error)
+	SaveTOTPSecret(ctx context.Context, userID int, secret string, recoveryHashes [][]byte) error
+	EnableTOTP(ctx context.Context, userID int, step int64) error
+	DisableTOTP(ctx context.Context, userID int) error
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int, hash []byte) (bool, error)
+}
+
+type loginAttempts interface {
+	IsLocked(ctx context.Context, login string) (bool, error)
+	RegisterFailure(ctx context.Context, login string) error
+}
+
+type twoFactorService struct {
+	repo              twoFactorRepo
+	attempts          loginAttempts
+	encryptionService *EncryptionService
+	now               func() time.Time
+}
+
+// NewTwoFactorService - конструктор сервиса второго фактора. TOTP-секреты
+// хранятся зашифрованными тем же ключом, что и данные пользователей.
+// Неверные коды при включении и выключении 2FA учитываются в attempts
+// так же, как при входе.
+func NewTwoFactorService(
+	repo twoFactorRepo, attempts loginAttempts, encryptionService *EncryptionService,
+) *twoFactorService {
+	return &twoFactorService{repo: repo, attempts: attempts, encryptionService: encryptionService, now: time.Now}
+}
+
+// Enroll - создаёт новый секрет и коды восстановления. Второй фактор
+// включается только после Confirm, поэтому повторный вызов до подтверждения
+// просто заменяет секрет.
+func (s *twoFactorService) Enroll(ctx context.Context, userID int) (*entity.TOTPEnrollment, error) {
+	state, err := s.repo.TOTPState(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения состояния 2FA: %w", err)
+	}
+	if state.Enabled {
+		return nil, helper.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.encryptionService.Encrypt(secret)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка шифрования TOTP-секрета: %w", err)
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([][]byte, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := s.repo.SaveTOTPSecret(ctx, userID, encrypted, hashes); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения TOTP-секрета: %w", err)
+	}
+
+	return &entity.TOTPEnrollment{
+		URI:           totp.URI(totpIssuer, state.Login, secret),
+		Secret:        secret,
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Confirm - включает второй фактор, если код из аутентификатора подходит
+// к секрету, выданному в Enroll.
+func (s *twoFactorService) Confirm(ctx context.Context, userID int, code string) error {
+	state, err := s.repo.TOTPState(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения состояния 2FA: %w", err)
+	}
+	if state.Enabled {
+		return helper.ErrTwoFactorEnabled
+	}
+	if state.Secret == "" {
+		return helper.ErrTOTPNotEnrolled
+	}
+
+	secret, err := s.encryptionService.Decrypt(state.Secret)
+	if err != nil {
+		return fmt.Errorf("ошибка расшифровки TOTP-секрета: %w", err)
+	}
+	var step int64
+	err = s.limitAttempts(ctx, state.Login, func() error {
+		var ok bool
+		if step, ok = totp.Validate(secret, code, s.now(), totpSkew); !ok {
+			return helper.ErrInvalidOTP
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.EnableTOTP(ctx, userID, step); err != nil {
+		return fmt.Errorf("ошибка включения 2FA: %w", err)
+	}
+
+	return nil
+}
+
+// Verify - проверяет код второго фактора при входе. Подходит код из
+// аутентификатора или неиспользованный код восстановления.
+func (s *twoFactorService) Verify(ctx context.Context, userID int, code string) error {
+	state, err := s.repo.TOTPState(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения состояния 2FA: %w", err)
+	}
+	if !state.Enabled {
+		return helper.ErrTwoFactorDisabled
+	}
+
+	return s.check(ctx, userID, state, code)
+}
+
+// Disable - выключает второй фактор. Требует действующий код, чтобы
+// украденного access-токена было недостаточно.
+func (s *twoFactorService) Disable(ctx context.Context, userID int, code string) error {
+	state, err := s.repo.TOTPState(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения состояния 2FA: %w", err)
+	}
+	if !state.Enabled {
+		return helper.ErrTwoFactorDisabled
+	}
+
+	err = s.limitAttempts(ctx, state.Login, func() error {
+		return s.check(ctx, userID, state, code)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DisableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("ошибка выключения 2FA: %w", err)
+	}
+
+	return nil
+}
+
+// limitAttempts - проверяет код функцией check, пока вход для login не
+// заблокирован. Неверный код учитывается как неудачная попытка входа, иначе
+// код можно было бы подбирать через включение и выключение 2FA.
+func (s *twoFactorService) limitAttempts(ctx context.Context, login string, check func() error) error {
+	locked, err := s.attempts.IsLocked(ctx, login)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки блокировки входа: %w", err)
+	}
+	if locked {
+		return helper.ErrAccountLocked
+	}
+
+	err = check()
+	if errors.Is(err, helper.ErrInvalidOTP) {
+		if err := s.attempts.RegisterFailure(ctx, login); err != nil {
+			return fmt.Errorf("ошибка учёта неудачной попытки: %w", err)
+		}
+	}
+
+	return err
+}
+
+// check - сверяет код с секретом или кодами восстановления. Принятый шаг
+// запоминается, поэтому один и тот же TOTP-код дважды не пройдёт.
+func (s *twoFactorService) check(ctx context.Context, userID int, state *entity.TwoFactorState, code string) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		secret, err := s.encryptionService.Decrypt(state.Secret)
+		if err != nil {
+			return fmt.Errorf("ошибка расшифровки TOTP-секрета: %w", err)
+		}
+		step, ok := totp.Validate(secret, code, s.now(), totpSkew)
+		if !ok || step <= state.LastStep {
+			return helper.ErrInvalidOTP
+		}
+		used, err := s.repo.UseTOTPStep(ctx, userID, step)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения шага TOTP: %w", err)
+		}
+		if !used {
+			return helper.ErrInvalidOTP
+		}
+		return nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return fmt.Errorf("ошибка проверки кода восстановления: %w", err)
+	}
+	if !used {
+		return helper.ErrInvalidOTP
+	}
+
+	return nil
+}
+
+// newRecoveryCode - случайный код вида xxxx-xxxx.
+func newRecoveryCode() (string, error) {
+	raw := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("ошибка генерации кода восстановления: %w", err)
+	}
+	code := strings.ToLower(recoveryEncoding.EncodeToString(raw))
+
+	return code[:4] + "-" + code[4:], nil
+}
+
+// hashRecoveryCode - хеш кода без учёта регистра, пробелов и дефисов.
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/NikolosHGW/goph-keeper/internal/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type TwoFactorRepoMock struct {
+	mock.Mock
+}
+
+func (m *TwoFactorRepoMock) TOTPState(ctx context.Context, userID int) (*entity.TwoFactorState, error) {
+	args := m.Called(ctx, userID)
+	state, _ := args.Get(0).(*entity.TwoFactorState)
+	return state, args.Error(1)
+}
+
+func (m *TwoFactorRepoMock) SaveTOTPSecret(ctx context.Context, userID int, secret string, recoveryHashes [][]byte) error {
+	args := m.Called(ctx, userID, secret, recoveryHashes)
+	return args.Error(0)
+}
+
+func (m *TwoFactorRepoMock) EnableTOTP(ctx context.Context, userID int, step int64) error {
+	args := m.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+func (m *TwoFactorRepoMock) DisableTOTP(ctx context.Context, userID int) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *TwoFactorRepoMock) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	args := m.Called(ctx, userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *TwoFactorRepoMock) UseRecoveryCode(ctx context.Context, userID int, hash []byte) (bool, error) {
+	args := m.Called(ctx, userID, hash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *TwoFactorRepoMock) ListTOTPSecretsForRotation(
+	ctx context.Context, activePrefix string, afterID, limit int,
+) ([]*entity.TOTPSecret, error) {
+	args := m.Called(ctx, activePrefix, afterID, limit)
+	secrets, _ := args.Get(0).([]*entity.TOTPSecret)
+	return secrets, args.Error(1)
+}
+
+func (m *TwoFactorRepoMock) ReplaceTOTPSecret(ctx context.Context, old, updated *entity.TOTPSecret) (bool, error) {
+	args := m.Called(ctx, old, updated)
+	return args.Bool(0), args.Error(1)
+}
+
+// fakeLoginAttempts - неудачные попытки входа по логинам в памяти.
+type fakeLoginAttempts struct {
+	failures map[string]int
+	locked   bool
+}
+
+func (f *fakeLoginAttempts) IsLocked(_ context.Context, _ string) (bool, error) {
+	return f.locked, nil
+}
+
+func (f *fakeLoginAttempts) RegisterFailure(_ context.Context, login string) error {
+	if f.failures == nil {
+		f.failures = make(map[string]int)
+	}
+	f.failures[login]++
+	return nil
+}
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTestTwoFactorService(t *testing.T, repo *TwoFactorRepoMock, now time.Time) *twoFactorService {
+	t.Helper()
+
+	svc := NewTwoFactorService(repo, &fakeLoginAttempts{}, newTestEncryptionService(t))
+	svc.now = func() time.Time { return now }
+
+	return svc
+}
+
+func TestTwoFactorService_Enroll(t *testing.T) {
+	ctx := context.Background()
+	repo := new(TwoFactorRepoMock)
+	svc := newTestTwoFactorService(t, repo, time.Now())
+
+	repo.On("TOTPState", ctx, 1).Return(&entity.TwoFactorState{Login: "user"}, nil).Once()
+
+	var stored string
+	var hashes [][]byte
+	repo.On("SaveTOTPSecret", ctx, 1, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.String(2)
+		hashes = args.Get(3).([][]byte)
+	}).Return(nil).Once()
+
+	enrollment, err := svc.Enroll(ctx, 1)
+	require.NoError(t, err)
+
+	decrypted, err := svc.encryptionService.Decrypt(stored)
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, decrypted)
+	assert.NotEqual(t, enrollment.Secret, stored)
+
+	uri, err := url.Parse(enrollment.URI)
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, uri.Query().Get("secret"))
+	assert.Equal(t, "/GophKeeper:user", uri.Path)
+
+	require.Len(t, enrollment.RecoveryCodes, recoveryCodeCount)
+	require.Len(t, hashes, recoveryCodeCount)
+	for i, code := range enrollment.RecoveryCodes {
+		assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+		assert.Equal(t, hashRecoveryCode(code), hashes[i])
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestTwoFactorService_EnrollAlreadyEnabled(t *testing.T) {
+	ctx := context.Background()
+	repo := new(TwoFactorRepoMock)
+	svc := newTestTwoFactorService(t, repo, time.Now())
+
+	repo.On("TOTPState", ctx, 1).Return(&entity.TwoFactorState{Enabled: true}, nil).Once()
+
+	_, err := svc.Enroll(ctx, 1)
+
+	assert.ErrorIs(t, err, helper.ErrTwoFactorEnabled)
+	repo.AssertNotCalled(t, "SaveTOTPSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	step := totp.Step(now)
+	code, err := totp.Code(testTOTPSecret, step)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		state            func(svc *twoFactorService) *entity.TwoFactorState
+		code             string
+		locked           bool
+		setupMock        func(repo *TwoFactorRepoMock)
+		expectedErr      error
+		expectedFailures int
+	}{
+		{
+			name: "код подходит",
+			state: func(svc *twoFactorService) *entity.TwoFactorState {
+				encrypted, _ := svc.encryptionService.Encrypt(testTOTPSecret)
+				return &entity.TwoFactorState{Secret: encrypted}
+			},
+			code: code,
+			setupMock: func(repo *TwoFactorRepoMock) {
+				repo.On("EnableTOTP", ctx, 1, step).Return(nil).Once()
+			},
+		},
+		{
+			name: "неверный код",
+			state: func(svc *twoFactorService) *entity.TwoFactorState {
+				encrypted, _ := svc.encryptionService.Encrypt(testTOTPSecret)
+				return &entity.TwoFactorState{Secret: encrypted}
+			},
+			code:             "000000",
+			setupMock:        func(repo *TwoFactorRepoMock) {},
+			expectedErr:      helper.ErrInvalidOTP,
+			expectedFailures: 1,
+		},
+		{
+			name: "вход заблокирован",
+			state: func(svc *twoFactorService) *entity.TwoFactorState {
+				encrypted, _ := svc.encryptionService.Encrypt(testTOTPSecret)
+				return &entity.TwoFactorState{Secret: encrypted}
+			},
+			code:        code,
+			locked:      true,
+			setupMock:   func(repo *TwoFactorRepoMock) {},
+			expectedErr: helper.ErrAccountLocked,
+		},
+		{
+			name: "подключение не начато",
+			state: func(svc *twoFactorService) *entity.TwoFactorState {
+				return &entity.TwoFactorState{}
+			},
+			code:        code,
+			setupMock:   func(repo *TwoFactorRepoMock) {},
+			expectedErr: helper.ErrTOTPNotEnrolled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(TwoFactorRepoMock)
+			svc := newTestTwoFactorService(t, repo, now)
+			attempts := &fakeLoginAttempts{locked: tt.locked}
+			svc.attempts = attempts
+			state := tt.state(svc)
+			state.Login = "user"
+			repo.On("TOTPState", ctx, 1).Return(state, nil).Once()
+			tt.setupMock(repo)
+
+			err := svc.Confirm(ctx, 1, tt.code)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedFailures, attempts.failures["user"])
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTwoFactorService_Verify(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	step := totp.Step(now)
+	code, err := totp.Code(testTOTPSecret, step)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		lastStep    int64
+		enabled     bool
+		code        string
+		setupMock   func(repo *TwoFactorRepoMock)
+		expectedErr error
+	}{
+		{
+			name:    "код из аутентификатора",
+			enabled: true,
+			code:    code,
+			setupMock: func(repo *TwoFactorRepoMock) {
+				repo.On("UseTOTPStep", ctx, 1, step).Return(true, nil).Once()
+			},
+		},
+		{
+			name:        "повтор уже принятого кода",
+			enabled:     true,
+			lastStep:    step,
+			code:        code,
+			setupMock:   func(repo *TwoFactorRepoMock) {},
+			expectedErr: helper.ErrInvalidOTP,
+		},
+		{
+			name:    "код принят параллельным запросом",
+			enabled: true,
+			code:    code,
+			setupMock: func(repo *TwoFactorRepoMock) {
+				repo.On("UseTOTPStep", ctx, 1, step).Return(false, nil).Once()
+			},
+			expectedErr: helper.ErrInvalidOTP,
+		},
+		{
+			name:    "код восстановления",
+			enabled: true,
+			code:    "ABCD-EFGH",
+			setupMock: func(repo *TwoFactorRepoMock) {
+				repo.On("UseRecoveryCode", ctx, 1, hashRecoveryCode("abcdefgh")).Return(true, nil).Once()
+			},
+		},
+		{
+			name:    "использованный код восстановления",
+			enabled: true,
+			code:    "abcd-efgh",
+			setupMock: func(repo *TwoFactorRepoMock) {
+				repo.On("UseRecoveryCode", ctx, 1, hashRecoveryCode("abcd-efgh")).Return(false, nil).Once()
+			},
+			expectedErr: helper.ErrInvalidOTP,
+		},
+		{
+			name:        "2FA не включена",
+			code:        code,
+			setupMock:   func(repo *TwoFactorRepoMock) {},
+			expectedErr: helper.ErrTwoFactorDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(TwoFactorRepoMock)
+			svc := newTestTwoFactorService(t, repo, now)
+			encrypted, err := svc.encryptionService.Encrypt(testTOTPSecret)
+			require.NoError(t, err)
+
+			repo.On("TOTPState", ctx, 1).Return(&entity.TwoFactorState{
+				Secret: encrypted, Enabled: tt.enabled, LastStep: tt.lastStep,
+			}, nil).Once()
+			tt.setupMock(repo)
+
+			err = svc.Verify(ctx, 1, tt.code)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	step := totp.Step(now)
+	code, err := totp.Code(testTOTPSecret, step)
+	require.NoError(t, err)
+
+	repo := new(TwoFactorRepoMock)
+	svc := newTestTwoFactorService(t, repo, now)
+	encrypted, err := svc.encryptionService.Encrypt(testTOTPSecret)
+	require.NoError(t, err)
+
+	attempts := &fakeLoginAttempts{}
+	svc.attempts = attempts
+
+	repo.On("TOTPState", ctx, 1).
+		Return(&entity.TwoFactorState{Login: "user", Secret: encrypted, Enabled: true}, nil).Times(3)
+	repo.On("UseRecoveryCode", ctx, 1, mock.Anything).Return(false, nil).Once()
+	repo.On("UseTOTPStep", ctx, 1, step).Return(true, nil).Once()
+	repo.On("DisableTOTP", ctx, 1).Return(nil).Once()
+
+	assert.ErrorIs(t, svc.Disable(ctx, 1, "wrong-code"), helper.ErrInvalidOTP)
+	assert.Equal(t, 1, attempts.failures["user"])
+
+	attempts.locked = true
+	assert.ErrorIs(t, svc.Disable(ctx, 1, code), helper.ErrAccountLocked)
+
+	attempts.locked = false
+	assert.NoError(t, svc.Disable(ctx, 1, code))
+	assert.Equal(t, 1, attempts.failures["user"])
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+)
+
+type vaultRepo interface {
+	SetKeyPair(ctx context.Context, userID int, pair *entity.KeyPair) error
+	KeyPair(ctx context.Context, userID int) (*entity.KeyPair, error)
+	PublicKey(ctx context.Context, login string) (int, string, error)
+	CreateVault(ctx context.Context, vault *entity.Vault) (int, error)
+	ListVaults(ctx context.Context, userID int) ([]*entity.Vault, error)
+	VaultRole(ctx context.Context, userID, vaultID int) (string, error)
+	AddMember(ctx context.Context, vaultID int, member *entity.VaultMember, wrappedKey string) error
+	Member(ctx context.Context, vaultID int, login string) (*entity.VaultMember, error)
+	RemoveMember(ctx context.Context, vaultID, userID int) error
+	ListMembers(ctx context.Context, vaultID int) ([]*entity.VaultMember, error)
+}
+
+type vaultService struct {
+	repo vaultRepo
+}
+
+// NewVaultService - конструктор сервиса общих хранилищ. Ключи хранилищ
+// шифруются и расшифровываются только на клиентах, сервер хранит их
+// обёрнутыми открытым ключом каждого участника.
+func NewVaultService(repo vaultRepo) *vaultService {
+	return &vaultService{repo: repo}
+}
+
+// SetKeyPair - сохраняет ключевую пару пользователя, если её ещё нет.
+func (s *vaultService) SetKeyPair(ctx context.Context, userID int, pair *entity.KeyPair) error {
+	return s.repo.SetKeyPair(ctx, userID, pair)
+}
+
+// KeyPair - возвращает ключевую пару пользователя.
+func (s *vaultService) KeyPair(ctx context.Context, userID int) (*entity.KeyPair, error) {
+	return s.repo.KeyPair(ctx, userID)
+}
+
+// PublicKey - возвращает открытый ключ пользователя, которого хотят пригласить.
+func (s *vaultService) PublicKey(ctx context.Context, login string) (string, error) {
+	_, publicKey, err := s.repo.PublicKey(ctx, login)
+	return publicKey, err
+}
+
+// CreateVault - создаёт хранилище, владельцем которого становится userID.
+func (s *vaultService) CreateVault(ctx context.Context, userID int, name, wrappedKey string) (int, error) {
+	id, err := s.repo.CreateVault(ctx, &entity.Vault{Name: name, OwnerID: userID, WrappedKey: wrappedKey})
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания хранилища: %w", err)
+	}
+
+	return id, nil
+}
+
+// ListVaults - возвращает хранилища пользователя.
+func (s *vaultService) ListVaults(ctx context.Context, userID int) ([]*entity.Vault, error) {
+	return s.repo.ListVaults(ctx, userID)
+}
+
+// AddMember - приглашает пользователя login с ролью role или меняет его роль.
+// Приглашать может только владелец; wrappedKey - ключ хранилища, зашифрованный
+// открытым ключом приглашённого.
+func (s *vaultService) AddMember(ctx context.Context, userID, vaultID int, login, role, wrappedKey string) error {
+	if role != entity.RoleRead && role != entity.RoleWrite {
+		return helper.ErrInvalidRole
+	}
+	if err := s.requireOwner(ctx, userID, vaultID); err != nil {
+		return err
+	}
+
+	memberID, _, err := s.repo.PublicKey(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.AddMember(ctx, vaultID, &entity.VaultMember{UserID: memberID, Login: login, Role: role}, wrappedKey)
+}
+
+// RemoveMember - исключает участника. Исключать может владелец, а любой
+// участник может выйти из хранилища сам. Владелец выйти не может.
+//
+// Исключённый участник мог сохранить ключ хранилища, поэтому записи, которые
+// он видел, стоит считать раскрытыми.
+func (s *vaultService) RemoveMember(ctx context.Context, userID, vaultID int, login string) error {
+	role, err := s.repo.VaultRole(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.repo.Member(ctx, vaultID, login)
+	if err != nil {
+		return err
+	}
+	if member.Role == entity.RoleOwner || (role != entity.RoleOwner && member.UserID != userID) {
+		return helper.ErrAccessDenied
+	}
+
+	return s.repo.RemoveMember(ctx, vaultID, member.UserID)
+}
+
+// ListMembers - возвращает участников хранилища; доступно любому участнику.
+func (s *vaultService) ListMembers(ctx context.Context, userID, vaultID int) ([]*entity.VaultMember, error) {
+	if _, err := s.repo.VaultRole(ctx, userID, vaultID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListMembers(ctx, vaultID)
+}
+
+func (s *vaultService) requireOwner(ctx context.Context, userID, vaultID int) error {
+	role, err := s.repo.VaultRole(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+	if role != entity.RoleOwner {
+		return helper.ErrAccessDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NikolosHGW/goph-keeper/internal/server/entity"
+	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type VaultRepoMock struct {
+	mock.Mock
+}
+
+func (m *VaultRepoMock) SetKeyPair(ctx context.Context, userID int, pair *entity.KeyPair) error {
+	return m.Called(ctx, userID, pair).Error(0)
+}
+
+func (m *VaultRepoMock) KeyPair(ctx context.Context, userID int) (*entity.KeyPair, error) {
+	args := m.Called(ctx, userID)
+	pair, _ := args.Get(0).(*entity.KeyPair)
+	return pair, args.Error(1)
+}
+
+func (m *VaultRepoMock) PublicKey(ctx context.Context, login string) (int, string, error) {
+	args := m.Called(ctx, login)
+	return args.Int(0), args.String(1), args.Error(2)
+}
+
+func (m *VaultRepoMock) CreateVault(ctx context.Context, vault *entity.Vault) (int, error) {
+	args := m.Called(ctx, vault)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *VaultRepoMock) ListVaults(ctx context.Context, userID int) ([]*entity.Vault, error) {
+	args := m.Called(ctx, userID)
+	vaults, _ := args.Get(0).([]*entity.Vault)
+	return vaults, args.Error(1)
+}
+
+func (m *VaultRepoMock) VaultRole(ctx context.Context, userID, vaultID int) (string, error) {
+	args := m.Called(ctx, userID, vaultID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *VaultRepoMock) AddMember(
+	ctx context.Context, vaultID int, member *entity.VaultMember, wrappedKey string,
+) error {
+	return m.Called(ctx, vaultID, member, wrappedKey).Error(0)
+}
+
+func (m *VaultRepoMock) Member(ctx context.Context, vaultID int, login string) (*entity.VaultMember, error) {
+	args := m.Called(ctx, vaultID, login)
+	member, _ := args.Get(0).(*entity.VaultMember)
+	return member, args.Error(1)
+}
+
+func (m *VaultRepoMock) RemoveMember(ctx context.Context, vaultID, userID int) error {
+	return m.Called(ctx, vaultID, userID).Error(0)
+}
+
+func (m *VaultRepoMock) ListMembers(ctx context.Context, vaultID int) ([]*entity.VaultMember, error) {
+	args := m.Called(ctx, vaultID)
+	members, _ := args.Get(0).([]*entity.VaultMember)
+	return members, args.Error(1)
+}
+
+func TestVaultService_AddMember(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		role        string
+		setup       func(m *VaultRepoMock)
+		expectedErr error
+	}{
+		{
+			name: "владелец приглашает участника",
+			role: entity.RoleWrite,
+			setup: func(m *VaultRepoMock) {
+				m.On("VaultRole", ctx, 1, 7).Return(entity.RoleOwner, nil)
+				m.On("PublicKey", ctx, "bob").Return(2, "pub", nil)
+				m.On("AddMember", ctx, 7, &entity.VaultMember{UserID: 2, Login: "bob", Role: entity.RoleWrite}, "wrapped").
+					Return(nil)
+			},
+		},
+		{
+			name: "приглашает не владелец",
+			role: entity.RoleRead,
+			setup: func(m *VaultRepoMock) {
+				m.On("VaultRole", ctx, 1, 7).Return(entity.RoleWrite, nil)
+			},
+			expectedErr: helper.ErrAccessDenied,
+		},
+		{
+			name:        "роль владельца не выдаётся",
+			role:        entity.RoleOwner,
+			setup:       func(m *VaultRepoMock) {},
+			expectedErr: helper.ErrInvalidRole,
+		},
+		{
+			name: "у приглашённого нет ключей",
+			role: entity.RoleRead,
+			setup: func(m *VaultRepoMock) {
+				m.On("VaultRole", ctx, 1, 7).Return(entity.RoleOwner, nil)
+				m.On("PublicKey", ctx, "bob").Return(0, "", helper.ErrNoPublicKey)
+			},
+			expectedErr: helper.ErrNoPublicKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(VaultRepoMock)
+			tt.setup(repo)
+
+			err := NewVaultService(repo).AddMember(ctx, 1, 7, "bob", tt.role, "wrapped")
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVaultService_RemoveMember(t *testing.T) {
+	ctx := context.Background()
+	bob := &entity.VaultMember{UserID: 2, Login: "bob", Role: entity.RoleRead}
+
+	tests := []struct {
+		name        string
+		userID      int
+		callerRole  string
+		member      *entity.VaultMember
+		expectedErr error
+	}{
+		{name: "владелец исключает участника", userID: 1, callerRole: entity.RoleOwner, member: bob},
+		{name: "участник выходит сам", userID: 2, callerRole: entity.RoleRead, member: bob},
+		{
+			name:        "участник исключает другого",
+			userID:      3,
+			callerRole:  entity.RoleWrite,
+			member:      bob,
+			expectedErr: helper.ErrAccessDenied,
+		},
+		{
+			name:        "владелец не выходит",
+			userID:      1,
+			callerRole:  entity.RoleOwner,
+			member:      &entity.VaultMember{UserID: 1, Login: "alice", Role: entity.RoleOwner},
+			expectedErr: helper.ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(VaultRepoMock)
+			repo.On("VaultRole", ctx, tt.userID, 7).Return(tt.callerRole, nil)
+			repo.On("Member", ctx, 7, tt.member.Login).Return(tt.member, nil)
+			if tt.expectedErr == nil {
+				repo.On("RemoveMember", ctx, 7, tt.member.UserID).Return(nil)
+			}
+
+			err := NewVaultService(repo).RemoveMember(ctx, tt.userID, 7, tt.member.Login)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVaultService_ListMembers_NotMember(t *testing.T) {
+	ctx := context.Background()
+	repo := new(VaultRepoMock)
+	repo.On("VaultRole", ctx, 1, 7).Return("", helper.ErrVaultNotFound)
+
+	_, err := NewVaultService(repo).ListMembers(ctx, 1, 7)
+
+	assert.ErrorIs(t, err, helper.ErrVaultNotFound)
+	repo.AssertNotCalled(t, "ListMembers", mock.Anything, mock.Anything)
+}
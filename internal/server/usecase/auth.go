@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	pb "github.com/NikolosHGW/goph-keeper/api/authpb"
@@ -9,35 +10,182 @@ import (
 	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
 )
 
+// dummyPasswordHash - bcrypt-хеш с той же стоимостью, что у паролей
+// пользователей. С ним сверяется пароль для неизвестного логина, чтобы по
+// времени ответа нельзя было понять, есть ли такой пользователь.
+const dummyPasswordHash = "$2a$10$2lv9BFpv6ofCmWC0abQFzedepsxR4BoF8Cfg1rNCJiFI4WhRzvu6K"
+
 type authRepo interface {
 	User(context.Context, string) (*entity.User, error)
+	SetKeyCheck(ctx context.Context, userID int, keyCheck []byte) error
 	userRepo
 }
 
+type passwordComparer interface {
+	Compare(hash, plain string) error
+}
+
+type loginAttemptRepo interface {
+	IsLocked(ctx context.Context, login string) (bool, error)
+	RegisterFailure(ctx context.Context, login string) error
+	Reset(ctx context.Context, login string) error
+}
+
+type challengeIssuer interface {
+	GenerateChallenge(userID int, login, deviceName string) (string, error)
+	ParseChallenge(challenge string) (*entity.ChallengeClaims, error)
+}
+
+type otpVerifier interface {
+	Verify(ctx context.Context, userID int, code string) error
+}
+
 type auth struct {
-	tokenService tokenServicer
-	authRepo     authRepo
+	sessionService   sessionOpener
+	authRepo         authRepo
+	passwordService  passwordComparer
+	loginAttemptRepo loginAttemptRepo
+	challenges       challengeIssuer
+	twoFactorService otpVerifier
 }
 
-// NewAuth - конструктор юзкейса регистрации пользователя.
-func NewAuth(tokenService tokenServicer, authRepo authRepo) *auth {
+// NewAuth - конструктор юзкейса авторизации пользователя.
+func NewAuth(
+	sessionService sessionOpener,
+	authRepo authRepo,
+	passwordService passwordComparer,
+	loginAttemptRepo loginAttemptRepo,
+	challenges challengeIssuer,
+	twoFactorService otpVerifier,
+) *auth {
 	return &auth{
-		authRepo:     authRepo,
-		tokenService: tokenService,
+		authRepo:         authRepo,
+		sessionService:   sessionService,
+		passwordService:  passwordService,
+		loginAttemptRepo: loginAttemptRepo,
+		challenges:       challenges,
+		twoFactorService: twoFactorService,
 	}
 }
 
 // Handle - авторизация пользователя.
-func (r *auth) Handle(ctx context.Context, req *pb.LoginUserRequest) (string, error) {
+func (r *auth) Handle(ctx context.Context, req *pb.LoginUserRequest) (*entity.AuthResult, error) {
+	locked, err := r.loginAttemptRepo.IsLocked(ctx, req.Login)
+	if err != nil {
+		return nil, helper.ErrInternalServer
+	}
+	if locked {
+		return nil, helper.ErrAccountLocked
+	}
+
 	user, err := r.authRepo.User(ctx, req.Login)
 	if err != nil {
-		return "", helper.ErrInternalServer
+		if errors.Is(err, helper.ErrInvalidCredentials) {
+			_ = r.passwordService.Compare(dummyPasswordHash, req.Password)
+			return nil, r.registerFailure(ctx, req.Login)
+		}
+		return nil, helper.ErrInternalServer
+	}
+
+	err = r.passwordService.Compare(user.Password, req.Password)
+	if err != nil {
+		if errors.Is(err, helper.ErrInvalidCredentials) {
+			return nil, r.registerFailure(ctx, req.Login)
+		}
+		return nil, helper.ErrInternalServer
+	}
+
+	// Счётчик неудач не сбрасывается, пока не введён второй фактор:
+	// иначе подбор кода не упирался бы в блокировку.
+	if user.TOTPEnabled {
+		challenge, err := r.challenges.GenerateChallenge(user.ID, user.Login, req.DeviceName)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при создании challenge: %w", err)
+		}
+		return &entity.AuthResult{Challenge: challenge}, nil
+	}
+
+	return r.openSession(ctx, user, req.DeviceName)
+}
+
+// Complete - второй шаг входа с 2FA: проверяет код и открывает сессию.
+// Неверный код учитывается как неудачная попытка входа.
+func (r *auth) Complete(ctx context.Context, challenge, code string) (*entity.AuthResult, error) {
+	claims, err := r.challenges.ParseChallenge(challenge)
+	if err != nil {
+		return nil, helper.ErrInvalidChallenge
 	}
 
-	token, err := r.tokenService.GenerateJWT(user)
+	locked, err := r.loginAttemptRepo.IsLocked(ctx, claims.Login)
 	if err != nil {
-		return "", fmt.Errorf("ошибка при генерации токена: %w", err)
+		return nil, helper.ErrInternalServer
+	}
+	if locked {
+		return nil, helper.ErrAccountLocked
+	}
+
+	err = r.twoFactorService.Verify(ctx, claims.UserID, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, helper.ErrInvalidOTP):
+			if err := r.loginAttemptRepo.RegisterFailure(ctx, claims.Login); err != nil {
+				return nil, helper.ErrInternalServer
+			}
+			return nil, helper.ErrInvalidOTP
+		case errors.Is(err, helper.ErrTwoFactorDisabled):
+			return nil, helper.ErrInvalidChallenge
+		default:
+			return nil, helper.ErrInternalServer
+		}
+	}
+
+	user, err := r.authRepo.User(ctx, claims.Login)
+	if err != nil {
+		if errors.Is(err, helper.ErrInvalidCredentials) {
+			return nil, helper.ErrInvalidChallenge
+		}
+		return nil, helper.ErrInternalServer
+	}
+	if user.ID != claims.UserID {
+		return nil, helper.ErrInvalidChallenge
+	}
+
+	return r.openSession(ctx, user, claims.DeviceName)
+}
+
+// SetKeyCheck - сохраняет проверку мастер-пароля, если она ещё не задана.
+func (r *auth) SetKeyCheck(ctx context.Context, userID int, keyCheck []byte) error {
+	err := r.authRepo.SetKeyCheck(ctx, userID, keyCheck)
+	if err != nil {
+		if errors.Is(err, helper.ErrKeyCheckExists) {
+			return err
+		}
+		return helper.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (r *auth) openSession(ctx context.Context, user *entity.User, deviceName string) (*entity.AuthResult, error) {
+	if err := r.loginAttemptRepo.Reset(ctx, user.Login); err != nil {
+		return nil, helper.ErrInternalServer
+	}
+
+	result, err := r.sessionService.Open(ctx, user, deviceName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при открытии сессии: %w", err)
+	}
+
+	return result, nil
+}
+
+// registerFailure - учитывает неудачную попытку и возвращает ошибку для клиента.
+// Неизвестный логин учитывается так же, как неверный пароль, чтобы по ответу
+// нельзя было понять, существует ли пользователь.
+func (r *auth) registerFailure(ctx context.Context, login string) error {
+	if err := r.loginAttemptRepo.RegisterFailure(ctx, login); err != nil {
+		return helper.ErrInternalServer
 	}
 
-	return token, nil
+	return helper.ErrInvalidCredentials
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/NikolosHGW/goph-keeper/internal/server/helper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func (m *UserRepoMock) User(ctx context.Context, login string) (*entity.User, error) {
@@ -18,72 +19,233 @@ func (m *UserRepoMock) User(ctx context.Context, login string) (*entity.User, er
 	return user, args.Error(1)
 }
 
+func (m *UserRepoMock) SetKeyCheck(ctx context.Context, userID int, keyCheck []byte) error {
+	args := m.Called(ctx, userID, keyCheck)
+	return args.Error(0)
+}
+
+type PasswordComparerMock struct {
+	mock.Mock
+}
+
+func (m *PasswordComparerMock) Compare(hash, plain string) error {
+	args := m.Called(hash, plain)
+	return args.Error(0)
+}
+
+type LoginAttemptRepoMock struct {
+	mock.Mock
+}
+
+func (m *LoginAttemptRepoMock) IsLocked(ctx context.Context, login string) (bool, error) {
+	args := m.Called(ctx, login)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *LoginAttemptRepoMock) RegisterFailure(ctx context.Context, login string) error {
+	args := m.Called(ctx, login)
+	return args.Error(0)
+}
+
+func (m *LoginAttemptRepoMock) Reset(ctx context.Context, login string) error {
+	args := m.Called(ctx, login)
+	return args.Error(0)
+}
+
+type ChallengeIssuerMock struct {
+	mock.Mock
+}
+
+func (m *ChallengeIssuerMock) GenerateChallenge(userID int, login, deviceName string) (string, error) {
+	args := m.Called(userID, login, deviceName)
+	return args.String(0), args.Error(1)
+}
+
+func (m *ChallengeIssuerMock) ParseChallenge(challenge string) (*entity.ChallengeClaims, error) {
+	args := m.Called(challenge)
+	claims, _ := args.Get(0).(*entity.ChallengeClaims)
+	return claims, args.Error(1)
+}
+
+type OTPVerifierMock struct {
+	mock.Mock
+}
+
+func (m *OTPVerifierMock) Verify(ctx context.Context, userID int, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func TestDummyPasswordHash(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+
+	assert.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, cost)
+}
+
 func TestAuth_Handle(t *testing.T) {
 	mockRepo := new(UserRepoMock)
-	mockTokenService := new(TokenServicerMock)
+	mockSessions := new(SessionOpenerMock)
+	mockPassword := new(PasswordComparerMock)
+	mockAttempts := new(LoginAttemptRepoMock)
+	mockChallenges := new(ChallengeIssuerMock)
 
-	authUseCase := NewAuth(mockTokenService, mockRepo)
+	authUseCase := NewAuth(mockSessions, mockRepo, mockPassword, mockAttempts, mockChallenges, new(OTPVerifierMock))
 
 	ctx := context.Background()
-	req := &pb.LoginUserRequest{Login: "testuser"}
+	req := &pb.LoginUserRequest{Login: "testuser", Password: "password123", DeviceName: "laptop"}
+	user := &entity.User{
+		ID:        123,
+		Login:     "testuser",
+		Password:  "hashedpassword",
+		KDFParams: entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
+	}
+
+	userWith2FA := *user
+	userWith2FA.TOTPEnabled = true
 
 	type testCase struct {
-		name             string
-		setupMocks       func()
-		expectedToken    string
-		expectedError    error
-		assertAdditional func()
+		name              string
+		setupMocks        func()
+		expectedToken     string
+		expectedChallenge string
+		expectedError     error
+		assertAdditional  func()
 	}
 
 	tests := []testCase{
 		{
 			name: "успешная авторизация",
 			setupMocks: func() {
-				user := &entity.User{
-					ID:       123,
-					Login:    "testuser",
-					Password: "hashedpassword",
-				}
-				token := "jwt.token.string"
-
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(false, nil)
 				mockRepo.On("User", ctx, req.Login).Return(user, nil)
-				mockTokenService.On("GenerateJWT", user).Return(token, nil)
+				mockPassword.On("Compare", user.Password, req.Password).Return(nil)
+				mockAttempts.On("Reset", ctx, req.Login).Return(nil)
+				mockSessions.On("Open", ctx, user, "laptop").Return(&entity.AuthResult{
+					Token:        "jwt.token.string",
+					RefreshToken: "refresh",
+					KDF:          user.KDFParams,
+				}, nil)
 			},
 			expectedToken: "jwt.token.string",
 			expectedError: nil,
 			assertAdditional: func() {
 				mockRepo.AssertExpectations(t)
-				mockTokenService.AssertExpectations(t)
+				mockSessions.AssertExpectations(t)
+				mockAttempts.AssertExpectations(t)
+				mockAttempts.AssertNotCalled(t, "RegisterFailure", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "включена 2FA",
+			setupMocks: func() {
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(false, nil)
+				mockRepo.On("User", ctx, req.Login).Return(&userWith2FA, nil)
+				mockPassword.On("Compare", user.Password, req.Password).Return(nil)
+				mockChallenges.On("GenerateChallenge", 123, "testuser", "laptop").Return("challenge", nil)
+			},
+			expectedChallenge: "challenge",
+			assertAdditional: func() {
+				mockChallenges.AssertExpectations(t)
+				mockAttempts.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+				mockSessions.AssertNotCalled(t, "Open", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "неверный пароль",
+			setupMocks: func() {
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(false, nil)
+				mockRepo.On("User", ctx, req.Login).Return(user, nil)
+				mockPassword.On("Compare", user.Password, req.Password).Return(helper.ErrInvalidCredentials)
+				mockAttempts.On("RegisterFailure", ctx, req.Login).Return(nil)
+			},
+			expectedToken: "",
+			expectedError: helper.ErrInvalidCredentials,
+			assertAdditional: func() {
+				mockAttempts.AssertExpectations(t)
+				mockAttempts.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+				mockSessions.AssertNotCalled(t, "Open", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "неизвестный логин",
+			setupMocks: func() {
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(false, nil)
+				mockRepo.On("User", ctx, req.Login).Return(nil, helper.ErrInvalidCredentials)
+				mockPassword.On("Compare", dummyPasswordHash, req.Password).Return(helper.ErrInvalidCredentials)
+				mockAttempts.On("RegisterFailure", ctx, req.Login).Return(nil)
+			},
+			expectedToken: "",
+			expectedError: helper.ErrInvalidCredentials,
+			assertAdditional: func() {
+				mockAttempts.AssertExpectations(t)
+				mockPassword.AssertCalled(t, "Compare", dummyPasswordHash, req.Password)
+				mockSessions.AssertNotCalled(t, "Open", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "учётная запись заблокирована",
+			setupMocks: func() {
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(true, nil)
+			},
+			expectedToken: "",
+			expectedError: helper.ErrAccountLocked,
+			assertAdditional: func() {
+				mockRepo.AssertNotCalled(t, "User", mock.Anything, mock.Anything)
+				mockPassword.AssertNotCalled(t, "Compare", mock.Anything, mock.Anything)
+				mockSessions.AssertNotCalled(t, "Open", mock.Anything, mock.Anything, mock.Anything)
 			},
 		},
 		{
-			name: "пользователь не найден",
+			name: "ошибка при проверке блокировки",
 			setupMocks: func() {
-				mockRepo.On("User", ctx, req.Login).Return(nil, helper.ErrInternalServer)
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(false, helper.ErrInternalServer)
+			},
+			expectedToken: "",
+			expectedError: helper.ErrInternalServer,
+			assertAdditional: func() {
+				mockRepo.AssertNotCalled(t, "User", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "ошибка при сохранении неудачной попытки",
+			setupMocks: func() {
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(false, nil)
+				mockRepo.On("User", ctx, req.Login).Return(user, nil)
+				mockPassword.On("Compare", user.Password, req.Password).Return(helper.ErrInvalidCredentials)
+				mockAttempts.On("RegisterFailure", ctx, req.Login).Return(helper.ErrInternalServer)
+			},
+			expectedToken: "",
+			expectedError: helper.ErrInternalServer,
+		},
+		{
+			name: "ошибка базы при поиске пользователя",
+			setupMocks: func() {
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(false, nil)
+				mockRepo.On("User", ctx, req.Login).Return(nil, errors.New("ошибка при поиске пользователя"))
 			},
 			expectedToken: "",
 			expectedError: helper.ErrInternalServer,
 			assertAdditional: func() {
 				mockRepo.AssertExpectations(t)
-				mockTokenService.AssertNotCalled(t, "GenerateJWT", mock.Anything)
+				mockAttempts.AssertNotCalled(t, "RegisterFailure", mock.Anything, mock.Anything)
+				mockSessions.AssertNotCalled(t, "Open", mock.Anything, mock.Anything, mock.Anything)
 			},
 		},
 		{
-			name: "ошибка при генерации токена",
+			name: "ошибка при открытии сессии",
 			setupMocks: func() {
-				user := &entity.User{
-					ID:       123,
-					Login:    "testuser",
-					Password: "hashedpassword",
-				}
+				mockAttempts.On("IsLocked", ctx, req.Login).Return(false, nil)
 				mockRepo.On("User", ctx, req.Login).Return(user, nil)
-				mockTokenService.On("GenerateJWT", user).Return("", errors.New("генерация токена не удалась"))
+				mockPassword.On("Compare", user.Password, req.Password).Return(nil)
+				mockAttempts.On("Reset", ctx, req.Login).Return(nil)
+				mockSessions.On("Open", ctx, user, "laptop").Return(nil, errors.New("генерация токена не удалась"))
 			},
 			expectedToken: "",
-			expectedError: errors.New("ошибка при генерации токена: генерация токена не удалась"),
+			expectedError: errors.New("ошибка при открытии сессии: генерация токена не удалась"),
 			assertAdditional: func() {
 				mockRepo.AssertExpectations(t)
-				mockTokenService.AssertExpectations(t)
+				mockSessions.AssertExpectations(t)
 			},
 		},
 	}
@@ -97,20 +259,150 @@ func TestAuth_Handle(t *testing.T) {
 			if tc.expectedError != nil {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tc.expectedError.Error())
-				assert.Equal(t, tc.expectedToken, result)
+				assert.Nil(t, result)
+			} else if tc.expectedChallenge != "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedChallenge, result.Challenge)
+				assert.Empty(t, result.Token)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tc.expectedToken, result)
+				assert.Equal(t, tc.expectedToken, result.Token)
+				assert.Equal(t, user.KDFParams, result.KDF)
 			}
 
 			if tc.assertAdditional != nil {
 				tc.assertAdditional()
 			}
 
-			mockRepo.ExpectedCalls = nil
-			mockRepo.Calls = nil
-			mockTokenService.ExpectedCalls = nil
-			mockTokenService.Calls = nil
+			for _, m := range []*mock.Mock{
+				&mockRepo.Mock, &mockSessions.Mock, &mockPassword.Mock, &mockAttempts.Mock, &mockChallenges.Mock,
+			} {
+				m.ExpectedCalls = nil
+				m.Calls = nil
+			}
+		})
+	}
+}
+
+func TestAuth_Complete(t *testing.T) {
+	ctx := context.Background()
+	user := &entity.User{ID: 123, Login: "testuser", TOTPEnabled: true}
+	claims := &entity.ChallengeClaims{UserID: 123, Login: "testuser", DeviceName: "laptop"}
+	authResult := &entity.AuthResult{Token: "jwt.token.string", RefreshToken: "refresh"}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*UserRepoMock, *SessionOpenerMock, *LoginAttemptRepoMock, *ChallengeIssuerMock, *OTPVerifierMock)
+		expected      *entity.AuthResult
+		expectedError error
+	}{
+		{
+			name: "код подходит",
+			setupMocks: func(
+				repo *UserRepoMock, sessions *SessionOpenerMock, attempts *LoginAttemptRepoMock,
+				challenges *ChallengeIssuerMock, otp *OTPVerifierMock,
+			) {
+				challenges.On("ParseChallenge", "challenge").Return(claims, nil)
+				attempts.On("IsLocked", ctx, "testuser").Return(false, nil)
+				otp.On("Verify", ctx, 123, "123456").Return(nil)
+				repo.On("User", ctx, "testuser").Return(user, nil)
+				attempts.On("Reset", ctx, "testuser").Return(nil)
+				sessions.On("Open", ctx, user, "laptop").Return(authResult, nil)
+			},
+			expected: authResult,
+		},
+		{
+			name: "неверный код",
+			setupMocks: func(
+				repo *UserRepoMock, sessions *SessionOpenerMock, attempts *LoginAttemptRepoMock,
+				challenges *ChallengeIssuerMock, otp *OTPVerifierMock,
+			) {
+				challenges.On("ParseChallenge", "challenge").Return(claims, nil)
+				attempts.On("IsLocked", ctx, "testuser").Return(false, nil)
+				otp.On("Verify", ctx, 123, "123456").Return(helper.ErrInvalidOTP)
+				attempts.On("RegisterFailure", ctx, "testuser").Return(nil)
+			},
+			expectedError: helper.ErrInvalidOTP,
+		},
+		{
+			name: "challenge истёк",
+			setupMocks: func(
+				repo *UserRepoMock, sessions *SessionOpenerMock, attempts *LoginAttemptRepoMock,
+				challenges *ChallengeIssuerMock, otp *OTPVerifierMock,
+			) {
+				challenges.On("ParseChallenge", "challenge").Return(nil, helper.ErrInvalidChallenge)
+			},
+			expectedError: helper.ErrInvalidChallenge,
+		},
+		{
+			name: "учётная запись заблокирована",
+			setupMocks: func(
+				repo *UserRepoMock, sessions *SessionOpenerMock, attempts *LoginAttemptRepoMock,
+				challenges *ChallengeIssuerMock, otp *OTPVerifierMock,
+			) {
+				challenges.On("ParseChallenge", "challenge").Return(claims, nil)
+				attempts.On("IsLocked", ctx, "testuser").Return(true, nil)
+			},
+			expectedError: helper.ErrAccountLocked,
+		},
+		{
+			name: "2FA выключили после первого шага",
+			setupMocks: func(
+				repo *UserRepoMock, sessions *SessionOpenerMock, attempts *LoginAttemptRepoMock,
+				challenges *ChallengeIssuerMock, otp *OTPVerifierMock,
+			) {
+				challenges.On("ParseChallenge", "challenge").Return(claims, nil)
+				attempts.On("IsLocked", ctx, "testuser").Return(false, nil)
+				otp.On("Verify", ctx, 123, "123456").Return(helper.ErrTwoFactorDisabled)
+			},
+			expectedError: helper.ErrInvalidChallenge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(UserRepoMock)
+			sessions := new(SessionOpenerMock)
+			attempts := new(LoginAttemptRepoMock)
+			challenges := new(ChallengeIssuerMock)
+			otp := new(OTPVerifierMock)
+			tt.setupMocks(repo, sessions, attempts, challenges, otp)
+
+			authUseCase := NewAuth(sessions, repo, new(PasswordComparerMock), attempts, challenges, otp)
+			result, err := authUseCase.Complete(ctx, "challenge", "123456")
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected, result)
+			for _, m := range []*mock.Mock{&repo.Mock, &sessions.Mock, &attempts.Mock, &challenges.Mock, &otp.Mock} {
+				m.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestAuth_SetKeyCheck(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		repoErr       error
+		expectedError error
+		name          string
+	}{
+		{name: "сохранена"},
+		{name: "уже задана", repoErr: helper.ErrKeyCheckExists, expectedError: helper.ErrKeyCheckExists},
+		{name: "ошибка базы", repoErr: errors.New("db"), expectedError: helper.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(UserRepoMock)
+			repo.On("SetKeyCheck", ctx, 1, []byte("check")).Return(tt.repoErr)
+			uc := NewAuth(nil, repo, nil, nil, nil, nil)
+
+			err := uc.SetKeyCheck(ctx, 1, []byte("check"))
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			repo.AssertExpectations(t)
 		})
 	}
 }
@@ -35,13 +35,14 @@ func (m *RegisterServicerMock) CreateUser(req *pb.RegisterUserRequest) (*entity.
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
-type TokenServicerMock struct {
+type SessionOpenerMock struct {
 	mock.Mock
 }
 
-func (m *TokenServicerMock) GenerateJWT(user *entity.User) (string, error) {
-	args := m.Called(user)
-	return args.String(0), args.Error(1)
+func (m *SessionOpenerMock) Open(ctx context.Context, user *entity.User, deviceName string) (*entity.AuthResult, error) {
+	args := m.Called(ctx, user, deviceName)
+	result, _ := args.Get(0).(*entity.AuthResult)
+	return result, args.Error(1)
 }
 
 func TestRegister_Handle(t *testing.T) {
@@ -49,30 +50,41 @@ func TestRegister_Handle(t *testing.T) {
 
 	tests := []struct {
 		name          string
-		setupMocks    func(*UserRepoMock, *RegisterServicerMock, *TokenServicerMock)
+		setupMocks    func(*UserRepoMock, *RegisterServicerMock, *SessionOpenerMock)
 		req           *pb.RegisterUserRequest
 		expectedToken string
+		expectedKDF   entity.KDFParams
 		expectedError error
 	}{
 		{
 			name: "Successful registration",
-			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, tokenService *TokenServicerMock) {
+			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, sessionService *SessionOpenerMock) {
 				userRepo.On("ExistsByLogin", ctx, "newuser").Return(false, nil)
-				user := &entity.User{Login: "newuser", Password: "hashedpassword"}
+				user := &entity.User{
+					Login:     "newuser",
+					Password:  "hashedpassword",
+					KDFParams: entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
+				}
 				registerService.On("CreateUser", mock.Anything).Return(user, nil)
 				userRepo.On("Save", ctx, user).Return(nil)
-				tokenService.On("GenerateJWT", user).Return("token123", nil)
+				sessionService.On("Open", ctx, user, "laptop").Return(&entity.AuthResult{
+					Token:        "token123",
+					RefreshToken: "refresh123",
+					KDF:          user.KDFParams,
+				}, nil)
 			},
 			req: &pb.RegisterUserRequest{
-				Login:    "newuser",
-				Password: "password123",
+				Login:      "newuser",
+				Password:   "password123",
+				DeviceName: "laptop",
 			},
 			expectedToken: "token123",
+			expectedKDF:   entity.KDFParams{Salt: []byte("salt"), Time: 3, Memory: 65536, Threads: 4},
 			expectedError: nil,
 		},
 		{
 			name: "Login already exists",
-			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, tokenService *TokenServicerMock) {
+			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, sessionService *SessionOpenerMock) {
 				userRepo.On("ExistsByLogin", ctx, "existinguser").Return(true, nil)
 			},
 			req: &pb.RegisterUserRequest{
@@ -84,7 +96,7 @@ func TestRegister_Handle(t *testing.T) {
 		},
 		{
 			name: "Error checking login existence",
-			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, tokenService *TokenServicerMock) {
+			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, sessionService *SessionOpenerMock) {
 				userRepo.On("ExistsByLogin", ctx, "newuser").Return(false, errors.New("database error"))
 			},
 			req: &pb.RegisterUserRequest{
@@ -96,7 +108,7 @@ func TestRegister_Handle(t *testing.T) {
 		},
 		{
 			name: "Error creating user",
-			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, tokenService *TokenServicerMock) {
+			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, sessionService *SessionOpenerMock) {
 				userRepo.On("ExistsByLogin", ctx, "newuser").Return(false, nil)
 				registerService.On("CreateUser", mock.Anything).Return((*entity.User)(nil), errors.New("creation error"))
 			},
@@ -109,7 +121,7 @@ func TestRegister_Handle(t *testing.T) {
 		},
 		{
 			name: "Error saving user",
-			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, tokenService *TokenServicerMock) {
+			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, sessionService *SessionOpenerMock) {
 				userRepo.On("ExistsByLogin", ctx, "newuser").Return(false, nil)
 				user := &entity.User{Login: "newuser", Password: "hashedpassword"}
 				registerService.On("CreateUser", mock.Anything).Return(user, nil)
@@ -123,20 +135,20 @@ func TestRegister_Handle(t *testing.T) {
 			expectedError: errors.New("ошибка при сохранении пользователя: save error"),
 		},
 		{
-			name: "Error generating token",
-			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, tokenService *TokenServicerMock) {
+			name: "Error opening session",
+			setupMocks: func(userRepo *UserRepoMock, registerService *RegisterServicerMock, sessionService *SessionOpenerMock) {
 				userRepo.On("ExistsByLogin", ctx, "newuser").Return(false, nil)
 				user := &entity.User{Login: "newuser", Password: "hashedpassword"}
 				registerService.On("CreateUser", mock.Anything).Return(user, nil)
 				userRepo.On("Save", ctx, user).Return(nil)
-				tokenService.On("GenerateJWT", user).Return("", errors.New("token error"))
+				sessionService.On("Open", ctx, user, "").Return(nil, errors.New("token error"))
 			},
 			req: &pb.RegisterUserRequest{
 				Login:    "newuser",
 				Password: "password123",
 			},
 			expectedToken: "",
-			expectedError: errors.New("ошибка при генерации токена: token error"),
+			expectedError: errors.New("ошибка при открытии сессии: token error"),
 		},
 	}
 
@@ -144,25 +156,26 @@ func TestRegister_Handle(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepoMock := new(UserRepoMock)
 			registerServiceMock := new(RegisterServicerMock)
-			tokenServiceMock := new(TokenServicerMock)
+			sessionServiceMock := new(SessionOpenerMock)
 
-			tt.setupMocks(userRepoMock, registerServiceMock, tokenServiceMock)
+			tt.setupMocks(userRepoMock, registerServiceMock, sessionServiceMock)
 
-			reg := NewRegister(registerServiceMock, tokenServiceMock, userRepoMock)
+			reg := NewRegister(registerServiceMock, sessionServiceMock, userRepoMock)
 
-			token, err := reg.Handle(ctx, tt.req)
+			result, err := reg.Handle(ctx, tt.req)
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
-				assert.Empty(t, token)
+				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedToken, token)
+				assert.Equal(t, tt.expectedToken, result.Token)
+				assert.Equal(t, tt.expectedKDF, result.KDF)
 			}
 
 			userRepoMock.AssertExpectations(t)
 			registerServiceMock.AssertExpectations(t)
-			tokenServiceMock.AssertExpectations(t)
+			sessionServiceMock.AssertExpectations(t)
 		})
 	}
 }
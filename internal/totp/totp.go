@@ -0,0 +1,248 @@
+// Package totp реализует одноразовые коды RFC 6238 (HMAC-SHA1, 6 цифр, шаг 30 секунд),
+// совместимые с Google Authenticator и аналогами. Используется сервером для
+// второго фактора входа и клиентом для генерации кодов к сохранённым записям.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Period - длительность шага в секундах.
+	Period = 30
+	// Digits - количество цифр в коде.
+	Digits = 6
+
+	secretSize = 20
+)
+
+var (
+	// ErrInvalidSecret - секрет не является корректной строкой base32.
+	ErrInvalidSecret = errors.New("некорректный TOTP-секрет")
+	// ErrInvalidURI - ссылка otpauth:// не разбирается или описывает не TOTP.
+	ErrInvalidURI = errors.New("некорректная ссылка otpauth://")
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret - создаёт случайный секрет в base32 без выравнивания.
+func GenerateSecret() (string, error) {
+	raw := make([]byte, secretSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("ошибка генерации TOTP-секрета: %w", err)
+	}
+	return encoding.EncodeToString(raw), nil
+}
+
+// Step - номер шага для момента времени t.
+func Step(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+// Remaining - сколько секунд осталось до смены кода.
+func Remaining(t time.Time) int {
+	return Period - int(t.Unix()%Period)
+}
+
+// Code - код для шага step. Секрет принимается в любом регистре,
+// с пробелами и без выравнивания, как его показывают приложения.
+func Code(secret string, step int64) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return generate(key, step, Digits, sha1.New), nil
+}
+
+// Validate - ищет шаг, для которого код совпадает, в окне ±skew шагов
+// вокруг t. Возвращает найденный шаг, чтобы вызывающий мог запретить
+// повторное использование кода.
+func Validate(secret, code string, t time.Time, skew int) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(t)
+	for i := -skew; i <= skew; i++ {
+		expected, err := Code(secret, current+int64(i))
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return current + int64(i), true
+		}
+	}
+
+	return 0, false
+}
+
+// URI - ссылка otpauth:// для добавления секрета в приложение-аутентификатор.
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("period", fmt.Sprint(Period))
+	query.Set("digits", fmt.Sprint(Digits))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Key - параметры генерации кодов для сохранённой записи. Приложения
+// могут задавать алгоритм, длину кода и шаг, отличные от стандартных.
+type Key struct {
+	Secret    string
+	Issuer    string
+	Account   string
+	Algorithm string
+	Digits    int
+	Period    int
+}
+
+// ParseKey - разбирает значение поля записи: ссылку otpauth:// или
+// секрет в base32 со стандартными параметрами.
+func ParseKey(value string) (*Key, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(strings.ToLower(value), "otpauth://") {
+		return ParseURI(value)
+	}
+
+	if _, err := decodeSecret(value); err != nil {
+		return nil, err
+	}
+
+	return &Key{Secret: value, Algorithm: "SHA1", Digits: Digits, Period: Period}, nil
+}
+
+// ParseURI - разбирает ссылку otpauth://totp/... в формате Google Authenticator.
+func ParseURI(uri string) (*Key, error) {
+	parsed, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+	if !strings.EqualFold(parsed.Scheme, "otpauth") || !strings.EqualFold(parsed.Host, "totp") {
+		return nil, fmt.Errorf("%w: поддерживается только otpauth://totp/", ErrInvalidURI)
+	}
+
+	query := parsed.Query()
+	key := &Key{
+		Secret:    query.Get("secret"),
+		Issuer:    query.Get("issuer"),
+		Algorithm: strings.ToUpper(query.Get("algorithm")),
+		Digits:    Digits,
+		Period:    Period,
+	}
+	if _, err := decodeSecret(key.Secret); err != nil {
+		return nil, err
+	}
+
+	label := strings.TrimPrefix(parsed.Path, "/")
+	if issuer, account, found := strings.Cut(label, ":"); found {
+		if key.Issuer == "" {
+			key.Issuer = issuer
+		}
+		label = account
+	}
+	key.Account = strings.TrimSpace(label)
+
+	if key.Algorithm == "" {
+		key.Algorithm = "SHA1"
+	}
+	if hashByName(key.Algorithm) == nil {
+		return nil, fmt.Errorf("%w: неизвестный алгоритм %s", ErrInvalidURI, key.Algorithm)
+	}
+	if v := query.Get("digits"); v != "" {
+		if key.Digits, err = strconv.Atoi(v); err != nil || key.Digits < 6 || key.Digits > 8 {
+			return nil, fmt.Errorf("%w: некорректная длина кода %s", ErrInvalidURI, v)
+		}
+	}
+	if v := query.Get("period"); v != "" {
+		if key.Period, err = strconv.Atoi(v); err != nil || key.Period <= 0 {
+			return nil, fmt.Errorf("%w: некорректный шаг %s", ErrInvalidURI, v)
+		}
+	}
+
+	return key, nil
+}
+
+// Code - код для момента времени t.
+func (k *Key) Code(t time.Time) (string, error) {
+	secret, err := decodeSecret(k.Secret)
+	if err != nil {
+		return "", err
+	}
+	newHash := hashByName(k.Algorithm)
+	if newHash == nil {
+		return "", fmt.Errorf("%w: неизвестный алгоритм %s", ErrInvalidURI, k.Algorithm)
+	}
+
+	return generate(secret, t.Unix()/int64(k.Period), k.Digits, newHash), nil
+}
+
+// Remaining - сколько секунд осталось до смены кода.
+func (k *Key) Remaining(t time.Time) int {
+	return k.Period - int(t.Unix()%int64(k.Period))
+}
+
+// generate - HOTP (RFC 4226) для счётчика step.
+func generate(key []byte, step int64, digits int, newHash func() hash.Hash) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(newHash, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range digits {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
+
+func hashByName(name string) func() hash.Hash {
+	switch name {
+	case "SHA1":
+		return sha1.New
+	case "SHA256":
+		return sha256.New
+	case "SHA512":
+		return sha512.New
+	default:
+		return nil
+	}
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	normalized = strings.TrimRight(normalized, "=")
+	if normalized == "" {
+		return nil, ErrInvalidSecret
+	}
+
+	key, err := encoding.DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSecret, err)
+	}
+
+	return key, nil
+}
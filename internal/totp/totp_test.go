@@ -0,0 +1,202 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret - ключ "12345678901234567890" из приложения B RFC 6238 в base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.code, code, "время %d", tt.unix)
+	}
+}
+
+func TestCode_NormalizesSecret(t *testing.T) {
+	expected, err := Code(rfcSecret, 1)
+	require.NoError(t, err)
+
+	code, err := Code("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", 1)
+	require.NoError(t, err)
+	assert.Equal(t, expected, code)
+
+	_, err = Code("не base32", 1)
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+
+	_, err = Code("", 1)
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	previous, err := Code(rfcSecret, Step(now)-1)
+	require.NoError(t, err)
+	old, err := Code(rfcSecret, Step(now)-3)
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, "050471", now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	step, ok = Validate(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Validate(rfcSecret, old, now, 1)
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	require.NoError(t, err)
+	second, err := GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+	_, err = Code(first, 1)
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("GophKeeper", "user@example", "ABC")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/GophKeeper:user@example", parsed.Path)
+	assert.Equal(t, "ABC", parsed.Query().Get("secret"))
+	assert.Equal(t, "GophKeeper", parsed.Query().Get("issuer"))
+}
+
+func TestRemaining(t *testing.T) {
+	assert.Equal(t, 30, Remaining(time.Unix(60, 0)))
+	assert.Equal(t, 1, Remaining(time.Unix(89, 0)))
+}
+
+func TestKey_RFC6238Algorithms(t *testing.T) {
+	// Ключи из приложения B RFC 6238: для SHA256 и SHA512 они длиннее.
+	tests := []struct {
+		algorithm string
+		secret    string
+		unix      int64
+		code      string
+	}{
+		{"SHA1", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", 59, "94287082"},
+		{"SHA256", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA", 59, "46119246"},
+		{"SHA512", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNA", 59, "90693936"},
+		{"SHA256", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA", 1111111109, "68084774"},
+	}
+
+	for _, tt := range tests {
+		key := &Key{Secret: tt.secret, Algorithm: tt.algorithm, Digits: 8, Period: 30}
+		code, err := key.Code(time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tt.code, code, "%s, время %d", tt.algorithm, tt.unix)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    *Key
+		expectedErr error
+	}{
+		{
+			name:     "секрет base32",
+			value:    " " + rfcSecret + " ",
+			expected: &Key{Secret: rfcSecret, Algorithm: "SHA1", Digits: 6, Period: 30},
+		},
+		{
+			name:  "ссылка со всеми параметрами",
+			value: "otpauth://totp/ACME%20Co:john@example.com?secret=" + rfcSecret + "&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60",
+			expected: &Key{
+				Secret: rfcSecret, Issuer: "ACME Co", Account: "john@example.com",
+				Algorithm: "SHA256", Digits: 8, Period: 60,
+			},
+		},
+		{
+			name:  "issuer только в метке",
+			value: "otpauth://totp/GitHub:octocat?secret=" + rfcSecret,
+			expected: &Key{
+				Secret: rfcSecret, Issuer: "GitHub", Account: "octocat", Algorithm: "SHA1", Digits: 6, Period: 30,
+			},
+		},
+		{
+			name:        "HOTP не поддерживается",
+			value:       "otpauth://hotp/x?secret=" + rfcSecret + "&counter=1",
+			expectedErr: ErrInvalidURI,
+		},
+		{
+			name:        "нет секрета",
+			value:       "otpauth://totp/x?issuer=y",
+			expectedErr: ErrInvalidSecret,
+		},
+		{
+			name:        "неизвестный алгоритм",
+			value:       "otpauth://totp/x?secret=" + rfcSecret + "&algorithm=MD5",
+			expectedErr: ErrInvalidURI,
+		},
+		{
+			name:        "некорректная длина кода",
+			value:       "otpauth://totp/x?secret=" + rfcSecret + "&digits=12",
+			expectedErr: ErrInvalidURI,
+		},
+		{
+			name:        "не base32",
+			value:       "не секрет",
+			expectedErr: ErrInvalidSecret,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParseKey(tt.value)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, key)
+		})
+	}
+}
+
+func TestKey_Remaining(t *testing.T) {
+	key := &Key{Period: 60}
+	assert.Equal(t, 60, key.Remaining(time.Unix(120, 0)))
+	assert.Equal(t, 15, key.Remaining(time.Unix(165, 0)))
+}
+
+func TestKey_MatchesServerCode(t *testing.T) {
+	key, err := ParseKey(URI("GophKeeper", "user", rfcSecret))
+	require.NoError(t, err)
+
+	now := time.Unix(1111111111, 0)
+	code, err := key.Code(now)
+	require.NoError(t, err)
+	_, ok := Validate(rfcSecret, code, now, 0)
+	assert.True(t, ok)
+}